@@ -0,0 +1,116 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AccessReviewSpec defines the identity whose access to projects and workspaces should be reviewed.
+// +kubebuilder:validation:XValidation:rule="has(self.user) || has(self.groups)",message="Either user or groups must be specified"
+type AccessReviewSpec struct {
+	// User is the name of the user whose access is reviewed.
+	// ServiceAccounts are specified as 'system:serviceaccount:<namespace>:<name>'.
+	// +optional
+	User string `json:"user,omitempty"`
+
+	// Groups are the groups the user belongs to.
+	// +optional
+	Groups []string `json:"groups,omitempty"`
+}
+
+// AccessReviewStatus contains the projects and workspaces the reviewed identity has access to.
+type AccessReviewStatus struct {
+	// ObservedGeneration is the generation of the spec the result has been computed for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// CompletionTime is the time when the result has been computed.
+	// The AccessReview is deleted automatically some time after this.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// ResolvedGroups are the groups of the user which have been resolved from the GroupSnapshots, in addition to the groups of the spec.
+	// They are considered for the result like the groups of the spec.
+	// +optional
+	ResolvedGroups []string `json:"resolvedGroups,omitempty"`
+
+	// Warnings contains the problems which might make the result incomplete, e.g. stale GroupSnapshots.
+	// +optional
+	Warnings []string `json:"warnings,omitempty"`
+
+	// Projects contains the projects the identity is a member of or can manage via a member override.
+	// +optional
+	Projects []ProjectAccess `json:"projects,omitempty"`
+
+	// Workspaces contains the workspaces the identity is a member of, either directly or inherited from the project, or can manage via a member override.
+	// +optional
+	Workspaces []WorkspaceAccess `json:"workspaces,omitempty"`
+}
+
+// ProjectAccess describes the access of an identity to a project.
+type ProjectAccess struct {
+	// Name is the name of the project.
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the project.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Roles are the roles the identity has in the project.
+	// +optional
+	Roles []ProjectMemberRole `json:"roles,omitempty"`
+
+	// AdminOverride is true if the identity is allowed to manage the project via a member override, regardless of its roles.
+	// +optional
+	AdminOverride bool `json:"adminOverride,omitempty"`
+}
+
+// WorkspaceAccess describes the access of an identity to a workspace.
+type WorkspaceAccess struct {
+	// Project is the name of the project the workspace belongs to.
+	Project string `json:"project"`
+
+	// Name is the name of the workspace.
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the workspace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Roles are the roles the identity has in the workspace, including the ones inherited from the project.
+	// +optional
+	Roles []WorkspaceMemberRole `json:"roles,omitempty"`
+
+	// AdminOverride is true if the identity is allowed to manage the workspace via a member override, regardless of its roles.
+	// +optional
+	AdminOverride bool `json:"adminOverride,omitempty"`
+}
+
+// AccessReview lists the projects and workspaces a given user or set of groups has access to, together with the respective roles.
+// The result is computed once from the members of all projects and workspaces and the member overrides of the config, it is not updated afterwards.
+// The groups of the user are resolved from the GroupSnapshots, if there are any.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="User",type="string",JSONPath=".spec.user"
+// +kubebuilder:printcolumn:name="Completed",type="date",JSONPath=".status.completionTime"
+// +kubebuilder:metadata:labels="openmcp.cloud/cluster=onboarding"
+type AccessReview struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AccessReviewSpec   `json:"spec,omitempty"`
+	Status AccessReviewStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AccessReviewList contains a list of AccessReview
+type AccessReviewList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AccessReview `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AccessReview{}, &AccessReviewList{})
+}
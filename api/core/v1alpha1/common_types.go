@@ -13,6 +13,25 @@ import (
 var (
 	CreatedByAnnotation   = fmt.Sprintf("%s/created-by", GroupVersion.Group)
 	DisplayNameAnnotation = fmt.Sprintf("%s/display-name", GroupVersion.Group)
+	// HibernatedAnnotation is set to "true" on the namespace of a hibernated workspace.
+	// ServiceProviders can watch for it to scale down the resources they manage within that namespace.
+	HibernatedAnnotation = fmt.Sprintf("%s/hibernated", GroupVersion.Group)
+	// DeletionRequestedAnnotation is set to "true" on the namespace of a project or workspace that is being deleted.
+	// ServiceProviders should watch for it and clean up the resources they manage within that namespace,
+	// since the deletion does not proceed as long as any of the registered service resources exist.
+	DeletionRequestedAnnotation = fmt.Sprintf("%s/deletion-requested", GroupVersion.Group)
+	// ChargingTargetAnnotation can be set on projects and workspaces to record the charging target (e.g. a cost center) for the resources within them.
+	// It is not interpreted by the platform service, but included in exports. Workspaces without the annotation are charged to the target of their project.
+	ChargingTargetAnnotation = fmt.Sprintf("%s/charging-target", GroupVersion.Group)
+	// MembershipSourceLabel is set on projects and workspaces whose members are synced from a ProjectMembershipSource and contains its name.
+	// ConfigMaps and Secrets which are referenced by a ProjectMembershipSource should carry it too (with any value), so that changes are synced immediately.
+	MembershipSourceLabel = fmt.Sprintf("%s/membership-source", GroupVersion.Group)
+	// ManagedMembersAnnotation contains the JSON encoded list of subjects whose membership in a project or workspace is synced from a ProjectMembershipSource.
+	// These members can only be changed via the source.
+	ManagedMembersAnnotation = fmt.Sprintf("%s/managed-members", GroupVersion.Group)
+
+	// ImmutableAnnotations are the annotations of projects and workspaces which cannot be changed after their creation.
+	ImmutableAnnotations = []string{CreatedByAnnotation}
 )
 
 // Subject contains a reference to the object or user identities a role binding applies to. This can either hold a direct API object reference,
@@ -25,6 +44,7 @@ type Subject struct {
 	Kind string `json:"kind"`
 
 	// Name of the object being referenced.
+	// +kubebuilder:validation:MinLength=1
 	Name string `json:"name"`
 
 	// Namespace of the referenced object. Required if Kind is "ServiceAccount". Must not be specified if Kind is "User" or "Group".
@@ -157,8 +177,36 @@ type RemainingContentResource struct {
 	Name string `json:"name"`
 	// Namespace is the namespace of the resource.
 	Namespace string `json:"namespace"`
+	// Source is the origin of the deletion-blocking resource type, e.g. 'Builtin', 'ProjectWorkspaceConfig', or 'ServiceProvider[<name>]'.
+	// +optional
+	Source string `json:"source,omitempty"`
+}
+
+// RemainingContentDetails are the details of the ContentRemaining condition.
+// To keep the condition small for namespaces with many resources, only a limited number of the remaining resources is listed as examples.
+type RemainingContentDetails struct {
+	// Count is the total number of remaining resources.
+	Count int `json:"count"`
+	// Resources contains examples of the remaining resources.
+	// +optional
+	Resources []RemainingContentResource `json:"resources,omitempty"`
 }
 
+const (
+	// EventReasonBlockingResourceKindMissing is the reason of the warning event which is recorded on a project/workspace
+	// in deletion if the CRD of a resource type blocking its deletion is not installed. The resource type is skipped in this case.
+	EventReasonBlockingResourceKindMissing = "BlockingResourceKindMissing"
+	// EventReasonNonBlockingResourcesRemaining is the reason of the warning event which is recorded on a workspace in deletion
+	// if its namespace still contains resources whose kind has the blocking resource policy 'Warn'. They are deleted together with the namespace.
+	EventReasonNonBlockingResourcesRemaining = "NonBlockingResourcesRemaining"
+	// EventReasonSubjectsChanged is the reason of the event which is recorded on a project/workspace if the subjects of one of
+	// its ClusterRoleBindings change, e.g. because members have been added or removed. The event lists the added and removed subjects.
+	EventReasonSubjectsChanged = "SubjectsChanged"
+	// EventReasonNamespaceOwnershipMismatch is the reason of the warning event which is recorded on a project/workspace in deletion
+	// if the labels of its namespace do not identify it as created by the platform service for the project/workspace anymore. The namespace is not deleted in this case.
+	EventReasonNamespaceOwnershipMismatch = "NamespaceOwnershipMismatch"
+)
+
 const (
 	// ConditionTypeContentRemaining is a condition type that indicates that there is content in a project/workspace
 	// that is preventing the deletion.
@@ -168,6 +216,63 @@ const (
 	// project/workspace that are preventing the deletion.
 	ConditionReasonResourcesRemaining ConditionReason = "SomeResourcesRemain"
 
+	// ConditionReasonResourcesNotBlocking is a condition reason that indicates that there are remaining resources in a
+	// workspace, but their blocking resource policy allows the deletion to proceed.
+	ConditionReasonResourcesNotBlocking ConditionReason = "RemainingResourcesNotBlocking"
+
+	// ConditionTypeNamespacesTerminating is a condition type that indicates that the deletion of a project is waiting
+	// for namespaces belonging to the project to be deleted.
+	ConditionTypeNamespacesTerminating ConditionType = "NamespacesTerminating"
+
+	// ConditionReasonNamespacesRemaining is a condition reason that indicates that there are namespaces belonging to a
+	// project which still exist.
+	ConditionReasonNamespacesRemaining ConditionReason = "NamespacesRemaining"
+
+	// ConditionTypePendingDeletion is a condition type that indicates that the deletion of a project has been requested,
+	// but its teardown is delayed until the deletion grace period has passed.
+	ConditionTypePendingDeletion ConditionType = "PendingDeletion"
+
+	// ConditionReasonDeletionGracePeriod is a condition reason that indicates that the deletion grace period of a project has not passed yet.
+	ConditionReasonDeletionGracePeriod ConditionReason = "DeletionGracePeriod"
+
+	// ConditionReasonDeletionCancelled is a condition reason that indicates that the deletion of a project has been cancelled,
+	// but the project could not be re-created yet.
+	ConditionReasonDeletionCancelled ConditionReason = "DeletionCancelled"
+
+	// ConditionTypeHibernated is a condition type that indicates that a workspace is hibernated.
+	ConditionTypeHibernated ConditionType = "Hibernated"
+
+	// ConditionReasonHibernationRequested is a condition reason that indicates that the hibernation has been requested via the spec.
+	ConditionReasonHibernationRequested ConditionReason = "HibernationRequested"
+
+	// ConditionTypeMembersRejected is a condition type that indicates that some members of a workspace are not bound,
+	// because they are ServiceAccounts from namespaces which are not allowed by the config.
+	ConditionTypeMembersRejected ConditionType = "MembersRejected"
+
+	// ConditionReasonServiceAccountNamespaceNotAllowed is a condition reason that indicates that ServiceAccount members
+	// of a workspace reside in namespaces which do not belong to its project and are not explicitly allowed.
+	ConditionReasonServiceAccountNamespaceNotAllowed ConditionReason = "ServiceAccountNamespaceNotAllowed"
+
+	// ConditionTypeReady is a condition type that indicates that the RBAC of a workspace has been applied
+	// and verified to grant access to its members.
+	ConditionTypeReady ConditionType = "Ready"
+
+	// ConditionReasonAccessVerified is a condition reason that indicates that a SubjectAccessReview confirmed
+	// that the RBAC resources of a workspace grant access to one of its members.
+	ConditionReasonAccessVerified ConditionReason = "AccessVerified"
+
+	// ConditionReasonAccessNotGranted is a condition reason that indicates that the RBAC resources of a workspace
+	// have not been applied or do not grant the expected access to its members (yet).
+	ConditionReasonAccessNotGranted ConditionReason = "AccessNotGranted"
+
+	// ConditionReasonNoActiveMembers is a condition reason that indicates that the access of a workspace could not be verified,
+	// because none of its members is active.
+	ConditionReasonNoActiveMembers ConditionReason = "NoActiveMembers"
+
+	// ConditionTypeReconcileError is a condition type that indicates that the last reconciliation of a project/workspace failed.
+	// The reason is the kind of the error (Retriable or Terminal), the message contains the error.
+	ConditionTypeReconcileError ConditionType = "ReconcileError"
+
 	// ConditionStatusTrue indicates that the condition is currently active.
 	ConditionStatusTrue ConditionStatus = "True"
 	// ConditionStatusFalse indicates that the condition is not currently active.
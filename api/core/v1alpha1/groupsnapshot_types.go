@@ -0,0 +1,96 @@
+package v1alpha1
+
+import (
+	"slices"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultGroupSnapshotMaxAge is the age after which a GroupSnapshot is considered stale, if it does not specify one.
+const DefaultGroupSnapshotMaxAge = 24 * time.Hour
+
+// GroupSnapshotSpec contains the users of groups, as exported from an external group provider.
+type GroupSnapshotSpec struct {
+	// Provider is the name of the group provider the snapshot has been taken from, e.g. the identity provider.
+	// It is only used for informational purposes.
+	// +optional
+	Provider string `json:"provider,omitempty"`
+
+	// SnapshotTime is the time when the groups have been read from the group provider.
+	SnapshotTime metav1.Time `json:"snapshotTime"`
+
+	// MaxAge is the age of the snapshot after which it is considered stale, usually a bit more than the interval in which it is synced.
+	// Stale snapshots are still used, but reported with a warning. Defaults to 24h.
+	// +optional
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+
+	// Groups are the groups with their users.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Groups []GroupUsers `json:"groups,omitempty"`
+}
+
+// GroupUsers contains the users of a group.
+type GroupUsers struct {
+	// Name is the name of the group, as it appears in the groups of authenticated users.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Users are the names of the users in the group, as they appear in the usernames of authenticated users.
+	// +optional
+	Users []string `json:"users,omitempty"`
+}
+
+// GetMaxAge returns the age after which the snapshot is considered stale.
+func (s *GroupSnapshot) GetMaxAge() time.Duration {
+	if s.Spec.MaxAge == nil {
+		return DefaultGroupSnapshotMaxAge
+	}
+	return s.Spec.MaxAge.Duration
+}
+
+// IsStale returns true if the snapshot is older than its maximum age at the given time.
+func (s *GroupSnapshot) IsStale(now time.Time) bool {
+	return now.Sub(s.Spec.SnapshotTime.Time) > s.GetMaxAge()
+}
+
+// GroupsOfUser returns the names of the groups of the snapshot which contain the given user.
+func (s *GroupSnapshot) GroupsOfUser(user string) []string {
+	var res []string
+	for _, group := range s.Spec.Groups {
+		if slices.Contains(group.Users, user) {
+			res = append(res, group.Name)
+		}
+	}
+	return res
+}
+
+// GroupSnapshot lists the users of groups, as exported from an external group provider by a job which keeps it in sync.
+// Since members of projects and workspaces are often groups, whose users are only known to the identity provider,
+// the platform service uses the snapshots to resolve the groups of users, e.g. for AccessReviews which only specify a user.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Provider",type="string",JSONPath=".spec.provider"
+// +kubebuilder:printcolumn:name="Snapshot",type="date",JSONPath=".spec.snapshotTime"
+// +kubebuilder:metadata:labels="openmcp.cloud/cluster=onboarding"
+type GroupSnapshot struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GroupSnapshotSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GroupSnapshotList contains a list of GroupSnapshot
+type GroupSnapshotList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GroupSnapshot `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GroupSnapshot{}, &GroupSnapshotList{})
+}
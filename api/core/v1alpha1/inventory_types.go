@@ -0,0 +1,125 @@
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultInventoryResyncInterval is the default interval in which the inventory on the platform cluster is compared with the projects on the onboarding cluster.
+const DefaultInventoryResyncInterval = time.Hour
+
+// InventoryConfig configures the inventory of projects and workspaces on the platform cluster.
+type InventoryConfig struct {
+	// ResyncInterval is the interval in which all ProjectInventories are compared with the projects and workspaces on the onboarding cluster,
+	// to heal changes which have been missed, e.g. deletions while the platform service was not running.
+	// Defaults to 1h.
+	// +optional
+	ResyncInterval *metav1.Duration `json:"resyncInterval,omitempty"`
+}
+
+// GetResyncInterval returns the configured resync interval or the default.
+func (c *InventoryConfig) GetResyncInterval() time.Duration {
+	if c == nil || c.ResyncInterval == nil || c.ResyncInterval.Duration <= 0 {
+		return DefaultInventoryResyncInterval
+	}
+	return c.ResyncInterval.Duration
+}
+
+// ProjectInventorySpec contains the condensed state of a project and its workspaces on the onboarding cluster.
+type ProjectInventorySpec struct {
+	// UID is the UID of the Project on the onboarding cluster.
+	UID string `json:"uid"`
+	// DisplayName is the display name of the project.
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+	// Namespace is the namespace of the project on the onboarding cluster.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// CreatedBy is the user who created the project.
+	// +optional
+	CreatedBy string `json:"createdBy,omitempty"`
+	// ChargingTarget is the charging target of the project.
+	// +optional
+	ChargingTarget string `json:"chargingTarget,omitempty"`
+	// Members are the members of the project.
+	// +optional
+	Members []InventoryMember `json:"members,omitempty"`
+	// DeletionTimestamp is set once the deletion of the project has been requested.
+	// +optional
+	DeletionTimestamp *metav1.Time `json:"deletionTimestamp,omitempty"`
+	// Workspaces are the workspaces of the project, sorted by name.
+	// +optional
+	Workspaces []WorkspaceInventory `json:"workspaces,omitempty"`
+}
+
+// WorkspaceInventory contains the condensed state of a workspace on the onboarding cluster.
+type WorkspaceInventory struct {
+	// Name is the name of the workspace.
+	Name string `json:"name"`
+	// UID is the UID of the Workspace on the onboarding cluster.
+	UID string `json:"uid"`
+	// DisplayName is the display name of the workspace.
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+	// Namespace is the namespace of the workspace on the onboarding cluster.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// CreatedBy is the user who created the workspace.
+	// +optional
+	CreatedBy string `json:"createdBy,omitempty"`
+	// ChargingTarget is the charging target of the workspace, or the one of its project if the workspace has none.
+	// +optional
+	ChargingTarget string `json:"chargingTarget,omitempty"`
+	// InheritsProjectMembers is true if the members of the project are members of the workspace as well.
+	// +optional
+	InheritsProjectMembers bool `json:"inheritsProjectMembers,omitempty"`
+	// Members are the effective members of the workspace, including the ones inherited from the project.
+	// +optional
+	Members []InventoryMember `json:"members,omitempty"`
+	// DeletionTimestamp is set once the deletion of the workspace has been requested.
+	// +optional
+	DeletionTimestamp *metav1.Time `json:"deletionTimestamp,omitempty"`
+}
+
+// InventoryMember is a member of a project or workspace in the inventory.
+type InventoryMember struct {
+	// Kind is the kind of the subject, i.e. 'User', 'Group' or 'ServiceAccount'.
+	Kind string `json:"kind"`
+	// Name is the name of the subject.
+	Name string `json:"name"`
+	// Namespace is the namespace of a ServiceAccount.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Roles are the roles of the member.
+	Roles []string `json:"roles"`
+}
+
+// ProjectInventory is a read-only copy of a project and its workspaces on the platform cluster, for platform-side consumers like billing
+// which cannot access the onboarding cluster. It is maintained by the platform service if the inventory is enabled in the ProjectWorkspaceConfig,
+// has the same name as the Project and is deleted once the Project is gone.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName=pinv
+// +kubebuilder:printcolumn:name="Namespace",type="string",JSONPath=".spec.namespace"
+// +kubebuilder:printcolumn:name="Charging Target",type="string",JSONPath=".spec.chargingTarget"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:metadata:labels="openmcp.cloud/cluster=platform"
+type ProjectInventory struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ProjectInventorySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ProjectInventoryList contains a list of ProjectInventory
+type ProjectInventoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ProjectInventory `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ProjectInventory{}, &ProjectInventoryList{})
+}
@@ -0,0 +1,154 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	authv1 "k8s.io/api/authentication/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	// InvitationAcceptAnnotation is set to "true" on an Invitation by the invited user to accept it.
+	// The webhook only allows the invited user (or a member of the invited group) to set it, and it cannot be removed afterwards.
+	InvitationAcceptAnnotation = fmt.Sprintf("%s/accept", GroupVersion.Group)
+	// InvitationAcceptedByAnnotation is set by the webhook to the user who added the InvitationAcceptAnnotation.
+	// A value provided by the user is overwritten, so that nobody can accept an invitation on behalf of someone else.
+	InvitationAcceptedByAnnotation = fmt.Sprintf("%s/accepted-by", GroupVersion.Group)
+)
+
+const (
+	// DefaultInvitationTTL is the time after which an Invitation expires, unless it specifies another TTL.
+	DefaultInvitationTTL = 7 * 24 * time.Hour
+)
+
+// InvitationPhase is the state of an Invitation.
+type InvitationPhase string
+
+const (
+	// InvitationPhasePending means that the invitation has not been accepted yet.
+	InvitationPhasePending InvitationPhase = "Pending"
+	// InvitationPhaseAccepted means that the invited subject has been added to the members of the project or workspace.
+	InvitationPhaseAccepted InvitationPhase = "Accepted"
+	// InvitationPhaseExpired means that the invitation has not been accepted before its TTL passed.
+	InvitationPhaseExpired InvitationPhase = "Expired"
+	// InvitationPhaseFailed means that the invitation has been accepted, but the subject could not be added, e.g. because the project has been deleted.
+	InvitationPhaseFailed InvitationPhase = "Failed"
+)
+
+// InvitationSpec defines who is invited to which project or workspace.
+// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="spec is immutable"
+type InvitationSpec struct {
+	// Project is the name of the project the subject is invited to, or which contains the workspace the subject is invited to.
+	// +kubebuilder:validation:MinLength=1
+	Project string `json:"project"`
+
+	// Workspace is the name of the workspace the subject is invited to.
+	// If empty, the subject is invited to the project itself.
+	// +optional
+	Workspace string `json:"workspace,omitempty"`
+
+	// Subject is the invited user or group. ServiceAccounts cannot be invited, since they cannot accept invitations.
+	// +kubebuilder:validation:XValidation:rule="self.kind != 'ServiceAccount'",message="ServiceAccounts cannot be invited"
+	Subject Subject `json:"subject"`
+
+	// Roles are the roles the subject gets in the project or workspace. They are added to the roles the subject already has.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:items:Enum=admin;view;auditor
+	Roles []string `json:"roles"`
+
+	// TTL is the time after which the invitation expires, if it has not been accepted. Defaults to 7 days.
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+}
+
+// InvitationStatus shows whether the invitation has been accepted.
+type InvitationStatus struct {
+	// Phase is the state of the invitation.
+	// +optional
+	Phase InvitationPhase `json:"phase,omitempty"`
+
+	// ExpirationTime is the time after which the invitation cannot be accepted anymore.
+	// +optional
+	ExpirationTime *metav1.Time `json:"expirationTime,omitempty"`
+
+	// AcceptedBy is the user who accepted the invitation.
+	// +optional
+	AcceptedBy string `json:"acceptedBy,omitempty"`
+
+	// CompletionTime is the time when the invitation has been accepted, has expired or has failed.
+	// The Invitation is deleted automatically some time after this.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Message contains details about the phase, e.g. why the subject could not be added.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// Invitation invites a user or group to a project or workspace. The subject is only added to the members once
+// the invited user accepts the invitation by setting the 'core.openmcp.cloud/accept' annotation, so that nobody becomes a member without consent.
+// Only admins of the project or workspace can create invitations.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Project",type="string",JSONPath=".spec.project"
+// +kubebuilder:printcolumn:name="Workspace",type="string",JSONPath=".spec.workspace"
+// +kubebuilder:printcolumn:name="Kind",type="string",JSONPath=".spec.subject.kind"
+// +kubebuilder:printcolumn:name="Subject",type="string",JSONPath=".spec.subject.name"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Expires",type="date",JSONPath=".status.expirationTime"
+// +kubebuilder:metadata:labels="openmcp.cloud/cluster=onboarding"
+type Invitation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   InvitationSpec   `json:"spec,omitempty"`
+	Status InvitationStatus `json:"status,omitempty"`
+}
+
+// ExpirationTime returns the time after which the invitation cannot be accepted anymore, based on its creation time and TTL.
+func (inv *Invitation) ExpirationTime() time.Time {
+	ttl := DefaultInvitationTTL
+	if inv.Spec.TTL != nil {
+		ttl = inv.Spec.TTL.Duration
+	}
+	return inv.CreationTimestamp.Add(ttl)
+}
+
+// IsAccepted returns true if the invited user has requested to accept the invitation.
+func (inv *Invitation) IsAccepted() bool {
+	return inv.GetAnnotations()[InvitationAcceptAnnotation] == "true"
+}
+
+// IsCompleted returns true if the invitation has been processed and cannot be accepted anymore.
+func (inv *Invitation) IsCompleted() bool {
+	return inv.Status.Phase == InvitationPhaseAccepted || inv.Status.Phase == InvitationPhaseExpired || inv.Status.Phase == InvitationPhaseFailed
+}
+
+// IsInvited returns true if the given user is the invited user or a member of the invited group.
+func (inv *Invitation) IsInvited(userInfo authv1.UserInfo) bool {
+	switch inv.Spec.Subject.Kind {
+	case rbacv1.UserKind:
+		return inv.Spec.Subject.Name == userInfo.Username
+	case rbacv1.GroupKind:
+		return slices.Contains(userInfo.Groups, inv.Spec.Subject.Name)
+	default:
+		return false
+	}
+}
+
+// +kubebuilder:object:root=true
+
+// InvitationList contains a list of Invitation
+type InvitationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Invitation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Invitation{}, &InvitationList{})
+}
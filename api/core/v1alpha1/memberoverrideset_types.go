@@ -0,0 +1,38 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MemberOverrideSetSpec contains member overrides which are maintained separately from the ProjectWorkspaceConfig.
+type MemberOverrideSetSpec struct {
+	// Overrides are added to the member overrides of the ProjectWorkspaceConfig.
+	// +optional
+	Overrides MemberOverrides `json:"overrides,omitempty"`
+}
+
+// MemberOverrideSet contains member overrides which are maintained separately from the ProjectWorkspaceConfig,
+// so that e.g. central platform admins and regional admins can each manage their own overrides.
+// A set is only used if it matches the memberOverrideSetSelector of the ProjectWorkspaceConfig.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName=mos
+// +kubebuilder:metadata:labels="openmcp.cloud/cluster=platform"
+type MemberOverrideSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec MemberOverrideSetSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MemberOverrideSetList contains a list of MemberOverrideSet
+type MemberOverrideSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MemberOverrideSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MemberOverrideSet{}, &MemberOverrideSetList{})
+}
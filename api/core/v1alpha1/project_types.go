@@ -2,6 +2,7 @@ package v1alpha1
 
 import (
 	"fmt"
+	"time"
 
 	authv1 "k8s.io/api/authentication/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -15,9 +16,10 @@ var (
 	_ entities.AccessEntity = &Project{}
 	_ entities.AccessRole   = ProjectRoleAdmin
 	_ entities.AccessRole   = ProjectRoleView
+	_ entities.AccessRole   = ProjectRoleAuditor
 )
 
-// +kubebuilder:validation:Enum=admin;view
+// +kubebuilder:validation:Enum=admin;view;auditor
 type ProjectMemberRole string
 
 // Identifier implements AccessRole.
@@ -33,12 +35,103 @@ func (ProjectMemberRole) EntityType() entities.AccessEntity {
 const (
 	ProjectRoleAdmin ProjectMemberRole = "admin"
 	ProjectRoleView  ProjectMemberRole = "view"
+	// ProjectRoleAuditor grants read-only access, like ProjectRoleView, but excludes sensitive resources like secrets.
+	ProjectRoleAuditor ProjectMemberRole = "auditor"
+)
+
+// ProjectMemberRoles returns all roles which can be assigned to project members.
+func ProjectMemberRoles() []ProjectMemberRole {
+	return []ProjectMemberRole{ProjectRoleAdmin, ProjectRoleView, ProjectRoleAuditor}
+}
+
+const (
+	// AutomationServiceAccountName is the name of the ServiceAccount which is created in the project namespace if the automation ServiceAccount is enabled in the ProjectWorkspaceConfig.
+	AutomationServiceAccountName = "project-automation"
+	// AutomationTokenSecretName is the name of the Secret in the project namespace which contains the token that has been issued last for the automation ServiceAccount.
+	AutomationTokenSecretName = "project-automation-token"
+	// AutomationTokenSecretTokenKey is the key of the token in the automation token Secret.
+	AutomationTokenSecretTokenKey = "token"
+	// AutomationTokenSecretExpirationKey is the key of the token's expiration timestamp (RFC 3339) in the automation token Secret.
+	AutomationTokenSecretExpirationKey = "expirationTimestamp"
+
+	// DefaultMaxAutomationTokenExpiration is the default maximum lifetime of tokens issued for the automation ServiceAccount.
+	DefaultMaxAutomationTokenExpiration = time.Hour
+	// MinAutomationTokenExpiration is the minimum lifetime of tokens issued for the automation ServiceAccount, as enforced by the TokenRequest API.
+	MinAutomationTokenExpiration = 10 * time.Minute
+
+	// SourceProjectPrefix is the prefix of the source of deletion-blocking resource types which come from the spec of a Project.
+	SourceProjectPrefix = "Project"
+
+	EventReasonAutomationTokenIssued        = "AutomationTokenIssued"
+	EventReasonAutomationTokenRequestFailed = "AutomationTokenRequestFailed"
+
+	// EventReasonDeletionPending is the reason of the event which is recorded when the deletion of a project has been requested
+	// and its teardown is delayed by the deletion grace period.
+	EventReasonDeletionPending = "DeletionPending"
+	// EventReasonDeletionStarted is the reason of the event which is recorded when the deletion grace period of a project has passed and its teardown starts.
+	EventReasonDeletionStarted = "DeletionStarted"
+	// EventReasonDeletionCancelled is the reason of the event which is recorded when the deletion of a project has been cancelled during the grace period.
+	EventReasonDeletionCancelled = "DeletionCancelled"
+	// EventReasonDeletionCancelFailed is the reason of the warning event which is recorded when a cancelled deletion could not be reverted.
+	EventReasonDeletionCancelFailed = "DeletionCancelFailed"
+	// EventReasonOwnershipTransferred is the reason of the event which is recorded when the ownership of a project has been transferred to another admin.
+	EventReasonOwnershipTransferred = "OwnershipTransferred"
+	// EventReasonOwnershipTransferFailed is the reason of the warning event which is recorded when the new owner of a project is no admin anymore.
+	EventReasonOwnershipTransferFailed = "OwnershipTransferFailed"
+)
+
+var (
+	// AutomationTokenRequestAnnotation requests a new token for the automation ServiceAccount when added to a Project.
+	// The value is the desired lifetime of the token (e.g. '30m'), an empty value requests the maximum lifetime.
+	// The controller removes the annotation after the token has been issued.
+	AutomationTokenRequestAnnotation = fmt.Sprintf("%s/request-automation-token", GroupVersion.Group)
+	// AutomationTokenRequestedByAnnotation is set by the webhook to the user who added the AutomationTokenRequestAnnotation.
+	AutomationTokenRequestedByAnnotation = fmt.Sprintf("%s/automation-token-requested-by", GroupVersion.Group)
+	// CancelDeletionAnnotation cancels the deletion of a Project when set to "true" during the deletion grace period.
+	// Since Kubernetes cannot revoke a deletion, the controller re-creates the Project without this annotation, its namespaces and their content are kept.
+	CancelDeletionAnnotation = fmt.Sprintf("%s/cancel-deletion", GroupVersion.Group)
+	// TransferOwnershipAnnotation requests the transfer of the ownership of a Project to the user in its value, who must be an admin of the project.
+	// Only admins granted by member overrides may set it. The controller replaces the CreatedByAnnotation with the new owner and removes the annotation.
+	// A Project whose creator is no admin anymore can only be deleted while this annotation is set.
+	TransferOwnershipAnnotation = fmt.Sprintf("%s/transfer-ownership-to", GroupVersion.Group)
+	// SecretStoreAnnotationPrefix is the prefix of the namespace annotations for secret store references, unless the ProjectWorkspaceConfig specifies another annotation for the store.
+	// The name of the store is appended to it.
+	SecretStoreAnnotationPrefix = fmt.Sprintf("secretstore.%s/", GroupVersion.Group)
 )
 
 // ProjectSpec defines the desired state of Project
 type ProjectSpec struct {
 	// Members is a list of project members.
 	Members []ProjectMember `json:"members,omitempty"`
+
+	// SecretStores references locations in external secret stores, e.g. paths in a vault, which the project and its workspaces use.
+	// The references are added as annotations to the project namespace and the namespaces of its workspaces, where they can be consumed e.g. by the External Secrets Operator.
+	// Only stores which are configured in the ProjectWorkspaceConfig can be referenced.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	SecretStores []SecretStoreReference `json:"secretStores,omitempty"`
+
+	// PodSecurity defines Pod Security Admission levels for the namespaces of the project's workspaces.
+	// Only takes effect if pod security is configured in the ProjectWorkspaceConfig, and only levels which are stricter than the configured ones are applied.
+	// +optional
+	PodSecurity *PodSecurityLevels `json:"podSecurity,omitempty"`
+
+	// ResourcesBlockingDeletion defines resource types which block the deletion of this project, in addition to the ones from the ProjectWorkspaceConfig.
+	// Only resource types which are allowed in spec.project.allowedResourcesBlockingDeletion of the ProjectWorkspaceConfig can be added.
+	// +optional
+	ResourcesBlockingDeletion []metav1.GroupVersionKind `json:"resourcesBlockingDeletion,omitempty"`
+}
+
+// SecretStoreReference references a location in an external secret store.
+type SecretStoreReference struct {
+	// Name is the name of the secret store, as configured in the ProjectWorkspaceConfig.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Path is the location within the secret store, e.g. the path of a vault.
+	// +kubebuilder:validation:MinLength=1
+	Path string `json:"path"`
 }
 
 type ProjectMember struct {
@@ -64,14 +157,19 @@ type ProjectStatus struct {
 	Namespace string `json:"namespace"`
 	// +optional
 	Conditions []Condition `json:"conditions,omitempty"`
+	// BlockingResourceCount is the number of resources in the project namespace which block the deletion of the project.
+	// It is only set while the project is in deletion, the resources are listed in the details of the ContentRemaining condition.
+	// +optional
+	BlockingResourceCount int32 `json:"blockingResourceCount,omitempty"`
 }
 
 // Project is the Schema for the projects API
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster
-// +kubebuilder:printcolumn:name="Display Name",type="string",JSONPath=".metadata.annotations.openmcp\\.cloud/display-name"
+// +kubebuilder:printcolumn:name="Display Name",type="string",JSONPath=".metadata.annotations.core\\.openmcp\\.cloud/display-name"
 // +kubebuilder:printcolumn:name="Resulting Namespace",type="string",JSONPath=".status.namespace"
+// +kubebuilder:printcolumn:name="Blocking",type="integer",JSONPath=".status.blockingResourceCount"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:validation:XValidation:rule="size(self.metadata.name) <= 25",message="Name must not be longer than 25 characters"
 // +kubebuilder:metadata:labels="openmcp.cloud/cluster=onboarding"
@@ -88,6 +186,11 @@ func (p *Project) TypeIdentifier() string {
 	return "project"
 }
 
+// DeletionBlockingSource returns the source of the deletion-blocking resource types which come from the spec of this project, e.g. 'Project[sample]'.
+func (p *Project) DeletionBlockingSource() string {
+	return fmt.Sprintf("%s[%s]", SourceProjectPrefix, p.Name)
+}
+
 func (p *Project) UserInfoRoles(userInfo authv1.UserInfo) []ProjectMemberRole {
 	effectiveRoles := sets.Set[ProjectMemberRole]{}
 
@@ -121,6 +224,16 @@ func (p *Project) UserInfoHasRole(userInfo authv1.UserInfo, role ProjectMemberRo
 	return false
 }
 
+// GetCondition returns the condition of the given type, or nil if the project does not have it.
+func (p *Project) GetCondition(conditionType ConditionType) *Condition {
+	for i := range p.Status.Conditions {
+		if p.Status.Conditions[i].Type == conditionType {
+			return &p.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
 func (p *Project) SetOrUpdateCondition(condition Condition) {
 	var existingCondition *Condition
 	for i, c := range p.Status.Conditions {
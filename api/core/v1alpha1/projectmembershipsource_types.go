@@ -0,0 +1,148 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// MembershipSourceKindConfigMap is the kind of a membership source which is stored in a ConfigMap.
+	MembershipSourceKindConfigMap = "ConfigMap"
+	// MembershipSourceKindSecret is the kind of a membership source which is stored in a Secret.
+	MembershipSourceKindSecret = "Secret"
+	// DefaultMembershipSourceKey is the key of the data entry which contains the memberships, if the reference does not specify one.
+	DefaultMembershipSourceKey = "members.yaml"
+)
+
+const (
+	// ConditionTypeSynced is a condition type that indicates whether the members of a ProjectMembershipSource
+	// have been synced to all projects and workspaces it contains.
+	ConditionTypeSynced ConditionType = "Synced"
+
+	// ConditionReasonMembersSynced is a condition reason that indicates that the members have been synced to all projects and workspaces.
+	ConditionReasonMembersSynced ConditionReason = "MembersSynced"
+
+	// ConditionReasonSourceNotFound is a condition reason that indicates that the referenced ConfigMap or Secret does not exist.
+	// The members which have been synced before are kept.
+	ConditionReasonSourceNotFound ConditionReason = "SourceNotFound"
+
+	// ConditionReasonSourceInvalid is a condition reason that indicates that the content of the referenced ConfigMap or Secret cannot be parsed.
+	// The members which have been synced before are kept.
+	ConditionReasonSourceInvalid ConditionReason = "SourceInvalid"
+
+	// ConditionReasonTargetsSkipped is a condition reason that indicates that some projects or workspaces of the source have been skipped,
+	// because they don't exist or their members are already synced from another source.
+	ConditionReasonTargetsSkipped ConditionReason = "TargetsSkipped"
+)
+
+// ProjectMembershipSourceSpec references the ConfigMap or Secret which contains the members of projects and workspaces.
+type ProjectMembershipSourceSpec struct {
+	// SourceRef references the ConfigMap or Secret which contains the memberships,
+	// usually one which is kept in sync with a Git repository by a GitOps tool like Flux or Argo CD.
+	SourceRef MembershipSourceReference `json:"sourceRef"`
+}
+
+// MembershipSourceReference references a data entry of a ConfigMap or Secret on the onboarding cluster.
+type MembershipSourceReference struct {
+	// Kind of the referenced object, either 'ConfigMap' or 'Secret'.
+	// +kubebuilder:validation:Enum=ConfigMap;Secret
+	Kind string `json:"kind"`
+
+	// Name of the referenced object.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace of the referenced object.
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+
+	// Key of the data entry which contains the memberships. Defaults to 'members.yaml'.
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// DataKey returns the key of the data entry which contains the memberships.
+func (r MembershipSourceReference) DataKey() string {
+	if r.Key == "" {
+		return DefaultMembershipSourceKey
+	}
+	return r.Key
+}
+
+// ProjectMembershipSourceStatus contains the projects and workspaces whose members are synced from the source.
+type ProjectMembershipSourceStatus struct {
+	// ObservedGeneration is the generation of the spec the status has been computed for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Projects are the names of the projects whose members are synced from the source.
+	// +optional
+	Projects []string `json:"projects,omitempty"`
+
+	// Workspaces are the workspaces whose members are synced from the source, as '<project>/<workspace>'.
+	// +optional
+	Workspaces []string `json:"workspaces,omitempty"`
+
+	// Conditions contains the Synced condition, which shows whether all projects and workspaces of the source could be synced.
+	// +optional
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// ProjectMembershipSource imports the members of projects and workspaces from a ConfigMap or Secret,
+// which is usually kept in sync with a Git repository by a GitOps tool.
+// The imported members are merged into the members of the projects and workspaces and marked as managed by the source,
+// so that they cannot be changed manually anymore. Other members are not touched.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Kind",type="string",JSONPath=".spec.sourceRef.kind"
+// +kubebuilder:printcolumn:name="Source",type="string",JSONPath=".spec.sourceRef.name"
+// +kubebuilder:printcolumn:name="Synced",type="string",JSONPath=".status.conditions[?(@.type==\"Synced\")].status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:metadata:labels="openmcp.cloud/cluster=onboarding"
+type ProjectMembershipSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ProjectMembershipSourceSpec   `json:"spec,omitempty"`
+	Status ProjectMembershipSourceStatus `json:"status,omitempty"`
+}
+
+// GetCondition returns the condition of the given type, or nil if the source does not have it.
+func (s *ProjectMembershipSource) GetCondition(conditionType ConditionType) *Condition {
+	for i := range s.Status.Conditions {
+		if s.Status.Conditions[i].Type == conditionType {
+			return &s.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// SetOrUpdateCondition adds the given condition or replaces the existing one of the same type.
+// The transition time is only updated if the status changes.
+func (s *ProjectMembershipSource) SetOrUpdateCondition(condition Condition) {
+	existingCondition := s.GetCondition(condition.Type)
+	if existingCondition == nil {
+		condition.LastTransitionTime = metav1.Now()
+		s.Status.Conditions = append(s.Status.Conditions, condition)
+		return
+	}
+	if existingCondition.Status != condition.Status {
+		condition.LastTransitionTime = metav1.Now()
+	} else {
+		condition.LastTransitionTime = existingCondition.LastTransitionTime
+	}
+	*existingCondition = condition
+}
+
+// +kubebuilder:object:root=true
+
+// ProjectMembershipSourceList contains a list of ProjectMembershipSource
+type ProjectMembershipSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ProjectMembershipSource `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ProjectMembershipSource{}, &ProjectMembershipSourceList{})
+}
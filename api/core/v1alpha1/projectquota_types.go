@@ -0,0 +1,64 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ProjectQuotaSpec defines the number of projects a single user is allowed to create.
+type ProjectQuotaSpec struct {
+	// User is the name of the user the quota applies to, as in the 'core.openmcp.cloud/created-by' annotation of the projects.
+	// ServiceAccounts are specified as 'system:serviceaccount:<namespace>:<name>'.
+	// +kubebuilder:validation:MinLength=1
+	User string `json:"user"`
+
+	// MaxProjects is the maximum number of projects the user can create.
+	// It replaces the 'maxProjectsPerCreator' value of the ProjectWorkspaceConfig for this user.
+	// +kubebuilder:validation:Minimum=0
+	MaxProjects int32 `json:"maxProjects"`
+}
+
+// ProjectQuotaStatus contains the projects which count against the quota.
+type ProjectQuotaStatus struct {
+	// ObservedGeneration is the generation of the spec the status has been computed for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Used is the number of existing projects which have been created by the user.
+	// +optional
+	Used int32 `json:"used"`
+
+	// Projects are the names of the existing projects which have been created by the user.
+	// +optional
+	Projects []string `json:"projects,omitempty"`
+}
+
+// ProjectQuota limits the number of projects a single user can create, e.g. to stop runaway automation.
+// The limit is enforced by the project webhook, the status shows the current usage.
+// If there are multiple ProjectQuotas for the same user, the lowest limit applies.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="User",type="string",JSONPath=".spec.user"
+// +kubebuilder:printcolumn:name="Max",type="integer",JSONPath=".spec.maxProjects"
+// +kubebuilder:printcolumn:name="Used",type="integer",JSONPath=".status.used"
+// +kubebuilder:metadata:labels="openmcp.cloud/cluster=onboarding"
+type ProjectQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ProjectQuotaSpec   `json:"spec,omitempty"`
+	Status ProjectQuotaStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ProjectQuotaList contains a list of ProjectQuota
+type ProjectQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ProjectQuota `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ProjectQuota{}, &ProjectQuotaList{})
+}
@@ -1,19 +1,61 @@
 package v1alpha1
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 const (
 	EventReasonReconcileFailed    = "ReconcileFailed"
 	EventReasonReconcileSucceeded = "ReconcileSucceeded"
+	// EventReasonDeletionBlocked is the reason of the warning event which is recorded when the ProjectWorkspaceConfig is deleted while Projects or Workspaces still exist.
+	EventReasonDeletionBlocked = "DeletionBlocked"
+	// EventReasonOnboardingAccessDegraded is the reason of the warning event which is recorded when the circuit breaker for the dynamic onboarding cluster access opens.
+	EventReasonOnboardingAccessDegraded = "OnboardingAccessDegraded"
+	// EventReasonOnboardingAccessRecovered is the reason of the event which is recorded when the dynamic onboarding cluster access is available again after it has been degraded.
+	EventReasonOnboardingAccessRecovered = "OnboardingAccessRecovered"
+	// EventReasonClusterRolesRestored is the reason of the warning event which is recorded when the ClusterRoles of the project and workspace roles had to be recreated after they have been deleted.
+	EventReasonClusterRolesRestored = "ClusterRolesRestored"
 
 	SourceBuiltin                = "Builtin"
 	SourceProjectWorkspaceConfig = "ProjectWorkspaceConfig"
 	SourceServiceProviderPrefix  = "ServiceProvider"
 )
 
+const (
+	// ConditionTypeOnboardingAccessDegraded is a condition type that indicates that the AccessRequest for the dynamic onboarding cluster access
+	// cannot be reconciled, e.g. because the cluster provider is degraded. The configuration is still served and the static onboarding cluster access
+	// keeps being used, but resources blocking deletion which are registered by ServiceProviders cannot be checked without a previously granted access.
+	ConditionTypeOnboardingAccessDegraded ConditionType = "OnboardingAccessDegraded"
+
+	// ConditionReasonAccessRequestFailing is a condition reason that indicates that the reconciliation of the AccessRequest failed and is retried with backoff.
+	ConditionReasonAccessRequestFailing ConditionReason = "AccessRequestFailing"
+
+	// ConditionReasonCircuitBreakerOpen is a condition reason that indicates that the reconciliation of the AccessRequest failed repeatedly
+	// and is paused until the cool-down of the circuit breaker has passed.
+	ConditionReasonCircuitBreakerOpen ConditionReason = "CircuitBreakerOpen"
+
+	// ConditionReasonAccessAvailable is a condition reason that indicates that the dynamic onboarding cluster access is available.
+	ConditionReasonAccessAvailable ConditionReason = "AccessAvailable"
+)
+
 // ProjectWorkspaceConfigSpec defines the desired state of ProjectWorkspaceConfig
 type ProjectWorkspaceConfigSpec struct {
 	// +optional
@@ -25,13 +67,84 @@ type ProjectWorkspaceConfigSpec struct {
 	// Leave empty to disable.
 	// +optional
 	MemberOverrides MemberOverrides `json:"memberOverrides,omitempty"`
+	// MemberOverrideSetSelector selects the MemberOverrideSets whose overrides are added to the MemberOverrides.
+	// An empty selector selects all sets. If not set, no MemberOverrideSets are used.
+	// +optional
+	MemberOverrideSetSelector *metav1.LabelSelector `json:"memberOverrideSetSelector,omitempty"`
+	// MemberOverridePruning configures the removal of member override resources which reference deleted projects or workspaces.
+	// Such resources are always listed in the status, they are only removed from the member overrides if this is configured.
+	// +optional
+	MemberOverridePruning *MemberOverridePruningConfig `json:"memberOverridePruning,omitempty"`
 	// Webhook contains the configuration for the webhooks.
 	// +optional
 	Webhook WebhookConfig `json:"webhook"`
+	// ManagementLabels configures the labels which mark resources as managed by the platform service.
+	// +optional
+	ManagementLabels ManagementLabelsConfig `json:"managementLabels"`
+	// EventSink configures an external HTTPS endpoint, e.g. of a CMDB, to which an event is sent for each created, updated or deleted project and workspace.
+	// Leave empty to disable.
+	// +optional
+	EventSink *EventSinkConfig `json:"eventSink,omitempty"`
+	// Inventory enables ProjectInventory resources on the platform cluster, which contain read-only copies of the projects and workspaces,
+	// so that platform-side services like billing do not need access to the onboarding cluster.
+	// Leave empty to disable.
+	// +optional
+	Inventory *InventoryConfig `json:"inventory,omitempty"`
+	// Logging configures the log levels of the controllers and webhooks at runtime.
+	// +optional
+	Logging *LoggingConfig `json:"logging,omitempty"`
+}
+
+// ProjectWorkspaceConfigStatus shows how the platform service resolved the configuration.
+type ProjectWorkspaceConfigStatus struct {
+	// LastDiscoveryTime is the time when the resources registered by the ServiceProviders have been discovered the last time.
+	// +optional
+	LastDiscoveryTime *metav1.Time `json:"lastDiscoveryTime,omitempty"`
+	// ServiceProviders contains the resources registered by each ServiceProvider.
+	// Each of them blocks the deletion of workspaces and can be managed by workspace members, if its resource name could be discovered.
+	// +optional
+	ServiceProviders []ServiceProviderResources `json:"serviceProviders,omitempty"`
+	// StaleMemberOverrides lists the resources of member overrides which reference projects or workspaces that do not exist anymore.
+	// +optional
+	StaleMemberOverrides []StaleMemberOverride `json:"staleMemberOverrides,omitempty"`
+	// +optional
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// StaleMemberOverride is a resource of a member override which references a project or workspace that does not exist anymore.
+type StaleMemberOverride struct {
+	// Subject is the subject of the member override.
+	Subject `json:",inline"`
+	// Resource is the project or workspace which does not exist anymore.
+	Resource OverrideResource `json:"resource"`
+	// Since is the time when the project or workspace has been found to be missing for the first time.
+	Since metav1.Time `json:"since"`
+}
+
+// ServiceProviderResources contains the resources registered by a ServiceProvider.
+type ServiceProviderResources struct {
+	// Name is the name of the ServiceProvider.
+	Name string `json:"name"`
+	// Resources are the resources registered by the ServiceProvider.
+	// +optional
+	Resources []DiscoveredResource `json:"resources,omitempty"`
+}
+
+// DiscoveredResource is a resource kind together with the resource name which has been discovered for it.
+type DiscoveredResource struct {
+	metav1.GroupVersionKind `json:",inline"`
+	// Resource is the discovered resource name of the kind, e.g. 'managedcontrolplanev2s'.
+	// It is empty if the discovery failed.
+	// +optional
+	Resource string `json:"resource,omitempty"`
+	// Error contains the reason why the resource name could not be discovered.
+	// +optional
+	Error string `json:"error,omitempty"`
 }
 
 // ProjectWorkspaceConfig is the Schema for the ProjectWorkspaceConfigs API
 // +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster,shortName=pwcfg
 // +kubebuilder:metadata:labels="openmcp.cloud/cluster=platform"
 type ProjectWorkspaceConfig struct {
@@ -39,30 +152,663 @@ type ProjectWorkspaceConfig struct {
 	metav1.ObjectMeta `json:"metadata"`
 
 	Spec ProjectWorkspaceConfigSpec `json:"spec"`
+	// +optional
+	Status ProjectWorkspaceConfigStatus `json:"status,omitempty"`
+}
+
+// GetCondition returns the condition with the given type, or nil if it does not exist.
+func (pwc *ProjectWorkspaceConfig) GetCondition(conditionType ConditionType) *Condition {
+	for i, c := range pwc.Status.Conditions {
+		if c.Type == conditionType {
+			return &pwc.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// SetOrUpdateCondition sets or updates the condition with the given type.
+func (pwc *ProjectWorkspaceConfig) SetOrUpdateCondition(condition Condition) {
+	existingCondition := pwc.GetCondition(condition.Type)
+	if existingCondition == nil {
+		condition.LastTransitionTime = metav1.Now()
+		pwc.Status.Conditions = append(pwc.Status.Conditions, condition)
+	} else {
+		if existingCondition.Status != condition.Status {
+			condition.LastTransitionTime = metav1.Now()
+		} else {
+			condition.LastTransitionTime = existingCondition.LastTransitionTime
+		}
+		*existingCondition = condition
+	}
 }
 
 // ProjectConfig contains the configuration for projects.
 type ProjectConfig struct {
 	// +optional
 	ResourcesBlockingDeletion []metav1.GroupVersionKind `json:"resourcesBlockingDeletion,omitempty"`
+	// AllowedResourcesBlockingDeletion defines the resource types which individual projects may add to the resources blocking their own deletion via spec.resourcesBlockingDeletion.
+	// Resource types of projects which are not allowed (anymore) are ignored. Permissions to read them are requested for the dynamic onboarding cluster access.
+	// +optional
+	AllowedResourcesBlockingDeletion []metav1.GroupVersionKind `json:"allowedResourcesBlockingDeletion,omitempty"`
+	// IgnoredBlockingResources defines resources which are ignored when checking whether there are resources blocking the deletion of a project.
+	// +optional
+	IgnoredBlockingResources []DeletionIgnoreRule `json:"ignoredBlockingResources,omitempty"`
 	// AdditionalPermissions defines additional permissions users should have in a project, depending on their role.
 	// +optional
 	AdditionalPermissions map[ProjectMemberRole][]rbacv1.PolicyRule `json:"additionalPermissions,omitempty"`
+	// AuditorExcludedResources defines resources which members with the 'auditor' role must not be able to read, although the 'view' role can.
+	// Additional permissions which are explicitly configured for the 'auditor' role are not affected.
+	// If not set, secrets are excluded.
+	// +optional
+	AuditorExcludedResources []metav1.GroupResource `json:"auditorExcludedResources,omitempty"`
+	// AutomationServiceAccount configures an optional ServiceAccount in each project namespace, which allows automation like CI systems to access the project without a human member.
+	// +optional
+	AutomationServiceAccount *AutomationServiceAccountConfig `json:"automationServiceAccount,omitempty"`
+	// ConsolidatedClusterRoles specifies whether the 'view' and 'auditor' ClusterRoles and ClusterRoleBindings of each project are replaced by a single 'member' ClusterRole and ClusterRoleBinding,
+	// which grant read access to the project to all of its members. This reduces the number of cluster-scoped RBAC resources for installations with many projects.
+	// The 'admin' ClusterRole and ClusterRoleBinding are kept, because RBAC cannot grant different verbs to the subjects of a single binding.
+	// +optional
+	ConsolidatedClusterRoles *bool `json:"consolidatedClusterRoles,omitempty"`
+	// DeletionGracePeriod is the duration for which the teardown of a project is delayed after its deletion has been requested.
+	// During this period, the project and all of its content are kept, the project has the 'PendingDeletion' condition,
+	// and the deletion can be cancelled by adding the 'core.openmcp.cloud/cancel-deletion' annotation to the Project.
+	// If not set, the teardown starts immediately.
+	// +optional
+	DeletionGracePeriod *metav1.Duration `json:"deletionGracePeriod,omitempty"`
+	// SecretStores defines the external secret stores which projects can reference in their spec.
+	// References to other stores are rejected by the webhook and not applied by the project and workspace controllers.
+	// +optional
+	SecretStores []SecretStoreConfig `json:"secretStores,omitempty"`
+	// MaxProjectsPerCreator limits the number of projects a single user can create, e.g. to stop runaway automation.
+	// Projects are attributed to the user in their 'core.openmcp.cloud/created-by' annotation. The limit of individual users
+	// can be changed with ProjectQuota resources on the onboarding cluster. Identities which are excluded from the webhooks are not limited.
+	// If not set, only users with a ProjectQuota are limited.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxProjectsPerCreator *int32 `json:"maxProjectsPerCreator,omitempty"`
+}
+
+// SecretStoreConfig allows projects to reference locations in an external secret store.
+type SecretStoreConfig struct {
+	// Name is the name projects use to reference the store.
+	Name string `json:"name"`
+	// Annotation is the key of the namespace annotation which is set to the referenced path, e.g. to be matched by a ClusterSecretStore of the External Secrets Operator.
+	// Defaults to 'secretstore.core.openmcp.cloud/<name>'.
+	// +optional
+	Annotation string `json:"annotation,omitempty"`
+	// PathPrefix restricts the paths projects can reference, so that a project cannot access the secrets of another project.
+	// It is rendered as a Go template, with the fields of NetworkPolicyTemplateValues available, but only '{{ .Project }}' is set, e.g. 'teams/{{ .Project }}/'.
+	// If empty, any path can be referenced.
+	// +optional
+	PathPrefix string `json:"pathPrefix,omitempty"`
+}
+
+// AutomationServiceAccountConfig contains the configuration for the automation ServiceAccount of projects.
+type AutomationServiceAccountConfig struct {
+	// Enabled specifies whether the 'project-automation' ServiceAccount is created in each project namespace and bound to the project's admin role.
+	// Project admins can then request short-lived tokens for it via the 'core.openmcp.cloud/request-automation-token' annotation on the Project.
+	// +optional
+	Enabled bool `json:"enabled"`
+	// MaxTokenExpiration is the maximum lifetime of the tokens which are issued for the ServiceAccount.
+	// Requests for longer lifetimes are capped to this value. Must be at least 10m.
+	// Defaults to 1h.
+	// +optional
+	MaxTokenExpiration *metav1.Duration `json:"maxTokenExpiration,omitempty"`
 }
 
 // WorkspaceConfig contains the configuration for workspaces.
 type WorkspaceConfig struct {
 	// +optional
 	ResourcesBlockingDeletion []metav1.GroupVersionKind `json:"resourcesBlockingDeletion,omitempty"`
+	// IgnoredBlockingResources defines resources which are ignored when checking whether there are resources blocking the deletion of a workspace.
+	// +optional
+	IgnoredBlockingResources []DeletionIgnoreRule `json:"ignoredBlockingResources,omitempty"`
+	// BlockingResourcePolicies defines how the remaining resources of a kind which blocks the deletion of workspaces are handled.
+	// They apply to the resource types from the config, the builtin ones, the ones registered by ServiceProviders and the ones of WorkspaceClasses.
+	// Kinds without policy block the deletion.
+	// +optional
+	BlockingResourcePolicies []BlockingResourcePolicyRule `json:"blockingResourcePolicies,omitempty"`
 	// AdditionalPermissions defines additional permissions users should have in a workspace, depending on their role.
 	// +optional
 	AdditionalPermissions map[WorkspaceMemberRole][]rbacv1.PolicyRule `json:"additionalPermissions,omitempty"`
+	// AuditorExcludedResources defines resources which members with the 'auditor' role must not be able to read, although the 'view' role can.
+	// Additional permissions which are explicitly configured for the 'auditor' role are not affected.
+	// If not set, secrets are excluded.
+	// +optional
+	AuditorExcludedResources []metav1.GroupResource `json:"auditorExcludedResources,omitempty"`
+	// NetworkPolicies defines NetworkPolicies which are created in every workspace namespace, e.g. to establish a default-deny baseline.
+	// Manual changes to these NetworkPolicies are reverted, and NetworkPolicies which are removed from this list are deleted from the workspace namespaces.
+	// +optional
+	NetworkPolicies []NetworkPolicyTemplate `json:"networkPolicies,omitempty"`
+	// AdditionalResources defines arbitrary namespaced resources which are created in every workspace namespace, e.g. default ServiceAccounts or RoleBindings for platform groups.
+	// Changes are applied on the next reconciliation of each workspace, and resources which are removed from this list are deleted from the workspace namespaces.
+	// +optional
+	AdditionalResources []ResourceTemplate `json:"additionalResources,omitempty"`
+	// Flat specifies whether new workspaces are pure RBAC groupings within the namespace of their project, instead of getting a dedicated namespace.
+	// It can be overwritten per WorkspaceClass. Existing workspaces are not affected by changes.
+	// +optional
+	Flat *bool `json:"flat,omitempty"`
+	// ServiceAccountMembers restricts the namespaces of ServiceAccounts which can be members of workspaces.
+	// +optional
+	ServiceAccountMembers *ServiceAccountMembersConfig `json:"serviceAccountMembers,omitempty"`
+	// ProviderHints defines the placement hints which workspaces can pass to ServiceProviders in their spec.
+	// Hints with other keys, or for ServiceProviders which are not registered, are rejected by the webhook and not applied by the workspace controller.
+	// +optional
+	ProviderHints []ProviderHintConfig `json:"providerHints,omitempty"`
+	// PodSecurity defines the Pod Security Admission levels which are set as labels on every workspace namespace.
+	// If set, the platform service owns the 'pod-security.kubernetes.io/' labels of the workspace namespaces and reverts manual changes to them.
+	// WorkspaceClasses and projects can only choose stricter levels.
+	// +optional
+	PodSecurity *PodSecurityConfig `json:"podSecurity,omitempty"`
+}
+
+// ProviderHintConfig allows workspaces to pass a placement hint to a ServiceProvider via an annotation of their namespace.
+type ProviderHintConfig struct {
+	// Key is the key of the hint in the providerHints of workspaces, e.g. 'region'.
+	Key string `json:"key"`
+	// ServiceProvider is the name of the ServiceProvider which consumes the hint.
+	// Workspaces can only set the hint while this ServiceProvider is registered on the platform cluster.
+	ServiceProvider string `json:"serviceProvider"`
+	// Annotation is the key of the namespace annotation which is set to the value of the hint.
+	// Defaults to 'providerhint.core.openmcp.cloud/<key>'.
+	// +optional
+	Annotation string `json:"annotation,omitempty"`
+	// AllowedValues lists the values which workspaces can set for the hint, e.g. the regions supported by the ServiceProvider.
+	// If empty, any value can be set.
+	// +optional
+	AllowedValues []string `json:"allowedValues,omitempty"`
+}
+
+// ServiceAccountMembersConfig restricts the namespaces of ServiceAccounts which can be members of workspaces.
+type ServiceAccountMembersConfig struct {
+	// RestrictToProject specifies that ServiceAccount members of a workspace must reside in a namespace belonging to the same project,
+	// i.e. the project namespace or the namespace of one of the project's workspaces.
+	// Other ServiceAccounts are rejected by the webhook and not bound by the workspace controller.
+	// +optional
+	RestrictToProject bool `json:"restrictToProject"`
+	// AllowedNamespaces lists namespaces whose ServiceAccounts can be members of any workspace, e.g. namespaces of shared automation.
+	// Only evaluated if RestrictToProject is enabled.
+	// +optional
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+	// DeniedNamespaces lists sensitive namespaces, e.g. 'kube-system' or the namespaces of operators, whose ServiceAccounts cannot be added
+	// as members of projects and workspaces, except by admins by member override. It is evaluated regardless of RestrictToProject and AllowedNamespaces.
+	// Only the webhooks enforce this list, members which have been added before are still bound.
+	// +optional
+	DeniedNamespaces []string `json:"deniedNamespaces,omitempty"`
+}
+
+// DeniesNamespace returns true if ServiceAccounts from the given namespace can only be added as members by admins by member override.
+func (c *ServiceAccountMembersConfig) DeniesNamespace(namespace string) bool {
+	return c != nil && slices.Contains(c.DeniedNamespaces, namespace)
+}
+
+// AllowsNamespace returns true if ServiceAccounts from the given namespace can be members of the workspaces of the given project.
+// namespaceProject is the project the namespace belongs to, it is empty if the namespace does not belong to any project.
+func (c *ServiceAccountMembersConfig) AllowsNamespace(namespace, namespaceProject, project string) bool {
+	if c == nil || !c.RestrictToProject {
+		return true
+	}
+	if namespaceProject != "" && namespaceProject == project {
+		return true
+	}
+	return slices.Contains(c.AllowedNamespaces, namespace)
+}
+
+// PodSecurityLevel is a level of the Pod Security Standards.
+// +kubebuilder:validation:Enum=privileged;baseline;restricted
+type PodSecurityLevel string
+
+const (
+	PodSecurityLevelPrivileged PodSecurityLevel = "privileged"
+	PodSecurityLevelBaseline   PodSecurityLevel = "baseline"
+	PodSecurityLevelRestricted PodSecurityLevel = "restricted"
+
+	// PodSecurityLabelPrefix is the prefix of the namespace labels which configure the Pod Security Admission.
+	PodSecurityLabelPrefix = "pod-security.kubernetes.io/"
+	// PodSecurityVersionLatest is the version of the Pod Security Standards which is used if the config does not pin one.
+	PodSecurityVersionLatest = "latest"
+)
+
+// PodSecurityLevels defines the Pod Security Standards level for each mode of the Pod Security Admission.
+// Modes without a level are not configured.
+type PodSecurityLevels struct {
+	// Enforce is the level above which pods are rejected.
+	// +optional
+	Enforce PodSecurityLevel `json:"enforce,omitempty"`
+	// Warn is the level above which users get a warning when creating pods.
+	// +optional
+	Warn PodSecurityLevel `json:"warn,omitempty"`
+	// Audit is the level above which pods are recorded in the audit log.
+	// +optional
+	Audit PodSecurityLevel `json:"audit,omitempty"`
+}
+
+// PodSecurityConfig defines the Pod Security Admission labels of the workspace namespaces.
+type PodSecurityConfig struct {
+	PodSecurityLevels `json:",inline"`
+	// Version pins the version of the Pod Security Standards, e.g. 'v1.30'.
+	// Defaults to 'latest'.
+	// +optional
+	Version string `json:"version,omitempty"`
+}
+
+// NetworkPolicyTemplate describes a NetworkPolicy which is created in each workspace namespace.
+type NetworkPolicyTemplate struct {
+	// Name is the name of the NetworkPolicy.
+	Name string `json:"name"`
+	// Labels are set on the NetworkPolicy, in addition to the management labels.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Spec is the spec of the NetworkPolicy.
+	// It is rendered as a Go template, with the fields of NetworkPolicyTemplateValues available, e.g. '{{ .ProjectNamespace }}'.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	Spec runtime.RawExtension `json:"spec"`
+}
+
+// ResourceTemplate describes an arbitrary namespaced resource which is created in each workspace namespace.
+type ResourceTemplate struct {
+	// Manifest is the manifest of the resource, it must contain apiVersion, kind and metadata.name.
+	// The resource is always created in the workspace namespace, metadata.namespace can be omitted.
+	// It is rendered as a Go template, with the fields of NetworkPolicyTemplateValues available, e.g. '{{ .Workspace }}'.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	Manifest runtime.RawExtension `json:"manifest"`
+}
+
+// NetworkPolicyTemplateValues contains the values which can be used in the spec of a NetworkPolicyTemplate and in the manifest of a ResourceTemplate.
+type NetworkPolicyTemplateValues struct {
+	// Project is the name of the project the workspace belongs to.
+	Project string
+	// ProjectNamespace is the namespace of the project the workspace belongs to.
+	ProjectNamespace string
+	// Workspace is the name of the workspace.
+	Workspace string
+	// Namespace is the namespace of the workspace, in which the NetworkPolicy or resource is created.
+	Namespace string
+}
+
+// exampleTemplateValues are used to validate the templates of the config.
+var exampleTemplateValues = NetworkPolicyTemplateValues{
+	Project:          "example",
+	ProjectNamespace: "project-example",
+	Workspace:        "example",
+	Namespace:        "project-example--ws-example",
+}
+
+// DeletionIgnoreRule describes resources which should not block the deletion of a project or workspace, even if their kind is in the list of resources blocking deletion.
+// A resource is ignored if it matches all of the specified criteria.
+type DeletionIgnoreRule struct {
+	// Group restricts this rule to resources of the given API group. Use "" for the core group.
+	// Only evaluated if Kind is set.
+	// +optional
+	Group string `json:"group,omitempty"`
+	// Kind restricts this rule to resources of the given kind.
+	// If empty, the rule applies to resources of all kinds.
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// LabelSelector matches the labels of the resources to ignore.
+	// +optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+	// NamePatterns is a list of shell file name patterns (e.g. 'sh.helm.release.v1.*'), of which the name of the resource to ignore must match at least one.
+	// +optional
+	NamePatterns []string `json:"namePatterns,omitempty"`
+}
+
+// BlockingResourcePolicy determines how the remaining resources of a kind which blocks the deletion of a workspace are handled.
+// +kubebuilder:validation:Enum=Block;Warn;Cascade
+type BlockingResourcePolicy string
+
+const (
+	// BlockingResourcePolicyBlock keeps the workspace until the resources have been deleted by someone else, e.g. their ServiceProvider.
+	BlockingResourcePolicyBlock BlockingResourcePolicy = "Block"
+	// BlockingResourcePolicyWarn reports the resources in the ContentRemaining condition and in an event, but does not wait for them.
+	// They are deleted together with the workspace namespace.
+	BlockingResourcePolicyWarn BlockingResourcePolicy = "Warn"
+	// BlockingResourcePolicyCascade makes the platform service delete the resources and keeps the workspace until they are gone.
+	BlockingResourcePolicyCascade BlockingResourcePolicy = "Cascade"
+)
+
+// BlockingResourcePolicyRule sets the policy for the remaining resources of a kind which blocks the deletion of workspaces.
+type BlockingResourcePolicyRule struct {
+	// Group is the API group of the resources. Use "" for the core group.
+	// +optional
+	Group string `json:"group,omitempty"`
+	// Kind is the kind of the resources.
+	Kind string `json:"kind"`
+	// Policy determines how the remaining resources are handled.
+	Policy BlockingResourcePolicy `json:"policy"`
+}
+
+// Validate checks that the rule references a kind and sets a known policy.
+func (r *BlockingResourcePolicyRule) Validate() error {
+	if r.Kind == "" {
+		return fmt.Errorf("kind must not be empty")
+	}
+	switch r.Policy {
+	case BlockingResourcePolicyBlock, BlockingResourcePolicyWarn, BlockingResourcePolicyCascade:
+		return nil
+	}
+	return fmt.Errorf("unknown policy '%s', must be one of '%s', '%s', '%s'", r.Policy, BlockingResourcePolicyBlock, BlockingResourcePolicyWarn, BlockingResourcePolicyCascade)
+}
+
+// BlockingResourcePolicyFor returns the policy of the first of the given rules which matches the given group and kind, the kind is compared case-insensitively.
+// Resources without matching rule block the deletion.
+func BlockingResourcePolicyFor(rules []BlockingResourcePolicyRule, group, kind string) BlockingResourcePolicy {
+	for _, rule := range rules {
+		if rule.Group == group && strings.EqualFold(rule.Kind, kind) {
+			return rule.Policy
+		}
+	}
+	return BlockingResourcePolicyBlock
 }
 
 type WebhookConfig struct {
 	// Disabled specifies whether the webhooks should be disabled.
 	// +optional
 	Disabled bool `json:"disabled"`
+	// ExcludedIdentities is a list of system identities (e.g. service accounts of GitOps tools or migration jobs) which are excluded from the webhooks' membership validation.
+	// This allows automation to manage projects and workspaces without being a member of them.
+	// The platform service's own identity is always excluded and does not need to be listed here.
+	// +optional
+	ExcludedIdentities []IdentityMatcher `json:"excludedIdentities,omitempty"`
+	// AddCreatorAsAdmin specifies whether the mutating webhooks add the requesting user as admin to projects and workspaces which are created without any admin member.
+	// Otherwise, the validating webhooks reject such resources, because the requesting user would not be able to manage them.
+	// +optional
+	AddCreatorAsAdmin bool `json:"addCreatorAsAdmin,omitempty"`
+	// DNS configures how the webhooks are exposed to the onboarding cluster, if it differs from the platform cluster.
+	// +optional
+	DNS DNSConfig `json:"dns"`
+	// ChargingTarget configures the validation of the charging target annotation of projects and workspaces.
+	// Leave empty to accept any value.
+	// +optional
+	ChargingTarget *ChargingTargetConfig `json:"chargingTarget,omitempty"`
+	// MemberPolicy restricts the members of projects and workspaces.
+	// Leave empty to allow any number and kind of members.
+	// +optional
+	MemberPolicy *MemberPolicyConfig `json:"memberPolicy,omitempty"`
+	// ProjectAdminsManageWorkspaces specifies whether the validating webhooks accept requests for workspaces from admins of the parent project,
+	// even if they are not admin of the workspace itself.
+	// +optional
+	ProjectAdminsManageWorkspaces bool `json:"projectAdminsManageWorkspaces,omitempty"`
+	// OperatorIdentities configures further identities of the platform service, e.g. of replicas which use different ServiceAccounts.
+	// They are excluded from the webhooks' membership validation like the platform service's own identity.
+	// +optional
+	OperatorIdentities *OperatorIdentitiesConfig `json:"operatorIdentities,omitempty"`
+	// Lookups limits how long the validating webhooks wait for the resources they look up, e.g. the MemberOverrides or the parent project of a workspace,
+	// so that a slow API server does not cause admission timeouts.
+	// +optional
+	Lookups *WebhookLookupsConfig `json:"lookups,omitempty"`
+}
+
+// DNSProvider is the kind of infrastructure which is used to expose the webhooks under a host name.
+// +kubebuilder:validation:Enum=Gateway;LoadBalancer
+type DNSProvider string
+
+const (
+	// DNSProviderGateway exposes the webhooks via a TLSRoute attached to the default Gateway API gateway.
+	DNSProviderGateway DNSProvider = "Gateway"
+	// DNSProviderLoadBalancer exposes the webhooks via a Service of type LoadBalancer, which is annotated for external-dns.
+	DNSProviderLoadBalancer DNSProvider = "LoadBalancer"
+)
+
+// DNSConfig contains the configuration for exposing the webhooks.
+type DNSConfig struct {
+	// Provider is the kind of infrastructure which is used to expose the webhooks.
+	// Defaults to 'Gateway'.
+	// +optional
+	Provider DNSProvider `json:"provider,omitempty"`
+	// BaseDomain is the domain under which the host name for the webhooks is created.
+	// Required for the 'LoadBalancer' provider, the 'Gateway' provider takes the base domain from the annotation of the gateway instead.
+	// +optional
+	BaseDomain string `json:"baseDomain,omitempty"`
+	// ServiceAnnotations are added to the Service created by the 'LoadBalancer' provider, e.g. to configure the load balancer of the cloud provider.
+	// +optional
+	ServiceAnnotations map[string]string `json:"serviceAnnotations,omitempty"`
+	// HostNames maps environments to the host name under which the webhooks of the platform service instance with this environment are exposed.
+	// Environments which are not listed use the host name 'pwo-webhooks.<base domain>'.
+	// +optional
+	HostNames map[string]string `json:"hostNames,omitempty"`
+	// Port is the port under which the webhooks are exposed.
+	// For the 'LoadBalancer' provider, this is the port of the Service, which defaults to 443.
+	// For the 'Gateway' provider, this selects the TLS listener of the gateway with this port, unless a listener name is configured.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+	// IPFamilies are the IP families under which the webhooks are reachable, e.g. both 'IPv4' and 'IPv6' for dual-stack clusters.
+	// The 'LoadBalancer' provider requests a load balancer with these IP families, and both providers only consider the route ready
+	// once the load balancer or gateway has an address of each family, so that the A and AAAA records can be created.
+	// If empty, the IP families are not configured and not checked.
+	// +kubebuilder:validation:MaxItems=2
+	// +kubebuilder:validation:items:Enum=IPv4;IPv6
+	// +listType=set
+	// +optional
+	IPFamilies []corev1.IPFamily `json:"ipFamilies,omitempty"`
+	// Gateway selects the gateway and listener which are used by the 'Gateway' provider.
+	// +optional
+	Gateway *GatewayDNSConfig `json:"gateway,omitempty"`
+}
+
+// GatewayDNSConfig selects the Gateway API gateway and listener to which the route for the webhooks is attached.
+type GatewayDNSConfig struct {
+	// Name of the gateway.
+	// Defaults to 'default'.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Namespace of the gateway.
+	// Defaults to 'openmcp-system'.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// ListenerName is the name of the TLS listener of the gateway to which the route is attached.
+	// If empty, the gateway's only TLS listener, or the TLS listener named 'tls' if there are multiple ones, is used.
+	// +optional
+	ListenerName string `json:"listenerName,omitempty"`
+}
+
+// IdentityMatcher matches the username of a requesting entity, either exactly or by prefix.
+// Exactly one of Name and Prefix must be set.
+type IdentityMatcher struct {
+	// Name is the exact username to match, e.g. 'system:serviceaccount:flux-system:kustomize-controller'.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Prefix matches all usernames starting with the given prefix, e.g. 'system:serviceaccount:migration:'.
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+}
+
+// ManagementLabelsConfig configures the labels which are set on all resources managed by the platform service.
+type ManagementLabelsConfig struct {
+	// Labels are set on all managed resources, in addition to the default 'openmcp.cloud/managed-by' and 'openmcp.cloud/managed-purpose' labels.
+	// They can also be used to overwrite the values of the default labels.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// PreviousLabels is a list of label sets which have been used to mark managed resources in the past.
+	// Resources which carry all labels of any of these sets are still considered to be managed by the platform service.
+	// Labels from these sets which are not part of the current label set are removed from managed resources during reconciliation.
+	// +optional
+	PreviousLabels []map[string]string `json:"previousLabels,omitempty"`
+}
+
+const (
+	// ChargingTargetAllowedValuesKey is the key of the allowed charging targets in the ConfigMap referenced by the charging target configuration.
+	ChargingTargetAllowedValuesKey = "allowedValues"
+	// DefaultChargingTargetCacheDuration is the default duration for which the allowed charging targets are cached.
+	DefaultChargingTargetCacheDuration = 5 * time.Minute
+	// DefaultOperatorIdentitiesRefreshInterval is the default duration for which the selected operator identities are cached.
+	DefaultOperatorIdentitiesRefreshInterval = 1 * time.Minute
+	// DefaultMemberOverrideRetentionPeriod is the default duration for which a member override resource has to be missing before it is pruned.
+	DefaultMemberOverrideRetentionPeriod = 24 * time.Hour
+)
+
+// ChargingTargetConfig configures the validation of the charging target annotation, so that typos in e.g. cost center IDs are rejected at admission time.
+// Values are only validated when they are set or changed, so that existing projects and workspaces can still be updated if the allowed values change.
+type ChargingTargetConfig struct {
+	// Required specifies whether projects must have a charging target.
+	// Workspaces without a charging target are charged to the target of their project and are not affected.
+	// +optional
+	Required bool `json:"required,omitempty"`
+	// Pattern is a regular expression which charging targets must match completely, e.g. 'cc-[0-9]{4}'.
+	// +optional
+	Pattern string `json:"pattern,omitempty"`
+	// AllowedValuesConfigMapName is the name of a ConfigMap in the namespace of the platform service on the platform cluster.
+	// Its 'allowedValues' entry lists the allowed charging targets, one per line. Empty lines and lines starting with '#' are ignored.
+	// +optional
+	AllowedValuesConfigMapName string `json:"allowedValuesConfigMapName,omitempty"`
+	// CacheDuration is the duration for which the allowed charging targets are cached, before the ConfigMap is read again.
+	// Defaults to 5m.
+	// +optional
+	CacheDuration *metav1.Duration `json:"cacheDuration,omitempty"`
+}
+
+// OperatorIdentitiesConfig selects the identities of all replicas of the platform service.
+// It is required if the replicas use different identities to access the onboarding cluster, e.g. per-zone installations,
+// because each replica only determines its own identity at startup.
+type OperatorIdentitiesConfig struct {
+	// ServiceAccountSelector selects ServiceAccounts in all namespaces of the onboarding cluster, whose identities are treated like the platform service's own identity.
+	ServiceAccountSelector metav1.LabelSelector `json:"serviceAccountSelector"`
+	// RefreshInterval is the duration for which the selected ServiceAccounts are cached, before they are listed again.
+	// Defaults to 1m.
+	// +optional
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+}
+
+// MemberOverridePruningConfig configures the removal of member override resources which reference deleted projects or workspaces.
+// Member overrides whose resources have all been removed are removed completely, since an override without resources applies to all projects and workspaces.
+type MemberOverridePruningConfig struct {
+	// Enabled specifies whether stale resources are removed from the member overrides of the ProjectWorkspaceConfig.
+	// Member overrides from a ProjectWorkspaceConfigOverride are never changed.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// RetentionPeriod is the duration for which a resource has to be missing before it is removed from the member overrides,
+	// so that overrides for projects or workspaces which are recreated, e.g. during a migration, are kept.
+	// Defaults to 24h.
+	// +optional
+	RetentionPeriod *metav1.Duration `json:"retentionPeriod,omitempty"`
+}
+
+// MemberPolicyConfig restricts the members of projects and workspaces, which is enforced by the webhooks.
+// The policy is only enforced for changes which violate it, so that existing projects and workspaces can still be updated after the policy has been tightened.
+// Excluded identities are exempt from the policy.
+type MemberPolicyConfig struct {
+	// MaxProjectMembers is the maximum number of members of a project.
+	// Zero means unlimited.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxProjectMembers int32 `json:"maxProjectMembers,omitempty"`
+	// MaxWorkspaceMembers is the maximum number of members of a workspace.
+	// Zero means unlimited.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxWorkspaceMembers int32 `json:"maxWorkspaceMembers,omitempty"`
+	// GroupsOnly forbids User members in projects and workspaces, so that access is granted via groups.
+	// ServiceAccount members are still allowed. If AddCreatorAsAdmin is enabled, requesting users are not added as members.
+	// +optional
+	GroupsOnly bool `json:"groupsOnly,omitempty"`
+}
+
+// WebhookCheck is a check of the validating webhooks which looks up other resources and whose failure policy can be configured.
+// Checks which decide whether the requesting user may change a resource, e.g. via the MemberOverrides, always fail closed.
+// +kubebuilder:validation:Enum=ProjectQuota;WorkspaceClass;DeletionBlockers
+type WebhookCheck string
+
+const (
+	// WebhookCheckProjectQuota counts the projects of the creator of a new project.
+	WebhookCheckProjectQuota WebhookCheck = "ProjectQuota"
+	// WebhookCheckWorkspaceClass verifies that the WorkspaceClass of a new workspace exists.
+	WebhookCheckWorkspaceClass WebhookCheck = "WorkspaceClass"
+	// WebhookCheckDeletionBlockers lists the resources which block the deletion of a workspace.
+	// It uses 'Ignore' by default, since the deletion is enforced by the finalizer of the workspace anyway.
+	WebhookCheckDeletionBlockers WebhookCheck = "DeletionBlockers"
+)
+
+// WebhookFailurePolicy determines how a check of the validating webhooks reacts if its lookups fail or time out.
+// +kubebuilder:validation:Enum=Fail;Ignore
+type WebhookFailurePolicy string
+
+const (
+	// WebhookFailurePolicyFail rejects the request (fail closed).
+	WebhookFailurePolicyFail WebhookFailurePolicy = "Fail"
+	// WebhookFailurePolicyIgnore skips the check and accepts the request, unless another check rejects it (fail open).
+	WebhookFailurePolicyIgnore WebhookFailurePolicy = "Ignore"
+)
+
+const (
+	// DefaultWebhookLookupTimeout is the default time budget for the lookups of a single admission request.
+	// It leaves room for the remaining checks within the default webhook timeout of the API server of 10s.
+	DefaultWebhookLookupTimeout = 5 * time.Second
+	// DefaultWebhookLookupRetries is the default number of retries of a failed lookup.
+	DefaultWebhookLookupRetries = 1
+)
+
+// WebhookLookupsConfig configures the lookups of the validating webhooks.
+type WebhookLookupsConfig struct {
+	// Timeout is the time budget for all lookups of a single admission request. Lookups which are still running when it is exhausted fail.
+	// Defaults to 5s.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// Retries is the number of times a lookup which failed with a transient error, e.g. a server timeout, is retried within the time budget.
+	// Defaults to 1.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Retries *int32 `json:"retries,omitempty"`
+	// FailurePolicies maps checks to the policy which is applied if their lookups fail.
+	// Checks which are not listed use 'Fail', except for 'DeletionBlockers', which uses 'Ignore'.
+	// +kubebuilder:validation:XValidation:rule="self.all(k, k in ['ProjectQuota', 'WorkspaceClass', 'DeletionBlockers'])",message="only the checks ProjectQuota, WorkspaceClass and DeletionBlockers can be configured"
+	// +kubebuilder:validation:XValidation:rule="!('Membership' in self) || self['Membership'] == 'Fail'",message="membership lookups always fail closed"
+	// +optional
+	FailurePolicies map[WebhookCheck]WebhookFailurePolicy `json:"failurePolicies,omitempty"`
+}
+
+const (
+	// EventSinkSigningKeyKey is the key of the HMAC signing key in the Secret referenced by the event sink configuration.
+	EventSinkSigningKeyKey = "signingKey"
+	// DefaultEventSinkMaxRetries is the default number of retries for the delivery of an event to the event sink.
+	DefaultEventSinkMaxRetries = 10
+	// DefaultEventSinkTimeout is the default timeout for a single request to the event sink.
+	DefaultEventSinkTimeout = 10 * time.Second
+)
+
+// EventSinkConfig configures the endpoint which receives an event for each change of a project or workspace.
+type EventSinkConfig struct {
+	// URL is the HTTPS endpoint to which the events are POSTed as JSON.
+	// +kubebuilder:validation:Pattern=`^https://`
+	URL string `json:"url"`
+	// SigningSecretName is the name of a Secret in the namespace of the platform service on the platform cluster.
+	// Its 'signingKey' entry is used to compute an HMAC-SHA256 signature of each request body, which is sent in the 'X-Signature-256' header.
+	SigningSecretName string `json:"signingSecretName"`
+	// MaxRetries is the number of times the delivery of an event is retried with exponential backoff before the event is dropped.
+	// Defaults to 10.
+	// +optional
+	MaxRetries *int32 `json:"maxRetries,omitempty"`
+	// Timeout is the timeout for a single request to the endpoint.
+	// Defaults to 10s.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// LogLevel is the verbosity of the logs.
+// +kubebuilder:validation:Enum=error;info;debug
+type LogLevel string
+
+const (
+	LogLevelError LogLevel = "error"
+	LogLevelInfo  LogLevel = "info"
+	LogLevelDebug LogLevel = "debug"
+)
+
+// LoggingConfig configures the log levels of the controllers and webhooks, which are applied without restarting the platform service.
+// The levels can only reduce the verbosity which is configured via the '--verbosity' flag of the platform service.
+type LoggingConfig struct {
+	// DefaultLevel is the log level of all logs which do not belong to a controller or webhook with an explicitly configured level.
+	// If not set, the verbosity configured via the '--verbosity' flag is used.
+	// +optional
+	DefaultLevel LogLevel `json:"defaultLevel,omitempty"`
+	// Controllers maps the names of controllers and webhooks, e.g. 'project', 'workspace' or 'project-webhook', to their log level.
+	// +optional
+	Controllers map[string]LogLevel `json:"controllers,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -79,9 +825,778 @@ func init() {
 }
 
 // SetDefaults sets the default values for the project workspace configuration when not set.
-func (pwc *ProjectWorkspaceConfig) SetDefaults() {}
+func (pwc *ProjectWorkspaceConfig) SetDefaults() {
+	if pwc.Spec.Webhook.DNS.Provider == "" {
+		pwc.Spec.Webhook.DNS.Provider = DNSProviderGateway
+	}
+	for i, gvk := range pwc.Spec.Project.ResourcesBlockingDeletion {
+		pwc.Spec.Project.ResourcesBlockingDeletion[i] = NormalizeGroupVersionKind(gvk)
+	}
+	for i, gvk := range pwc.Spec.Project.AllowedResourcesBlockingDeletion {
+		pwc.Spec.Project.AllowedResourcesBlockingDeletion[i] = NormalizeGroupVersionKind(gvk)
+	}
+	for i, gvk := range pwc.Spec.Workspace.ResourcesBlockingDeletion {
+		pwc.Spec.Workspace.ResourcesBlockingDeletion[i] = NormalizeGroupVersionKind(gvk)
+	}
+}
 
 // Validate validates the project workspace configuration.
 func (pwc *ProjectWorkspaceConfig) Validate() error {
+	for i, gvk := range pwc.Spec.Project.ResourcesBlockingDeletion {
+		if err := ValidateGroupVersionKind(gvk); err != nil {
+			return fmt.Errorf("invalid entry spec.project.resourcesBlockingDeletion[%d]: %w", i, err)
+		}
+	}
+	for i, gvk := range pwc.Spec.Project.AllowedResourcesBlockingDeletion {
+		if err := ValidateGroupVersionKind(gvk); err != nil {
+			return fmt.Errorf("invalid entry spec.project.allowedResourcesBlockingDeletion[%d]: %w", i, err)
+		}
+	}
+	for i, gvk := range pwc.Spec.Workspace.ResourcesBlockingDeletion {
+		if err := ValidateGroupVersionKind(gvk); err != nil {
+			return fmt.Errorf("invalid entry spec.workspace.resourcesBlockingDeletion[%d]: %w", i, err)
+		}
+	}
+	for i, rule := range pwc.Spec.Project.IgnoredBlockingResources {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("invalid entry spec.project.ignoredBlockingResources[%d]: %w", i, err)
+		}
+	}
+	for i, rule := range pwc.Spec.Workspace.IgnoredBlockingResources {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("invalid entry spec.workspace.ignoredBlockingResources[%d]: %w", i, err)
+		}
+	}
+	policyKinds := map[string]bool{}
+	for i, rule := range pwc.Spec.Workspace.BlockingResourcePolicies {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("invalid entry spec.workspace.blockingResourcePolicies[%d]: %w", i, err)
+		}
+		groupKind := rule.Group + "/" + strings.ToLower(rule.Kind)
+		if policyKinds[groupKind] {
+			return fmt.Errorf("invalid entry spec.workspace.blockingResourcePolicies[%d]: duplicate kind '%s' in group '%s'", i, rule.Kind, rule.Group)
+		}
+		policyKinds[groupKind] = true
+	}
+	for i, im := range pwc.Spec.Webhook.ExcludedIdentities {
+		if err := im.Validate(); err != nil {
+			return fmt.Errorf("invalid entry spec.webhook.excludedIdentities[%d]: %w", i, err)
+		}
+	}
+	if sel := pwc.Spec.MemberOverrideSetSelector; sel != nil {
+		if _, err := metav1.LabelSelectorAsSelector(sel); err != nil {
+			return fmt.Errorf("invalid spec.memberOverrideSetSelector: %w", err)
+		}
+	}
+	if asa := pwc.Spec.Project.AutomationServiceAccount; asa != nil {
+		if err := asa.Validate(); err != nil {
+			return fmt.Errorf("invalid spec.project.automationServiceAccount: %w", err)
+		}
+	}
+	if lc := pwc.Spec.Logging; lc != nil {
+		if err := lc.Validate(); err != nil {
+			return fmt.Errorf("invalid spec.logging: %w", err)
+		}
+	}
+	if gp := pwc.Spec.Project.DeletionGracePeriod; gp != nil && gp.Duration < 0 {
+		return fmt.Errorf("invalid spec.project.deletionGracePeriod: must not be negative")
+	}
+	if m := pwc.Spec.Project.MaxProjectsPerCreator; m != nil && *m < 0 {
+		return fmt.Errorf("invalid spec.project.maxProjectsPerCreator: must not be negative")
+	}
+	storeNames := map[string]bool{}
+	annotations := map[string]bool{}
+	for i, ss := range pwc.Spec.Project.SecretStores {
+		if err := ss.Validate(); err != nil {
+			return fmt.Errorf("invalid entry spec.project.secretStores[%d]: %w", i, err)
+		}
+		if storeNames[ss.Name] {
+			return fmt.Errorf("invalid entry spec.project.secretStores[%d]: duplicate name '%s'", i, ss.Name)
+		}
+		storeNames[ss.Name] = true
+		if annotations[ss.AnnotationKey()] {
+			return fmt.Errorf("invalid entry spec.project.secretStores[%d]: duplicate annotation '%s'", i, ss.AnnotationKey())
+		}
+		annotations[ss.AnnotationKey()] = true
+	}
+	// provider hints are set on the same namespaces as secret store references, so their annotations must not collide either
+	hintKeys := map[string]bool{}
+	for i, ph := range pwc.Spec.Workspace.ProviderHints {
+		if err := ph.Validate(); err != nil {
+			return fmt.Errorf("invalid entry spec.workspace.providerHints[%d]: %w", i, err)
+		}
+		if hintKeys[ph.Key] {
+			return fmt.Errorf("invalid entry spec.workspace.providerHints[%d]: duplicate key '%s'", i, ph.Key)
+		}
+		hintKeys[ph.Key] = true
+		if annotations[ph.AnnotationKey()] {
+			return fmt.Errorf("invalid entry spec.workspace.providerHints[%d]: duplicate annotation '%s'", i, ph.AnnotationKey())
+		}
+		annotations[ph.AnnotationKey()] = true
+	}
+	if ps := pwc.Spec.Workspace.PodSecurity; ps != nil {
+		if err := ps.Validate(); err != nil {
+			return fmt.Errorf("invalid spec.workspace.podSecurity: %w", err)
+		}
+	}
+	names := map[string]bool{}
+	for i, np := range pwc.Spec.Workspace.NetworkPolicies {
+		if err := np.Validate(); err != nil {
+			return fmt.Errorf("invalid entry spec.workspace.networkPolicies[%d]: %w", i, err)
+		}
+		if names[np.Name] {
+			return fmt.Errorf("invalid entry spec.workspace.networkPolicies[%d]: duplicate name '%s'", i, np.Name)
+		}
+		names[np.Name] = true
+	}
+	resources := map[AppliedResource]bool{}
+	for i, rt := range pwc.Spec.Workspace.AdditionalResources {
+		obj, err := rt.Render(exampleTemplateValues)
+		if err != nil {
+			return fmt.Errorf("invalid entry spec.workspace.additionalResources[%d]: %w", i, err)
+		}
+		ref := AppliedResourceOf(obj)
+		if resources[ref] {
+			return fmt.Errorf("invalid entry spec.workspace.additionalResources[%d]: duplicate resource %s", i, ref)
+		}
+		resources[ref] = true
+	}
+	if err := pwc.Spec.Webhook.DNS.Validate(); err != nil {
+		return fmt.Errorf("invalid spec.webhook.dns: %w", err)
+	}
+	if err := validateLabels(pwc.Spec.ManagementLabels.Labels); err != nil {
+		return fmt.Errorf("invalid spec.managementLabels.labels: %w", err)
+	}
+	for i, ls := range pwc.Spec.ManagementLabels.PreviousLabels {
+		if len(ls) == 0 {
+			return fmt.Errorf("invalid entry spec.managementLabels.previousLabels[%d]: label set must not be empty", i)
+		}
+		if err := validateLabels(ls); err != nil {
+			return fmt.Errorf("invalid entry spec.managementLabels.previousLabels[%d]: %w", i, err)
+		}
+	}
+	if ct := pwc.Spec.Webhook.ChargingTarget; ct != nil {
+		if err := ct.Validate(); err != nil {
+			return fmt.Errorf("invalid spec.webhook.chargingTarget: %w", err)
+		}
+	}
+	if mp := pwc.Spec.Webhook.MemberPolicy; mp != nil {
+		if err := mp.Validate(); err != nil {
+			return fmt.Errorf("invalid spec.webhook.memberPolicy: %w", err)
+		}
+	}
+	if mp := pwc.Spec.MemberOverridePruning; mp != nil {
+		if err := mp.Validate(); err != nil {
+			return fmt.Errorf("invalid spec.memberOverridePruning: %w", err)
+		}
+	}
+	if oi := pwc.Spec.Webhook.OperatorIdentities; oi != nil {
+		if err := oi.Validate(); err != nil {
+			return fmt.Errorf("invalid spec.webhook.operatorIdentities: %w", err)
+		}
+	}
+	if es := pwc.Spec.EventSink; es != nil {
+		if err := es.Validate(); err != nil {
+			return fmt.Errorf("invalid spec.eventSink: %w", err)
+		}
+	}
+	if wl := pwc.Spec.Webhook.Lookups; wl != nil {
+		if err := wl.Validate(); err != nil {
+			return fmt.Errorf("invalid spec.webhook.lookups: %w", err)
+		}
+	}
+	return nil
+}
+
+// kindPattern matches kinds in PascalCase, which is how Kubernetes names kinds.
+var kindPattern = regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`)
+
+// NormalizeGroupVersionKind returns the given GroupVersionKind with the corrections which are safe to apply:
+// surrounding whitespace is removed, the group and version are lower-cased, since API groups and versions are always lower-case,
+// and the first letter of the kind is upper-cased. Other letters of the kind are kept, since e.g. 'managedcontrolplane' cannot be
+// converted into 'ManagedControlPlane' reliably.
+func NormalizeGroupVersionKind(gvk metav1.GroupVersionKind) metav1.GroupVersionKind {
+	res := metav1.GroupVersionKind{
+		Group:   strings.ToLower(strings.TrimSpace(gvk.Group)),
+		Version: strings.ToLower(strings.TrimSpace(gvk.Version)),
+		Kind:    strings.TrimSpace(gvk.Kind),
+	}
+	if res.Kind != "" {
+		res.Kind = strings.ToUpper(res.Kind[:1]) + res.Kind[1:]
+	}
+	return res
+}
+
+// ValidateGroupVersionKind checks that the group of the given GroupVersionKind is empty (the core group) or a DNS subdomain,
+// that the version is a non-empty DNS label and that the kind is a non-empty name in PascalCase, so that the kind can be discovered.
+func ValidateGroupVersionKind(gvk metav1.GroupVersionKind) error {
+	if gvk.Group != "" {
+		if errs := validation.IsDNS1123Subdomain(gvk.Group); len(errs) > 0 {
+			return fmt.Errorf("invalid group '%s': %s", gvk.Group, strings.Join(errs, "; "))
+		}
+	}
+	if gvk.Version == "" {
+		return fmt.Errorf("version must not be empty")
+	}
+	if errs := validation.IsDNS1123Label(gvk.Version); len(errs) > 0 {
+		return fmt.Errorf("invalid version '%s': %s", gvk.Version, strings.Join(errs, "; "))
+	}
+	if gvk.Kind == "" {
+		return fmt.Errorf("kind must not be empty")
+	}
+	if !kindPattern.MatchString(gvk.Kind) {
+		return fmt.Errorf("invalid kind '%s': must be alphanumeric and start with an upper-case letter, e.g. 'ConfigMap'", gvk.Kind)
+	}
+	return nil
+}
+
+// validateLabels checks that the given keys and values are valid label keys and values.
+func validateLabels(ls map[string]string) error {
+	for k, v := range ls {
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return fmt.Errorf("invalid label key '%s': %s", k, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+			return fmt.Errorf("invalid value for label '%s': %s", k, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
+// Validate checks that the name is a valid DNS label, that the annotation is a valid annotation key, and that the path prefix can be rendered.
+func (ss *SecretStoreConfig) Validate() error {
+	if errs := validation.IsDNS1123Label(ss.Name); len(errs) > 0 {
+		return fmt.Errorf("invalid name '%s': %s", ss.Name, strings.Join(errs, "; "))
+	}
+	if errs := validation.IsQualifiedName(ss.AnnotationKey()); len(errs) > 0 {
+		return fmt.Errorf("invalid annotation '%s': %s", ss.AnnotationKey(), strings.Join(errs, "; "))
+	}
+	if _, err := ss.RenderPathPrefix(exampleTemplateValues.Project); err != nil {
+		return err
+	}
+	return nil
+}
+
+// AnnotationKey returns the key of the namespace annotation for references to this store.
+func (ss *SecretStoreConfig) AnnotationKey() string {
+	if ss.Annotation != "" {
+		return ss.Annotation
+	}
+	return SecretStoreAnnotationPrefix + ss.Name
+}
+
+// RenderPathPrefix renders the path prefix template for the given project.
+func (ss *SecretStoreConfig) RenderPathPrefix(project string) (string, error) {
+	if ss.PathPrefix == "" {
+		return "", nil
+	}
+	tmpl, err := template.New(ss.Name).Parse(ss.PathPrefix)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse pathPrefix template: %w", err)
+	}
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, NetworkPolicyTemplateValues{Project: project}); err != nil {
+		return "", fmt.Errorf("failed to render pathPrefix template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// AllowsPath returns true if the given project can reference the given path in this store.
+// Paths containing '..' segments are never allowed, since they could escape the prefix.
+func (ss *SecretStoreConfig) AllowsPath(project, storePath string) (bool, error) {
+	if storePath == "" || slices.Contains(strings.Split(storePath, "/"), "..") {
+		return false, nil
+	}
+	prefix, err := ss.RenderPathPrefix(project)
+	if err != nil {
+		return false, err
+	}
+	return strings.HasPrefix(storePath, prefix), nil
+}
+
+// Validate checks that the key is a valid DNS label, that a ServiceProvider is set, and that the annotation is a valid annotation key
+// which is not used by the platform service itself.
+func (ph *ProviderHintConfig) Validate() error {
+	if errs := validation.IsDNS1123Label(ph.Key); len(errs) > 0 {
+		return fmt.Errorf("invalid key '%s': %s", ph.Key, strings.Join(errs, "; "))
+	}
+	if ph.ServiceProvider == "" {
+		return fmt.Errorf("serviceProvider must not be empty")
+	}
+	if errs := validation.IsQualifiedName(ph.AnnotationKey()); len(errs) > 0 {
+		return fmt.Errorf("invalid annotation '%s': %s", ph.AnnotationKey(), strings.Join(errs, "; "))
+	}
+	if ph.AnnotationKey() == HibernatedAnnotation || ph.AnnotationKey() == DeletionRequestedAnnotation {
+		return fmt.Errorf("annotation '%s' is reserved for the platform service", ph.AnnotationKey())
+	}
+	return nil
+}
+
+// AnnotationKey returns the key of the namespace annotation for this hint.
+func (ph *ProviderHintConfig) AnnotationKey() string {
+	if ph.Annotation != "" {
+		return ph.Annotation
+	}
+	return ProviderHintAnnotationPrefix + ph.Key
+}
+
+// AllowsValue returns true if workspaces can set the given value for this hint.
+func (ph *ProviderHintConfig) AllowsValue(value string) bool {
+	return len(ph.AllowedValues) == 0 || slices.Contains(ph.AllowedValues, value)
+}
+
+// podSecurityVersionPattern matches the versions of the Pod Security Standards, which follow the minor versions of Kubernetes.
+var podSecurityVersionPattern = regexp.MustCompile(`^v1\.[0-9]+$`)
+
+// strictness returns the rank of the level, unset levels have the lowest rank.
+func (l PodSecurityLevel) strictness() int {
+	switch l {
+	case PodSecurityLevelPrivileged:
+		return 1
+	case PodSecurityLevelBaseline:
+		return 2
+	case PodSecurityLevelRestricted:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Validate checks that all set levels are known.
+func (l *PodSecurityLevels) Validate() error {
+	for mode, level := range map[string]PodSecurityLevel{"enforce": l.Enforce, "warn": l.Warn, "audit": l.Audit} {
+		if level != "" && level.strictness() == 0 {
+			return fmt.Errorf("unknown %s level '%s'", mode, level)
+		}
+	}
+	return nil
+}
+
+// Stricter returns, for each mode, the stricter of the levels of l and other (may be nil).
+func (l PodSecurityLevels) Stricter(other *PodSecurityLevels) PodSecurityLevels {
+	if other == nil {
+		return l
+	}
+	stricter := func(a, b PodSecurityLevel) PodSecurityLevel {
+		if b.strictness() > a.strictness() {
+			return b
+		}
+		return a
+	}
+	return PodSecurityLevels{
+		Enforce: stricter(l.Enforce, other.Enforce),
+		Warn:    stricter(l.Warn, other.Warn),
+		Audit:   stricter(l.Audit, other.Audit),
+	}
+}
+
+// Validate checks that the levels are known and that the version is 'latest' or a Kubernetes minor version like 'v1.30'.
+func (c *PodSecurityConfig) Validate() error {
+	if err := c.PodSecurityLevels.Validate(); err != nil {
+		return err
+	}
+	if c.Version != "" && c.Version != PodSecurityVersionLatest && !podSecurityVersionPattern.MatchString(c.Version) {
+		return fmt.Errorf("invalid version '%s', must be '%s' or a version like 'v1.30'", c.Version, PodSecurityVersionLatest)
+	}
+	return nil
+}
+
+// NamespaceLabels returns the Pod Security Admission labels for the given levels, using the version of the config.
+// The values of modes without a level are empty, which means that their labels have to be removed.
+func (c *PodSecurityConfig) NamespaceLabels(levels PodSecurityLevels) map[string]string {
+	version := c.Version
+	if version == "" {
+		version = PodSecurityVersionLatest
+	}
+	res := map[string]string{}
+	for mode, level := range map[string]PodSecurityLevel{"enforce": levels.Enforce, "warn": levels.Warn, "audit": levels.Audit} {
+		res[PodSecurityLabelPrefix+mode] = string(level)
+		res[PodSecurityLabelPrefix+mode+"-version"] = ""
+		if level != "" {
+			res[PodSecurityLabelPrefix+mode+"-version"] = version
+		}
+	}
+	return res
+}
+
+// Validate checks that the maximum token expiration is not below the minimum which is accepted by the TokenRequest API.
+func (asa *AutomationServiceAccountConfig) Validate() error {
+	if asa.MaxTokenExpiration != nil && asa.MaxTokenExpiration.Duration < MinAutomationTokenExpiration {
+		return fmt.Errorf("maxTokenExpiration must be at least %s", MinAutomationTokenExpiration)
+	}
+	return nil
+}
+
+// Validate checks that all levels are known.
+func (lc *LoggingConfig) Validate() error {
+	if lc.DefaultLevel != "" && !lc.DefaultLevel.isKnown() {
+		return fmt.Errorf("unknown defaultLevel '%s'", lc.DefaultLevel)
+	}
+	for name, level := range lc.Controllers {
+		if !level.isKnown() {
+			return fmt.Errorf("unknown level '%s' for controller '%s'", level, name)
+		}
+	}
+	return nil
+}
+
+func (l LogLevel) isKnown() bool {
+	return l == LogLevelError || l == LogLevelInfo || l == LogLevelDebug
+}
+
+// Validate checks that the name and labels are valid and that the spec can be rendered into a NetworkPolicySpec.
+func (t *NetworkPolicyTemplate) Validate() error {
+	if errs := validation.IsDNS1123Subdomain(t.Name); len(errs) > 0 {
+		return fmt.Errorf("invalid name '%s': %s", t.Name, strings.Join(errs, "; "))
+	}
+	if err := validateLabels(t.Labels); err != nil {
+		return fmt.Errorf("invalid labels: %w", err)
+	}
+	if _, err := t.Render(exampleTemplateValues); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Render renders the spec template with the given values and decodes the result into a NetworkPolicySpec.
+// Unknown fields are rejected, so that typos in the config do not silently weaken the resulting policy.
+func (t *NetworkPolicyTemplate) Render(values NetworkPolicyTemplateValues) (*networkingv1.NetworkPolicySpec, error) {
+	if len(t.Spec.Raw) == 0 {
+		return nil, fmt.Errorf("spec must be specified")
+	}
+	tmpl, err := template.New(t.Name).Parse(string(t.Spec.Raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse spec template: %w", err)
+	}
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, values); err != nil {
+		return nil, fmt.Errorf("failed to render spec template: %w", err)
+	}
+	spec := &networkingv1.NetworkPolicySpec{}
+	dec := json.NewDecoder(buf)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(spec); err != nil {
+		return nil, fmt.Errorf("failed to decode rendered spec: %w", err)
+	}
+	return spec, nil
+}
+
+// Render renders the manifest template with the given values and decodes the result into an unstructured object in the given namespace.
+// The rendered manifest must specify apiVersion, kind and name, a namespace other than the one of the workspace is rejected.
+func (t *ResourceTemplate) Render(values NetworkPolicyTemplateValues) (*unstructured.Unstructured, error) {
+	if len(t.Manifest.Raw) == 0 {
+		return nil, fmt.Errorf("manifest must be specified")
+	}
+	tmpl, err := template.New("manifest").Parse(string(t.Manifest.Raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse manifest template: %w", err)
+	}
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, values); err != nil {
+		return nil, fmt.Errorf("failed to render manifest template: %w", err)
+	}
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(buf.Bytes()); err != nil {
+		return nil, fmt.Errorf("failed to decode rendered manifest: %w", err)
+	}
+	if obj.GetAPIVersion() == "" {
+		return nil, fmt.Errorf("manifest must specify apiVersion")
+	}
+	if obj.GetName() == "" {
+		return nil, fmt.Errorf("manifest must specify metadata.name")
+	}
+	if ns := obj.GetNamespace(); ns != "" && ns != values.Namespace {
+		return nil, fmt.Errorf("manifest of %s must not specify a namespace other than the workspace namespace", AppliedResourceOf(obj))
+	}
+	obj.SetNamespace(values.Namespace)
+	return obj, nil
+}
+
+// Validate checks that the provider is known, that the base domain is set if the provider requires it, and that the host names, port and IP families are valid.
+func (dc *DNSConfig) Validate() error {
+	switch dc.Provider {
+	case "", DNSProviderGateway:
+	case DNSProviderLoadBalancer:
+		if dc.BaseDomain == "" {
+			return fmt.Errorf("baseDomain must be specified for provider '%s'", dc.Provider)
+		}
+	default:
+		return fmt.Errorf("unknown provider '%s'", dc.Provider)
+	}
+	if dc.BaseDomain != "" {
+		if errs := validation.IsDNS1123Subdomain(dc.BaseDomain); len(errs) > 0 {
+			return fmt.Errorf("invalid baseDomain '%s': %s", dc.BaseDomain, strings.Join(errs, "; "))
+		}
+	}
+	for env, hostName := range dc.HostNames {
+		if errs := validation.IsDNS1123Subdomain(hostName); len(errs) > 0 {
+			return fmt.Errorf("invalid host name '%s' for environment '%s': %s", hostName, env, strings.Join(errs, "; "))
+		}
+	}
+	if dc.Port != 0 {
+		if errs := validation.IsValidPortNum(int(dc.Port)); len(errs) > 0 {
+			return fmt.Errorf("invalid port %d: %s", dc.Port, strings.Join(errs, "; "))
+		}
+	}
+	if len(dc.IPFamilies) > 2 {
+		return fmt.Errorf("at most two ipFamilies can be specified")
+	}
+	for i, family := range dc.IPFamilies {
+		if family != corev1.IPv4Protocol && family != corev1.IPv6Protocol {
+			return fmt.Errorf("unknown IP family '%s', must be '%s' or '%s'", family, corev1.IPv4Protocol, corev1.IPv6Protocol)
+		}
+		if slices.Contains(dc.IPFamilies[:i], family) {
+			return fmt.Errorf("duplicate IP family '%s'", family)
+		}
+	}
+	if dc.Gateway != nil && dc.Gateway.Namespace != "" {
+		if errs := validation.IsDNS1123Label(dc.Gateway.Namespace); len(errs) > 0 {
+			return fmt.Errorf("invalid gateway namespace '%s': %s", dc.Gateway.Namespace, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
+// Validate checks that the pattern is a valid regular expression and that the cache duration is positive.
+func (ct *ChargingTargetConfig) Validate() error {
+	if _, err := ct.compilePattern(); err != nil {
+		return err
+	}
+	if ct.CacheDuration != nil && ct.CacheDuration.Duration <= 0 {
+		return fmt.Errorf("cacheDuration must be positive")
+	}
+	return nil
+}
+
+// Validate checks that the ServiceAccount selector is valid and not empty and that the refresh interval is positive.
+// An empty selector would select all ServiceAccounts and thereby exclude them all from the validation.
+func (oi *OperatorIdentitiesConfig) Validate() error {
+	if len(oi.ServiceAccountSelector.MatchLabels) == 0 && len(oi.ServiceAccountSelector.MatchExpressions) == 0 {
+		return fmt.Errorf("serviceAccountSelector must not be empty")
+	}
+	if _, err := metav1.LabelSelectorAsSelector(&oi.ServiceAccountSelector); err != nil {
+		return fmt.Errorf("invalid serviceAccountSelector: %w", err)
+	}
+	if oi.RefreshInterval != nil && oi.RefreshInterval.Duration <= 0 {
+		return fmt.Errorf("refreshInterval must be positive")
+	}
+	return nil
+}
+
+// GetRefreshInterval returns the configured refresh interval or the default, if not set.
+func (oi *OperatorIdentitiesConfig) GetRefreshInterval() time.Duration {
+	if oi.RefreshInterval == nil {
+		return DefaultOperatorIdentitiesRefreshInterval
+	}
+	return oi.RefreshInterval.Duration
+}
+
+// Validate checks that the retention period is not negative.
+func (mp *MemberOverridePruningConfig) Validate() error {
+	if mp.RetentionPeriod != nil && mp.RetentionPeriod.Duration < 0 {
+		return fmt.Errorf("retentionPeriod must not be negative")
+	}
+	return nil
+}
+
+// GetRetentionPeriod returns the configured retention period or the default, if not set.
+func (mp *MemberOverridePruningConfig) GetRetentionPeriod() time.Duration {
+	if mp.RetentionPeriod == nil {
+		return DefaultMemberOverrideRetentionPeriod
+	}
+	return mp.RetentionPeriod.Duration
+}
+
+// Validate checks that the maximum numbers of members are not negative.
+func (mp *MemberPolicyConfig) Validate() error {
+	if mp.MaxProjectMembers < 0 {
+		return fmt.Errorf("maxProjectMembers must not be negative")
+	}
+	if mp.MaxWorkspaceMembers < 0 {
+		return fmt.Errorf("maxWorkspaceMembers must not be negative")
+	}
+	return nil
+}
+
+// MatchesPattern returns true if the given charging target matches the configured pattern completely, or if no pattern is configured.
+func (ct *ChargingTargetConfig) MatchesPattern(value string) (bool, error) {
+	re, err := ct.compilePattern()
+	if err != nil || re == nil {
+		return err == nil, err
+	}
+	return re.MatchString(value), nil
+}
+
+// compilePattern compiles the configured pattern, anchored at both ends. Returns nil if no pattern is configured.
+func (ct *ChargingTargetConfig) compilePattern() (*regexp.Regexp, error) {
+	if ct.Pattern == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile("^(?:" + ct.Pattern + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern '%s': %w", ct.Pattern, err)
+	}
+	return re, nil
+}
+
+// GetCacheDuration returns the configured cache duration or the default, if not set.
+func (ct *ChargingTargetConfig) GetCacheDuration() time.Duration {
+	if ct.CacheDuration == nil {
+		return DefaultChargingTargetCacheDuration
+	}
+	return ct.CacheDuration.Duration
+}
+
+// Validate checks that the URL is an absolute HTTPS URL, that the signing secret is specified and that the retries and timeout are not negative.
+func (es *EventSinkConfig) Validate() error {
+	u, err := url.Parse(es.URL)
+	if err != nil {
+		return fmt.Errorf("invalid url '%s': %w", es.URL, err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("invalid url '%s': must be an absolute https URL", es.URL)
+	}
+	if es.SigningSecretName == "" {
+		return fmt.Errorf("signingSecretName must be specified")
+	}
+	if es.MaxRetries != nil && *es.MaxRetries < 0 {
+		return fmt.Errorf("maxRetries must not be negative")
+	}
+	if es.Timeout != nil && es.Timeout.Duration <= 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+	return nil
+}
+
+// GetMaxRetries returns the configured number of retries or the default, if not set.
+func (es *EventSinkConfig) GetMaxRetries() int {
+	if es.MaxRetries == nil {
+		return DefaultEventSinkMaxRetries
+	}
+	return int(*es.MaxRetries)
+}
+
+// GetTimeout returns the configured request timeout or the default, if not set.
+func (es *EventSinkConfig) GetTimeout() time.Duration {
+	if es.Timeout == nil {
+		return DefaultEventSinkTimeout
+	}
+	return es.Timeout.Duration
+}
+
+// Validate checks that the timeout is positive, that the retries are not negative and that the failure policies only contain known checks and policies.
+func (wl *WebhookLookupsConfig) Validate() error {
+	if wl.Timeout != nil && wl.Timeout.Duration <= 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+	if wl.Retries != nil && *wl.Retries < 0 {
+		return fmt.Errorf("retries must not be negative")
+	}
+	for check, policy := range wl.FailurePolicies {
+		switch check {
+		case WebhookCheckProjectQuota, WebhookCheckWorkspaceClass, WebhookCheckDeletionBlockers:
+		default:
+			return fmt.Errorf("unknown check '%s' in failurePolicies, must be one of '%s', '%s', '%s'", check, WebhookCheckProjectQuota, WebhookCheckWorkspaceClass, WebhookCheckDeletionBlockers)
+		}
+		if policy != WebhookFailurePolicyFail && policy != WebhookFailurePolicyIgnore {
+			return fmt.Errorf("unknown failure policy '%s' for check '%s', must be one of '%s', '%s'", policy, check, WebhookFailurePolicyFail, WebhookFailurePolicyIgnore)
+		}
+	}
 	return nil
 }
+
+// GetTimeout returns the configured time budget or the default, if not set.
+func (wl *WebhookLookupsConfig) GetTimeout() time.Duration {
+	if wl.Timeout == nil {
+		return DefaultWebhookLookupTimeout
+	}
+	return wl.Timeout.Duration
+}
+
+// GetRetries returns the configured number of retries or the default, if not set.
+func (wl *WebhookLookupsConfig) GetRetries() int {
+	if wl.Retries == nil {
+		return DefaultWebhookLookupRetries
+	}
+	return int(*wl.Retries)
+}
+
+// FailurePolicyFor returns the configured failure policy of the given check or its default, if not set.
+// Checks other than the configurable ones, e.g. the membership lookups, always use 'Fail'.
+func (wl *WebhookLookupsConfig) FailurePolicyFor(check WebhookCheck) WebhookFailurePolicy {
+	switch check {
+	case WebhookCheckProjectQuota, WebhookCheckWorkspaceClass, WebhookCheckDeletionBlockers:
+	default:
+		return WebhookFailurePolicyFail
+	}
+	if policy, ok := wl.FailurePolicies[check]; ok {
+		return policy
+	}
+	if check == WebhookCheckDeletionBlockers {
+		return WebhookFailurePolicyIgnore
+	}
+	return WebhookFailurePolicyFail
+}
+
+// Validate checks that exactly one of name and prefix is set.
+func (im *IdentityMatcher) Validate() error {
+	if (im.Name == "") == (im.Prefix == "") {
+		return fmt.Errorf("exactly one of name and prefix must be specified")
+	}
+	return nil
+}
+
+// Matches returns true if the given username is matched by this IdentityMatcher.
+func (im *IdentityMatcher) Matches(username string) bool {
+	if im.Name != "" {
+		return username == im.Name
+	}
+	return im.Prefix != "" && strings.HasPrefix(username, im.Prefix)
+}
+
+// Validate checks that the rule specifies at least one of label selector and name patterns and that both are syntactically valid.
+func (r *DeletionIgnoreRule) Validate() error {
+	if r.LabelSelector == nil && len(r.NamePatterns) == 0 {
+		return fmt.Errorf("at least one of labelSelector and namePatterns must be specified")
+	}
+	if r.LabelSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(r.LabelSelector); err != nil {
+			return fmt.Errorf("invalid label selector: %w", err)
+		}
+	}
+	for _, pattern := range r.NamePatterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid name pattern '%s': %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Matches returns true if the given object, which is expected to be of the given API group and kind, is matched by this rule.
+func (r *DeletionIgnoreRule) Matches(obj metav1.Object, group, kind string) (bool, error) {
+	if r.Kind != "" && (r.Kind != kind || r.Group != group) {
+		return false, nil
+	}
+	if r.LabelSelector != nil {
+		sel, err := metav1.LabelSelectorAsSelector(r.LabelSelector)
+		if err != nil {
+			return false, fmt.Errorf("invalid label selector: %w", err)
+		}
+		if !sel.Matches(labels.Set(obj.GetLabels())) {
+			return false, nil
+		}
+	}
+	if len(r.NamePatterns) > 0 {
+		for _, pattern := range r.NamePatterns {
+			matched, err := path.Match(pattern, obj.GetName())
+			if err != nil {
+				return false, fmt.Errorf("invalid name pattern '%s': %w", pattern, err)
+			}
+			if matched {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	return true, nil
+}
@@ -0,0 +1,149 @@
+package v1alpha1_test
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+)
+
+func TestValidateGroupVersionKind(t *testing.T) {
+	tests := []struct {
+		gvk         metav1.GroupVersionKind
+		expectedErr string
+	}{
+		{gvk: metav1.GroupVersionKind{Group: "core.openmcp.cloud", Version: "v2alpha1", Kind: "ManagedControlPlaneV2"}},
+		{gvk: metav1.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}},
+		{gvk: metav1.GroupVersionKind{Group: "Core.openmcp.cloud", Version: "v1", Kind: "Foo"}, expectedErr: "invalid group"},
+		{gvk: metav1.GroupVersionKind{Group: "core.openmcp.cloud", Kind: "Foo"}, expectedErr: "version must not be empty"},
+		{gvk: metav1.GroupVersionKind{Group: "core.openmcp.cloud", Version: "v1.0", Kind: "Foo"}, expectedErr: "invalid version"},
+		{gvk: metav1.GroupVersionKind{Group: "core.openmcp.cloud", Version: "v1"}, expectedErr: "kind must not be empty"},
+		{gvk: metav1.GroupVersionKind{Group: "core.openmcp.cloud", Version: "v1", Kind: "managedcontrolplane"}, expectedErr: "invalid kind"},
+		{gvk: metav1.GroupVersionKind{Group: "core.openmcp.cloud", Version: "v1", Kind: "Managed-ControlPlane"}, expectedErr: "invalid kind"},
+	}
+	for _, test := range tests {
+		err := pwv1alpha1.ValidateGroupVersionKind(test.gvk)
+		if test.expectedErr == "" {
+			if err != nil {
+				t.Errorf("expected %v to be valid, got %v", test.gvk, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), test.expectedErr) {
+			t.Errorf("expected error containing %q for %v, got %v", test.expectedErr, test.gvk, err)
+		}
+	}
+}
+
+func TestNormalizeGroupVersionKind(t *testing.T) {
+	actual := pwv1alpha1.NormalizeGroupVersionKind(metav1.GroupVersionKind{Group: " Core.OpenMCP.cloud", Version: "V1alpha1 ", Kind: " managedControlPlane"})
+	expected := metav1.GroupVersionKind{Group: "core.openmcp.cloud", Version: "v1alpha1", Kind: "ManagedControlPlane"}
+	if actual != expected {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+	if err := pwv1alpha1.ValidateGroupVersionKind(actual); err != nil {
+		t.Errorf("expected the normalized GroupVersionKind to be valid, got %v", err)
+	}
+
+	cfg := &pwv1alpha1.ProjectWorkspaceConfig{Spec: pwv1alpha1.ProjectWorkspaceConfigSpec{
+		Project: pwv1alpha1.ProjectConfig{ResourcesBlockingDeletion: []metav1.GroupVersionKind{{Version: "v1", Kind: "configMap"}}},
+	}}
+	cfg.SetDefaults()
+	if kind := cfg.Spec.Project.ResourcesBlockingDeletion[0].Kind; kind != "ConfigMap" {
+		t.Errorf("expected SetDefaults to normalize the kind, got %s", kind)
+	}
+}
+
+func TestPodSecurityConfigValidate(t *testing.T) {
+	tests := []struct {
+		cfg         pwv1alpha1.PodSecurityConfig
+		expectedErr string
+	}{
+		{cfg: pwv1alpha1.PodSecurityConfig{}},
+		{cfg: pwv1alpha1.PodSecurityConfig{PodSecurityLevels: pwv1alpha1.PodSecurityLevels{Enforce: "baseline", Warn: "restricted", Audit: "privileged"}, Version: "v1.30"}},
+		{cfg: pwv1alpha1.PodSecurityConfig{Version: "latest"}},
+		{cfg: pwv1alpha1.PodSecurityConfig{PodSecurityLevels: pwv1alpha1.PodSecurityLevels{Warn: "strict"}}, expectedErr: "unknown warn level"},
+		{cfg: pwv1alpha1.PodSecurityConfig{Version: "1.30"}, expectedErr: "invalid version"},
+	}
+	for _, test := range tests {
+		err := test.cfg.Validate()
+		if test.expectedErr == "" {
+			if err != nil {
+				t.Errorf("expected %v to be valid, got %v", test.cfg, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), test.expectedErr) {
+			t.Errorf("expected error containing %q for %v, got %v", test.expectedErr, test.cfg, err)
+		}
+	}
+}
+
+func TestWebhookLookupsConfigValidate(t *testing.T) {
+	negative := int32(-1)
+	tests := []struct {
+		cfg         pwv1alpha1.WebhookLookupsConfig
+		expectedErr string
+	}{
+		{cfg: pwv1alpha1.WebhookLookupsConfig{}},
+		{cfg: pwv1alpha1.WebhookLookupsConfig{FailurePolicies: map[pwv1alpha1.WebhookCheck]pwv1alpha1.WebhookFailurePolicy{pwv1alpha1.WebhookCheckProjectQuota: pwv1alpha1.WebhookFailurePolicyIgnore}}},
+		{cfg: pwv1alpha1.WebhookLookupsConfig{Timeout: &metav1.Duration{}}, expectedErr: "timeout must be positive"},
+		{cfg: pwv1alpha1.WebhookLookupsConfig{Retries: &negative}, expectedErr: "retries must not be negative"},
+		{cfg: pwv1alpha1.WebhookLookupsConfig{FailurePolicies: map[pwv1alpha1.WebhookCheck]pwv1alpha1.WebhookFailurePolicy{"Membership": pwv1alpha1.WebhookFailurePolicyIgnore}}, expectedErr: "unknown check"},
+		{cfg: pwv1alpha1.WebhookLookupsConfig{FailurePolicies: map[pwv1alpha1.WebhookCheck]pwv1alpha1.WebhookFailurePolicy{pwv1alpha1.WebhookCheckWorkspaceClass: "Skip"}}, expectedErr: "unknown failure policy"},
+	}
+	for _, test := range tests {
+		err := test.cfg.Validate()
+		if test.expectedErr == "" {
+			if err != nil {
+				t.Errorf("expected %v to be valid, got %v", test.cfg, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), test.expectedErr) {
+			t.Errorf("expected error containing %q for %v, got %v", test.expectedErr, test.cfg, err)
+		}
+	}
+}
+
+func TestBlockingResourcePolicies(t *testing.T) {
+	rules := []pwv1alpha1.BlockingResourcePolicyRule{
+		{Group: "core.openmcp.cloud", Kind: "ManagedControlPlaneV2", Policy: pwv1alpha1.BlockingResourcePolicyCascade},
+		{Kind: "ConfigMap", Policy: pwv1alpha1.BlockingResourcePolicyWarn},
+	}
+	for _, test := range []struct {
+		group, kind string
+		expected    pwv1alpha1.BlockingResourcePolicy
+	}{
+		{group: "core.openmcp.cloud", kind: "managedcontrolplanev2", expected: pwv1alpha1.BlockingResourcePolicyCascade},
+		{kind: "ConfigMap", expected: pwv1alpha1.BlockingResourcePolicyWarn},
+		{group: "example.com", kind: "ConfigMap", expected: pwv1alpha1.BlockingResourcePolicyBlock},
+	} {
+		if actual := pwv1alpha1.BlockingResourcePolicyFor(rules, test.group, test.kind); actual != test.expected {
+			t.Errorf("expected policy %s for %s/%s, got %s", test.expected, test.group, test.kind, actual)
+		}
+	}
+
+	cfg := &pwv1alpha1.ProjectWorkspaceConfig{Spec: pwv1alpha1.ProjectWorkspaceConfigSpec{
+		Workspace: pwv1alpha1.WorkspaceConfig{BlockingResourcePolicies: rules},
+	}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected the policies to be valid, got %v", err)
+	}
+	for _, test := range []struct {
+		rule        pwv1alpha1.BlockingResourcePolicyRule
+		expectedErr string
+	}{
+		{rule: pwv1alpha1.BlockingResourcePolicyRule{Kind: "Secret", Policy: "Orphan"}, expectedErr: "unknown policy 'Orphan'"},
+		{rule: pwv1alpha1.BlockingResourcePolicyRule{Policy: pwv1alpha1.BlockingResourcePolicyWarn}, expectedErr: "kind must not be empty"},
+		{rule: pwv1alpha1.BlockingResourcePolicyRule{Kind: "configmap", Policy: pwv1alpha1.BlockingResourcePolicyBlock}, expectedErr: "duplicate kind 'configmap'"},
+	} {
+		invalid := cfg.DeepCopy()
+		invalid.Spec.Workspace.BlockingResourcePolicies = append(invalid.Spec.Workspace.BlockingResourcePolicies, test.rule)
+		if err := invalid.Validate(); err == nil || !strings.Contains(err.Error(), test.expectedErr) {
+			t.Errorf("expected error containing %q for %v, got %v", test.expectedErr, test.rule, err)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ProjectWorkspaceConfigOverrideSpec defines the values which are merged over the ProjectWorkspaceConfig.
+// Each list, map, or optional value which is set replaces the corresponding value of the ProjectWorkspaceConfig, an explicitly empty list removes it.
+// Values which are not set are taken from the ProjectWorkspaceConfig.
+type ProjectWorkspaceConfigOverrideSpec struct {
+	// +optional
+	Project ProjectConfig `json:"project"`
+	// +optional
+	Workspace WorkspaceConfig `json:"workspace"`
+	// MemberOverrides replaces the member overrides of the ProjectWorkspaceConfig.
+	// +optional
+	MemberOverrides MemberOverrides `json:"memberOverrides,omitempty"`
+}
+
+// ProjectWorkspaceConfigOverride is an environment-specific overlay for the ProjectWorkspaceConfig.
+// It is only used by the platform service instance whose environment matches the name of the override,
+// and it must be located in the namespace the platform service is running in.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=pwcfgo
+// +kubebuilder:metadata:labels="openmcp.cloud/cluster=platform"
+type ProjectWorkspaceConfigOverride struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec ProjectWorkspaceConfigOverrideSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// ProjectWorkspaceConfigOverrideList contains a list of ProjectWorkspaceConfigOverride
+type ProjectWorkspaceConfigOverrideList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []ProjectWorkspaceConfigOverride `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ProjectWorkspaceConfigOverride{}, &ProjectWorkspaceConfigOverrideList{})
+}
@@ -0,0 +1,152 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ConditionTypeControllersHealthy indicates whether the controllers of the platform service reconcile without (too many) errors.
+	ConditionTypeControllersHealthy ConditionType = "ControllersHealthy"
+	// ConditionTypeWebhookCertificateValid indicates whether the certificate used by the webhooks is valid and not about to expire.
+	ConditionTypeWebhookCertificateValid ConditionType = "WebhookCertificateValid"
+	// ConditionTypeAccessRequestsGranted indicates whether all AccessRequests of the platform service have been granted.
+	ConditionTypeAccessRequestsGranted ConditionType = "AccessRequestsGranted"
+
+	// ConditionReasonHealthy is a condition reason that indicates that the checked aspect is healthy.
+	ConditionReasonHealthy ConditionReason = "Healthy"
+	// ConditionReasonReconcileErrors is a condition reason that indicates that the error rate of at least one controller is too high.
+	ConditionReasonReconcileErrors ConditionReason = "ReconcileErrors"
+	// ConditionReasonCertificateExpiring is a condition reason that indicates that the webhook certificate expires soon.
+	ConditionReasonCertificateExpiring ConditionReason = "CertificateExpiring"
+	// ConditionReasonCertificateExpired is a condition reason that indicates that the webhook certificate has expired.
+	ConditionReasonCertificateExpired ConditionReason = "CertificateExpired"
+	// ConditionReasonCertificateUnavailable is a condition reason that indicates that the webhook certificate could not be read.
+	ConditionReasonCertificateUnavailable ConditionReason = "CertificateUnavailable"
+	// ConditionReasonAccessRequestsNotGranted is a condition reason that indicates that at least one AccessRequest is pending or denied.
+	ConditionReasonAccessRequestsNotGranted ConditionReason = "AccessRequestsNotGranted"
+	// ConditionReasonAccessRequestsUnavailable is a condition reason that indicates that the AccessRequests could not be listed.
+	ConditionReasonAccessRequestsUnavailable ConditionReason = "AccessRequestsUnavailable"
+)
+
+// PWOHealthStatus defines the observed state of PWOHealth
+type PWOHealthStatus struct {
+	// LastUpdateTime is the time when the status was last computed.
+	// +optional
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+	// Controllers contains the reconcile statistics of the controllers of the platform service.
+	// +optional
+	Controllers []ControllerHealth `json:"controllers,omitempty"`
+	// WebhookCertificate contains information about the certificate used by the webhooks.
+	// It is not set if the webhooks are disabled.
+	// +optional
+	WebhookCertificate *CertificateHealth `json:"webhookCertificate,omitempty"`
+	// AccessRequests contains the state of the AccessRequests the platform service uses to access the onboarding cluster.
+	// +optional
+	AccessRequests []AccessRequestHealth `json:"accessRequests,omitempty"`
+	// +optional
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// ControllerHealth contains the reconcile statistics of a single controller.
+type ControllerHealth struct {
+	// Name is the name of the controller.
+	Name string `json:"name"`
+	// LastSuccessfulReconcileTime is the time of the last reconcile which did not return an error.
+	// +optional
+	LastSuccessfulReconcileTime *metav1.Time `json:"lastSuccessfulReconcileTime,omitempty"`
+	// Reconciles is the number of reconciles since the platform service has been started.
+	Reconciles int64 `json:"reconciles"`
+	// Errors is the number of reconciles which returned an error since the platform service has been started.
+	Errors int64 `json:"errors"`
+	// RecentReconciles is the number of reconciles since the previous status update.
+	RecentReconciles int64 `json:"recentReconciles"`
+	// RecentErrors is the number of reconciles which returned an error since the previous status update.
+	RecentErrors int64 `json:"recentErrors"`
+}
+
+// CertificateHealth contains information about a certificate.
+type CertificateHealth struct {
+	// Source describes where the certificate has been read from.
+	Source string `json:"source"`
+	// NotAfter is the time when the certificate expires.
+	// +optional
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+	// Error contains the reason why the certificate could not be read.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// AccessRequestHealth contains the state of an AccessRequest.
+type AccessRequestHealth struct {
+	// Name is the name of the AccessRequest.
+	Name string `json:"name"`
+	// Namespace is the namespace of the AccessRequest.
+	Namespace string `json:"namespace"`
+	// Phase is the phase of the AccessRequest.
+	// +optional
+	Phase string `json:"phase,omitempty"`
+}
+
+// PWOHealth summarizes the health of the platform service, so that it can be monitored by observing a single object.
+// It is maintained by the platform service and has the same name as the ProjectWorkspaceConfig.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName=pwohealth
+// +kubebuilder:printcolumn:name="Controllers",type="string",JSONPath=".status.conditions[?(@.type==\"ControllersHealthy\")].status"
+// +kubebuilder:printcolumn:name="Webhook Certificate",type="string",JSONPath=".status.conditions[?(@.type==\"WebhookCertificateValid\")].status"
+// +kubebuilder:printcolumn:name="Access Requests",type="string",JSONPath=".status.conditions[?(@.type==\"AccessRequestsGranted\")].status"
+// +kubebuilder:printcolumn:name="Last Update",type="date",JSONPath=".status.lastUpdateTime"
+// +kubebuilder:metadata:labels="openmcp.cloud/cluster=platform"
+type PWOHealth struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status PWOHealthStatus `json:"status,omitempty"`
+}
+
+// SetOrUpdateCondition sets or updates the condition with the given type.
+func (h *PWOHealth) SetOrUpdateCondition(condition Condition) {
+	var existingCondition *Condition
+	for i, c := range h.Status.Conditions {
+		if c.Type == condition.Type {
+			existingCondition = &h.Status.Conditions[i]
+			break
+		}
+	}
+
+	if existingCondition == nil {
+		condition.LastTransitionTime = metav1.Now()
+		h.Status.Conditions = append(h.Status.Conditions, condition)
+	} else {
+		if existingCondition.Status != condition.Status {
+			condition.LastTransitionTime = metav1.Now()
+		} else {
+			condition.LastTransitionTime = existingCondition.LastTransitionTime
+		}
+		*existingCondition = condition
+	}
+}
+
+// RemoveCondition removes the condition with the given type.
+func (h *PWOHealth) RemoveCondition(conditionType ConditionType) {
+	var conditions []Condition
+	for _, c := range h.Status.Conditions {
+		if c.Type != conditionType {
+			conditions = append(conditions, c)
+		}
+	}
+	h.Status.Conditions = conditions
+}
+
+// +kubebuilder:object:root=true
+
+// PWOHealthList contains a list of PWOHealth
+type PWOHealthList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PWOHealth `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PWOHealth{}, &PWOHealthList{})
+}
@@ -2,10 +2,12 @@ package v1alpha1
 
 import (
 	"fmt"
+	"slices"
 
 	authv1 "k8s.io/api/authentication/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/openmcp-project/platform-service-project-workspace/api/v2/entities"
@@ -15,9 +17,10 @@ var (
 	_ entities.AccessEntity = &Workspace{}
 	_ entities.AccessRole   = WorkspaceRoleAdmin
 	_ entities.AccessRole   = WorkspaceRoleView
+	_ entities.AccessRole   = WorkspaceRoleAuditor
 )
 
-// +kubebuilder:validation:Enum=admin;view
+// +kubebuilder:validation:Enum=admin;view;auditor
 type WorkspaceMemberRole string
 
 // EntityType implements AccessRole.
@@ -33,12 +36,69 @@ func (w WorkspaceMemberRole) Identifier() string {
 const (
 	WorkspaceRoleAdmin WorkspaceMemberRole = "admin"
 	WorkspaceRoleView  WorkspaceMemberRole = "view"
+	// WorkspaceRoleAuditor grants read-only access, like WorkspaceRoleView, but excludes sensitive resources like secrets.
+	WorkspaceRoleAuditor WorkspaceMemberRole = "auditor"
 )
 
+// ProviderHintAnnotationPrefix is the prefix of the namespace annotations for the provider hints of workspaces, unless the ProjectWorkspaceConfig specifies another annotation for the hint.
+// The key of the hint is appended to it.
+var ProviderHintAnnotationPrefix = fmt.Sprintf("providerhint.%s/", GroupVersion.Group)
+
+// WorkspaceMemberRoles returns all roles which can be assigned to workspace members.
+func WorkspaceMemberRoles() []WorkspaceMemberRole {
+	return []WorkspaceMemberRole{WorkspaceRoleAdmin, WorkspaceRoleView, WorkspaceRoleAuditor}
+}
+
 // WorkspaceSpec defines the desired state of Workspace
 type WorkspaceSpec struct {
 	// Members is a list of workspace members.
 	Members []WorkspaceMember `json:"members,omitempty"`
+
+	// Hibernated can be set to put the workspace into hibernation.
+	// A hibernated workspace's namespace gets a zero ResourceQuota and all members are reduced to the 'view' role.
+	// The namespace is additionally annotated so that ServiceProviders can scale down their resources.
+	// Setting it back to false restores the previous state.
+	// +optional
+	Hibernated bool `json:"hibernated,omitempty"`
+
+	// InheritProjectMembers can be used to make the members of the owning project members of the workspace.
+	// The inherited members are merged with the explicitly listed members when the workspace's RBAC is reconciled, they are not added to the member list.
+	// +optional
+	InheritProjectMembers *InheritProjectMembers `json:"inheritProjectMembers,omitempty"`
+
+	// ClassName is the name of the WorkspaceClass of this workspace.
+	// The class defines additional namespace labels, a ResourceQuota, additional permissions, and resources blocking the deletion.
+	// If empty, no class-specific defaults are applied.
+	// +optional
+	ClassName string `json:"className,omitempty"`
+
+	// ProviderHints passes placement hints, e.g. a preferred region or tier, to the ServiceProviders which manage resources in the workspace.
+	// Each hint is added as annotation to the workspace namespace. Only the keys which are configured in the ProjectWorkspaceConfig
+	// for a registered ServiceProvider can be set. Flat workspaces do not have a namespace of their own, so their hints are not applied.
+	// +optional
+	ProviderHints map[string]string `json:"providerHints,omitempty"`
+}
+
+// InheritProjectMembers configures which members of the owning project are inherited by a workspace and which roles they get.
+type InheritProjectMembers struct {
+	// Enabled specifies whether the workspace inherits the members of its project.
+	Enabled bool `json:"enabled"`
+
+	// RoleMapping maps project roles to the workspace roles which project members with the respective role get.
+	// Project roles that are not part of the mapping are not inherited.
+	// If empty, each project role is mapped to the workspace role with the same name.
+	// +kubebuilder:validation:XValidation:rule="self.all(k, k in ['admin', 'view', 'auditor'])",message="Keys must be valid project roles"
+	// +optional
+	RoleMapping map[ProjectMemberRole]WorkspaceMemberRole `json:"roleMapping,omitempty"`
+}
+
+// DefaultInheritedRoleMapping returns the role mapping which is used for inherited project members if no mapping is specified.
+func DefaultInheritedRoleMapping() map[ProjectMemberRole]WorkspaceMemberRole {
+	return map[ProjectMemberRole]WorkspaceMemberRole{
+		ProjectRoleAdmin:   WorkspaceRoleAdmin,
+		ProjectRoleView:    WorkspaceRoleView,
+		ProjectRoleAuditor: WorkspaceRoleAuditor,
+	}
 }
 
 type WorkspaceMember struct {
@@ -61,17 +121,88 @@ func (wm *WorkspaceMember) Username() (string, bool) {
 
 // WorkspaceStatus defines the observed state of Workspace
 type WorkspaceStatus struct {
+	// Namespace is the namespace of the workspace.
+	// For flat workspaces, this is the namespace of the project.
 	Namespace string `json:"namespace"`
 	// +optional
 	Conditions []Condition `json:"conditions,omitempty"`
+	// MemberStatuses contains the RBAC status of each effective member of the workspace, including the members inherited from the project.
+	// +optional
+	MemberStatuses []WorkspaceMemberStatus `json:"memberStatuses,omitempty"`
+	// AdditionalResources lists the additional resources from the config which have been applied to the workspace namespace.
+	// It is used to delete resources which are removed from the config.
+	// +optional
+	AdditionalResources []AppliedResource `json:"additionalResources,omitempty"`
+	// BlockingResourceCount is the number of resources in the workspace namespace which block the deletion of the workspace.
+	// It is only set while the workspace is in deletion, the resources are listed in the details of the ContentRemaining condition.
+	// +optional
+	BlockingResourceCount int32 `json:"blockingResourceCount,omitempty"`
+}
+
+// AppliedResource identifies a resource in the workspace namespace which has been created from the config.
+type AppliedResource struct {
+	// APIVersion is the API version of the resource.
+	APIVersion string `json:"apiVersion"`
+	// Kind is the kind of the resource.
+	Kind string `json:"kind"`
+	// Name is the name of the resource.
+	Name string `json:"name"`
+}
+
+// AppliedResourceOf returns the AppliedResource identifying the given object.
+func AppliedResourceOf(obj *unstructured.Unstructured) AppliedResource {
+	return AppliedResource{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Name:       obj.GetName(),
+	}
+}
+
+func (ar AppliedResource) String() string {
+	return fmt.Sprintf("%s '%s' (%s)", ar.Kind, ar.Name, ar.APIVersion)
+}
+
+// +kubebuilder:validation:Enum=Pending;Active;Failed
+type MemberPhase string
+
+const (
+	// MemberPhasePending indicates that the RBAC for the member has not been applied yet.
+	MemberPhasePending MemberPhase = "Pending"
+	// MemberPhaseActive indicates that the RBAC for the member has been applied.
+	MemberPhaseActive MemberPhase = "Active"
+	// MemberPhaseFailed indicates that the RBAC for the member cannot become effective, e.g. because the namespace of a ServiceAccount does not exist.
+	MemberPhaseFailed MemberPhase = "Failed"
+)
+
+// WorkspaceMemberStatus describes whether the RBAC for a workspace member has been applied.
+type WorkspaceMemberStatus struct {
+	Subject `json:""`
+
+	// Phase is the phase of the member's RBAC.
+	Phase MemberPhase `json:"phase"`
+
+	// Roles are the roles which the member effectively has, e.g. reduced to 'view' for hibernated workspaces.
+	// +optional
+	Roles []WorkspaceMemberRole `json:"roles,omitempty"`
+
+	// LastAppliedTime is the time when the RBAC for the member's current roles was applied.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+
+	// Message is a human-readable message with details about the phase, e.g. the reason for a failure.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // Workspace is the Schema for the workspaces API
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:shortName=ws
-// +kubebuilder:printcolumn:name="Display Name",type="string",JSONPath=".metadata.annotations.openmcp\\.cloud/display-name"
+// +kubebuilder:printcolumn:name="Display Name",type="string",JSONPath=".metadata.annotations.core\\.openmcp\\.cloud/display-name"
 // +kubebuilder:printcolumn:name="Resulting Namespace",type="string",JSONPath=".status.namespace"
+// +kubebuilder:printcolumn:name="Hibernated",type="boolean",JSONPath=".spec.hibernated"
+// +kubebuilder:printcolumn:name="Class",type="string",JSONPath=".spec.className"
+// +kubebuilder:printcolumn:name="Blocking",type="integer",JSONPath=".status.blockingResourceCount"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:validation:XValidation:rule="size(self.metadata.name) <= 25",message="Name must not be longer than 25 characters"
 // +kubebuilder:metadata:labels="openmcp.cloud/cluster=onboarding"
@@ -88,6 +219,72 @@ func (ws *Workspace) TypeIdentifier() string {
 	return "workspace"
 }
 
+// IsFlat returns true if the workspace is a pure RBAC grouping within the namespace of its project, instead of having a dedicated namespace.
+// This is decided when the workspace is reconciled for the first time and reflected by its status, so that it does not change afterwards.
+func (ws *Workspace) IsFlat() bool {
+	return ws.Status.Namespace != "" && ws.Status.Namespace == ws.Namespace
+}
+
+// InheritsProjectMembers returns true if the workspace inherits the members of its project.
+func (ws *Workspace) InheritsProjectMembers() bool {
+	return ws.Spec.InheritProjectMembers != nil && ws.Spec.InheritProjectMembers.Enabled
+}
+
+// InheritedRoles returns the workspace roles which a project member with the given project roles inherits.
+// The result is empty if the workspace does not inherit the members of its project.
+func (ws *Workspace) InheritedRoles(projectRoles []ProjectMemberRole) []WorkspaceMemberRole {
+	if !ws.InheritsProjectMembers() {
+		return nil
+	}
+	mapping := ws.Spec.InheritProjectMembers.RoleMapping
+	if len(mapping) == 0 {
+		mapping = DefaultInheritedRoleMapping()
+	}
+	res := []WorkspaceMemberRole{}
+	for _, projectRole := range projectRoles {
+		if role, ok := mapping[projectRole]; ok && !slices.Contains(res, role) {
+			res = append(res, role)
+		}
+	}
+	return res
+}
+
+// EffectiveMembers returns the explicitly listed members of the workspace, merged with the members inherited from the given project.
+// Members which are listed explicitly and inherited get the union of their roles.
+// The project may be nil, in which case only the explicitly listed members are returned.
+func (ws *Workspace) EffectiveMembers(project *Project) []WorkspaceMember {
+	members := make([]WorkspaceMember, 0, len(ws.Spec.Members))
+	for _, member := range ws.Spec.Members {
+		members = append(members, *member.DeepCopy())
+	}
+	if project == nil || !ws.InheritsProjectMembers() {
+		return members
+	}
+
+	for _, projectMember := range project.Spec.Members {
+		roles := ws.InheritedRoles(projectMember.Roles)
+		if len(roles) == 0 {
+			continue
+		}
+		idx := slices.IndexFunc(members, func(m WorkspaceMember) bool {
+			return m.Subject == projectMember.Subject
+		})
+		if idx < 0 {
+			members = append(members, WorkspaceMember{
+				Subject: projectMember.Subject,
+				Roles:   roles,
+			})
+			continue
+		}
+		for _, role := range roles {
+			if !slices.Contains(members[idx].Roles, role) {
+				members[idx].Roles = append(members[idx].Roles, role)
+			}
+		}
+	}
+	return members
+}
+
 func (ws *Workspace) UserInfoRoles(userInfo authv1.UserInfo) []WorkspaceMemberRole {
 	effectiveRoles := sets.Set[WorkspaceMemberRole]{}
 
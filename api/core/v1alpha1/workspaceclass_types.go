@@ -0,0 +1,75 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SourceWorkspaceClassPrefix is the prefix of the source of deletion-blocking resource types which come from a WorkspaceClass.
+const SourceWorkspaceClassPrefix = "WorkspaceClass"
+
+// WorkspaceClassSpec defines the defaults which are applied to all workspaces of the class.
+type WorkspaceClassSpec struct {
+	// NamespaceLabels are set on the namespace of each workspace of this class.
+	// Labels which are removed from the class are not removed from existing namespaces.
+	// +optional
+	NamespaceLabels map[string]string `json:"namespaceLabels,omitempty"`
+
+	// ResourceQuota defines the hard limits of a ResourceQuota which is created in the namespace of each workspace of this class.
+	// +optional
+	ResourceQuota corev1.ResourceList `json:"resourceQuota,omitempty"`
+
+	// AdditionalPermissions defines additional permissions members of the workspaces of this class should have, depending on their role.
+	// They are granted in addition to the permissions from the ProjectWorkspaceConfig, via a Role in the workspace namespace.
+	// +kubebuilder:validation:XValidation:rule="self.all(k, k in ['admin', 'view', 'auditor'])",message="Keys must be valid workspace roles"
+	// +optional
+	AdditionalPermissions map[WorkspaceMemberRole][]rbacv1.PolicyRule `json:"additionalPermissions,omitempty"`
+
+	// ResourcesBlockingDeletion defines resource types which block the deletion of the workspaces of this class,
+	// in addition to the ones from the ProjectWorkspaceConfig.
+	// +optional
+	ResourcesBlockingDeletion []metav1.GroupVersionKind `json:"resourcesBlockingDeletion,omitempty"`
+
+	// Flat specifies whether new workspaces of this class are pure RBAC groupings within the namespace of their project, instead of getting a dedicated namespace.
+	// If not set, the setting of the ProjectWorkspaceConfig applies. Existing workspaces are not affected by changes.
+	// +optional
+	Flat *bool `json:"flat,omitempty"`
+
+	// PodSecurity defines Pod Security Admission levels for the namespaces of the workspaces of this class.
+	// Only takes effect if pod security is configured in the ProjectWorkspaceConfig, and only levels which are stricter than the configured ones are applied.
+	// +optional
+	PodSecurity *PodSecurityLevels `json:"podSecurity,omitempty"`
+}
+
+// WorkspaceClass defines a flavor of workspaces, e.g. 'small' or 'secure', similar to a StorageClass.
+// Workspaces select a class via spec.className.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName=wscls
+// +kubebuilder:metadata:labels="openmcp.cloud/cluster=onboarding"
+type WorkspaceClass struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec WorkspaceClassSpec `json:"spec,omitempty"`
+}
+
+// DeletionBlockingSource returns the source of the deletion-blocking resource types which come from this class, e.g. 'WorkspaceClass[secure]'.
+func (c *WorkspaceClass) DeletionBlockingSource() string {
+	return fmt.Sprintf("%s[%s]", SourceWorkspaceClassPrefix, c.Name)
+}
+
+// +kubebuilder:object:root=true
+
+// WorkspaceClassList contains a list of WorkspaceClass
+type WorkspaceClassList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []WorkspaceClass `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&WorkspaceClass{}, &WorkspaceClassList{})
+}
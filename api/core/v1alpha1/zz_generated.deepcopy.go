@@ -7,411 +7,2308 @@ package v1alpha1
 import (
 	"encoding/json"
 
+	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Condition) DeepCopyInto(out *Condition) {
+func (in *AccessRequestHealth) DeepCopyInto(out *AccessRequestHealth) {
 	*out = *in
-	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
-	if in.Details != nil {
-		in, out := &in.Details, &out.Details
-		*out = make(json.RawMessage, len(*in))
-		copy(*out, *in)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessRequestHealth.
+func (in *AccessRequestHealth) DeepCopy() *AccessRequestHealth {
+	if in == nil {
+		return nil
 	}
+	out := new(AccessRequestHealth)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
-func (in *Condition) DeepCopy() *Condition {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccessReview) DeepCopyInto(out *AccessReview) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessReview.
+func (in *AccessReview) DeepCopy() *AccessReview {
 	if in == nil {
 		return nil
 	}
-	out := new(Condition)
+	out := new(AccessReview)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AccessReview) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MemberOverride) DeepCopyInto(out *MemberOverride) {
+func (in *AccessReviewList) DeepCopyInto(out *AccessReviewList) {
 	*out = *in
-	out.Subject = in.Subject
-	if in.Roles != nil {
-		in, out := &in.Roles, &out.Roles
-		*out = make([]OverrideRole, len(*in))
-		copy(*out, *in)
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AccessReview, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = make([]OverrideResource, len(*in))
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessReviewList.
+func (in *AccessReviewList) DeepCopy() *AccessReviewList {
+	if in == nil {
+		return nil
+	}
+	out := new(AccessReviewList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AccessReviewList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccessReviewSpec) DeepCopyInto(out *AccessReviewSpec) {
+	*out = *in
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemberOverride.
-func (in *MemberOverride) DeepCopy() *MemberOverride {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessReviewSpec.
+func (in *AccessReviewSpec) DeepCopy() *AccessReviewSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MemberOverride)
+	out := new(AccessReviewSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in MemberOverrides) DeepCopyInto(out *MemberOverrides) {
-	{
-		in := &in
-		*out = make(MemberOverrides, len(*in))
+func (in *AccessReviewStatus) DeepCopyInto(out *AccessReviewStatus) {
+	*out = *in
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ResolvedGroups != nil {
+		in, out := &in.ResolvedGroups, &out.ResolvedGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Warnings != nil {
+		in, out := &in.Warnings, &out.Warnings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Projects != nil {
+		in, out := &in.Projects, &out.Projects
+		*out = make([]ProjectAccess, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Workspaces != nil {
+		in, out := &in.Workspaces, &out.Workspaces
+		*out = make([]WorkspaceAccess, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemberOverrides.
-func (in MemberOverrides) DeepCopy() MemberOverrides {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessReviewStatus.
+func (in *AccessReviewStatus) DeepCopy() *AccessReviewStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(MemberOverrides)
+	out := new(AccessReviewStatus)
 	in.DeepCopyInto(out)
-	return *out
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OverrideResource) DeepCopyInto(out *OverrideResource) {
+func (in *AppliedResource) DeepCopyInto(out *AppliedResource) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OverrideResource.
-func (in *OverrideResource) DeepCopy() *OverrideResource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppliedResource.
+func (in *AppliedResource) DeepCopy() *AppliedResource {
 	if in == nil {
 		return nil
 	}
-	out := new(OverrideResource)
+	out := new(AppliedResource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Project) DeepCopyInto(out *Project) {
+func (in *AutomationServiceAccountConfig) DeepCopyInto(out *AutomationServiceAccountConfig) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	if in.MaxTokenExpiration != nil {
+		in, out := &in.MaxTokenExpiration, &out.MaxTokenExpiration
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Project.
-func (in *Project) DeepCopy() *Project {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutomationServiceAccountConfig.
+func (in *AutomationServiceAccountConfig) DeepCopy() *AutomationServiceAccountConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(Project)
+	out := new(AutomationServiceAccountConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Project) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateHealth) DeepCopyInto(out *CertificateHealth) {
+	*out = *in
+	if in.NotAfter != nil {
+		in, out := &in.NotAfter, &out.NotAfter
+		*out = (*in).DeepCopy()
 	}
-	return nil
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateHealth.
+func (in *CertificateHealth) DeepCopy() *CertificateHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateHealth)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProjectConfig) DeepCopyInto(out *ProjectConfig) {
+func (in *BlockingResourcePolicyRule) DeepCopyInto(out *BlockingResourcePolicyRule) {
 	*out = *in
-	if in.ResourcesBlockingDeletion != nil {
-		in, out := &in.ResourcesBlockingDeletion, &out.ResourcesBlockingDeletion
-		*out = make([]v1.GroupVersionKind, len(*in))
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlockingResourcePolicyRule.
+func (in *BlockingResourcePolicyRule) DeepCopy() *BlockingResourcePolicyRule {
+	if in == nil {
+		return nil
+	}
+	out := new(BlockingResourcePolicyRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChargingTargetConfig) DeepCopyInto(out *ChargingTargetConfig) {
+	*out = *in
+	if in.CacheDuration != nil {
+		in, out := &in.CacheDuration, &out.CacheDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChargingTargetConfig.
+func (in *ChargingTargetConfig) DeepCopy() *ChargingTargetConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ChargingTargetConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Condition) DeepCopyInto(out *Condition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	if in.Details != nil {
+		in, out := &in.Details, &out.Details
+		*out = make(json.RawMessage, len(*in))
 		copy(*out, *in)
 	}
-	if in.AdditionalPermissions != nil {
-		in, out := &in.AdditionalPermissions, &out.AdditionalPermissions
-		*out = make(map[ProjectMemberRole][]rbacv1.PolicyRule, len(*in))
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
+func (in *Condition) DeepCopy() *Condition {
+	if in == nil {
+		return nil
+	}
+	out := new(Condition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControllerHealth) DeepCopyInto(out *ControllerHealth) {
+	*out = *in
+	if in.LastSuccessfulReconcileTime != nil {
+		in, out := &in.LastSuccessfulReconcileTime, &out.LastSuccessfulReconcileTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerHealth.
+func (in *ControllerHealth) DeepCopy() *ControllerHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(ControllerHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSConfig) DeepCopyInto(out *DNSConfig) {
+	*out = *in
+	if in.ServiceAnnotations != nil {
+		in, out := &in.ServiceAnnotations, &out.ServiceAnnotations
+		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
-			var outVal []rbacv1.PolicyRule
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				inVal := (*in)[key]
-				in, out := &inVal, &outVal
-				*out = make([]rbacv1.PolicyRule, len(*in))
-				for i := range *in {
-					(*in)[i].DeepCopyInto(&(*out)[i])
-				}
-			}
-			(*out)[key] = outVal
+			(*out)[key] = val
+		}
+	}
+	if in.HostNames != nil {
+		in, out := &in.HostNames, &out.HostNames
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
+	if in.IPFamilies != nil {
+		in, out := &in.IPFamilies, &out.IPFamilies
+		*out = make([]corev1.IPFamily, len(*in))
+		copy(*out, *in)
+	}
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(GatewayDNSConfig)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectConfig.
-func (in *ProjectConfig) DeepCopy() *ProjectConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSConfig.
+func (in *DNSConfig) DeepCopy() *DNSConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(ProjectConfig)
+	out := new(DNSConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProjectList) DeepCopyInto(out *ProjectList) {
+func (in *DeletionIgnoreRule) DeepCopyInto(out *DeletionIgnoreRule) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Project, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NamePatterns != nil {
+		in, out := &in.NamePatterns, &out.NamePatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectList.
-func (in *ProjectList) DeepCopy() *ProjectList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeletionIgnoreRule.
+func (in *DeletionIgnoreRule) DeepCopy() *DeletionIgnoreRule {
 	if in == nil {
 		return nil
 	}
-	out := new(ProjectList)
+	out := new(DeletionIgnoreRule)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ProjectList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiscoveredResource) DeepCopyInto(out *DiscoveredResource) {
+	*out = *in
+	out.GroupVersionKind = in.GroupVersionKind
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiscoveredResource.
+func (in *DiscoveredResource) DeepCopy() *DiscoveredResource {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(DiscoveredResource)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProjectMember) DeepCopyInto(out *ProjectMember) {
+func (in *EventSinkConfig) DeepCopyInto(out *EventSinkConfig) {
 	*out = *in
-	out.Subject = in.Subject
-	if in.Roles != nil {
-		in, out := &in.Roles, &out.Roles
-		*out = make([]ProjectMemberRole, len(*in))
-		copy(*out, *in)
+	if in.MaxRetries != nil {
+		in, out := &in.MaxRetries, &out.MaxRetries
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectMember.
-func (in *ProjectMember) DeepCopy() *ProjectMember {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventSinkConfig.
+func (in *EventSinkConfig) DeepCopy() *EventSinkConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(ProjectMember)
+	out := new(EventSinkConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayDNSConfig) DeepCopyInto(out *GatewayDNSConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayDNSConfig.
+func (in *GatewayDNSConfig) DeepCopy() *GatewayDNSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayDNSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupSnapshot) DeepCopyInto(out *GroupSnapshot) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupSnapshot.
+func (in *GroupSnapshot) DeepCopy() *GroupSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GroupSnapshot) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupSnapshotList) DeepCopyInto(out *GroupSnapshotList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GroupSnapshot, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupSnapshotList.
+func (in *GroupSnapshotList) DeepCopy() *GroupSnapshotList {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupSnapshotList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GroupSnapshotList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupSnapshotSpec) DeepCopyInto(out *GroupSnapshotSpec) {
+	*out = *in
+	in.SnapshotTime.DeepCopyInto(&out.SnapshotTime)
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]GroupUsers, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupSnapshotSpec.
+func (in *GroupSnapshotSpec) DeepCopy() *GroupSnapshotSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupSnapshotSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupUsers) DeepCopyInto(out *GroupUsers) {
+	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupUsers.
+func (in *GroupUsers) DeepCopy() *GroupUsers {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupUsers)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IdentityMatcher) DeepCopyInto(out *IdentityMatcher) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdentityMatcher.
+func (in *IdentityMatcher) DeepCopy() *IdentityMatcher {
+	if in == nil {
+		return nil
+	}
+	out := new(IdentityMatcher)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InheritProjectMembers) DeepCopyInto(out *InheritProjectMembers) {
+	*out = *in
+	if in.RoleMapping != nil {
+		in, out := &in.RoleMapping, &out.RoleMapping
+		*out = make(map[ProjectMemberRole]WorkspaceMemberRole, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InheritProjectMembers.
+func (in *InheritProjectMembers) DeepCopy() *InheritProjectMembers {
+	if in == nil {
+		return nil
+	}
+	out := new(InheritProjectMembers)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InventoryConfig) DeepCopyInto(out *InventoryConfig) {
+	*out = *in
+	if in.ResyncInterval != nil {
+		in, out := &in.ResyncInterval, &out.ResyncInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InventoryConfig.
+func (in *InventoryConfig) DeepCopy() *InventoryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(InventoryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InventoryMember) DeepCopyInto(out *InventoryMember) {
+	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InventoryMember.
+func (in *InventoryMember) DeepCopy() *InventoryMember {
+	if in == nil {
+		return nil
+	}
+	out := new(InventoryMember)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Invitation) DeepCopyInto(out *Invitation) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Invitation.
+func (in *Invitation) DeepCopy() *Invitation {
+	if in == nil {
+		return nil
+	}
+	out := new(Invitation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Invitation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InvitationList) DeepCopyInto(out *InvitationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Invitation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InvitationList.
+func (in *InvitationList) DeepCopy() *InvitationList {
+	if in == nil {
+		return nil
+	}
+	out := new(InvitationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InvitationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InvitationSpec) DeepCopyInto(out *InvitationSpec) {
+	*out = *in
+	out.Subject = in.Subject
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InvitationSpec.
+func (in *InvitationSpec) DeepCopy() *InvitationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InvitationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InvitationStatus) DeepCopyInto(out *InvitationStatus) {
+	*out = *in
+	if in.ExpirationTime != nil {
+		in, out := &in.ExpirationTime, &out.ExpirationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InvitationStatus.
+func (in *InvitationStatus) DeepCopy() *InvitationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InvitationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoggingConfig) DeepCopyInto(out *LoggingConfig) {
+	*out = *in
+	if in.Controllers != nil {
+		in, out := &in.Controllers, &out.Controllers
+		*out = make(map[string]LogLevel, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoggingConfig.
+func (in *LoggingConfig) DeepCopy() *LoggingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LoggingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagementLabelsConfig) DeepCopyInto(out *ManagementLabelsConfig) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PreviousLabels != nil {
+		in, out := &in.PreviousLabels, &out.PreviousLabels
+		*out = make([]map[string]string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementLabelsConfig.
+func (in *ManagementLabelsConfig) DeepCopy() *ManagementLabelsConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagementLabelsConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemberPolicyConfig) DeepCopyInto(out *MemberPolicyConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemberPolicyConfig.
+func (in *MemberPolicyConfig) DeepCopy() *MemberPolicyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MemberPolicyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemberOverride) DeepCopyInto(out *MemberOverride) {
+	*out = *in
+	out.Subject = in.Subject
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]OverrideRole, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]OverrideResource, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemberOverride.
+func (in *MemberOverride) DeepCopy() *MemberOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(MemberOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemberOverridePruningConfig) DeepCopyInto(out *MemberOverridePruningConfig) {
+	*out = *in
+	if in.RetentionPeriod != nil {
+		in, out := &in.RetentionPeriod, &out.RetentionPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemberOverridePruningConfig.
+func (in *MemberOverridePruningConfig) DeepCopy() *MemberOverridePruningConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MemberOverridePruningConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemberOverrideSet) DeepCopyInto(out *MemberOverrideSet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemberOverrideSet.
+func (in *MemberOverrideSet) DeepCopy() *MemberOverrideSet {
+	if in == nil {
+		return nil
+	}
+	out := new(MemberOverrideSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MemberOverrideSet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemberOverrideSetList) DeepCopyInto(out *MemberOverrideSetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MemberOverrideSet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemberOverrideSetList.
+func (in *MemberOverrideSetList) DeepCopy() *MemberOverrideSetList {
+	if in == nil {
+		return nil
+	}
+	out := new(MemberOverrideSetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MemberOverrideSetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemberOverrideSetSpec) DeepCopyInto(out *MemberOverrideSetSpec) {
+	*out = *in
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make(MemberOverrides, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemberOverrideSetSpec.
+func (in *MemberOverrideSetSpec) DeepCopy() *MemberOverrideSetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MemberOverrideSetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in MemberOverrides) DeepCopyInto(out *MemberOverrides) {
+	{
+		in := &in
+		*out = make(MemberOverrides, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemberOverrides.
+func (in MemberOverrides) DeepCopy() MemberOverrides {
+	if in == nil {
+		return nil
+	}
+	out := new(MemberOverrides)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MembershipSourceReference) DeepCopyInto(out *MembershipSourceReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MembershipSourceReference.
+func (in *MembershipSourceReference) DeepCopy() *MembershipSourceReference {
+	if in == nil {
+		return nil
+	}
+	out := new(MembershipSourceReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicyTemplate) DeepCopyInto(out *NetworkPolicyTemplate) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyTemplate.
+func (in *NetworkPolicyTemplate) DeepCopy() *NetworkPolicyTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicyTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicyTemplateValues) DeepCopyInto(out *NetworkPolicyTemplateValues) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyTemplateValues.
+func (in *NetworkPolicyTemplateValues) DeepCopy() *NetworkPolicyTemplateValues {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicyTemplateValues)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorIdentitiesConfig) DeepCopyInto(out *OperatorIdentitiesConfig) {
+	*out = *in
+	in.ServiceAccountSelector.DeepCopyInto(&out.ServiceAccountSelector)
+	if in.RefreshInterval != nil {
+		in, out := &in.RefreshInterval, &out.RefreshInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorIdentitiesConfig.
+func (in *OperatorIdentitiesConfig) DeepCopy() *OperatorIdentitiesConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorIdentitiesConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OverrideResource) DeepCopyInto(out *OverrideResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OverrideResource.
+func (in *OverrideResource) DeepCopy() *OverrideResource {
+	if in == nil {
+		return nil
+	}
+	out := new(OverrideResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PWOHealth) DeepCopyInto(out *PWOHealth) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PWOHealth.
+func (in *PWOHealth) DeepCopy() *PWOHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(PWOHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PWOHealth) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PWOHealthList) DeepCopyInto(out *PWOHealthList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PWOHealth, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PWOHealthList.
+func (in *PWOHealthList) DeepCopy() *PWOHealthList {
+	if in == nil {
+		return nil
+	}
+	out := new(PWOHealthList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PWOHealthList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PWOHealthStatus) DeepCopyInto(out *PWOHealthStatus) {
+	*out = *in
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+	if in.Controllers != nil {
+		in, out := &in.Controllers, &out.Controllers
+		*out = make([]ControllerHealth, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.WebhookCertificate != nil {
+		in, out := &in.WebhookCertificate, &out.WebhookCertificate
+		*out = new(CertificateHealth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AccessRequests != nil {
+		in, out := &in.AccessRequests, &out.AccessRequests
+		*out = make([]AccessRequestHealth, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PWOHealthStatus.
+func (in *PWOHealthStatus) DeepCopy() *PWOHealthStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PWOHealthStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodSecurityConfig) DeepCopyInto(out *PodSecurityConfig) {
+	*out = *in
+	out.PodSecurityLevels = in.PodSecurityLevels
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSecurityConfig.
+func (in *PodSecurityConfig) DeepCopy() *PodSecurityConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PodSecurityConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodSecurityLevels) DeepCopyInto(out *PodSecurityLevels) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSecurityLevels.
+func (in *PodSecurityLevels) DeepCopy() *PodSecurityLevels {
+	if in == nil {
+		return nil
+	}
+	out := new(PodSecurityLevels)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Project) DeepCopyInto(out *Project) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Project.
+func (in *Project) DeepCopy() *Project {
+	if in == nil {
+		return nil
+	}
+	out := new(Project)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Project) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectAccess) DeepCopyInto(out *ProjectAccess) {
+	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]ProjectMemberRole, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectAccess.
+func (in *ProjectAccess) DeepCopy() *ProjectAccess {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectAccess)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectConfig) DeepCopyInto(out *ProjectConfig) {
+	*out = *in
+	if in.ResourcesBlockingDeletion != nil {
+		in, out := &in.ResourcesBlockingDeletion, &out.ResourcesBlockingDeletion
+		*out = make([]v1.GroupVersionKind, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedResourcesBlockingDeletion != nil {
+		in, out := &in.AllowedResourcesBlockingDeletion, &out.AllowedResourcesBlockingDeletion
+		*out = make([]v1.GroupVersionKind, len(*in))
+		copy(*out, *in)
+	}
+	if in.IgnoredBlockingResources != nil {
+		in, out := &in.IgnoredBlockingResources, &out.IgnoredBlockingResources
+		*out = make([]DeletionIgnoreRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AdditionalPermissions != nil {
+		in, out := &in.AdditionalPermissions, &out.AdditionalPermissions
+		*out = make(map[ProjectMemberRole][]rbacv1.PolicyRule, len(*in))
+		for key, val := range *in {
+			var outVal []rbacv1.PolicyRule
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]rbacv1.PolicyRule, len(*in))
+				for i := range *in {
+					(*in)[i].DeepCopyInto(&(*out)[i])
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.AuditorExcludedResources != nil {
+		in, out := &in.AuditorExcludedResources, &out.AuditorExcludedResources
+		*out = make([]v1.GroupResource, len(*in))
+		copy(*out, *in)
+	}
+	if in.AutomationServiceAccount != nil {
+		in, out := &in.AutomationServiceAccount, &out.AutomationServiceAccount
+		*out = new(AutomationServiceAccountConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConsolidatedClusterRoles != nil {
+		in, out := &in.ConsolidatedClusterRoles, &out.ConsolidatedClusterRoles
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DeletionGracePeriod != nil {
+		in, out := &in.DeletionGracePeriod, &out.DeletionGracePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.SecretStores != nil {
+		in, out := &in.SecretStores, &out.SecretStores
+		*out = make([]SecretStoreConfig, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxProjectsPerCreator != nil {
+		in, out := &in.MaxProjectsPerCreator, &out.MaxProjectsPerCreator
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectConfig.
+func (in *ProjectConfig) DeepCopy() *ProjectConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectInventory) DeepCopyInto(out *ProjectInventory) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectInventory.
+func (in *ProjectInventory) DeepCopy() *ProjectInventory {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectInventory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProjectInventory) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectInventoryList) DeepCopyInto(out *ProjectInventoryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ProjectInventory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectInventoryList.
+func (in *ProjectInventoryList) DeepCopy() *ProjectInventoryList {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectInventoryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProjectInventoryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectInventorySpec) DeepCopyInto(out *ProjectInventorySpec) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]InventoryMember, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DeletionTimestamp != nil {
+		in, out := &in.DeletionTimestamp, &out.DeletionTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.Workspaces != nil {
+		in, out := &in.Workspaces, &out.Workspaces
+		*out = make([]WorkspaceInventory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectInventorySpec.
+func (in *ProjectInventorySpec) DeepCopy() *ProjectInventorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectInventorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectList) DeepCopyInto(out *ProjectList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Project, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectList.
+func (in *ProjectList) DeepCopy() *ProjectList {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProjectList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectMember) DeepCopyInto(out *ProjectMember) {
+	*out = *in
+	out.Subject = in.Subject
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]ProjectMemberRole, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectMember.
+func (in *ProjectMember) DeepCopy() *ProjectMember {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectMember)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectMembershipSource) DeepCopyInto(out *ProjectMembershipSource) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectMembershipSource.
+func (in *ProjectMembershipSource) DeepCopy() *ProjectMembershipSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectMembershipSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProjectMembershipSource) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectMembershipSourceList) DeepCopyInto(out *ProjectMembershipSourceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ProjectMembershipSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectMembershipSourceList.
+func (in *ProjectMembershipSourceList) DeepCopy() *ProjectMembershipSourceList {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectMembershipSourceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProjectMembershipSourceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectMembershipSourceSpec) DeepCopyInto(out *ProjectMembershipSourceSpec) {
+	*out = *in
+	out.SourceRef = in.SourceRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectMembershipSourceSpec.
+func (in *ProjectMembershipSourceSpec) DeepCopy() *ProjectMembershipSourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectMembershipSourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectMembershipSourceStatus) DeepCopyInto(out *ProjectMembershipSourceStatus) {
+	*out = *in
+	if in.Projects != nil {
+		in, out := &in.Projects, &out.Projects
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Workspaces != nil {
+		in, out := &in.Workspaces, &out.Workspaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectMembershipSourceStatus.
+func (in *ProjectMembershipSourceStatus) DeepCopy() *ProjectMembershipSourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectMembershipSourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectQuota) DeepCopyInto(out *ProjectQuota) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectQuota.
+func (in *ProjectQuota) DeepCopy() *ProjectQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProjectQuota) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectQuotaList) DeepCopyInto(out *ProjectQuotaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ProjectQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectQuotaList.
+func (in *ProjectQuotaList) DeepCopy() *ProjectQuotaList {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectQuotaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProjectQuotaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectQuotaSpec) DeepCopyInto(out *ProjectQuotaSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectQuotaSpec.
+func (in *ProjectQuotaSpec) DeepCopy() *ProjectQuotaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectQuotaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectQuotaStatus) DeepCopyInto(out *ProjectQuotaStatus) {
+	*out = *in
+	if in.Projects != nil {
+		in, out := &in.Projects, &out.Projects
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectQuotaStatus.
+func (in *ProjectQuotaStatus) DeepCopy() *ProjectQuotaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectQuotaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectSpec) DeepCopyInto(out *ProjectSpec) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]ProjectMember, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SecretStores != nil {
+		in, out := &in.SecretStores, &out.SecretStores
+		*out = make([]SecretStoreReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodSecurity != nil {
+		in, out := &in.PodSecurity, &out.PodSecurity
+		*out = new(PodSecurityLevels)
+		**out = **in
+	}
+	if in.ResourcesBlockingDeletion != nil {
+		in, out := &in.ResourcesBlockingDeletion, &out.ResourcesBlockingDeletion
+		*out = make([]v1.GroupVersionKind, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectSpec.
+func (in *ProjectSpec) DeepCopy() *ProjectSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectStatus) DeepCopyInto(out *ProjectStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectStatus.
+func (in *ProjectStatus) DeepCopy() *ProjectStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectWorkspaceConfig) DeepCopyInto(out *ProjectWorkspaceConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectWorkspaceConfig.
+func (in *ProjectWorkspaceConfig) DeepCopy() *ProjectWorkspaceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectWorkspaceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProjectWorkspaceConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectWorkspaceConfigList) DeepCopyInto(out *ProjectWorkspaceConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ProjectWorkspaceConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectWorkspaceConfigList.
+func (in *ProjectWorkspaceConfigList) DeepCopy() *ProjectWorkspaceConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectWorkspaceConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProjectWorkspaceConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectWorkspaceConfigOverride) DeepCopyInto(out *ProjectWorkspaceConfigOverride) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectWorkspaceConfigOverride.
+func (in *ProjectWorkspaceConfigOverride) DeepCopy() *ProjectWorkspaceConfigOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectWorkspaceConfigOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProjectWorkspaceConfigOverride) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectWorkspaceConfigOverrideList) DeepCopyInto(out *ProjectWorkspaceConfigOverrideList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ProjectWorkspaceConfigOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectWorkspaceConfigOverrideList.
+func (in *ProjectWorkspaceConfigOverrideList) DeepCopy() *ProjectWorkspaceConfigOverrideList {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectWorkspaceConfigOverrideList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProjectWorkspaceConfigOverrideList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectWorkspaceConfigOverrideSpec) DeepCopyInto(out *ProjectWorkspaceConfigOverrideSpec) {
+	*out = *in
+	in.Project.DeepCopyInto(&out.Project)
+	in.Workspace.DeepCopyInto(&out.Workspace)
+	if in.MemberOverrides != nil {
+		in, out := &in.MemberOverrides, &out.MemberOverrides
+		*out = make(MemberOverrides, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectWorkspaceConfigOverrideSpec.
+func (in *ProjectWorkspaceConfigOverrideSpec) DeepCopy() *ProjectWorkspaceConfigOverrideSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectWorkspaceConfigOverrideSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectWorkspaceConfigSpec) DeepCopyInto(out *ProjectWorkspaceConfigSpec) {
+	*out = *in
+	in.Project.DeepCopyInto(&out.Project)
+	in.Workspace.DeepCopyInto(&out.Workspace)
+	if in.MemberOverrides != nil {
+		in, out := &in.MemberOverrides, &out.MemberOverrides
+		*out = make(MemberOverrides, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MemberOverrideSetSelector != nil {
+		in, out := &in.MemberOverrideSetSelector, &out.MemberOverrideSetSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MemberOverridePruning != nil {
+		in, out := &in.MemberOverridePruning, &out.MemberOverridePruning
+		*out = new(MemberOverridePruningConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Webhook.DeepCopyInto(&out.Webhook)
+	in.ManagementLabels.DeepCopyInto(&out.ManagementLabels)
+	if in.EventSink != nil {
+		in, out := &in.EventSink, &out.EventSink
+		*out = new(EventSinkConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Inventory != nil {
+		in, out := &in.Inventory, &out.Inventory
+		*out = new(InventoryConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(LoggingConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectWorkspaceConfigSpec.
+func (in *ProjectWorkspaceConfigSpec) DeepCopy() *ProjectWorkspaceConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectWorkspaceConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectWorkspaceConfigStatus) DeepCopyInto(out *ProjectWorkspaceConfigStatus) {
+	*out = *in
+	if in.LastDiscoveryTime != nil {
+		in, out := &in.LastDiscoveryTime, &out.LastDiscoveryTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ServiceProviders != nil {
+		in, out := &in.ServiceProviders, &out.ServiceProviders
+		*out = make([]ServiceProviderResources, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StaleMemberOverrides != nil {
+		in, out := &in.StaleMemberOverrides, &out.StaleMemberOverrides
+		*out = make([]StaleMemberOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectWorkspaceConfigStatus.
+func (in *ProjectWorkspaceConfigStatus) DeepCopy() *ProjectWorkspaceConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectWorkspaceConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderHintConfig) DeepCopyInto(out *ProviderHintConfig) {
+	*out = *in
+	if in.AllowedValues != nil {
+		in, out := &in.AllowedValues, &out.AllowedValues
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderHintConfig.
+func (in *ProviderHintConfig) DeepCopy() *ProviderHintConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderHintConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemainingContentDetails) DeepCopyInto(out *RemainingContentDetails) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]RemainingContentResource, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemainingContentDetails.
+func (in *RemainingContentDetails) DeepCopy() *RemainingContentDetails {
+	if in == nil {
+		return nil
+	}
+	out := new(RemainingContentDetails)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemainingContentResource) DeepCopyInto(out *RemainingContentResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemainingContentResource.
+func (in *RemainingContentResource) DeepCopy() *RemainingContentResource {
+	if in == nil {
+		return nil
+	}
+	out := new(RemainingContentResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceTemplate) DeepCopyInto(out *ResourceTemplate) {
+	*out = *in
+	in.Manifest.DeepCopyInto(&out.Manifest)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceTemplate.
+func (in *ResourceTemplate) DeepCopy() *ResourceTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretStoreConfig) DeepCopyInto(out *SecretStoreConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreConfig.
+func (in *SecretStoreConfig) DeepCopy() *SecretStoreConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretStoreConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretStoreReference) DeepCopyInto(out *SecretStoreReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreReference.
+func (in *SecretStoreReference) DeepCopy() *SecretStoreReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretStoreReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAccountMembersConfig) DeepCopyInto(out *ServiceAccountMembersConfig) {
+	*out = *in
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeniedNamespaces != nil {
+		in, out := &in.DeniedNamespaces, &out.DeniedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountMembersConfig.
+func (in *ServiceAccountMembersConfig) DeepCopy() *ServiceAccountMembersConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAccountMembersConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProjectSpec) DeepCopyInto(out *ProjectSpec) {
+func (in *ServiceProviderResources) DeepCopyInto(out *ServiceProviderResources) {
 	*out = *in
-	if in.Members != nil {
-		in, out := &in.Members, &out.Members
-		*out = make([]ProjectMember, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]DiscoveredResource, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectSpec.
-func (in *ProjectSpec) DeepCopy() *ProjectSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceProviderResources.
+func (in *ServiceProviderResources) DeepCopy() *ServiceProviderResources {
 	if in == nil {
 		return nil
 	}
-	out := new(ProjectSpec)
+	out := new(ServiceProviderResources)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProjectStatus) DeepCopyInto(out *ProjectStatus) {
+func (in *StaleMemberOverride) DeepCopyInto(out *StaleMemberOverride) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	out.Subject = in.Subject
+	out.Resource = in.Resource
+	in.Since.DeepCopyInto(&out.Since)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectStatus.
-func (in *ProjectStatus) DeepCopy() *ProjectStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StaleMemberOverride.
+func (in *StaleMemberOverride) DeepCopy() *StaleMemberOverride {
 	if in == nil {
 		return nil
 	}
-	out := new(ProjectStatus)
+	out := new(StaleMemberOverride)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProjectWorkspaceConfig) DeepCopyInto(out *ProjectWorkspaceConfig) {
+func (in *Subject) DeepCopyInto(out *Subject) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectWorkspaceConfig.
-func (in *ProjectWorkspaceConfig) DeepCopy() *ProjectWorkspaceConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Subject.
+func (in *Subject) DeepCopy() *Subject {
 	if in == nil {
 		return nil
 	}
-	out := new(ProjectWorkspaceConfig)
+	out := new(Subject)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ProjectWorkspaceConfig) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProjectWorkspaceConfigList) DeepCopyInto(out *ProjectWorkspaceConfigList) {
+func (in *WebhookConfig) DeepCopyInto(out *WebhookConfig) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]ProjectWorkspaceConfig, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.ExcludedIdentities != nil {
+		in, out := &in.ExcludedIdentities, &out.ExcludedIdentities
+		*out = make([]IdentityMatcher, len(*in))
+		copy(*out, *in)
+	}
+	in.DNS.DeepCopyInto(&out.DNS)
+	if in.ChargingTarget != nil {
+		in, out := &in.ChargingTarget, &out.ChargingTarget
+		*out = new(ChargingTargetConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MemberPolicy != nil {
+		in, out := &in.MemberPolicy, &out.MemberPolicy
+		*out = new(MemberPolicyConfig)
+		**out = **in
+	}
+	if in.OperatorIdentities != nil {
+		in, out := &in.OperatorIdentities, &out.OperatorIdentities
+		*out = new(OperatorIdentitiesConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Lookups != nil {
+		in, out := &in.Lookups, &out.Lookups
+		*out = new(WebhookLookupsConfig)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectWorkspaceConfigList.
-func (in *ProjectWorkspaceConfigList) DeepCopy() *ProjectWorkspaceConfigList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookConfig.
+func (in *WebhookConfig) DeepCopy() *WebhookConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(ProjectWorkspaceConfigList)
+	out := new(WebhookConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ProjectWorkspaceConfigList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProjectWorkspaceConfigSpec) DeepCopyInto(out *ProjectWorkspaceConfigSpec) {
+func (in *WebhookLookupsConfig) DeepCopyInto(out *WebhookLookupsConfig) {
 	*out = *in
-	in.Project.DeepCopyInto(&out.Project)
-	in.Workspace.DeepCopyInto(&out.Workspace)
-	if in.MemberOverrides != nil {
-		in, out := &in.MemberOverrides, &out.MemberOverrides
-		*out = make(MemberOverrides, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Retries != nil {
+		in, out := &in.Retries, &out.Retries
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailurePolicies != nil {
+		in, out := &in.FailurePolicies, &out.FailurePolicies
+		*out = make(map[WebhookCheck]WebhookFailurePolicy, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
-	out.Webhook = in.Webhook
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectWorkspaceConfigSpec.
-func (in *ProjectWorkspaceConfigSpec) DeepCopy() *ProjectWorkspaceConfigSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookLookupsConfig.
+func (in *WebhookLookupsConfig) DeepCopy() *WebhookLookupsConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(ProjectWorkspaceConfigSpec)
+	out := new(WebhookLookupsConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RemainingContentResource) DeepCopyInto(out *RemainingContentResource) {
+func (in *Workspace) DeepCopyInto(out *Workspace) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemainingContentResource.
-func (in *RemainingContentResource) DeepCopy() *RemainingContentResource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Workspace.
+func (in *Workspace) DeepCopy() *Workspace {
 	if in == nil {
 		return nil
 	}
-	out := new(RemainingContentResource)
+	out := new(Workspace)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Workspace) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Subject) DeepCopyInto(out *Subject) {
+func (in *WorkspaceAccess) DeepCopyInto(out *WorkspaceAccess) {
 	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]WorkspaceMemberRole, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Subject.
-func (in *Subject) DeepCopy() *Subject {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceAccess.
+func (in *WorkspaceAccess) DeepCopy() *WorkspaceAccess {
 	if in == nil {
 		return nil
 	}
-	out := new(Subject)
+	out := new(WorkspaceAccess)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *WebhookConfig) DeepCopyInto(out *WebhookConfig) {
+func (in *WorkspaceClass) DeepCopyInto(out *WorkspaceClass) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookConfig.
-func (in *WebhookConfig) DeepCopy() *WebhookConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceClass.
+func (in *WorkspaceClass) DeepCopy() *WorkspaceClass {
 	if in == nil {
 		return nil
 	}
-	out := new(WebhookConfig)
+	out := new(WorkspaceClass)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkspaceClass) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Workspace) DeepCopyInto(out *Workspace) {
+func (in *WorkspaceClassList) DeepCopyInto(out *WorkspaceClassList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]WorkspaceClass, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Workspace.
-func (in *Workspace) DeepCopy() *Workspace {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceClassList.
+func (in *WorkspaceClassList) DeepCopy() *WorkspaceClassList {
 	if in == nil {
 		return nil
 	}
-	out := new(Workspace)
+	out := new(WorkspaceClassList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Workspace) DeepCopyObject() runtime.Object {
+func (in *WorkspaceClassList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceClassSpec) DeepCopyInto(out *WorkspaceClassSpec) {
+	*out = *in
+	if in.NamespaceLabels != nil {
+		in, out := &in.NamespaceLabels, &out.NamespaceLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ResourceQuota != nil {
+		in, out := &in.ResourceQuota, &out.ResourceQuota
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.AdditionalPermissions != nil {
+		in, out := &in.AdditionalPermissions, &out.AdditionalPermissions
+		*out = make(map[WorkspaceMemberRole][]rbacv1.PolicyRule, len(*in))
+		for key, val := range *in {
+			var outVal []rbacv1.PolicyRule
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]rbacv1.PolicyRule, len(*in))
+				for i := range *in {
+					(*in)[i].DeepCopyInto(&(*out)[i])
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.ResourcesBlockingDeletion != nil {
+		in, out := &in.ResourcesBlockingDeletion, &out.ResourcesBlockingDeletion
+		*out = make([]v1.GroupVersionKind, len(*in))
+		copy(*out, *in)
+	}
+	if in.Flat != nil {
+		in, out := &in.Flat, &out.Flat
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PodSecurity != nil {
+		in, out := &in.PodSecurity, &out.PodSecurity
+		*out = new(PodSecurityLevels)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceClassSpec.
+func (in *WorkspaceClassSpec) DeepCopy() *WorkspaceClassSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceClassSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkspaceConfig) DeepCopyInto(out *WorkspaceConfig) {
 	*out = *in
@@ -420,6 +2317,18 @@ func (in *WorkspaceConfig) DeepCopyInto(out *WorkspaceConfig) {
 		*out = make([]v1.GroupVersionKind, len(*in))
 		copy(*out, *in)
 	}
+	if in.IgnoredBlockingResources != nil {
+		in, out := &in.IgnoredBlockingResources, &out.IgnoredBlockingResources
+		*out = make([]DeletionIgnoreRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BlockingResourcePolicies != nil {
+		in, out := &in.BlockingResourcePolicies, &out.BlockingResourcePolicies
+		*out = make([]BlockingResourcePolicyRule, len(*in))
+		copy(*out, *in)
+	}
 	if in.AdditionalPermissions != nil {
 		in, out := &in.AdditionalPermissions, &out.AdditionalPermissions
 		*out = make(map[WorkspaceMemberRole][]rbacv1.PolicyRule, len(*in))
@@ -438,6 +2347,47 @@ func (in *WorkspaceConfig) DeepCopyInto(out *WorkspaceConfig) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.AuditorExcludedResources != nil {
+		in, out := &in.AuditorExcludedResources, &out.AuditorExcludedResources
+		*out = make([]v1.GroupResource, len(*in))
+		copy(*out, *in)
+	}
+	if in.NetworkPolicies != nil {
+		in, out := &in.NetworkPolicies, &out.NetworkPolicies
+		*out = make([]NetworkPolicyTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AdditionalResources != nil {
+		in, out := &in.AdditionalResources, &out.AdditionalResources
+		*out = make([]ResourceTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Flat != nil {
+		in, out := &in.Flat, &out.Flat
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ServiceAccountMembers != nil {
+		in, out := &in.ServiceAccountMembers, &out.ServiceAccountMembers
+		*out = new(ServiceAccountMembersConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProviderHints != nil {
+		in, out := &in.ProviderHints, &out.ProviderHints
+		*out = make([]ProviderHintConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PodSecurity != nil {
+		in, out := &in.PodSecurity, &out.PodSecurity
+		*out = new(PodSecurityConfig)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceConfig.
@@ -450,6 +2400,32 @@ func (in *WorkspaceConfig) DeepCopy() *WorkspaceConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceInventory) DeepCopyInto(out *WorkspaceInventory) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]InventoryMember, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DeletionTimestamp != nil {
+		in, out := &in.DeletionTimestamp, &out.DeletionTimestamp
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceInventory.
+func (in *WorkspaceInventory) DeepCopy() *WorkspaceInventory {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceInventory)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkspaceList) DeepCopyInto(out *WorkspaceList) {
 	*out = *in
@@ -503,6 +2479,31 @@ func (in *WorkspaceMember) DeepCopy() *WorkspaceMember {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceMemberStatus) DeepCopyInto(out *WorkspaceMemberStatus) {
+	*out = *in
+	out.Subject = in.Subject
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]WorkspaceMemberRole, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastAppliedTime != nil {
+		in, out := &in.LastAppliedTime, &out.LastAppliedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceMemberStatus.
+func (in *WorkspaceMemberStatus) DeepCopy() *WorkspaceMemberStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceMemberStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkspaceSpec) DeepCopyInto(out *WorkspaceSpec) {
 	*out = *in
@@ -513,6 +2514,18 @@ func (in *WorkspaceSpec) DeepCopyInto(out *WorkspaceSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.InheritProjectMembers != nil {
+		in, out := &in.InheritProjectMembers, &out.InheritProjectMembers
+		*out = new(InheritProjectMembers)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProviderHints != nil {
+		in, out := &in.ProviderHints, &out.ProviderHints
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceSpec.
@@ -535,6 +2548,18 @@ func (in *WorkspaceStatus) DeepCopyInto(out *WorkspaceStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.MemberStatuses != nil {
+		in, out := &in.MemberStatuses, &out.MemberStatuses
+		*out = make([]WorkspaceMemberStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AdditionalResources != nil {
+		in, out := &in.AdditionalResources, &out.AdditionalResources
+		*out = make([]AppliedResource, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceStatus.
@@ -0,0 +1,86 @@
+// Package metadata contains helpers for the labels and annotations of Kubernetes objects.
+// It is part of the api module, so that it can be used by the platform service as well as by its clients, e.g. ServiceProviders.
+package metadata
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SetLabel sets the key value pair in the labels of the given object.
+// If the object does not have labels yet, they are initialized.
+func SetLabel(obj metav1.Object, key, value string) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[key] = value
+	obj.SetLabels(labels)
+}
+
+// SetAnnotation sets the key value pair in the annotations of the given object.
+// If the object does not have annotations yet, they are initialized.
+func SetAnnotation(obj metav1.Object, key, value string) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[key] = value
+	obj.SetAnnotations(annotations)
+}
+
+// EnsureLabels sets all given labels on the given object.
+// Returns true if any label has been added or changed.
+func EnsureLabels(obj metav1.Object, labels map[string]string) bool {
+	changed := false
+	for k, v := range labels {
+		if actual, ok := obj.GetLabels()[k]; ok && actual == v {
+			continue
+		}
+		SetLabel(obj, k, v)
+		changed = true
+	}
+	return changed
+}
+
+// EnsureAnnotations sets all given annotations on the given object.
+// Returns true if any annotation has been added or changed.
+func EnsureAnnotations(obj metav1.Object, annotations map[string]string) bool {
+	changed := false
+	for k, v := range annotations {
+		if actual, ok := obj.GetAnnotations()[k]; ok && actual == v {
+			continue
+		}
+		SetAnnotation(obj, k, v)
+		changed = true
+	}
+	return changed
+}
+
+// HasLabels returns true if the given object carries all expected labels with the expected values.
+func HasLabels(obj metav1.Object, expected map[string]string) bool {
+	labels := obj.GetLabels()
+	for k, v := range expected {
+		if actual, ok := labels[k]; !ok || actual != v {
+			return false
+		}
+	}
+	return true
+}
+
+// SameValue returns true if the value of the given key is the same in both maps.
+// A missing key is treated like an empty value.
+func SameValue(a, b map[string]string, key string) bool {
+	return a[key] == b[key]
+}
+
+// ChangedKeys returns those of the given keys whose value differs between the old and the new map, in the given order.
+// This is meant to enforce that protected labels or annotations are not changed, e.g. in webhooks.
+func ChangedKeys(oldValues, newValues map[string]string, keys ...string) []string {
+	var changed []string
+	for _, k := range keys {
+		if !SameValue(oldValues, newValues, k) {
+			changed = append(changed, k)
+		}
+	}
+	return changed
+}
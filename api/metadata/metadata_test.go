@@ -0,0 +1,163 @@
+package metadata_test
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openmcp-project/platform-service-project-workspace/api/v2/metadata"
+)
+
+func TestSetLabel(t *testing.T) {
+	t.Run("sets the label on an object which has no other labels set", func(t *testing.T) {
+		var obj metav1.ObjectMeta
+
+		metadata.SetLabel(&obj, "test", "abc")
+
+		expectMap(t, map[string]string{"test": "abc"}, obj.GetLabels())
+	})
+	t.Run("overwrites the label on an object if it was set before", func(t *testing.T) {
+		var obj metav1.ObjectMeta
+
+		metadata.SetLabel(&obj, "test", "abc")
+		metadata.SetLabel(&obj, "test", "def")
+
+		expectMap(t, map[string]string{"test": "def"}, obj.GetLabels())
+	})
+	t.Run("doesn't modify other labels", func(t *testing.T) {
+		var obj metav1.ObjectMeta
+
+		metadata.SetLabel(&obj, "a", "abc")
+		metadata.SetLabel(&obj, "b", "def")
+
+		expectMap(t, map[string]string{"a": "abc", "b": "def"}, obj.GetLabels())
+	})
+}
+
+func TestSetAnnotation(t *testing.T) {
+	obj := metav1.ObjectMeta{Labels: map[string]string{"a": "label"}}
+
+	metadata.SetAnnotation(&obj, "a", "abc")
+	metadata.SetAnnotation(&obj, "b", "def")
+
+	expectMap(t, map[string]string{"a": "abc", "b": "def"}, obj.GetAnnotations())
+	expectMap(t, map[string]string{"a": "label"}, obj.GetLabels())
+}
+
+func TestEnsureLabelsAndAnnotations(t *testing.T) {
+	tests := []struct {
+		description string
+		existing    map[string]string
+		ensured     map[string]string
+		expected    map[string]string
+		changed     bool
+	}{
+		{
+			description: "initializes the map of an object without labels or annotations",
+			ensured:     map[string]string{"a": "1"},
+			expected:    map[string]string{"a": "1"},
+			changed:     true,
+		},
+		{
+			description: "adds missing and overwrites different values",
+			existing:    map[string]string{"a": "1", "b": "old", "c": "3"},
+			ensured:     map[string]string{"b": "2", "d": "4"},
+			expected:    map[string]string{"a": "1", "b": "2", "c": "3", "d": "4"},
+			changed:     true,
+		},
+		{
+			description: "reports no change if all values are already set",
+			existing:    map[string]string{"a": "1", "b": "2"},
+			ensured:     map[string]string{"a": "1"},
+			expected:    map[string]string{"a": "1", "b": "2"},
+		},
+		{
+			description: "sets empty values of missing keys",
+			ensured:     map[string]string{"a": ""},
+			expected:    map[string]string{"a": ""},
+			changed:     true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			labeled := metav1.ObjectMeta{Labels: maps.Clone(test.existing)}
+			if changed := metadata.EnsureLabels(&labeled, test.ensured); changed != test.changed {
+				t.Errorf("expected labels changed to be %t, got %t", test.changed, changed)
+			}
+			expectMap(t, test.expected, labeled.GetLabels())
+
+			annotated := metav1.ObjectMeta{Annotations: maps.Clone(test.existing)}
+			if changed := metadata.EnsureAnnotations(&annotated, test.ensured); changed != test.changed {
+				t.Errorf("expected annotations changed to be %t, got %t", test.changed, changed)
+			}
+			expectMap(t, test.expected, annotated.GetAnnotations())
+		})
+	}
+}
+
+func TestHasLabels(t *testing.T) {
+	obj := &metav1.ObjectMeta{Labels: map[string]string{"a": "1", "b": "2"}}
+
+	tests := []struct {
+		description string
+		expected    map[string]string
+		result      bool
+	}{
+		{description: "returns true for a subset of the labels", expected: map[string]string{"a": "1"}, result: true},
+		{description: "returns true for no expected labels", result: true},
+		{description: "returns false for a different value", expected: map[string]string{"a": "2"}},
+		{description: "returns false for a missing label", expected: map[string]string{"a": "1", "c": "3"}},
+		{description: "returns false for a missing label with an empty expected value", expected: map[string]string{"c": ""}},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			if result := metadata.HasLabels(obj, test.expected); result != test.result {
+				t.Errorf("expected %t, got %t", test.result, result)
+			}
+		})
+	}
+}
+
+func TestSameValue(t *testing.T) {
+	tests := []struct {
+		description string
+		mapA        map[string]string
+		mapB        map[string]string
+		result      bool
+	}{
+		{description: "returns true if both maps contain the same value", mapA: map[string]string{"test": "test"}, mapB: map[string]string{"test": "test"}, result: true},
+		{description: "returns true if both maps don't contain the key", result: true},
+		{description: "returns false if both maps contain different values", mapA: map[string]string{"test": "test1"}, mapB: map[string]string{"test": "test2"}},
+		{description: "returns false if mapA doesn't contain the key", mapB: map[string]string{"test": "test"}},
+		{description: "returns false if mapB doesn't contain the key", mapA: map[string]string{"test": "test"}},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			if result := metadata.SameValue(test.mapA, test.mapB, "test"); result != test.result {
+				t.Errorf("expected %t, got %t", test.result, result)
+			}
+		})
+	}
+}
+
+func TestChangedKeys(t *testing.T) {
+	oldValues := map[string]string{"a": "1", "b": "2", "c": "3"}
+	newValues := map[string]string{"a": "1", "b": "changed", "d": "added", "other": "changed"}
+
+	changed := metadata.ChangedKeys(oldValues, newValues, "d", "a", "b", "c")
+	if expected := []string{"d", "b", "c"}; !slices.Equal(expected, changed) {
+		t.Errorf("expected changed keys %v, got %v", expected, changed)
+	}
+	if changed := metadata.ChangedKeys(oldValues, oldValues, "a", "b"); len(changed) != 0 {
+		t.Errorf("expected no changed keys, got %v", changed)
+	}
+}
+
+func expectMap(t *testing.T, expected, actual map[string]string) {
+	t.Helper()
+	if !maps.Equal(expected, actual) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
@@ -0,0 +1,192 @@
+// Package webhooktest contains helpers to test admission webhooks for the resources of this API without a running API server.
+// Requests are built from typed objects and passed through the same decoding and response handling as in the webhook server,
+// so that new validation and defaulting rules can be covered by plain unit tests instead of an envtest based suite.
+package webhooktest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	authv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+)
+
+// DefaultUsername is the name of the user who sends requests, unless another user is set with AsUser.
+const DefaultUsername = "test-user"
+
+// Scheme contains the Kubernetes core types and the types of this API. It is used to encode requests and by NewFakeClient.
+var Scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(Scheme))
+	utilruntime.Must(pwv1alpha1.AddToScheme(Scheme))
+}
+
+// NewFakeClient returns a fake client with the given objects, which can be passed to webhooks that look up other resources.
+func NewFakeClient(objs ...client.Object) client.WithWatch {
+	return fake.NewClientBuilder().WithScheme(Scheme).WithObjects(objs...).Build()
+}
+
+// RequestOption modifies an admission request built by one of the request builders.
+type RequestOption func(*admission.Request)
+
+// AsUser sends the request as the user with the given name and groups.
+func AsUser(username string, groups ...string) RequestOption {
+	return func(req *admission.Request) {
+		req.UserInfo = authv1.UserInfo{Username: username, Groups: groups}
+	}
+}
+
+// DryRun marks the request as dry run.
+func DryRun() RequestOption {
+	return func(req *admission.Request) {
+		dryRun := true
+		req.DryRun = &dryRun
+	}
+}
+
+// CreateRequest returns an admission request for the creation of the given object.
+func CreateRequest(obj client.Object, opts ...RequestOption) admission.Request {
+	return newRequest(admissionv1.Create, obj, nil, opts)
+}
+
+// UpdateRequest returns an admission request for the update of oldObj to obj.
+func UpdateRequest(oldObj, obj client.Object, opts ...RequestOption) admission.Request {
+	return newRequest(admissionv1.Update, obj, oldObj, opts)
+}
+
+// DeleteRequest returns an admission request for the deletion of the given object.
+// Like the API server, the object is passed as old object.
+func DeleteRequest(obj client.Object, opts ...RequestOption) admission.Request {
+	return newRequest(admissionv1.Delete, nil, obj, opts)
+}
+
+// newRequest builds an admission request for the given operation, the name, namespace and kind are taken from obj or, if it is nil, from oldObj.
+// It panics if the objects cannot be encoded, since this is always a mistake in the test.
+func newRequest(op admissionv1.Operation, obj, oldObj client.Object, opts []RequestOption) admission.Request {
+	ref := obj
+	if ref == nil {
+		ref = oldObj
+	}
+	gvk, err := apiutil.GVKForObject(ref, Scheme)
+	if err != nil {
+		panic(fmt.Errorf("failed to get kind of %T: %w", ref, err))
+	}
+	gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+
+	req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		UID:       uuid.NewUUID(),
+		Kind:      metav1.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind},
+		Resource:  metav1.GroupVersionResource{Group: gvr.Group, Version: gvr.Version, Resource: gvr.Resource},
+		Name:      ref.GetName(),
+		Namespace: ref.GetNamespace(),
+		Operation: op,
+		UserInfo:  authv1.UserInfo{Username: DefaultUsername, Groups: []string{"system:authenticated"}},
+	}}
+	if obj != nil {
+		req.Object = encode(obj, gvk)
+	}
+	if oldObj != nil {
+		req.OldObject = encode(oldObj, gvk)
+	}
+	for _, opt := range opts {
+		opt(&req)
+	}
+	return req
+}
+
+// encode returns the given object with apiVersion and kind as raw JSON, like the API server sends it.
+func encode(obj client.Object, gvk schema.GroupVersionKind) runtime.RawExtension {
+	obj = obj.DeepCopyObject().(client.Object)
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		panic(fmt.Errorf("failed to encode %T: %w", obj, err))
+	}
+	return runtime.RawExtension{Raw: raw}
+}
+
+// Harness passes admission requests to the defaulter and validator of a webhook for objects of type T, e.g. *Project.
+// Either of them may be nil if the webhook only mutates or only validates.
+type Harness[T client.Object] struct {
+	defaulter  admission.Defaulter[T]
+	validating *admission.Webhook
+}
+
+// NewHarness returns a harness for the given defaulter and validator.
+func NewHarness[T client.Object](defaulter admission.Defaulter[T], validator admission.Validator[T]) *Harness[T] {
+	h := &Harness[T]{defaulter: defaulter}
+	if validator != nil {
+		h.validating = admission.WithValidator(Scheme, validator)
+	}
+	return h
+}
+
+// Default decodes the object of the given request and passes it to the defaulter.
+// Returns the defaulted object, so that tests can check the changes directly instead of inspecting JSON patches.
+func (h *Harness[T]) Default(ctx context.Context, req admission.Request) (T, error) {
+	obj := newObject[T]()
+	if h.defaulter == nil {
+		return obj, fmt.Errorf("harness for %T has no defaulter", obj)
+	}
+	if err := admission.NewDecoder(Scheme).DecodeRaw(req.Object, obj); err != nil {
+		return obj, fmt.Errorf("failed to decode object: %w", err)
+	}
+	// the webhook server passes a logger and the request in the context
+	ctx = admission.NewContextWithRequest(logf.IntoContext(ctx, logf.FromContext(ctx)), req)
+	return obj, h.defaulter.Default(ctx, obj)
+}
+
+// Validate passes the given request to the validator and returns the response, like the webhook server would.
+// Use Allowed, Result.Message and Warnings of the response to check the outcome.
+func (h *Harness[T]) Validate(ctx context.Context, req admission.Request) admission.Response {
+	if h.validating == nil {
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("harness for %T has no validator", newObject[T]()))
+	}
+	return h.validating.Handle(ctx, req)
+}
+
+// Admit passes the given request to the defaulter and then the defaulted object to the validator,
+// like the API server calls mutating webhooks before validating ones. Delete requests are only validated.
+// Returns the defaulted object along with the response of the validator, or the error of the defaulter as denied response.
+func (h *Harness[T]) Admit(ctx context.Context, req admission.Request) (T, admission.Response) {
+	obj := newObject[T]()
+	if h.defaulter != nil && req.Operation != admissionv1.Delete {
+		var err error
+		if obj, err = h.Default(ctx, req); err != nil {
+			return obj, admission.Denied(err.Error())
+		}
+		raw, err := json.Marshal(obj)
+		if err != nil {
+			return obj, admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to encode defaulted object: %w", err))
+		}
+		req.Object = runtime.RawExtension{Raw: raw}
+	}
+	if h.validating == nil {
+		return obj, admission.Allowed("")
+	}
+	return obj, h.Validate(ctx, req)
+}
+
+// newObject returns a new object of type T, which must be a pointer to a struct.
+func newObject[T client.Object]() T {
+	var zero T
+	return reflect.New(reflect.TypeOf(zero).Elem()).Interface().(T)
+}
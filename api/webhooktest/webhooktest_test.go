@@ -0,0 +1,159 @@
+package webhooktest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/api/v2/metadata"
+	"github.com/openmcp-project/platform-service-project-workspace/api/v2/webhooktest"
+)
+
+// projectWebhook sets the display name of projects and rejects projects without display name or deletions by other users than the creator.
+type projectWebhook struct{}
+
+func (projectWebhook) Default(ctx context.Context, project *pwv1alpha1.Project) error {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if project.Name == "invalid" {
+		return errors.New("invalid name")
+	}
+	metadata.SetAnnotation(project, pwv1alpha1.DisplayNameAnnotation, project.Name)
+	metadata.SetAnnotation(project, pwv1alpha1.CreatedByAnnotation, req.UserInfo.Username)
+	return nil
+}
+
+func (projectWebhook) ValidateCreate(_ context.Context, project *pwv1alpha1.Project) (admission.Warnings, error) {
+	if project.Annotations[pwv1alpha1.DisplayNameAnnotation] == "" {
+		return nil, errors.New("display name missing")
+	}
+	return admission.Warnings{"created"}, nil
+}
+
+func (projectWebhook) ValidateUpdate(_ context.Context, oldProject, project *pwv1alpha1.Project) (admission.Warnings, error) {
+	if oldProject.Annotations[pwv1alpha1.DisplayNameAnnotation] == project.Annotations[pwv1alpha1.DisplayNameAnnotation] {
+		return admission.Warnings{"unchanged"}, nil
+	}
+	return nil, nil
+}
+
+func (projectWebhook) ValidateDelete(ctx context.Context, project *pwv1alpha1.Project) (admission.Warnings, error) {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.UserInfo.Username != project.Annotations[pwv1alpha1.CreatedByAnnotation] {
+		return nil, errors.New("not the creator")
+	}
+	return nil, nil
+}
+
+func newProject(name string) *pwv1alpha1.Project {
+	return &pwv1alpha1.Project{ObjectMeta: metav1.ObjectMeta{Name: name}}
+}
+
+func TestRequests(t *testing.T) {
+	t.Run("builds a create request with kind, resource and the default user", func(t *testing.T) {
+		req := webhooktest.CreateRequest(newProject("foo"))
+		if req.Operation != admissionv1.Create || req.Name != "foo" || req.UID == "" {
+			t.Errorf("unexpected request %+v", req.AdmissionRequest)
+		}
+		if req.Kind.Kind != "Project" || req.Kind.Group != pwv1alpha1.GroupVersion.Group || req.Resource.Resource != "projects" {
+			t.Errorf("unexpected kind %v or resource %v", req.Kind, req.Resource)
+		}
+		if req.UserInfo.Username != webhooktest.DefaultUsername {
+			t.Errorf("expected default user, got %s", req.UserInfo.Username)
+		}
+		if len(req.Object.Raw) == 0 || len(req.OldObject.Raw) != 0 {
+			t.Errorf("expected only the object to be set")
+		}
+	})
+
+	t.Run("applies the options", func(t *testing.T) {
+		req := webhooktest.UpdateRequest(newProject("foo"), newProject("foo"), webhooktest.AsUser("jane", "devs"), webhooktest.DryRun())
+		if req.UserInfo.Username != "jane" || len(req.UserInfo.Groups) != 1 || req.UserInfo.Groups[0] != "devs" {
+			t.Errorf("unexpected user %v", req.UserInfo)
+		}
+		if req.DryRun == nil || !*req.DryRun {
+			t.Errorf("expected a dry run request")
+		}
+		if len(req.Object.Raw) == 0 || len(req.OldObject.Raw) == 0 {
+			t.Errorf("expected both objects to be set")
+		}
+	})
+
+	t.Run("passes the deleted object as old object", func(t *testing.T) {
+		req := webhooktest.DeleteRequest(newProject("foo"))
+		if req.Name != "foo" || len(req.Object.Raw) != 0 || len(req.OldObject.Raw) == 0 {
+			t.Errorf("unexpected request %+v", req.AdmissionRequest)
+		}
+	})
+}
+
+func TestHarness(t *testing.T) {
+	ctx := context.Background()
+	h := webhooktest.NewHarness[*pwv1alpha1.Project](projectWebhook{}, projectWebhook{})
+
+	t.Run("Default returns the defaulted object", func(t *testing.T) {
+		project, err := h.Default(ctx, webhooktest.CreateRequest(newProject("foo"), webhooktest.AsUser("jane")))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if project.Annotations[pwv1alpha1.DisplayNameAnnotation] != "foo" || project.Annotations[pwv1alpha1.CreatedByAnnotation] != "jane" {
+			t.Errorf("unexpected annotations %v", project.Annotations)
+		}
+	})
+
+	t.Run("Validate returns the response of the validator", func(t *testing.T) {
+		res := h.Validate(ctx, webhooktest.CreateRequest(newProject("foo")))
+		if res.Allowed || res.Result.Message != "display name missing" {
+			t.Errorf("expected the request to be denied, got %+v", res.AdmissionResponse)
+		}
+
+		res = h.Validate(ctx, webhooktest.UpdateRequest(newProject("foo"), newProject("foo")))
+		if !res.Allowed || len(res.Warnings) != 1 || res.Warnings[0] != "unchanged" {
+			t.Errorf("expected the request to be allowed with a warning, got %+v", res.AdmissionResponse)
+		}
+	})
+
+	t.Run("Admit validates the defaulted object", func(t *testing.T) {
+		project, res := h.Admit(ctx, webhooktest.CreateRequest(newProject("foo")))
+		if !res.Allowed || len(res.Warnings) != 1 || res.Warnings[0] != "created" {
+			t.Errorf("expected the request to be allowed with a warning, got %+v", res.AdmissionResponse)
+		}
+		if project.Annotations[pwv1alpha1.DisplayNameAnnotation] != "foo" {
+			t.Errorf("expected the defaulted object, got %v", project.Annotations)
+		}
+
+		_, res = h.Admit(ctx, webhooktest.CreateRequest(newProject("invalid")))
+		if res.Allowed || res.Result.Message != "invalid name" {
+			t.Errorf("expected the error of the defaulter, got %+v", res.AdmissionResponse)
+		}
+	})
+
+	t.Run("Admit only validates deletions", func(t *testing.T) {
+		project := newProject("foo")
+		metadata.SetAnnotation(project, pwv1alpha1.CreatedByAnnotation, "jane")
+		if _, res := h.Admit(ctx, webhooktest.DeleteRequest(project, webhooktest.AsUser("jane"))); !res.Allowed {
+			t.Errorf("expected the creator to be allowed, got %+v", res.AdmissionResponse)
+		}
+		if _, res := h.Admit(ctx, webhooktest.DeleteRequest(project, webhooktest.AsUser("john"))); res.Allowed {
+			t.Errorf("expected other users to be denied")
+		}
+	})
+
+	t.Run("NewFakeClient returns a client with the given objects", func(t *testing.T) {
+		c := webhooktest.NewFakeClient(newProject("foo"))
+		projects := &pwv1alpha1.ProjectList{}
+		if err := c.List(ctx, projects); err != nil || len(projects.Items) != 1 {
+			t.Errorf("expected one project, got %d (%v)", len(projects.Items), err)
+		}
+	})
+}
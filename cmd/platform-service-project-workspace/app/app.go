@@ -10,6 +10,9 @@ import (
 
 	"github.com/openmcp-project/controller-utils/pkg/clusters"
 	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	"github.com/openmcp-project/platform-service-project-workspace/internal/logconfig"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/restconfig"
 )
 
 const (
@@ -33,8 +36,11 @@ func NewPlatformServiceProjectWorkspaceCommand() *cobra.Command {
 		PlatformCluster:  clusters.New("platform"),
 	}
 	so.AddPersistentFlags(cmd)
+	addOptionsFileFlag(cmd, &so.OptionsFile)
 	cmd.AddCommand(NewInitCommand(so))
 	cmd.AddCommand(NewRunCommand(so))
+	cmd.AddCommand(NewExportCommand(so))
+	cmd.AddCommand(NewUninstallCommand(so))
 
 	return cmd
 }
@@ -43,6 +49,9 @@ type RawSharedOptions struct {
 	Environment  string `json:"environment"`
 	ProviderName string `json:"provider-name"`
 	DryRun       bool   `json:"dry-run"`
+	OptionsFile  string `json:"options-file,omitempty"`
+	// OnboardingConnection contains additional settings for connecting to the onboarding cluster, e.g. via a proxy.
+	OnboardingConnection restconfig.ConnectionOptions `json:"onboarding-connection"`
 }
 
 type SharedOptions struct {
@@ -51,6 +60,8 @@ type SharedOptions struct {
 
 	// fields filled in Complete()
 	Log logging.Logger
+	// LogLevels are the log levels from the ProjectWorkspaceConfig, which are applied to Log.
+	LogLevels *logconfig.Levels
 }
 
 func (o *SharedOptions) AddPersistentFlags(cmd *cobra.Command) {
@@ -62,6 +73,10 @@ func (o *SharedOptions) AddPersistentFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringVar(&o.Environment, "environment", "", "Environment name. Required. This is used to distinguish between different environments that are watching the same Onboarding cluster. Must be globally unique.")
 	// provider name
 	cmd.PersistentFlags().StringVar(&o.ProviderName, "provider-name", "", "Name of the provider resource.")
+	// onboarding cluster connection
+	cmd.PersistentFlags().StringVar(&o.OnboardingConnection.ProxyURL, "onboarding-proxy-url", "", "URL of the proxy through which the onboarding cluster is reached, e.g. 'http://proxy.example.com:3128'. Supported schemes are 'http', 'https', and 'socks5'.")
+	cmd.PersistentFlags().StringVar(&o.OnboardingConnection.CABundleFile, "onboarding-ca-bundle", "", "Path to a file with PEM encoded CA certificates which are trusted in addition to the CA from the onboarding cluster kubeconfig, e.g. the CA of a TLS intercepting proxy.")
+	cmd.PersistentFlags().StringVar(&o.OnboardingConnection.TLSServerName, "onboarding-tls-server-name", "", "Server name which is used to verify the certificate of the onboarding cluster API server, if it differs from the host in the kubeconfig.")
 	cmd.PersistentFlags().BoolVar(&o.DryRun, "dry-run", false, "If set, the command aborts after evaluation of the given flags.")
 }
 
@@ -78,9 +93,14 @@ func (o *SharedOptions) Complete() error {
 	if err != nil {
 		return err
 	}
-	o.Log = log
+	o.LogLevels = logconfig.NewLevels()
+	o.Log = o.LogLevels.Wrap(log).WithValues(logconfig.EnvironmentKey, o.Environment, logconfig.ProviderNameKey, o.ProviderName)
 	ctrl.SetLogger(o.Log.Logr())
 
+	if err := o.OnboardingConnection.Validate(); err != nil {
+		return fmt.Errorf("invalid onboarding cluster connection settings: %w", err)
+	}
+
 	if err := o.PlatformCluster.InitializeRESTConfig(); err != nil {
 		return err
 	}
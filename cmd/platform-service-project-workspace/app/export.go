@@ -0,0 +1,89 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/openmcp-project/controller-utils/pkg/clusters"
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	providerscheme "github.com/openmcp-project/platform-service-project-workspace/api/v2/install"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/export"
+)
+
+func NewExportCommand(so *SharedOptions) *cobra.Command {
+	opts := &ExportOptions{
+		SharedOptions:     so,
+		RawExportOptions:  &RawExportOptions{},
+		OnboardingCluster: clusters.New("onboarding"),
+	}
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export all projects and workspaces with their members",
+		Long:  "Connects to the onboarding cluster and writes a report of all projects and workspaces with their members, roles, creators, and charging targets to stdout.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Complete(); err != nil {
+				return fmt.Errorf("error completing options: %w", err)
+			}
+			return opts.Run(cmd.Context(), cmd)
+		},
+	}
+	opts.AddFlags(cmd)
+
+	return cmd
+}
+
+type RawExportOptions struct {
+	Format string `json:"format"`
+}
+
+type ExportOptions struct {
+	*SharedOptions
+	*RawExportOptions
+	OnboardingCluster *clusters.Cluster
+}
+
+func (o *ExportOptions) AddFlags(cmd *cobra.Command) {
+	o.OnboardingCluster.RegisterConfigPathFlag(cmd.Flags())
+	cmd.Flags().StringVar(&o.Format, "format", string(export.FormatJSON), fmt.Sprintf("Output format, one of %v.", export.Formats()))
+}
+
+func (o *ExportOptions) Complete() error {
+	if !slices.Contains(export.Formats(), export.Format(o.Format)) {
+		return fmt.Errorf("unsupported format '%s', must be one of %v", o.Format, export.Formats())
+	}
+
+	// build logger
+	log, err := logging.GetLogger()
+	if err != nil {
+		return err
+	}
+	ctrl.SetLogger(log.Logr())
+
+	// the export only needs access to the onboarding cluster, so the generic '--kubeconfig' flag can be used as well
+	if o.OnboardingCluster.ConfigPath() == "" {
+		o.OnboardingCluster.WithConfigPath(o.PlatformCluster.ConfigPath())
+	}
+	if err := o.OnboardingCluster.InitializeRESTConfig(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (o *ExportOptions) Run(ctx context.Context, cmd *cobra.Command) error {
+	if err := o.OnboardingCluster.InitializeClient(providerscheme.InstallOperatorAPIsOnboarding(runtime.NewScheme())); err != nil {
+		return err
+	}
+
+	report, err := export.Collect(ctx, o.OnboardingCluster.Client())
+	if err != nil {
+		return err
+	}
+	return report.Write(cmd.OutOrStdout(), export.Format(o.Format))
+}
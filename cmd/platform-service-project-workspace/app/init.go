@@ -3,9 +3,13 @@ package app
 import (
 	"context"
 	"fmt"
+	"net"
 	"os"
+	"slices"
+	"strconv"
 	"time"
 
+	"github.com/openmcp-project/controller-utils/pkg/clusters"
 	ctrlutils "github.com/openmcp-project/controller-utils/pkg/controller"
 	crdutil "github.com/openmcp-project/controller-utils/pkg/crds"
 	"github.com/openmcp-project/controller-utils/pkg/init/webhooks"
@@ -15,8 +19,11 @@ import (
 	"github.com/openmcp-project/openmcp-operator/lib/clusteraccess"
 	libutils "github.com/openmcp-project/openmcp-operator/lib/utils"
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -26,6 +33,8 @@ import (
 	providerscheme "github.com/openmcp-project/platform-service-project-workspace/api/v2/install"
 	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/core"
 	"github.com/openmcp-project/platform-service-project-workspace/internal/dns"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/systemnamespace"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/webhookconfig"
 )
 
 func NewInitCommand(so *SharedOptions) *cobra.Command {
@@ -55,11 +64,28 @@ func NewInitCommand(so *SharedOptions) *cobra.Command {
 	return cmd
 }
 
+type RawInitOptions struct {
+	// webhook DNS flags, which take precedence over the DNS configuration in the ProjectWorkspaceConfig
+	WebhookHostName        string   `json:"webhook-host-name"`
+	WebhookPort            int32    `json:"webhook-port"`
+	WebhookGatewayListener string   `json:"webhook-gateway-listener"`
+	WebhookIPFamilies      []string `json:"webhook-ip-families"`
+	// IngressPorts are the ports of the platform service pods which are reachable in addition to the webhook port.
+	IngressPorts []int32 `json:"ingress-ports"`
+}
+
 type InitOptions struct {
 	*SharedOptions
+	RawInitOptions
 }
 
-func (o *InitOptions) AddFlags(cmd *cobra.Command) {}
+func (o *InitOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.WebhookHostName, "webhook-host-name", "", "The host name under which the webhooks are exposed. Overwrites the host name for this environment from the ProjectWorkspaceConfig.")
+	cmd.Flags().Int32Var(&o.WebhookPort, "webhook-port", 0, "The port under which the webhooks are exposed. Overwrites the port from the ProjectWorkspaceConfig.")
+	cmd.Flags().StringVar(&o.WebhookGatewayListener, "webhook-gateway-listener", "", "The name of the TLS listener of the gateway to which the webhook route is attached. Overwrites the listener name from the ProjectWorkspaceConfig.")
+	cmd.Flags().StringSliceVar(&o.WebhookIPFamilies, "webhook-ip-families", nil, "The IP families (IPv4, IPv6) under which the webhooks are reachable. Overwrites the IP families from the ProjectWorkspaceConfig.")
+	cmd.Flags().Int32SliceVar(&o.IngressPorts, "ingress-ports", nil, "Ports of the platform service pods which accept traffic in addition to the webhook port, e.g. the metrics port. The NetworkPolicy in the provider system namespace denies ingress traffic to all other ports.")
+}
 
 func (o *InitOptions) Complete(ctx context.Context) error {
 	if err := o.SharedOptions.Complete(); err != nil {
@@ -69,34 +95,49 @@ func (o *InitOptions) Complete(ctx context.Context) error {
 	return nil
 }
 
-func (o *InitOptions) Run(ctx context.Context) error {
-	if err := o.PlatformCluster.InitializeClient(providerscheme.InstallOperatorAPIsPlatform(runtime.NewScheme())); err != nil {
-		return err
+// dnsConfig returns the given DNS configuration from the ProjectWorkspaceConfig, overwritten with the values from the flags.
+// The result is validated, because the flags could render it invalid.
+func (o *InitOptions) dnsConfig(cfg pwv1alpha1.DNSConfig) (pwv1alpha1.DNSConfig, error) {
+	res := *cfg.DeepCopy()
+	if o.WebhookHostName != "" {
+		if res.HostNames == nil {
+			res.HostNames = map[string]string{}
+		}
+		res.HostNames[o.Environment] = o.WebhookHostName
 	}
-
-	log := o.Log.WithName("main")
-	ctx = logging.NewContext(ctx, log)
-	log.Info("Environment", "value", o.Environment)
-	log.Info("ProviderName", "value", o.ProviderName)
-
-	log.Info("Getting access to the onboarding cluster")
-	onboardingScheme := runtime.NewScheme()
-	providerscheme.InstallOperatorAPIsOnboarding(onboardingScheme)
-
-	providerSystemNamespace := os.Getenv(openmcpconst.EnvVariablePodNamespace)
-	if providerSystemNamespace == "" {
-		return fmt.Errorf("environment variable %s is not set", openmcpconst.EnvVariablePodNamespace)
+	if o.WebhookPort != 0 {
+		res.Port = o.WebhookPort
 	}
+	if o.WebhookGatewayListener != "" {
+		if res.Gateway == nil {
+			res.Gateway = &pwv1alpha1.GatewayDNSConfig{}
+		}
+		res.Gateway.ListenerName = o.WebhookGatewayListener
+	}
+	if len(o.WebhookIPFamilies) > 0 {
+		res.IPFamilies = make([]corev1.IPFamily, 0, len(o.WebhookIPFamilies))
+		for _, family := range o.WebhookIPFamilies {
+			res.IPFamilies = append(res.IPFamilies, corev1.IPFamily(family))
+		}
+	}
+	if err := res.Validate(); err != nil {
+		return res, fmt.Errorf("invalid webhook DNS configuration: %w", err)
+	}
+	return res, nil
+}
 
+// onboardingClusterAccess requests access to the onboarding cluster with the permissions required to install and uninstall the CRDs and webhooks, and the given additional permissions.
+// The localName distinguishes the AccessRequests of the different commands.
+func (o *SharedOptions) onboardingClusterAccess(ctx context.Context, log logging.Logger, providerSystemNamespace, localName string, onboardingScheme *runtime.Scheme, additionalRules ...rbacv1.PolicyRule) (*clusters.Cluster, error) {
 	clusterAccessManager := clusteraccess.NewClusterAccessManager(o.PlatformCluster.Client(), core.ControllerName, providerSystemNamespace)
 	clusterAccessManager.WithLogger(&log).
 		WithInterval(10 * time.Second).
 		WithTimeout(30 * time.Minute)
 
-	onboardingCluster, err := clusterAccessManager.CreateAndWaitForCluster(ctx, clustersv1alpha1.PURPOSE_ONBOARDING+"-init", clustersv1alpha1.PURPOSE_ONBOARDING,
+	onboardingCluster, err := clusterAccessManager.CreateAndWaitForCluster(ctx, localName, clustersv1alpha1.PURPOSE_ONBOARDING,
 		onboardingScheme, []clustersv1alpha1.PermissionsRequest{
 			{
-				Rules: []rbacv1.PolicyRule{
+				Rules: append([]rbacv1.PolicyRule{
 					{
 						APIGroups: []string{"apiextensions.k8s.io"},
 						Resources: []string{"customresourcedefinitions"},
@@ -112,12 +153,123 @@ func (o *InitOptions) Run(ctx context.Context) error {
 						Resources: []string{"secrets", "services"},
 						Verbs:     []string{"*"},
 					},
-				},
+				}, additionalRules...),
 			},
 		})
 
 	if err != nil {
-		return fmt.Errorf("error creating/updating onboarding cluster: %w", err)
+		return nil, fmt.Errorf("error creating/updating onboarding cluster: %w", err)
+	}
+	onboardingCluster, err = o.OnboardingConnection.ApplyToCluster(onboardingCluster)
+	if err != nil {
+		return nil, fmt.Errorf("error configuring onboarding cluster connection: %w", err)
+	}
+	return onboardingCluster, nil
+}
+
+// systemNamespaceSetup returns the setup of the provider system namespace, the webhook port is only reachable if webhooks are enabled.
+func (o *InitOptions) systemNamespaceSetup(providerSystemNamespace string, webhooksEnabled bool) *systemnamespace.Setup {
+	ports := slices.Clone(o.IngressPorts)
+	if webhooksEnabled {
+		ports = append([]int32{WebhookPortPod}, ports...)
+	}
+	return &systemnamespace.Setup{
+		Client:       o.PlatformCluster.Client(),
+		Namespace:    providerSystemNamespace,
+		ProviderName: o.ProviderName,
+		ManagedBy:    core.ControllerName,
+		PodSelector:  podSelectorLabels(o.ProviderName),
+		IngressPorts: ports,
+	}
+}
+
+// webhookDNSInstance returns the instance under which the webhook service is exposed by the DNS provider.
+func (o *InitOptions) webhookDNSInstance(dnsConfig pwv1alpha1.DNSConfig, providerSystemNamespace string) *dns.Instance {
+	whServiceName := webhookServiceName(o.ProviderName)
+	return &dns.Instance{
+		Name:              whServiceName,
+		Namespace:         providerSystemNamespace,
+		SubDomainPrefix:   "pwo-webhooks",
+		HostName:          dnsConfig.HostNames[o.Environment],
+		BackendName:       whServiceName,
+		BackendPort:       int32(WebhookPortSvc),
+		BackendSelector:   podSelectorLabels(o.ProviderName),
+		BackendTargetPort: int32(WebhookPortPod),
+	}
+}
+
+// webhookServiceName returns the name of the service in the provider system namespace which exposes the webhooks.
+func webhookServiceName(providerName string) string {
+	suffix := "-webhook"
+	return ctrlutils.ShortenToXCharactersUnsafe(providerName, ctrlutils.K8sMaxNameLength-len(suffix)) + suffix
+}
+
+// webhookInstallOptions returns the options for installing and uninstalling the webhooks, the service which exposes them is managed by the installation.
+// The webhook configurations are managed by the webhookconfig package instead, because the installation does not scope their names to the instance.
+func webhookInstallOptions(providerName, providerSystemNamespace, whSecretName string, onboardingClient client.Client) []webhooks.InstallOption {
+	return []webhooks.InstallOption{
+		webhooks.WithWebhookService{Name: webhookServiceName(providerName), Namespace: providerSystemNamespace},
+		webhooks.WithWebhookSecret{Name: whSecretName, Namespace: providerSystemNamespace},
+		webhooks.WithRemoteClient{Client: onboardingClient},
+		webhooks.WithWebhookServicePort(WebhookPortSvc),
+		webhooks.WithManagedWebhookService{
+			TargetPort:     intstr.FromInt32(WebhookPortPod),
+			SelectorLabels: podSelectorLabels(providerName),
+		},
+	}
+}
+
+// webhookTypes returns the types for which validating and mutating webhooks are installed.
+func webhookTypes() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		pwv1alpha1.GroupVersion.WithKind("Project"),
+		pwv1alpha1.GroupVersion.WithKind("Workspace"),
+		pwv1alpha1.GroupVersion.WithKind("Invitation"),
+	}
+}
+
+// webhookScope returns the scope of the webhook configurations of this instance.
+func (o *SharedOptions) webhookScope() webhookconfig.Scope {
+	return webhookconfig.Scope{ProviderName: o.ProviderName, Environment: o.Environment}
+}
+
+// podSelectorLabels returns the labels of the platform service pods, which are set by the openmcp-operator.
+func podSelectorLabels(providerName string) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/component":  "controller",
+		"app.kubernetes.io/managed-by": "openmcp-operator",
+		"app.kubernetes.io/name":       "PlatformService",
+		"app.kubernetes.io/instance":   providerName,
+	}
+}
+
+func (o *InitOptions) Run(ctx context.Context) error {
+	if err := o.PlatformCluster.InitializeClient(providerscheme.InstallOperatorAPIsPlatform(runtime.NewScheme())); err != nil {
+		return err
+	}
+
+	log := o.Log.WithName("main")
+	ctx = logging.NewContext(ctx, log)
+	log.Info("Environment", "value", o.Environment)
+	log.Info("ProviderName", "value", o.ProviderName)
+
+	log.Info("Getting access to the onboarding cluster")
+	onboardingScheme := runtime.NewScheme()
+	providerscheme.InstallOperatorAPIsOnboarding(onboardingScheme)
+
+	providerSystemNamespace := os.Getenv(openmcpconst.EnvVariablePodNamespace)
+	if providerSystemNamespace == "" {
+		return fmt.Errorf("environment variable %s is not set", openmcpconst.EnvVariablePodNamespace)
+	}
+
+	// the existing resources of the webhook types are labeled for the environment when the webhook configurations are installed
+	onboardingCluster, err := o.onboardingClusterAccess(ctx, log, providerSystemNamespace, clustersv1alpha1.PURPOSE_ONBOARDING+"-init", onboardingScheme, rbacv1.PolicyRule{
+		APIGroups: []string{pwv1alpha1.GroupName},
+		Resources: []string{"projects", "workspaces", "invitations"},
+		Verbs:     []string{"list", "patch"},
+	})
+	if err != nil {
+		return err
 	}
 
 	// apply CRDs
@@ -143,95 +295,92 @@ func (o *InitOptions) Run(ctx context.Context) error {
 		return fmt.Errorf("invalid ProjectWorkspaceConfig '%s': %w", o.ProviderName, err)
 	}
 
-	suffix := "-webhook"
-	whServiceName := ctrlutils.ShortenToXCharactersUnsafe(o.ProviderName, ctrlutils.K8sMaxNameLength-len(suffix)) + suffix
+	log.Info("Ensuring provider system namespace", "namespace", providerSystemNamespace)
+	if err := o.systemNamespaceSetup(providerSystemNamespace, !pwc.Spec.Webhook.Disabled).Ensure(ctx); err != nil {
+		return err
+	}
+
+	whServiceName := webhookServiceName(o.ProviderName)
 	whSecretName, err := libutils.WebhookSecretName(o.ProviderName)
 	if err != nil {
 		return fmt.Errorf("unable to determine webhook secret name: %w", err)
 	}
 
-	var gatewayResult dns.GatewayReconcileResult
+	var endpointResult dns.EndpointReconcileResult
 	if os.Getenv("SKIP_GATEWAY") != "true" {
-		// setup gateway for webhooks
-		dnsInstance := &dns.Instance{
-			Name:            whServiceName,
-			Namespace:       providerSystemNamespace,
-			SubDomainPrefix: "pwo-webhooks",
-			BackendName:     whServiceName,
-			BackendPort:     int32(WebhookPortSvc),
+		// expose webhooks via the configured DNS provider
+		dnsConfig, err := o.dnsConfig(pwc.Spec.Webhook.DNS)
+		if err != nil {
+			return err
 		}
-		dnsReconciler := dns.NewReconciler()
+		dnsProvider, err := dns.NewProvider(dnsConfig)
+		if err != nil {
+			return fmt.Errorf("unable to create DNS provider: %w", err)
+		}
+		dnsInstance := o.webhookDNSInstance(dnsConfig, providerSystemNamespace)
 		timeout := 3 * time.Minute
-		log.Info("Verifying default Gateway is available", "timeout", timeout.String())
+		log.Info("Verifying DNS endpoint is available", "provider", dnsConfig.Provider, "timeout", timeout.String())
 		waitCtx, cancelCtx := context.WithTimeout(ctx, timeout)
 		defer cancelCtx()
 		err = wait.PollUntilContextTimeout(waitCtx, 10*time.Second, timeout, true, func(ctx context.Context) (bool, error) {
-			gatewayResult, err = dnsReconciler.ReconcileGateway(ctx, dnsInstance, o.PlatformCluster)
+			endpointResult, err = dnsProvider.ReconcileEndpoint(ctx, dnsInstance, o.PlatformCluster)
 			if err != nil {
-				log.Error(err, "Error reconciling Gateway, retrying...")
+				log.Error(err, "Error reconciling DNS endpoint, retrying...")
 				return false, nil
 			}
-			if gatewayResult.RequeueAfter > 0 {
-				log.Debug("Default Gateway is not yet available, retrying...")
+			if endpointResult.RequeueAfter > 0 {
+				log.Debug("DNS endpoint is not yet available, retrying...")
 				return false, nil
 			}
 			return true, nil
 		})
 		if err != nil {
-			return fmt.Errorf("default Gateway did not become available within %s: %w", timeout.String(), err)
+			return fmt.Errorf("DNS endpoint did not become available within %s: %w", timeout.String(), err)
 		}
-		log.Info("Default Gateway is available", "hostName", gatewayResult.HostName)
+		log.Info("DNS endpoint is available", "hostName", endpointResult.HostName)
 
-		log.Info("Waiting for TLS route to become ready", "timeout", timeout.String())
+		log.Info("Waiting for route to become ready", "timeout", timeout.String())
 		waitCtx, cancelCtx = context.WithTimeout(ctx, timeout)
 		defer cancelCtx()
 		err = wait.PollUntilContextTimeout(waitCtx, 10*time.Second, timeout, true, func(ctx context.Context) (bool, error) {
-			if err := dnsReconciler.ReconcileTLSRoute(ctx, dnsInstance, o.PlatformCluster); err != nil {
-				log.Error(err, "Error reconciling TLS route, retrying...")
+			if err := dnsProvider.ReconcileRoute(ctx, dnsInstance, o.PlatformCluster); err != nil {
+				log.Error(err, "Error reconciling route, retrying...")
 				return false, nil
 			}
-			tlsReady, err := dnsReconciler.IsTLSRouteReady(ctx, dnsInstance, o.PlatformCluster)
+			routeReady, err := dnsProvider.IsRouteReady(ctx, dnsInstance, o.PlatformCluster)
 			if err != nil {
-				log.Error(err, "Error checking TLS route readiness, retrying...")
+				log.Error(err, "Error checking route readiness, retrying...")
 				return false, nil
 			}
-			if !tlsReady {
-				log.Debug("TLS route is not yet ready, retrying...")
+			if !routeReady {
+				log.Debug("Route is not yet ready, retrying...")
 				return false, nil
 			}
 			return true, nil
 		})
 		if err != nil {
-			return fmt.Errorf("TLS route did not become ready within %s: %w", timeout.String(), err)
+			return fmt.Errorf("route did not become ready within %s: %w", timeout.String(), err)
 		}
-		log.Info("TLS route is ready")
+		log.Info("Route is ready")
 	} else {
 		log.Info("Skipping Gateway setup as per SKIP_GATEWAY environment variable")
 	}
 
-	installOpts := []webhooks.InstallOption{
-		webhooks.WithWebhookService{Name: whServiceName, Namespace: providerSystemNamespace},
-		webhooks.WithWebhookSecret{Name: whSecretName, Namespace: providerSystemNamespace},
-		webhooks.WithRemoteClient{Client: onboardingCluster.Client()},
-		webhooks.WithWebhookServicePort(WebhookPortSvc),
-		webhooks.WithManagedWebhookService{
-			TargetPort: intstr.FromInt32(WebhookPortPod),
-			SelectorLabels: map[string]string{
-				"app.kubernetes.io/component":  "controller",
-				"app.kubernetes.io/managed-by": "openmcp-operator",
-				"app.kubernetes.io/name":       "PlatformService",
-				"app.kubernetes.io/instance":   o.ProviderName,
-			},
-		},
-	}
+	installOpts := webhookInstallOptions(o.ProviderName, providerSystemNamespace, whSecretName, onboardingCluster.Client())
 	certOpts := []webhooks.CertOption{
 		webhooks.WithWebhookService{Name: whServiceName, Namespace: providerSystemNamespace},
 		webhooks.WithWebhookSecret{Name: whSecretName, Namespace: providerSystemNamespace},
 	}
+	webhookConfigs := &webhookconfig.Installer{
+		Scope:       o.webhookScope(),
+		Client:      onboardingCluster.Client(),
+		Service:     types.NamespacedName{Name: whServiceName, Namespace: providerSystemNamespace},
+		ServicePort: WebhookPortSvc,
+	}
 	if o.PlatformCluster.RESTConfig().Host != onboardingCluster.RESTConfig().Host {
 		// create a URL-based webhook otherwise
-		installOpts = append(installOpts, webhooks.WithCustomBaseURL(fmt.Sprintf("https://%s:%d", gatewayResult.HostName, gatewayResult.TLSPort)))
-		certOpts = append(certOpts, webhooks.WithAdditionalDNSNames{gatewayResult.HostName})
+		webhookConfigs.BaseURL = "https://" + net.JoinHostPort(endpointResult.HostName, strconv.Itoa(int(endpointResult.TLSPort)))
+		certOpts = append(certOpts, webhooks.WithAdditionalDNSNames{endpointResult.HostName})
 	}
 
 	// webhook options we might or might not support at a later time
@@ -240,19 +389,6 @@ func (o *InitOptions) Run(ctx context.Context) error {
 		opts = append(opts, webhooks.WithCustomCA{todo})
 	*/
 
-	webhookTypes := []webhooks.APITypes{
-		{
-			Obj:       &pwv1alpha1.Project{},
-			Validator: true,
-			Defaulter: true,
-		},
-		{
-			Obj:       &pwv1alpha1.Workspace{},
-			Validator: true,
-			Defaulter: true,
-		},
-	}
-
 	if !pwc.Spec.Webhook.Disabled {
 		log.Info("Webhooks are enabled, ensuring required resources ...")
 
@@ -261,28 +397,38 @@ func (o *InitOptions) Run(ctx context.Context) error {
 			return fmt.Errorf("unable to generate webhook certificate: %w", err)
 		}
 
-		// Compile-time checks to ensure Project implements the required interfaces
-
-		// Install webhooks
+		// Install the webhook service, the webhook configurations are created below
 		err := webhooks.Install(
 			ctx,
 			o.PlatformCluster.Client(),
 			onboardingScheme,
-			webhookTypes,
+			nil,
 			installOpts...,
 		)
 		if err != nil {
 			return fmt.Errorf("unable to install webhooks: %w", err)
 		}
+
+		secret := &corev1.Secret{}
+		if err := o.PlatformCluster.Client().Get(ctx, client.ObjectKey{Name: whSecretName, Namespace: providerSystemNamespace}, secret); err != nil {
+			return fmt.Errorf("unable to get webhook secret: %w", err)
+		}
+		webhookConfigs.CABundle = secret.Data[corev1.TLSCertKey]
+		if err := webhookConfigs.Install(ctx, webhookTypes()...); err != nil {
+			return fmt.Errorf("unable to install webhook configurations: %w", err)
+		}
 	} else {
 		log.Info("Webhooks are disabled, removing webhook resources if they exist ...")
 
 		// Uninstall webhooks
+		if err := webhookConfigs.Uninstall(ctx, webhookTypes()...); err != nil {
+			return fmt.Errorf("unable to uninstall webhook configurations: %w", err)
+		}
 		err := webhooks.Uninstall(
 			ctx,
 			o.PlatformCluster.Client(),
 			onboardingScheme,
-			webhookTypes,
+			nil,
 			installOpts...,
 		)
 		if err != nil {
@@ -0,0 +1,151 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// OperatorConfigurationKind is the kind of the file which is passed with the '--options-file' flag.
+const OperatorConfigurationKind = "OperatorConfiguration"
+
+// OperatorConfiguration contains the values of the command line flags of all commands in a single file,
+// so that they can be rendered e.g. from the values of a Helm chart instead of being passed as arguments.
+type OperatorConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+	// Options maps the names of the flags, without the leading dashes, to their values.
+	// Lists are joined with commas and maps are converted to comma separated 'key=value' pairs, e.g. for '--feature-gates'.
+	Options map[string]json.RawMessage `json:"options,omitempty"`
+}
+
+// LoadOperatorConfiguration reads the OperatorConfiguration from the given file.
+// Unknown fields are rejected, the kind is optional.
+func LoadOperatorConfiguration(path string) (*OperatorConfiguration, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading options file '%s': %w", path, err)
+	}
+	cfg := &OperatorConfiguration{}
+	if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+		return nil, fmt.Errorf("error parsing options file '%s': %w", path, err)
+	}
+	if cfg.Kind != "" && cfg.Kind != OperatorConfigurationKind {
+		return nil, fmt.Errorf("options file '%s' has kind '%s', expected '%s'", path, cfg.Kind, OperatorConfigurationKind)
+	}
+	return cfg, nil
+}
+
+// ApplyTo sets the flags of the given command to the values of the options.
+// Flags which have been set on the command line take precedence over the file.
+// Options of flags which only exist for other commands are ignored, so that the same file can be used for all commands.
+func (c *OperatorConfiguration) ApplyTo(cmd *cobra.Command) error {
+	names := make([]string, 0, len(c.Options))
+	for name := range c.Options {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil {
+			if !isFlagOfAnyCommand(cmd.Root(), name) {
+				return fmt.Errorf("unknown option '%s'", name)
+			}
+			continue
+		}
+		if flag.Changed {
+			continue
+		}
+		value, err := optionValue(c.Options[name])
+		if err != nil {
+			return fmt.Errorf("invalid value for option '%s': %w", name, err)
+		}
+		if err := cmd.Flags().Set(name, value); err != nil {
+			return fmt.Errorf("invalid value for option '%s': %w", name, err)
+		}
+	}
+	return nil
+}
+
+// optionValue converts the JSON value of an option into the string representation of the flag value.
+func optionValue(raw json.RawMessage) (string, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return "", nil
+	}
+	switch raw[0] {
+	case '"':
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return "", err
+		}
+		return s, nil
+	case '[':
+		var list []json.RawMessage
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return "", err
+		}
+		values := make([]string, 0, len(list))
+		for _, item := range list {
+			value, err := optionValue(item)
+			if err != nil {
+				return "", err
+			}
+			values = append(values, value)
+		}
+		return strings.Join(values, ","), nil
+	case '{':
+		var m map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &m); err != nil {
+			return "", err
+		}
+		pairs := make([]string, 0, len(m))
+		for key, item := range m {
+			value, err := optionValue(item)
+			if err != nil {
+				return "", err
+			}
+			pairs = append(pairs, key+"="+value)
+		}
+		slices.Sort(pairs)
+		return strings.Join(pairs, ","), nil
+	case 'n':
+		return "", fmt.Errorf("null is not allowed, remove the option to use the default")
+	default:
+		// numbers and booleans are passed as written
+		return string(raw), nil
+	}
+}
+
+// isFlagOfAnyCommand returns true if the given command or any of its subcommands has a flag with the given name.
+func isFlagOfAnyCommand(cmd *cobra.Command, name string) bool {
+	if cmd.Flags().Lookup(name) != nil || cmd.PersistentFlags().Lookup(name) != nil {
+		return true
+	}
+	return slices.ContainsFunc(cmd.Commands(), func(sub *cobra.Command) bool {
+		return isFlagOfAnyCommand(sub, name)
+	})
+}
+
+// addOptionsFileFlag adds the '--options-file' flag and applies the file before any command is run.
+func addOptionsFileFlag(cmd *cobra.Command, path *string) {
+	cmd.PersistentFlags().StringVar(path, "options-file", "", "Path of a file containing an OperatorConfiguration, whose 'options' map flag names to values. Flags which are set on the command line take precedence over the file.")
+	cmd.PersistentPreRunE = func(cmd *cobra.Command, _ []string) error {
+		if *path == "" {
+			return nil
+		}
+		cfg, err := LoadOperatorConfiguration(*path)
+		if err != nil {
+			return err
+		}
+		return cfg.ApplyTo(cmd)
+	}
+}
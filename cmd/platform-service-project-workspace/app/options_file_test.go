@@ -0,0 +1,168 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/utils/ptr"
+)
+
+func TestLoadOperatorConfiguration(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		content         *string
+		expectedOptions map[string]json.RawMessage
+		expectError     bool
+	}{
+		{
+			desc: "should load the options",
+			content: ptr.To(`apiVersion: openmcp.cloud/v1alpha1
+kind: OperatorConfiguration
+options:
+  environment: dev
+  verbosity: 2
+`),
+			expectedOptions: map[string]json.RawMessage{
+				"environment": json.RawMessage(`"dev"`),
+				"verbosity":   json.RawMessage(`2`),
+			},
+		},
+		{
+			desc:            "should accept a file without kind",
+			content:         ptr.To("options:\n  environment: dev\n"),
+			expectedOptions: map[string]json.RawMessage{"environment": json.RawMessage(`"dev"`)},
+		},
+		{
+			desc:        "should reject unknown fields",
+			content:     ptr.To("option:\n  environment: dev\n"),
+			expectError: true,
+		},
+		{
+			desc:        "should reject another kind",
+			content:     ptr.To("kind: ConfigMap\noptions:\n  environment: dev\n"),
+			expectError: true,
+		},
+		{
+			desc:        "should reject invalid yaml",
+			content:     ptr.To("options: [environment\n"),
+			expectError: true,
+		},
+		{
+			desc:        "should fail for a missing file",
+			expectError: true,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "options.yaml")
+			if tC.content != nil {
+				require.NoError(t, os.WriteFile(path, []byte(*tC.content), 0o600))
+			}
+
+			cfg, err := LoadOperatorConfiguration(path)
+			if tC.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, cfg.Options, len(tC.expectedOptions))
+			for name, expected := range tC.expectedOptions {
+				assert.JSONEq(t, string(expected), string(cfg.Options[name]), name)
+			}
+		})
+	}
+}
+
+func TestOperatorConfigurationApplyTo(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		args           []string
+		options        map[string]string
+		expectedValues map[string]string
+		expectError    bool
+	}{
+		{
+			desc:           "should keep the defaults without options",
+			expectedValues: map[string]string{"environment": "default", "verbosity": "0", "feature-gates": "", "watch-namespaces": "[]"},
+		},
+		{
+			desc:           "should override the defaults with the options",
+			options:        map[string]string{"environment": `"dev"`, "verbosity": `2`},
+			expectedValues: map[string]string{"environment": "dev", "verbosity": "2"},
+		},
+		{
+			desc:           "should prefer flags set on the command line",
+			args:           []string{"--environment", "prod"},
+			options:        map[string]string{"environment": `"dev"`, "verbosity": `2`},
+			expectedValues: map[string]string{"environment": "prod", "verbosity": "2"},
+		},
+		{
+			desc:    "should join lists and maps",
+			options: map[string]string{"watch-namespaces": `["a", "b"]`, "feature-gates": `{"B": false, "A": true}`},
+			expectedValues: map[string]string{
+				"watch-namespaces": "[a,b]",
+				"feature-gates":    "A=true,B=false",
+			},
+		},
+		{
+			desc:           "should ignore options of other commands",
+			options:        map[string]string{"environment": `"dev"`, "output": `"yaml"`},
+			expectedValues: map[string]string{"environment": "dev"},
+		},
+		{
+			desc:        "should reject unknown options",
+			options:     map[string]string{"unknown": `"dev"`},
+			expectError: true,
+		},
+		{
+			desc:        "should reject null values",
+			options:     map[string]string{"environment": `null`},
+			expectError: true,
+		},
+		{
+			desc:        "should reject invalid values",
+			options:     map[string]string{"verbosity": `"high"`},
+			expectError: true,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			cmd := newTestCommand()
+			require.NoError(t, cmd.ParseFlags(tC.args))
+			cfg := &OperatorConfiguration{Options: map[string]json.RawMessage{}}
+			for name, value := range tC.options {
+				cfg.Options[name] = json.RawMessage(value)
+			}
+
+			err := cfg.ApplyTo(cmd)
+			if tC.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			for name, expected := range tC.expectedValues {
+				assert.Equal(t, expected, cmd.Flags().Lookup(name).Value.String(), name)
+			}
+		})
+	}
+}
+
+// newTestCommand returns the 'run' subcommand of a root command, which has another subcommand with different flags.
+func newTestCommand() *cobra.Command {
+	root := &cobra.Command{Use: "root"}
+	run := &cobra.Command{Use: "run"}
+	run.Flags().String("environment", "default", "")
+	run.Flags().Int("verbosity", 0, "")
+	run.Flags().StringSlice("watch-namespaces", nil, "")
+	run.Flags().String("feature-gates", "", "")
+	printCmd := &cobra.Command{Use: "print"}
+	printCmd.Flags().String("output", "json", "")
+	root.AddCommand(run, printCmd)
+	return run
+}
@@ -31,7 +31,14 @@ func (o *SharedOptions) PrintCompleted(cmd *cobra.Command) {
 	cmd.Print(string(data))
 }
 
-func (o *InitOptions) PrintRaw(cmd *cobra.Command) {}
+func (o *InitOptions) PrintRaw(cmd *cobra.Command) {
+	data, err := yaml.Marshal(o.RawInitOptions)
+	if err != nil {
+		cmd.Println(fmt.Errorf("error marshalling raw options: %w", err).Error())
+		return
+	}
+	cmd.Print(string(data))
+}
 
 func (o *InitOptions) PrintRawOptions(cmd *cobra.Command) {
 	cmd.Println("########## RAW OPTIONS START ##########")
@@ -6,18 +6,20 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
-	authenticationv1 "k8s.io/api/authentication/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
-	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
@@ -25,11 +27,21 @@ import (
 	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
 	openmcpconst "github.com/openmcp-project/openmcp-operator/api/constants"
 	"github.com/openmcp-project/openmcp-operator/lib/clusteraccess"
+	libutils "github.com/openmcp-project/openmcp-operator/lib/utils"
 
 	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/api/v2/crds"
 	providerscheme "github.com/openmcp-project/platform-service-project-workspace/api/v2/install"
 	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
 	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/core"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/eventsink"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/health"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/inventory"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/webhookcert"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/crdcheck"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/features"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/metrics"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/shutdown"
 	pwwebhooks "github.com/openmcp-project/platform-service-project-workspace/internal/webhooks"
 )
 
@@ -64,18 +76,32 @@ func NewRunCommand(so *SharedOptions) *cobra.Command {
 
 type RawRunOptions struct {
 	// kubebuilder default flags
-	MetricsAddr          string `json:"metrics-bind-address"`
-	MetricsCertPath      string `json:"metrics-cert-path"`
-	MetricsCertName      string `json:"metrics-cert-name"`
-	MetricsCertKey       string `json:"metrics-cert-key"`
-	WebhookCertPath      string `json:"webhook-cert-path"`
-	WebhookCertName      string `json:"webhook-cert-name"`
-	WebhookCertKey       string `json:"webhook-cert-key"`
-	EnableLeaderElection bool   `json:"leader-elect"`
-	ProbeAddr            string `json:"health-probe-bind-address"`
-	PprofAddr            string `json:"pprof-bind-address"`
-	SecureMetrics        bool   `json:"metrics-secure"`
-	EnableHTTP2          bool   `json:"enable-http2"`
+	MetricsAddr          string        `json:"metrics-bind-address"`
+	MetricsCertPath      string        `json:"metrics-cert-path"`
+	MetricsCertName      string        `json:"metrics-cert-name"`
+	MetricsCertKey       string        `json:"metrics-cert-key"`
+	WebhookCertPath      string        `json:"webhook-cert-path"`
+	WebhookCertName      string        `json:"webhook-cert-name"`
+	WebhookCertKey       string        `json:"webhook-cert-key"`
+	WebhookCertTimeout   time.Duration `json:"webhook-cert-timeout"`
+	EnableLeaderElection bool          `json:"leader-elect"`
+	ProbeAddr            string        `json:"health-probe-bind-address"`
+	PprofAddr            string        `json:"pprof-bind-address"`
+	SecureMetrics        bool          `json:"metrics-secure"`
+	MetricsAuth          string        `json:"metrics-auth"`
+	MetricsTokenFile     string        `json:"metrics-token-file"`
+	EnableHTTP2          bool          `json:"enable-http2"`
+
+	ResyncInterval          time.Duration `json:"resync-interval"`
+	OwnershipSweepInterval  time.Duration `json:"ownership-sweep-interval"`
+	AccessCoolDown          time.Duration `json:"access-cool-down"`
+	ShutdownDelay           time.Duration `json:"shutdown-delay"`
+	GracefulShutdownTimeout time.Duration `json:"graceful-shutdown-timeout"`
+	ConfigFallbackPath      string        `json:"config-fallback-path"`
+	CRDSkewPolicy           string        `json:"crd-skew-policy"`
+
+	OwnIdentityPrefix          string        `json:"own-identity-prefix"`
+	OwnIdentityRefreshInterval time.Duration `json:"own-identity-refresh-interval"`
 }
 
 type RunOptions struct {
@@ -97,14 +123,27 @@ func (o *RunOptions) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&o.PprofAddr, "pprof-bind-address", "", "The address the pprof endpoint binds to. Expected format is ':<port>'. Leave empty to disable pprof endpoint.")
 	cmd.Flags().BoolVar(&o.EnableLeaderElection, "leader-elect", false, "Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager.")
 	cmd.Flags().BoolVar(&o.SecureMetrics, "metrics-secure", true, "If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
+	cmd.Flags().StringVar(&o.MetricsAuth, "metrics-auth", string(metrics.AuthModeOnboarding), "Determines how requests to the secure metrics endpoint are authenticated and authorized. 'onboarding' uses TokenReviews and SubjectAccessReviews on the onboarding cluster, 'platform' uses them on the platform cluster, so that the platform Prometheus does not need permissions on the onboarding cluster, and 'token' only accepts the bearer tokens from --metrics-token-file.")
+	cmd.Flags().StringVar(&o.MetricsTokenFile, "metrics-token-file", "", "Path of a file with the bearer tokens which are allowed to access the metrics endpoint, one per line. Required for --metrics-auth=token. The file is re-read for each request.")
 	cmd.Flags().StringVar(&o.WebhookCertPath, "webhook-cert-path", "", "The directory that contains the webhook certificate.")
 	cmd.Flags().StringVar(&o.WebhookCertName, "webhook-cert-name", "tls.crt", "The name of the webhook certificate file.")
 	cmd.Flags().StringVar(&o.WebhookCertKey, "webhook-cert-key", "tls.key", "The name of the webhook key file.")
+	cmd.Flags().DurationVar(&o.WebhookCertTimeout, "webhook-cert-timeout", 5*time.Minute, "The maximum time to wait at startup until the webhook certificate files in --webhook-cert-path are available.")
 	cmd.Flags().StringVar(&o.MetricsCertPath, "metrics-cert-path", "", "The directory that contains the metrics server certificate.")
 	cmd.Flags().StringVar(&o.MetricsCertName, "metrics-cert-name", "tls.crt", "The name of the metrics server certificate file.")
 	cmd.Flags().StringVar(&o.MetricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	cmd.Flags().BoolVar(&o.EnableHTTP2, "enable-http2", false, "If set, HTTP/2 will be enabled for the metrics and webhook servers")
 
+	cmd.Flags().DurationVar(&o.ResyncInterval, "resync-interval", sharedconfig.DefaultResyncInterval, "The interval in which all Projects and Workspaces are reconciled, even if neither they nor the config have changed, to heal drift of the created resources. A random jitter of up to 10% is added. Set to 0 to disable the periodic resync.")
+	cmd.Flags().DurationVar(&o.AccessCoolDown, "access-cool-down", sharedconfig.DefaultAccessCoolDown, "The time for which the AccessRequest for the dynamic onboarding cluster access is not reconciled after it failed repeatedly, e.g. because the cluster provider is degraded. The static onboarding cluster access keeps being used in the meantime.")
+	cmd.Flags().DurationVar(&o.OwnershipSweepInterval, "ownership-sweep-interval", core.DefaultOwnershipSweepInterval, "The interval in which managed resources labeled with the UID of a Project or Workspace which does not exist anymore are deleted. Set to 0 to disable the periodic cleanup of these orphaned resources.")
+	cmd.Flags().DurationVar(&o.ShutdownDelay, "shutdown-delay", 5*time.Second, "The time between a termination signal and the stop of the controllers, during which the readiness probe fails while webhook requests are still served, so that no new requests are routed to the replica.")
+	cmd.Flags().DurationVar(&o.GracefulShutdownTimeout, "graceful-shutdown-timeout", 30*time.Second, "The time in-flight reconciliations and event deliveries get to complete once the controllers are stopped. The leader election lease is released afterwards.")
+	cmd.Flags().StringVar(&o.ConfigFallbackPath, "config-fallback-path", "", "Path of a file containing a ProjectWorkspaceConfig or its spec, which is used as long as the ProjectWorkspaceConfig resource does not exist, e.g. during the bootstrap of air-gapped landscapes. The platform service switches to the resource as soon as it is created.")
+	cmd.Flags().StringVar(&o.CRDSkewPolicy, "crd-skew-policy", string(crdcheck.SkewPolicyFail), "Determines what happens at startup if the installed CRDs differ in storage version or schema from the ones this version has been built with, e.g. after a partial upgrade. 'fail' refuses to start, 'warn' only logs the differences.")
+	cmd.Flags().StringVar(&o.OwnIdentityPrefix, "own-identity-prefix", "", "Username prefix of the ServiceAccounts which are treated as the platform service's own identity in addition to the one determined at startup, in the format 'system:serviceaccount:<namespace>:<name prefix>'. This keeps the webhooks from rejecting the platform service's requests after the access to the onboarding cluster has been re-issued with another ServiceAccount.")
+	cmd.Flags().DurationVar(&o.OwnIdentityRefreshInterval, "own-identity-refresh-interval", pwwebhooks.DefaultOwnIdentityRefreshInterval, "The interval in which the platform service's own identity on the onboarding cluster is determined again, in addition to after authentication errors. Set to 0 to disable the periodic refresh.")
+	cmd.Flags().Var(features.DefaultGate, "feature-gates", "A set of key=value pairs that describe feature gates for experimental features. Options are:\n"+strings.Join(features.DefaultGate.KnownFeatures(), "\n"))
 	cmd.Flags().BoolVar(&sharedconfig.SupportV1, "v1", false, "If set, the permissions for v1 resources (ManagedControlPlane, ClusterAdmin) are granted to users within workspace namespaces and they can also block workspace deletion.")
 }
 
@@ -133,17 +172,28 @@ func (o *RunOptions) Complete(ctx context.Context) error {
 		o.TLSOpts = append(o.TLSOpts, disableHTTP2)
 	}
 
+	if err := crdcheck.SkewPolicy(o.CRDSkewPolicy).Validate(); err != nil {
+		return err
+	}
+	if err := pwwebhooks.ValidateOwnIdentityPrefix(o.OwnIdentityPrefix); err != nil {
+		return err
+	}
+
 	// Initial webhook TLS options
 	o.WebhookTLSOpts = o.TLSOpts
 
 	if len(o.WebhookCertPath) > 0 {
 		setupLog.Info("Initializing webhook certificate watcher using provided certificates", "webhook-cert-path", o.WebhookCertPath, "webhook-cert-name", o.WebhookCertName, "webhook-cert-key", o.WebhookCertKey)
 
+		certFile := filepath.Join(o.WebhookCertPath, o.WebhookCertName)
+		keyFile := filepath.Join(o.WebhookCertPath, o.WebhookCertKey)
+		// mounted certificates might not be available yet, the webhook server would fail every request without them
+		if err := health.WaitForCertificateFiles(ctx, certFile, keyFile, 5*time.Second, o.WebhookCertTimeout); err != nil {
+			return fmt.Errorf("webhook certificate is not available: %w", err)
+		}
+
 		var err error
-		o.WebhookCertWatcher, err = certwatcher.New(
-			filepath.Join(o.WebhookCertPath, o.WebhookCertName),
-			filepath.Join(o.WebhookCertPath, o.WebhookCertKey),
-		)
+		o.WebhookCertWatcher, err = certwatcher.New(certFile, keyFile)
 		if err != nil {
 			return fmt.Errorf("failed to initialize webhook certificate watcher: %w", err)
 		}
@@ -167,7 +217,13 @@ func (o *RunOptions) Complete(ctx context.Context) error {
 		// These configurations ensure that only authorized users and service accounts
 		// can access the metrics endpoint. The RBAC are configured in 'config/rbac/kustomization.yaml'. More info:
 		// https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.20.2/pkg/metrics/filters#WithAuthenticationAndAuthorization
-		o.MetricsServerOptions.FilterProvider = filters.WithAuthenticationAndAuthorization
+		// The manager runs against the onboarding cluster, so by default the reviews are created there. Alternatively,
+		// the platform cluster or a static token allowlist can be used, see the '--metrics-auth' flag.
+		filterProvider, err := metrics.AuthFilterProvider(metrics.AuthMode(o.MetricsAuth), o.PlatformCluster.RESTConfig(), o.MetricsTokenFile)
+		if err != nil {
+			return fmt.Errorf("invalid metrics auth configuration: %w", err)
+		}
+		o.MetricsServerOptions.FilterProvider = filterProvider
 	}
 
 	// If the certificate is not specified, controller-runtime will automatically
@@ -207,6 +263,7 @@ func (o *RunOptions) Run(ctx context.Context) error {
 	setupLog = o.Log.WithName("setup")
 	setupLog.Info("Environment", "value", o.Environment)
 	setupLog.Info("ProviderName", "value", o.ProviderName)
+	setupLog.Info("FeatureGates", "value", features.DefaultGate.String())
 
 	setupLog.Info("Determining pod namespace")
 	podNamespace := os.Getenv(openmcpconst.EnvVariablePodNamespace)
@@ -218,12 +275,22 @@ func (o *RunOptions) Run(ctx context.Context) error {
 	setupLog.Info("Fetching ProjectWorkspaceConfig")
 	pwc := &pwv1alpha1.ProjectWorkspaceConfig{}
 	if err := o.PlatformCluster.Client().Get(ctx, client.ObjectKey{Name: o.ProviderName}, pwc); err != nil {
-		return fmt.Errorf("unable to get ProjectWorkspaceConfig '%s': %w", o.ProviderName, err)
+		if !apierrors.IsNotFound(err) || o.ConfigFallbackPath == "" {
+			return fmt.Errorf("unable to get ProjectWorkspaceConfig '%s': %w", o.ProviderName, err)
+		}
+		setupLog.Info("ProjectWorkspaceConfig does not exist, using fallback config", "path", o.ConfigFallbackPath)
+		pwc, err = sharedconfig.LoadFallbackConfig(o.ConfigFallbackPath, o.ProviderName)
+		if err != nil {
+			return err
+		}
 	}
 	pwc.SetDefaults()
 	if err := pwc.Validate(); err != nil {
 		return fmt.Errorf("invalid ProjectWorkspaceConfig '%s': %w", o.ProviderName, err)
 	}
+	if err := o.LogLevels.Apply(pwc.Spec.Logging); err != nil {
+		return fmt.Errorf("unable to apply log levels from ProjectWorkspaceConfig '%s': %w", o.ProviderName, err)
+	}
 
 	setupLog.Info("Getting access to the onboarding cluster")
 	onboardingScheme := providerscheme.InstallOperatorAPIsOnboarding(runtime.NewScheme())
@@ -246,21 +313,76 @@ func (o *RunOptions) Run(ctx context.Context) error {
 					Resources: []string{"projects", "projects/status", "workspaces", "workspaces/status"},
 					Verbs:     []string{"*"},
 				},
+				{
+					APIGroups: []string{pwv1alpha1.GroupName},
+					Resources: []string{"workspaceclasses", "groupsnapshots"},
+					Verbs:     []string{"get", "list", "watch"},
+				},
+				{
+					APIGroups: []string{pwv1alpha1.GroupName},
+					Resources: []string{"accessreviews", "accessreviews/status", "invitations", "invitations/status"},
+					Verbs:     []string{"get", "list", "watch", "update", "patch", "delete"},
+				},
+				{
+					APIGroups: []string{pwv1alpha1.GroupName},
+					Resources: []string{"projectquotas", "projectquotas/status", "projectmembershipsources", "projectmembershipsources/status", "projectmembershipsources/finalizers"},
+					Verbs:     []string{"get", "list", "watch", "update", "patch"},
+				},
 				{
 					APIGroups: []string{""},
-					Resources: []string{"namespaces"},
+					Resources: []string{"namespaces", "resourcequotas"},
 					Verbs:     []string{"*"},
 				},
+				{
+					APIGroups: []string{""},
+					Resources: []string{"serviceaccounts", "secrets"},
+					Verbs:     []string{"get", "list", "watch", "create", "update", "patch", "delete"},
+				},
+				{
+					APIGroups: []string{""},
+					Resources: []string{"configmaps"},
+					Verbs:     []string{"get", "list", "watch"},
+				},
+				{
+					APIGroups: []string{""},
+					Resources: []string{"serviceaccounts/token"},
+					Verbs:     []string{"create"},
+				},
+				{
+					APIGroups: []string{"networking.k8s.io"},
+					Resources: []string{"networkpolicies"},
+					Verbs:     []string{"get", "list", "watch", "create", "update", "patch", "delete"},
+				},
+				{
+					APIGroups: []string{"events.k8s.io"},
+					Resources: []string{"events"},
+					Verbs:     []string{"create", "patch"},
+				},
 				{
 					APIGroups: []string{"rbac.authorization.k8s.io"},
-					Resources: []string{"clusterroles", "clusterrolebindings", "rolebindings"},
+					Resources: []string{"clusterroles", "clusterrolebindings", "roles", "rolebindings"},
 					Verbs:     []string{"*"},
 				},
+				{
+					APIGroups: []string{"authorization.k8s.io"},
+					Resources: []string{"subjectaccessreviews"},
+					Verbs:     []string{"create"},
+				},
 				{
 					APIGroups: []string{"authentication.k8s.io/v1"},
 					Resources: []string{"selfsubjectreviews"},
 					Verbs:     []string{"*"},
 				},
+				{
+					APIGroups: []string{"apiextensions.k8s.io"},
+					Resources: []string{"customresourcedefinitions"},
+					Verbs:     []string{"get"},
+				},
+				{
+					APIGroups: []string{"admissionregistration.k8s.io"},
+					Resources: []string{"mutatingwebhookconfigurations", "validatingwebhookconfigurations"},
+					Verbs:     []string{"get", "list", "watch", "update", "patch"},
+				},
 			},
 		},
 	}
@@ -268,39 +390,48 @@ func (o *RunOptions) Run(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("error creating/updating onboarding cluster: %w", err)
 	}
+	onboardingCluster, err = o.OnboardingConnection.ApplyToCluster(onboardingCluster)
+	if err != nil {
+		return fmt.Errorf("error configuring onboarding cluster connection: %w", err)
+	}
+
+	setupLog.Info("Verifying installed CRDs")
+	if err := o.verifyCRDs(ctx, onboardingCluster.Client()); err != nil {
+		if crdcheck.SkewPolicy(o.CRDSkewPolicy) == crdcheck.SkewPolicyFail {
+			return err
+		}
+		setupLog.Error(err, "Installed CRDs do not match, continuing because of the CRD skew policy", "policy", o.CRDSkewPolicy)
+	}
 
 	// figure out own identity
-	review := &authenticationv1.SelfSubjectReview{}
-	if err := onboardingCluster.Client().Create(ctx, review); err != nil {
-		return fmt.Errorf("failed to get own identity: %w", err)
+	identity, err := pwwebhooks.NewOwnIdentity(ctx, onboardingCluster.Client(), o.OwnIdentityPrefix)
+	if err != nil {
+		return err
 	}
-	identity := review.Status.UserInfo.Username
-	setupLog.Info("Determined own identity to exclude from webhook validation", "identity", identity)
+	identity.RefreshInterval = o.OwnIdentityRefreshInterval
+	setupLog.Info("Determined own identity to exclude from webhook validation", "identity", identity.Username(), "prefix", o.OwnIdentityPrefix)
+	// the identity is determined again if the credentials of the manager are rejected, since they might have been rotated
+	mgrConfig := rest.CopyConfig(onboardingCluster.RESTConfig())
+	mgrConfig.Wrap(identity.WrapTransport)
 
 	webhookServer := webhook.NewServer(webhook.Options{
 		TLSOpts: o.WebhookTLSOpts,
 		Port:    WebhookPortPod,
 	})
 
-	mgr, err := ctrl.NewManager(onboardingCluster.RESTConfig(), ctrl.Options{
+	mgr, err := ctrl.NewManager(mgrConfig, ctrl.Options{
 		Scheme:                 onboardingScheme,
+		Cache:                  cache.Options{ByObject: core.CacheByObject()},
 		Metrics:                o.MetricsServerOptions,
 		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: o.ProbeAddr,
 		PprofBindAddress:       o.PprofAddr,
 		LeaderElection:         o.EnableLeaderElection,
 		LeaderElectionID:       "github.com/openmcp-project/platform-service-project-workspace",
-		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
-		// when the Manager ends. This requires the binary to immediately end when the
-		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
-		// speeds up voluntary leader transitions as the new leader don't have to wait
-		// LeaseDuration time first.
-		//
-		// In the default scaffold provided, the program ends immediately after
-		// the manager stops, so would be fine to enable this option. However,
-		// if you are doing or is intended to do any operation such as perform cleanups
-		// after the manager stops then its usage might be unsafe.
-		// LeaderElectionReleaseOnCancel: true,
+		// The lease is released only after all runnables have been stopped or the graceful shutdown timeout has passed,
+		// and the program ends right after the manager stops, so the new leader does not have to wait for the lease to expire.
+		LeaderElectionReleaseOnCancel: true,
+		GracefulShutdownTimeout:       &o.GracefulShutdownTimeout,
 	})
 	if err != nil {
 		return fmt.Errorf("unable to create manager: %w", err)
@@ -308,6 +439,9 @@ func (o *RunOptions) Run(ctx context.Context) error {
 	if err := mgr.Add(o.PlatformCluster.Cluster()); err != nil {
 		return fmt.Errorf("unable to add platform cluster to manager: %w", err)
 	}
+	if err := identity.SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("unable to add own identity refresh to manager: %w", err)
+	}
 
 	cr, err := clusterAccessManager.ClusterRequest(ctx, clustersv1alpha1.PURPOSE_ONBOARDING)
 	if err != nil {
@@ -318,25 +452,45 @@ func (o *RunOptions) Run(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("unable to create ProjectWorkspaceConfig controller: %w", err)
 	}
+	cfgCtrl.Environment = o.Environment
+	cfgCtrl.LogLevels = o.LogLevels
+	cfgCtrl.ResyncInterval = o.ResyncInterval
+	cfgCtrl.AccessCircuitBreaker.CoolDown = o.AccessCoolDown
+	cfgCtrl.FallbackConfigPath = o.ConfigFallbackPath
+	cfgCtrl.OnboardingConnection = o.OnboardingConnection
 	if err := cfgCtrl.SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("unable to add ProjectWorkspaceConfig controller to manager: %w", err)
 	}
 
+	if err := sharedconfig.NewMemberOverridePruner(o.ProviderName, o.PlatformCluster, mgr.GetClient()).SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("unable to add member override pruning controller to manager: %w", err)
+	}
+
 	if !pwc.Spec.Webhook.Disabled {
-		if err = pwwebhooks.SetupProjectWebhookWithManager(ctx, mgr, identity, cfgCtrl); err != nil {
+		// the config controller only runs on the leading replica, the cache of the platform cluster is started on every replica
+		overrides, err := pwwebhooks.NewMemberOverridesCache(ctx, o.PlatformCluster.Cluster().GetCache(), o.ProviderName, o.Environment, podNamespace)
+		if err != nil {
+			return fmt.Errorf("unable to create member overrides cache: %w", err)
+		}
+		if err = pwwebhooks.SetupProjectWebhookWithManager(ctx, mgr, identity, cfgCtrl, overrides, o.webhookScope()); err != nil {
 			return fmt.Errorf("unable to setup Project webhook: %w", err)
 		}
-		if err = pwwebhooks.SetupWorkspaceWebhookWithManager(ctx, mgr, identity, cfgCtrl); err != nil {
+		if err = pwwebhooks.SetupWorkspaceWebhookWithManager(ctx, mgr, identity, cfgCtrl, overrides, o.webhookScope()); err != nil {
 			return fmt.Errorf("unable to setup Workspace webhook: %w", err)
 		}
+		if err = pwwebhooks.SetupInvitationWebhookWithManager(ctx, mgr, identity, cfgCtrl, overrides, o.webhookScope()); err != nil {
+			return fmt.Errorf("unable to setup Invitation webhook: %w", err)
+		}
 	}
 
 	commonReconciler := core.NewCommonReconciler(cfgCtrl, o.ProviderName)
+	commonReconciler.ShutdownGracePeriod = o.GracefulShutdownTimeout
 
 	pr, err := core.NewProjectReconciler(mgr.GetScheme(), commonReconciler)
 	if err != nil {
 		return fmt.Errorf("unable to create Project reconciler: %w", err)
 	}
+	pr.ConfigChanges = cfgCtrl.ProjectEvents()
 	if err := pr.SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("unable to add Project controller to manager: %w", err)
 	}
@@ -345,10 +499,98 @@ func (o *RunOptions) Run(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("unable to create Workspace reconciler: %w", err)
 	}
+	wr.ConfigChanges = cfgCtrl.WorkspaceEvents()
 	if err := wr.SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("unable to add Workspace controller to manager: %w", err)
 	}
 
+	arr, err := core.NewAccessReviewReconciler(commonReconciler)
+	if err != nil {
+		return fmt.Errorf("unable to create AccessReview reconciler: %w", err)
+	}
+	if err := arr.SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("unable to add AccessReview controller to manager: %w", err)
+	}
+
+	pqr, err := core.NewProjectQuotaReconciler(commonReconciler)
+	if err != nil {
+		return fmt.Errorf("unable to create ProjectQuota reconciler: %w", err)
+	}
+	if err := pqr.SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("unable to add ProjectQuota controller to manager: %w", err)
+	}
+
+	ir, err := core.NewInvitationReconciler(commonReconciler)
+	if err != nil {
+		return fmt.Errorf("unable to create Invitation reconciler: %w", err)
+	}
+	if err := ir.SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("unable to add Invitation controller to manager: %w", err)
+	}
+
+	pmsr, err := core.NewProjectMembershipSourceReconciler(commonReconciler)
+	if err != nil {
+		return fmt.Errorf("unable to create ProjectMembershipSource reconciler: %w", err)
+	}
+	if err := pmsr.SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("unable to add ProjectMembershipSource controller to manager: %w", err)
+	}
+
+	if o.OwnershipSweepInterval > 0 {
+		sweeper := core.NewOwnershipSweeper(commonReconciler)
+		sweeper.Interval = o.OwnershipSweepInterval
+		if err := sweeper.SetupWithManager(mgr); err != nil {
+			return fmt.Errorf("unable to add ownership sweeper to manager: %w", err)
+		}
+	}
+
+	if pwc.Spec.EventSink != nil {
+		esc, err := eventsink.NewEventSinkController(*pwc.Spec.EventSink, o.PlatformCluster, podNamespace)
+		if err != nil {
+			return fmt.Errorf("unable to create event sink controller: %w", err)
+		}
+		esc.ShutdownGracePeriod = o.GracefulShutdownTimeout
+		if err := esc.SetupWithManager(mgr); err != nil {
+			return fmt.Errorf("unable to add event sink controller to manager: %w", err)
+		}
+	}
+
+	controllerNames := []string{sharedconfig.ReconcilerName, core.ProjectControllerName, core.ProjectDeletionControllerName, core.WorkspaceControllerName, core.WorkspaceDeletionControllerName, core.AccessReviewControllerName, core.ProjectQuotaControllerName, core.InvitationControllerName, core.MembershipSourceControllerName, sharedconfig.MemberOverridePruningControllerName}
+	if pwc.Spec.Inventory != nil {
+		if err := inventory.NewInventoryController(*pwc.Spec.Inventory, o.ProviderName, o.PlatformCluster, mgr.GetClient(), cfgCtrl).SetupWithManager(mgr); err != nil {
+			return fmt.Errorf("unable to add inventory controller to manager: %w", err)
+		}
+		controllerNames = append(controllerNames, inventory.ControllerName)
+	}
+
+	hc := health.NewHealthController(o.ProviderName, o.PlatformCluster, podNamespace, controllerNames...)
+	if !pwc.Spec.Webhook.Disabled {
+		if o.WebhookCertWatcher != nil {
+			webhookCertificate := health.TLSCertificate(o.WebhookCertWatcher.GetCertificate)
+			hc.WithWebhookCertificate(filepath.Join(o.WebhookCertPath, o.WebhookCertName), webhookCertificate)
+			// each replica is kept unready while it cannot serve a valid certificate
+			if err := mgr.AddReadyzCheck(health.WebhookCertificateReadyzCheckName, health.CertificateReadyzCheck(webhookCertificate)); err != nil {
+				return fmt.Errorf("unable to set up webhook certificate ready check: %w", err)
+			}
+		} else {
+			whSecretName, err := libutils.WebhookSecretName(o.ProviderName)
+			if err != nil {
+				return fmt.Errorf("unable to determine webhook secret name: %w", err)
+			}
+			whSecretKey := client.ObjectKey{Name: whSecretName, Namespace: podNamespace}
+			hc.WithWebhookCertificate(fmt.Sprintf("secret %s", whSecretKey.String()), health.SecretCertificate(o.PlatformCluster.Client(), whSecretKey))
+
+			// the certificate in the secret is generated by the init command, but rotated at runtime
+			rotator := webhookcert.NewCertRotator(o.PlatformCluster, onboardingCluster, whSecretKey, o.webhookScope(), webhookTypes()...)
+			if err := rotator.SetupWithManager(mgr); err != nil {
+				return fmt.Errorf("unable to add webhook certificate rotation controller to manager: %w", err)
+			}
+		}
+	}
+	if err := hc.SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("unable to add PWOHealth controller to manager: %w", err)
+	}
+
 	if o.MetricsCertWatcher != nil {
 		setupLog.Info("Adding metrics certificate watcher to manager")
 		if err := mgr.Add(o.MetricsCertWatcher); err != nil {
@@ -369,11 +611,36 @@ func (o *RunOptions) Run(ctx context.Context) error {
 	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
 		return fmt.Errorf("unable to set up ready check: %w", err)
 	}
+	shutdownSequence := shutdown.NewSequence(o.ShutdownDelay, setupLog)
+	if err := mgr.AddReadyzCheck("shutdown", shutdownSequence.ReadyzCheck); err != nil {
+		return fmt.Errorf("unable to set up shutdown ready check: %w", err)
+	}
+	if !pwc.Spec.Webhook.Disabled {
+		// the webhook server is only started once its certificate could be loaded
+		if err := mgr.AddReadyzCheck("webhook", webhookServer.StartedChecker()); err != nil {
+			return fmt.Errorf("unable to set up webhook ready check: %w", err)
+		}
+	}
 
 	setupLog.Info("Starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(shutdownSequence.Context(ctrl.SetupSignalHandler())); err != nil {
 		return fmt.Errorf("problem running manager: %w", err)
 	}
 
 	return nil
 }
+
+// verifyCRDs compares the CRDs installed on the platform and onboarding cluster with the ones embedded in the binary.
+func (o *RunOptions) verifyCRDs(ctx context.Context, onboardingClient client.Client) error {
+	expected, err := crds.CRDs()
+	if err != nil {
+		return fmt.Errorf("failed to load embedded CRDs: %w", err)
+	}
+	if err := crdcheck.Verify(ctx, o.PlatformCluster.Client(), crdcheck.ForCluster(expected, clustersv1alpha1.PURPOSE_PLATFORM)); err != nil {
+		return fmt.Errorf("platform cluster: %w", err)
+	}
+	if err := crdcheck.Verify(ctx, onboardingClient, crdcheck.ForCluster(expected, clustersv1alpha1.PURPOSE_ONBOARDING)); err != nil {
+		return fmt.Errorf("onboarding cluster: %w", err)
+	}
+	return nil
+}
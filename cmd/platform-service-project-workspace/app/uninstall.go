@@ -0,0 +1,347 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/openmcp-project/controller-utils/pkg/clusters"
+	"github.com/openmcp-project/controller-utils/pkg/init/webhooks"
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+	openmcpconst "github.com/openmcp-project/openmcp-operator/api/constants"
+	libutils "github.com/openmcp-project/openmcp-operator/lib/utils"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/api/v2/crds"
+	providerscheme "github.com/openmcp-project/platform-service-project-workspace/api/v2/install"
+	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/core"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/dns"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/webhookconfig"
+)
+
+func NewUninstallCommand(so *SharedOptions) *cobra.Command {
+	opts := &UninstallOptions{
+		InitOptions: &InitOptions{
+			SharedOptions: so,
+		},
+	}
+	cmd := &cobra.Command{
+		Use:   "uninstall",
+		Short: "Remove the resources created by the init command and the platform service",
+		Long: `Remove the resources which have been created by the init command and the platform service:
+the webhook configurations, Service, and Secret, the route of the webhooks, the managed ClusterRoles and ClusterRoleBindings on the onboarding cluster,
+the NetworkPolicy and label of the provider system namespace, the permissions ConfigMap, and the AccessRequests and ClusterRequests of the platform service.
+The CRDs are only removed if requested, because deleting them deletes all projects and workspaces.
+The platform service should not be running anymore, otherwise it recreates most of the resources.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			opts.PrintRawOptions(cmd)
+			if err := opts.Complete(cmd.Context()); err != nil {
+				panic(fmt.Errorf("error completing options: %w", err))
+			}
+			opts.PrintCompletedOptions(cmd)
+			if opts.DryRun {
+				cmd.Println("=== END OF DRY RUN ===")
+				return
+			}
+			if err := opts.Run(cmd.Context()); err != nil {
+				panic(err)
+			}
+		},
+	}
+	// the webhook DNS flags are needed to find the route of the webhooks
+	opts.InitOptions.AddFlags(cmd)
+	opts.AddFlags(cmd)
+
+	return cmd
+}
+
+type RawUninstallOptions struct {
+	DeleteCRDs   bool `json:"delete-crds"`
+	ServerDryRun bool `json:"server-dry-run"`
+}
+
+type UninstallOptions struct {
+	*InitOptions
+	RawUninstallOptions
+}
+
+func (o *UninstallOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&o.DeleteCRDs, "delete-crds", false, "If set, the CRDs are deleted from the platform and onboarding cluster as well. Refused as long as projects or workspaces exist.")
+	cmd.Flags().BoolVar(&o.ServerDryRun, "server-dry-run", false, "If set, all deletions are sent as server-side dry run requests, so that the command only logs what it would delete. Only the AccessRequest of the command itself is created.")
+}
+
+func (o *UninstallOptions) PrintRaw(cmd *cobra.Command) {
+	data, err := yaml.Marshal(o.RawUninstallOptions)
+	if err != nil {
+		cmd.Println(fmt.Errorf("error marshalling raw options: %w", err).Error())
+		return
+	}
+	cmd.Print(string(data))
+}
+
+func (o *UninstallOptions) PrintRawOptions(cmd *cobra.Command) {
+	cmd.Println("########## RAW OPTIONS START ##########")
+	o.SharedOptions.PrintRaw(cmd)
+	o.InitOptions.PrintRaw(cmd)
+	o.PrintRaw(cmd)
+	cmd.Println("########## RAW OPTIONS END ##########")
+}
+
+func (o *UninstallOptions) Run(ctx context.Context) error {
+	platformScheme := providerscheme.InstallOperatorAPIsPlatform(runtime.NewScheme())
+	if err := o.PlatformCluster.InitializeClient(platformScheme); err != nil {
+		return err
+	}
+
+	log := o.Log.WithName("main")
+	ctx = logging.NewContext(ctx, log)
+	log.Info("Environment", "value", o.Environment)
+	log.Info("ProviderName", "value", o.ProviderName)
+
+	providerSystemNamespace := os.Getenv(openmcpconst.EnvVariablePodNamespace)
+	if providerSystemNamespace == "" {
+		return fmt.Errorf("environment variable %s is not set", openmcpconst.EnvVariablePodNamespace)
+	}
+
+	log.Info("Getting access to the onboarding cluster")
+	onboardingScheme := runtime.NewScheme()
+	providerscheme.InstallOperatorAPIsOnboarding(onboardingScheme)
+	onboardingCluster, err := o.onboardingClusterAccess(ctx, log, providerSystemNamespace, clustersv1alpha1.PURPOSE_ONBOARDING+"-uninstall", onboardingScheme,
+		rbacv1.PolicyRule{
+			APIGroups: []string{rbacv1.GroupName},
+			Resources: []string{"clusterroles", "clusterrolebindings"},
+			Verbs:     []string{"get", "list", "delete"},
+		},
+		rbacv1.PolicyRule{
+			APIGroups: []string{pwv1alpha1.GroupName},
+			Resources: []string{"projects", "workspaces"},
+			Verbs:     []string{"get", "list"},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	// the deletions are sent via these clusters, which only perform server-side dry run requests if requested
+	platformCluster := o.PlatformCluster
+	onboardingClient := onboardingCluster.Client()
+	if o.ServerDryRun {
+		log.Info("Server-side dry run, nothing is deleted")
+		platformCluster = clusters.New(o.PlatformCluster.ID()).WithRESTConfig(o.PlatformCluster.RESTConfig()).WithClientOptions(client.Options{Scheme: platformScheme, DryRun: ptr.To(true)})
+		if err := platformCluster.InitializeClient(platformScheme); err != nil {
+			return err
+		}
+		onboardingClient = client.NewDryRunClient(onboardingClient)
+	}
+
+	// the ProjectWorkspaceConfig is only needed for the DNS configuration and the management labels, it might have been deleted already
+	pwc := &pwv1alpha1.ProjectWorkspaceConfig{}
+	if err := o.PlatformCluster.Client().Get(ctx, client.ObjectKey{Name: o.ProviderName}, pwc); err != nil {
+		if !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return fmt.Errorf("unable to get ProjectWorkspaceConfig '%s': %w", o.ProviderName, err)
+		}
+		log.Info("ProjectWorkspaceConfig not found, using the default configuration")
+	}
+	pwc.SetDefaults()
+
+	if o.DeleteCRDs {
+		if err := verifyNoProjectsOrWorkspaces(ctx, onboardingCluster.Client()); err != nil {
+			return err
+		}
+	}
+
+	log.Info("Removing webhooks")
+	whSecretName, err := libutils.WebhookSecretName(o.ProviderName)
+	if err != nil {
+		return fmt.Errorf("unable to determine webhook secret name: %w", err)
+	}
+	webhookConfigs := &webhookconfig.Installer{
+		Scope:   o.webhookScope(),
+		Client:  onboardingClient,
+		Service: types.NamespacedName{Name: webhookServiceName(o.ProviderName), Namespace: providerSystemNamespace},
+	}
+	if err := webhookConfigs.Uninstall(ctx, webhookTypes()...); err != nil {
+		return fmt.Errorf("unable to uninstall webhook configurations: %w", err)
+	}
+	if err := webhooks.Uninstall(ctx, platformCluster.Client(), onboardingScheme, nil,
+		webhookInstallOptions(o.ProviderName, providerSystemNamespace, whSecretName, onboardingClient)...); err != nil {
+		return fmt.Errorf("unable to uninstall webhooks: %w", err)
+	}
+	if err := deleteIfExists(ctx, platformCluster.Client(), &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: whSecretName, Namespace: providerSystemNamespace}}); err != nil {
+		return err
+	}
+
+	if os.Getenv("SKIP_GATEWAY") != "true" {
+		log.Info("Removing webhook route")
+		dnsConfig, err := o.dnsConfig(pwc.Spec.Webhook.DNS)
+		if err != nil {
+			return err
+		}
+		dnsProvider, err := dns.NewProvider(dnsConfig)
+		if err != nil {
+			return fmt.Errorf("unable to create DNS provider: %w", err)
+		}
+		if err := dnsProvider.DeleteRoute(ctx, o.webhookDNSInstance(dnsConfig, providerSystemNamespace), platformCluster); err != nil {
+			return fmt.Errorf("unable to delete webhook route: %w", err)
+		}
+	}
+
+	log.Info("Removing managed ClusterRoles and ClusterRoleBindings from the onboarding cluster")
+	if err := deleteManagedClusterRBAC(ctx, onboardingCluster.Client(), onboardingClient, o.ProviderName, pwc.Spec.ManagementLabels); err != nil {
+		return err
+	}
+
+	log.Info("Cleaning up provider system namespace", "namespace", providerSystemNamespace)
+	nsSetup := o.systemNamespaceSetup(providerSystemNamespace, false)
+	nsSetup.Client = platformCluster.Client()
+	if err := nsSetup.Cleanup(ctx); err != nil {
+		return err
+	}
+	if err := deleteIfExists(ctx, platformCluster.Client(), &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: sharedconfig.PermissionsConfigMapName(o.ProviderName), Namespace: providerSystemNamespace}}); err != nil {
+		return err
+	}
+
+	if o.DeleteCRDs {
+		log.Info("Removing CRDs")
+		if err := deleteCRDs(ctx, map[string]client.Client{
+			clustersv1alpha1.PURPOSE_PLATFORM:   platformCluster.Client(),
+			clustersv1alpha1.PURPOSE_ONBOARDING: onboardingClient,
+		}); err != nil {
+			return err
+		}
+	}
+
+	// the AccessRequests are removed last, since the one of this command is among them
+	log.Info("Removing AccessRequests and ClusterRequests")
+	if err := deleteAccessRequests(ctx, o.PlatformCluster.Client(), platformCluster.Client(), providerSystemNamespace); err != nil {
+		return err
+	}
+
+	log.Info("Finished uninstall command")
+	return nil
+}
+
+// deleteIfExists deletes the given object and logs it, objects which do not exist are ignored.
+func deleteIfExists(ctx context.Context, c client.Client, obj client.Object) error {
+	if err := c.Delete(ctx, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("unable to delete %T '%s': %w", obj, client.ObjectKeyFromObject(obj).String(), err)
+	}
+	logging.FromContextOrDiscard(ctx).Info("Deleted resource", "kind", fmt.Sprintf("%T", obj), "name", obj.GetName(), "namespace", obj.GetNamespace())
+	return nil
+}
+
+// verifyNoProjectsOrWorkspaces returns an error if projects or workspaces exist on the onboarding cluster, since deleting the CRDs would delete them.
+func verifyNoProjectsOrWorkspaces(ctx context.Context, onboardingClient client.Client) error {
+	for _, list := range []client.ObjectList{&pwv1alpha1.ProjectList{}, &pwv1alpha1.WorkspaceList{}} {
+		if err := onboardingClient.List(ctx, list, client.Limit(1)); err != nil {
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+			return fmt.Errorf("unable to list %T: %w", list, err)
+		}
+		if meta.LenList(list) > 0 {
+			return fmt.Errorf("refusing to delete the CRDs, because projects or workspaces still exist on the onboarding cluster")
+		}
+	}
+	return nil
+}
+
+// deleteManagedClusterRBAC deletes the ClusterRoles and ClusterRoleBindings on the onboarding cluster which carry the management labels of the platform service.
+// They are read with reader and deleted with deleter, so that the deletion can be a dry run.
+func deleteManagedClusterRBAC(ctx context.Context, reader, deleter client.Client, providerName string, cfg pwv1alpha1.ManagementLabelsConfig) error {
+	clusterRoles := &rbacv1.ClusterRoleList{}
+	if err := reader.List(ctx, clusterRoles); err != nil {
+		return fmt.Errorf("unable to list ClusterRoles: %w", err)
+	}
+	for i := range clusterRoles.Items {
+		if !utils.IsManaged(&clusterRoles.Items[i], providerName, cfg) {
+			continue
+		}
+		if err := deleteIfExists(ctx, deleter, &clusterRoles.Items[i]); err != nil {
+			return err
+		}
+	}
+
+	clusterRoleBindings := &rbacv1.ClusterRoleBindingList{}
+	if err := reader.List(ctx, clusterRoleBindings); err != nil {
+		return fmt.Errorf("unable to list ClusterRoleBindings: %w", err)
+	}
+	for i := range clusterRoleBindings.Items {
+		if !utils.IsManaged(&clusterRoleBindings.Items[i], providerName, cfg) {
+			continue
+		}
+		if err := deleteIfExists(ctx, deleter, &clusterRoleBindings.Items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteCRDs deletes the CRDs of the platform service, the clients are mapped by the cluster label of the CRDs.
+func deleteCRDs(ctx context.Context, clientsByClusterLabel map[string]client.Client) error {
+	crdList, err := crds.CRDs()
+	if err != nil {
+		return fmt.Errorf("error getting CRDs: %w", err)
+	}
+	for _, crd := range crdList {
+		c, ok := clientsByClusterLabel[crd.Labels[openmcpconst.ClusterLabel]]
+		if !ok {
+			return fmt.Errorf("no cluster mapping found for label value '%s' in CRD '%s'", crd.Labels[openmcpconst.ClusterLabel], crd.Name)
+		}
+		if err := deleteIfExists(ctx, c, &apiextv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: crd.Name}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteAccessRequests deletes the AccessRequests and ClusterRequests in the provider system namespace which have been created by the init, uninstall, and run commands.
+// They are read with reader and deleted with deleter, so that the deletion can be a dry run.
+func deleteAccessRequests(ctx context.Context, reader, deleter client.Client, providerSystemNamespace string) error {
+	req, err := labels.NewRequirement(openmcpconst.ManagedByLabel, selection.In, []string{core.ControllerName, sharedconfig.ControllerName})
+	if err != nil {
+		return fmt.Errorf("failed to build label selector: %w", err)
+	}
+	opts := []client.ListOption{client.InNamespace(providerSystemNamespace), client.MatchingLabelsSelector{Selector: labels.NewSelector().Add(*req)}}
+
+	ars := &clustersv1alpha1.AccessRequestList{}
+	if err := reader.List(ctx, ars, opts...); err != nil {
+		return fmt.Errorf("unable to list AccessRequests: %w", err)
+	}
+	for i := range ars.Items {
+		if err := deleteIfExists(ctx, deleter, &ars.Items[i]); err != nil {
+			return err
+		}
+	}
+
+	crs := &clustersv1alpha1.ClusterRequestList{}
+	if err := reader.List(ctx, crs, opts...); err != nil {
+		return fmt.Errorf("unable to list ClusterRequests: %w", err)
+	}
+	for i := range crs.Items {
+		if err := deleteIfExists(ctx, deleter, &crs.Items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,6 +2,8 @@ package config
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -146,6 +148,54 @@ func BuiltinPermissibleWorkspaceResourcesAdminOnly() []rbacv1.PolicyRule {
 	}
 }
 
+// DefaultAuditorExcludedResources returns the resources which members with the 'auditor' role must not read,
+// if nothing else is configured in the ProjectWorkspaceConfig.
+func DefaultAuditorExcludedResources() []metav1.GroupResource {
+	return []metav1.GroupResource{
+		{
+			Group:    corev1.GroupName,
+			Resource: "secrets",
+		},
+	}
+}
+
+// RemoveExcludedResources returns a copy of the given policy rules without the excluded resources and their subresources.
+// A resource is removed from a rule if the rule applies to the resource's API group, which includes rules for multiple API groups.
+// Rules which do not contain any resources afterwards are dropped, wildcard resources are not expanded.
+// The given list is not modified.
+func RemoveExcludedResources(rules []rbacv1.PolicyRule, excluded []metav1.GroupResource) []rbacv1.PolicyRule {
+	if len(excluded) == 0 {
+		return rules
+	}
+	res := make([]rbacv1.PolicyRule, 0, len(rules))
+	for _, rule := range rules {
+		excludedResources := sets.New[string]()
+		for _, gr := range excluded {
+			if slices.Contains(rule.APIGroups, gr.Group) || slices.Contains(rule.APIGroups, rbacv1.APIGroupAll) {
+				excludedResources.Insert(gr.Resource)
+			}
+		}
+		if excludedResources.Len() == 0 {
+			res = append(res, *rule.DeepCopy())
+			continue
+		}
+		resources := make([]string, 0, len(rule.Resources))
+		for _, r := range rule.Resources {
+			resource, _, _ := strings.Cut(r, "/")
+			if !excludedResources.Has(resource) {
+				resources = append(resources, r)
+			}
+		}
+		if len(resources) == 0 {
+			continue
+		}
+		filtered := *rule.DeepCopy()
+		filtered.Resources = resources
+		res = append(res, filtered)
+	}
+	return res
+}
+
 // AppendPolicyRules appends the given elements to the list and returns the new list.
 // If there is already an entry with the same apiGroups, the resources are merged.
 // Otherwise, a new entry is appended.
@@ -185,7 +235,7 @@ func InjectMissingVerbs(roleID string, permissibleResources []rbacv1.PolicyRule)
 	switch roleID {
 	case utils.AdminRoleID:
 		verbGenerator = utils.AllVerbs
-	case utils.ViewerRoleID:
+	case utils.ViewerRoleID, utils.AuditorRoleID:
 		verbGenerator = utils.ReadOnlyVerbs
 	default:
 		return fmt.Errorf("unknown role ID: %s", roleID)
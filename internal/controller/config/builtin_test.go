@@ -0,0 +1,85 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+)
+
+func TestRemoveExcludedResources(t *testing.T) {
+	secrets := metav1.GroupResource{Group: corev1.GroupName, Resource: "secrets"}
+
+	tests := []struct {
+		description string
+		rules       []rbacv1.PolicyRule
+		excluded    []metav1.GroupResource
+		expected    []rbacv1.PolicyRule
+	}{
+		{
+			description: "returns the rules unchanged if nothing is excluded",
+			rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{corev1.GroupName}, Resources: []string{"secrets"}},
+			},
+			expected: []rbacv1.PolicyRule{
+				{APIGroups: []string{corev1.GroupName}, Resources: []string{"secrets"}},
+			},
+		},
+		{
+			description: "removes excluded resources and their subresources",
+			rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{corev1.GroupName}, Resources: []string{"secrets", "secrets/status", "configmaps"}},
+			},
+			excluded: []metav1.GroupResource{secrets},
+			expected: []rbacv1.PolicyRule{
+				{APIGroups: []string{corev1.GroupName}, Resources: []string{"configmaps"}},
+			},
+		},
+		{
+			description: "drops rules without remaining resources",
+			rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{corev1.GroupName}, Resources: []string{"secrets"}},
+				{APIGroups: []string{corev1.GroupName}, Resources: []string{"pods"}, Verbs: []string{"list"}},
+			},
+			excluded: []metav1.GroupResource{secrets},
+			expected: []rbacv1.PolicyRule{
+				{APIGroups: []string{corev1.GroupName}, Resources: []string{"pods"}, Verbs: []string{"list"}},
+			},
+		},
+		{
+			description: "ignores resources with the same name in other API groups",
+			rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{"example.com"}, Resources: []string{"secrets"}},
+			},
+			excluded: []metav1.GroupResource{secrets},
+			expected: []rbacv1.PolicyRule{
+				{APIGroups: []string{"example.com"}, Resources: []string{"secrets"}},
+			},
+		},
+		{
+			description: "removes resources from rules for all API groups",
+			rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{rbacv1.APIGroupAll}, Resources: []string{"secrets", "configmaps"}},
+			},
+			excluded: []metav1.GroupResource{secrets},
+			expected: []rbacv1.PolicyRule{
+				{APIGroups: []string{rbacv1.APIGroupAll}, Resources: []string{"configmaps"}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			original := make([]rbacv1.PolicyRule, len(test.rules))
+			for i := range test.rules {
+				original[i] = *test.rules[i].DeepCopy()
+			}
+			assert.Equal(t, test.expected, config.RemoveExcludedResources(test.rules, test.excluded))
+			assert.Equal(t, original, test.rules, "input rules must not be modified")
+		})
+	}
+}
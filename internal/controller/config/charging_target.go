@@ -0,0 +1,64 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+)
+
+// chargingTargetCache caches the allowed charging targets from a ConfigMap on the platform cluster,
+// so that the webhooks do not have to read and parse the ConfigMap for every admission request.
+type chargingTargetCache struct {
+	lock      sync.Mutex
+	key       client.ObjectKey
+	values    sets.Set[string]
+	expiresAt time.Time
+	// now returns the current time, it can be replaced in tests.
+	now func() time.Time
+}
+
+// get returns the allowed charging targets from the ConfigMap with the given key.
+// The ConfigMap is only read again if the cached values have expired or a different ConfigMap is requested.
+func (c *chargingTargetCache) get(ctx context.Context, platformClient client.Client, key client.ObjectKey, ttl time.Duration) (sets.Set[string], error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := time.Now
+	if c.now != nil {
+		now = c.now
+	}
+	if c.values != nil && c.key == key && now().Before(c.expiresAt) {
+		return c.values, nil
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := platformClient.Get(ctx, key, cm); err != nil {
+		return nil, fmt.Errorf("failed to get ConfigMap '%s' with the allowed charging targets: %w", key.String(), err)
+	}
+	c.key = key
+	c.values = parseAllowedChargingTargets(cm.Data[pwv1alpha1.ChargingTargetAllowedValuesKey])
+	c.expiresAt = now().Add(ttl)
+	return c.values, nil
+}
+
+// parseAllowedChargingTargets returns the charging targets listed in the given ConfigMap entry, one per line.
+// Surrounding whitespace is trimmed, empty lines and lines starting with '#' are ignored.
+func parseAllowedChargingTargets(data string) sets.Set[string] {
+	values := sets.New[string]()
+	for line := range strings.Lines(data) {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		values.Insert(line)
+	}
+	return values
+}
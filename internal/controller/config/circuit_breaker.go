@@ -0,0 +1,212 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/metrics"
+)
+
+const (
+	// DefaultAccessRetryBaseDelay is the default delay after the first failed reconciliation of the AccessRequest for the dynamic onboarding cluster access.
+	// It is doubled with each further consecutive failure.
+	DefaultAccessRetryBaseDelay = 5 * time.Second
+	// DefaultAccessRetryMaxDelay is the default upper limit of the delay between retries, as long as the circuit breaker is closed.
+	DefaultAccessRetryMaxDelay = 2 * time.Minute
+	// DefaultAccessFailureThreshold is the default number of consecutive failures after which the circuit breaker opens.
+	DefaultAccessFailureThreshold = 5
+	// DefaultAccessCoolDown is the default time for which the AccessRequest is not reconciled once the circuit breaker has opened.
+	DefaultAccessCoolDown = 10 * time.Minute
+)
+
+// AccessCircuitBreaker limits how often the AccessRequest for the dynamic onboarding cluster access is reconciled while this fails,
+// e.g. because the cluster provider is degraded, so that it is not retried in a tight loop which floods the logs.
+// Failed reconciliations are retried with exponential backoff. After FailureThreshold consecutive failures, the breaker opens and the
+// AccessRequest is not reconciled until the cool-down has passed. Then a single attempt is made, which closes the breaker if it succeeds
+// and opens it again otherwise.
+// It is not safe for concurrent use, the PWOConfigController only uses it while holding its lock.
+type AccessCircuitBreaker struct {
+	// FailureThreshold is the number of consecutive failures after which the breaker opens.
+	FailureThreshold int
+	// BaseDelay is the delay after the first failure, it is doubled with each further failure up to MaxDelay.
+	BaseDelay time.Duration
+	// MaxDelay is the upper limit of the delay between retries while the breaker is closed.
+	MaxDelay time.Duration
+	// CoolDown is the time for which the breaker stays open.
+	CoolDown time.Duration
+
+	failures  int
+	lastErr   error
+	openUntil time.Time
+}
+
+// NewAccessCircuitBreaker creates a new closed AccessCircuitBreaker with the default settings.
+func NewAccessCircuitBreaker() *AccessCircuitBreaker {
+	return &AccessCircuitBreaker{
+		FailureThreshold: DefaultAccessFailureThreshold,
+		BaseDelay:        DefaultAccessRetryBaseDelay,
+		MaxDelay:         DefaultAccessRetryMaxDelay,
+		CoolDown:         DefaultAccessCoolDown,
+	}
+}
+
+// Allow returns whether the AccessRequest may be reconciled at the given time.
+// If not, the remaining cool-down is returned as well.
+func (b *AccessCircuitBreaker) Allow(now time.Time) (bool, time.Duration) {
+	if !now.Before(b.openUntil) {
+		return true, 0
+	}
+	return false, b.openUntil.Sub(now)
+}
+
+// Open returns whether the threshold of consecutive failures has been reached.
+// This stays true for the attempt after the cool-down, until it succeeds.
+func (b *AccessCircuitBreaker) Open() bool {
+	return b.failures >= b.FailureThreshold
+}
+
+// Failures returns the number of consecutive failures.
+func (b *AccessCircuitBreaker) Failures() int {
+	return b.failures
+}
+
+// LastError returns the error of the last failure, or nil if the last reconciliation succeeded.
+func (b *AccessCircuitBreaker) LastError() error {
+	return b.lastErr
+}
+
+// RecordFailure records a failed reconciliation at the given time and returns the delay after which it should be retried.
+// Once the threshold has been reached, the breaker opens and the delay is the cool-down.
+func (b *AccessCircuitBreaker) RecordFailure(now time.Time, err error) time.Duration {
+	b.failures++
+	b.lastErr = err
+	if b.Open() {
+		b.openUntil = now.Add(b.CoolDown)
+		return b.CoolDown
+	}
+	delay := b.BaseDelay
+	for i := 1; i < b.failures && delay < b.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, b.MaxDelay)
+}
+
+// RecordSuccess records a successful reconciliation, which closes the breaker.
+// Returns whether the previous reconciliation had failed.
+func (b *AccessCircuitBreaker) RecordSuccess() bool {
+	failed := b.failures > 0
+	b.failures = 0
+	b.lastErr = nil
+	b.openUntil = time.Time{}
+	return failed
+}
+
+// AccessDegradedError is returned by the reconciliation of the ProjectWorkspaceConfig if the dynamic onboarding cluster access could not be reconciled.
+// The remaining configuration has been loaded nonetheless, so the reconciliation is retried after RetryAfter instead of with the rate limiter of the controller.
+type AccessDegradedError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *AccessDegradedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *AccessDegradedError) Unwrap() error {
+	return e.Err
+}
+
+// handleAccessFailureInternal records the failed reconciliation of the AccessRequest in the circuit breaker, the metrics and the status of the given ProjectWorkspaceConfig.
+// The returned error contains the delay after which the reconciliation should be retried.
+// The given config is nil for the fallback config from the file, which has no status.
+// The lock must be held when calling this method.
+func (c *PWOConfigController) handleAccessFailureInternal(ctx context.Context, cfg *pwv1alpha1.ProjectWorkspaceConfig, accessErr error) *AccessDegradedError {
+	log := logging.FromContextOrPanic(ctx)
+
+	metrics.OnboardingAccessFailures.Inc()
+	wasOpen := c.AccessCircuitBreaker.Open()
+	retryAfter := c.AccessCircuitBreaker.RecordFailure(time.Now(), accessErr)
+
+	condition := pwv1alpha1.Condition{
+		Type:    pwv1alpha1.ConditionTypeOnboardingAccessDegraded,
+		Status:  pwv1alpha1.ConditionStatusTrue,
+		Reason:  pwv1alpha1.ConditionReasonAccessRequestFailing,
+		Message: fmt.Sprintf("Reconciling the dynamic onboarding cluster access failed %d time(s) in a row, retrying in %s: %s", c.AccessCircuitBreaker.Failures(), retryAfter, accessErr.Error()),
+	}
+	if c.AccessCircuitBreaker.Open() {
+		metrics.OnboardingAccessCircuitBreakerOpen.Set(1)
+		condition.Reason = pwv1alpha1.ConditionReasonCircuitBreakerOpen
+		condition.Message = fmt.Sprintf("Reconciling the dynamic onboarding cluster access failed %d time(s) in a row, it is paused for %s: %s", c.AccessCircuitBreaker.Failures(), retryAfter, accessErr.Error())
+		if !wasOpen {
+			log.Error(accessErr, "Circuit breaker for dynamic onboarding cluster access opened, static access keeps being used", "coolDown", retryAfter)
+			if c.rec != nil && cfg != nil {
+				c.rec.Event(cfg, corev1.EventTypeWarning, pwv1alpha1.EventReasonOnboardingAccessDegraded, condition.Message)
+			}
+		}
+	} else {
+		log.Info("Failed to reconcile dynamic onboarding cluster access, retrying with backoff", "error", accessErr.Error(), "failures", c.AccessCircuitBreaker.Failures(), "retryAfter", retryAfter)
+	}
+
+	if cfg != nil {
+		if err := c.updateAccessConditionInternal(ctx, cfg, condition); err != nil {
+			log.Error(err, "failed to update status")
+		}
+	}
+
+	return &AccessDegradedError{
+		Err:        fmt.Errorf("failed to reconcile cluster access to the onboarding cluster: %w", accessErr),
+		RetryAfter: retryAfter,
+	}
+}
+
+// handleAccessSuccessInternal closes the circuit breaker after a successful reconciliation of the AccessRequest.
+// If the access is shown as degraded in the status of the given ProjectWorkspaceConfig (nil for the fallback config), the condition is updated.
+// The condition is checked independently of the circuit breaker, since it may have been set before a restart.
+// The lock must be held when calling this method.
+func (c *PWOConfigController) handleAccessSuccessInternal(ctx context.Context, cfg *pwv1alpha1.ProjectWorkspaceConfig) {
+	log := logging.FromContextOrPanic(ctx)
+
+	wasOpen := c.AccessCircuitBreaker.Open()
+	if c.AccessCircuitBreaker.RecordSuccess() {
+		metrics.OnboardingAccessCircuitBreakerOpen.Set(0)
+		log.Info("Dynamic onboarding cluster access recovered")
+		if wasOpen && c.rec != nil && cfg != nil {
+			c.rec.Event(cfg, corev1.EventTypeNormal, pwv1alpha1.EventReasonOnboardingAccessRecovered, "Dynamic onboarding cluster access is available again")
+		}
+	}
+
+	if cfg == nil {
+		return
+	}
+	if existing := cfg.GetCondition(pwv1alpha1.ConditionTypeOnboardingAccessDegraded); existing == nil || existing.Status != pwv1alpha1.ConditionStatusTrue {
+		return
+	}
+	if err := c.updateAccessConditionInternal(ctx, cfg, pwv1alpha1.Condition{
+		Type:    pwv1alpha1.ConditionTypeOnboardingAccessDegraded,
+		Status:  pwv1alpha1.ConditionStatusFalse,
+		Reason:  pwv1alpha1.ConditionReasonAccessAvailable,
+		Message: "Dynamic onboarding cluster access is available",
+	}); err != nil {
+		log.Error(err, "failed to update status")
+	}
+}
+
+// updateAccessConditionInternal sets the given condition in the status of the given ProjectWorkspaceConfig, if it changed.
+func (c *PWOConfigController) updateAccessConditionInternal(ctx context.Context, cfg *pwv1alpha1.ProjectWorkspaceConfig, condition pwv1alpha1.Condition) error {
+	if existing := cfg.GetCondition(condition.Type); existing != nil && existing.Status == condition.Status && existing.Reason == condition.Reason && existing.Message == condition.Message {
+		return nil
+	}
+	old := cfg.DeepCopy()
+	cfg.SetOrUpdateCondition(condition)
+	if err := c.platformCluster.Client().Status().Patch(ctx, cfg, client.MergeFrom(old)); err != nil {
+		return fmt.Errorf("failed to update status of ProjectWorkspaceConfig: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package config_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+)
+
+func TestAccessCircuitBreaker(t *testing.T) {
+	errAccess := errors.New("cluster provider unavailable")
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	newBreaker := func() *config.AccessCircuitBreaker {
+		return &config.AccessCircuitBreaker{
+			FailureThreshold: 4,
+			BaseDelay:        10 * time.Second,
+			MaxDelay:         25 * time.Second,
+			CoolDown:         5 * time.Minute,
+		}
+	}
+
+	t.Run("should retry with exponential backoff until the threshold is reached", func(t *testing.T) {
+		b := newBreaker()
+		delays := []time.Duration{}
+		for range 3 {
+			delays = append(delays, b.RecordFailure(now, errAccess))
+		}
+		assert.Equal(t, []time.Duration{10 * time.Second, 20 * time.Second, 25 * time.Second}, delays)
+		assert.False(t, b.Open())
+		allowed, _ := b.Allow(now)
+		assert.True(t, allowed)
+	})
+
+	t.Run("should open after the threshold and allow a single attempt after the cool-down", func(t *testing.T) {
+		b := newBreaker()
+		for range 3 {
+			b.RecordFailure(now, errAccess)
+		}
+		assert.Equal(t, 5*time.Minute, b.RecordFailure(now, errAccess))
+		assert.True(t, b.Open())
+		assert.ErrorIs(t, b.LastError(), errAccess)
+
+		allowed, remaining := b.Allow(now.Add(time.Minute))
+		assert.False(t, allowed)
+		assert.Equal(t, 4*time.Minute, remaining)
+
+		allowed, _ = b.Allow(now.Add(5 * time.Minute))
+		assert.True(t, allowed)
+		assert.Equal(t, 5*time.Minute, b.RecordFailure(now.Add(5*time.Minute), errAccess), "a failure after the cool-down must open the breaker again")
+		allowed, _ = b.Allow(now.Add(6 * time.Minute))
+		assert.False(t, allowed)
+	})
+
+	t.Run("should close after a success", func(t *testing.T) {
+		b := newBreaker()
+		for range 4 {
+			b.RecordFailure(now, errAccess)
+		}
+		assert.True(t, b.RecordSuccess())
+		assert.False(t, b.Open())
+		assert.Zero(t, b.Failures())
+		assert.NoError(t, b.LastError())
+		allowed, _ := b.Allow(now)
+		assert.True(t, allowed)
+		assert.False(t, b.RecordSuccess(), "a success without previous failures must not be reported as recovery")
+		assert.Equal(t, 10*time.Second, b.RecordFailure(now, errAccess), "the backoff must start over")
+	})
+}
@@ -3,9 +3,12 @@ package config
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -13,10 +16,16 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
@@ -34,6 +43,10 @@ import (
 
 	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
 	"github.com/openmcp-project/platform-service-project-workspace/api/v2/install"
+	pwoerrors "github.com/openmcp-project/platform-service-project-workspace/internal/errors"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/logconfig"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/metrics"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/restconfig"
 	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
 )
 
@@ -42,6 +55,13 @@ import (
 const (
 	ControllerName             = "pw-config"
 	ClusterIDOnboardingDynamic = "onboarding-dynamic"
+	// ReconcilerName is the name the ProjectWorkspaceConfig controller is registered with at the manager.
+	ReconcilerName = "projectworkspaceconfig"
+	// Finalizer is added to the ProjectWorkspaceConfig, so that it is not removed while Projects or Workspaces still depend on it.
+	// It differs from the finalizer of Projects and Workspaces, so that both can be told apart.
+	Finalizer = pwv1alpha1.GroupName + "/config-protection"
+	// deletionBlockedRequeueInterval is the interval in which a ProjectWorkspaceConfig in deletion is checked for remaining Projects and Workspaces.
+	deletionBlockedRequeueInterval = 1 * time.Minute
 )
 
 // Setup //
@@ -53,26 +73,90 @@ type PWOConfigController struct {
 	rec                           record.EventRecorder
 	OnboardingClusterAccessStatic *clusters.Cluster
 	DiscoveryService              discovery.DiscoveryInterface
+	podNamespace                  string
+	// Environment is the environment of this instance of the platform service.
+	// If set, the ProjectWorkspaceConfigOverride with this name in the pod namespace is merged over the ProjectWorkspaceConfig.
+	Environment string
+	// PropagationQPS and PropagationBurst limit the rate at which Projects and Workspaces are enqueued after a config change.
+	PropagationQPS   float32
+	PropagationBurst int
+	// ResyncInterval is the interval in which all Projects and Workspaces are enqueued regardless of configuration changes, so that drift is healed.
+	// The interval is jittered, a value of zero disables the periodic resync.
+	ResyncInterval time.Duration
+	// FallbackConfigPath is the path of a file containing a ProjectWorkspaceConfig or its spec.
+	// If set, the config is read from this file as long as the ProjectWorkspaceConfig resource does not exist, e.g. during the bootstrap of air-gapped landscapes.
+	// The controller switches to the resource as soon as it is created.
+	FallbackConfigPath string
+	// LogLevels are updated with the log levels from the config, if set.
+	LogLevels *logconfig.Levels
+	// OnboardingConnection contains additional settings for connecting to the onboarding cluster, e.g. via a proxy.
+	// They are applied to the dynamic onboarding cluster access, the static access is expected to have them applied already.
+	OnboardingConnection restconfig.ConnectionOptions
+	// AccessCircuitBreaker limits the reconciliations of the AccessRequest for the dynamic onboarding cluster access while they fail.
+	// It is protected by the lock below.
+	AccessCircuitBreaker *AccessCircuitBreaker
+	// chargingTargets caches the allowed charging targets, it is protected by its own lock.
+	chargingTargets chargingTargetCache
+	// operatorIdentityCache caches the selected operator identities, it is protected by its own lock.
+	operatorIdentityCache operatorIdentityCache
+	// subscriptions contains the channels returned by Subscribe, it is protected by its own lock.
+	subscriptions subscriptions
 
 	// The lock needs to be held when reading or writing any of the fields below.
 	lock                               *sync.RWMutex
 	resourcesBlockingProjectDeletion   []DeletionBlockingResource
 	resourcesBlockingWorkspaceDeletion []DeletionBlockingResource
+	projectDeletionIgnoreRules         []pwv1alpha1.DeletionIgnoreRule
+	workspaceDeletionIgnoreRules       []pwv1alpha1.DeletionIgnoreRule
+	workspaceBlockingResourcePolicies  []pwv1alpha1.BlockingResourcePolicyRule
+	excludedWebhookIdentities          []pwv1alpha1.IdentityMatcher
+	addCreatorAsAdmin                  bool
+	projectAdminsManageWorkspaces      bool
+	operatorIdentities                 *pwv1alpha1.OperatorIdentitiesConfig
+	chargingTarget                     pwv1alpha1.ChargingTargetConfig
+	memberPolicy                       pwv1alpha1.MemberPolicyConfig
+	webhookLookups                     pwv1alpha1.WebhookLookupsConfig
+	managementLabels                   pwv1alpha1.ManagementLabelsConfig
+	automationServiceAccount           pwv1alpha1.AutomationServiceAccountConfig
+	workspaceNetworkPolicies           []pwv1alpha1.NetworkPolicyTemplate
+	workspaceAdditionalResources       []pwv1alpha1.ResourceTemplate
+	flatWorkspaces                     bool
+	consolidatedProjectClusterRoles    bool
+	projectDeletionGracePeriod         time.Duration
+	serviceAccountMembers              pwv1alpha1.ServiceAccountMembersConfig
+	secretStores                       []pwv1alpha1.SecretStoreConfig
+	allowedProjectBlockingResources    []metav1.GroupVersionKind
+	maxProjectsPerCreator              *int32
+	workspaceProviderHints             []pwv1alpha1.ProviderHintConfig
+	workspacePodSecurity               *pwv1alpha1.PodSecurityConfig
 	permissibleProjectResources        []rbacv1.PolicyRule
 	permissibleWorkspaceResources      []rbacv1.PolicyRule
 	// the config allows more precise definition of roles, therefore the 'permissible...' fields are not enough to store the permissions from the config
-	projectPermissionsFromConfig   map[string][]rbacv1.PolicyRule
-	workspacePermissionsFromConfig map[string][]rbacv1.PolicyRule
-	onboardingClusterAccessDynamic *clusters.Cluster
-	memberOverrides                []pwv1alpha1.MemberOverride
-	missingConfig                  bool
+	projectPermissionsFromConfig      map[string][]rbacv1.PolicyRule
+	workspacePermissionsFromConfig    map[string][]rbacv1.PolicyRule
+	projectAuditorExcludedResources   []metav1.GroupResource
+	workspaceAuditorExcludedResources []metav1.GroupResource
+	onboardingClusterAccessDynamic    *clusters.Cluster
+	memberOverrides                   []pwv1alpha1.MemberOverride
+	missingConfig                     bool
+	usingFallbackConfig               bool
+	projectEvents                     chan event.GenericEvent
+	workspaceEvents                   chan event.GenericEvent
+	propagatedFingerprint             string
+	permissionsFingerprint            string
+	blockingResourcesFingerprint      string
+	cancelPropagation                 context.CancelFunc
+	// clusterRolesEnsured is true once the ClusterRoles of the project and workspace roles have been created or updated.
+	clusterRolesEnsured bool
 }
 
 // NewPWConfigController creates a new PWOConfigController.
 // This controller has the following responsibilities:
 // - It watches the ProjectWorkspaceConfig resource belonging to this instance of the PlatformService PWO and reloads it on changes.
 // - It watches ServiceProvider resources for their registered resource types in their status and updates permissions and blocking resources accordingly.
-// - It can trigger project and workspace reconciliations via the passed-in channels if the config changes in a way that requires it.
+// - It can trigger project and workspace reconciliations via the channels returned by ProjectEvents and WorkspaceEvents if the config changes in a way that requires it.
+// - It notifies the subscribers registered via Subscribe if the permissions or the resources blocking deletion change.
+// - It watches the ClusterRoles of the project and workspace roles and restores them if they are deleted.
 // - It implements the SharedInformation interface, so that other controllers can query it for the current configuration.
 // - It reconciles the OnboardingCluster AccessRequests for the project and workspace controllers to ensure they can always fetch the the resources that are supposed to block deletion.
 func NewPWConfigController(providerName string, platformCluster *clusters.Cluster, onboardingClusterStatic *clusters.Cluster, onboardingClusterRef *commonapi.ObjectReference, rec record.EventRecorder, podNamespace string) (*PWOConfigController, error) {
@@ -91,6 +175,11 @@ func NewPWConfigController(providerName string, platformCluster *clusters.Cluste
 		platformCluster:               platformCluster,
 		OnboardingClusterAccessStatic: onboardingClusterStatic,
 		DiscoveryService:              ds,
+		podNamespace:                  podNamespace,
+		PropagationQPS:                DefaultPropagationQPS,
+		PropagationBurst:              DefaultPropagationBurst,
+		ResyncInterval:                DefaultResyncInterval,
+		AccessCircuitBreaker:          NewAccessCircuitBreaker(),
 		Car: advanced.NewClusterAccessReconciler(platformCluster.Client(), ControllerName).
 			Register(advanced.ExistingCluster(ClusterIDOnboardingDynamic, "obdyn", obRef).WithScheme(scheme).WithNamespaceGenerator(func(_ reconcile.Request, _ ...any) (string, error) { return podNamespace, nil }).Build()),
 		rec:                                rec,
@@ -134,8 +223,18 @@ func (c *PWOConfigController) discoverResourceNameForGVK(log logging.Logger, gvk
 }
 
 func (c *PWOConfigController) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		Named("projectworkspaceconfig").
+	if c.ResyncInterval > 0 {
+		// the resync is added as a runnable which requires leader election, so that only the leading replica enqueues the objects
+		log := logging.Wrap(mgr.GetLogger()).WithName(ControllerName)
+		if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+			return c.runResync(logging.NewContext(ctx, log), c.ResyncInterval)
+		})); err != nil {
+			return fmt.Errorf("unable to add periodic resync to manager: %w", err)
+		}
+	}
+	b := ctrl.NewControllerManagedBy(mgr).
+		Named(ReconcilerName).
+		WithLogConstructor(logconfig.LogConstructor(mgr.GetLogger(), ReconcilerName)).
 		WatchesRawSource(source.Kind(c.platformCluster.Cluster().GetCache(), &pwv1alpha1.ProjectWorkspaceConfig{}, &handler.TypedEnqueueRequestForObject[*pwv1alpha1.ProjectWorkspaceConfig]{}, ctrlutils.ToTypedPredicate[*pwv1alpha1.ProjectWorkspaceConfig](
 			predicate.And(
 				ctrlutils.ExactNamePredicate(c.providerName, ""),
@@ -156,7 +255,59 @@ func (c *PWOConfigController) SetupWithManager(mgr ctrl.Manager) error {
 				},
 			}
 		}), ctrlutils.ToTypedPredicate[*providerv1alpha1.ServiceProvider](ctrlutils.StatusChangedPredicate{}))).
-		Complete(c)
+		WatchesRawSource(source.Kind(mgr.GetCache(), &pwv1alpha1.WorkspaceClass{}, handler.TypedEnqueueRequestsFromMapFunc(func(_ context.Context, _ *pwv1alpha1.WorkspaceClass) []ctrl.Request {
+			// the dynamic onboarding cluster access needs permissions for the resources blocking the deletion of the workspaces of each class
+			return []ctrl.Request{
+				reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name: c.providerName,
+					},
+				},
+			}
+		}), predicate.TypedGenerationChangedPredicate[*pwv1alpha1.WorkspaceClass]{})).
+		WatchesRawSource(source.Kind(c.platformCluster.Cluster().GetCache(), &pwv1alpha1.ProjectWorkspaceConfigOverride{}, handler.TypedEnqueueRequestsFromMapFunc(func(_ context.Context, _ *pwv1alpha1.ProjectWorkspaceConfigOverride) []ctrl.Request {
+			// the override for this environment is merged over the config, so the config needs to be reconciled
+			return []ctrl.Request{
+				reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name: c.providerName,
+					},
+				},
+			}
+		}), ctrlutils.ToTypedPredicate[*pwv1alpha1.ProjectWorkspaceConfigOverride](
+			predicate.And(
+				ctrlutils.ExactNamePredicate(c.Environment, c.podNamespace),
+				predicate.Or(
+					predicate.GenerationChangedPredicate{},
+					ctrlutils.DeletionTimestampChangedPredicate{},
+				),
+			),
+		))).
+		WatchesRawSource(source.Kind(c.platformCluster.Cluster().GetCache(), &pwv1alpha1.MemberOverrideSet{}, handler.TypedEnqueueRequestsFromMapFunc(func(_ context.Context, _ *pwv1alpha1.MemberOverrideSet) []ctrl.Request {
+			// the overrides of the selected sets are added to the member overrides of the config
+			return []ctrl.Request{
+				reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name: c.providerName,
+					},
+				},
+			}
+		}), ctrlutils.ToTypedPredicate[*pwv1alpha1.MemberOverrideSet](
+			predicate.Or(
+				predicate.GenerationChangedPredicate{},
+				predicate.LabelChangedPredicate{},
+				ctrlutils.DeletionTimestampChangedPredicate{},
+			),
+		)))
+	if c.FallbackConfigPath != "" {
+		b = b.WatchesRawSource(c.fallbackConfigSource())
+	}
+	clusterRoles, err := c.baseClusterRolesSource(mgr)
+	if err != nil {
+		return err
+	}
+	b = b.WatchesRawSource(clusterRoles)
+	return b.Complete(metrics.ObserveReconciler(ReconcilerName, c))
 }
 
 // Reconciler Implementation //
@@ -168,13 +319,28 @@ func (c *PWOConfigController) Reconcile(ctx context.Context, req reconcile.Reque
 	ctx = logging.NewContext(ctx, log)
 	log.Info("Starting reconcile")
 	cfg, rr, err := c.reconcile(ctx, req)
-	if c.rec != nil && cfg != nil {
+	// the fallback config from the file does not exist in the cluster, so no events can be recorded for it
+	if c.rec != nil && cfg != nil && cfg.UID != "" {
 		if err != nil {
 			c.rec.Event(cfg, corev1.EventTypeWarning, pwv1alpha1.EventReasonReconcileFailed, err.Error())
 		} else {
 			c.rec.Event(cfg, corev1.EventTypeNormal, pwv1alpha1.EventReasonReconcileSucceeded, "Reconciliation successful")
 		}
 	}
+	var degraded *AccessDegradedError
+	if errors.As(err, &degraded) {
+		// the remaining configuration has been loaded and the failure is shown in the status and the metrics,
+		// so the retry is delayed according to the circuit breaker instead of the rate limiter of the controller
+		return reconcile.Result{RequeueAfter: degraded.RetryAfter}, nil
+	}
+	if err != nil {
+		kind := pwoerrors.Classify(err)
+		metrics.ReconcileErrors.WithLabelValues(ReconcilerName, string(kind)).Inc()
+		if kind == pwoerrors.KindTerminal {
+			// retrying does not help, the config or its override has to be fixed, which triggers a new reconciliation
+			return reconcile.Result{}, reconcile.TerminalError(err)
+		}
+	}
 	return rr, err
 }
 
@@ -196,59 +362,176 @@ func (c *PWOConfigController) reconcile(ctx context.Context, req reconcile.Reque
 		c.permissibleWorkspaceResources = nil
 		c.resourcesBlockingProjectDeletion = nil
 		c.resourcesBlockingWorkspaceDeletion = nil
+		c.projectDeletionIgnoreRules = nil
+		c.workspaceDeletionIgnoreRules = nil
+		c.workspaceBlockingResourcePolicies = nil
+		c.excludedWebhookIdentities = nil
+		c.addCreatorAsAdmin = false
+		c.projectAdminsManageWorkspaces = false
+		c.operatorIdentities = nil
+		c.chargingTarget = pwv1alpha1.ChargingTargetConfig{}
+		c.memberPolicy = pwv1alpha1.MemberPolicyConfig{}
+		c.webhookLookups = pwv1alpha1.WebhookLookupsConfig{}
+		c.managementLabels = pwv1alpha1.ManagementLabelsConfig{}
 		c.projectPermissionsFromConfig = nil
 		c.workspacePermissionsFromConfig = nil
+		c.projectAuditorExcludedResources = nil
+		c.workspaceAuditorExcludedResources = nil
+		c.workspaceNetworkPolicies = nil
+		c.workspaceAdditionalResources = nil
+		c.flatWorkspaces = false
+		c.consolidatedProjectClusterRoles = false
+		c.projectDeletionGracePeriod = 0
+		c.serviceAccountMembers = pwv1alpha1.ServiceAccountMembersConfig{}
+		c.secretStores = nil
+		c.allowedProjectBlockingResources = nil
+		c.maxProjectsPerCreator = nil
+		c.workspaceProviderHints = nil
+		c.workspacePodSecurity = nil
 		c.memberOverrides = nil
 		c.missingConfig = true
+		c.usingFallbackConfig = false
+		if c.LogLevels != nil {
+			_ = c.LogLevels.Apply(nil)
+		}
 		log.Info("Resetting state and deleting AccessRequest because ProjectWorkspaceConfig is missing or in deletion")
 		return c.Car.ReconcileDelete(ctx, req)
 	}
 
 	// fetch the config
 	cfg := &pwv1alpha1.ProjectWorkspaceConfig{}
+	fromFile := false
 	if err := c.platformCluster.Client().Get(ctx, req.NamespacedName, cfg); err != nil {
-		if apierrors.IsNotFound(err) {
-			_, _ = reset()
+		if !apierrors.IsNotFound(err) || c.FallbackConfigPath == "" {
+			if apierrors.IsNotFound(err) {
+				_, _ = reset()
+			}
+			return nil, reconcile.Result{}, fmt.Errorf("failed to fetch ProjectWorkspaceConfig: %w", err)
 		}
-		return nil, reconcile.Result{}, fmt.Errorf("failed to fetch ProjectWorkspaceConfig: %w", err)
+		// serve the config from the file until the resource is created
+		cfg, err = LoadFallbackConfig(c.FallbackConfigPath, c.providerName)
+		if err != nil {
+			return nil, reconcile.Result{}, err
+		}
+		if !c.usingFallbackConfig {
+			log.Info("ProjectWorkspaceConfig does not exist, using fallback config from file", "path", c.FallbackConfigPath)
+		}
+		fromFile = true
+	} else if c.usingFallbackConfig {
+		log.Info("ProjectWorkspaceConfig has been created, switching from fallback config to the resource")
 	}
 
+	if c.OnboardingClusterAccessStatic == nil {
+		return nil, reconcile.Result{}, fmt.Errorf("static onboarding cluster access is not available")
+	}
+
+	var requeueAfter time.Duration
 	if !cfg.DeletionTimestamp.IsZero() {
-		// config is being deleted, this should not happen
-		log.Info("Warning: ProjectWorkspaceConfig is in deletion, this should only happen when the PlatformService is being deleted")
-		rr, err := reset()
-		return cfg, rr, err
+		// config is being deleted, this should only happen when the PlatformService is being deleted
+		// Projects and Workspaces cannot be reconciled without the config, so it stays active until all of them are gone
+		remaining, err := c.remainingProjectsAndWorkspaces(ctx)
+		if err != nil {
+			return cfg, reconcile.Result{}, err
+		}
+		if remaining == "" {
+			rr, err := reset()
+			if err != nil || rr.RequeueAfter > 0 {
+				return cfg, rr, err
+			}
+			if controllerutil.ContainsFinalizer(cfg, Finalizer) {
+				log.Info("Removing finalizer from ProjectWorkspaceConfig")
+				old := cfg.DeepCopy()
+				controllerutil.RemoveFinalizer(cfg, Finalizer)
+				if err := c.platformCluster.Client().Patch(ctx, cfg, client.MergeFrom(old)); err != nil {
+					return cfg, reconcile.Result{}, fmt.Errorf("failed to remove finalizer from ProjectWorkspaceConfig: %w", err)
+				}
+			}
+			return cfg, rr, nil
+		}
+		msg := fmt.Sprintf("ProjectWorkspaceConfig is in deletion, but %s still exist, the configuration stays active until they are deleted", remaining)
+		log.Info("Warning: " + msg)
+		if c.rec != nil {
+			c.rec.Event(cfg, corev1.EventTypeWarning, pwv1alpha1.EventReasonDeletionBlocked, msg)
+		}
+		requeueAfter = deletionBlockedRequeueInterval
+	} else if !fromFile && !controllerutil.ContainsFinalizer(cfg, Finalizer) {
+		log.Info("Adding finalizer to ProjectWorkspaceConfig")
+		old := cfg.DeepCopy()
+		controllerutil.AddFinalizer(cfg, Finalizer)
+		if err := c.platformCluster.Client().Patch(ctx, cfg, client.MergeFrom(old)); err != nil {
+			return cfg, reconcile.Result{}, fmt.Errorf("failed to add finalizer to ProjectWorkspaceConfig: %w", err)
+		}
 	}
 	c.missingConfig = false
+	c.usingFallbackConfig = fromFile
 
-	if c.OnboardingClusterAccessStatic == nil {
-		return nil, reconcile.Result{}, fmt.Errorf("static onboarding cluster access is not available")
+	// handle operation annotation
+	if !fromFile && cfg.GetAnnotations()[apiconst.OperationAnnotation] == apiconst.OperationAnnotationValueReconcile {
+		log.Debug("Removing reconcile operation annotation from resource")
+		if err := ctrlutils.EnsureAnnotation(ctx, c.platformCluster.Client(), cfg, apiconst.OperationAnnotation, "", true, ctrlutils.DELETE); err != nil {
+			return cfg, reconcile.Result{}, fmt.Errorf("error removing operation annotation: %w", err)
+		}
 	}
 
-	// use information from config
-	newResourcesBlockingProjectDeletion := collections.ProjectSliceToSlice(cfg.Spec.Project.ResourcesBlockingDeletion, func(gvk metav1.GroupVersionKind) DeletionBlockingResource {
-		return DeletionBlockingResource{
-			GroupVersionKind: gvk,
-			Source:           pwv1alpha1.SourceProjectWorkspaceConfig,
-		}
-	})
-	newResourcesBlockingWorkspaceDeletion := collections.ProjectSliceToSlice(cfg.Spec.Workspace.ResourcesBlockingDeletion, func(gvk metav1.GroupVersionKind) DeletionBlockingResource {
-		return DeletionBlockingResource{
-			GroupVersionKind: gvk,
-			Source:           pwv1alpha1.SourceProjectWorkspaceConfig,
+	// keep the stored config for status updates, the merged one only exists in memory
+	stored := cfg
+
+	// merge the override for this environment over the config, if there is one
+	override, err := c.fetchOverride(ctx)
+	if err != nil {
+		return cfg, reconcile.Result{}, err
+	}
+	if override != nil {
+		log.Info("Merging ProjectWorkspaceConfigOverride over ProjectWorkspaceConfig", "override", client.ObjectKeyFromObject(override).String())
+		cfg = MergeOverride(cfg, override)
+		if err := cfg.Validate(); err != nil {
+			return cfg, reconcile.Result{}, pwoerrors.NewTerminalError(fmt.Errorf("invalid ProjectWorkspaceConfig after merging ProjectWorkspaceConfigOverride '%s': %w", client.ObjectKeyFromObject(override).String(), err))
 		}
-	})
-	newProjectPermissionsFromConfig := map[string][]rbacv1.PolicyRule{}
-	for role, rules := range cfg.Spec.Project.AdditionalPermissions {
-		newProjectPermissionsFromConfig[utils.ProjectMemberRoleToRoleID(role)] = rules
 	}
-	newWorkspacePermissionsFromConfig := map[string][]rbacv1.PolicyRule{}
-	for role, rules := range cfg.Spec.Workspace.AdditionalPermissions {
-		newWorkspacePermissionsFromConfig[utils.WorkspaceMemberRoleToRoleID(role)] = rules
+
+	// add the member overrides of the selected MemberOverrideSets
+	memberOverrides, err := EffectiveMemberOverrides(ctx, c.platformCluster.Client(), cfg)
+	if err != nil {
+		return cfg, reconcile.Result{}, err
 	}
 
-	// set member overrides
-	c.memberOverrides = cfg.Spec.MemberOverrides
+	// use information from config
+	newResourcesBlockingProjectDeletion := deletionBlockingResourcesFromConfig(cfg.Spec.Project.ResourcesBlockingDeletion)
+	newResourcesBlockingWorkspaceDeletion := deletionBlockingResourcesFromConfig(cfg.Spec.Workspace.ResourcesBlockingDeletion)
+	newProjectPermissionsFromConfig := projectPermissionsFromConfig(cfg)
+	newWorkspacePermissionsFromConfig := workspacePermissionsFromConfig(cfg)
+
+	// set member overrides and deletion ignore rules
+	c.memberOverrides = memberOverrides
+	c.projectDeletionIgnoreRules = cfg.Spec.Project.IgnoredBlockingResources
+	c.workspaceDeletionIgnoreRules = cfg.Spec.Workspace.IgnoredBlockingResources
+	c.workspaceBlockingResourcePolicies = cfg.Spec.Workspace.BlockingResourcePolicies
+	c.excludedWebhookIdentities = cfg.Spec.Webhook.ExcludedIdentities
+	c.addCreatorAsAdmin = cfg.Spec.Webhook.AddCreatorAsAdmin
+	c.projectAdminsManageWorkspaces = cfg.Spec.Webhook.ProjectAdminsManageWorkspaces
+	c.operatorIdentities = cfg.Spec.Webhook.OperatorIdentities.DeepCopy()
+	c.chargingTarget = chargingTargetFromConfig(cfg.Spec.Webhook.ChargingTarget)
+	c.memberPolicy = memberPolicyFromConfig(cfg.Spec.Webhook.MemberPolicy)
+	c.webhookLookups = webhookLookupsFromConfig(cfg.Spec.Webhook.Lookups)
+	c.managementLabels = *cfg.Spec.ManagementLabels.DeepCopy()
+	c.projectAuditorExcludedResources = auditorExcludedResourcesFromConfig(cfg.Spec.Project.AuditorExcludedResources)
+	c.workspaceAuditorExcludedResources = auditorExcludedResourcesFromConfig(cfg.Spec.Workspace.AuditorExcludedResources)
+	c.automationServiceAccount = automationServiceAccountFromConfig(cfg.Spec.Project.AutomationServiceAccount)
+	c.workspaceNetworkPolicies = cloneNetworkPolicyTemplates(cfg.Spec.Workspace.NetworkPolicies)
+	c.workspaceAdditionalResources = cloneResourceTemplates(cfg.Spec.Workspace.AdditionalResources)
+	c.flatWorkspaces = ptr.Deref(cfg.Spec.Workspace.Flat, false)
+	c.consolidatedProjectClusterRoles = ptr.Deref(cfg.Spec.Project.ConsolidatedClusterRoles, false)
+	c.projectDeletionGracePeriod = deletionGracePeriodFromConfig(cfg.Spec.Project.DeletionGracePeriod)
+	c.serviceAccountMembers = serviceAccountMembersFromConfig(cfg.Spec.Workspace.ServiceAccountMembers)
+	c.secretStores = slices.Clone(cfg.Spec.Project.SecretStores)
+	c.allowedProjectBlockingResources = slices.Clone(cfg.Spec.Project.AllowedResourcesBlockingDeletion)
+	c.maxProjectsPerCreator = maxProjectsPerCreatorFromConfig(cfg.Spec.Project.MaxProjectsPerCreator)
+	c.workspacePodSecurity = cfg.Spec.Workspace.PodSecurity.DeepCopy()
+	if c.LogLevels != nil {
+		if err := c.LogLevels.Apply(cfg.Spec.Logging); err != nil {
+			return cfg, reconcile.Result{}, pwoerrors.NewTerminalError(fmt.Errorf("failed to apply log levels: %w", err))
+		}
+	}
 
 	// fetch ServiceProvider resources to get their registered resource types
 	log.Debug("Fetching ServiceProvider resources to get registered resource types")
@@ -259,7 +542,10 @@ func (c *PWOConfigController) reconcile(ctx context.Context, req reconcile.Reque
 		return cfg, reconcile.Result{}, fmt.Errorf("failed to list ServiceProviders: %w", err)
 	}
 	log.Debug("Fetched ServiceProviders", "count", len(sps.Items))
+	discovered := make([]pwv1alpha1.ServiceProviderResources, 0, len(sps.Items))
+	var discoveryErrs []error
 	for _, sp := range sps.Items {
+		spResources := pwv1alpha1.ServiceProviderResources{Name: sp.Name}
 		for _, gvk := range sp.Status.Resources {
 			// add resource to list of resources blocking workspace deletion
 			// (this needs to be extended for project deletion blocking as well, if we ever allow MCPs on project level)
@@ -268,10 +554,15 @@ func (c *PWOConfigController) reconcile(ctx context.Context, req reconcile.Reque
 				Source:           fmt.Sprintf("%s[%s]", pwv1alpha1.SourceServiceProviderPrefix, sp.Name),
 			})
 			// add resource to permissible resources
+			// discovery errors are collected, so that the status shows all resources which could not be discovered
 			resourceName, err := c.discoverResourceNameForGVK(log, gvk)
 			if err != nil {
-				return cfg, reconcile.Result{}, fmt.Errorf("error determining resource name for kind '%s' with apiVersion '%s/%s', registered by ServiceProvider '%s: %w", gvk.Kind, gvk.Group, gvk.Version, sp.Name, err)
+				err = fmt.Errorf("error determining resource name for kind '%s' with apiVersion '%s/%s', registered by ServiceProvider '%s': %w", gvk.Kind, gvk.Group, gvk.Version, sp.Name, err)
+				discoveryErrs = append(discoveryErrs, err)
+				spResources.Resources = append(spResources.Resources, pwv1alpha1.DiscoveredResource{GroupVersionKind: gvk, Error: err.Error()})
+				continue
 			}
+			spResources.Resources = append(spResources.Resources, pwv1alpha1.DiscoveredResource{GroupVersionKind: gvk, Resource: resourceName})
 			agr := rbacv1.PolicyRule{
 				APIGroups: []string{gvk.Group},
 				Resources: []string{resourceName},
@@ -280,10 +571,20 @@ func (c *PWOConfigController) reconcile(ctx context.Context, req reconcile.Reque
 			// newPermissibleProjectResources = AppendPolicyRules(newPermissibleProjectResources, agr)
 			newPermissibleWorkspaceResources = AppendPolicyRules(newPermissibleWorkspaceResources, agr)
 		}
+		discovered = append(discovered, spResources)
 	}
 	log.Debug("Finished processing ServiceProviders")
+	if !fromFile {
+		if err := c.updateDiscoveryStatus(ctx, stored, discovered); err != nil {
+			return cfg, reconcile.Result{}, err
+		}
+	}
+	if err := errors.Join(discoveryErrs...); err != nil {
+		return cfg, reconcile.Result{}, err
+	}
 
 	// now we have all required information, update internal state
+	c.workspaceProviderHints = providerHintsOfRegisteredProviders(log, cfg.Spec.Workspace.ProviderHints, sps.Items)
 	c.resourcesBlockingProjectDeletion = newResourcesBlockingProjectDeletion
 	c.resourcesBlockingWorkspaceDeletion = newResourcesBlockingWorkspaceDeletion
 	c.permissibleProjectResources = newPermissibleProjectResources
@@ -293,9 +594,22 @@ func (c *PWOConfigController) reconcile(ctx context.Context, req reconcile.Reque
 
 	// update the ClusterRoles for project and workspace to ensure end-users have sufficient permissions for the resources registered by the ServiceProviders and the additional permissions from the config
 	log.Debug("Ensuring that ClusterRoles for projects and workspaces are up-to-date ...")
-	if err := NewRBACSetup(c.OnboardingClusterAccessStatic.Client(), c.providerName).EnsureResources(ctx, c.projectPermissionsForRoleInternal, c.workspacePermissionsForRoleInternal); err != nil {
+	rbacSetup := NewRBACSetup(c.OnboardingClusterAccessStatic.Client(), c.providerName).WithManagementLabels(c.managementLabels)
+	if err := rbacSetup.EnsureResources(ctx, c.projectPermissionsForRoleInternal, c.workspacePermissionsForRoleInternal); err != nil {
 		return cfg, reconcile.Result{}, fmt.Errorf("error updating project and workspace ClusterRoles on the onboarding cluster: %w", err)
 	}
+	// the ClusterRoles are created during the first reconciliation, afterwards they are only created if they have been deleted in the meantime
+	if created := rbacSetup.CreatedClusterRoles(); c.clusterRolesEnsured && len(created) > 0 {
+		msg := fmt.Sprintf("Restored deleted ClusterRoles: %s", strings.Join(created, ", "))
+		log.Info(msg)
+		if c.rec != nil && cfg.UID != "" {
+			c.rec.Event(cfg, corev1.EventTypeWarning, pwv1alpha1.EventReasonClusterRolesRestored, msg)
+		}
+	}
+	c.clusterRolesEnsured = true
+	if err := c.updatePermissionsDocumentInternal(ctx); err != nil {
+		return cfg, reconcile.Result{}, err
+	}
 
 	// update the AccessRequests for the onboarding cluster to ensure that the project and workspace controllers have sufficient permissions to get the resources blocking deletion
 	log.Info("Updating AccessRequests to ensure project and workspace controllers have sufficient permissions to get deletion blocking resources")
@@ -311,6 +625,19 @@ func (c *PWOConfigController) reconcile(ctx context.Context, req reconcile.Reque
 		})
 
 	}
+	// projects can only add allowed resource types to the resources blocking their deletion, so permissions for all of them are requested upfront
+	for _, gvk := range c.allowedProjectBlockingResources {
+		resourceName, err := c.discoverResourceNameForGVK(log, gvk)
+		if err != nil {
+			return cfg, reconcile.Result{}, fmt.Errorf("error determining resource name for kind '%s' with apiVersion '%s/%s', allowed for projects: %w", gvk.Kind, gvk.Group, gvk.Version, err)
+		}
+		permissionGroups = AppendPolicyRules(permissionGroups, rbacv1.PolicyRule{
+			APIGroups: []string{gvk.Group},
+			Resources: []string{resourceName, fmt.Sprintf("%s/status", resourceName)},
+		})
+	}
+	// remaining resources with policy 'Cascade' are deleted by the workspace controller, which requires additional permissions
+	cascadeGroups := []rbacv1.PolicyRule{}
 	for _, res := range c.resourcesBlockingWorkspaceDeletionInternal() {
 		resourceName, err := c.discoverResourceNameForGVK(log, res.GroupVersionKind)
 		if err != nil {
@@ -320,6 +647,35 @@ func (c *PWOConfigController) reconcile(ctx context.Context, req reconcile.Reque
 			APIGroups: []string{res.Group},
 			Resources: []string{resourceName, fmt.Sprintf("%s/status", resourceName)},
 		})
+		if res.Policy == pwv1alpha1.BlockingResourcePolicyCascade {
+			cascadeGroups = AppendPolicyRules(cascadeGroups, rbacv1.PolicyRule{
+				APIGroups: []string{res.Group},
+				Resources: []string{resourceName},
+			})
+		}
+	}
+	// the resources blocking the deletion of the workspaces of a WorkspaceClass are checked via the dynamic access as well
+	classes := &pwv1alpha1.WorkspaceClassList{}
+	if err := c.OnboardingClusterAccessStatic.Client().List(ctx, classes); err != nil {
+		return cfg, reconcile.Result{}, fmt.Errorf("failed to list WorkspaceClasses: %w", err)
+	}
+	for _, class := range classes.Items {
+		for _, gvk := range class.Spec.ResourcesBlockingDeletion {
+			resourceName, err := c.discoverResourceNameForGVK(log, gvk)
+			if err != nil {
+				return cfg, reconcile.Result{}, fmt.Errorf("error determining resource name for kind '%s' with apiVersion '%s/%s', registered by WorkspaceClass '%s': %w", gvk.Kind, gvk.Group, gvk.Version, class.Name, err)
+			}
+			permissionGroups = AppendPolicyRules(permissionGroups, rbacv1.PolicyRule{
+				APIGroups: []string{gvk.Group},
+				Resources: []string{resourceName, fmt.Sprintf("%s/status", resourceName)},
+			})
+			if pwv1alpha1.BlockingResourcePolicyFor(c.workspaceBlockingResourcePolicies, gvk.Group, gvk.Kind) == pwv1alpha1.BlockingResourcePolicyCascade {
+				cascadeGroups = AppendPolicyRules(cascadeGroups, rbacv1.PolicyRule{
+					APIGroups: []string{gvk.Group},
+					Resources: []string{resourceName},
+				})
+			}
+		}
 	}
 	permissions := collections.ProjectSliceToSlice(permissionGroups, func(elem rbacv1.PolicyRule) clustersv1alpha1.PermissionsRequest {
 		return clustersv1alpha1.PermissionsRequest{
@@ -332,13 +688,46 @@ func (c *PWOConfigController) reconcile(ctx context.Context, req reconcile.Reque
 			},
 		}
 	})
+	for _, elem := range cascadeGroups {
+		permissions = append(permissions, clustersv1alpha1.PermissionsRequest{
+			Rules: []rbacv1.PolicyRule{
+				{
+					APIGroups: elem.APIGroups,
+					Resources: elem.Resources,
+					Verbs:     []string{"delete"},
+				},
+			},
+		})
+	}
 	if err := c.Car.Update(ClusterIDOnboardingDynamic, advanced.UpdateTokenAccess(&clustersv1alpha1.TokenConfig{Permissions: permissions})); err != nil {
 		return cfg, reconcile.Result{}, fmt.Errorf("failed to update AccessRequest for onboarding cluster: %w", err)
 	}
+	// the fallback config from the file does not exist in the cluster, so the state of the access cannot be shown in its status
+	statusCfg := stored
+	if fromFile {
+		statusCfg = nil
+	}
+	if allowed, coolDown := c.AccessCircuitBreaker.Allow(time.Now()); !allowed {
+		// the previous dynamic access (if any) and the static access keep being used, only the configuration changes are propagated
+		log.Debug("Skipping reconciliation of dynamic onboarding cluster access, because the circuit breaker is open", "retryAfter", coolDown)
+		if err := c.propagateConfigChangesInternal(ctx); err != nil {
+			return cfg, reconcile.Result{}, fmt.Errorf("failed to propagate configuration changes: %w", err)
+		}
+		return cfg, reconcile.Result{}, &AccessDegradedError{
+			Err:        fmt.Errorf("reconciliation of the dynamic onboarding cluster access is paused after repeated failures: %w", c.AccessCircuitBreaker.LastError()),
+			RetryAfter: minRequeueAfter(coolDown, requeueAfter),
+		}
+	}
 	rr, err := c.Car.Reconcile(ctx, req)
 	if err != nil {
-		return cfg, rr, fmt.Errorf("failed to reconcile cluster access to the onboarding cluster: %w", err)
+		degraded := c.handleAccessFailureInternal(ctx, statusCfg, err)
+		degraded.RetryAfter = minRequeueAfter(degraded.RetryAfter, requeueAfter)
+		if err := c.propagateConfigChangesInternal(ctx); err != nil {
+			return cfg, reconcile.Result{}, fmt.Errorf("failed to propagate configuration changes: %w", err)
+		}
+		return cfg, reconcile.Result{}, degraded
 	}
+	c.handleAccessSuccessInternal(ctx, statusCfg)
 	if rr.RequeueAfter > 0 {
 		log.Info("Waiting for dynamic onboarding cluster access to become available/updated")
 		return cfg, rr, nil
@@ -349,8 +738,17 @@ func (c *PWOConfigController) reconcile(ctx context.Context, req reconcile.Reque
 	if err != nil {
 		return cfg, rr, fmt.Errorf("failed to get dynamic onboarding cluster access: %w", err)
 	}
+	access, err = c.OnboardingConnection.ApplyToCluster(access)
+	if err != nil {
+		return cfg, rr, fmt.Errorf("failed to configure dynamic onboarding cluster connection: %w", err)
+	}
 	c.onboardingClusterAccessDynamic = access
 
+	// enqueue all projects and workspaces if the config changed in a way that affects them
+	if err := c.propagateConfigChangesInternal(ctx); err != nil {
+		return cfg, reconcile.Result{}, fmt.Errorf("failed to propagate configuration changes: %w", err)
+	}
+
 	log.Info("Successfully reloaded configuration")
 	if log.Enabled(logging.DEBUG) {
 		// if logging on debug level is enabled, log the current configuration for easier debugging
@@ -366,7 +764,7 @@ func (c *PWOConfigController) reconcile(ctx context.Context, req reconcile.Reque
 			}
 		}
 		projectPermissions := map[string][]rbacv1.PolicyRule{}
-		for _, roleID := range []string{utils.AdminRoleID, utils.ViewerRoleID} {
+		for _, roleID := range []string{utils.AdminRoleID, utils.ViewerRoleID, utils.AuditorRoleID} {
 			perms, err := c.projectPermissionsForRoleInternal(roleID)
 			if err != nil {
 				log.Error(err, "error determining project permissions", "roleID", roleID)
@@ -375,7 +773,7 @@ func (c *PWOConfigController) reconcile(ctx context.Context, req reconcile.Reque
 			projectPermissions[roleID] = perms
 		}
 		workspacePermissions := map[string][]rbacv1.PolicyRule{}
-		for _, roleID := range []string{utils.AdminRoleID, utils.ViewerRoleID} {
+		for _, roleID := range []string{utils.AdminRoleID, utils.ViewerRoleID, utils.AuditorRoleID} {
 			perms, err := c.workspacePermissionsForRoleInternal(roleID)
 			if err != nil {
 				log.Error(err, "error determining workspace permissions", "roleID", roleID)
@@ -398,7 +796,179 @@ func (c *PWOConfigController) reconcile(ctx context.Context, req reconcile.Reque
 		}
 	}
 
-	return cfg, reconcile.Result{}, nil
+	return cfg, reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// minRequeueAfter returns the shorter of the given durations, ignoring the second one if it is zero.
+func minRequeueAfter(d, requeueAfter time.Duration) time.Duration {
+	if requeueAfter > 0 {
+		return min(d, requeueAfter)
+	}
+	return d
+}
+
+// updateDiscoveryStatus writes the resources discovered for the ServiceProviders into the status of the given ProjectWorkspaceConfig.
+func (c *PWOConfigController) updateDiscoveryStatus(ctx context.Context, cfg *pwv1alpha1.ProjectWorkspaceConfig, discovered []pwv1alpha1.ServiceProviderResources) error {
+	old := cfg.DeepCopy()
+	cfg.Status.LastDiscoveryTime = ptr.To(metav1.Now())
+	cfg.Status.ServiceProviders = discovered
+	if err := c.platformCluster.Client().Status().Patch(ctx, cfg, client.MergeFrom(old)); err != nil {
+		return fmt.Errorf("failed to update status of ProjectWorkspaceConfig: %w", err)
+	}
+	return nil
+}
+
+// remainingProjectsAndWorkspaces returns a description of the Projects and Workspaces which still exist on the onboarding cluster.
+// The returned string is empty if there are none.
+func (c *PWOConfigController) remainingProjectsAndWorkspaces(ctx context.Context) (string, error) {
+	projects := &pwv1alpha1.ProjectList{}
+	if err := c.OnboardingClusterAccessStatic.Client().List(ctx, projects); err != nil {
+		return "", fmt.Errorf("failed to list Projects: %w", err)
+	}
+	workspaces := &pwv1alpha1.WorkspaceList{}
+	if err := c.OnboardingClusterAccessStatic.Client().List(ctx, workspaces); err != nil {
+		return "", fmt.Errorf("failed to list Workspaces: %w", err)
+	}
+	if len(projects.Items) == 0 && len(workspaces.Items) == 0 {
+		return "", nil
+	}
+	return fmt.Sprintf("%d Project(s) and %d Workspace(s)", len(projects.Items), len(workspaces.Items)), nil
+}
+
+// deletionBlockingResourcesFromConfig converts the GroupVersionKinds from the config into DeletionBlockingResources.
+// They are normalized, since configs which have not been defaulted, e.g. merged overrides, might contain lower-case kinds.
+func deletionBlockingResourcesFromConfig(gvks []metav1.GroupVersionKind) []DeletionBlockingResource {
+	return collections.ProjectSliceToSlice(gvks, func(gvk metav1.GroupVersionKind) DeletionBlockingResource {
+		return DeletionBlockingResource{
+			GroupVersionKind: pwv1alpha1.NormalizeGroupVersionKind(gvk),
+			Source:           pwv1alpha1.SourceProjectWorkspaceConfig,
+		}
+	})
+}
+
+// projectPermissionsFromConfig returns the additional project permissions from the config, mapped by role ID.
+func projectPermissionsFromConfig(cfg *pwv1alpha1.ProjectWorkspaceConfig) map[string][]rbacv1.PolicyRule {
+	res := map[string][]rbacv1.PolicyRule{}
+	for role, rules := range cfg.Spec.Project.AdditionalPermissions {
+		res[utils.ProjectMemberRoleToRoleID(role)] = rules
+	}
+	return res
+}
+
+// workspacePermissionsFromConfig returns the additional workspace permissions from the config, mapped by role ID.
+func workspacePermissionsFromConfig(cfg *pwv1alpha1.ProjectWorkspaceConfig) map[string][]rbacv1.PolicyRule {
+	res := map[string][]rbacv1.PolicyRule{}
+	for role, rules := range cfg.Spec.Workspace.AdditionalPermissions {
+		res[utils.WorkspaceMemberRoleToRoleID(role)] = rules
+	}
+	return res
+}
+
+// auditorExcludedResourcesFromConfig returns the resources which are excluded for the 'auditor' role.
+// If the config does not specify any, the default ones are returned.
+func auditorExcludedResourcesFromConfig(configured []metav1.GroupResource) []metav1.GroupResource {
+	if configured == nil {
+		return DefaultAuditorExcludedResources()
+	}
+	return slices.Clone(configured)
+}
+
+// automationServiceAccountFromConfig returns the given automation ServiceAccount configuration with defaults filled in.
+func automationServiceAccountFromConfig(configured *pwv1alpha1.AutomationServiceAccountConfig) pwv1alpha1.AutomationServiceAccountConfig {
+	res := pwv1alpha1.AutomationServiceAccountConfig{}
+	if configured != nil {
+		res = *configured.DeepCopy()
+	}
+	if res.MaxTokenExpiration == nil {
+		res.MaxTokenExpiration = &metav1.Duration{Duration: pwv1alpha1.DefaultMaxAutomationTokenExpiration}
+	}
+	return res
+}
+
+// serviceAccountMembersFromConfig returns a copy of the given ServiceAccount member restrictions, which do not restrict anything if not configured.
+func serviceAccountMembersFromConfig(configured *pwv1alpha1.ServiceAccountMembersConfig) pwv1alpha1.ServiceAccountMembersConfig {
+	if configured == nil {
+		return pwv1alpha1.ServiceAccountMembersConfig{}
+	}
+	return *configured.DeepCopy()
+}
+
+// deletionGracePeriodFromConfig returns the configured deletion grace period, which is zero if not configured.
+func deletionGracePeriodFromConfig(configured *metav1.Duration) time.Duration {
+	if configured == nil {
+		return 0
+	}
+	return configured.Duration
+}
+
+// maxProjectsPerCreatorFromConfig returns a copy of the configured limit of projects per creator, which is nil if not configured.
+func maxProjectsPerCreatorFromConfig(configured *int32) *int32 {
+	if configured == nil {
+		return nil
+	}
+	return ptr.To(*configured)
+}
+
+// chargingTargetFromConfig returns a copy of the given charging target validation, which accepts any value if not configured.
+func chargingTargetFromConfig(configured *pwv1alpha1.ChargingTargetConfig) pwv1alpha1.ChargingTargetConfig {
+	if configured == nil {
+		return pwv1alpha1.ChargingTargetConfig{}
+	}
+	return *configured.DeepCopy()
+}
+
+// memberPolicyFromConfig returns a copy of the given member policy, which does not restrict the members if not configured.
+func memberPolicyFromConfig(configured *pwv1alpha1.MemberPolicyConfig) pwv1alpha1.MemberPolicyConfig {
+	if configured == nil {
+		return pwv1alpha1.MemberPolicyConfig{}
+	}
+	return *configured
+}
+
+// webhookLookupsFromConfig returns a deep copy of the given lookup config, which uses the defaults if not configured.
+func webhookLookupsFromConfig(configured *pwv1alpha1.WebhookLookupsConfig) pwv1alpha1.WebhookLookupsConfig {
+	if configured == nil {
+		return pwv1alpha1.WebhookLookupsConfig{}
+	}
+	return *configured.DeepCopy()
+}
+
+// providerHintsOfRegisteredProviders returns a deep copy of the given provider hints, without the ones whose ServiceProvider is not among the given ones.
+// The hints of missing ServiceProviders are logged, since workspaces cannot set them until the ServiceProvider is registered.
+func providerHintsOfRegisteredProviders(log logging.Logger, hints []pwv1alpha1.ProviderHintConfig, sps []providerv1alpha1.ServiceProvider) []pwv1alpha1.ProviderHintConfig {
+	res := make([]pwv1alpha1.ProviderHintConfig, 0, len(hints))
+	for _, hint := range hints {
+		if !slices.ContainsFunc(sps, func(sp providerv1alpha1.ServiceProvider) bool { return sp.Name == hint.ServiceProvider }) {
+			log.Info("Ignoring provider hint, because its ServiceProvider is not registered", "key", hint.Key, "serviceProvider", hint.ServiceProvider)
+			continue
+		}
+		res = append(res, *hint.DeepCopy())
+	}
+	return res
+}
+
+// cloneNetworkPolicyTemplates returns a deep copy of the given NetworkPolicy templates.
+func cloneNetworkPolicyTemplates(templates []pwv1alpha1.NetworkPolicyTemplate) []pwv1alpha1.NetworkPolicyTemplate {
+	if templates == nil {
+		return nil
+	}
+	res := make([]pwv1alpha1.NetworkPolicyTemplate, len(templates))
+	for i := range templates {
+		templates[i].DeepCopyInto(&res[i])
+	}
+	return res
+}
+
+// cloneResourceTemplates returns a deep copy of the given resource templates.
+func cloneResourceTemplates(templates []pwv1alpha1.ResourceTemplate) []pwv1alpha1.ResourceTemplate {
+	if templates == nil {
+		return nil
+	}
+	res := make([]pwv1alpha1.ResourceTemplate, len(templates))
+	for i := range templates {
+		templates[i].DeepCopyInto(&res[i])
+	}
+	return res
 }
 
 func (c *PWOConfigController) MemberOverrides(ctx context.Context) (pwv1alpha1.MemberOverrides, error) {
@@ -412,6 +982,203 @@ func (c *PWOConfigController) MemberOverrides(ctx context.Context) (pwv1alpha1.M
 	return res, nil
 }
 
+func (c *PWOConfigController) ExcludedWebhookIdentities(ctx context.Context) ([]pwv1alpha1.IdentityMatcher, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.missingConfig {
+		return nil, fmt.Errorf("ProjectWorkspaceConfig is missing")
+	}
+	return slices.Clone(c.excludedWebhookIdentities), nil
+}
+
+func (c *PWOConfigController) AddCreatorAsAdmin(ctx context.Context) (bool, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.missingConfig {
+		return false, fmt.Errorf("ProjectWorkspaceConfig is missing")
+	}
+	return c.addCreatorAsAdmin, nil
+}
+
+func (c *PWOConfigController) ProjectAdminsManageWorkspaces(ctx context.Context) (bool, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.missingConfig {
+		return false, fmt.Errorf("ProjectWorkspaceConfig is missing")
+	}
+	return c.projectAdminsManageWorkspaces, nil
+}
+
+func (c *PWOConfigController) OperatorIdentities(ctx context.Context) (sets.Set[string], error) {
+	c.lock.RLock()
+	cfg, missing := c.operatorIdentities, c.missingConfig
+	c.lock.RUnlock()
+	if missing {
+		return nil, fmt.Errorf("ProjectWorkspaceConfig is missing")
+	}
+	if cfg == nil {
+		return nil, nil
+	}
+	return c.operatorIdentityCache.get(ctx, c.OnboardingClusterAccessStatic.Client(), cfg)
+}
+
+func (c *PWOConfigController) ChargingTarget(ctx context.Context) (pwv1alpha1.ChargingTargetConfig, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.missingConfig {
+		return pwv1alpha1.ChargingTargetConfig{}, fmt.Errorf("ProjectWorkspaceConfig is missing")
+	}
+	return *c.chargingTarget.DeepCopy(), nil
+}
+
+func (c *PWOConfigController) MemberPolicy(ctx context.Context) (pwv1alpha1.MemberPolicyConfig, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.missingConfig {
+		return pwv1alpha1.MemberPolicyConfig{}, fmt.Errorf("ProjectWorkspaceConfig is missing")
+	}
+	return c.memberPolicy, nil
+}
+
+func (c *PWOConfigController) WebhookLookups(ctx context.Context) (pwv1alpha1.WebhookLookupsConfig, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.missingConfig {
+		return pwv1alpha1.WebhookLookupsConfig{}, fmt.Errorf("ProjectWorkspaceConfig is missing")
+	}
+	return *c.webhookLookups.DeepCopy(), nil
+}
+
+func (c *PWOConfigController) AllowedChargingTargets(ctx context.Context) (sets.Set[string], error) {
+	cfg, err := c.ChargingTarget(ctx)
+	if err != nil || cfg.AllowedValuesConfigMapName == "" {
+		return nil, err
+	}
+	key := client.ObjectKey{Name: cfg.AllowedValuesConfigMapName, Namespace: c.podNamespace}
+	return c.chargingTargets.get(ctx, c.platformCluster.Client(), key, cfg.GetCacheDuration())
+}
+
+func (c *PWOConfigController) ManagementLabels(ctx context.Context) (pwv1alpha1.ManagementLabelsConfig, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.missingConfig {
+		return pwv1alpha1.ManagementLabelsConfig{}, fmt.Errorf("ProjectWorkspaceConfig is missing")
+	}
+	return *c.managementLabels.DeepCopy(), nil
+}
+
+func (c *PWOConfigController) AutomationServiceAccount(ctx context.Context) (pwv1alpha1.AutomationServiceAccountConfig, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.missingConfig {
+		return pwv1alpha1.AutomationServiceAccountConfig{}, fmt.Errorf("ProjectWorkspaceConfig is missing")
+	}
+	return *c.automationServiceAccount.DeepCopy(), nil
+}
+
+func (c *PWOConfigController) WorkspaceNetworkPolicies(ctx context.Context) ([]pwv1alpha1.NetworkPolicyTemplate, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.missingConfig {
+		return nil, fmt.Errorf("ProjectWorkspaceConfig is missing")
+	}
+	return cloneNetworkPolicyTemplates(c.workspaceNetworkPolicies), nil
+}
+
+func (c *PWOConfigController) WorkspaceAdditionalResources(ctx context.Context) ([]pwv1alpha1.ResourceTemplate, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.missingConfig {
+		return nil, fmt.Errorf("ProjectWorkspaceConfig is missing")
+	}
+	return cloneResourceTemplates(c.workspaceAdditionalResources), nil
+}
+
+func (c *PWOConfigController) FlatWorkspaces(ctx context.Context) (bool, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.missingConfig {
+		return false, fmt.Errorf("ProjectWorkspaceConfig is missing")
+	}
+	return c.flatWorkspaces, nil
+}
+
+func (c *PWOConfigController) ConsolidatedProjectClusterRoles(ctx context.Context) (bool, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.missingConfig {
+		return false, fmt.Errorf("ProjectWorkspaceConfig is missing")
+	}
+	return c.consolidatedProjectClusterRoles, nil
+}
+
+func (c *PWOConfigController) ProjectDeletionGracePeriod(ctx context.Context) (time.Duration, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.missingConfig {
+		return 0, fmt.Errorf("ProjectWorkspaceConfig is missing")
+	}
+	return c.projectDeletionGracePeriod, nil
+}
+
+func (c *PWOConfigController) ServiceAccountMembers(ctx context.Context) (pwv1alpha1.ServiceAccountMembersConfig, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.missingConfig {
+		return pwv1alpha1.ServiceAccountMembersConfig{}, fmt.Errorf("ProjectWorkspaceConfig is missing")
+	}
+	return *c.serviceAccountMembers.DeepCopy(), nil
+}
+
+func (c *PWOConfigController) SecretStores(ctx context.Context) ([]pwv1alpha1.SecretStoreConfig, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.missingConfig {
+		return nil, fmt.Errorf("ProjectWorkspaceConfig is missing")
+	}
+	return slices.Clone(c.secretStores), nil
+}
+
+func (c *PWOConfigController) AllowedProjectResourcesBlockingDeletion(ctx context.Context) ([]metav1.GroupVersionKind, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.missingConfig {
+		return nil, fmt.Errorf("ProjectWorkspaceConfig is missing")
+	}
+	return slices.Clone(c.allowedProjectBlockingResources), nil
+}
+
+func (c *PWOConfigController) WorkspaceProviderHints(ctx context.Context) ([]pwv1alpha1.ProviderHintConfig, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.missingConfig {
+		return nil, fmt.Errorf("ProjectWorkspaceConfig is missing")
+	}
+	res := make([]pwv1alpha1.ProviderHintConfig, len(c.workspaceProviderHints))
+	for i := range c.workspaceProviderHints {
+		c.workspaceProviderHints[i].DeepCopyInto(&res[i])
+	}
+	return res, nil
+}
+
+func (c *PWOConfigController) WorkspacePodSecurity(ctx context.Context) (*pwv1alpha1.PodSecurityConfig, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.missingConfig {
+		return nil, fmt.Errorf("ProjectWorkspaceConfig is missing")
+	}
+	return c.workspacePodSecurity.DeepCopy(), nil
+}
+
+func (c *PWOConfigController) MaxProjectsPerCreator(ctx context.Context) (*int32, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.missingConfig {
+		return nil, fmt.Errorf("ProjectWorkspaceConfig is missing")
+	}
+	return maxProjectsPerCreatorFromConfig(c.maxProjectsPerCreator), nil
+}
+
 func (c *PWOConfigController) ResourcesBlockingProjectDeletion(ctx context.Context) ([]DeletionBlockingResource, error) {
 	c.lock.RLock()
 	defer c.lock.RUnlock()
@@ -437,29 +1204,90 @@ func (c *PWOConfigController) ResourcesBlockingWorkspaceDeletion(ctx context.Con
 func (c *PWOConfigController) resourcesBlockingWorkspaceDeletionInternal() []DeletionBlockingResource {
 	res := BuiltinResourcesBlockingWorkspaceDeletion()
 	res = append(res, c.resourcesBlockingWorkspaceDeletion...)
-	return res
+	return WithBlockingResourcePolicies(c.workspaceBlockingResourcePolicies, res)
+}
+
+func (c *PWOConfigController) ProjectDeletionIgnoreRules(ctx context.Context) ([]pwv1alpha1.DeletionIgnoreRule, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.missingConfig {
+		return nil, fmt.Errorf("ProjectWorkspaceConfig is missing")
+	}
+	return slices.Clone(c.projectDeletionIgnoreRules), nil
 }
 
+func (c *PWOConfigController) WorkspaceDeletionIgnoreRules(ctx context.Context) ([]pwv1alpha1.DeletionIgnoreRule, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.missingConfig {
+		return nil, fmt.Errorf("ProjectWorkspaceConfig is missing")
+	}
+	return slices.Clone(c.workspaceDeletionIgnoreRules), nil
+}
+
+func (c *PWOConfigController) WorkspaceBlockingResourcePolicies(ctx context.Context) ([]pwv1alpha1.BlockingResourcePolicyRule, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.missingConfig {
+		return nil, fmt.Errorf("ProjectWorkspaceConfig is missing")
+	}
+	return slices.Clone(c.workspaceBlockingResourcePolicies), nil
+}
+
+func (c *PWOConfigController) ProjectPermissionsForRole(ctx context.Context, roleID string) ([]rbacv1.PolicyRule, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.missingConfig {
+		return nil, fmt.Errorf("ProjectWorkspaceConfig is missing")
+	}
+	return c.projectPermissionsForRoleInternal(roleID)
+}
 func (c *PWOConfigController) projectPermissionsForRoleInternal(roleID string) ([]rbacv1.PolicyRule, error) {
+	return projectPermissionsForRole(roleID, c.permissibleProjectResources, c.projectPermissionsFromConfig, c.projectAuditorExcludedResources)
+}
+
+func (c *PWOConfigController) WorkspacePermissionsForRole(ctx context.Context, roleID string) ([]rbacv1.PolicyRule, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.missingConfig {
+		return nil, fmt.Errorf("ProjectWorkspaceConfig is missing")
+	}
+	return c.workspacePermissionsForRoleInternal(roleID)
+}
+func (c *PWOConfigController) workspacePermissionsForRoleInternal(roleID string) ([]rbacv1.PolicyRule, error) {
+	return workspacePermissionsForRole(roleID, c.permissibleWorkspaceResources, c.workspacePermissionsFromConfig, c.workspaceAuditorExcludedResources)
+}
+
+// projectPermissionsForRole merges the builtin project permissions with the given permissible resources and the permissions from the config for the given role.
+// For the 'auditor' role, the given excluded resources are removed before the permissions from the config are added.
+func projectPermissionsForRole(roleID string, permissibleResources []rbacv1.PolicyRule, permissionsFromConfig map[string][]rbacv1.PolicyRule, auditorExcludedResources []metav1.GroupResource) ([]rbacv1.PolicyRule, error) {
 	res := BuiltinPermissibleProjectResources()
 	if roleID == utils.AdminRoleID {
 		res = AppendPolicyRules(res, BuiltinPermissibleProjectResourcesAdminOnly()...)
 	}
-	res = AppendPolicyRules(res, c.permissibleProjectResources...)
-	res = AppendPolicyRules(res, c.projectPermissionsFromConfig[roleID]...)
+	res = AppendPolicyRules(res, permissibleResources...)
+	if roleID == utils.AuditorRoleID {
+		res = RemoveExcludedResources(res, auditorExcludedResources)
+	}
+	res = AppendPolicyRules(res, permissionsFromConfig[roleID]...)
 	if err := InjectMissingVerbs(roleID, res); err != nil {
 		return nil, fmt.Errorf("error injecting missing verbs for project role '%s': %w", roleID, err)
 	}
 	return res, nil
 }
 
-func (c *PWOConfigController) workspacePermissionsForRoleInternal(roleID string) ([]rbacv1.PolicyRule, error) {
+// workspacePermissionsForRole merges the builtin workspace permissions with the given permissible resources and the permissions from the config for the given role.
+// For the 'auditor' role, the given excluded resources are removed before the permissions from the config are added.
+func workspacePermissionsForRole(roleID string, permissibleResources []rbacv1.PolicyRule, permissionsFromConfig map[string][]rbacv1.PolicyRule, auditorExcludedResources []metav1.GroupResource) ([]rbacv1.PolicyRule, error) {
 	res := BuiltinPermissibleWorkspaceResources()
 	if roleID == utils.AdminRoleID {
 		res = AppendPolicyRules(res, BuiltinPermissibleWorkspaceResourcesAdminOnly()...)
 	}
-	res = AppendPolicyRules(res, c.permissibleWorkspaceResources...)
-	res = AppendPolicyRules(res, c.workspacePermissionsFromConfig[roleID]...)
+	res = AppendPolicyRules(res, permissibleResources...)
+	if roleID == utils.AuditorRoleID {
+		res = RemoveExcludedResources(res, auditorExcludedResources)
+	}
+	res = AppendPolicyRules(res, permissionsFromConfig[roleID]...)
 	if err := InjectMissingVerbs(roleID, res); err != nil {
 		return nil, fmt.Errorf("error injecting missing verbs for workspace role '%s': %w", roleID, err)
 	}
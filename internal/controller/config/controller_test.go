@@ -3,7 +3,6 @@ package config_test
 import (
 	"context"
 	"fmt"
-	"os"
 	"path/filepath"
 	"slices"
 	"time"
@@ -14,67 +13,36 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
-	fakediscovery "k8s.io/client-go/discovery/fake"
-	fakeclientset "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
 
-	"github.com/openmcp-project/controller-utils/pkg/clusters"
 	"github.com/openmcp-project/controller-utils/pkg/collections"
 	testutils "github.com/openmcp-project/controller-utils/pkg/testing"
 	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
-	commonapi "github.com/openmcp-project/openmcp-operator/api/common"
+	apiconst "github.com/openmcp-project/openmcp-operator/api/constants"
 	openmcpcorev2alpha1 "github.com/openmcp-project/openmcp-operator/api/core/v2alpha1"
 	providerv1alpha1 "github.com/openmcp-project/openmcp-operator/api/provider/v1alpha1"
-	"github.com/openmcp-project/openmcp-operator/lib/clusteraccess/advanced"
 
 	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
-	"github.com/openmcp-project/platform-service-project-workspace/api/v2/install"
 	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+	configtesting "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config/testing"
 	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
 )
 
 const (
-	platformClusterID   = "platform"
-	onboardingClusterID = "onboarding"
+	platformClusterID   = configtesting.PlatformClusterID
+	onboardingClusterID = configtesting.OnboardingClusterID
 
-	pwcRec       = "projectworkspaceconfig-controller"
-	providerName = "project-workspace"
-	podNamespace = "openmcp-system"
+	pwcRec       = configtesting.ReconcilerID
+	providerName = configtesting.DefaultProviderName
+	podNamespace = configtesting.DefaultPodNamespace
 )
 
-var onboardingScheme = install.InstallOperatorAPIsOnboarding(runtime.NewScheme())
-var platformScheme = install.InstallOperatorAPIsPlatform(runtime.NewScheme())
-
-// This is for the builtin deletion blocking resources
-var alwaysKnownAPIResources = []*metav1.APIResourceList{
-	{
-		GroupVersion: pwv1alpha1.GroupVersion.String(),
-		APIResources: []metav1.APIResource{
-			{
-				Name:       "workspaces",
-				Group:      pwv1alpha1.GroupVersion.Group,
-				Version:    pwv1alpha1.GroupVersion.Version,
-				Kind:       "Workspace",
-				Namespaced: true,
-			},
-		},
-	},
-	{
-		GroupVersion: openmcpcorev2alpha1.GroupVersion.String(),
-		APIResources: []metav1.APIResource{
-			{
-				Name:       "managedcontrolplanev2s",
-				Group:      openmcpcorev2alpha1.GroupVersion.Group,
-				Version:    openmcpcorev2alpha1.GroupVersion.Version,
-				Kind:       "ManagedControlPlaneV2",
-				Namespaced: true,
-			},
-		},
-	},
-}
 var alwaysExpectedDynamicAccessPermissions = []rbacv1.PolicyRule{
 	{
 		APIGroups: []string{sharedconfig.OpenMCPV1ApiGroup},
@@ -89,75 +57,12 @@ var alwaysExpectedDynamicAccessPermissions = []rbacv1.PolicyRule{
 }
 
 func defaultTestSetup(testDirPath string, knownAPIResources ...*metav1.APIResourceList) (*sharedconfig.PWOConfigController, *testutils.ComplexEnvironment) {
-	envb := testutils.NewComplexEnvironmentBuilder().
-		WithFakeClient(platformClusterID, platformScheme).
-		WithFakeClient(onboardingClusterID, onboardingScheme)
-	platformDirPath := filepath.Join(testDirPath, "platform")
-	pdi, err := os.Stat(platformDirPath)
-	if err != nil {
-		if !os.IsNotExist(err) {
-			panic(err)
-		}
-	} else {
-		if !pdi.IsDir() {
-			panic(fmt.Sprintf("expected platform test dir '%s' to be a directory", platformDirPath))
-		}
-		envb = envb.WithInitObjectPath(platformClusterID, platformDirPath)
-	}
-	onboardingDirPath := filepath.Join(testDirPath, "onboarding")
-	odi, err := os.Stat(onboardingDirPath)
-	if err != nil {
-		if !os.IsNotExist(err) {
-			panic(err)
-		}
-	} else {
-		if !odi.IsDir() {
-			panic(fmt.Sprintf("expected onboarding test dir '%s' to be a directory", onboardingDirPath))
-		}
-		envb = envb.WithInitObjectPath(onboardingClusterID, onboardingDirPath)
-	}
-	env := envb.
-		WithInitObjects(platformClusterID, &clustersv1alpha1.Cluster{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "onboarding",
-				Namespace: "default",
-			},
-		}).
-		WithDynamicObjectsWithStatus(platformClusterID, &clustersv1alpha1.AccessRequest{}).
-		WithReconcilerConstructor(pwcRec, func(c ...client.Client) reconcile.Reconciler {
-			pwc, err := sharedconfig.NewPWConfigController(providerName, clusters.NewTestClusterFromClient(platformClusterID, c[0]), clusters.NewTestClusterFromClient(onboardingClusterID, c[1]), &commonapi.ObjectReference{Name: "onboarding", Namespace: "default"}, nil, podNamespace)
-			Expect(err).ToNot(HaveOccurred(), "failed to create PWOConfigController")
-			pwc.Car.WithFakingCallback(advanced.FakingCallback_WaitingForAccessRequestReadiness, advanced.FakeAccessRequestReadiness())
-			pwc.Car.WithFakingCallback(advanced.FakingCallback_WaitingForAccessRequestDeletion, advanced.FakeAccessRequestDeletion([]string{"clusterprovider"}, nil))
-			pwc.Car.WithFakeClientGenerator(func(ctx context.Context, kcfgData []byte, scheme *runtime.Scheme, additionalData ...any) (client.Client, error) {
-				// this controller creates AccessRequests only for the onboarding cluster
-				// and the permissions are hard to test in unit tests anyway, so let's just return the static onboarding cluster client
-				return pwc.OnboardingClusterAccessStatic.Client(), nil
-			})
-			fd := fakeclientset.NewClientset().Discovery().(*fakediscovery.FakeDiscovery)
-			fd.Resources = knownAPIResources
-			for _, akrl := range alwaysKnownAPIResources {
-				found := false
-				for _, krl := range fd.Resources {
-					if krl.GroupVersion == akrl.GroupVersion {
-						krl.APIResources = append(krl.APIResources, akrl.APIResources...)
-						found = true
-						break
-					}
-				}
-				if !found {
-					fd.Resources = append(fd.Resources, akrl)
-				}
-			}
-			pwc.DiscoveryService = fd
-			return pwc
-		}, platformClusterID, onboardingClusterID).
+	return configtesting.NewPWOConfigControllerBuilder().
+		WithProviderName(providerName).
+		WithPodNamespace(podNamespace).
+		WithTestDataPath(testDirPath).
+		WithAPIResources(knownAPIResources...).
 		Build()
-
-	pwc, ok := env.Reconciler(pwcRec).(*sharedconfig.PWOConfigController)
-	Expect(ok).To(BeTrue(), "Reconciler is not of type *PWOConfigController")
-
-	return pwc, env
 }
 
 func sortPolicyRuleFields(rules []rbacv1.PolicyRule) []rbacv1.PolicyRule {
@@ -174,6 +79,17 @@ func sortPolicyRuleFields(rules []rbacv1.PolicyRule) []rbacv1.PolicyRule {
 	return res
 }
 
+// withPolicy returns copies of the given resources with the given policy.
+// None of the tests configures policies for the resources blocking workspace deletion, so all of them are expected to block it.
+func withPolicy(policy pwv1alpha1.BlockingResourcePolicy, resources []sharedconfig.DeletionBlockingResource) []sharedconfig.DeletionBlockingResource {
+	res := make([]sharedconfig.DeletionBlockingResource, len(resources))
+	for i := range resources {
+		res[i] = *resources[i].DeepCopy()
+		res[i].Policy = policy
+	}
+	return res
+}
+
 type expectedValues struct {
 	resourcesBlockingProjectDeletion   []sharedconfig.DeletionBlockingResource
 	resourcesBlockingWorkspaceDeletion []sharedconfig.DeletionBlockingResource
@@ -197,7 +113,7 @@ func (expected *expectedValues) validate(env *testutils.ComplexEnvironment, pwc
 
 	actualResourcesBlockingWorkspaceDeletion, err := pwc.ResourcesBlockingWorkspaceDeletion(env.Ctx)
 	ExpectWithOffset(1, err).ToNot(HaveOccurred())
-	ExpectWithOffset(1, actualResourcesBlockingWorkspaceDeletion).To(ConsistOf(expected.resourcesBlockingWorkspaceDeletion), "resources blocking workspace deletion do not match")
+	ExpectWithOffset(1, actualResourcesBlockingWorkspaceDeletion).To(ConsistOf(withPolicy(pwv1alpha1.BlockingResourcePolicyBlock, expected.resourcesBlockingWorkspaceDeletion)), "resources blocking workspace deletion do not match")
 
 	for role := range utils.ProjectRolesWithVerbs() {
 		cr := &rbacv1.ClusterRole{}
@@ -326,6 +242,28 @@ func defaultProjectPermissionsPerRole() map[pwv1alpha1.ProjectMemberRole][]rbacv
 				Verbs:     utils.ReadOnlyVerbs(),
 			},
 		},
+		pwv1alpha1.ProjectRoleAuditor: {
+			{
+				APIGroups: []string{pwv1alpha1.GroupName},
+				Resources: []string{"workspaces"},
+				Verbs:     utils.ReadOnlyVerbs(),
+			},
+			{
+				APIGroups: []string{corev1.GroupName},
+				Resources: []string{"serviceaccounts"},
+				Verbs:     utils.ReadOnlyVerbs(),
+			},
+			{
+				APIGroups: []string{corev1.GroupName},
+				Resources: []string{"pods"},
+				Verbs:     []string{"list"},
+			},
+			{
+				APIGroups: []string{corev1.GroupName},
+				Resources: []string{"resourcequotas"},
+				Verbs:     utils.ReadOnlyVerbs(),
+			},
+		},
 	}
 }
 
@@ -388,6 +326,32 @@ func defaultWorkspacePermissionsPerRole() map[pwv1alpha1.WorkspaceMemberRole][]r
 				Verbs:     utils.ReadOnlyVerbs(),
 			},
 		},
+		// secrets are excluded for auditors by default
+		pwv1alpha1.WorkspaceRoleAuditor: {
+			{
+				APIGroups: []string{openmcpcorev2alpha1.GroupName},
+				Resources: []string{"managedcontrolplanev2s"},
+				Verbs:     utils.ReadOnlyVerbs(),
+			},
+			{
+				APIGroups: []string{corev1.GroupName},
+				Resources: []string{
+					"configmaps",
+					"serviceaccounts",
+				},
+				Verbs: utils.ReadOnlyVerbs(),
+			},
+			{
+				APIGroups: []string{corev1.GroupName},
+				Resources: []string{"pods"},
+				Verbs:     []string{"list"},
+			},
+			{
+				APIGroups: []string{corev1.GroupName},
+				Resources: []string{"resourcequotas"},
+				Verbs:     utils.ReadOnlyVerbs(),
+			},
+		},
 	}
 }
 
@@ -411,6 +375,46 @@ var _ = Describe("ProjectWorkspaceConfig Controller Test", Serial, func() {
 		expected.validate(env, pwc)
 	})
 
+	It("should remove the reconcile operation annotation", func() {
+		pwc, env := defaultTestSetup(filepath.Join("testdata", "test-01"))
+
+		cfg := &pwv1alpha1.ProjectWorkspaceConfig{}
+		Expect(env.Client(platformClusterID).Get(env.Ctx, client.ObjectKey{Name: providerName}, cfg)).To(Succeed())
+		cfg.SetAnnotations(map[string]string{apiconst.OperationAnnotation: apiconst.OperationAnnotationValueReconcile})
+		Expect(env.Client(platformClusterID).Update(env.Ctx, cfg)).To(Succeed())
+
+		expected := &expectedValues{}
+		expected.resourcesBlockingProjectDeletion = sharedconfig.BuiltinResourcesBlockingProjectDeletion()
+		expected.resourcesBlockingWorkspaceDeletion = sharedconfig.BuiltinResourcesBlockingWorkspaceDeletion()
+		expected.projectPermissionsPerRole = defaultProjectPermissionsPerRole()
+		expected.workspacePermissionsPerRole = defaultWorkspacePermissionsPerRole()
+		expected.validate(env, pwc)
+
+		Expect(env.Client(platformClusterID).Get(env.Ctx, client.ObjectKey{Name: providerName}, cfg)).To(Succeed())
+		Expect(cfg.GetAnnotations()).ToNot(HaveKey(apiconst.OperationAnnotation))
+	})
+
+	It("should document the effective permissions of the roles in a ConfigMap", func() {
+		pwc, env := defaultTestSetup(filepath.Join("testdata", "test-01"))
+		env.ShouldReconcile(pwcRec, testutils.RequestFromStrings(providerName))
+
+		cm := &corev1.ConfigMap{}
+		Expect(env.Client(platformClusterID).Get(env.Ctx, client.ObjectKey{Name: sharedconfig.PermissionsConfigMapName(providerName), Namespace: podNamespace}, cm)).To(Succeed())
+		Expect(cm.Data).To(HaveLen(len(utils.ProjectRolesWithVerbs()) + len(utils.WorkspaceRolesWithVerbs())))
+
+		for role := range utils.WorkspaceRolesWithVerbs() {
+			roleID := utils.WorkspaceMemberRoleToRoleID(role)
+			Expect(cm.Data).To(HaveKey(fmt.Sprintf("workspace-%s.yaml", roleID)))
+			cr := &rbacv1.ClusterRole{}
+			Expect(yaml.Unmarshal([]byte(cm.Data[fmt.Sprintf("workspace-%s.yaml", roleID)]), cr)).To(Succeed())
+			Expect(cr.Name).To(Equal(utils.ClusterRoleForRole(role)))
+			expectedRules, err := pwc.WorkspacePermissionsForRole(env.Ctx, roleID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cr.Rules).To(Equal(expectedRules))
+		}
+		Expect(cm.Data).To(HaveKey(fmt.Sprintf("project-%s.yaml", utils.AdminRoleID)))
+	})
+
 	It("should add the v1 resources, if v1 support is enabled", func() {
 		sharedconfig.SupportV1 = true
 		pwc, env := defaultTestSetup(filepath.Join("testdata", "test-01"), &metav1.APIResourceList{
@@ -489,6 +493,48 @@ var _ = Describe("ProjectWorkspaceConfig Controller Test", Serial, func() {
 		expected.validate(env, pwc)
 	})
 
+	It("should request dynamic access for the resources blocking the deletion of workspaces of a WorkspaceClass", func() {
+		pwc, env := configtesting.NewPWOConfigControllerBuilder().
+			WithProviderName(providerName).
+			WithPodNamespace(podNamespace).
+			WithTestDataPath(filepath.Join("testdata", "test-01")).
+			WithOnboardingObjects(&pwv1alpha1.WorkspaceClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "secure"},
+				Spec: pwv1alpha1.WorkspaceClassSpec{
+					ResourcesBlockingDeletion: []metav1.GroupVersionKind{{Group: "backup.example.com", Version: "v1", Kind: "Backup"}},
+				},
+			}).
+			WithAPIResources(&metav1.APIResourceList{
+				GroupVersion: "backup.example.com/v1",
+				APIResources: []metav1.APIResource{
+					{
+						Name:       "backups",
+						Group:      "backup.example.com",
+						Version:    "v1",
+						Kind:       "Backup",
+						Namespaced: true,
+					},
+				},
+			}).
+			Build()
+
+		expected := &expectedValues{}
+		expected.resourcesBlockingProjectDeletion = sharedconfig.BuiltinResourcesBlockingProjectDeletion()
+		// the resources of a class are not part of the config, they are added by the workspace controller for the workspaces of the class
+		expected.resourcesBlockingWorkspaceDeletion = sharedconfig.BuiltinResourcesBlockingWorkspaceDeletion()
+		expected.projectPermissionsPerRole = defaultProjectPermissionsPerRole()
+		expected.workspacePermissionsPerRole = defaultWorkspacePermissionsPerRole()
+		expected.dynamicAccessPermissions = []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{"backup.example.com"},
+				Resources: []string{"backups", "backups/status"},
+				Verbs:     utils.ReadOnlyVerbs(),
+			},
+		}
+
+		expected.validate(env, pwc)
+	})
+
 	It("should correctly handle empty config with ServiceProviders", func() {
 		pwc, env := defaultTestSetup(filepath.Join("testdata", "test-02"), &metav1.APIResourceList{
 			GroupVersion: "v1",
@@ -549,6 +595,13 @@ var _ = Describe("ProjectWorkspaceConfig Controller Test", Serial, func() {
 				Verbs:     utils.ReadOnlyVerbs(),
 			},
 		)
+		expected.workspacePermissionsPerRole[pwv1alpha1.WorkspaceRoleAuditor] = sharedconfig.AppendPolicyRules(expected.workspacePermissionsPerRole[pwv1alpha1.WorkspaceRoleAuditor],
+			rbacv1.PolicyRule{
+				APIGroups: []string{""},
+				Resources: []string{"services", "pods"},
+				Verbs:     utils.ReadOnlyVerbs(),
+			},
+		)
 
 		expected.dynamicAccessPermissions = []rbacv1.PolicyRule{
 			{
@@ -561,6 +614,83 @@ var _ = Describe("ProjectWorkspaceConfig Controller Test", Serial, func() {
 		expected.validate(env, pwc)
 	})
 
+	It("should show the discovered resources of the ServiceProviders in the status", func() {
+		_, env := defaultTestSetup(filepath.Join("testdata", "test-02"), &metav1.APIResourceList{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{
+					Name:       "services",
+					Group:      "",
+					Version:    "v1",
+					Kind:       "Service",
+					Namespaced: true,
+				},
+			},
+		})
+		req := testutils.RequestFromStrings(providerName)
+
+		// the resource of the second ServiceProvider cannot be discovered
+		env.ShouldNotReconcile(pwcRec, req)
+
+		cfg := &pwv1alpha1.ProjectWorkspaceConfig{}
+		Expect(env.Client(platformClusterID).Get(env.Ctx, client.ObjectKey{Name: providerName}, cfg)).To(Succeed())
+		Expect(cfg.Status.LastDiscoveryTime).ToNot(BeNil())
+		Expect(cfg.Status.ServiceProviders).To(ConsistOf(
+			pwv1alpha1.ServiceProviderResources{
+				Name: "dummy-1",
+				Resources: []pwv1alpha1.DiscoveredResource{
+					{GroupVersionKind: metav1.GroupVersionKind{Version: "v1", Kind: "Service"}, Resource: "services"},
+				},
+			},
+			MatchFields(IgnoreExtras, Fields{
+				"Name": Equal("dummy-2"),
+				"Resources": ConsistOf(MatchFields(IgnoreExtras, Fields{
+					"GroupVersionKind": Equal(metav1.GroupVersionKind{Version: "v1", Kind: "Pod"}),
+					"Resource":         BeEmpty(),
+					"Error":            ContainSubstring("registered by ServiceProvider 'dummy-2'"),
+				})),
+			}),
+		))
+	})
+
+	It("should only return the provider hints of registered ServiceProviders", func() {
+		pwc, env := defaultTestSetup(filepath.Join("testdata", "test-02"), &metav1.APIResourceList{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{
+					Name:       "services",
+					Group:      "",
+					Version:    "v1",
+					Kind:       "Service",
+					Namespaced: true,
+				},
+				{
+					Name:       "pods",
+					Group:      "",
+					Version:    "v1",
+					Kind:       "Pod",
+					Namespaced: true,
+				},
+			},
+		})
+		req := testutils.RequestFromStrings(providerName)
+
+		cfg := &pwv1alpha1.ProjectWorkspaceConfig{}
+		Expect(env.Client(platformClusterID).Get(env.Ctx, client.ObjectKey{Name: providerName}, cfg)).To(Succeed())
+		cfg.Spec.Workspace.ProviderHints = []pwv1alpha1.ProviderHintConfig{
+			{Key: "region", ServiceProvider: "dummy-1", AllowedValues: []string{"eu10"}},
+			{Key: "tier", ServiceProvider: "missing"},
+		}
+		Expect(env.Client(platformClusterID).Update(env.Ctx, cfg)).To(Succeed())
+		env.ShouldReconcile(pwcRec, req)
+
+		hints, err := pwc.WorkspaceProviderHints(env.Ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hints).To(Equal([]pwv1alpha1.ProviderHintConfig{
+			{Key: "region", ServiceProvider: "dummy-1", AllowedValues: []string{"eu10"}},
+		}))
+	})
+
 	It("should correctly handle non-empty config without ServiceProviders", func() {
 		pwc, env := defaultTestSetup(filepath.Join("testdata", "test-03"), &metav1.APIResourceList{
 			GroupVersion: "mygroup.project/v1alpha1",
@@ -814,6 +944,13 @@ var _ = Describe("ProjectWorkspaceConfig Controller Test", Serial, func() {
 				Verbs:     utils.ReadOnlyVerbs(),
 			},
 		)
+		originallyExpected.workspacePermissionsPerRole[pwv1alpha1.WorkspaceRoleAuditor] = sharedconfig.AppendPolicyRules(originallyExpected.workspacePermissionsPerRole[pwv1alpha1.WorkspaceRoleAuditor],
+			rbacv1.PolicyRule{
+				APIGroups: []string{""},
+				Resources: []string{"services"},
+				Verbs:     utils.ReadOnlyVerbs(),
+			},
+		)
 
 		originallyExpected.dynamicAccessPermissions = []rbacv1.PolicyRule{
 			{
@@ -869,6 +1006,13 @@ var _ = Describe("ProjectWorkspaceConfig Controller Test", Serial, func() {
 				Verbs:     utils.ReadOnlyVerbs(),
 			},
 		)
+		expected.workspacePermissionsPerRole[pwv1alpha1.WorkspaceRoleAuditor] = sharedconfig.AppendPolicyRules(expected.workspacePermissionsPerRole[pwv1alpha1.WorkspaceRoleAuditor],
+			rbacv1.PolicyRule{
+				APIGroups: []string{""},
+				Resources: []string{"pods"},
+				Verbs:     utils.ReadOnlyVerbs(),
+			},
+		)
 
 		expected.dynamicAccessPermissions = append(expected.dynamicAccessPermissions, rbacv1.PolicyRule{
 			APIGroups: []string{""},
@@ -882,6 +1026,8 @@ var _ = Describe("ProjectWorkspaceConfig Controller Test", Serial, func() {
 		originallyExpected.validate(env, pwc)
 
 		expected = originallyExpected.clone()
+		// the config is modified by the reconciliations, e.g. by adding the finalizer, so it has to be read again before each update
+		Expect(env.Client(platformClusterID).Get(env.Ctx, client.ObjectKeyFromObject(cfg), cfg)).To(Succeed())
 		// modifying the config by adding project and workspace viewer permissions should modify the permissions accordingly
 		cfg.Spec.Project.AdditionalPermissions[pwv1alpha1.ProjectRoleView] = []rbacv1.PolicyRule{
 			{
@@ -903,10 +1049,326 @@ var _ = Describe("ProjectWorkspaceConfig Controller Test", Serial, func() {
 		expected.validate(env, pwc)
 
 		// removing the additional permissions again should undo that change
+		Expect(env.Client(platformClusterID).Get(env.Ctx, client.ObjectKeyFromObject(cfg), cfg)).To(Succeed())
 		delete(cfg.Spec.Project.AdditionalPermissions, pwv1alpha1.ProjectRoleView)
 		delete(cfg.Spec.Workspace.AdditionalPermissions, pwv1alpha1.WorkspaceRoleView)
 		Expect(env.Client(platformClusterID).Update(env.Ctx, cfg)).To(Succeed())
 		originallyExpected.validate(env, pwc)
 	})
 
+	It("should merge the ProjectWorkspaceConfigOverride for the configured environment", func() {
+		pwc, env := defaultTestSetup(filepath.Join("testdata", "test-05"), &metav1.APIResourceList{
+			GroupVersion: "mygroup.workspace/v1alpha1",
+			APIResources: []metav1.APIResource{
+				{
+					Name:       "myworkspaceblockingresources1",
+					Group:      "mygroup.workspace",
+					Version:    "v1alpha1",
+					Kind:       "MyWorkspaceBlockingResource1",
+					Namespaced: true,
+				},
+				{
+					Name:       "myworkspaceblockingresources2",
+					Group:      "mygroup.workspace",
+					Version:    "v1alpha1",
+					Kind:       "MyWorkspaceBlockingResource2",
+					Namespaced: true,
+				},
+			},
+		})
+		pwc.Environment = "canary"
+
+		blockingResource := func(kind string) sharedconfig.DeletionBlockingResource {
+			return sharedconfig.DeletionBlockingResource{
+				GroupVersionKind: metav1.GroupVersionKind{
+					Group:   "mygroup.workspace",
+					Version: "v1alpha1",
+					Kind:    kind,
+				},
+				Source: pwv1alpha1.SourceProjectWorkspaceConfig,
+			}
+		}
+
+		// the override for the 'canary' environment replaces the blocking workspace resources, the one for 'dev' is ignored
+		expected := &expectedValues{}
+		expected.resourcesBlockingProjectDeletion = sharedconfig.BuiltinResourcesBlockingProjectDeletion()
+		expected.resourcesBlockingWorkspaceDeletion = append(sharedconfig.BuiltinResourcesBlockingWorkspaceDeletion(), blockingResource("MyWorkspaceBlockingResource2"))
+		expected.projectPermissionsPerRole = defaultProjectPermissionsPerRole()
+		expected.workspacePermissionsPerRole = defaultWorkspacePermissionsPerRole()
+		expected.dynamicAccessPermissions = []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{"mygroup.workspace"},
+				Resources: []string{"myworkspaceblockingresources2"},
+				Verbs:     utils.ReadOnlyVerbs(),
+			},
+		}
+		expected.validate(env, pwc)
+
+		// without the override, the values from the config are used again
+		override := &pwv1alpha1.ProjectWorkspaceConfigOverride{}
+		Expect(env.Client(platformClusterID).Get(env.Ctx, client.ObjectKey{Name: "canary", Namespace: podNamespace}, override)).To(Succeed())
+		Expect(env.Client(platformClusterID).Delete(env.Ctx, override)).To(Succeed())
+
+		expected = expected.clone()
+		expected.resourcesBlockingWorkspaceDeletion = append(sharedconfig.BuiltinResourcesBlockingWorkspaceDeletion(), blockingResource("MyWorkspaceBlockingResource1"))
+		expected.dynamicAccessPermissions = []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{"mygroup.workspace"},
+				Resources: []string{"myworkspaceblockingresources1"},
+				Verbs:     utils.ReadOnlyVerbs(),
+			},
+		}
+		expected.validate(env, pwc)
+	})
+
+	It("should add the member overrides of the selected MemberOverrideSets", func() {
+		pwc, env := defaultTestSetup(filepath.Join("testdata", "test-01"))
+		req := testutils.RequestFromStrings(providerName)
+		override := func(name string) pwv1alpha1.MemberOverride {
+			return pwv1alpha1.MemberOverride{Subject: pwv1alpha1.Subject{Kind: rbacv1.GroupKind, Name: name}, Roles: []pwv1alpha1.OverrideRole{pwv1alpha1.OverrideRoleAdmin}}
+		}
+		for name, scope := range map[string]string{"central": "central", "region-a": "regional", "region-b": "regional"} {
+			Expect(env.Client(platformClusterID).Create(env.Ctx, &pwv1alpha1.MemberOverrideSet{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"scope": scope}},
+				Spec:       pwv1alpha1.MemberOverrideSetSpec{Overrides: pwv1alpha1.MemberOverrides{override(name + "-admins")}},
+			})).To(Succeed())
+		}
+
+		// the sets are ignored without selector
+		Eventually(env.ShouldReconcile).WithArguments(pwcRec, req).Should(WithTransform(func(rr reconcile.Result) time.Duration { return rr.RequeueAfter }, BeZero()))
+		overrides, err := pwc.MemberOverrides(env.Ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(overrides).To(BeEmpty())
+
+		cfg := &pwv1alpha1.ProjectWorkspaceConfig{}
+		Expect(env.Client(platformClusterID).Get(env.Ctx, client.ObjectKey{Name: providerName}, cfg)).To(Succeed())
+		cfg.Spec.MemberOverrides = pwv1alpha1.MemberOverrides{override("config-admins")}
+		cfg.Spec.MemberOverrideSetSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"scope": "regional"}}
+		Expect(env.Client(platformClusterID).Update(env.Ctx, cfg)).To(Succeed())
+		env.ShouldReconcile(pwcRec, req)
+
+		overrides, err = pwc.MemberOverrides(env.Ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(overrides).To(Equal(pwv1alpha1.MemberOverrides{override("config-admins"), override("region-a-admins"), override("region-b-admins")}))
+	})
+
+	It("should return the charging target validation and cache the allowed charging targets", func() {
+		pwc, env := defaultTestSetup(filepath.Join("testdata", "test-06"))
+		req := testutils.RequestFromStrings(providerName)
+		Eventually(env.ShouldReconcile).WithArguments(pwcRec, req).Should(WithTransform(func(rr reconcile.Result) time.Duration { return rr.RequeueAfter }, BeZero()))
+
+		ct, err := pwc.ChargingTarget(env.Ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ct.Required).To(BeTrue())
+		Expect(ct.Pattern).To(Equal("cc-[0-9]{4}"))
+		Expect(ct.GetCacheDuration()).To(Equal(time.Hour))
+
+		allowed, err := pwc.AllowedChargingTargets(env.Ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sets.List(allowed)).To(Equal([]string{"cc-1234", "cc-5678", "cc-9012"}))
+
+		// changes to the ConfigMap only become visible after the cache duration
+		cm := &corev1.ConfigMap{}
+		Expect(env.Client(platformClusterID).Get(env.Ctx, client.ObjectKey{Name: "charging-targets", Namespace: podNamespace}, cm)).To(Succeed())
+		cm.Data[pwv1alpha1.ChargingTargetAllowedValuesKey] = "cc-0000"
+		Expect(env.Client(platformClusterID).Update(env.Ctx, cm)).To(Succeed())
+		allowed, err = pwc.AllowedChargingTargets(env.Ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allowed.Has("cc-1234")).To(BeTrue())
+		Expect(allowed.Has("cc-0000")).To(BeFalse())
+	})
+
+	It("should return and cache the selected operator identities", func() {
+		pwc, env := defaultTestSetup(filepath.Join("testdata", "test-07"))
+		req := testutils.RequestFromStrings(providerName)
+		Eventually(env.ShouldReconcile).WithArguments(pwcRec, req).Should(WithTransform(func(rr reconcile.Result) time.Duration { return rr.RequeueAfter }, BeZero()))
+
+		identities, err := pwc.OperatorIdentities(env.Ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sets.List(identities)).To(Equal([]string{"system:serviceaccount:zone-a:project-workspace"}))
+
+		// new ServiceAccounts only become visible after the refresh interval
+		Expect(env.Client(onboardingClusterID).Create(env.Ctx, &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+			Name:      "project-workspace",
+			Namespace: "zone-b",
+			Labels:    map[string]string{"app.kubernetes.io/name": "project-workspace"},
+		}})).To(Succeed())
+		identities, err = pwc.OperatorIdentities(env.Ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(identities.Has("system:serviceaccount:zone-b:project-workspace")).To(BeFalse())
+	})
+
+	It("should enqueue all projects and workspaces if the config changes", func() {
+		pwc, env := defaultTestSetup(filepath.Join("testdata", "test-01"))
+		projectEvents := pwc.ProjectEvents()
+		workspaceEvents := pwc.WorkspaceEvents()
+		req := testutils.RequestFromStrings(providerName)
+		eventName := func(e event.GenericEvent) string { return e.Object.GetNamespace() + "/" + e.Object.GetName() }
+
+		Expect(env.Client(onboardingClusterID).Create(env.Ctx, &pwv1alpha1.Project{ObjectMeta: metav1.ObjectMeta{Name: "alpha"}})).To(Succeed())
+		Expect(env.Client(onboardingClusterID).Create(env.Ctx, &pwv1alpha1.Project{ObjectMeta: metav1.ObjectMeta{
+			Name:        "beta",
+			Annotations: map[string]string{apiconst.OperationAnnotation: apiconst.OperationAnnotationValueIgnore},
+		}})).To(Succeed())
+		Expect(env.Client(onboardingClusterID).Create(env.Ctx, &pwv1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "dev", Namespace: "project-alpha"}})).To(Succeed())
+
+		// nothing is enqueued when the config is loaded for the first time
+		Eventually(env.ShouldReconcile).WithArguments(pwcRec, req).Should(WithTransform(func(rr reconcile.Result) time.Duration { return rr.RequeueAfter }, BeZero()))
+		Consistently(projectEvents).WithTimeout(200 * time.Millisecond).ShouldNot(Receive())
+		Consistently(workspaceEvents).WithTimeout(200 * time.Millisecond).ShouldNot(Receive())
+
+		// nothing is enqueued if the config did not change
+		env.ShouldReconcile(pwcRec, req)
+		Consistently(projectEvents).WithTimeout(200 * time.Millisecond).ShouldNot(Receive())
+
+		// all projects and workspaces without the ignore annotation are enqueued if the permissions change
+		cfg := &pwv1alpha1.ProjectWorkspaceConfig{}
+		Expect(env.Client(platformClusterID).Get(env.Ctx, client.ObjectKey{Name: providerName}, cfg)).To(Succeed())
+		cfg.Spec.Project.AdditionalPermissions = map[pwv1alpha1.ProjectMemberRole][]rbacv1.PolicyRule{
+			pwv1alpha1.ProjectRoleAdmin: {
+				{
+					APIGroups: []string{"mygroup.project"},
+					Resources: []string{"myprojectresources"},
+					Verbs:     []string{"get"},
+				},
+			},
+		}
+		Expect(env.Client(platformClusterID).Update(env.Ctx, cfg)).To(Succeed())
+		env.ShouldReconcile(pwcRec, req)
+
+		Eventually(projectEvents).Should(Receive(WithTransform(eventName, Equal("/alpha"))))
+		Eventually(workspaceEvents).Should(Receive(WithTransform(eventName, Equal("project-alpha/dev"))))
+		Consistently(projectEvents).WithTimeout(200 * time.Millisecond).ShouldNot(Receive())
+		Consistently(workspaceEvents).WithTimeout(200 * time.Millisecond).ShouldNot(Receive())
+	})
+
+	It("should enqueue all projects and workspaces on resync", func() {
+		pwc, env := defaultTestSetup(filepath.Join("testdata", "test-01"))
+		projectEvents := pwc.ProjectEvents()
+		workspaceEvents := pwc.WorkspaceEvents()
+		req := testutils.RequestFromStrings(providerName)
+		eventName := func(e event.GenericEvent) string { return e.Object.GetNamespace() + "/" + e.Object.GetName() }
+
+		Expect(env.Client(onboardingClusterID).Create(env.Ctx, &pwv1alpha1.Project{ObjectMeta: metav1.ObjectMeta{Name: "alpha"}})).To(Succeed())
+		Expect(env.Client(onboardingClusterID).Create(env.Ctx, &pwv1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "dev", Namespace: "project-alpha"}})).To(Succeed())
+
+		// nothing is enqueued before the config has been loaded
+		pwc.Resync(env.Ctx)
+		Consistently(projectEvents).WithTimeout(200 * time.Millisecond).ShouldNot(Receive())
+
+		// all projects and workspaces are enqueued, although the config did not change
+		Eventually(env.ShouldReconcile).WithArguments(pwcRec, req).Should(WithTransform(func(rr reconcile.Result) time.Duration { return rr.RequeueAfter }, BeZero()))
+		pwc.Resync(env.Ctx)
+		Eventually(projectEvents).Should(Receive(WithTransform(eventName, Equal("/alpha"))))
+		Eventually(workspaceEvents).Should(Receive(WithTransform(eventName, Equal("project-alpha/dev"))))
+		Consistently(projectEvents).WithTimeout(200 * time.Millisecond).ShouldNot(Receive())
+	})
+
+	It("should notify subscribers if the permissions or blocking resources change", func() {
+		pwc, env := defaultTestSetup(filepath.Join("testdata", "test-01"))
+		ctx, cancel := context.WithCancel(env.Ctx)
+		defer cancel()
+		changes := pwc.Subscribe(ctx)
+		req := testutils.RequestFromStrings(providerName)
+
+		// subscribers are not notified when the config is loaded for the first time or did not change
+		Eventually(env.ShouldReconcile).WithArguments(pwcRec, req).Should(WithTransform(func(rr reconcile.Result) time.Duration { return rr.RequeueAfter }, BeZero()))
+		env.ShouldReconcile(pwcRec, req)
+		Consistently(changes).WithTimeout(200 * time.Millisecond).ShouldNot(Receive())
+
+		cfg := &pwv1alpha1.ProjectWorkspaceConfig{}
+		Expect(env.Client(platformClusterID).Get(env.Ctx, client.ObjectKey{Name: providerName}, cfg)).To(Succeed())
+		cfg.Spec.Workspace.AdditionalPermissions = map[pwv1alpha1.WorkspaceMemberRole][]rbacv1.PolicyRule{
+			pwv1alpha1.WorkspaceRoleAdmin: {
+				{
+					APIGroups: []string{"mygroup.workspace"},
+					Resources: []string{"myworkspaceresources"},
+					Verbs:     []string{"get"},
+				},
+			},
+		}
+		Expect(env.Client(platformClusterID).Update(env.Ctx, cfg)).To(Succeed())
+		env.ShouldReconcile(pwcRec, req)
+		Eventually(changes).Should(Receive(Equal(sharedconfig.ChangePermissions)))
+
+		Expect(env.Client(platformClusterID).Get(env.Ctx, client.ObjectKey{Name: providerName}, cfg)).To(Succeed())
+		cfg.Spec.Project.IgnoredBlockingResources = []pwv1alpha1.DeletionIgnoreRule{{NamePatterns: []string{"sh.helm.release.v1.*"}}}
+		Expect(env.Client(platformClusterID).Update(env.Ctx, cfg)).To(Succeed())
+		env.ShouldReconcile(pwcRec, req)
+		Eventually(changes).Should(Receive(Equal(sharedconfig.ChangeBlockingResources)))
+	})
+
+	It("should restore deleted ClusterRoles of the project and workspace roles", func() {
+		_, env := defaultTestSetup(filepath.Join("testdata", "test-01"))
+		req := testutils.RequestFromStrings(providerName)
+		Eventually(env.ShouldReconcile).WithArguments(pwcRec, req).Should(WithTransform(func(rr reconcile.Result) time.Duration { return rr.RequeueAfter }, BeZero()))
+
+		cr := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: utils.ClusterRoleForRole(pwv1alpha1.ProjectRoleAdmin)}}
+		Expect(env.Client(onboardingClusterID).Get(env.Ctx, client.ObjectKeyFromObject(cr), cr)).To(Succeed())
+		Expect(cr.Labels).To(HaveKeyWithValue(utils.LabelBaseClusterRole, providerName))
+		rules := cr.Rules
+		Expect(env.Client(onboardingClusterID).Delete(env.Ctx, cr)).To(Succeed())
+
+		env.ShouldReconcile(pwcRec, req)
+		restored := &rbacv1.ClusterRole{}
+		Expect(env.Client(onboardingClusterID).Get(env.Ctx, client.ObjectKeyFromObject(cr), restored)).To(Succeed())
+		Expect(restored.Rules).To(Equal(rules))
+	})
+
+	It("should use the fallback config while the ProjectWorkspaceConfig does not exist", func() {
+		pwc, env := defaultTestSetup(filepath.Join("testdata", "test-01"))
+		pwc.FallbackConfigPath = filepath.Join("testdata", "fallback-config.yaml")
+		req := testutils.RequestFromStrings(providerName)
+
+		cfg := &pwv1alpha1.ProjectWorkspaceConfig{}
+		Expect(env.Client(platformClusterID).Get(env.Ctx, client.ObjectKey{Name: providerName}, cfg)).To(Succeed())
+		Expect(env.Client(platformClusterID).Delete(env.Ctx, cfg)).To(Succeed())
+
+		// the values are served from the file
+		Eventually(env.ShouldReconcile).WithArguments(pwcRec, req).Should(WithTransform(func(rr reconcile.Result) time.Duration { return rr.RequeueAfter }, BeZero()))
+		addCreatorAsAdmin, err := pwc.AddCreatorAsAdmin(env.Ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(addCreatorAsAdmin).To(BeTrue())
+
+		// the controller switches to the resource once it is created
+		Expect(env.Client(platformClusterID).Create(env.Ctx, &pwv1alpha1.ProjectWorkspaceConfig{ObjectMeta: metav1.ObjectMeta{Name: providerName}})).To(Succeed())
+		Eventually(env.ShouldReconcile).WithArguments(pwcRec, req).Should(WithTransform(func(rr reconcile.Result) time.Duration { return rr.RequeueAfter }, BeZero()))
+		addCreatorAsAdmin, err = pwc.AddCreatorAsAdmin(env.Ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(addCreatorAsAdmin).To(BeFalse())
+		Expect(env.Client(platformClusterID).Get(env.Ctx, client.ObjectKey{Name: providerName}, cfg)).To(Succeed())
+		Expect(cfg.Finalizers).To(ContainElement(sharedconfig.Finalizer))
+	})
+
+	It("should keep the config active while it is in deletion and Projects still exist", func() {
+		pwc, env := defaultTestSetup(filepath.Join("testdata", "test-01"))
+		req := testutils.RequestFromStrings(providerName)
+
+		Eventually(env.ShouldReconcile).WithArguments(pwcRec, req).Should(WithTransform(func(rr reconcile.Result) time.Duration { return rr.RequeueAfter }, BeZero()))
+		cfg := &pwv1alpha1.ProjectWorkspaceConfig{}
+		Expect(env.Client(platformClusterID).Get(env.Ctx, client.ObjectKey{Name: providerName}, cfg)).To(Succeed())
+		Expect(cfg.Finalizers).To(ContainElement(sharedconfig.Finalizer))
+
+		project := &pwv1alpha1.Project{ObjectMeta: metav1.ObjectMeta{Name: "alpha"}}
+		Expect(env.Client(onboardingClusterID).Create(env.Ctx, project)).To(Succeed())
+		Expect(env.Client(platformClusterID).Delete(env.Ctx, cfg)).To(Succeed())
+
+		// the deletion is blocked and the config stays active
+		rr := env.ShouldReconcile(pwcRec, req)
+		Expect(rr.RequeueAfter).To(BeNumerically(">", 0))
+		Expect(env.Client(platformClusterID).Get(env.Ctx, client.ObjectKey{Name: providerName}, cfg)).To(Succeed())
+		Expect(cfg.DeletionTimestamp).ToNot(BeNil())
+		_, err := pwc.ResourcesBlockingProjectDeletion(env.Ctx)
+		Expect(err).ToNot(HaveOccurred())
+
+		// the config is removed once all projects are gone
+		Expect(env.Client(onboardingClusterID).Delete(env.Ctx, project)).To(Succeed())
+		Eventually(func() error {
+			_, _ = env.Reconciler(pwcRec).Reconcile(env.Ctx, req)
+			return env.Client(platformClusterID).Get(env.Ctx, client.ObjectKey{Name: providerName}, cfg)
+		}).Should(Satisfy(apierrors.IsNotFound))
+		_, err = pwc.ResourcesBlockingProjectDeletion(env.Ctx)
+		Expect(err).To(HaveOccurred())
+	})
+
 })
@@ -2,7 +2,11 @@ package config
 
 import (
 	"context"
+	"time"
 
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/openmcp-project/controller-utils/pkg/clusters"
@@ -22,10 +26,40 @@ func NewFakeSharedInformation(onboardingClient client.Client, resourcesBlockingP
 // FakeSharedInformation is a dummy implementation of the SharedInformation interface.
 // It is meant for unit tests and should not be used anywhere else.
 type FakeSharedInformation struct {
-	OnboardingCluster                      *clusters.Cluster
+	OnboardingCluster *clusters.Cluster
+	// OnboardingClusterDynamicErr is returned by OnboardingClusterDynamic, if set, e.g. to simulate a dynamic access which is not initialized yet.
+	OnboardingClusterDynamicErr            error
 	ResourcesBlockingProjectDeletionData   []DeletionBlockingResource
 	ResourcesBlockingWorkspaceDeletionData []DeletionBlockingResource
+	ProjectDeletionIgnoreRulesData         []pwv1alpha1.DeletionIgnoreRule
+	WorkspaceDeletionIgnoreRulesData       []pwv1alpha1.DeletionIgnoreRule
+	WorkspaceBlockingResourcePoliciesData  []pwv1alpha1.BlockingResourcePolicyRule
 	MemberOverridesData                    pwv1alpha1.MemberOverrides
+	ExcludedWebhookIdentitiesData          []pwv1alpha1.IdentityMatcher
+	AddCreatorAsAdminData                  bool
+	ProjectAdminsManageWorkspacesData      bool
+	OperatorIdentitiesData                 sets.Set[string]
+	ChargingTargetData                     pwv1alpha1.ChargingTargetConfig
+	AllowedChargingTargetsData             sets.Set[string]
+	MemberPolicyData                       pwv1alpha1.MemberPolicyConfig
+	WebhookLookupsData                     pwv1alpha1.WebhookLookupsConfig
+	ManagementLabelsData                   pwv1alpha1.ManagementLabelsConfig
+	AutomationServiceAccountData           pwv1alpha1.AutomationServiceAccountConfig
+	WorkspaceNetworkPoliciesData           []pwv1alpha1.NetworkPolicyTemplate
+	WorkspaceAdditionalResourcesData       []pwv1alpha1.ResourceTemplate
+	FlatWorkspacesData                     bool
+	ConsolidatedProjectClusterRolesData    bool
+	ProjectDeletionGracePeriodData         time.Duration
+	ServiceAccountMembersData              pwv1alpha1.ServiceAccountMembersConfig
+	SecretStoresData                       []pwv1alpha1.SecretStoreConfig
+	AllowedProjectBlockingResourcesData    []metav1.GroupVersionKind
+	MaxProjectsPerCreatorData              *int32
+	WorkspaceProviderHintsData             []pwv1alpha1.ProviderHintConfig
+	WorkspacePodSecurityData               *pwv1alpha1.PodSecurityConfig
+	ProjectPermissionsData                 map[string][]rbacv1.PolicyRule
+	WorkspacePermissionsData               map[string][]rbacv1.PolicyRule
+
+	subscriptions subscriptions
 }
 
 var _ SharedInformation = &FakeSharedInformation{}
@@ -38,11 +72,182 @@ func (f *FakeSharedInformation) MemberOverrides(ctx context.Context) (pwv1alpha1
 	return f.MemberOverridesData, nil
 }
 
+// ExcludedWebhookIdentities implements SharedInformation.
+func (f *FakeSharedInformation) ExcludedWebhookIdentities(ctx context.Context) ([]pwv1alpha1.IdentityMatcher, error) {
+	if f == nil {
+		return nil, nil
+	}
+	return f.ExcludedWebhookIdentitiesData, nil
+}
+
+// AddCreatorAsAdmin implements SharedInformation.
+func (f *FakeSharedInformation) AddCreatorAsAdmin(ctx context.Context) (bool, error) {
+	if f == nil {
+		return false, nil
+	}
+	return f.AddCreatorAsAdminData, nil
+}
+
+// ProjectAdminsManageWorkspaces implements SharedInformation.
+func (f *FakeSharedInformation) ProjectAdminsManageWorkspaces(ctx context.Context) (bool, error) {
+	if f == nil {
+		return false, nil
+	}
+	return f.ProjectAdminsManageWorkspacesData, nil
+}
+
+// OperatorIdentities implements SharedInformation.
+func (f *FakeSharedInformation) OperatorIdentities(ctx context.Context) (sets.Set[string], error) {
+	if f == nil {
+		return nil, nil
+	}
+	return f.OperatorIdentitiesData, nil
+}
+
+// ManagementLabels implements SharedInformation.
+func (f *FakeSharedInformation) ManagementLabels(ctx context.Context) (pwv1alpha1.ManagementLabelsConfig, error) {
+	if f == nil {
+		return pwv1alpha1.ManagementLabelsConfig{}, nil
+	}
+	return f.ManagementLabelsData, nil
+}
+
+// AutomationServiceAccount implements SharedInformation.
+func (f *FakeSharedInformation) AutomationServiceAccount(ctx context.Context) (pwv1alpha1.AutomationServiceAccountConfig, error) {
+	if f == nil {
+		return pwv1alpha1.AutomationServiceAccountConfig{}, nil
+	}
+	return f.AutomationServiceAccountData, nil
+}
+
+// WorkspaceNetworkPolicies implements SharedInformation.
+func (f *FakeSharedInformation) WorkspaceNetworkPolicies(ctx context.Context) ([]pwv1alpha1.NetworkPolicyTemplate, error) {
+	if f == nil {
+		return nil, nil
+	}
+	return f.WorkspaceNetworkPoliciesData, nil
+}
+
+// WorkspaceAdditionalResources implements SharedInformation.
+func (f *FakeSharedInformation) WorkspaceAdditionalResources(ctx context.Context) ([]pwv1alpha1.ResourceTemplate, error) {
+	if f == nil {
+		return nil, nil
+	}
+	return f.WorkspaceAdditionalResourcesData, nil
+}
+
+// FlatWorkspaces implements SharedInformation.
+func (f *FakeSharedInformation) FlatWorkspaces(ctx context.Context) (bool, error) {
+	if f == nil {
+		return false, nil
+	}
+	return f.FlatWorkspacesData, nil
+}
+
+// ChargingTarget implements SharedInformation.
+func (f *FakeSharedInformation) ChargingTarget(ctx context.Context) (pwv1alpha1.ChargingTargetConfig, error) {
+	if f == nil {
+		return pwv1alpha1.ChargingTargetConfig{}, nil
+	}
+	return f.ChargingTargetData, nil
+}
+
+// AllowedChargingTargets implements SharedInformation.
+func (f *FakeSharedInformation) AllowedChargingTargets(ctx context.Context) (sets.Set[string], error) {
+	if f == nil {
+		return nil, nil
+	}
+	return f.AllowedChargingTargetsData, nil
+}
+
+// MemberPolicy implements SharedInformation.
+func (f *FakeSharedInformation) MemberPolicy(ctx context.Context) (pwv1alpha1.MemberPolicyConfig, error) {
+	if f == nil {
+		return pwv1alpha1.MemberPolicyConfig{}, nil
+	}
+	return f.MemberPolicyData, nil
+}
+
+// WebhookLookups implements SharedInformation.
+func (f *FakeSharedInformation) WebhookLookups(ctx context.Context) (pwv1alpha1.WebhookLookupsConfig, error) {
+	if f == nil {
+		return pwv1alpha1.WebhookLookupsConfig{}, nil
+	}
+	return f.WebhookLookupsData, nil
+}
+
+// ConsolidatedProjectClusterRoles implements SharedInformation.
+func (f *FakeSharedInformation) ConsolidatedProjectClusterRoles(ctx context.Context) (bool, error) {
+	if f == nil {
+		return false, nil
+	}
+	return f.ConsolidatedProjectClusterRolesData, nil
+}
+
+// ProjectDeletionGracePeriod implements SharedInformation.
+func (f *FakeSharedInformation) ProjectDeletionGracePeriod(ctx context.Context) (time.Duration, error) {
+	if f == nil {
+		return 0, nil
+	}
+	return f.ProjectDeletionGracePeriodData, nil
+}
+
+// ServiceAccountMembers implements SharedInformation.
+func (f *FakeSharedInformation) ServiceAccountMembers(ctx context.Context) (pwv1alpha1.ServiceAccountMembersConfig, error) {
+	if f == nil {
+		return pwv1alpha1.ServiceAccountMembersConfig{}, nil
+	}
+	return f.ServiceAccountMembersData, nil
+}
+
+// SecretStores implements SharedInformation.
+func (f *FakeSharedInformation) SecretStores(ctx context.Context) ([]pwv1alpha1.SecretStoreConfig, error) {
+	if f == nil {
+		return nil, nil
+	}
+	return f.SecretStoresData, nil
+}
+
+// AllowedProjectResourcesBlockingDeletion implements SharedInformation.
+func (f *FakeSharedInformation) AllowedProjectResourcesBlockingDeletion(ctx context.Context) ([]metav1.GroupVersionKind, error) {
+	if f == nil {
+		return nil, nil
+	}
+	return f.AllowedProjectBlockingResourcesData, nil
+}
+
+// WorkspaceProviderHints implements SharedInformation.
+func (f *FakeSharedInformation) WorkspaceProviderHints(ctx context.Context) ([]pwv1alpha1.ProviderHintConfig, error) {
+	if f == nil {
+		return nil, nil
+	}
+	return f.WorkspaceProviderHintsData, nil
+}
+
+// WorkspacePodSecurity implements SharedInformation.
+func (f *FakeSharedInformation) WorkspacePodSecurity(ctx context.Context) (*pwv1alpha1.PodSecurityConfig, error) {
+	if f == nil {
+		return nil, nil
+	}
+	return f.WorkspacePodSecurityData, nil
+}
+
+// MaxProjectsPerCreator implements SharedInformation.
+func (f *FakeSharedInformation) MaxProjectsPerCreator(ctx context.Context) (*int32, error) {
+	if f == nil {
+		return nil, nil
+	}
+	return f.MaxProjectsPerCreatorData, nil
+}
+
 // OnboardingClusterDynamic implements SharedInformation.
 func (f *FakeSharedInformation) OnboardingClusterDynamic(ctx context.Context) (*clusters.Cluster, error) {
 	if f == nil {
 		return nil, nil
 	}
+	if f.OnboardingClusterDynamicErr != nil {
+		return nil, f.OnboardingClusterDynamicErr
+	}
 	return f.OnboardingCluster, nil
 }
 
@@ -69,3 +274,57 @@ func (f *FakeSharedInformation) ResourcesBlockingWorkspaceDeletion(ctx context.C
 	}
 	return f.ResourcesBlockingWorkspaceDeletionData, nil
 }
+
+// ProjectDeletionIgnoreRules implements SharedInformation.
+func (f *FakeSharedInformation) ProjectDeletionIgnoreRules(ctx context.Context) ([]pwv1alpha1.DeletionIgnoreRule, error) {
+	if f == nil {
+		return nil, nil
+	}
+	return f.ProjectDeletionIgnoreRulesData, nil
+}
+
+// WorkspaceDeletionIgnoreRules implements SharedInformation.
+func (f *FakeSharedInformation) WorkspaceDeletionIgnoreRules(ctx context.Context) ([]pwv1alpha1.DeletionIgnoreRule, error) {
+	if f == nil {
+		return nil, nil
+	}
+	return f.WorkspaceDeletionIgnoreRulesData, nil
+}
+
+// WorkspaceBlockingResourcePolicies implements SharedInformation.
+func (f *FakeSharedInformation) WorkspaceBlockingResourcePolicies(ctx context.Context) ([]pwv1alpha1.BlockingResourcePolicyRule, error) {
+	if f == nil {
+		return nil, nil
+	}
+	return f.WorkspaceBlockingResourcePoliciesData, nil
+}
+
+// ProjectPermissionsForRole implements SharedInformation.
+func (f *FakeSharedInformation) ProjectPermissionsForRole(ctx context.Context, roleID string) ([]rbacv1.PolicyRule, error) {
+	if f == nil {
+		return nil, nil
+	}
+	return f.ProjectPermissionsData[roleID], nil
+}
+
+// WorkspacePermissionsForRole implements SharedInformation.
+func (f *FakeSharedInformation) WorkspacePermissionsForRole(ctx context.Context, roleID string) ([]rbacv1.PolicyRule, error) {
+	if f == nil {
+		return nil, nil
+	}
+	return f.WorkspacePermissionsData[roleID], nil
+}
+
+// Subscribe implements SharedInformation.
+// The subscribers only receive the changes passed to Notify.
+func (f *FakeSharedInformation) Subscribe(ctx context.Context) <-chan Change {
+	if f == nil {
+		return nil
+	}
+	return f.subscriptions.subscribe(ctx)
+}
+
+// Notify sends the given changes to the subscribers, like the real implementation does when the configuration changes.
+func (f *FakeSharedInformation) Notify(changes Change) {
+	f.subscriptions.notify(changes)
+}
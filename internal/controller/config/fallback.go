@@ -0,0 +1,38 @@
+package config
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	coreconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/core/config"
+)
+
+// LoadFallbackConfig reads a ProjectWorkspaceConfig from the given file, which is used as long as the ProjectWorkspaceConfig resource with the given name does not exist.
+// The file may contain either a complete ProjectWorkspaceConfig or only its spec. Defaults are applied and the result is validated.
+func LoadFallbackConfig(path, name string) (*pwv1alpha1.ProjectWorkspaceConfig, error) {
+	cfg, err := coreconfig.LoadConfig(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fallback config from '%s': %w", path, err)
+	}
+	cfg.Name = name
+	cfg.SetDefaults()
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid fallback config in '%s': %w", path, err)
+	}
+	return cfg, nil
+}
+
+// fallbackConfigSource enqueues the config once the controller starts.
+// Without the ProjectWorkspaceConfig resource, the watches would not trigger a reconciliation, so the fallback config would not be loaded otherwise.
+func (c *PWOConfigController) fallbackConfigSource() source.Source {
+	return source.Func(func(_ context.Context, queue workqueue.TypedRateLimitingInterface[reconcile.Request]) error {
+		queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{Name: c.providerName}})
+		return nil
+	})
+}
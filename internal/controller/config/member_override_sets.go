@@ -0,0 +1,43 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+)
+
+// EffectiveMemberOverrides returns the member overrides of the given config, followed by the overrides of all MemberOverrideSets
+// which match its memberOverrideSetSelector, ordered by the names of the sets. Sets in deletion are ignored.
+// The config is expected to be merged with the ProjectWorkspaceConfigOverride already.
+// Since member overrides only grant permissions, appending them is sufficient, no set can revoke an override of the config or another set.
+func EffectiveMemberOverrides(ctx context.Context, r client.Reader, cfg *pwv1alpha1.ProjectWorkspaceConfig) (pwv1alpha1.MemberOverrides, error) {
+	if cfg.Spec.MemberOverrideSetSelector == nil {
+		return cfg.Spec.MemberOverrides, nil
+	}
+	sel, err := metav1.LabelSelectorAsSelector(cfg.Spec.MemberOverrideSetSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid memberOverrideSetSelector: %w", err)
+	}
+	sets := &pwv1alpha1.MemberOverrideSetList{}
+	if err := r.List(ctx, sets, client.MatchingLabelsSelector{Selector: sel}); err != nil {
+		return nil, fmt.Errorf("failed to list MemberOverrideSets: %w", err)
+	}
+	slices.SortFunc(sets.Items, func(a, b pwv1alpha1.MemberOverrideSet) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	res := slices.Clone(cfg.Spec.MemberOverrides)
+	for _, set := range sets.Items {
+		if !set.DeletionTimestamp.IsZero() {
+			continue
+		}
+		res = append(res, set.Spec.Overrides...)
+	}
+	return res, nil
+}
@@ -0,0 +1,86 @@
+package config_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+)
+
+func TestEffectiveMemberOverrides(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, pwv1alpha1.AddToScheme(scheme))
+
+	override := func(name string) pwv1alpha1.MemberOverride {
+		return pwv1alpha1.MemberOverride{Subject: pwv1alpha1.Subject{Kind: rbacv1.GroupKind, Name: name}, Roles: []pwv1alpha1.OverrideRole{pwv1alpha1.OverrideRoleAdmin}}
+	}
+	set := func(name, scope string, overrides ...pwv1alpha1.MemberOverride) *pwv1alpha1.MemberOverrideSet {
+		return &pwv1alpha1.MemberOverrideSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"scope": scope}},
+			Spec:       pwv1alpha1.MemberOverrideSetSpec{Overrides: overrides},
+		}
+	}
+	platform := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		set("region-b", "regional", override("region-b-admins")),
+		set("central", "central", override("central-admins"), override("support")),
+		set("region-a", "regional", override("region-a-admins")),
+	).Build()
+
+	tests := []struct {
+		name     string
+		selector *metav1.LabelSelector
+		expected pwv1alpha1.MemberOverrides
+	}{
+		{
+			name:     "should only return the overrides of the config without selector",
+			expected: pwv1alpha1.MemberOverrides{override("config-admins")},
+		},
+		{
+			name:     "should append the overrides of all sets ordered by name for an empty selector",
+			selector: &metav1.LabelSelector{},
+			expected: pwv1alpha1.MemberOverrides{override("config-admins"), override("central-admins"), override("support"), override("region-a-admins"), override("region-b-admins")},
+		},
+		{
+			name:     "should only append the overrides of the selected sets",
+			selector: &metav1.LabelSelector{MatchLabels: map[string]string{"scope": "regional"}},
+			expected: pwv1alpha1.MemberOverrides{override("config-admins"), override("region-a-admins"), override("region-b-admins")},
+		},
+		{
+			name:     "should not append anything if no set is selected",
+			selector: &metav1.LabelSelector{MatchLabels: map[string]string{"scope": "other"}},
+			expected: pwv1alpha1.MemberOverrides{override("config-admins")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &pwv1alpha1.ProjectWorkspaceConfig{
+				Spec: pwv1alpha1.ProjectWorkspaceConfigSpec{
+					MemberOverrides:           pwv1alpha1.MemberOverrides{override("config-admins")},
+					MemberOverrideSetSelector: tt.selector,
+				},
+			}
+			res, err := config.EffectiveMemberOverrides(context.Background(), platform, cfg)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, res)
+			assert.Len(t, cfg.Spec.MemberOverrides, 1, "the config must not be modified")
+		})
+	}
+
+	t.Run("should return an error for an invalid selector", func(t *testing.T) {
+		cfg := &pwv1alpha1.ProjectWorkspaceConfig{
+			Spec: pwv1alpha1.ProjectWorkspaceConfigSpec{
+				MemberOverrideSetSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "scope", Operator: "Unknown"}}},
+			},
+		}
+		_, err := config.EffectiveMemberOverrides(context.Background(), platform, cfg)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,221 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/openmcp-project/controller-utils/pkg/clusters"
+	ctrlutils "github.com/openmcp-project/controller-utils/pkg/controller"
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/logconfig"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/metrics"
+)
+
+// MemberOverridePruningControllerName is the name the controller which prunes stale member overrides is registered with at the manager.
+const MemberOverridePruningControllerName = "member-override-pruning"
+
+// MemberOverridePruner lists the resources of member overrides in the ProjectWorkspaceConfig which reference projects or workspaces
+// that do not exist anymore in the status of the config. If pruning is enabled, these resources are removed from the member overrides
+// once they have been missing for the retention period, and member overrides without any remaining resource are removed completely.
+type MemberOverridePruner struct {
+	providerName     string
+	platformCluster  *clusters.Cluster
+	onboardingClient client.Client
+}
+
+// NewMemberOverridePruner creates a new MemberOverridePruner for the ProjectWorkspaceConfig with the given name.
+func NewMemberOverridePruner(providerName string, platformCluster *clusters.Cluster, onboardingClient client.Client) *MemberOverridePruner {
+	return &MemberOverridePruner{
+		providerName:     providerName,
+		platformCluster:  platformCluster,
+		onboardingClient: onboardingClient,
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// The config is reconciled when it changes and whenever a Project or Workspace on the onboarding cluster is created or deleted.
+func (p *MemberOverridePruner) SetupWithManager(mgr ctrl.Manager) error {
+	createdOrDeleted := predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return true },
+		UpdateFunc:  func(event.UpdateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return true },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+	configRequest := []ctrl.Request{{NamespacedName: types.NamespacedName{Name: p.providerName}}}
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(MemberOverridePruningControllerName).
+		WithLogConstructor(logconfig.LogConstructor(mgr.GetLogger(), MemberOverridePruningControllerName)).
+		WatchesRawSource(source.Kind(p.platformCluster.Cluster().GetCache(), &pwv1alpha1.ProjectWorkspaceConfig{}, &handler.TypedEnqueueRequestForObject[*pwv1alpha1.ProjectWorkspaceConfig]{}, ctrlutils.ToTypedPredicate[*pwv1alpha1.ProjectWorkspaceConfig](
+			predicate.And(
+				ctrlutils.ExactNamePredicate(p.providerName, ""),
+				predicate.GenerationChangedPredicate{},
+			),
+		))).
+		WatchesRawSource(source.Kind(mgr.GetCache(), &pwv1alpha1.Project{}, handler.TypedEnqueueRequestsFromMapFunc(func(_ context.Context, _ *pwv1alpha1.Project) []ctrl.Request {
+			return configRequest
+		}), ctrlutils.ToTypedPredicate[*pwv1alpha1.Project](createdOrDeleted))).
+		WatchesRawSource(source.Kind(mgr.GetCache(), &pwv1alpha1.Workspace{}, handler.TypedEnqueueRequestsFromMapFunc(func(_ context.Context, _ *pwv1alpha1.Workspace) []ctrl.Request {
+			return configRequest
+		}), ctrlutils.ToTypedPredicate[*pwv1alpha1.Workspace](createdOrDeleted))).
+		Complete(metrics.ObserveReconciler(MemberOverridePruningControllerName, p))
+}
+
+var _ reconcile.Reconciler = &MemberOverridePruner{}
+
+func (p *MemberOverridePruner) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := logging.FromContextOrPanic(ctx).WithName(MemberOverridePruningControllerName)
+	ctx = logging.NewContext(ctx, log)
+
+	if req.Name != p.providerName {
+		return reconcile.Result{}, nil
+	}
+	cfg := &pwv1alpha1.ProjectWorkspaceConfig{}
+	if err := p.platformCluster.Client().Get(ctx, req.NamespacedName, cfg); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+	if !cfg.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, nil
+	}
+
+	projects, workspaces, err := p.existingNames(ctx)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	var pruning pwv1alpha1.MemberOverridePruningConfig
+	if cfg.Spec.MemberOverridePruning != nil {
+		pruning = *cfg.Spec.MemberOverridePruning
+	}
+	result := pruneMemberOverrides(cfg.Spec.MemberOverrides, cfg.Status.StaleMemberOverrides, projects, workspaces, pruning, time.Now())
+
+	if result.removed > 0 {
+		log.Info("Removing stale resources from member overrides", "resources", result.removed, "overrides", len(cfg.Spec.MemberOverrides)-len(result.overrides))
+		old := cfg.DeepCopy()
+		cfg.Spec.MemberOverrides = result.overrides
+		// the lock prevents removing overrides which have been changed in the meantime, e.g. re-added by GitOps
+		if err := p.platformCluster.Client().Patch(ctx, cfg, client.MergeFromWithOptions(old, client.MergeFromWithOptimisticLock{})); err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to remove stale member overrides: %w", err)
+		}
+	}
+
+	if !equality.Semantic.DeepEqual(cfg.Status.StaleMemberOverrides, result.stale) {
+		old := cfg.DeepCopy()
+		cfg.Status.StaleMemberOverrides = result.stale
+		if err := p.platformCluster.Client().Status().Patch(ctx, cfg, client.MergeFrom(old)); err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to update stale member overrides in status of ProjectWorkspaceConfig: %w", err)
+		}
+	}
+
+	return reconcile.Result{RequeueAfter: result.requeueAfter}, nil
+}
+
+// existingNames returns the lower-cased names of all projects and workspaces on the onboarding cluster,
+// since member overrides match their resources case-insensitively.
+func (p *MemberOverridePruner) existingNames(ctx context.Context) (sets.Set[string], sets.Set[string], error) {
+	projects := &pwv1alpha1.ProjectList{}
+	if err := p.onboardingClient.List(ctx, projects); err != nil {
+		return nil, nil, fmt.Errorf("failed to list Projects: %w", err)
+	}
+	workspaces := &pwv1alpha1.WorkspaceList{}
+	if err := p.onboardingClient.List(ctx, workspaces); err != nil {
+		return nil, nil, fmt.Errorf("failed to list Workspaces: %w", err)
+	}
+	projectNames := sets.New[string]()
+	for _, project := range projects.Items {
+		projectNames.Insert(strings.ToLower(project.Name))
+	}
+	workspaceNames := sets.New[string]()
+	for _, workspace := range workspaces.Items {
+		workspaceNames.Insert(strings.ToLower(workspace.Name))
+	}
+	return projectNames, workspaceNames, nil
+}
+
+// pruneResult is the result of pruneMemberOverrides.
+type pruneResult struct {
+	// overrides are the member overrides without the removed resources.
+	overrides pwv1alpha1.MemberOverrides
+	// removed is the number of removed resources.
+	removed int
+	// stale are the resources which reference missing projects or workspaces and have not been removed.
+	stale []pwv1alpha1.StaleMemberOverride
+	// requeueAfter is the duration until the next stale resource can be removed, zero if there is none.
+	requeueAfter time.Duration
+}
+
+// pruneMemberOverrides determines the resources of the given member overrides which reference projects or workspaces that do not exist.
+// The time since when a resource is missing is taken from the previously reported stale resources, or set to now for new ones.
+// If pruning is enabled, resources which have been missing for the retention period are removed, and so are member overrides without remaining resources.
+// A workspace resource is only considered missing if there is no workspace with its name in any project, since the override does not name the project.
+func pruneMemberOverrides(overrides pwv1alpha1.MemberOverrides, previous []pwv1alpha1.StaleMemberOverride, projects, workspaces sets.Set[string], pruning pwv1alpha1.MemberOverridePruningConfig, now time.Time) pruneResult {
+	type staleKey struct {
+		subject  pwv1alpha1.Subject
+		resource pwv1alpha1.OverrideResource
+	}
+	keyOf := func(subject pwv1alpha1.Subject, resource pwv1alpha1.OverrideResource) staleKey {
+		return staleKey{subject: subject, resource: pwv1alpha1.OverrideResource{Kind: strings.ToLower(resource.Kind), Name: strings.ToLower(resource.Name)}}
+	}
+	since := make(map[staleKey]metav1.Time, len(previous))
+	for _, s := range previous {
+		since[keyOf(s.Subject, s.Resource)] = s.Since
+	}
+	retention := pruning.GetRetentionPeriod()
+
+	res := pruneResult{overrides: make(pwv1alpha1.MemberOverrides, 0, len(overrides))}
+	for _, override := range overrides {
+		if len(override.Resources) == 0 {
+			res.overrides = append(res.overrides, override)
+			continue
+		}
+		kept := make([]pwv1alpha1.OverrideResource, 0, len(override.Resources))
+		for _, resource := range override.Resources {
+			name := strings.ToLower(resource.Name)
+			exists := projects.Has(name)
+			if strings.EqualFold(resource.Kind, pwv1alpha1.OverrideResourceKindWorkspace) {
+				exists = workspaces.Has(name)
+			}
+			if exists {
+				kept = append(kept, resource)
+				continue
+			}
+
+			missingSince, ok := since[keyOf(override.Subject, resource)]
+			if !ok {
+				missingSince = metav1.NewTime(now)
+			}
+			expiresIn := missingSince.Add(retention).Sub(now)
+			if pruning.Enabled && expiresIn <= 0 {
+				res.removed++
+				continue
+			}
+			kept = append(kept, resource)
+			res.stale = append(res.stale, pwv1alpha1.StaleMemberOverride{Subject: override.Subject, Resource: resource, Since: missingSince})
+			if pruning.Enabled && (res.requeueAfter == 0 || expiresIn < res.requeueAfter) {
+				res.requeueAfter = expiresIn
+			}
+		}
+		// an override without resources would apply to all projects and workspaces
+		if len(kept) == 0 {
+			continue
+		}
+		override.Resources = kept
+		res.overrides = append(res.overrides, override)
+	}
+	return res
+}
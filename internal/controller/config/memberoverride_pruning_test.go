@@ -0,0 +1,103 @@
+package config_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openmcp-project/controller-utils/pkg/clusters"
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+)
+
+func TestMemberOverridePruner(t *testing.T) {
+	ctx := logging.NewContext(context.Background(), logging.Discard())
+	scheme := runtime.NewScheme()
+	require.NoError(t, pwv1alpha1.AddToScheme(scheme))
+
+	support := pwv1alpha1.Subject{Kind: rbacv1.UserKind, Name: "support"}
+	admins := pwv1alpha1.Subject{Kind: rbacv1.GroupKind, Name: "admins"}
+	project := func(name string) pwv1alpha1.OverrideResource {
+		return pwv1alpha1.OverrideResource{Kind: pwv1alpha1.OverrideResourceKindProject, Name: name}
+	}
+	workspace := func(name string) pwv1alpha1.OverrideResource {
+		return pwv1alpha1.OverrideResource{Kind: pwv1alpha1.OverrideResourceKindWorkspace, Name: name}
+	}
+	expired := metav1.NewTime(time.Now().Add(-48 * time.Hour).Truncate(time.Second))
+
+	newConfig := func(pruning *pwv1alpha1.MemberOverridePruningConfig) *pwv1alpha1.ProjectWorkspaceConfig {
+		return &pwv1alpha1.ProjectWorkspaceConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "project-workspace"},
+			Spec: pwv1alpha1.ProjectWorkspaceConfigSpec{
+				MemberOverrides: pwv1alpha1.MemberOverrides{
+					{Subject: admins, Roles: []pwv1alpha1.OverrideRole{pwv1alpha1.OverrideRoleAdmin}},
+					{Subject: support, Roles: []pwv1alpha1.OverrideRole{pwv1alpha1.OverrideRoleAdmin}, Resources: []pwv1alpha1.OverrideResource{project("alpha"), workspace("dev"), project("gone")}},
+					{Subject: admins, Roles: []pwv1alpha1.OverrideRole{pwv1alpha1.OverrideRoleView}, Resources: []pwv1alpha1.OverrideResource{project("deleted")}},
+				},
+				MemberOverridePruning: pruning,
+			},
+			Status: pwv1alpha1.ProjectWorkspaceConfigStatus{
+				StaleMemberOverrides: []pwv1alpha1.StaleMemberOverride{
+					{Subject: admins, Resource: project("Deleted"), Since: expired},
+				},
+			},
+		}
+	}
+	onboarding := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&pwv1alpha1.Project{ObjectMeta: metav1.ObjectMeta{Name: "alpha"}},
+		&pwv1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "dev", Namespace: "project-alpha"}},
+	).Build()
+
+	reconcileConfig := func(t *testing.T, cfg *pwv1alpha1.ProjectWorkspaceConfig) (*pwv1alpha1.ProjectWorkspaceConfig, reconcile.Result) {
+		t.Helper()
+		platform := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cfg).WithStatusSubresource(cfg).Build()
+		p := config.NewMemberOverridePruner(cfg.Name, clusters.NewTestClusterFromClient("platform", platform), onboarding)
+		res, err := p.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(cfg)})
+		require.NoError(t, err)
+		actual := &pwv1alpha1.ProjectWorkspaceConfig{}
+		require.NoError(t, platform.Get(ctx, client.ObjectKeyFromObject(cfg), actual))
+		return actual, res
+	}
+
+	t.Run("only reports stale resources if pruning is not enabled", func(t *testing.T) {
+		cfg, res := reconcileConfig(t, newConfig(nil))
+		assert.Equal(t, newConfig(nil).Spec.MemberOverrides, cfg.Spec.MemberOverrides)
+		if assert.Len(t, cfg.Status.StaleMemberOverrides, 2) {
+			assert.Equal(t, support, cfg.Status.StaleMemberOverrides[0].Subject)
+			assert.Equal(t, project("gone"), cfg.Status.StaleMemberOverrides[0].Resource)
+			assert.WithinDuration(t, time.Now(), cfg.Status.StaleMemberOverrides[0].Since.Time, time.Minute)
+			assert.Equal(t, pwv1alpha1.StaleMemberOverride{Subject: admins, Resource: project("deleted"), Since: expired}, cfg.Status.StaleMemberOverrides[1])
+		}
+		assert.Zero(t, res.RequeueAfter)
+	})
+
+	t.Run("removes resources after the retention period and overrides without remaining resources", func(t *testing.T) {
+		pruning := &pwv1alpha1.MemberOverridePruningConfig{Enabled: true, RetentionPeriod: &metav1.Duration{Duration: time.Hour}}
+		cfg, res := reconcileConfig(t, newConfig(pruning))
+		assert.Equal(t, pwv1alpha1.MemberOverrides{
+			{Subject: admins, Roles: []pwv1alpha1.OverrideRole{pwv1alpha1.OverrideRoleAdmin}},
+			{Subject: support, Roles: []pwv1alpha1.OverrideRole{pwv1alpha1.OverrideRoleAdmin}, Resources: []pwv1alpha1.OverrideResource{project("alpha"), workspace("dev"), project("gone")}},
+		}, cfg.Spec.MemberOverrides)
+		if assert.Len(t, cfg.Status.StaleMemberOverrides, 1) {
+			assert.Equal(t, project("gone"), cfg.Status.StaleMemberOverrides[0].Resource)
+		}
+		assert.InDelta(t, time.Hour, res.RequeueAfter, float64(time.Minute))
+	})
+
+	t.Run("ignores other configs", func(t *testing.T) {
+		p := config.NewMemberOverridePruner("other", clusters.NewTestClusterFromClient("platform", fake.NewClientBuilder().WithScheme(scheme).Build()), onboarding)
+		_, err := p.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKey{Name: "project-workspace"}})
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+)
+
+// operatorIdentityCache caches the usernames of the ServiceAccounts which are selected as identities of the platform service,
+// so that the webhooks do not have to list the ServiceAccounts on the onboarding cluster for every admission request.
+type operatorIdentityCache struct {
+	lock      sync.Mutex
+	selector  string
+	values    sets.Set[string]
+	expiresAt time.Time
+	// now returns the current time, it can be replaced in tests.
+	now func() time.Time
+}
+
+// get returns the usernames of the ServiceAccounts selected by the given config.
+// The ServiceAccounts are only listed again if the cached values have expired or a different selector is configured.
+func (c *operatorIdentityCache) get(ctx context.Context, onboardingClient client.Client, cfg *pwv1alpha1.OperatorIdentitiesConfig) (sets.Set[string], error) {
+	selector, err := metav1.LabelSelectorAsSelector(&cfg.ServiceAccountSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ServiceAccount selector for operator identities: %w", err)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := time.Now
+	if c.now != nil {
+		now = c.now
+	}
+	if c.values != nil && c.selector == selector.String() && now().Before(c.expiresAt) {
+		return c.values, nil
+	}
+
+	sas := &corev1.ServiceAccountList{}
+	if err := onboardingClient.List(ctx, sas, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("failed to list ServiceAccounts of the operator identities: %w", err)
+	}
+	values := sets.New[string]()
+	for _, sa := range sas.Items {
+		values.Insert(fmt.Sprintf("system:serviceaccount:%s:%s", sa.Namespace, sa.Name))
+	}
+	c.selector = selector.String()
+	c.values = values
+	c.expiresAt = now().Add(cfg.GetRefreshInterval())
+	return c.values, nil
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+)
+
+// fetchOverride fetches the ProjectWorkspaceConfigOverride for the environment of this instance from the pod namespace.
+// It returns nil if no environment is configured or if there is no override for it.
+func (c *PWOConfigController) fetchOverride(ctx context.Context) (*pwv1alpha1.ProjectWorkspaceConfigOverride, error) {
+	if c.Environment == "" {
+		return nil, nil
+	}
+	override := &pwv1alpha1.ProjectWorkspaceConfigOverride{}
+	if err := c.platformCluster.Client().Get(ctx, client.ObjectKey{Name: c.Environment, Namespace: c.podNamespace}, override); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch ProjectWorkspaceConfigOverride '%s/%s': %w", c.podNamespace, c.Environment, err)
+	}
+	if !override.DeletionTimestamp.IsZero() {
+		return nil, nil
+	}
+	return override, nil
+}
+
+// MergeOverride returns a copy of the given ProjectWorkspaceConfig with the values of the given override merged over it.
+// Lists, maps, and optional values which are set in the override replace the corresponding values of the config, all other values are kept.
+// The arguments are not modified.
+func MergeOverride(cfg *pwv1alpha1.ProjectWorkspaceConfig, override *pwv1alpha1.ProjectWorkspaceConfigOverride) *pwv1alpha1.ProjectWorkspaceConfig {
+	res := cfg.DeepCopy()
+	if override == nil {
+		return res
+	}
+	o := override.Spec.DeepCopy()
+
+	if o.Project.ResourcesBlockingDeletion != nil {
+		res.Spec.Project.ResourcesBlockingDeletion = o.Project.ResourcesBlockingDeletion
+	}
+	if o.Project.AllowedResourcesBlockingDeletion != nil {
+		res.Spec.Project.AllowedResourcesBlockingDeletion = o.Project.AllowedResourcesBlockingDeletion
+	}
+	if o.Project.IgnoredBlockingResources != nil {
+		res.Spec.Project.IgnoredBlockingResources = o.Project.IgnoredBlockingResources
+	}
+	if o.Project.AdditionalPermissions != nil {
+		res.Spec.Project.AdditionalPermissions = o.Project.AdditionalPermissions
+	}
+	if o.Project.AuditorExcludedResources != nil {
+		res.Spec.Project.AuditorExcludedResources = o.Project.AuditorExcludedResources
+	}
+	if o.Project.AutomationServiceAccount != nil {
+		res.Spec.Project.AutomationServiceAccount = o.Project.AutomationServiceAccount
+	}
+	if o.Project.ConsolidatedClusterRoles != nil {
+		res.Spec.Project.ConsolidatedClusterRoles = o.Project.ConsolidatedClusterRoles
+	}
+	if o.Project.DeletionGracePeriod != nil {
+		res.Spec.Project.DeletionGracePeriod = o.Project.DeletionGracePeriod
+	}
+	if o.Project.SecretStores != nil {
+		res.Spec.Project.SecretStores = o.Project.SecretStores
+	}
+	if o.Project.MaxProjectsPerCreator != nil {
+		res.Spec.Project.MaxProjectsPerCreator = o.Project.MaxProjectsPerCreator
+	}
+
+	if o.Workspace.ResourcesBlockingDeletion != nil {
+		res.Spec.Workspace.ResourcesBlockingDeletion = o.Workspace.ResourcesBlockingDeletion
+	}
+	if o.Workspace.IgnoredBlockingResources != nil {
+		res.Spec.Workspace.IgnoredBlockingResources = o.Workspace.IgnoredBlockingResources
+	}
+	if o.Workspace.BlockingResourcePolicies != nil {
+		res.Spec.Workspace.BlockingResourcePolicies = o.Workspace.BlockingResourcePolicies
+	}
+	if o.Workspace.AdditionalPermissions != nil {
+		res.Spec.Workspace.AdditionalPermissions = o.Workspace.AdditionalPermissions
+	}
+	if o.Workspace.AuditorExcludedResources != nil {
+		res.Spec.Workspace.AuditorExcludedResources = o.Workspace.AuditorExcludedResources
+	}
+	if o.Workspace.NetworkPolicies != nil {
+		res.Spec.Workspace.NetworkPolicies = o.Workspace.NetworkPolicies
+	}
+	if o.Workspace.AdditionalResources != nil {
+		res.Spec.Workspace.AdditionalResources = o.Workspace.AdditionalResources
+	}
+	if o.Workspace.Flat != nil {
+		res.Spec.Workspace.Flat = o.Workspace.Flat
+	}
+	if o.Workspace.ServiceAccountMembers != nil {
+		res.Spec.Workspace.ServiceAccountMembers = o.Workspace.ServiceAccountMembers
+	}
+	if o.Workspace.ProviderHints != nil {
+		res.Spec.Workspace.ProviderHints = o.Workspace.ProviderHints
+	}
+	if o.Workspace.PodSecurity != nil {
+		res.Spec.Workspace.PodSecurity = o.Workspace.PodSecurity
+	}
+
+	if o.MemberOverrides != nil {
+		res.Spec.MemberOverrides = o.MemberOverrides
+	}
+
+	return res
+}
@@ -0,0 +1,118 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+)
+
+func TestMergeOverride(t *testing.T) {
+	blockingA := metav1.GroupVersionKind{Group: "a.example.com", Version: "v1", Kind: "A"}
+	blockingB := metav1.GroupVersionKind{Group: "b.example.com", Version: "v1", Kind: "B"}
+	override := pwv1alpha1.MemberOverride{
+		Subject: pwv1alpha1.Subject{Kind: rbacv1.UserKind, Name: "admin@example.com"},
+		Roles:   []pwv1alpha1.OverrideRole{pwv1alpha1.OverrideRoleAdmin},
+	}
+
+	base := &pwv1alpha1.ProjectWorkspaceConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "project-workspace"},
+		Spec: pwv1alpha1.ProjectWorkspaceConfigSpec{
+			Project: pwv1alpha1.ProjectConfig{
+				ResourcesBlockingDeletion: []metav1.GroupVersionKind{blockingA},
+			},
+			Workspace: pwv1alpha1.WorkspaceConfig{
+				ResourcesBlockingDeletion: []metav1.GroupVersionKind{blockingA},
+			},
+			MemberOverrides: pwv1alpha1.MemberOverrides{override},
+			Webhook: pwv1alpha1.WebhookConfig{
+				Disabled: true,
+			},
+		},
+	}
+
+	tests := []struct {
+		description string
+		override    *pwv1alpha1.ProjectWorkspaceConfigOverride
+		expected    pwv1alpha1.ProjectWorkspaceConfigSpec
+	}{
+		{
+			description: "keeps the config if there is no override",
+			expected:    base.Spec,
+		},
+		{
+			description: "keeps values which are not set in the override",
+			override:    &pwv1alpha1.ProjectWorkspaceConfigOverride{},
+			expected:    base.Spec,
+		},
+		{
+			description: "replaces values which are set in the override",
+			override: &pwv1alpha1.ProjectWorkspaceConfigOverride{
+				Spec: pwv1alpha1.ProjectWorkspaceConfigOverrideSpec{
+					Workspace: pwv1alpha1.WorkspaceConfig{
+						ResourcesBlockingDeletion: []metav1.GroupVersionKind{blockingB},
+					},
+				},
+			},
+			expected: pwv1alpha1.ProjectWorkspaceConfigSpec{
+				Project:         base.Spec.Project,
+				Workspace:       pwv1alpha1.WorkspaceConfig{ResourcesBlockingDeletion: []metav1.GroupVersionKind{blockingB}},
+				MemberOverrides: base.Spec.MemberOverrides,
+				Webhook:         base.Spec.Webhook,
+			},
+		},
+		{
+			description: "replaces optional values which are set in the override",
+			override: &pwv1alpha1.ProjectWorkspaceConfigOverride{
+				Spec: pwv1alpha1.ProjectWorkspaceConfigOverrideSpec{
+					Workspace: pwv1alpha1.WorkspaceConfig{
+						Flat:                  ptr.To(true),
+						ServiceAccountMembers: &pwv1alpha1.ServiceAccountMembersConfig{RestrictToProject: true},
+					},
+				},
+			},
+			expected: pwv1alpha1.ProjectWorkspaceConfigSpec{
+				Project: base.Spec.Project,
+				Workspace: pwv1alpha1.WorkspaceConfig{
+					ResourcesBlockingDeletion: base.Spec.Workspace.ResourcesBlockingDeletion,
+					Flat:                      ptr.To(true),
+					ServiceAccountMembers:     &pwv1alpha1.ServiceAccountMembersConfig{RestrictToProject: true},
+				},
+				MemberOverrides: base.Spec.MemberOverrides,
+				Webhook:         base.Spec.Webhook,
+			},
+		},
+		{
+			description: "removes values which are explicitly empty in the override",
+			override: &pwv1alpha1.ProjectWorkspaceConfigOverride{
+				Spec: pwv1alpha1.ProjectWorkspaceConfigOverrideSpec{
+					Project: pwv1alpha1.ProjectConfig{
+						ResourcesBlockingDeletion: []metav1.GroupVersionKind{},
+					},
+					MemberOverrides: pwv1alpha1.MemberOverrides{},
+				},
+			},
+			expected: pwv1alpha1.ProjectWorkspaceConfigSpec{
+				Project:         pwv1alpha1.ProjectConfig{ResourcesBlockingDeletion: []metav1.GroupVersionKind{}},
+				Workspace:       base.Spec.Workspace,
+				MemberOverrides: pwv1alpha1.MemberOverrides{},
+				Webhook:         base.Spec.Webhook,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			original := base.DeepCopy()
+			merged := config.MergeOverride(base, test.override)
+			assert.Equal(t, test.expected, merged.Spec)
+			assert.Equal(t, base.ObjectMeta, merged.ObjectMeta)
+			assert.Equal(t, original, base, "input config must not be modified")
+		})
+	}
+}
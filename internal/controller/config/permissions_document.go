@@ -0,0 +1,94 @@
+package config
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+// PermissionsConfigMapName returns the name of the ConfigMap in the pod namespace of the platform cluster,
+// which documents the effective permissions of the project and workspace roles of the platform service with the given name.
+func PermissionsConfigMapName(providerName string) string {
+	return providerName + "-permissions"
+}
+
+// permissionsDocumentEntry is a ClusterRole manifest in the permissions document.
+// It is rendered without the remaining metadata of a ClusterRole, which would only be empty.
+type permissionsDocumentEntry struct {
+	APIVersion string              `json:"apiVersion"`
+	Kind       string              `json:"kind"`
+	Metadata   map[string]string   `json:"metadata"`
+	Rules      []rbacv1.PolicyRule `json:"rules"`
+}
+
+// permissionsDocument renders the effective rules of each project and workspace role as ClusterRole manifest, keyed by '<project|workspace>-<roleID>.yaml'.
+// The rules are the ones of the ClusterRoles on the onboarding cluster, i.e. the builtin ones merged with the resources registered by ServiceProviders and the permissions from the config.
+func permissionsDocument(projectPermissionsForRole, workspacePermissionsForRole func(string) ([]rbacv1.PolicyRule, error)) (map[string]string, error) {
+	data := map[string]string{}
+	render := func(scope, roleID, clusterRole string, permissionsForRole func(string) ([]rbacv1.PolicyRule, error)) error {
+		rules, err := permissionsForRole(roleID)
+		if err != nil {
+			return err
+		}
+		manifest, err := yaml.Marshal(permissionsDocumentEntry{
+			APIVersion: rbacv1.SchemeGroupVersion.String(),
+			Kind:       "ClusterRole",
+			Metadata:   map[string]string{"name": clusterRole},
+			Rules:      rules,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to render permissions of %s role '%s': %w", scope, roleID, err)
+		}
+		data[fmt.Sprintf("%s-%s.yaml", scope, roleID)] = string(manifest)
+		return nil
+	}
+	for role := range utils.ProjectRolesWithVerbs() {
+		if err := render("project", utils.ProjectMemberRoleToRoleID(role), utils.ClusterRoleForRole(role), projectPermissionsForRole); err != nil {
+			return nil, err
+		}
+	}
+	for role := range utils.WorkspaceRolesWithVerbs() {
+		if err := render("workspace", utils.WorkspaceMemberRoleToRoleID(role), utils.ClusterRoleForRole(role), workspacePermissionsForRole); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// updatePermissionsDocumentInternal writes the effective permissions of the project and workspace roles into the permissions ConfigMap,
+// so that they can be reviewed without reconstructing them from the builtin permissions, the ServiceProviders and the config.
+// Nothing is written if the pod namespace is unknown.
+func (c *PWOConfigController) updatePermissionsDocumentInternal(ctx context.Context) error {
+	if c.podNamespace == "" {
+		return nil
+	}
+	data, err := permissionsDocument(c.projectPermissionsForRoleInternal, c.workspacePermissionsForRoleInternal)
+	if err != nil {
+		return err
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      PermissionsConfigMapName(c.providerName),
+			Namespace: c.podNamespace,
+		},
+	}
+	result, err := controllerutil.CreateOrUpdate(ctx, c.platformCluster.Client(), cm, func() error {
+		cm.Data = data
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update permissions ConfigMap '%s/%s': %w", cm.Namespace, cm.Name, err)
+	}
+	utils.LogOperationResult(logging.FromContextOrDiscard(ctx), logging.DEBUG, cm, result)
+	return nil
+}
@@ -0,0 +1,253 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/flowcontrol"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+	apiconst "github.com/openmcp-project/openmcp-operator/api/constants"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+)
+
+const (
+	// DefaultPropagationQPS is the default rate at which Projects and Workspaces are enqueued after a config change.
+	DefaultPropagationQPS = 5
+	// DefaultPropagationBurst is the default burst for enqueuing Projects and Workspaces after a config change.
+	DefaultPropagationBurst = 10
+)
+
+// ProjectEvents returns a channel which receives an event for each Project whenever the configuration changes in a way that affects the resources created for Projects.
+// It is meant to be passed to the project controller. Events are only sent if this method has been called before the configuration changes.
+func (c *PWOConfigController) ProjectEvents() <-chan event.GenericEvent {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.projectEvents == nil {
+		c.projectEvents = make(chan event.GenericEvent)
+	}
+	return c.projectEvents
+}
+
+// WorkspaceEvents returns a channel which receives an event for each Workspace whenever the configuration changes in a way that affects the resources created for Workspaces.
+// It is meant to be passed to the workspace controller. Events are only sent if this method has been called before the configuration changes.
+func (c *PWOConfigController) WorkspaceEvents() <-chan event.GenericEvent {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.workspaceEvents == nil {
+		c.workspaceEvents = make(chan event.GenericEvent)
+	}
+	return c.workspaceEvents
+}
+
+// Subscribe implements SharedInformation.
+// Subscribers are notified when a reconciliation of the config changes the permissions or blocking resources, but not when the config is loaded for the first time.
+func (c *PWOConfigController) Subscribe(ctx context.Context) <-chan Change {
+	return c.subscriptions.subscribe(ctx)
+}
+
+// propagatedState contains the parts of the internal state which influence the resources created for Projects and Workspaces.
+type propagatedState struct {
+	ManagementLabels                  pwv1alpha1.ManagementLabelsConfig         `json:"managementLabels"`
+	MemberOverrides                   []pwv1alpha1.MemberOverride               `json:"memberOverrides"`
+	PermissibleProjectResources       []rbacv1.PolicyRule                       `json:"permissibleProjectResources"`
+	PermissibleWorkspaceResources     []rbacv1.PolicyRule                       `json:"permissibleWorkspaceResources"`
+	ProjectPermissionsFromConfig      map[string][]rbacv1.PolicyRule            `json:"projectPermissionsFromConfig"`
+	WorkspacePermissionsFromConfig    map[string][]rbacv1.PolicyRule            `json:"workspacePermissionsFromConfig"`
+	ProjectAuditorExcludedResources   []metav1.GroupResource                    `json:"projectAuditorExcludedResources"`
+	WorkspaceAuditorExcludedResources []metav1.GroupResource                    `json:"workspaceAuditorExcludedResources"`
+	AutomationServiceAccount          pwv1alpha1.AutomationServiceAccountConfig `json:"automationServiceAccount"`
+	WorkspaceNetworkPolicies          []pwv1alpha1.NetworkPolicyTemplate        `json:"workspaceNetworkPolicies"`
+	WorkspaceAdditionalResources      []pwv1alpha1.ResourceTemplate             `json:"workspaceAdditionalResources"`
+	ServiceAccountMembers             pwv1alpha1.ServiceAccountMembersConfig    `json:"serviceAccountMembers"`
+	SecretStores                      []pwv1alpha1.SecretStoreConfig            `json:"secretStores"`
+	WorkspaceProviderHints            []pwv1alpha1.ProviderHintConfig           `json:"workspaceProviderHints"`
+	WorkspacePodSecurity              *pwv1alpha1.PodSecurityConfig             `json:"workspacePodSecurity"`
+	ConsolidatedProjectClusterRoles   bool                                      `json:"consolidatedProjectClusterRoles"`
+	ProjectDeletionGracePeriod        time.Duration                             `json:"projectDeletionGracePeriod"`
+}
+
+// propagatedStateFingerprintInternal returns a fingerprint of the parts of the internal state which influence the resources created for Projects and Workspaces.
+// The lock must be held when calling this method.
+func (c *PWOConfigController) propagatedStateFingerprintInternal() (string, error) {
+	data, err := json.Marshal(propagatedState{
+		ManagementLabels:                  c.managementLabels,
+		MemberOverrides:                   c.memberOverrides,
+		PermissibleProjectResources:       c.permissibleProjectResources,
+		PermissibleWorkspaceResources:     c.permissibleWorkspaceResources,
+		ProjectPermissionsFromConfig:      c.projectPermissionsFromConfig,
+		WorkspacePermissionsFromConfig:    c.workspacePermissionsFromConfig,
+		ProjectAuditorExcludedResources:   c.projectAuditorExcludedResources,
+		WorkspaceAuditorExcludedResources: c.workspaceAuditorExcludedResources,
+		AutomationServiceAccount:          c.automationServiceAccount,
+		WorkspaceNetworkPolicies:          c.workspaceNetworkPolicies,
+		WorkspaceAdditionalResources:      c.workspaceAdditionalResources,
+		ServiceAccountMembers:             c.serviceAccountMembers,
+		SecretStores:                      c.secretStores,
+		WorkspaceProviderHints:            c.workspaceProviderHints,
+		WorkspacePodSecurity:              c.workspacePodSecurity,
+		ConsolidatedProjectClusterRoles:   c.consolidatedProjectClusterRoles,
+		ProjectDeletionGracePeriod:        c.projectDeletionGracePeriod,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal configuration state: %w", err)
+	}
+	return string(data), nil
+}
+
+// permissionsState contains the parts of the internal state which determine the permissions of the roles in projects and workspaces.
+type permissionsState struct {
+	PermissibleProjectResources       []rbacv1.PolicyRule            `json:"permissibleProjectResources"`
+	PermissibleWorkspaceResources     []rbacv1.PolicyRule            `json:"permissibleWorkspaceResources"`
+	ProjectPermissionsFromConfig      map[string][]rbacv1.PolicyRule `json:"projectPermissionsFromConfig"`
+	WorkspacePermissionsFromConfig    map[string][]rbacv1.PolicyRule `json:"workspacePermissionsFromConfig"`
+	ProjectAuditorExcludedResources   []metav1.GroupResource         `json:"projectAuditorExcludedResources"`
+	WorkspaceAuditorExcludedResources []metav1.GroupResource         `json:"workspaceAuditorExcludedResources"`
+}
+
+// blockingResourcesState contains the parts of the internal state which determine the resources blocking the deletion of projects and workspaces.
+type blockingResourcesState struct {
+	ResourcesBlockingProjectDeletion   []DeletionBlockingResource              `json:"resourcesBlockingProjectDeletion"`
+	ResourcesBlockingWorkspaceDeletion []DeletionBlockingResource              `json:"resourcesBlockingWorkspaceDeletion"`
+	ProjectDeletionIgnoreRules         []pwv1alpha1.DeletionIgnoreRule         `json:"projectDeletionIgnoreRules"`
+	WorkspaceDeletionIgnoreRules       []pwv1alpha1.DeletionIgnoreRule         `json:"workspaceDeletionIgnoreRules"`
+	WorkspaceBlockingResourcePolicies  []pwv1alpha1.BlockingResourcePolicyRule `json:"workspaceBlockingResourcePolicies"`
+	AllowedProjectBlockingResources    []metav1.GroupVersionKind               `json:"allowedProjectBlockingResources"`
+}
+
+// changesInternal returns the changes of the permissions and blocking resources since the last call.
+// No changes are returned by the first call, because there is nothing to compare with.
+// The lock must be held when calling this method.
+func (c *PWOConfigController) changesInternal() (Change, error) {
+	permissions, err := json.Marshal(permissionsState{
+		PermissibleProjectResources:       c.permissibleProjectResources,
+		PermissibleWorkspaceResources:     c.permissibleWorkspaceResources,
+		ProjectPermissionsFromConfig:      c.projectPermissionsFromConfig,
+		WorkspacePermissionsFromConfig:    c.workspacePermissionsFromConfig,
+		ProjectAuditorExcludedResources:   c.projectAuditorExcludedResources,
+		WorkspaceAuditorExcludedResources: c.workspaceAuditorExcludedResources,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal permissions: %w", err)
+	}
+	blockingResources, err := json.Marshal(blockingResourcesState{
+		ResourcesBlockingProjectDeletion:   c.resourcesBlockingProjectDeletion,
+		ResourcesBlockingWorkspaceDeletion: c.resourcesBlockingWorkspaceDeletion,
+		ProjectDeletionIgnoreRules:         c.projectDeletionIgnoreRules,
+		WorkspaceDeletionIgnoreRules:       c.workspaceDeletionIgnoreRules,
+		WorkspaceBlockingResourcePolicies:  c.workspaceBlockingResourcePolicies,
+		AllowedProjectBlockingResources:    c.allowedProjectBlockingResources,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal blocking resources: %w", err)
+	}
+
+	var changes Change
+	if c.permissionsFingerprint != "" && c.permissionsFingerprint != string(permissions) {
+		changes |= ChangePermissions
+	}
+	if c.blockingResourcesFingerprint != "" && c.blockingResourcesFingerprint != string(blockingResources) {
+		changes |= ChangeBlockingResources
+	}
+	c.permissionsFingerprint = string(permissions)
+	c.blockingResourcesFingerprint = string(blockingResources)
+	return changes, nil
+}
+
+// propagateConfigChangesInternal enqueues all Projects and Workspaces if the configuration has changed in a way that affects them since the last call.
+// Nothing is enqueued after the configuration has been loaded for the first time, because the project and workspace controllers reconcile all resources on startup anyway.
+// The objects are enqueued asynchronously and rate-limited, a propagation which is still running is aborted when a new one is started.
+// Besides, the subscribers are notified if the permissions or blocking resources have changed.
+// The lock must be held when calling this method.
+func (c *PWOConfigController) propagateConfigChangesInternal(ctx context.Context) error {
+	log := logging.FromContextOrPanic(ctx)
+
+	changes, err := c.changesInternal()
+	if err != nil {
+		return err
+	}
+	if changes != 0 {
+		log.Info("Configuration changed, notifying subscribers", "changes", changes.String())
+		c.subscriptions.notify(changes)
+	}
+
+	fingerprint, err := c.propagatedStateFingerprintInternal()
+	if err != nil {
+		return err
+	}
+	previous := c.propagatedFingerprint
+	c.propagatedFingerprint = fingerprint
+	if previous == "" || previous == fingerprint {
+		return nil
+	}
+	if c.projectEvents == nil && c.workspaceEvents == nil {
+		log.Debug("Configuration changed, but no controllers are registered for propagation")
+		return nil
+	}
+
+	log.Info("Configuration changed, enqueuing all projects and workspaces", "qps", c.PropagationQPS, "burst", c.PropagationBurst)
+	c.startPropagationInternal(ctx)
+	return nil
+}
+
+// startPropagationInternal starts enqueuing all Projects and Workspaces asynchronously and rate-limited.
+// A propagation which is still running is aborted, since the new one enqueues all objects anyway.
+// The lock must be held when calling this method.
+func (c *PWOConfigController) startPropagationInternal(ctx context.Context) {
+	if c.cancelPropagation != nil {
+		c.cancelPropagation()
+	}
+	propagationCtx, cancel := context.WithCancel(ctx)
+	c.cancelPropagation = cancel
+	go c.propagate(propagationCtx, flowcontrol.NewTokenBucketRateLimiter(c.PropagationQPS, c.PropagationBurst), c.projectEvents, c.workspaceEvents)
+}
+
+// propagate sends an event for each Project and Workspace on the onboarding cluster to the respective channel.
+// Objects with the ignore operation annotation are skipped.
+func (c *PWOConfigController) propagate(ctx context.Context, limiter flowcontrol.RateLimiter, projectEvents, workspaceEvents chan event.GenericEvent) {
+	log := logging.FromContextOrPanic(ctx)
+	defer limiter.Stop()
+
+	for _, target := range []struct {
+		kind   string
+		events chan event.GenericEvent
+	}{
+		{kind: "Project", events: projectEvents},
+		{kind: "Workspace", events: workspaceEvents},
+	} {
+		if target.events == nil {
+			continue
+		}
+		list := &metav1.PartialObjectMetadataList{}
+		list.SetGroupVersionKind(pwv1alpha1.GroupVersion.WithKind(target.kind + "List"))
+		if err := c.OnboardingClusterAccessStatic.Client().List(ctx, list); err != nil {
+			log.Error(err, "failed to list resources for propagation", "kind", target.kind)
+			continue
+		}
+		count := 0
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if obj.GetAnnotations()[apiconst.OperationAnnotation] == apiconst.OperationAnnotationValueIgnore {
+				continue
+			}
+			if err := limiter.Wait(ctx); err != nil {
+				log.Debug("Aborting propagation", "reason", err.Error())
+				return
+			}
+			select {
+			case target.events <- event.GenericEvent{Object: obj}:
+				count++
+			case <-ctx.Done():
+				log.Debug("Aborting propagation", "reason", ctx.Err().Error())
+				return
+			}
+		}
+		log.Info("Enqueued all resources", "kind", target.kind, "count", count)
+	}
+}
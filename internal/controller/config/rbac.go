@@ -2,14 +2,26 @@ package config
 
 import (
 	"context"
+	"fmt"
 
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlcache "sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/openmcp-project/controller-utils/pkg/logging"
 
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/metrics"
 	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
 )
 
@@ -21,8 +33,22 @@ func NewRBACSetup(onboardingClient client.Client, providerName string) *RBACSetu
 }
 
 type RBACSetup struct {
-	client       client.Client
-	providerName string
+	client           client.Client
+	providerName     string
+	managementLabels pwv1alpha1.ManagementLabelsConfig
+	// created contains the names of the ClusterRoles which did not exist and have been created by EnsureResources.
+	created []string
+}
+
+// WithManagementLabels sets the configuration of the labels which are applied to the managed ClusterRoles.
+func (setup *RBACSetup) WithManagementLabels(cfg pwv1alpha1.ManagementLabelsConfig) *RBACSetup {
+	setup.managementLabels = cfg
+	return setup
+}
+
+// CreatedClusterRoles returns the names of the ClusterRoles which did not exist before and have been created by EnsureResources.
+func (setup *RBACSetup) CreatedClusterRoles() []string {
+	return setup.created
 }
 
 func (setup *RBACSetup) EnsureResources(ctx context.Context, projectPermissionsForRoleGenerator, workspacePermissionsForRoleGenerator func(string) ([]rbacv1.PolicyRule, error)) error {
@@ -48,14 +74,15 @@ func (setup *RBACSetup) createOrUpdateProjectClusterRoles(ctx context.Context, p
 		}
 
 		result, err := controllerutil.CreateOrUpdate(ctx, setup.client, clusterRole, func() error {
-			utils.SetManagementLabels(clusterRole, setup.providerName)
+			utils.ApplyManagementLabels(clusterRole, setup.providerName, setup.managementLabels)
+			utils.SetBaseClusterRoleLabel(clusterRole, setup.providerName)
 
-			var err error
 			roleID := utils.ProjectMemberRoleToRoleID(role)
-			clusterRole.Rules, err = projectPermissionsForRoleGenerator(roleID)
+			rules, err := projectPermissionsForRoleGenerator(roleID)
 			if err != nil {
 				return err
 			}
+			utils.SetRulesIfChanged(&clusterRole.Rules, rules)
 
 			return nil
 		})
@@ -63,6 +90,10 @@ func (setup *RBACSetup) createOrUpdateProjectClusterRoles(ctx context.Context, p
 			return err
 		}
 		utils.LogOperationResult(log, logging.INFO, clusterRole, result)
+		metrics.RecordRBACUpdate(clusterRole, result)
+		if result == controllerutil.OperationResultCreated {
+			setup.created = append(setup.created, clusterRole.Name)
+		}
 	}
 
 	return nil
@@ -79,14 +110,15 @@ func (setup *RBACSetup) createOrUpdateWorkspaceClusterRoles(ctx context.Context,
 		}
 
 		result, err := controllerutil.CreateOrUpdate(ctx, setup.client, clusterRole, func() error {
-			utils.SetManagementLabels(clusterRole, setup.providerName)
+			utils.ApplyManagementLabels(clusterRole, setup.providerName, setup.managementLabels)
+			utils.SetBaseClusterRoleLabel(clusterRole, setup.providerName)
 
-			var err error
 			roleID := utils.WorkspaceMemberRoleToRoleID(role)
-			clusterRole.Rules, err = workspacePermissionsForRoleGenerator(roleID)
+			rules, err := workspacePermissionsForRoleGenerator(roleID)
 			if err != nil {
 				return err
 			}
+			utils.SetRulesIfChanged(&clusterRole.Rules, rules)
 
 			return nil
 		})
@@ -94,7 +126,67 @@ func (setup *RBACSetup) createOrUpdateWorkspaceClusterRoles(ctx context.Context,
 			return err
 		}
 		utils.LogOperationResult(log, logging.INFO, clusterRole, result)
+		metrics.RecordRBACUpdate(clusterRole, result)
+		if result == controllerutil.OperationResultCreated {
+			setup.created = append(setup.created, clusterRole.Name)
+		}
 	}
 
 	return nil
 }
+
+// BaseClusterRoleNames returns the names of the ClusterRoles of the project and workspace roles, which are referenced by the RoleBindings of all projects and workspaces.
+func BaseClusterRoleNames() sets.Set[string] {
+	names := sets.New[string]()
+	for role := range utils.ProjectRolesWithVerbs() {
+		names.Insert(utils.ClusterRoleForRole(role))
+	}
+	for role := range utils.WorkspaceRolesWithVerbs() {
+		names.Insert(utils.ClusterRoleForRole(role))
+	}
+	return names
+}
+
+// baseClusterRolesSource returns a source which enqueues the config if one of the base ClusterRoles is deleted, so that it is restored.
+// The manager cache only contains the ClusterRoles of single projects and workspaces, therefore the base ClusterRoles are watched
+// with a dedicated cache, which is added to the manager. It only contains the ClusterRoles with the base ClusterRole label of this
+// platform service, which is set by the RBACSetup only, so that the ClusterRoles of the projects and workspaces are not cached twice.
+// Removing the label is treated like a deletion, since the ClusterRole disappears from the cache.
+// Creations and updates are ignored, the config controller causes them itself.
+func (c *PWOConfigController) baseClusterRolesSource(mgr ctrl.Manager) (source.Source, error) {
+	clusterRoleCache, err := ctrlcache.New(c.OnboardingClusterAccessStatic.RESTConfig(), ctrlcache.Options{
+		Scheme: c.OnboardingClusterAccessStatic.Scheme(),
+		ByObject: map[client.Object]ctrlcache.ByObject{
+			&rbacv1.ClusterRole{}: {Label: labels.SelectorFromSet(labels.Set{utils.LabelBaseClusterRole: c.providerName})},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to create cache for ClusterRoles: %w", err)
+	}
+	if err := mgr.Add(clusterRoleCache); err != nil {
+		return nil, fmt.Errorf("unable to add ClusterRole cache to manager: %w", err)
+	}
+	names := BaseClusterRoleNames()
+	return source.Kind(clusterRoleCache, &rbacv1.ClusterRole{}, handler.TypedEnqueueRequestsFromMapFunc(func(_ context.Context, _ *rbacv1.ClusterRole) []ctrl.Request {
+		return []ctrl.Request{
+			{
+				NamespacedName: types.NamespacedName{
+					Name: c.providerName,
+				},
+			},
+		}
+	}), predicate.TypedFuncs[*rbacv1.ClusterRole]{
+		CreateFunc: func(event.TypedCreateEvent[*rbacv1.ClusterRole]) bool {
+			return false
+		},
+		UpdateFunc: func(event.TypedUpdateEvent[*rbacv1.ClusterRole]) bool {
+			return false
+		},
+		DeleteFunc: func(e event.TypedDeleteEvent[*rbacv1.ClusterRole]) bool {
+			return names.Has(e.Object.GetName())
+		},
+		GenericFunc: func(event.TypedGenericEvent[*rbacv1.ClusterRole]) bool {
+			return false
+		},
+	}), nil
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -142,3 +143,25 @@ func TestRBACSetup_EnsureResources(t *testing.T) {
 		})
 	}
 }
+
+func TestRBACSetup_CreatedClusterRoles(t *testing.T) {
+	ctx := context.TODO()
+	c := fake.NewClientBuilder().Build()
+	permissions := func(string) ([]rbacv1.PolicyRule, error) {
+		return nil, nil
+	}
+
+	s := config.NewRBACSetup(c, "test-rbac-controller")
+	assert.NoError(t, s.EnsureResources(ctx, permissions, permissions))
+	assert.ElementsMatch(t, config.BaseClusterRoleNames().UnsortedList(), s.CreatedClusterRoles())
+
+	s = config.NewRBACSetup(c, "test-rbac-controller")
+	assert.NoError(t, s.EnsureResources(ctx, permissions, permissions))
+	assert.Empty(t, s.CreatedClusterRoles(), "existing ClusterRoles must not be reported as created")
+
+	deleted := utils.ClusterRoleForRole(pwv1alpha1.WorkspaceRoleAdmin)
+	assert.NoError(t, c.Delete(ctx, &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: deleted}}))
+	s = config.NewRBACSetup(c, "test-rbac-controller")
+	assert.NoError(t, s.EnsureResources(ctx, permissions, permissions))
+	assert.Equal(t, []string{deleted}, s.CreatedClusterRoles())
+}
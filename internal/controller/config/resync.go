@@ -0,0 +1,54 @@
+package config
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+)
+
+const (
+	// DefaultResyncInterval is the default interval in which all Projects and Workspaces are enqueued, regardless of configuration changes.
+	DefaultResyncInterval = 12 * time.Hour
+	// resyncJitterFactor is the maximum fraction of the resync interval which is randomly added to each wait,
+	// so that the resyncs of replicas which have been started at the same time do not coincide.
+	resyncJitterFactor = 0.1
+)
+
+// Resync enqueues all Projects and Workspaces, so that drift of the resources created for them is healed even in the absence of watch events.
+// The objects are enqueued in the same rate-limited way as for configuration changes. Nothing happens until the configuration has been loaded.
+func (c *PWOConfigController) Resync(ctx context.Context) {
+	log := logging.FromContextOrPanic(ctx)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.missingConfig || c.propagatedFingerprint == "" {
+		log.Debug("Skipping resync, because the configuration has not been loaded")
+		return
+	}
+	if c.projectEvents == nil && c.workspaceEvents == nil {
+		log.Debug("Skipping resync, because no controllers are registered for propagation")
+		return
+	}
+
+	log.Info("Resyncing, enqueuing all projects and workspaces", "qps", c.PropagationQPS, "burst", c.PropagationBurst)
+	c.startPropagationInternal(ctx)
+}
+
+// runResync calls Resync in the given interval, with jitter, until the context is cancelled.
+// The first resync happens after one interval, because the project and workspace controllers reconcile all resources on startup anyway.
+func (c *PWOConfigController) runResync(ctx context.Context, interval time.Duration) error {
+	for {
+		timer := time.NewTimer(wait.Jitter(interval, resyncJitterFactor))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+		c.Resync(ctx)
+	}
+}
@@ -2,8 +2,13 @@ package config
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/openmcp-project/controller-utils/pkg/clusters"
 
@@ -17,15 +22,72 @@ type DeletionBlockingResource struct {
 	metav1.GroupVersionKind `json:",inline"`
 	// Source is where this GVK comes from, e.g. config or a service provider. It is used for logging purposes.
 	Source string `json:"source"`
+	// Policy determines how remaining instances of this resource are handled. An empty policy blocks the deletion.
+	Policy pwov1alpha1.BlockingResourcePolicy `json:"policy,omitempty"`
 }
 
 func (dbr *DeletionBlockingResource) DeepCopy() *DeletionBlockingResource {
 	return &DeletionBlockingResource{
 		GroupVersionKind: *dbr.GroupVersionKind.DeepCopy(),
 		Source:           dbr.Source,
+		Policy:           dbr.Policy,
 	}
 }
 
+// Blocks returns true if remaining instances of this resource keep the project or workspace from being deleted.
+func (dbr *DeletionBlockingResource) Blocks() bool {
+	return dbr.Policy != pwov1alpha1.BlockingResourcePolicyWarn
+}
+
+// WithBlockingResourcePolicies sets the policy of each of the given resources according to the given rules and returns them.
+func WithBlockingResourcePolicies(rules []pwov1alpha1.BlockingResourcePolicyRule, resources []DeletionBlockingResource) []DeletionBlockingResource {
+	for i := range resources {
+		resources[i].Policy = pwov1alpha1.BlockingResourcePolicyFor(rules, resources[i].Group, resources[i].Kind)
+	}
+	return resources
+}
+
+// IsIgnoredForDeletion returns true if any of the given rules matches the given object.
+func IsIgnoredForDeletion(rules []pwov1alpha1.DeletionIgnoreRule, obj metav1.Object, group, kind string) (bool, error) {
+	for _, rule := range rules {
+		matched, err := rule.Matches(obj, group, kind)
+		if err != nil {
+			return false, fmt.Errorf("failed to evaluate deletion ignore rule: %w", err)
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Change is a set of parts of the shared information which have changed, it is sent to the channels returned by SharedInformation.Subscribe.
+type Change uint8
+
+const (
+	// ChangePermissions means that the permissions of the roles in projects or workspaces have changed.
+	ChangePermissions Change = 1 << iota
+	// ChangeBlockingResources means that the resources blocking the deletion of projects or workspaces, or the rules for handling them, have changed.
+	ChangeBlockingResources
+)
+
+// Has returns true if the set contains all of the given changes.
+func (c Change) Has(changes Change) bool {
+	return c&changes == changes
+}
+
+// String returns the names of the changes in the set, separated by commas.
+func (c Change) String() string {
+	names := []string{}
+	if c.Has(ChangePermissions) {
+		names = append(names, "Permissions")
+	}
+	if c.Has(ChangeBlockingResources) {
+		names = append(names, "BlockingResources")
+	}
+	return strings.Join(names, ",")
+}
+
 // SharedInformation holds information that is required by multiple controllers.
 // There should be one instance which every controller can access.
 // The implementation has to be thread-safe.
@@ -36,9 +98,69 @@ type SharedInformation interface {
 	// ResourcesBlockingWorkspaceDeletion returns a list of resources that should block workspace deletion.
 	// Each entry is a GroupVersionKind with an additional 'Source' field containing a string representation of the source of this information (e.g. config or a service provider).
 	ResourcesBlockingWorkspaceDeletion(ctx context.Context) ([]DeletionBlockingResource, error)
+	// ProjectDeletionIgnoreRules returns rules for resources which should be ignored when checking for resources blocking project deletion.
+	ProjectDeletionIgnoreRules(ctx context.Context) ([]pwov1alpha1.DeletionIgnoreRule, error)
+	// WorkspaceDeletionIgnoreRules returns rules for resources which should be ignored when checking for resources blocking workspace deletion.
+	WorkspaceDeletionIgnoreRules(ctx context.Context) ([]pwov1alpha1.DeletionIgnoreRule, error)
+	// WorkspaceBlockingResourcePolicies returns the rules which determine how the remaining resources of a kind blocking workspace deletion are handled.
+	// The resources returned by ResourcesBlockingWorkspaceDeletion already have their policy set, the rules are needed for the ones of WorkspaceClasses.
+	WorkspaceBlockingResourcePolicies(ctx context.Context) ([]pwov1alpha1.BlockingResourcePolicyRule, error)
+
+	// ProjectPermissionsForRole returns the RBAC rules that members with the given role (use the role IDs from the utils package) should have within a project's namespace.
+	ProjectPermissionsForRole(ctx context.Context, roleID string) ([]rbacv1.PolicyRule, error)
+	// WorkspacePermissionsForRole returns the RBAC rules that members with the given role (use the role IDs from the utils package) should have within a workspace's namespace.
+	WorkspacePermissionsForRole(ctx context.Context, roleID string) ([]rbacv1.PolicyRule, error)
 
 	// MemberOverrides returns the users and groups that should have admin permissions to projects and workspaces, bypassing the 'you must be admin of a project/workspace in order to modify it' check.
 	MemberOverrides(ctx context.Context) (pwov1alpha1.MemberOverrides, error)
+	// ExcludedWebhookIdentities returns the identities which are excluded from the webhooks' membership validation.
+	ExcludedWebhookIdentities(ctx context.Context) ([]pwov1alpha1.IdentityMatcher, error)
+	// AddCreatorAsAdmin returns whether the mutating webhooks add the requesting user as admin to new projects and workspaces without admin members.
+	AddCreatorAsAdmin(ctx context.Context) (bool, error)
+	// OperatorIdentities returns the usernames of the ServiceAccounts which are selected as further identities of the platform service, cached for the configured refresh interval.
+	// Returns nil if no ServiceAccounts are selected.
+	OperatorIdentities(ctx context.Context) (sets.Set[string], error)
+	// ProjectAdminsManageWorkspaces returns whether the validating webhooks accept requests for workspaces from admins of the parent project.
+	ProjectAdminsManageWorkspaces(ctx context.Context) (bool, error)
+	// ChargingTarget returns the validation of the charging target annotation of projects and workspaces.
+	ChargingTarget(ctx context.Context) (pwov1alpha1.ChargingTargetConfig, error)
+	// AllowedChargingTargets returns the charging targets listed in the configured ConfigMap on the platform cluster, cached for the configured duration.
+	// Returns nil if no ConfigMap is configured.
+	AllowedChargingTargets(ctx context.Context) (sets.Set[string], error)
+	// MemberPolicy returns the restrictions of the members of projects and workspaces, which are enforced by the webhooks.
+	MemberPolicy(ctx context.Context) (pwov1alpha1.MemberPolicyConfig, error)
+	// WebhookLookups returns the time budget, retries and failure policies of the lookups of the validating webhooks.
+	WebhookLookups(ctx context.Context) (pwov1alpha1.WebhookLookupsConfig, error)
+	// ManagementLabels returns the configuration of the labels which mark resources as managed by the platform service.
+	ManagementLabels(ctx context.Context) (pwov1alpha1.ManagementLabelsConfig, error)
+	// AutomationServiceAccount returns the configuration of the automation ServiceAccount of projects.
+	// If the config does not specify a maximum token expiration, the default is filled in.
+	AutomationServiceAccount(ctx context.Context) (pwov1alpha1.AutomationServiceAccountConfig, error)
+	// WorkspaceNetworkPolicies returns the templates of the NetworkPolicies which are created in every workspace namespace.
+	WorkspaceNetworkPolicies(ctx context.Context) ([]pwov1alpha1.NetworkPolicyTemplate, error)
+	// WorkspaceAdditionalResources returns the templates of the additional resources which are created in every workspace namespace.
+	WorkspaceAdditionalResources(ctx context.Context) ([]pwov1alpha1.ResourceTemplate, error)
+	// FlatWorkspaces returns whether new workspaces are pure RBAC groupings within the namespace of their project, unless their WorkspaceClass specifies otherwise.
+	FlatWorkspaces(ctx context.Context) (bool, error)
+	// ConsolidatedProjectClusterRoles returns whether the 'view' and 'auditor' ClusterRoles of each project are replaced by a single 'member' ClusterRole.
+	ConsolidatedProjectClusterRoles(ctx context.Context) (bool, error)
+	// ProjectDeletionGracePeriod returns the duration for which the teardown of a project is delayed after its deletion has been requested.
+	// Zero means that the teardown starts immediately.
+	ProjectDeletionGracePeriod(ctx context.Context) (time.Duration, error)
+	// ServiceAccountMembers returns the restrictions for the namespaces of ServiceAccounts which are members of workspaces.
+	ServiceAccountMembers(ctx context.Context) (pwov1alpha1.ServiceAccountMembersConfig, error)
+	// SecretStores returns the external secret stores which projects can reference.
+	SecretStores(ctx context.Context) ([]pwov1alpha1.SecretStoreConfig, error)
+	// AllowedProjectResourcesBlockingDeletion returns the resource types which individual projects may add to the resources blocking their deletion.
+	AllowedProjectResourcesBlockingDeletion(ctx context.Context) ([]metav1.GroupVersionKind, error)
+	// WorkspaceProviderHints returns the provider hints which workspaces can set, i.e. the configured ones whose ServiceProvider is registered.
+	WorkspaceProviderHints(ctx context.Context) ([]pwov1alpha1.ProviderHintConfig, error)
+	// WorkspacePodSecurity returns the Pod Security Admission levels of the workspace namespaces.
+	// Returns nil if pod security is not configured, in which case the Pod Security Admission labels of the workspace namespaces are not managed.
+	WorkspacePodSecurity(ctx context.Context) (*pwov1alpha1.PodSecurityConfig, error)
+	// MaxProjectsPerCreator returns the number of projects a single user can create, unless a ProjectQuota specifies otherwise.
+	// Returns nil if the number is not limited.
+	MaxProjectsPerCreator(ctx context.Context) (*int32, error)
 
 	// OnboardingClusterStatic returns the static access to the onboarding cluster.
 	// It has permissions for namespaces, rbac resources, CRDs, and Project/Workspace resources.
@@ -48,4 +170,9 @@ type SharedInformation interface {
 	// It is regularly updated to include get permissions for all resources that might block deletion of projects or workspaces.
 	// For interacting with any other resource, the static client needs to be used.
 	OnboardingClusterDynamic(ctx context.Context) (*clusters.Cluster, error)
+
+	// Subscribe returns a channel which receives the changes of the permissions and blocking resources, until the given context is cancelled.
+	// The channel is closed afterwards. Changes which have not been received before the next change happens are merged into one value,
+	// so subscribers are never blocking the notification, but need to read the current values via the other methods after receiving a change.
+	Subscribe(ctx context.Context) <-chan Change
 }
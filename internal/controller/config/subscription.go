@@ -0,0 +1,54 @@
+package config
+
+import (
+	"context"
+	"sync"
+)
+
+// subscriptions manages the channels returned by SharedInformation.Subscribe.
+// The zero value is ready to use.
+type subscriptions struct {
+	lock        sync.Mutex
+	subscribers map[chan Change]struct{}
+}
+
+// subscribe returns a channel which receives the changes passed to notify until the given context is cancelled, it is closed afterwards.
+func (s *subscriptions) subscribe(ctx context.Context) <-chan Change {
+	// the buffer holds the pending changes, so that notify never blocks
+	ch := make(chan Change, 1)
+	s.lock.Lock()
+	if s.subscribers == nil {
+		s.subscribers = map[chan Change]struct{}{}
+	}
+	s.subscribers[ch] = struct{}{}
+	s.lock.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		delete(s.subscribers, ch)
+		close(ch)
+	}()
+	return ch
+}
+
+// notify sends the given changes to all subscribers.
+// If a subscriber has not received the previous changes yet, they are merged with the new ones.
+func (s *subscriptions) notify(changes Change) {
+	if changes == 0 {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for ch := range s.subscribers {
+		// the lock ensures that there is no other sender, so the channel is empty after the select and the send below does not block
+		merged := changes
+		select {
+		case pending := <-ch:
+			merged |= pending
+		default:
+		}
+		ch <- merged
+	}
+}
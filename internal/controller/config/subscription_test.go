@@ -0,0 +1,51 @@
+package config_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+)
+
+func TestSubscribe(t *testing.T) {
+	t.Run("should send the changes to all subscribers", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		si := config.NewFakeSharedInformation(nil, nil, nil, nil)
+		first := si.Subscribe(ctx)
+		second := si.Subscribe(ctx)
+
+		si.Notify(config.ChangePermissions)
+		assert.Equal(t, config.ChangePermissions, <-first)
+		assert.Equal(t, config.ChangePermissions, <-second)
+	})
+
+	t.Run("should merge changes which have not been received yet", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		si := config.NewFakeSharedInformation(nil, nil, nil, nil)
+		changes := si.Subscribe(ctx)
+
+		si.Notify(config.ChangePermissions)
+		si.Notify(config.ChangeBlockingResources)
+		si.Notify(config.ChangePermissions)
+		change := <-changes
+		assert.True(t, change.Has(config.ChangePermissions|config.ChangeBlockingResources))
+		assert.Equal(t, "Permissions,BlockingResources", change.String())
+		assert.Empty(t, changes)
+	})
+
+	t.Run("should close the channel when the context is cancelled", func(t *testing.T) {
+		si := config.NewFakeSharedInformation(nil, nil, nil, nil)
+		ctx, cancel := context.WithCancel(context.Background())
+		changes := si.Subscribe(ctx)
+
+		cancel()
+		_, ok := <-changes
+		assert.False(t, ok)
+		// notifying after the subscription has ended must not panic
+		si.Notify(config.ChangePermissions)
+	})
+}
@@ -0,0 +1,226 @@
+// Package testing contains helpers for unit tests of controllers which depend on the PWOConfigController.
+package testing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	fakeclientset "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openmcp-project/controller-utils/pkg/clusters"
+	testutils "github.com/openmcp-project/controller-utils/pkg/testing"
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+	commonapi "github.com/openmcp-project/openmcp-operator/api/common"
+	openmcpcorev2alpha1 "github.com/openmcp-project/openmcp-operator/api/core/v2alpha1"
+	"github.com/openmcp-project/openmcp-operator/lib/clusteraccess/advanced"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/api/v2/install"
+	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+)
+
+const (
+	// PlatformClusterID is the name of the fake platform cluster in the test environment.
+	PlatformClusterID = "platform"
+	// OnboardingClusterID is the name of the fake onboarding cluster in the test environment.
+	OnboardingClusterID = "onboarding"
+	// ReconcilerID is the name under which the PWOConfigController is registered in the test environment.
+	ReconcilerID = "projectworkspaceconfig-controller"
+
+	// DefaultProviderName is the default name of the platform service, which is also the name of the ProjectWorkspaceConfig that is loaded.
+	DefaultProviderName = "project-workspace"
+	// DefaultPodNamespace is the default namespace in which the platform service is assumed to run.
+	DefaultPodNamespace = "openmcp-system"
+
+	// onboardingClusterName is the name of the Cluster resource for the onboarding cluster on the platform cluster.
+	onboardingClusterName      = "onboarding"
+	onboardingClusterNamespace = "default"
+)
+
+// AlwaysKnownAPIResources returns the API resources of the builtin resources blocking deletion.
+// The fake discovery of the built PWOConfigController always knows them, because the controller fails to resolve them otherwise.
+func AlwaysKnownAPIResources() []*metav1.APIResourceList {
+	return []*metav1.APIResourceList{
+		{
+			GroupVersion: pwv1alpha1.GroupVersion.String(),
+			APIResources: []metav1.APIResource{
+				{
+					Name:       "workspaces",
+					Group:      pwv1alpha1.GroupVersion.Group,
+					Version:    pwv1alpha1.GroupVersion.Version,
+					Kind:       "Workspace",
+					Namespaced: true,
+				},
+			},
+		},
+		{
+			GroupVersion: openmcpcorev2alpha1.GroupVersion.String(),
+			APIResources: []metav1.APIResource{
+				{
+					Name:       "managedcontrolplanev2s",
+					Group:      openmcpcorev2alpha1.GroupVersion.Group,
+					Version:    openmcpcorev2alpha1.GroupVersion.Version,
+					Kind:       "ManagedControlPlaneV2",
+					Namespaced: true,
+				},
+			},
+		},
+	}
+}
+
+// PWOConfigControllerBuilder constructs a functioning PWOConfigController for unit tests.
+// The controller works on fake platform and onboarding clusters, uses a fake discovery client, and its AccessRequests are faked to become ready immediately.
+// The dynamic onboarding cluster access returns the static onboarding cluster client.
+type PWOConfigControllerBuilder struct {
+	providerName          string
+	podNamespace          string
+	platformObjectPaths   []string
+	onboardingObjectPaths []string
+	platformObjects       []client.Object
+	onboardingObjects     []client.Object
+	apiResources          []*metav1.APIResourceList
+}
+
+// NewPWOConfigControllerBuilder creates a new PWOConfigControllerBuilder with the default provider name and pod namespace.
+func NewPWOConfigControllerBuilder() *PWOConfigControllerBuilder {
+	return &PWOConfigControllerBuilder{
+		providerName: DefaultProviderName,
+		podNamespace: DefaultPodNamespace,
+	}
+}
+
+// WithProviderName sets the name of the platform service, which is also the name of the ProjectWorkspaceConfig that is loaded.
+func (b *PWOConfigControllerBuilder) WithProviderName(providerName string) *PWOConfigControllerBuilder {
+	b.providerName = providerName
+	return b
+}
+
+// WithPodNamespace sets the namespace in which the platform service is assumed to run.
+func (b *PWOConfigControllerBuilder) WithPodNamespace(podNamespace string) *PWOConfigControllerBuilder {
+	b.podNamespace = podNamespace
+	return b
+}
+
+// WithTestDataPath loads the initial objects for the platform and onboarding cluster from the 'platform' and 'onboarding' subdirectories of the given directory.
+// Missing subdirectories are ignored.
+func (b *PWOConfigControllerBuilder) WithTestDataPath(testDirPath string) *PWOConfigControllerBuilder {
+	if dir := filepath.Join(testDirPath, "platform"); isDir(dir) {
+		b.platformObjectPaths = append(b.platformObjectPaths, dir)
+	}
+	if dir := filepath.Join(testDirPath, "onboarding"); isDir(dir) {
+		b.onboardingObjectPaths = append(b.onboardingObjectPaths, dir)
+	}
+	return b
+}
+
+// WithPlatformObjects adds initial objects for the platform cluster.
+// The ProjectWorkspaceConfig is usually one of them.
+func (b *PWOConfigControllerBuilder) WithPlatformObjects(objs ...client.Object) *PWOConfigControllerBuilder {
+	b.platformObjects = append(b.platformObjects, objs...)
+	return b
+}
+
+// WithOnboardingObjects adds initial objects for the onboarding cluster.
+func (b *PWOConfigControllerBuilder) WithOnboardingObjects(objs ...client.Object) *PWOConfigControllerBuilder {
+	b.onboardingObjects = append(b.onboardingObjects, objs...)
+	return b
+}
+
+// WithAPIResources adds API resources which are known to the fake discovery client, in addition to the ones returned by AlwaysKnownAPIResources.
+// This is required for all resources which are configured to block deletion, either in the config or by ServiceProviders.
+func (b *PWOConfigControllerBuilder) WithAPIResources(resources ...*metav1.APIResourceList) *PWOConfigControllerBuilder {
+	b.apiResources = append(b.apiResources, resources...)
+	return b
+}
+
+// Build constructs the test environment and the PWOConfigController.
+// The controller is registered in the environment as ReconcilerID, the fake clusters as PlatformClusterID and OnboardingClusterID.
+// Like the builder of the test environment, it panics if anything goes wrong.
+func (b *PWOConfigControllerBuilder) Build() (*sharedconfig.PWOConfigController, *testutils.ComplexEnvironment) {
+	envb := testutils.NewComplexEnvironmentBuilder().
+		WithFakeClient(PlatformClusterID, install.InstallOperatorAPIsPlatform(runtime.NewScheme())).
+		WithFakeClient(OnboardingClusterID, install.InstallOperatorAPIsOnboarding(runtime.NewScheme()))
+	for _, p := range b.platformObjectPaths {
+		envb = envb.WithInitObjectPath(PlatformClusterID, p)
+	}
+	for _, p := range b.onboardingObjectPaths {
+		envb = envb.WithInitObjectPath(OnboardingClusterID, p)
+	}
+	env := envb.
+		WithInitObjects(PlatformClusterID, append([]client.Object{
+			&clustersv1alpha1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      onboardingClusterName,
+					Namespace: onboardingClusterNamespace,
+				},
+			},
+		}, b.platformObjects...)...).
+		WithInitObjects(OnboardingClusterID, b.onboardingObjects...).
+		WithDynamicObjectsWithStatus(PlatformClusterID, &clustersv1alpha1.AccessRequest{}, &pwv1alpha1.ProjectWorkspaceConfig{}).
+		WithReconcilerConstructor(ReconcilerID, func(c ...client.Client) reconcile.Reconciler {
+			pwc, err := sharedconfig.NewPWConfigController(b.providerName, clusters.NewTestClusterFromClient(PlatformClusterID, c[0]), clusters.NewTestClusterFromClient(OnboardingClusterID, c[1]), &commonapi.ObjectReference{Name: onboardingClusterName, Namespace: onboardingClusterNamespace}, nil, b.podNamespace)
+			if err != nil {
+				panic(fmt.Errorf("failed to create PWOConfigController: %w", err))
+			}
+			pwc.Car.WithFakingCallback(advanced.FakingCallback_WaitingForAccessRequestReadiness, advanced.FakeAccessRequestReadiness())
+			pwc.Car.WithFakingCallback(advanced.FakingCallback_WaitingForAccessRequestDeletion, advanced.FakeAccessRequestDeletion([]string{"clusterprovider"}, nil))
+			pwc.Car.WithFakeClientGenerator(func(ctx context.Context, kcfgData []byte, scheme *runtime.Scheme, additionalData ...any) (client.Client, error) {
+				// this controller creates AccessRequests only for the onboarding cluster
+				// and the permissions are hard to test in unit tests anyway, so let's just return the static onboarding cluster client
+				return pwc.OnboardingClusterAccessStatic.Client(), nil
+			})
+			pwc.DiscoveryService = b.fakeDiscovery()
+			return pwc
+		}, PlatformClusterID, OnboardingClusterID).
+		Build()
+
+	pwc, ok := env.Reconciler(ReconcilerID).(*sharedconfig.PWOConfigController)
+	if !ok {
+		panic(fmt.Errorf("reconciler '%s' is not of type *PWOConfigController", ReconcilerID))
+	}
+
+	return pwc, env
+}
+
+// fakeDiscovery returns a fake discovery client which knows the configured API resources and the ones returned by AlwaysKnownAPIResources.
+func (b *PWOConfigControllerBuilder) fakeDiscovery() *fakediscovery.FakeDiscovery {
+	fd := fakeclientset.NewClientset().Discovery().(*fakediscovery.FakeDiscovery)
+	for _, krl := range b.apiResources {
+		fd.Resources = append(fd.Resources, krl.DeepCopy())
+	}
+	for _, akrl := range AlwaysKnownAPIResources() {
+		found := false
+		for _, krl := range fd.Resources {
+			if krl.GroupVersion == akrl.GroupVersion {
+				krl.APIResources = append(krl.APIResources, akrl.APIResources...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			fd.Resources = append(fd.Resources, akrl)
+		}
+	}
+	return fd
+}
+
+func isDir(path string) bool {
+	fi, err := os.Stat(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			panic(err)
+		}
+		return false
+	}
+	if !fi.IsDir() {
+		panic(fmt.Errorf("expected test dir '%s' to be a directory", path))
+	}
+	return true
+}
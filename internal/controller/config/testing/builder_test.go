@@ -0,0 +1,51 @@
+package testing_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+	configtesting "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config/testing"
+)
+
+func TestPWOConfigControllerBuilder(t *testing.T) {
+	pwc, env := configtesting.NewPWOConfigControllerBuilder().
+		WithPlatformObjects(&pwv1alpha1.ProjectWorkspaceConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: configtesting.DefaultProviderName,
+			},
+			Spec: pwv1alpha1.ProjectWorkspaceConfigSpec{
+				ManagementLabels: pwv1alpha1.ManagementLabelsConfig{
+					Labels: map[string]string{"example.com/team": "platform"},
+				},
+			},
+		}).
+		Build()
+
+	req := reconcile.Request{NamespacedName: client.ObjectKey{Name: configtesting.DefaultProviderName}}
+	for range 10 {
+		rr, err := env.Reconciler(configtesting.ReconcilerID).Reconcile(env.Ctx, req)
+		require.NoError(t, err)
+		if rr.RequeueAfter == 0 {
+			break
+		}
+	}
+
+	labels, err := pwc.ManagementLabels(env.Ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"example.com/team": "platform"}, labels.Labels)
+
+	resources, err := pwc.ResourcesBlockingProjectDeletion(env.Ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, sharedconfig.BuiltinResourcesBlockingProjectDeletion(), resources)
+
+	dynamic, err := pwc.OnboardingClusterDynamic(env.Ctx)
+	require.NoError(t, err)
+	assert.NotNil(t, dynamic, "the faked AccessRequest must result in a dynamic onboarding cluster access")
+}
@@ -0,0 +1,272 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
+
+	"github.com/openmcp-project/controller-utils/pkg/clusters"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+)
+
+// v1Config is a static implementation of the SharedInformation interface.
+// It is meant for v1 deployments, where the configuration is loaded once (e.g. from a file) and there are no ServiceProviders which could register additional resources.
+// Since there are no AccessRequests in v1, the static onboarding cluster access is returned for the dynamic one as well.
+type v1Config struct {
+	onboardingCluster                  *clusters.Cluster
+	resourcesBlockingProjectDeletion   []DeletionBlockingResource
+	resourcesBlockingWorkspaceDeletion []DeletionBlockingResource
+	projectDeletionIgnoreRules         []pwv1alpha1.DeletionIgnoreRule
+	workspaceDeletionIgnoreRules       []pwv1alpha1.DeletionIgnoreRule
+	workspaceBlockingResourcePolicies  []pwv1alpha1.BlockingResourcePolicyRule
+	projectPermissionsFromConfig       map[string][]rbacv1.PolicyRule
+	workspacePermissionsFromConfig     map[string][]rbacv1.PolicyRule
+	projectAuditorExcludedResources    []metav1.GroupResource
+	workspaceAuditorExcludedResources  []metav1.GroupResource
+	memberOverrides                    pwv1alpha1.MemberOverrides
+	excludedWebhookIdentities          []pwv1alpha1.IdentityMatcher
+	addCreatorAsAdmin                  bool
+	projectAdminsManageWorkspaces      bool
+	operatorIdentities                 *pwv1alpha1.OperatorIdentitiesConfig
+	operatorIdentityCache              operatorIdentityCache
+	chargingTarget                     pwv1alpha1.ChargingTargetConfig
+	memberPolicy                       pwv1alpha1.MemberPolicyConfig
+	webhookLookups                     pwv1alpha1.WebhookLookupsConfig
+	managementLabels                   pwv1alpha1.ManagementLabelsConfig
+	automationServiceAccount           pwv1alpha1.AutomationServiceAccountConfig
+	workspaceNetworkPolicies           []pwv1alpha1.NetworkPolicyTemplate
+	workspaceAdditionalResources       []pwv1alpha1.ResourceTemplate
+	flatWorkspaces                     bool
+	consolidatedProjectClusterRoles    bool
+	projectDeletionGracePeriod         time.Duration
+	serviceAccountMembers              pwv1alpha1.ServiceAccountMembersConfig
+	secretStores                       []pwv1alpha1.SecretStoreConfig
+	allowedProjectBlockingResources    []metav1.GroupVersionKind
+	maxProjectsPerCreator              *int32
+	workspacePodSecurity               *pwv1alpha1.PodSecurityConfig
+	subscriptions                      subscriptions
+}
+
+var _ SharedInformation = &v1Config{}
+
+// NewV1StaticConfig returns a SharedInformation implementation that is based on the given, static ProjectWorkspaceConfig.
+// The returned values never change, the config is not watched for updates.
+// The onboarding cluster access needs to have sufficient permissions to list all resources blocking project or workspace deletion.
+func NewV1StaticConfig(cfg *pwv1alpha1.ProjectWorkspaceConfig, onboardingCluster *clusters.Cluster) (SharedInformation, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("config must not be nil")
+	}
+	if onboardingCluster == nil {
+		return nil, fmt.Errorf("onboarding cluster access must not be nil")
+	}
+	res := &v1Config{
+		onboardingCluster:                 onboardingCluster,
+		projectPermissionsFromConfig:      projectPermissionsFromConfig(cfg),
+		workspacePermissionsFromConfig:    workspacePermissionsFromConfig(cfg),
+		projectAuditorExcludedResources:   auditorExcludedResourcesFromConfig(cfg.Spec.Project.AuditorExcludedResources),
+		workspaceAuditorExcludedResources: auditorExcludedResourcesFromConfig(cfg.Spec.Workspace.AuditorExcludedResources),
+		projectDeletionIgnoreRules:        slices.Clone(cfg.Spec.Project.IgnoredBlockingResources),
+		workspaceDeletionIgnoreRules:      slices.Clone(cfg.Spec.Workspace.IgnoredBlockingResources),
+		workspaceBlockingResourcePolicies: slices.Clone(cfg.Spec.Workspace.BlockingResourcePolicies),
+		memberOverrides:                   slices.Clone(cfg.Spec.MemberOverrides),
+		excludedWebhookIdentities:         slices.Clone(cfg.Spec.Webhook.ExcludedIdentities),
+		addCreatorAsAdmin:                 cfg.Spec.Webhook.AddCreatorAsAdmin,
+		projectAdminsManageWorkspaces:     cfg.Spec.Webhook.ProjectAdminsManageWorkspaces,
+		operatorIdentities:                cfg.Spec.Webhook.OperatorIdentities.DeepCopy(),
+		chargingTarget:                    chargingTargetFromConfig(cfg.Spec.Webhook.ChargingTarget),
+		memberPolicy:                      memberPolicyFromConfig(cfg.Spec.Webhook.MemberPolicy),
+		webhookLookups:                    webhookLookupsFromConfig(cfg.Spec.Webhook.Lookups),
+		managementLabels:                  *cfg.Spec.ManagementLabels.DeepCopy(),
+		automationServiceAccount:          automationServiceAccountFromConfig(cfg.Spec.Project.AutomationServiceAccount),
+		workspaceNetworkPolicies:          cloneNetworkPolicyTemplates(cfg.Spec.Workspace.NetworkPolicies),
+		workspaceAdditionalResources:      cloneResourceTemplates(cfg.Spec.Workspace.AdditionalResources),
+		flatWorkspaces:                    ptr.Deref(cfg.Spec.Workspace.Flat, false),
+		consolidatedProjectClusterRoles:   ptr.Deref(cfg.Spec.Project.ConsolidatedClusterRoles, false),
+		projectDeletionGracePeriod:        deletionGracePeriodFromConfig(cfg.Spec.Project.DeletionGracePeriod),
+		serviceAccountMembers:             serviceAccountMembersFromConfig(cfg.Spec.Workspace.ServiceAccountMembers),
+		secretStores:                      slices.Clone(cfg.Spec.Project.SecretStores),
+		allowedProjectBlockingResources:   slices.Clone(cfg.Spec.Project.AllowedResourcesBlockingDeletion),
+		maxProjectsPerCreator:             maxProjectsPerCreatorFromConfig(cfg.Spec.Project.MaxProjectsPerCreator),
+		workspacePodSecurity:              cfg.Spec.Workspace.PodSecurity.DeepCopy(),
+	}
+	res.resourcesBlockingProjectDeletion = append(BuiltinResourcesBlockingProjectDeletion(), deletionBlockingResourcesFromConfig(cfg.Spec.Project.ResourcesBlockingDeletion)...)
+	res.resourcesBlockingWorkspaceDeletion = WithBlockingResourcePolicies(res.workspaceBlockingResourcePolicies, append(BuiltinResourcesBlockingWorkspaceDeletion(), deletionBlockingResourcesFromConfig(cfg.Spec.Workspace.ResourcesBlockingDeletion)...))
+	return res, nil
+}
+
+// ResourcesBlockingProjectDeletion implements SharedInformation.
+func (c *v1Config) ResourcesBlockingProjectDeletion(ctx context.Context) ([]DeletionBlockingResource, error) {
+	return slices.Clone(c.resourcesBlockingProjectDeletion), nil
+}
+
+// ResourcesBlockingWorkspaceDeletion implements SharedInformation.
+func (c *v1Config) ResourcesBlockingWorkspaceDeletion(ctx context.Context) ([]DeletionBlockingResource, error) {
+	return slices.Clone(c.resourcesBlockingWorkspaceDeletion), nil
+}
+
+// ExcludedWebhookIdentities implements SharedInformation.
+func (c *v1Config) ExcludedWebhookIdentities(ctx context.Context) ([]pwv1alpha1.IdentityMatcher, error) {
+	return slices.Clone(c.excludedWebhookIdentities), nil
+}
+
+// AddCreatorAsAdmin implements SharedInformation.
+func (c *v1Config) AddCreatorAsAdmin(ctx context.Context) (bool, error) {
+	return c.addCreatorAsAdmin, nil
+}
+
+// ProjectAdminsManageWorkspaces implements SharedInformation.
+func (c *v1Config) ProjectAdminsManageWorkspaces(ctx context.Context) (bool, error) {
+	return c.projectAdminsManageWorkspaces, nil
+}
+
+// OperatorIdentities implements SharedInformation.
+func (c *v1Config) OperatorIdentities(ctx context.Context) (sets.Set[string], error) {
+	if c.operatorIdentities == nil {
+		return nil, nil
+	}
+	return c.operatorIdentityCache.get(ctx, c.onboardingCluster.Client(), c.operatorIdentities)
+}
+
+// ManagementLabels implements SharedInformation.
+func (c *v1Config) ManagementLabels(ctx context.Context) (pwv1alpha1.ManagementLabelsConfig, error) {
+	return *c.managementLabels.DeepCopy(), nil
+}
+
+// AutomationServiceAccount implements SharedInformation.
+func (c *v1Config) AutomationServiceAccount(ctx context.Context) (pwv1alpha1.AutomationServiceAccountConfig, error) {
+	return *c.automationServiceAccount.DeepCopy(), nil
+}
+
+// WorkspaceNetworkPolicies implements SharedInformation.
+func (c *v1Config) WorkspaceNetworkPolicies(ctx context.Context) ([]pwv1alpha1.NetworkPolicyTemplate, error) {
+	return cloneNetworkPolicyTemplates(c.workspaceNetworkPolicies), nil
+}
+
+// WorkspaceAdditionalResources implements SharedInformation.
+func (c *v1Config) WorkspaceAdditionalResources(ctx context.Context) ([]pwv1alpha1.ResourceTemplate, error) {
+	return cloneResourceTemplates(c.workspaceAdditionalResources), nil
+}
+
+// FlatWorkspaces implements SharedInformation.
+func (c *v1Config) FlatWorkspaces(ctx context.Context) (bool, error) {
+	return c.flatWorkspaces, nil
+}
+
+// ChargingTarget implements SharedInformation.
+func (c *v1Config) ChargingTarget(ctx context.Context) (pwv1alpha1.ChargingTargetConfig, error) {
+	return *c.chargingTarget.DeepCopy(), nil
+}
+
+// MemberPolicy implements SharedInformation.
+func (c *v1Config) MemberPolicy(ctx context.Context) (pwv1alpha1.MemberPolicyConfig, error) {
+	return c.memberPolicy, nil
+}
+
+// WebhookLookups implements SharedInformation.
+func (c *v1Config) WebhookLookups(ctx context.Context) (pwv1alpha1.WebhookLookupsConfig, error) {
+	return *c.webhookLookups.DeepCopy(), nil
+}
+
+// AllowedChargingTargets implements SharedInformation.
+// There is no platform cluster in v1, so the allowed values cannot be read from a ConfigMap.
+func (c *v1Config) AllowedChargingTargets(ctx context.Context) (sets.Set[string], error) {
+	if c.chargingTarget.AllowedValuesConfigMapName != "" {
+		return nil, fmt.Errorf("allowed charging targets from a ConfigMap are not supported in v1 mode")
+	}
+	return nil, nil
+}
+
+// ConsolidatedProjectClusterRoles implements SharedInformation.
+func (c *v1Config) ConsolidatedProjectClusterRoles(ctx context.Context) (bool, error) {
+	return c.consolidatedProjectClusterRoles, nil
+}
+
+// ProjectDeletionGracePeriod implements SharedInformation.
+func (c *v1Config) ProjectDeletionGracePeriod(ctx context.Context) (time.Duration, error) {
+	return c.projectDeletionGracePeriod, nil
+}
+
+// ServiceAccountMembers implements SharedInformation.
+func (c *v1Config) ServiceAccountMembers(ctx context.Context) (pwv1alpha1.ServiceAccountMembersConfig, error) {
+	return *c.serviceAccountMembers.DeepCopy(), nil
+}
+
+// SecretStores implements SharedInformation.
+func (c *v1Config) SecretStores(ctx context.Context) ([]pwv1alpha1.SecretStoreConfig, error) {
+	return slices.Clone(c.secretStores), nil
+}
+
+// AllowedProjectResourcesBlockingDeletion implements SharedInformation.
+func (c *v1Config) AllowedProjectResourcesBlockingDeletion(ctx context.Context) ([]metav1.GroupVersionKind, error) {
+	return slices.Clone(c.allowedProjectBlockingResources), nil
+}
+
+// WorkspaceProviderHints implements SharedInformation.
+// There are no ServiceProviders in v1, so workspaces cannot set any provider hints.
+func (c *v1Config) WorkspaceProviderHints(ctx context.Context) ([]pwv1alpha1.ProviderHintConfig, error) {
+	return nil, nil
+}
+
+// WorkspacePodSecurity implements SharedInformation.
+func (c *v1Config) WorkspacePodSecurity(ctx context.Context) (*pwv1alpha1.PodSecurityConfig, error) {
+	return c.workspacePodSecurity.DeepCopy(), nil
+}
+
+// MaxProjectsPerCreator implements SharedInformation.
+func (c *v1Config) MaxProjectsPerCreator(ctx context.Context) (*int32, error) {
+	return maxProjectsPerCreatorFromConfig(c.maxProjectsPerCreator), nil
+}
+
+// ProjectDeletionIgnoreRules implements SharedInformation.
+func (c *v1Config) ProjectDeletionIgnoreRules(ctx context.Context) ([]pwv1alpha1.DeletionIgnoreRule, error) {
+	return slices.Clone(c.projectDeletionIgnoreRules), nil
+}
+
+// WorkspaceDeletionIgnoreRules implements SharedInformation.
+func (c *v1Config) WorkspaceDeletionIgnoreRules(ctx context.Context) ([]pwv1alpha1.DeletionIgnoreRule, error) {
+	return slices.Clone(c.workspaceDeletionIgnoreRules), nil
+}
+
+// WorkspaceBlockingResourcePolicies implements SharedInformation.
+func (c *v1Config) WorkspaceBlockingResourcePolicies(ctx context.Context) ([]pwv1alpha1.BlockingResourcePolicyRule, error) {
+	return slices.Clone(c.workspaceBlockingResourcePolicies), nil
+}
+
+// ProjectPermissionsForRole implements SharedInformation.
+func (c *v1Config) ProjectPermissionsForRole(ctx context.Context, roleID string) ([]rbacv1.PolicyRule, error) {
+	return projectPermissionsForRole(roleID, nil, c.projectPermissionsFromConfig, c.projectAuditorExcludedResources)
+}
+
+// WorkspacePermissionsForRole implements SharedInformation.
+func (c *v1Config) WorkspacePermissionsForRole(ctx context.Context, roleID string) ([]rbacv1.PolicyRule, error) {
+	return workspacePermissionsForRole(roleID, nil, c.workspacePermissionsFromConfig, c.workspaceAuditorExcludedResources)
+}
+
+// MemberOverrides implements SharedInformation.
+func (c *v1Config) MemberOverrides(ctx context.Context) (pwv1alpha1.MemberOverrides, error) {
+	return slices.Clone(c.memberOverrides), nil
+}
+
+// OnboardingClusterStatic implements SharedInformation.
+func (c *v1Config) OnboardingClusterStatic(ctx context.Context) (*clusters.Cluster, error) {
+	return c.onboardingCluster, nil
+}
+
+// OnboardingClusterDynamic implements SharedInformation.
+// There is no dynamic access in v1, so the static one is returned.
+func (c *v1Config) OnboardingClusterDynamic(ctx context.Context) (*clusters.Cluster, error) {
+	return c.onboardingCluster, nil
+}
+
+// Subscribe implements SharedInformation.
+// The static config never changes, so the subscribers are never notified.
+func (c *v1Config) Subscribe(ctx context.Context) <-chan Change {
+	return c.subscriptions.subscribe(ctx)
+}
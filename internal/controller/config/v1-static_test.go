@@ -0,0 +1,126 @@
+package config_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openmcp-project/controller-utils/pkg/clusters"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+func TestV1StaticConfig(t *testing.T) {
+	ctx := context.TODO()
+	secretGVK := metav1.GroupVersionKind{Version: "v1", Kind: "Secret"}
+	cfg := &pwv1alpha1.ProjectWorkspaceConfig{
+		Spec: pwv1alpha1.ProjectWorkspaceConfigSpec{
+			Project: pwv1alpha1.ProjectConfig{
+				ResourcesBlockingDeletion: []metav1.GroupVersionKind{secretGVK},
+			},
+			Workspace: pwv1alpha1.WorkspaceConfig{
+				ResourcesBlockingDeletion: []metav1.GroupVersionKind{secretGVK},
+				AdditionalPermissions: map[pwv1alpha1.WorkspaceMemberRole][]rbacv1.PolicyRule{
+					pwv1alpha1.WorkspaceRoleView: {
+						{
+							APIGroups: []string{corev1.GroupName},
+							Resources: []string{"events"},
+						},
+					},
+				},
+			},
+			MemberOverrides: pwv1alpha1.MemberOverrides{
+				{
+					Subject: pwv1alpha1.Subject{Kind: rbacv1.UserKind, Name: "admin"},
+					Roles:   []pwv1alpha1.OverrideRole{pwv1alpha1.OverrideRoleAdmin},
+				},
+			},
+		},
+	}
+	onboarding := clusters.NewTestClusterFromClient("onboarding", fake.NewClientBuilder().Build())
+
+	t.Run("fails without config or cluster", func(t *testing.T) {
+		_, err := config.NewV1StaticConfig(nil, onboarding)
+		assert.Error(t, err)
+		_, err = config.NewV1StaticConfig(cfg, nil)
+		assert.Error(t, err)
+	})
+
+	si, err := config.NewV1StaticConfig(cfg, onboarding)
+	require.NoError(t, err)
+
+	t.Run("returns builtin and configured resources blocking deletion", func(t *testing.T) {
+		fromConfig := config.DeletionBlockingResource{GroupVersionKind: secretGVK, Source: pwv1alpha1.SourceProjectWorkspaceConfig}
+
+		projectRes, err := si.ResourcesBlockingProjectDeletion(ctx)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, append(config.BuiltinResourcesBlockingProjectDeletion(), fromConfig), projectRes)
+
+		workspaceRes, err := si.ResourcesBlockingWorkspaceDeletion(ctx)
+		assert.NoError(t, err)
+		// without policy rules, all resources block the deletion of workspaces
+		expectedWorkspaceRes := append(config.BuiltinResourcesBlockingWorkspaceDeletion(), fromConfig)
+		for i := range expectedWorkspaceRes {
+			expectedWorkspaceRes[i].Policy = pwv1alpha1.BlockingResourcePolicyBlock
+		}
+		assert.ElementsMatch(t, expectedWorkspaceRes, workspaceRes)
+	})
+
+	t.Run("returns builtin and configured permissions", func(t *testing.T) {
+		perms, err := si.WorkspacePermissionsForRole(ctx, utils.ViewerRoleID)
+		assert.NoError(t, err)
+		// the configured events are merged into the builtin rule of the core group
+		assert.Contains(t, perms, rbacv1.PolicyRule{
+			APIGroups: []string{corev1.GroupName},
+			Resources: []string{"secrets", "configmaps", "serviceaccounts", "events"},
+			Verbs:     utils.ReadOnlyVerbs(),
+		})
+
+		perms, err = si.ProjectPermissionsForRole(ctx, utils.AdminRoleID)
+		assert.NoError(t, err)
+		assert.Contains(t, perms, rbacv1.PolicyRule{
+			APIGroups: []string{corev1.GroupName},
+			Resources: []string{"serviceaccounts/token"},
+			Verbs:     []string{"create"},
+		})
+
+		_, err = si.ProjectPermissionsForRole(ctx, "unknown")
+		assert.Error(t, err)
+	})
+
+	t.Run("excludes secrets for auditors by default", func(t *testing.T) {
+		perms, err := si.WorkspacePermissionsForRole(ctx, utils.AuditorRoleID)
+		assert.NoError(t, err)
+		for _, rule := range perms {
+			assert.NotContains(t, rule.Resources, "secrets")
+		}
+		assert.Contains(t, perms, rbacv1.PolicyRule{
+			APIGroups: []string{corev1.GroupName},
+			Resources: []string{"configmaps", "serviceaccounts"},
+			Verbs:     utils.ReadOnlyVerbs(),
+		})
+	})
+
+	t.Run("returns member overrides", func(t *testing.T) {
+		overrides, err := si.MemberOverrides(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, cfg.Spec.MemberOverrides, overrides)
+	})
+
+	t.Run("returns the static onboarding cluster for both accesses", func(t *testing.T) {
+		static, err := si.OnboardingClusterStatic(ctx)
+		assert.NoError(t, err)
+		assert.Same(t, onboarding, static)
+		dynamic, err := si.OnboardingClusterDynamic(ctx)
+		assert.NoError(t, err)
+		assert.Same(t, onboarding, dynamic)
+	})
+}
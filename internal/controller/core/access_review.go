@@ -0,0 +1,233 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	authv1 "k8s.io/api/authentication/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/openmcp-project/controller-utils/pkg/clusters"
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/logconfig"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/metrics"
+)
+
+const (
+	AccessReviewControllerName = "access-review"
+
+	// DefaultAccessReviewTTL is the default time after which a completed AccessReview is deleted.
+	DefaultAccessReviewTTL = 10 * time.Minute
+)
+
+// AccessReviewReconciler computes the projects and workspaces the identity of an AccessReview has access to.
+// Portals can use it instead of issuing SelfSubjectAccessReviews for each project and workspace.
+type AccessReviewReconciler struct {
+	OnboardingStatic *clusters.Cluster
+	// TTL is the time after which a completed AccessReview is deleted, so that they do not pile up.
+	TTL time.Duration
+	*CommonReconciler
+}
+
+func NewAccessReviewReconciler(cr *CommonReconciler) (*AccessReviewReconciler, error) {
+	ar := &AccessReviewReconciler{
+		TTL:              DefaultAccessReviewTTL,
+		CommonReconciler: cr,
+	}
+
+	onboardingClusterStatic, err := cr.Config.OnboardingClusterStatic(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	ar.OnboardingStatic = onboardingClusterStatic
+
+	return ar, nil
+}
+
+// +kubebuilder:rbac:groups=core.openmcp.cloud,resources=accessreviews,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups=core.openmcp.cloud,resources=accessreviews/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core.openmcp.cloud,resources=groupsnapshots,verbs=get;list;watch
+
+func (r *AccessReviewReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logging.FromContextOrPanic(ctx).WithName(AccessReviewControllerName)
+	ctx = logging.NewContext(ctx, log)
+	log.Debug("Reconcile started")
+
+	review := &pwv1alpha1.AccessReview{}
+	if err := r.OnboardingStatic.Client().Get(ctx, req.NamespacedName, review); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("error fetching AccessReview: %w", err)
+	}
+	if !review.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	// the result is only computed once per generation, completed reviews are deleted after the TTL
+	if review.Status.CompletionTime != nil && review.Status.ObservedGeneration == review.Generation {
+		remaining := time.Until(review.Status.CompletionTime.Add(r.TTL))
+		if remaining > 0 {
+			return ctrl.Result{RequeueAfter: remaining}, nil
+		}
+		log.Info("Deleting expired AccessReview")
+		if err := r.OnboardingStatic.Client().Delete(ctx, review); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, fmt.Errorf("error deleting expired AccessReview: %w", err)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	now := metav1.Now()
+	resolvedGroups, warnings, err := r.resolveGroups(ctx, review.Spec.User, review.Spec.Groups, now.Time)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	for _, warning := range warnings {
+		log.Info("Result of AccessReview might be incomplete", "warning", warning)
+	}
+	projects, workspaces, err := r.reviewAccess(ctx, authv1.UserInfo{Username: review.Spec.User, Groups: append(slices.Clone(review.Spec.Groups), resolvedGroups...)})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	old := review.DeepCopy()
+	review.Status = pwv1alpha1.AccessReviewStatus{
+		ObservedGeneration: review.Generation,
+		CompletionTime:     &now,
+		ResolvedGroups:     resolvedGroups,
+		Warnings:           warnings,
+		Projects:           projects,
+		Workspaces:         workspaces,
+	}
+	if err := r.OnboardingStatic.Client().Status().Patch(ctx, review, client.MergeFrom(old)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("error updating AccessReview status: %w", err)
+	}
+	log.Info("Access reviewed", "projects", len(projects), "workspaces", len(workspaces))
+	return ctrl.Result{RequeueAfter: r.TTL}, nil
+}
+
+// resolveGroups returns the groups of the given user from all GroupSnapshots, sorted and without the ones which are already known.
+// Stale snapshots are used nonetheless, but a warning is returned for each of them, since the user might have joined or left groups in the meantime.
+func (r *AccessReviewReconciler) resolveGroups(ctx context.Context, user string, known []string, now time.Time) ([]string, []string, error) {
+	if user == "" {
+		return nil, nil, nil
+	}
+	snapshots := &pwv1alpha1.GroupSnapshotList{}
+	if err := r.OnboardingStatic.Client().List(ctx, snapshots); err != nil {
+		if meta.IsNoMatchError(err) {
+			// the GroupSnapshot CRD is not installed, so there is nothing to resolve
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to list GroupSnapshots: %w", err)
+	}
+	slices.SortFunc(snapshots.Items, func(a, b pwv1alpha1.GroupSnapshot) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	var groups, warnings []string
+	for i := range snapshots.Items {
+		snapshot := &snapshots.Items[i]
+		if snapshot.IsStale(now) {
+			warnings = append(warnings, fmt.Sprintf("GroupSnapshot '%s' is stale, it has been taken at %s", snapshot.Name, snapshot.Spec.SnapshotTime.UTC().Format(time.RFC3339)))
+		}
+		for _, group := range snapshot.GroupsOfUser(user) {
+			if !slices.Contains(known, group) && !slices.Contains(groups, group) {
+				groups = append(groups, group)
+			}
+		}
+	}
+	slices.Sort(groups)
+	return groups, warnings, nil
+}
+
+// reviewAccess returns the projects and workspaces the given identity has roles in or can manage via a member override, sorted by name.
+// Workspace roles include the roles inherited from the project. Projects and workspaces in deletion are included as well.
+func (r *AccessReviewReconciler) reviewAccess(ctx context.Context, userInfo authv1.UserInfo) ([]pwv1alpha1.ProjectAccess, []pwv1alpha1.WorkspaceAccess, error) {
+	overrides, err := r.Config.MemberOverrides(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get member overrides: %w", err)
+	}
+
+	projectList := &pwv1alpha1.ProjectList{}
+	if err := r.OnboardingStatic.Client().List(ctx, projectList); err != nil {
+		return nil, nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+	workspaceList := &pwv1alpha1.WorkspaceList{}
+	if err := r.OnboardingStatic.Client().List(ctx, workspaceList); err != nil {
+		return nil, nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+
+	projects := []pwv1alpha1.ProjectAccess{}
+	projectsByNamespace := map[string]*pwv1alpha1.Project{}
+	for i := range projectList.Items {
+		project := &projectList.Items[i]
+		if project.Status.Namespace != "" {
+			projectsByNamespace[project.Status.Namespace] = project
+		}
+		access := pwv1alpha1.ProjectAccess{
+			Name:          project.Name,
+			Namespace:     project.Status.Namespace,
+			Roles:         project.UserInfoRoles(userInfo),
+			AdminOverride: overrides.HasAdminOverrideForResource(&userInfo, project.Name, pwv1alpha1.OverrideResourceKindProject),
+		}
+		if len(access.Roles) == 0 && !access.AdminOverride {
+			continue
+		}
+		slices.Sort(access.Roles)
+		projects = append(projects, access)
+	}
+	slices.SortFunc(projects, func(a, b pwv1alpha1.ProjectAccess) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	workspaces := []pwv1alpha1.WorkspaceAccess{}
+	for i := range workspaceList.Items {
+		ws := &workspaceList.Items[i]
+		project := projectsByNamespace[ws.Namespace]
+		if project == nil {
+			// workspaces outside of project namespaces cannot be accessed via the project anyway
+			continue
+		}
+		effective := ws.DeepCopy()
+		effective.Spec.Members = ws.EffectiveMembers(project)
+		access := pwv1alpha1.WorkspaceAccess{
+			Project:   project.Name,
+			Name:      ws.Name,
+			Namespace: ws.Status.Namespace,
+			Roles:     effective.UserInfoRoles(userInfo),
+			// the webhook requires an override for both the workspace and its project
+			AdminOverride: overrides.HasAdminOverrideForResource(&userInfo, ws.Name, pwv1alpha1.OverrideResourceKindWorkspace) && overrides.HasAdminOverrideForResource(&userInfo, project.Name, pwv1alpha1.OverrideResourceKindProject),
+		}
+		if len(access.Roles) == 0 && !access.AdminOverride {
+			continue
+		}
+		slices.Sort(access.Roles)
+		workspaces = append(workspaces, access)
+	}
+	slices.SortFunc(workspaces, func(a, b pwv1alpha1.WorkspaceAccess) int {
+		if c := strings.Compare(a.Project, b.Project); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return projects, workspaces, nil
+}
+
+func (r *AccessReviewReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(AccessReviewControllerName).
+		WithLogConstructor(logconfig.LogConstructor(mgr.GetLogger(), AccessReviewControllerName)).
+		For(&pwv1alpha1.AccessReview{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(metrics.ObserveReconciler(AccessReviewControllerName, r))
+}
@@ -0,0 +1,175 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+)
+
+func Test_AccessReviewReconciler(t *testing.T) {
+	project := &pwv1alpha1.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: "alpha"},
+		Spec: pwv1alpha1.ProjectSpec{
+			Members: []pwv1alpha1.ProjectMember{
+				{Subject: pwv1alpha1.Subject{Kind: rbacv1.GroupKind, Name: "alpha-admins"}, Roles: []pwv1alpha1.ProjectMemberRole{pwv1alpha1.ProjectRoleAdmin}},
+				{Subject: pwv1alpha1.Subject{Kind: rbacv1.UserKind, Name: "jane"}, Roles: []pwv1alpha1.ProjectMemberRole{pwv1alpha1.ProjectRoleView}},
+			},
+		},
+		Status: pwv1alpha1.ProjectStatus{Namespace: "project-alpha"},
+	}
+	inheriting := &pwv1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "dev", Namespace: "project-alpha"},
+		Spec: pwv1alpha1.WorkspaceSpec{
+			InheritProjectMembers: &pwv1alpha1.InheritProjectMembers{Enabled: true},
+		},
+		Status: pwv1alpha1.WorkspaceStatus{Namespace: "project-alpha--ws-dev"},
+	}
+	explicit := &pwv1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "prod", Namespace: "project-alpha"},
+		Spec: pwv1alpha1.WorkspaceSpec{
+			Members: []pwv1alpha1.WorkspaceMember{
+				{Subject: pwv1alpha1.Subject{Kind: rbacv1.UserKind, Name: "jane"}, Roles: []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleAdmin}},
+			},
+		},
+		Status: pwv1alpha1.WorkspaceStatus{Namespace: "project-alpha--ws-prod"},
+	}
+	overrides := pwv1alpha1.MemberOverrides{
+		{
+			Subject:   pwv1alpha1.Subject{Kind: rbacv1.UserKind, Name: "support"},
+			Roles:     []pwv1alpha1.OverrideRole{pwv1alpha1.OverrideRoleAdmin},
+			Resources: []pwv1alpha1.OverrideResource{{Kind: pwv1alpha1.OverrideResourceKindProject, Name: "alpha"}},
+		},
+	}
+
+	newSnapshot := func(name string, age time.Duration, groups ...pwv1alpha1.GroupUsers) *pwv1alpha1.GroupSnapshot {
+		return &pwv1alpha1.GroupSnapshot{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: pwv1alpha1.GroupSnapshotSpec{
+				SnapshotTime: metav1.NewTime(time.Now().Add(-age).Truncate(time.Second)),
+				Groups:       groups,
+			},
+		}
+	}
+
+	testCases := []struct {
+		desc               string
+		spec               pwv1alpha1.AccessReviewSpec
+		snapshots          []*pwv1alpha1.GroupSnapshot
+		expectedGroups     []string
+		expectedWarnings   int
+		expectedProjects   []pwv1alpha1.ProjectAccess
+		expectedWorkspaces []pwv1alpha1.WorkspaceAccess
+	}{
+		{
+			desc: "should combine the roles of the user and its groups, including inherited workspace roles",
+			spec: pwv1alpha1.AccessReviewSpec{User: "jane", Groups: []string{"alpha-admins"}},
+			expectedProjects: []pwv1alpha1.ProjectAccess{
+				{Name: "alpha", Namespace: "project-alpha", Roles: []pwv1alpha1.ProjectMemberRole{pwv1alpha1.ProjectRoleAdmin, pwv1alpha1.ProjectRoleView}},
+			},
+			expectedWorkspaces: []pwv1alpha1.WorkspaceAccess{
+				{Project: "alpha", Name: "dev", Namespace: "project-alpha--ws-dev", Roles: []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleAdmin, pwv1alpha1.WorkspaceRoleView}},
+				{Project: "alpha", Name: "prod", Namespace: "project-alpha--ws-prod", Roles: []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleAdmin}},
+			},
+		},
+		{
+			desc: "should report member overrides",
+			spec: pwv1alpha1.AccessReviewSpec{User: "support"},
+			expectedProjects: []pwv1alpha1.ProjectAccess{
+				{Name: "alpha", Namespace: "project-alpha", AdminOverride: true},
+			},
+			expectedWorkspaces: []pwv1alpha1.WorkspaceAccess{},
+		},
+		{
+			desc: "should resolve the groups of the user from the GroupSnapshots",
+			spec: pwv1alpha1.AccessReviewSpec{User: "bob"},
+			snapshots: []*pwv1alpha1.GroupSnapshot{
+				newSnapshot("idp", time.Hour,
+					pwv1alpha1.GroupUsers{Name: "alpha-admins", Users: []string{"alice", "bob"}},
+					pwv1alpha1.GroupUsers{Name: "others", Users: []string{"carol"}},
+				),
+			},
+			expectedGroups: []string{"alpha-admins"},
+			expectedProjects: []pwv1alpha1.ProjectAccess{
+				{Name: "alpha", Namespace: "project-alpha", Roles: []pwv1alpha1.ProjectMemberRole{pwv1alpha1.ProjectRoleAdmin}},
+			},
+			expectedWorkspaces: []pwv1alpha1.WorkspaceAccess{
+				{Project: "alpha", Name: "dev", Namespace: "project-alpha--ws-dev", Roles: []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleAdmin}},
+			},
+		},
+		{
+			desc: "should use stale GroupSnapshots, but warn about them",
+			spec: pwv1alpha1.AccessReviewSpec{User: "bob", Groups: []string{"alpha-admins"}},
+			snapshots: []*pwv1alpha1.GroupSnapshot{
+				newSnapshot("idp", 2*pwv1alpha1.DefaultGroupSnapshotMaxAge, pwv1alpha1.GroupUsers{Name: "alpha-admins", Users: []string{"bob"}}),
+				newSnapshot("ldap", time.Hour, pwv1alpha1.GroupUsers{Name: "others", Users: []string{"bob"}}),
+			},
+			expectedGroups:   []string{"others"},
+			expectedWarnings: 1,
+			expectedProjects: []pwv1alpha1.ProjectAccess{
+				{Name: "alpha", Namespace: "project-alpha", Roles: []pwv1alpha1.ProjectMemberRole{pwv1alpha1.ProjectRoleAdmin}},
+			},
+			expectedWorkspaces: []pwv1alpha1.WorkspaceAccess{
+				{Project: "alpha", Name: "dev", Namespace: "project-alpha--ws-dev", Roles: []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleAdmin}},
+			},
+		},
+		{
+			desc:               "should return nothing for identities without access",
+			spec:               pwv1alpha1.AccessReviewSpec{Groups: []string{"others"}},
+			expectedProjects:   []pwv1alpha1.ProjectAccess{},
+			expectedWorkspaces: []pwv1alpha1.WorkspaceAccess{},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			review := &pwv1alpha1.AccessReview{
+				ObjectMeta: metav1.ObjectMeta{Name: "review", Generation: 1},
+				Spec:       tC.spec,
+			}
+			objs := []client.Object{project.DeepCopy(), inheriting.DeepCopy(), explicit.DeepCopy(), review}
+			for _, snapshot := range tC.snapshots {
+				objs = append(objs, snapshot)
+			}
+			c := fake.NewClientBuilder().
+				WithScheme(Scheme).
+				WithObjects(objs...).
+				WithStatusSubresource(&pwv1alpha1.AccessReview{}).
+				Build()
+			ctx := newContext()
+
+			arr, err := NewAccessReviewReconciler(NewCommonReconciler(sharedconfig.NewFakeSharedInformation(c, nil, nil, overrides), "test"))
+			require.NoError(t, err)
+			req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(review)}
+
+			rr, err := arr.Reconcile(ctx, req)
+			require.NoError(t, err)
+			assert.Equal(t, DefaultAccessReviewTTL, rr.RequeueAfter)
+
+			actual := &pwv1alpha1.AccessReview{}
+			require.NoError(t, c.Get(ctx, req.NamespacedName, actual))
+			assert.Equal(t, int64(1), actual.Status.ObservedGeneration)
+			assert.NotNil(t, actual.Status.CompletionTime)
+			assert.Equal(t, tC.expectedGroups, actual.Status.ResolvedGroups)
+			assert.Len(t, actual.Status.Warnings, tC.expectedWarnings)
+			assert.ElementsMatch(t, tC.expectedProjects, actual.Status.Projects)
+			assert.ElementsMatch(t, tC.expectedWorkspaces, actual.Status.Workspaces)
+
+			// the review is deleted once the TTL has passed
+			actual.Status.CompletionTime = &metav1.Time{Time: time.Now().Add(-DefaultAccessReviewTTL)}
+			require.NoError(t, c.Status().Update(ctx, actual))
+			_, err = arr.Reconcile(ctx, req)
+			require.NoError(t, err)
+			assert.True(t, apierrors.IsNotFound(c.Get(ctx, req.NamespacedName, actual)))
+		})
+	}
+}
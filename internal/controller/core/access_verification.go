@@ -0,0 +1,111 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	pwoerrors "github.com/openmcp-project/platform-service-project-workspace/internal/errors"
+)
+
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// verifyAccess checks via SubjectAccessReviews that the RBAC resources of the given workspace actually grant access to one of its active members
+// and sets the Ready condition accordingly. Admins are preferred, since their access covers the most bindings.
+// The checks cover the ClusterRole of the workspace, whose name might have been truncated, and the RoleBinding of the member's role, which might reference a missing ClusterRole.
+// If the access is not granted, a BlockedError is returned, so that the check is repeated with increasing backoff until the RBAC has propagated.
+func (r *WorkspaceReconciler) verifyAccess(ctx context.Context, ws *pwv1alpha1.Workspace) error {
+	member := accessVerificationMember(ws.Status.MemberStatuses)
+	if member == nil {
+		ws.SetOrUpdateCondition(pwv1alpha1.Condition{
+			Type:    pwv1alpha1.ConditionTypeReady,
+			Status:  pwv1alpha1.ConditionStatusUnknown,
+			Reason:  pwv1alpha1.ConditionReasonNoActiveMembers,
+			Message: "Access cannot be verified, because the workspace has no active members",
+		})
+		return nil
+	}
+
+	spec := authorizationv1.SubjectAccessReviewSpec{}
+	switch member.Kind {
+	case rbacv1.GroupKind:
+		spec.Groups = []string{member.Name}
+	case rbacv1.ServiceAccountKind:
+		spec.User = fmt.Sprintf("system:serviceaccount:%s:%s", member.Namespace, member.Name)
+	default:
+		spec.User = member.Name
+	}
+
+	checks := []authorizationv1.ResourceAttributes{
+		{Verb: "get", Resource: "namespaces", Name: ws.Status.Namespace},
+		{Verb: "list", Resource: "pods", Namespace: ws.Status.Namespace},
+	}
+	if slices.Contains(member.Roles, pwv1alpha1.WorkspaceRoleAdmin) {
+		checks = append(checks, authorizationv1.ResourceAttributes{Verb: "create", Resource: "configmaps", Namespace: ws.Status.Namespace})
+	}
+	for _, attributes := range checks {
+		sar := &authorizationv1.SubjectAccessReview{Spec: spec}
+		sar.Spec.ResourceAttributes = &attributes
+		if err := r.OnboardingStatic.Client().Create(ctx, sar); err != nil {
+			return fmt.Errorf("failed to create SubjectAccessReview: %w", err)
+		}
+		if !sar.Status.Allowed {
+			message := fmt.Sprintf("%s '%s' is not allowed to %s %s", member.Kind, member.Name, attributes.Verb, attributes.Resource)
+			if attributes.Name != "" {
+				message += fmt.Sprintf(" '%s'", attributes.Name)
+			}
+			if attributes.Namespace != "" {
+				message += fmt.Sprintf(" in namespace '%s'", attributes.Namespace)
+			}
+			ws.SetOrUpdateCondition(pwv1alpha1.Condition{
+				Type:    pwv1alpha1.ConditionTypeReady,
+				Status:  pwv1alpha1.ConditionStatusFalse,
+				Reason:  pwv1alpha1.ConditionReasonAccessNotGranted,
+				Message: message,
+			})
+			return pwoerrors.NewBlockedError(errors.New(message))
+		}
+	}
+
+	ws.SetOrUpdateCondition(pwv1alpha1.Condition{
+		Type:    pwv1alpha1.ConditionTypeReady,
+		Status:  pwv1alpha1.ConditionStatusTrue,
+		Reason:  pwv1alpha1.ConditionReasonAccessVerified,
+		Message: fmt.Sprintf("Access has been verified for %s '%s'", member.Kind, member.Name),
+	})
+	return nil
+}
+
+// setAccessNotGranted sets the Ready condition of the given workspace to false, because its RBAC resources could not be applied.
+func setAccessNotGranted(ws *pwv1alpha1.Workspace, rbacErr error) {
+	ws.SetOrUpdateCondition(pwv1alpha1.Condition{
+		Type:    pwv1alpha1.ConditionTypeReady,
+		Status:  pwv1alpha1.ConditionStatusFalse,
+		Reason:  pwv1alpha1.ConditionReasonAccessNotGranted,
+		Message: fmt.Sprintf("Failed to apply role bindings: %s", rbacErr.Error()),
+	})
+}
+
+// accessVerificationMember returns the member whose access is verified, which is the first active admin, or the first active member if there is no active admin.
+// Returns nil if no member is active.
+func accessVerificationMember(statuses []pwv1alpha1.WorkspaceMemberStatus) *pwv1alpha1.WorkspaceMemberStatus {
+	var res *pwv1alpha1.WorkspaceMemberStatus
+	for i := range statuses {
+		status := &statuses[i]
+		if status.Phase != pwv1alpha1.MemberPhaseActive || len(status.Roles) == 0 {
+			continue
+		}
+		if slices.Contains(status.Roles, pwv1alpha1.WorkspaceRoleAdmin) {
+			return status
+		}
+		if res == nil {
+			res = status
+		}
+	}
+	return res
+}
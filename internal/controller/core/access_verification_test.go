@@ -0,0 +1,141 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+	pwoerrors "github.com/openmcp-project/platform-service-project-workspace/internal/errors"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+// reviewAccess returns the given interceptor functions, extended to answer SubjectAccessReviews with the given decision, since the fake client cannot evaluate RBAC.
+func reviewAccess(allowed func(sar *authorizationv1.SubjectAccessReview) bool, funcs interceptor.Funcs) interceptor.Funcs {
+	create := funcs.Create
+	funcs.Create = func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+		if sar, ok := obj.(*authorizationv1.SubjectAccessReview); ok {
+			sar.Status.Allowed = allowed(sar)
+			return nil
+		}
+		if create != nil {
+			return create(ctx, c, obj, opts...)
+		}
+		return c.Create(ctx, obj, opts...)
+	}
+	return funcs
+}
+
+// allowAll grants every SubjectAccessReview.
+func allowAll(*authorizationv1.SubjectAccessReview) bool {
+	return true
+}
+
+func Test_WorkspaceReconciler_verifyAccess(t *testing.T) {
+	admin := pwv1alpha1.WorkspaceMemberStatus{
+		Subject: pwv1alpha1.Subject{Kind: rbacv1.UserKind, Name: "admin@example.com"},
+		Phase:   pwv1alpha1.MemberPhaseActive,
+		Roles:   []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleAdmin},
+	}
+	viewer := pwv1alpha1.WorkspaceMemberStatus{
+		Subject: pwv1alpha1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "default", Namespace: "default"},
+		Phase:   pwv1alpha1.MemberPhaseActive,
+		Roles:   []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleView},
+	}
+	pendingAdmin := admin
+	pendingAdmin.Phase = pwv1alpha1.MemberPhasePending
+
+	testCases := []struct {
+		desc            string
+		members         []pwv1alpha1.WorkspaceMemberStatus
+		allowed         func(sar *authorizationv1.SubjectAccessReview) bool
+		expectedStatus  pwv1alpha1.ConditionStatus
+		expectedReason  pwv1alpha1.ConditionReason
+		expectedUser    string
+		expectedChecks  int
+		expectedBlocked bool
+	}{
+		{
+			desc:           "should verify the access of an admin",
+			members:        []pwv1alpha1.WorkspaceMemberStatus{viewer, admin},
+			allowed:        allowAll,
+			expectedStatus: pwv1alpha1.ConditionStatusTrue,
+			expectedReason: pwv1alpha1.ConditionReasonAccessVerified,
+			expectedUser:   "admin@example.com",
+			expectedChecks: 3,
+		},
+		{
+			desc:           "should fall back to other active members without active admins",
+			members:        []pwv1alpha1.WorkspaceMemberStatus{pendingAdmin, viewer},
+			allowed:        allowAll,
+			expectedStatus: pwv1alpha1.ConditionStatusTrue,
+			expectedReason: pwv1alpha1.ConditionReasonAccessVerified,
+			expectedUser:   "system:serviceaccount:default:default",
+			expectedChecks: 2,
+		},
+		{
+			desc:    "should not be ready if the workspace ClusterRole does not grant access to the namespace",
+			members: []pwv1alpha1.WorkspaceMemberStatus{admin},
+			allowed: func(sar *authorizationv1.SubjectAccessReview) bool {
+				return sar.Spec.ResourceAttributes.Resource != "namespaces"
+			},
+			expectedStatus:  pwv1alpha1.ConditionStatusFalse,
+			expectedReason:  pwv1alpha1.ConditionReasonAccessNotGranted,
+			expectedUser:    "admin@example.com",
+			expectedChecks:  1,
+			expectedBlocked: true,
+		},
+		{
+			desc:           "should not verify anything without active members",
+			members:        []pwv1alpha1.WorkspaceMemberStatus{pendingAdmin},
+			allowed:        allowAll,
+			expectedStatus: pwv1alpha1.ConditionStatusUnknown,
+			expectedReason: pwv1alpha1.ConditionReasonNoActiveMembers,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			var reviews []*authorizationv1.SubjectAccessReview
+			c := fake.NewClientBuilder().
+				WithInterceptorFuncs(reviewAccess(func(sar *authorizationv1.SubjectAccessReview) bool {
+					reviews = append(reviews, sar)
+					return tC.allowed(sar)
+				}, interceptor.Funcs{})).
+				WithScheme(Scheme).
+				Build()
+			ctx := newContext()
+
+			wr, err := NewWorkspaceReconciler(c.Scheme(), NewCommonReconciler(sharedconfig.NewFakeSharedInformation(c, nil, nil, nil), "test"))
+			require.NoError(t, err)
+
+			ws := sampleWorkspace.DeepCopy()
+			ws.Status.Namespace = utils.NamespaceForWorkspace(ws)
+			ws.Status.MemberStatuses = tC.members
+
+			err = wr.verifyAccess(ctx, ws)
+			if tC.expectedBlocked {
+				assert.True(t, pwoerrors.IsBlocked(err))
+			} else {
+				assert.NoError(t, err)
+			}
+
+			condition := findCondition(ws.Status.Conditions, pwv1alpha1.ConditionTypeReady)
+			require.NotNil(t, condition)
+			assert.Equal(t, tC.expectedStatus, condition.Status)
+			assert.Equal(t, tC.expectedReason, condition.Reason)
+
+			require.Len(t, reviews, tC.expectedChecks)
+			for _, sar := range reviews {
+				assert.Equal(t, tC.expectedUser, sar.Spec.User)
+			}
+		})
+	}
+}
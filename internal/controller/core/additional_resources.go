@@ -0,0 +1,110 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/api/v2/metadata"
+	pwoerrors "github.com/openmcp-project/platform-service-project-workspace/internal/errors"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+// reconcileAdditionalResources creates or updates the additional resources from the config in the workspace namespace.
+// All applied resources are recorded in the status of the workspace, so that resources which are removed from the config can be deleted again.
+// Resources which are not managed by the platform service anymore are left untouched.
+func (r *WorkspaceReconciler) reconcileAdditionalResources(ctx context.Context, project *pwv1alpha1.Project, ws *pwv1alpha1.Workspace) error {
+	log := logging.FromContextOrPanic(ctx)
+	c := r.OnboardingStatic.Client()
+
+	templates, err := r.Config.WorkspaceAdditionalResources(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get additional resources from config: %w", err)
+	}
+	values := pwv1alpha1.NetworkPolicyTemplateValues{
+		Project:          project.Name,
+		ProjectNamespace: ws.Namespace,
+		Workspace:        ws.Name,
+		Namespace:        ws.Status.Namespace,
+	}
+
+	desired := map[pwv1alpha1.AppliedResource]bool{}
+	for i, tmpl := range templates {
+		rendered, err := tmpl.Render(values)
+		if err != nil {
+			// the template is part of the config, retrying does not help until the config is fixed
+			return pwoerrors.NewTerminalError(fmt.Errorf("failed to render additional resource %d: %w", i, err))
+		}
+		ref := pwv1alpha1.AppliedResourceOf(rendered)
+		desired[ref] = true
+
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(rendered.GroupVersionKind())
+		obj.SetNamespace(rendered.GetNamespace())
+		obj.SetName(rendered.GetName())
+		result, err := controllerutil.CreateOrUpdate(ctx, c, obj, func() error {
+			applyManifest(obj, rendered)
+			return r.applyManagementLabel(ctx, obj)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create or update %s: %w", ref, err)
+		}
+		utils.LogOperationResult(log, logging.INFO, obj, result, "kind", ref.Kind)
+		// record the resource right away, so that it is pruned later even if a subsequent resource fails
+		if !slices.Contains(ws.Status.AdditionalResources, ref) {
+			ws.Status.AdditionalResources = append(ws.Status.AdditionalResources, ref)
+		}
+	}
+
+	var errs []error
+	remaining := []pwv1alpha1.AppliedResource{}
+	for _, ref := range ws.Status.AdditionalResources {
+		if desired[ref] {
+			remaining = append(remaining, ref)
+			continue
+		}
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(ref.APIVersion)
+		obj.SetKind(ref.Kind)
+		obj.SetNamespace(ws.Status.Namespace)
+		obj.SetName(ref.Name)
+		deleted, err := r.deleteIfManaged(ctx, c, obj)
+		if err != nil && !meta.IsNoMatchError(err) {
+			errs = append(errs, fmt.Errorf("failed to delete %s: %w", ref, err))
+			remaining = append(remaining, ref)
+			continue
+		}
+		if deleted {
+			log.Info("Deleted additional resource which is not part of the config anymore", "kind", ref.Kind, "name", ref.Name)
+		}
+	}
+	if len(remaining) == 0 {
+		remaining = nil
+	}
+	ws.Status.AdditionalResources = remaining
+
+	return errors.Join(errs...)
+}
+
+// applyManifest sets the content of the rendered manifest on the given object.
+// All top-level fields except for metadata and status are replaced, while the labels and annotations of the manifest are added to the existing ones,
+// so that metadata maintained by others, e.g. the management labels, is kept.
+func applyManifest(obj, rendered *unstructured.Unstructured) {
+	for k, v := range rendered.Object {
+		if k == "metadata" || k == "status" {
+			continue
+		}
+		obj.Object[k] = runtime.DeepCopyJSONValue(v)
+	}
+	metadata.EnsureLabels(obj, rendered.GetLabels())
+	metadata.EnsureAnnotations(obj, rendered.GetAnnotations())
+}
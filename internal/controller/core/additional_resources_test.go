@@ -0,0 +1,98 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+	pwoerrors "github.com/openmcp-project/platform-service-project-workspace/internal/errors"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+func Test_WorkspaceReconciler_reconcileAdditionalResources(t *testing.T) {
+	ws := sampleWorkspace.DeepCopy()
+	ws.Status.Namespace = utils.NamespaceForWorkspace(ws)
+	managedLabels := utils.ManagementLabels("test", pwv1alpha1.ManagementLabelsConfig{})
+	drifted := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "sample-deployer",
+			Namespace:   ws.Status.Namespace,
+			Labels:      managedLabels,
+			Annotations: map[string]string{"example.com/kept": "true"},
+		},
+		AutomountServiceAccountToken: ptr.To(true),
+	}
+	stale := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: "removed-from-config", Namespace: ws.Status.Namespace, Labels: managedLabels},
+	}
+	unmanaged := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "taken-over", Namespace: ws.Status.Namespace},
+	}
+	ws.Status.AdditionalResources = []pwv1alpha1.AppliedResource{
+		{APIVersion: "v1", Kind: "ServiceAccount", Name: stale.Name},
+		{APIVersion: "v1", Kind: "ConfigMap", Name: unmanaged.Name},
+		{APIVersion: "v1", Kind: "ServiceAccount", Name: drifted.Name},
+	}
+
+	c := fake.NewClientBuilder().
+		WithObjects(projectNamespace, drifted, stale, unmanaged).
+		WithScheme(Scheme).
+		Build()
+	ctx := newContext()
+
+	cfg := sharedconfig.NewFakeSharedInformation(c, nil, nil, nil)
+	cfg.WorkspaceAdditionalResourcesData = []pwv1alpha1.ResourceTemplate{
+		{
+			Manifest: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ServiceAccount","metadata":{"name":"{{ .Workspace }}-deployer"},"automountServiceAccountToken":false}`)},
+		},
+		{
+			Manifest: runtime.RawExtension{Raw: []byte(`{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"RoleBinding","metadata":{"name":"platform-operators","labels":{"example.com/baseline":"true"}},` +
+				`"roleRef":{"apiGroup":"rbac.authorization.k8s.io","kind":"ClusterRole","name":"view"},"subjects":[{"apiGroup":"rbac.authorization.k8s.io","kind":"Group","name":"{{ .Project }}-operators"}]}`)},
+		},
+	}
+	wr, err := NewWorkspaceReconciler(c.Scheme(), NewCommonReconciler(cfg, "test"))
+	require.NoError(t, err)
+
+	require.NoError(t, wr.reconcileAdditionalResources(ctx, sampleProject, ws))
+
+	sa := &corev1.ServiceAccount{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(drifted), sa))
+	assert.Equal(t, ptr.To(false), sa.AutomountServiceAccountToken, "manual changes must be reverted")
+	assert.Equal(t, "true", sa.Annotations["example.com/kept"], "metadata which is not part of the manifest must be kept")
+
+	rb := &rbacv1.RoleBinding{}
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Name: "platform-operators", Namespace: ws.Status.Namespace}, rb))
+	assert.Equal(t, "true", rb.Labels["example.com/baseline"])
+	assert.True(t, utils.IsManaged(rb, "test", pwv1alpha1.ManagementLabelsConfig{}))
+	require.Len(t, rb.Subjects, 1)
+	assert.Equal(t, sampleProject.Name+"-operators", rb.Subjects[0].Name)
+
+	err = c.Get(ctx, client.ObjectKeyFromObject(stale), &corev1.ServiceAccount{})
+	assert.True(t, apierrors.IsNotFound(err), "managed resources which are not part of the config must be deleted")
+	assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(unmanaged), &corev1.ConfigMap{}), "unmanaged resources must not be deleted")
+
+	assert.Equal(t, []pwv1alpha1.AppliedResource{
+		{APIVersion: "v1", Kind: "ServiceAccount", Name: drifted.Name},
+		{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "RoleBinding", Name: "platform-operators"},
+	}, ws.Status.AdditionalResources)
+
+	t.Run("rejects manifests for other namespaces", func(t *testing.T) {
+		cfg.WorkspaceAdditionalResourcesData = []pwv1alpha1.ResourceTemplate{
+			{Manifest: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ServiceAccount","metadata":{"name":"sa","namespace":"{{ .ProjectNamespace }}"}}`)}},
+		}
+		err := wr.reconcileAdditionalResources(ctx, sampleProject, ws)
+		assert.Error(t, err)
+		assert.True(t, pwoerrors.IsTerminal(err))
+	})
+}
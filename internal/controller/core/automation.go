@@ -0,0 +1,196 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	ctrlutils "github.com/openmcp-project/controller-utils/pkg/controller"
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/metrics"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+// AutomationRoleBindingName is the name of the RoleBinding in the project namespace which binds the automation ServiceAccount to the project admin role.
+const AutomationRoleBindingName = pwv1alpha1.AutomationServiceAccountName
+
+// reconcileAutomationServiceAccount creates or deletes the automation ServiceAccount and its RoleBinding in the project namespace, depending on the config.
+// If a token has been requested via annotation, it is issued afterwards.
+func (r *ProjectReconciler) reconcileAutomationServiceAccount(ctx context.Context, project *pwv1alpha1.Project) error {
+	log := logging.FromContextOrPanic(ctx)
+
+	cfg, err := r.Config.AutomationServiceAccount(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get automation ServiceAccount config: %w", err)
+	}
+
+	sa := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pwv1alpha1.AutomationServiceAccountName,
+			Namespace: project.Status.Namespace,
+		},
+	}
+	roleBinding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      AutomationRoleBindingName,
+			Namespace: project.Status.Namespace,
+		},
+	}
+	tokenSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pwv1alpha1.AutomationTokenSecretName,
+			Namespace: project.Status.Namespace,
+		},
+	}
+
+	if !cfg.Enabled {
+		for _, obj := range []client.Object{tokenSecret, roleBinding, sa} {
+			if _, err := r.deleteIfManaged(ctx, r.OnboardingStatic.Client(), obj); err != nil {
+				return fmt.Errorf("failed to delete automation resource '%s': %w", client.ObjectKeyFromObject(obj).String(), err)
+			}
+		}
+		if _, requested := project.GetAnnotations()[pwv1alpha1.AutomationTokenRequestAnnotation]; requested {
+			r.recordEvent(project, corev1.EventTypeWarning, pwv1alpha1.EventReasonAutomationTokenRequestFailed, "RequestToken", "The automation ServiceAccount is not enabled for projects")
+			return r.removeAutomationTokenRequest(ctx, project)
+		}
+		return nil
+	}
+
+	result, err := controllerutil.CreateOrUpdate(ctx, r.OnboardingStatic.Client(), sa, func() error {
+		if err := r.applyManagementLabel(ctx, sa); err != nil {
+			return err
+		}
+		return controllerutil.SetOwnerReference(project, sa, r.Scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create or update automation ServiceAccount: %w", err)
+	}
+	utils.LogOperationResult(log, logging.INFO, sa, result)
+
+	result, err = controllerutil.CreateOrUpdate(ctx, r.OnboardingStatic.Client(), roleBinding, func() error {
+		if err := r.applyManagementLabel(ctx, roleBinding); err != nil {
+			return err
+		}
+
+		utils.SetSubjectsIfChanged(&roleBinding.Subjects, []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      sa.Name,
+				Namespace: sa.Namespace,
+			},
+		})
+		roleBinding.RoleRef = rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     utils.ClusterRoleForRole(pwv1alpha1.ProjectRoleAdmin),
+		}
+
+		return controllerutil.SetOwnerReference(project, roleBinding, r.Scheme)
+	})
+	utils.LogOperationResult(log, logging.INFO, roleBinding, result)
+	if err != nil {
+		return fmt.Errorf("failed to create or update automation RoleBinding: %w", err)
+	}
+	metrics.RecordRBACUpdate(roleBinding, result)
+
+	if _, requested := project.GetAnnotations()[pwv1alpha1.AutomationTokenRequestAnnotation]; requested {
+		return r.issueAutomationToken(ctx, project, sa, tokenSecret, cfg.MaxTokenExpiration)
+	}
+	return nil
+}
+
+// issueAutomationToken requests a token for the automation ServiceAccount via the TokenRequest API and stores it in the given Secret.
+// The issuance is recorded as an event on the Project, including the user who requested it, and the request annotations are removed afterwards.
+// Invalid requests are not retried, they are reported via a warning event instead.
+func (r *ProjectReconciler) issueAutomationToken(ctx context.Context, project *pwv1alpha1.Project, sa *corev1.ServiceAccount, tokenSecret *corev1.Secret, maxExpiration *metav1.Duration) error {
+	log := logging.FromContextOrPanic(ctx)
+
+	requestedBy := project.GetAnnotations()[pwv1alpha1.AutomationTokenRequestedByAnnotation]
+	if requestedBy == "" {
+		requestedBy = "<unknown>"
+	}
+	expiration, err := automationTokenExpiration(project.GetAnnotations()[pwv1alpha1.AutomationTokenRequestAnnotation], maxExpiration)
+	if err != nil {
+		r.recordEvent(project, corev1.EventTypeWarning, pwv1alpha1.EventReasonAutomationTokenRequestFailed, "RequestToken", fmt.Sprintf("Invalid token request by %s: %s", requestedBy, err.Error()))
+		return r.removeAutomationTokenRequest(ctx, project)
+	}
+
+	tr := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			ExpirationSeconds: ptr.To(int64(expiration.Seconds())),
+		},
+	}
+	if err := r.OnboardingStatic.Client().SubResource("token").Create(ctx, sa, tr); err != nil {
+		return fmt.Errorf("failed to request token for automation ServiceAccount: %w", err)
+	}
+
+	result, err := controllerutil.CreateOrUpdate(ctx, r.OnboardingStatic.Client(), tokenSecret, func() error {
+		if err := r.applyManagementLabel(ctx, tokenSecret); err != nil {
+			return err
+		}
+		tokenSecret.Type = corev1.SecretTypeOpaque
+		tokenSecret.Data = map[string][]byte{
+			pwv1alpha1.AutomationTokenSecretTokenKey:      []byte(tr.Status.Token),
+			pwv1alpha1.AutomationTokenSecretExpirationKey: []byte(tr.Status.ExpirationTimestamp.UTC().Format(time.RFC3339)),
+		}
+		return controllerutil.SetOwnerReference(project, tokenSecret, r.Scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store token for automation ServiceAccount: %w", err)
+	}
+	utils.LogOperationResult(log, logging.INFO, tokenSecret, result)
+
+	log.Info("Issued token for automation ServiceAccount", "requestedBy", requestedBy, "expirationTimestamp", tr.Status.ExpirationTimestamp.UTC().Format(time.RFC3339))
+	r.recordEvent(project, corev1.EventTypeNormal, pwv1alpha1.EventReasonAutomationTokenIssued, "IssueToken",
+		fmt.Sprintf("Issued token for ServiceAccount %s/%s requested by %s, valid until %s, stored in Secret %s", sa.Namespace, sa.Name, requestedBy, tr.Status.ExpirationTimestamp.UTC().Format(time.RFC3339), tokenSecret.Name))
+
+	return r.removeAutomationTokenRequest(ctx, project)
+}
+
+// removeAutomationTokenRequest removes the token request annotations from the given Project.
+func (r *ProjectReconciler) removeAutomationTokenRequest(ctx context.Context, project *pwv1alpha1.Project) error {
+	for _, key := range []string{pwv1alpha1.AutomationTokenRequestAnnotation, pwv1alpha1.AutomationTokenRequestedByAnnotation} {
+		if err := ctrlutils.EnsureAnnotation(ctx, r.OnboardingStatic.Client(), project, key, "", true, ctrlutils.DELETE); err != nil {
+			return fmt.Errorf("error removing annotation '%s': %w", key, err)
+		}
+	}
+	return nil
+}
+
+// recordEvent records an event for the given Project, if an event recorder is configured.
+func (r *ProjectReconciler) recordEvent(project *pwv1alpha1.Project, eventType, reason, action, note string) {
+	if r.Recorder == nil {
+		return
+	}
+	r.Recorder.Eventf(project, nil, eventType, reason, action, "%s", note)
+}
+
+// automationTokenExpiration parses the requested token lifetime from the value of the request annotation.
+// An empty value results in the maximum lifetime, longer lifetimes are capped to the maximum.
+func automationTokenExpiration(requested string, maxExpiration *metav1.Duration) (time.Duration, error) {
+	maxDuration := pwv1alpha1.DefaultMaxAutomationTokenExpiration
+	if maxExpiration != nil {
+		maxDuration = maxExpiration.Duration
+	}
+	if requested == "" {
+		return maxDuration, nil
+	}
+	expiration, err := time.ParseDuration(requested)
+	if err != nil {
+		return 0, fmt.Errorf("invalid token lifetime '%s': %w", requested, err)
+	}
+	if expiration < pwv1alpha1.MinAutomationTokenExpiration {
+		return 0, fmt.Errorf("token lifetime must be at least %s", pwv1alpha1.MinAutomationTokenExpiration)
+	}
+	return min(expiration, maxDuration), nil
+}
@@ -0,0 +1,188 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+func Test_ProjectReconciler_AutomationServiceAccount(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		enabled        bool
+		annotations    map[string]string
+		expectedEvents []string
+		validate       func(t *testing.T, ctx context.Context, c client.Client)
+	}{
+		{
+			desc:    "should create the automation ServiceAccount and bind it to the admin role",
+			enabled: true,
+			validate: func(t *testing.T, ctx context.Context, c client.Client) {
+				sa := &corev1.ServiceAccount{}
+				assert.NoError(t, c.Get(ctx, client.ObjectKey{Name: pwv1alpha1.AutomationServiceAccountName, Namespace: "project-sample"}, sa))
+
+				rb := &rbacv1.RoleBinding{}
+				assert.NoError(t, c.Get(ctx, client.ObjectKey{Name: AutomationRoleBindingName, Namespace: "project-sample"}, rb))
+				assert.Equal(t, utils.ClusterRoleForRole(pwv1alpha1.ProjectRoleAdmin), rb.RoleRef.Name)
+				assert.Equal(t, []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: pwv1alpha1.AutomationServiceAccountName, Namespace: "project-sample"}}, rb.Subjects)
+
+				err := c.Get(ctx, client.ObjectKey{Name: pwv1alpha1.AutomationTokenSecretName, Namespace: "project-sample"}, &corev1.Secret{})
+				assert.True(t, apierrors.IsNotFound(err), "no token must be issued without request")
+			},
+		},
+		{
+			desc:    "should issue a token on request and record it",
+			enabled: true,
+			annotations: map[string]string{
+				pwv1alpha1.AutomationTokenRequestAnnotation:     "30m",
+				pwv1alpha1.AutomationTokenRequestedByAnnotation: "user@example.com",
+			},
+			expectedEvents: []string{corev1.EventTypeNormal + " " + pwv1alpha1.EventReasonAutomationTokenIssued},
+			validate: func(t *testing.T, ctx context.Context, c client.Client) {
+				secret := &corev1.Secret{}
+				assert.NoError(t, c.Get(ctx, client.ObjectKey{Name: pwv1alpha1.AutomationTokenSecretName, Namespace: "project-sample"}, secret))
+				assert.Equal(t, "fake-token", string(secret.Data[pwv1alpha1.AutomationTokenSecretTokenKey]))
+				assert.NotEmpty(t, secret.Data[pwv1alpha1.AutomationTokenSecretExpirationKey])
+
+				p := &pwv1alpha1.Project{}
+				assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(sampleProject), p))
+				assert.NotContains(t, p.GetAnnotations(), pwv1alpha1.AutomationTokenRequestAnnotation)
+				assert.NotContains(t, p.GetAnnotations(), pwv1alpha1.AutomationTokenRequestedByAnnotation)
+			},
+		},
+		{
+			desc:    "should reject an invalid token request",
+			enabled: true,
+			annotations: map[string]string{
+				pwv1alpha1.AutomationTokenRequestAnnotation: "1m",
+			},
+			expectedEvents: []string{corev1.EventTypeWarning + " " + pwv1alpha1.EventReasonAutomationTokenRequestFailed},
+			validate: func(t *testing.T, ctx context.Context, c client.Client) {
+				err := c.Get(ctx, client.ObjectKey{Name: pwv1alpha1.AutomationTokenSecretName, Namespace: "project-sample"}, &corev1.Secret{})
+				assert.True(t, apierrors.IsNotFound(err))
+
+				p := &pwv1alpha1.Project{}
+				assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(sampleProject), p))
+				assert.NotContains(t, p.GetAnnotations(), pwv1alpha1.AutomationTokenRequestAnnotation)
+			},
+		},
+		{
+			desc:    "should reject token requests if the feature is disabled",
+			enabled: false,
+			annotations: map[string]string{
+				pwv1alpha1.AutomationTokenRequestAnnotation: "",
+			},
+			expectedEvents: []string{corev1.EventTypeWarning + " " + pwv1alpha1.EventReasonAutomationTokenRequestFailed},
+			validate: func(t *testing.T, ctx context.Context, c client.Client) {
+				err := c.Get(ctx, client.ObjectKey{Name: pwv1alpha1.AutomationServiceAccountName, Namespace: "project-sample"}, &corev1.ServiceAccount{})
+				assert.True(t, apierrors.IsNotFound(err))
+
+				p := &pwv1alpha1.Project{}
+				assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(sampleProject), p))
+				assert.NotContains(t, p.GetAnnotations(), pwv1alpha1.AutomationTokenRequestAnnotation)
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			project := sampleProject.DeepCopy()
+			project.SetAnnotations(tC.annotations)
+			c := fake.NewClientBuilder().
+				WithObjects(project).
+				WithStatusSubresource(project).
+				WithScheme(Scheme).
+				Build()
+			ctx := newContext()
+
+			cfg := sharedconfig.NewFakeSharedInformation(c, nil, nil, nil)
+			cfg.AutomationServiceAccountData = pwv1alpha1.AutomationServiceAccountConfig{
+				Enabled: tC.enabled,
+			}
+			pr, err := NewProjectReconciler(c.Scheme(), NewCommonReconciler(cfg, "test"))
+			require.NoError(t, err)
+			recorder := events.NewFakeRecorder(10)
+			pr.Recorder = recorder
+
+			_, err = pr.Reconcile(ctx, newRequest(project))
+			require.NoError(t, err)
+
+			close(recorder.Events)
+			recorded := []string{}
+			for e := range recorder.Events {
+				recorded = append(recorded, e)
+			}
+			require.Len(t, recorded, len(tC.expectedEvents))
+			for i := range tC.expectedEvents {
+				assert.Contains(t, recorded[i], tC.expectedEvents[i])
+			}
+
+			tC.validate(t, ctx, c)
+		})
+	}
+}
+
+func Test_automationTokenExpiration(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		requested     string
+		maxExpiration *metav1.Duration
+		expected      time.Duration
+		expectErr     bool
+	}{
+		{
+			desc:     "defaults to the default maximum",
+			expected: pwv1alpha1.DefaultMaxAutomationTokenExpiration,
+		},
+		{
+			desc:          "defaults to the configured maximum",
+			maxExpiration: &metav1.Duration{Duration: 2 * time.Hour},
+			expected:      2 * time.Hour,
+		},
+		{
+			desc:      "uses the requested lifetime",
+			requested: "30m",
+			expected:  30 * time.Minute,
+		},
+		{
+			desc:          "caps the requested lifetime to the maximum",
+			requested:     "24h",
+			maxExpiration: &metav1.Duration{Duration: 2 * time.Hour},
+			expected:      2 * time.Hour,
+		},
+		{
+			desc:      "rejects lifetimes below the minimum",
+			requested: "5m",
+			expectErr: true,
+		},
+		{
+			desc:      "rejects invalid lifetimes",
+			requested: "forever",
+			expectErr: true,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			expiration, err := automationTokenExpiration(tC.requested, tC.maxExpiration)
+			if tC.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tC.expected, expiration)
+		})
+	}
+}
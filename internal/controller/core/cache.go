@@ -0,0 +1,43 @@
+package core
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+// CacheByObject returns the label selectors which restrict the cache of the onboarding cluster manager to the resources
+// created for projects and workspaces, and to the ConfigMaps and Secrets which are labeled as membership sources. Onboarding clusters often contain many unrelated namespaces, and without these
+// selectors, every watch on one of these types would cache all of their instances cluster-wide.
+// Objects which do not match the selector are not visible through the cache, they have to be read with an uncached client,
+// like the controllers and webhooks already do for namespaces and RBAC resources.
+// Projects, Workspaces, and the other resources of the platform service are not restricted.
+func CacheByObject() map[client.Object]cache.ByObject {
+	return map[client.Object]cache.ByObject{
+		&corev1.Namespace{}:           {Label: hasLabelSelector(utils.LabelProject)},
+		&corev1.ConfigMap{}:           {Label: hasLabelSelector(pwv1alpha1.MembershipSourceLabel)},
+		&corev1.Secret{}:              {Label: hasLabelSelector(pwv1alpha1.MembershipSourceLabel)},
+		&networkingv1.NetworkPolicy{}: {Label: hasLabelSelector(utils.LabelWorkspace)},
+		&rbacv1.ClusterRole{}:         {Label: hasLabelSelector(utils.LabelOwnerUID)},
+		&rbacv1.ClusterRoleBinding{}:  {Label: hasLabelSelector(utils.LabelOwnerUID)},
+		&rbacv1.Role{}:                {Label: hasLabelSelector(utils.LabelOwnerUID)},
+		&rbacv1.RoleBinding{}:         {Label: hasLabelSelector(utils.LabelOwnerUID)},
+	}
+}
+
+// hasLabelSelector returns a selector which matches all objects that carry the given label, independent of its value.
+func hasLabelSelector(key string) labels.Selector {
+	req, err := labels.NewRequirement(key, selection.Exists, nil)
+	if err != nil {
+		// the keys are constants, so this can only happen due to a programming error
+		panic(err)
+	}
+	return labels.NewSelector().Add(*req)
+}
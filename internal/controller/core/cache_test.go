@@ -0,0 +1,79 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/api/v2/metadata"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+func Test_CacheByObject(t *testing.T) {
+	byObject := CacheByObject()
+	selectorFor := func(obj client.Object) labels.Selector {
+		for k, v := range byObject {
+			if reflect.TypeOf(k) == reflect.TypeOf(obj) {
+				return v.Label
+			}
+		}
+		t.Fatalf("no cache selector for %T", obj)
+		return nil
+	}
+
+	project := sampleProject.DeepCopy()
+	project.UID = types.UID("project-uid")
+
+	tests := []struct {
+		description string
+		labeled     client.Object
+		unlabeled   client.Object
+	}{
+		{
+			description: "namespaces of projects and workspaces",
+			labeled:     withLabels(&corev1.Namespace{}, func(obj metav1.Object) { utils.SetProjectLabel(obj, project.Name) }),
+			unlabeled:   &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+		},
+		{
+			description: "ConfigMaps and Secrets of membership sources",
+			labeled:     withLabels(&corev1.ConfigMap{}, func(obj metav1.Object) { metadata.SetLabel(obj, pwv1alpha1.MembershipSourceLabel, "true") }),
+			unlabeled:   &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "kube-root-ca.crt"}},
+		},
+		{
+			description: "NetworkPolicies of workspaces",
+			labeled:     withLabels(&networkingv1.NetworkPolicy{}, func(obj metav1.Object) { utils.SetWorkspaceLabel(obj, "ws") }),
+			unlabeled:   withLabels(&networkingv1.NetworkPolicy{}, func(obj metav1.Object) { utils.SetProjectLabel(obj, project.Name) }),
+		},
+		{
+			description: "ClusterRoles of projects and workspaces",
+			labeled:     withLabels(&rbacv1.ClusterRole{}, func(obj metav1.Object) { utils.SetOwnerUIDLabel(obj, project) }),
+			unlabeled:   withLabels(&rbacv1.ClusterRole{}, func(obj metav1.Object) { utils.SetManagementLabels(obj, "test") }),
+		},
+		{
+			description: "RoleBindings of projects and workspaces",
+			labeled:     withLabels(&rbacv1.RoleBinding{}, func(obj metav1.Object) { utils.SetOwnerUIDLabel(obj, project) }),
+			unlabeled:   &rbacv1.RoleBinding{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			selector := selectorFor(test.labeled)
+			assert.True(t, selector.Matches(labels.Set(test.labeled.GetLabels())))
+			assert.False(t, selector.Matches(labels.Set(test.unlabeled.GetLabels())))
+		})
+	}
+}
+
+func withLabels[T client.Object](obj T, setLabels func(metav1.Object)) T {
+	setLabels(obj)
+	return obj
+}
@@ -3,24 +3,35 @@ package core
 import (
 	"context"
 	"fmt"
-	"reflect"
+	"maps"
+	"slices"
+	"strings"
 	"time"
 
 	"k8s.io/apimachinery/pkg/util/json"
 
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"github.com/openmcp-project/controller-utils/pkg/clusters"
 	"github.com/openmcp-project/controller-utils/pkg/controller/smartrequeue"
 
 	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
 	"github.com/openmcp-project/platform-service-project-workspace/api/v2/install"
+	"github.com/openmcp-project/platform-service-project-workspace/api/v2/metadata"
 	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
 	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/core/config"
+	pwoerrors "github.com/openmcp-project/platform-service-project-workspace/internal/errors"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/metrics"
 	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
 )
 
@@ -32,6 +43,13 @@ var (
 	ControllerName = "project-workspace"
 )
 
+const (
+	// remainingContentPageSize is the maximum number of resources which are listed at once when checking for resources blocking the deletion.
+	remainingContentPageSize = 500
+	// maxRemainingContentExamples is the maximum number of remaining resources which are listed in the details of the ContentRemaining condition.
+	maxRemainingContentExamples = 20
+)
+
 func init() {
 	install.InstallOperatorAPIsOnboarding(Scheme)
 }
@@ -39,7 +57,10 @@ func init() {
 type CommonReconciler struct {
 	Config       sharedconfig.SharedInformation
 	ProviderName string
-	sr           *smartrequeue.Store // the store's key includes kind, so we can use one store for both Projects and Workspaces
+	// ShutdownGracePeriod is the time in-flight reconciliations get to complete when the manager is stopped, before their context is cancelled.
+	ShutdownGracePeriod time.Duration
+	sr                  *smartrequeue.Store // the store's key includes kind, so we can use one store for both Projects and Workspaces
+	status              *StatusWriter       // shared for the same reason, its key includes the type of the object
 }
 
 func NewCommonReconciler(config sharedconfig.SharedInformation, providerName string) *CommonReconciler {
@@ -47,6 +68,7 @@ func NewCommonReconciler(config sharedconfig.SharedInformation, providerName str
 		Config:       config,
 		ProviderName: providerName,
 		sr:           smartrequeue.NewStore(5*time.Second, 24*time.Hour, 1.2),
+		status:       NewStatusWriter(defaultStatusWriteInterval, defaultStatusWriteBurst),
 	}
 }
 
@@ -65,7 +87,11 @@ func (r *CommonReconciler) ensureFinalizer(ctx context.Context, o client.Object)
 	return nil
 }
 
-func (r *CommonReconciler) handleRemainingContentBeforeDelete(ctx context.Context, o client.Object) (bool, error) {
+// handleRemainingContentBeforeDelete checks whether the namespace of the given Project or Workspace in deletion still contains resources blocking the deletion.
+// Resource types whose CRD is not installed on the onboarding cluster are skipped, a warning event is recorded on the object via the given recorder (may be nil) in this case.
+// For workspaces, the blocking resource policy of each resource type is taken into account: remaining resources with policy 'Cascade' are deleted
+// and block the deletion until they are gone, remaining resources with policy 'Warn' are reported, but do not block the deletion.
+func (r *CommonReconciler) handleRemainingContentBeforeDelete(ctx context.Context, o client.Object, recorder events.EventRecorder) (bool, error) {
 	if !utils.WasDeleted(o) {
 		return false, nil
 	}
@@ -79,6 +105,7 @@ func (r *CommonReconciler) handleRemainingContentBeforeDelete(ctx context.Contex
 
 	var namespace string
 	var resourcesBlockingDeletion []sharedconfig.DeletionBlockingResource
+	var ignoreRules []pwv1alpha1.DeletionIgnoreRule
 	var err error
 
 	if isProject {
@@ -88,139 +115,455 @@ func (r *CommonReconciler) handleRemainingContentBeforeDelete(ctx context.Contex
 		if err != nil {
 			return false, fmt.Errorf("failed to get resources blocking project deletion: %w", err)
 		}
+		projectResources, err := r.resourcesBlockingDeletionFromProject(ctx, project)
+		if err != nil {
+			return false, err
+		}
+		resourcesBlockingDeletion = append(resourcesBlockingDeletion, projectResources...)
 		if len(resourcesBlockingDeletion) == 0 {
 			return false, nil
 		}
+
+		ignoreRules, err = r.Config.ProjectDeletionIgnoreRules(ctx)
+		if err != nil {
+			return false, fmt.Errorf("failed to get ignore rules for project deletion: %w", err)
+		}
 	} else {
+		if workspace.IsFlat() {
+			// flat workspaces share the namespace of their project, its content does not belong to them
+			return false, nil
+		}
 		namespace = workspace.Status.Namespace
 
 		resourcesBlockingDeletion, err = r.Config.ResourcesBlockingWorkspaceDeletion(ctx)
 		if err != nil {
 			return false, fmt.Errorf("failed to get resources blocking workspace deletion: %w", err)
 		}
+		classResources, err := r.resourcesBlockingDeletionFromClass(ctx, workspace)
+		if err != nil {
+			return false, err
+		}
+		policies, err := r.Config.WorkspaceBlockingResourcePolicies(ctx)
+		if err != nil {
+			return false, fmt.Errorf("failed to get blocking resource policies for workspace deletion: %w", err)
+		}
+		resourcesBlockingDeletion = append(resourcesBlockingDeletion, sharedconfig.WithBlockingResourcePolicies(policies, classResources)...)
 		if len(resourcesBlockingDeletion) == 0 {
 			return false, nil
 		}
+
+		ignoreRules, err = r.Config.WorkspaceDeletionIgnoreRules(ctx)
+		if err != nil {
+			return false, fmt.Errorf("failed to get ignore rules for workspace deletion: %w", err)
+		}
+	}
+
+	// signal ServiceProviders that they should clean up their resources in the namespace
+	if err := r.signalDeletionRequested(ctx, namespace); err != nil {
+		return false, err
 	}
 
-	remainingResources := make([]unstructured.Unstructured, 0)
+	// only the number of remaining resources per source and some examples are kept, to limit the memory usage for namespaces with many resources
+	remainingResources := make([]pwv1alpha1.RemainingContentResource, 0, maxRemainingContentExamples)
+	remainingCounts := map[string]int{}
+	remainingTotal := 0
+	// resources whose policy does not block the deletion are only counted
+	nonBlockingCounts := map[string]int{}
+	nonBlockingTotal := 0
 	var remainingResourcesCondition pwv1alpha1.Condition
 
 	log := log.FromContext(ctx)
-	onboardingCluster, err := r.Config.OnboardingClusterDynamic(ctx)
-	if err != nil {
-		return false, fmt.Errorf("failed to get onboarding cluster access: %w", err)
+	dynamicCluster, dynamicErr := r.Config.OnboardingClusterDynamic(ctx)
+	if dynamicErr != nil {
+		log.Info("dynamic onboarding cluster access not available, falling back to static access for builtin resource types", "error", dynamicErr.Error())
 	}
 
 	for _, br := range resourcesBlockingDeletion {
-		resList := &unstructured.UnstructuredList{}
-		resList.SetGroupVersionKind(config.ToSchemaGVK(br.GroupVersionKind))
-
-		if err := onboardingCluster.Client().List(ctx, resList, client.InNamespace(namespace)); err != nil {
-			log.Error(err, "failed to list resources")
+		c, err := r.blockingResourceClient(ctx, br, dynamicCluster, dynamicErr)
+		if err != nil {
 			return false, err
 		}
 
-		if len(resList.Items) > 0 {
-			remainingResources = append(remainingResources, resList.Items...)
+		gvk := config.ToSchemaGVK(br.GroupVersionKind)
+		resList := &metav1.PartialObjectMetadataList{}
+		resList.SetGroupVersionKind(gvk)
+
+		crdMissing := false
+		for {
+			if err := c.List(ctx, resList, client.InNamespace(namespace), client.Limit(remainingContentPageSize), client.Continue(resList.Continue)); err != nil {
+				if meta.IsNoMatchError(err) || apierrors.IsNotFound(err) {
+					crdMissing = true
+					break
+				}
+				log.Error(err, "failed to list resources")
+				return false, err
+			}
+
+			for i := range resList.Items {
+				item := &resList.Items[i]
+				ignored, err := sharedconfig.IsIgnoredForDeletion(ignoreRules, item, br.Group, br.Kind)
+				if err != nil {
+					return false, err
+				}
+				if ignored {
+					log.V(1).Info("ignoring resource blocking deletion", "resource", fmt.Sprintf("%s/%s", gvk.Kind, item.GetName()))
+					continue
+				}
+				if !br.Blocks() {
+					nonBlockingTotal++
+					nonBlockingCounts[br.Source]++
+					continue
+				}
+				if br.Policy == pwv1alpha1.BlockingResourcePolicyCascade && item.GetDeletionTimestamp() == nil {
+					// metadata-only lists do not contain the type of their items, it is required for the deletion though
+					item.SetGroupVersionKind(gvk)
+					if err := c.Delete(ctx, item); client.IgnoreNotFound(err) != nil {
+						return false, fmt.Errorf("failed to delete remaining resource %s '%s/%s': %w", gvk.Kind, item.GetNamespace(), item.GetName(), err)
+					}
+					log.Info("Deleted remaining resource because of blocking resource policy 'Cascade'", "resource", fmt.Sprintf("%s/%s", gvk.Kind, item.GetName()))
+				}
+				remainingTotal++
+				remainingCounts[br.Source]++
+				if len(remainingResources) < maxRemainingContentExamples {
+					// metadata-only lists do not contain the type of their items, so it is taken from the listed resource type
+					remainingResources = append(remainingResources, pwv1alpha1.RemainingContentResource{
+						APIGroup:  gvk.GroupVersion().String(),
+						Kind:      gvk.Kind,
+						Name:      item.GetName(),
+						Namespace: item.GetNamespace(),
+						Source:    br.Source,
+					})
+				}
+			}
+
+			if resList.Continue == "" {
+				break
+			}
 		}
-	}
 
-	if len(remainingResources) > 0 {
-		resources := make([]pwv1alpha1.RemainingContentResource, 0, len(remainingResources))
+		if crdMissing {
+			log.Info("skipping resource type blocking deletion, because its CRD is not installed", "gvk", gvk.String())
+			if recorder != nil {
+				recorder.Eventf(o, nil, corev1.EventTypeWarning, pwv1alpha1.EventReasonBlockingResourceKindMissing, "CheckRemainingContent",
+					"Skipped resource type %s while checking for resources blocking the deletion, because its CRD is not installed", gvk.String())
+			}
+		}
+	}
 
+	if remainingTotal > 0 {
 		remainingResourcesCondition = pwv1alpha1.Condition{
 			Type:    pwv1alpha1.ConditionTypeContentRemaining,
 			Status:  pwv1alpha1.ConditionStatusTrue,
 			Reason:  pwv1alpha1.ConditionReasonResourcesRemaining,
-			Message: fmt.Sprintf("There are %d remaining resources in namespace %s that are preventing deletion", len(remainingResources), namespace),
-		}
-
-		for _, res := range remainingResources {
-			resources = append(resources, pwv1alpha1.RemainingContentResource{
-				APIGroup:  res.GetAPIVersion(),
-				Kind:      res.GetKind(),
-				Name:      res.GetName(),
-				Namespace: res.GetNamespace(),
-			})
+			Message: fmt.Sprintf("There are %d remaining resources in namespace %s that are preventing deletion (%s)", remainingTotal, namespace, summarizeRemainingResourcesBySource(remainingCounts)),
 		}
 
-		resourcesMarshalled, err := json.Marshal(resources)
+		detailsMarshalled, err := json.Marshal(pwv1alpha1.RemainingContentDetails{
+			Count:     remainingTotal,
+			Resources: remainingResources,
+		})
 		if err != nil {
 			log.Error(err, "failed to marshal resources")
 			return false, err
 		}
 
-		remainingResourcesCondition.Details = resourcesMarshalled
+		remainingResourcesCondition.Details = detailsMarshalled
 
+		// the count is duplicated into the status, so that it can be shown as printer column
 		if isProject {
 			project.SetOrUpdateCondition(remainingResourcesCondition)
+			project.Status.BlockingResourceCount = int32(remainingTotal)
 		} else {
 			workspace.SetOrUpdateCondition(remainingResourcesCondition)
+			workspace.Status.BlockingResourceCount = int32(remainingTotal)
 		}
 
 		return true, nil
+	} else if nonBlockingTotal > 0 {
+		// only workspaces can have resources with a policy which does not block the deletion
+		workspace.SetOrUpdateCondition(pwv1alpha1.Condition{
+			Type:    pwv1alpha1.ConditionTypeContentRemaining,
+			Status:  pwv1alpha1.ConditionStatusFalse,
+			Reason:  pwv1alpha1.ConditionReasonResourcesNotBlocking,
+			Message: fmt.Sprintf("There are %d remaining resources in namespace %s which do not prevent deletion (%s)", nonBlockingTotal, namespace, summarizeRemainingResourcesBySource(nonBlockingCounts)),
+		})
+		workspace.Status.BlockingResourceCount = 0
+		if recorder != nil {
+			recorder.Eventf(o, nil, corev1.EventTypeWarning, pwv1alpha1.EventReasonNonBlockingResourcesRemaining, "CheckRemainingContent",
+				"Deleting namespace %s with %d remaining resources (%s)", namespace, nonBlockingTotal, summarizeRemainingResourcesBySource(nonBlockingCounts))
+		}
 	} else {
 		if isProject {
 			project.RemoveCondition(pwv1alpha1.ConditionTypeContentRemaining)
+			project.Status.BlockingResourceCount = 0
 		} else {
 			workspace.RemoveCondition(pwv1alpha1.ConditionTypeContentRemaining)
+			workspace.Status.BlockingResourceCount = 0
 		}
 	}
 
 	return false, nil
 }
-func (r *CommonReconciler) handleDelete(ctx context.Context, o client.Object, deleteFunc func() error) (bool, RequeueType, error) {
+
+// resourcesBlockingDeletionFromClass returns the resource types which block the deletion of the given workspace because of its WorkspaceClass.
+// If the class does not exist anymore, it does not block the deletion.
+func (r *CommonReconciler) resourcesBlockingDeletionFromClass(ctx context.Context, ws *pwv1alpha1.Workspace) ([]sharedconfig.DeletionBlockingResource, error) {
+	class, err := r.getWorkspaceClass(ctx, ws)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			log.FromContext(ctx).Info("ignoring resources blocking deletion of WorkspaceClass, because it does not exist", "workspaceClass", ws.Spec.ClassName)
+			return nil, nil
+		}
+		return nil, err
+	}
+	if class == nil {
+		return nil, nil
+	}
+
+	res := make([]sharedconfig.DeletionBlockingResource, 0, len(class.Spec.ResourcesBlockingDeletion))
+	for _, gvk := range class.Spec.ResourcesBlockingDeletion {
+		res = append(res, sharedconfig.DeletionBlockingResource{
+			GroupVersionKind: gvk,
+			Source:           class.DeletionBlockingSource(),
+		})
+	}
+	return res, nil
+}
+
+// resourcesBlockingDeletionFromProject returns the resource types from the spec of the given project which block its deletion.
+// Resource types which are not allowed in the config are ignored, since they might have been removed from the allowlist after the project has been created.
+func (r *CommonReconciler) resourcesBlockingDeletionFromProject(ctx context.Context, project *pwv1alpha1.Project) ([]sharedconfig.DeletionBlockingResource, error) {
+	if len(project.Spec.ResourcesBlockingDeletion) == 0 {
+		return nil, nil
+	}
+	allowed, err := r.Config.AllowedProjectResourcesBlockingDeletion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get allowed resources blocking project deletion: %w", err)
+	}
+
+	res := make([]sharedconfig.DeletionBlockingResource, 0, len(project.Spec.ResourcesBlockingDeletion))
+	for _, gvk := range project.Spec.ResourcesBlockingDeletion {
+		gvk = pwv1alpha1.NormalizeGroupVersionKind(gvk)
+		if !slices.Contains(allowed, gvk) {
+			log.FromContext(ctx).Info("ignoring resource type blocking deletion of project, because it is not allowed", "gvk", gvk.String())
+			continue
+		}
+		res = append(res, sharedconfig.DeletionBlockingResource{
+			GroupVersionKind: gvk,
+			Source:           project.DeletionBlockingSource(),
+		})
+	}
+	return res, nil
+}
+
+// blockingResourceClient returns the client which is used to list the instances of the given deletion-blocking resource type.
+// This is the dynamic onboarding cluster access, since only its permissions are adapted to the resource types registered by ServiceProviders.
+// If the dynamic access is not available, the static access is used for builtin resource types, which are covered by its permissions.
+func (r *CommonReconciler) blockingResourceClient(ctx context.Context, br sharedconfig.DeletionBlockingResource, dynamicCluster *clusters.Cluster, dynamicErr error) (client.Client, error) {
+	if dynamicErr == nil {
+		return dynamicCluster.Client(), nil
+	}
+	if br.Source != pwv1alpha1.SourceBuiltin {
+		return nil, fmt.Errorf("failed to get dynamic onboarding cluster access for resource type %s: %w", br.GroupVersionKind.String(), dynamicErr)
+	}
+	staticCluster, err := r.Config.OnboardingClusterStatic(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get onboarding cluster access: %w", err)
+	}
+	return staticCluster.Client(), nil
+}
+
+// CleanupResult is the result of a cleanup function passed to handleDelete.
+// Errors are reserved for actual failures, a cleanup which has to wait for something else to happen returns a result which is not done instead.
+type CleanupResult struct {
+	// Done is true if the cleanup has finished, so that the finalizer can be removed.
+	Done bool
+	// RetryAfter is the time after which an unfinished cleanup is checked again. If it is zero, the object is requeued with increasing backoff.
+	RetryAfter time.Duration
+	// Blockers describe what an unfinished cleanup is waiting for, e.g. 'namespace project-foo'.
+	Blockers []string
+}
+
+// cleanupDone returns the result of a finished cleanup.
+func cleanupDone() CleanupResult {
+	return CleanupResult{Done: true}
+}
+
+// cleanupBlocked returns the result of a cleanup which waits for the given blockers to disappear.
+func cleanupBlocked(blockers ...string) CleanupResult {
+	return CleanupResult{Blockers: blockers}
+}
+
+// handleDelete runs the given cleanup function and removes the finalizer from the given object once the cleanup is done, if the object is in deletion.
+// Returns true if the object is in deletion, in which case the returned result tells whether the cleanup has finished.
+// No further reconciliation is required for objects in deletion, unfinished cleanups have to be requeued via cleanupResult.
+func (r *CommonReconciler) handleDelete(ctx context.Context, o client.Object, deleteFunc func() (CleanupResult, error)) (bool, CleanupResult, error) {
 	if !utils.WasDeleted(o) {
-		return false, NoRequeue, nil
+		return false, CleanupResult{}, nil
 	}
 
-	log := log.FromContext(ctx)
 	onboardingCluster, err := r.Config.OnboardingClusterStatic(ctx)
 	if err != nil {
-		return false, RequeueError, fmt.Errorf("failed to get onboarding cluster access: %w", err)
+		return false, CleanupResult{}, fmt.Errorf("failed to get onboarding cluster access: %w", err)
 	}
 
 	if controllerutil.ContainsFinalizer(o, deleteFinalizer) {
-		if err := deleteFunc(); err != nil {
-			if rrErr, ok := err.(ResourcesRemainingError); ok {
-				log.Info(rrErr.Error())
-				return true, RequeueWithBackoff, nil
-			}
-
-			return false, RequeueError, fmt.Errorf("failed to perform cleanup operation: %w", err)
+		cleanup, err := deleteFunc()
+		if err != nil {
+			return false, CleanupResult{}, fmt.Errorf("failed to perform cleanup operation: %w", err)
+		}
+		if !cleanup.Done {
+			return true, cleanup, nil
 		}
 
 		controllerutil.RemoveFinalizer(o, deleteFinalizer)
 		if err := onboardingCluster.Client().Update(ctx, o); err != nil {
-			return false, RequeueError, fmt.Errorf("failed to remove finalizer: %w", err)
+			return false, CleanupResult{}, fmt.Errorf("failed to remove finalizer: %w", err)
 		}
 	}
 
-	return true, NoRequeue, nil
+	return true, cleanupDone(), nil
 }
 
-func (r *CommonReconciler) applyManagementLabel(obj metav1.Object) {
-	utils.SetManagementLabels(obj, r.ProviderName)
+// conditionedObject is a Project or Workspace, whose conditions reflect the result of the last reconciliation.
+type conditionedObject interface {
+	client.Object
+	SetOrUpdateCondition(condition pwv1alpha1.Condition)
+	RemoveCondition(conditionType pwv1alpha1.ConditionType)
 }
 
-type RequeueType int
+// reconcileResult translates the given error into the result of a reconciliation of the given object, depending on the kind of the error:
+//   - nil: the ReconcileError condition is removed and requeuing stops.
+//   - Blocked: the ReconcileError condition is removed and the object is requeued with increasing backoff.
+//     No error is returned, since controller-runtime would ignore the requeue interval otherwise.
+//   - Terminal: the ReconcileError condition is set and the error is returned as terminal error, so it is not retried.
+//   - Retriable: the ReconcileError condition is set and the error is returned, so it is retried by controller-runtime.
+//
+// Each error is counted in the ReconcileErrors metric of the given controller.
+// The condition is only persisted if the caller updates the status afterwards.
+func reconcileResult(controller string, sr *smartrequeue.Entry, obj conditionedObject, err error) (ctrl.Result, error) {
+	if err == nil {
+		obj.RemoveCondition(pwv1alpha1.ConditionTypeReconcileError)
+		return sr.StopRequeue()
+	}
 
-const (
-	RequeueError RequeueType = iota
-	RequeueWithMinInterval
-	RequeueWithBackoff
-	NoRequeue
-)
+	kind := pwoerrors.Classify(err)
+	metrics.ReconcileErrors.WithLabelValues(controller, string(kind)).Inc()
+
+	switch kind {
+	case pwoerrors.KindBlocked:
+		obj.RemoveCondition(pwv1alpha1.ConditionTypeReconcileError)
+		return sr.IsStable() // naming is unintuitive, this requeues with increasing backoff
+	case pwoerrors.KindTerminal:
+		obj.SetOrUpdateCondition(reconcileErrorCondition(kind, err))
+		_, _ = sr.StopRequeue() // terminal errors are not retried, so the backoff of the object can be dropped
+		return ctrl.Result{}, reconcile.TerminalError(err)
+	default:
+		obj.SetOrUpdateCondition(reconcileErrorCondition(kind, err))
+		return sr.ReturnError(err)
+	}
+}
 
-var _ error = ResourcesRemainingError{}
+// cleanupResult translates the result of the cleanup of the given object in deletion into the result of its reconciliation.
+// A finished cleanup stops requeuing. Waiting for blockers is not a failure, so the ReconcileError condition is removed for an unfinished cleanup,
+// and the object is requeued after RetryAfter or with increasing backoff. Unfinished cleanups are counted as blocked in the ReconcileErrors metric.
+// The condition is only persisted if the caller updates the status afterwards.
+func cleanupResult(ctx context.Context, controller string, sr *smartrequeue.Entry, obj conditionedObject, cleanup CleanupResult) (ctrl.Result, error) {
+	if cleanup.Done {
+		return reconcileResult(controller, sr, obj, nil)
+	}
 
-type ResourcesRemainingError struct{}
+	log.FromContext(ctx).Info("Cleanup not finished yet", "blockers", cleanup.Blockers)
+	metrics.ReconcileErrors.WithLabelValues(controller, string(pwoerrors.KindBlocked)).Inc()
+	obj.RemoveCondition(pwv1alpha1.ConditionTypeReconcileError)
+	rr, err := sr.IsStable() // naming is unintuitive, this requeues with increasing backoff
+	if cleanup.RetryAfter > 0 {
+		rr.RequeueAfter = cleanup.RetryAfter
+	}
+	return rr, err
+}
 
-func (err ResourcesRemainingError) Error() string {
-	return "cleanup not finished yet due to remaining resources"
+func reconcileErrorCondition(kind pwoerrors.Kind, err error) pwv1alpha1.Condition {
+	return pwv1alpha1.Condition{
+		Type:    pwv1alpha1.ConditionTypeReconcileError,
+		Status:  pwv1alpha1.ConditionStatusTrue,
+		Reason:  pwv1alpha1.ConditionReason(kind),
+		Message: err.Error(),
+	}
 }
 
-func (err ResourcesRemainingError) Is(target error) bool {
-	return reflect.TypeOf(target) == reflect.TypeOf(err)
+// applyManagementLabel sets the management labels, as configured in the ProjectWorkspaceConfig, on the given object.
+func (r *CommonReconciler) applyManagementLabel(ctx context.Context, obj metav1.Object) error {
+	cfg, err := r.Config.ManagementLabels(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get management labels: %w", err)
+	}
+	utils.ApplyManagementLabels(obj, r.ProviderName, cfg)
+	return nil
+}
+
+// isManaged returns true if the given object carries the current management labels or any of the configured previous ones.
+func (r *CommonReconciler) isManaged(ctx context.Context, obj metav1.Object) (bool, error) {
+	cfg, err := r.Config.ManagementLabels(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get management labels: %w", err)
+	}
+	return utils.IsManaged(obj, r.ProviderName, cfg), nil
+}
+
+// deleteIfManaged deletes the given object, if it exists and is managed by the platform service.
+// Objects which are not managed by the platform service are left untouched.
+// Returns true if the object has been deleted.
+func (r *CommonReconciler) deleteIfManaged(ctx context.Context, c client.Client, obj client.Object) (bool, error) {
+	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	managed, err := r.isManaged(ctx, obj)
+	if err != nil {
+		return false, err
+	}
+	if !managed {
+		return false, nil
+	}
+	if err := c.Delete(ctx, obj); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return true, nil
+}
+
+// signalDeletionRequested sets the deletion-requested annotation on the given namespace, if it exists.
+// ServiceProviders are expected to react to this annotation by cleaning up the resources they manage within the namespace.
+func (r *CommonReconciler) signalDeletionRequested(ctx context.Context, namespace string) error {
+	onboardingCluster, err := r.Config.OnboardingClusterStatic(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get onboarding cluster access: %w", err)
+	}
+
+	ns := &corev1.Namespace{}
+	if err := onboardingCluster.Client().Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get namespace %s: %w", namespace, err)
+	}
+	if ns.GetAnnotations()[pwv1alpha1.DeletionRequestedAnnotation] == "true" {
+		return nil
+	}
+
+	old := ns.DeepCopy()
+	metadata.SetAnnotation(ns, pwv1alpha1.DeletionRequestedAnnotation, "true")
+	if err := onboardingCluster.Client().Patch(ctx, ns, client.MergeFrom(old)); err != nil {
+		return fmt.Errorf("failed to annotate namespace %s: %w", namespace, err)
+	}
+	return nil
+}
+
+// summarizeRemainingResourcesBySource returns a human-readable summary of the given number of remaining resources per source,
+// e.g. 'Builtin: 1, ServiceProvider[foo]: 2'.
+func summarizeRemainingResourcesBySource(counts map[string]int) string {
+	parts := make([]string, 0, len(counts))
+	for _, source := range slices.Sorted(maps.Keys(counts)) {
+		parts = append(parts, fmt.Sprintf("%s: %d", source, counts[source]))
+	}
+	return strings.Join(parts, ", ")
 }
@@ -4,54 +4,36 @@ import (
 	"context"
 	"errors"
 	"fmt"
-
-	"io/fs"
 	"testing"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	openmcpv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
 	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+	pwoerrors "github.com/openmcp-project/platform-service-project-workspace/internal/errors"
 
 	"github.com/stretchr/testify/assert"
 )
 
-func Test_ResourcesRemainingError_Is(t *testing.T) {
-	testCases := []struct {
-		desc     string
-		a        error
-		b        error
-		expected bool
-	}{
-		{
-			desc:     "should return true for same error",
-			a:        ResourcesRemainingError{},
-			b:        ResourcesRemainingError{},
-			expected: true,
-		},
-		{
-			desc:     "should return false for different error",
-			a:        ResourcesRemainingError{},
-			b:        fs.ErrNotExist,
-			expected: false,
-		},
-		{
-			desc:     "should return false for different error (swapped)",
-			a:        fs.ErrNotExist,
-			b:        ResourcesRemainingError{},
-			expected: false,
-		},
-	}
-	for _, tC := range testCases {
-		t.Run(tC.desc, func(t *testing.T) {
-			assert.Equal(t, tC.expected, errors.Is(tC.a, tC.b))
-		})
+func Test_summarizeRemainingResourcesBySource(t *testing.T) {
+	counts := map[string]int{
+		"ServiceProvider[foo]":        2,
+		openmcpv1alpha1.SourceBuiltin: 1,
+		"ServiceProvider[bar]":        1,
 	}
+
+	assert.Equal(t, "Builtin: 1, ServiceProvider[bar]: 1, ServiceProvider[foo]: 2", summarizeRemainingResourcesBySource(counts))
 }
 
 func Test_CommonReconciler_handleDelete(t *testing.T) {
@@ -65,16 +47,16 @@ func Test_CommonReconciler_handleDelete(t *testing.T) {
 	}
 
 	type exp struct {
-		b   bool
-		rqt RequeueType
-		err error
+		b       bool
+		cleanup CleanupResult
+		err     error
 	}
 
 	test := []struct {
 		name             string
 		obj              client.Object
 		interceptorFuncs interceptor.Funcs
-		deleteFunc       func() error
+		deleteFunc       func() (CleanupResult, error)
 		expected         exp
 		validateFunc     func(ctx context.Context, c client.Client) error
 	}{
@@ -83,36 +65,33 @@ func Test_CommonReconciler_handleDelete(t *testing.T) {
 			obj: &openmcpv1alpha1.Project{
 				ObjectMeta: metav1.ObjectMeta{Name: "test-project"},
 			},
-			deleteFunc: func() error {
-				return nil
+			deleteFunc: func() (CleanupResult, error) {
+				return cleanupDone(), nil
 			},
 			expected: exp{
 				b:   false,
-				rqt: NoRequeue,
 				err: nil,
 			},
 		},
 		{
 			name: "Resources are still remaining in the cluster",
 			obj:  testProject.DeepCopy(),
-			deleteFunc: func() error {
-				return ResourcesRemainingError{}
+			deleteFunc: func() (CleanupResult, error) {
+				return cleanupBlocked("namespace foo"), nil
 			},
 			expected: exp{
-				b:   true,
-				rqt: RequeueWithBackoff,
-				err: nil,
+				b:       true,
+				cleanup: CleanupResult{Blockers: []string{"namespace foo"}},
 			},
 		},
 		{
 			name: "Failed to perform clean up operation",
 			obj:  testProject.DeepCopy(),
-			deleteFunc: func() error {
-				return errors.New("some error")
+			deleteFunc: func() (CleanupResult, error) {
+				return CleanupResult{}, errors.New("some error")
 			},
 			expected: exp{
 				b:   false,
-				rqt: RequeueError,
 				err: fmt.Errorf("failed to perform cleanup operation: %w", errors.New("some error")),
 			},
 		},
@@ -127,25 +106,23 @@ func Test_CommonReconciler_handleDelete(t *testing.T) {
 					return client.Update(ctx, obj, opts...)
 				},
 			},
-			deleteFunc: func() error {
-				return nil
+			deleteFunc: func() (CleanupResult, error) {
+				return cleanupDone(), nil
 			},
 			expected: exp{
 				b:   false,
-				rqt: RequeueError,
 				err: fmt.Errorf("failed to remove finalizer: %w", errors.New("some update error")),
 			},
 		},
 		{
 			name: "Finalizer removed successfully",
 			obj:  testProject.DeepCopy(),
-			deleteFunc: func() error {
-				return nil
+			deleteFunc: func() (CleanupResult, error) {
+				return cleanupDone(), nil
 			},
 			expected: exp{
-				b:   true,
-				rqt: NoRequeue,
-				err: nil,
+				b:       true,
+				cleanup: CleanupResult{Done: true},
 			},
 			validateFunc: func(ctx context.Context, c client.Client) error {
 				project := &openmcpv1alpha1.Project{}
@@ -166,9 +143,9 @@ func Test_CommonReconciler_handleDelete(t *testing.T) {
 			}
 			assert.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(tt.obj), tt.obj))
 
-			b, rqt, err := r.handleDelete(ctx, tt.obj, tt.deleteFunc)
+			b, cleanup, err := r.handleDelete(ctx, tt.obj, tt.deleteFunc)
 			assert.Equal(t, tt.expected.b, b)
-			assert.Equal(t, tt.expected.rqt, rqt)
+			assert.Equal(t, tt.expected.cleanup, cleanup)
 			assert.Equal(t, tt.expected.err, err)
 
 			if tt.validateFunc != nil {
@@ -180,6 +157,106 @@ func Test_CommonReconciler_handleDelete(t *testing.T) {
 	}
 }
 
+func Test_reconcileResult(t *testing.T) {
+	testCases := []struct {
+		desc              string
+		err               error
+		expectedResult    ctrl.Result
+		expectError       bool
+		expectTerminal    bool
+		expectedCondition openmcpv1alpha1.ConditionReason
+	}{
+		{
+			desc: "should stop requeuing and remove the condition without an error",
+			err:  nil,
+		},
+		{
+			desc:           "should requeue with backoff and remove the condition for blocked errors",
+			err:            pwoerrors.NewBlockedError(errors.New("waiting")),
+			expectedResult: ctrl.Result{RequeueAfter: 5 * time.Second},
+		},
+		{
+			desc:              "should return terminal errors as terminal error and set the condition",
+			err:               fmt.Errorf("wrapped: %w", pwoerrors.NewTerminalError(errFake)),
+			expectError:       true,
+			expectTerminal:    true,
+			expectedCondition: openmcpv1alpha1.ConditionReason(pwoerrors.KindTerminal),
+		},
+		{
+			desc:              "should return untyped errors and set the condition",
+			err:               errFake,
+			expectError:       true,
+			expectedCondition: openmcpv1alpha1.ConditionReason(pwoerrors.KindRetriable),
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			r := NewCommonReconciler(nil, "test")
+			project := sampleProject.DeepCopy()
+			project.SetOrUpdateCondition(openmcpv1alpha1.Condition{
+				Type:   openmcpv1alpha1.ConditionTypeReconcileError,
+				Status: openmcpv1alpha1.ConditionStatusTrue,
+				Reason: openmcpv1alpha1.ConditionReason(pwoerrors.KindRetriable),
+			})
+
+			result, err := reconcileResult(ProjectControllerName, r.sr.For(project), project, tC.err)
+			assert.Equal(t, tC.expectedResult, result)
+			if tC.expectError {
+				assert.ErrorIs(t, err, tC.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tC.expectTerminal, errors.Is(err, reconcile.TerminalError(nil)))
+
+			if tC.expectedCondition == "" {
+				assert.Empty(t, project.Status.Conditions)
+			} else if assert.Len(t, project.Status.Conditions, 1) {
+				assert.Equal(t, tC.expectedCondition, project.Status.Conditions[0].Reason)
+				assert.Equal(t, tC.err.Error(), project.Status.Conditions[0].Message)
+			}
+		})
+	}
+}
+
+func Test_cleanupResult(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		cleanup        CleanupResult
+		expectedResult ctrl.Result
+	}{
+		{
+			desc:    "should stop requeuing if the cleanup is done",
+			cleanup: cleanupDone(),
+		},
+		{
+			desc:           "should requeue with backoff if the cleanup is blocked",
+			cleanup:        cleanupBlocked("namespace foo"),
+			expectedResult: ctrl.Result{RequeueAfter: 5 * time.Second},
+		},
+		{
+			desc:           "should requeue after the given duration",
+			cleanup:        CleanupResult{RetryAfter: time.Minute, Blockers: []string{"namespace foo"}},
+			expectedResult: ctrl.Result{RequeueAfter: time.Minute},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			r := NewCommonReconciler(nil, "test")
+			project := sampleProject.DeepCopy()
+			project.SetOrUpdateCondition(openmcpv1alpha1.Condition{
+				Type:   openmcpv1alpha1.ConditionTypeReconcileError,
+				Status: openmcpv1alpha1.ConditionStatusTrue,
+				Reason: openmcpv1alpha1.ConditionReason(pwoerrors.KindRetriable),
+			})
+
+			result, err := cleanupResult(context.TODO(), ProjectControllerName, r.sr.For(project), project, tC.cleanup)
+			assert.NoError(t, err)
+			assert.Equal(t, tC.expectedResult, result)
+			assert.Empty(t, project.Status.Conditions)
+		})
+	}
+}
+
 func Test_CommonReconciler_ensureFinalizer(t *testing.T) {
 	test := []struct {
 		name             string
@@ -258,3 +335,280 @@ func Test_CommonReconciler_ensureFinalizer(t *testing.T) {
 		})
 	}
 }
+
+func Test_CommonReconciler_handleRemainingContentBeforeDelete_missingCRD(t *testing.T) {
+	missingGVK := metav1.GroupVersionKind{Group: "missing.example.com", Version: "v1", Kind: "Missing"}
+	project := sampleProjectDeleted.DeepCopy()
+	remaining := &openmcpv1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "remaining", Namespace: project.Status.Namespace},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(Scheme).
+		WithObjects(project, remaining).
+		WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				if gvk := list.GetObjectKind().GroupVersionKind(); gvk.Group == missingGVK.Group {
+					return &meta.NoKindMatchError{GroupKind: gvk.GroupKind(), SearchedVersions: []string{gvk.Version}}
+				}
+				return c.List(ctx, list, opts...)
+			},
+		}).
+		Build()
+	cfg := config.NewFakeSharedInformation(fakeClient, []config.DeletionBlockingResource{
+		{GroupVersionKind: missingGVK, Source: "ServiceProvider[missing]"},
+		{GroupVersionKind: metav1.GroupVersionKind{Group: openmcpv1alpha1.GroupVersion.Group, Version: openmcpv1alpha1.GroupVersion.Version, Kind: "Workspace"}, Source: openmcpv1alpha1.SourceBuiltin},
+	}, nil, nil)
+	r := NewCommonReconciler(cfg, "test")
+	recorder := events.NewFakeRecorder(10)
+
+	hasRemainingContent, err := r.handleRemainingContentBeforeDelete(newContext(), project, recorder)
+	assert.NoError(t, err)
+	assert.True(t, hasRemainingContent, "the other resource types must still be evaluated")
+
+	if assert.Len(t, project.Status.Conditions, 1) {
+		assert.Equal(t, openmcpv1alpha1.ConditionTypeContentRemaining, project.Status.Conditions[0].Type)
+		assert.Contains(t, project.Status.Conditions[0].Message, "Builtin: 1")
+	}
+
+	close(recorder.Events)
+	recorded := []string{}
+	for e := range recorder.Events {
+		recorded = append(recorded, e)
+	}
+	if assert.Len(t, recorded, 1) {
+		assert.Contains(t, recorded[0], corev1.EventTypeWarning+" "+openmcpv1alpha1.EventReasonBlockingResourceKindMissing)
+		assert.Contains(t, recorded[0], "missing.example.com")
+	}
+}
+
+func Test_CommonReconciler_handleRemainingContentBeforeDelete_dynamicAccessUnavailable(t *testing.T) {
+	builtin := config.DeletionBlockingResource{
+		GroupVersionKind: metav1.GroupVersionKind{Group: openmcpv1alpha1.GroupVersion.Group, Version: openmcpv1alpha1.GroupVersion.Version, Kind: "Workspace"},
+		Source:           openmcpv1alpha1.SourceBuiltin,
+	}
+	registered := config.DeletionBlockingResource{
+		GroupVersionKind: metav1.GroupVersionKind{Group: "foo.services.openmcp.cloud", Version: "v1alpha1", Kind: "Foo"},
+		Source:           "ServiceProvider[foo]",
+	}
+
+	testCases := []struct {
+		desc                     string
+		resourcesBlocking        []config.DeletionBlockingResource
+		expectedRemainingContent bool
+		expectedErr              bool
+	}{
+		{
+			desc:                     "should fall back to the static access for builtin resource types",
+			resourcesBlocking:        []config.DeletionBlockingResource{builtin},
+			expectedRemainingContent: true,
+		},
+		{
+			desc:              "should fail for resource types registered by ServiceProviders",
+			resourcesBlocking: []config.DeletionBlockingResource{builtin, registered},
+			expectedErr:       true,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			project := sampleProjectDeleted.DeepCopy()
+			remaining := &openmcpv1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Name: "remaining", Namespace: project.Status.Namespace},
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(Scheme).WithObjects(project, remaining).Build()
+			cfg := config.NewFakeSharedInformation(fakeClient, tC.resourcesBlocking, nil, nil)
+			cfg.OnboardingClusterDynamicErr = errFake
+			r := NewCommonReconciler(cfg, "test")
+
+			hasRemainingContent, err := r.handleRemainingContentBeforeDelete(newContext(), project, nil)
+			if tC.expectedErr {
+				assert.ErrorIs(t, err, errFake)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tC.expectedRemainingContent, hasRemainingContent)
+		})
+	}
+}
+
+func Test_CommonReconciler_handleRemainingContentBeforeDelete_pagination(t *testing.T) {
+	project := sampleProjectDeleted.DeepCopy()
+	objs := []client.Object{project}
+	total := maxRemainingContentExamples + 5
+	for i := range total {
+		objs = append(objs, &openmcpv1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("remaining-%02d", i), Namespace: project.Status.Namespace},
+		})
+	}
+
+	requestedLimit := int64(0)
+	pages := 0
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(Scheme).
+		WithObjects(objs...).
+		WithInterceptorFuncs(interceptor.Funcs{
+			// the fake client does not support pagination, so it is emulated with pages of 10 items, using the index of the next item as continue token
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				listOpts := &client.ListOptions{}
+				listOpts.ApplyOptions(opts)
+				partialList, ok := list.(*metav1.PartialObjectMetadataList)
+				if !ok {
+					return errors.New("expected metadata-only list")
+				}
+				if err := c.List(ctx, list, client.InNamespace(listOpts.Namespace)); err != nil {
+					return err
+				}
+				pages++
+				requestedLimit = listOpts.Limit
+				pageSize := min(int(listOpts.Limit), 10)
+				start := 0
+				if listOpts.Continue != "" {
+					_, _ = fmt.Sscanf(listOpts.Continue, "%d", &start)
+				}
+				allItems := partialList.Items
+				end := min(start+pageSize, len(allItems))
+				partialList.Items = allItems[start:end]
+				partialList.Continue = ""
+				if end < len(allItems) {
+					partialList.Continue = fmt.Sprintf("%d", end)
+				}
+				return nil
+			},
+		}).
+		Build()
+	cfg := config.NewFakeSharedInformation(fakeClient, []config.DeletionBlockingResource{
+		{GroupVersionKind: metav1.GroupVersionKind{Group: openmcpv1alpha1.GroupVersion.Group, Version: openmcpv1alpha1.GroupVersion.Version, Kind: "Workspace"}, Source: openmcpv1alpha1.SourceBuiltin},
+	}, nil, nil)
+	r := NewCommonReconciler(cfg, "test")
+
+	hasRemainingContent, err := r.handleRemainingContentBeforeDelete(newContext(), project, nil)
+	assert.NoError(t, err)
+	assert.True(t, hasRemainingContent)
+	assert.Equal(t, int64(remainingContentPageSize), requestedLimit)
+	assert.Equal(t, 3, pages, "all pages must be listed")
+	assert.Equal(t, int32(total), project.Status.BlockingResourceCount)
+
+	if assert.Len(t, project.Status.Conditions, 1) {
+		assert.Contains(t, project.Status.Conditions[0].Message, fmt.Sprintf("There are %d remaining resources", total))
+		assert.Contains(t, project.Status.Conditions[0].Message, fmt.Sprintf("Builtin: %d", total))
+
+		var details openmcpv1alpha1.RemainingContentDetails
+		assert.NoError(t, json.Unmarshal(project.Status.Conditions[0].Details, &details))
+		assert.Equal(t, total, details.Count)
+		assert.Len(t, details.Resources, maxRemainingContentExamples, "only a limited number of examples must be listed")
+		assert.Equal(t, "Workspace", details.Resources[0].Kind)
+		assert.Equal(t, openmcpv1alpha1.GroupVersion.String(), details.Resources[0].APIGroup)
+	}
+}
+
+func Test_CommonReconciler_handleRemainingContentBeforeDelete_projectResources(t *testing.T) {
+	workspaceGVK := metav1.GroupVersionKind{Group: openmcpv1alpha1.GroupVersion.Group, Version: openmcpv1alpha1.GroupVersion.Version, Kind: "Workspace"}
+
+	testCases := []struct {
+		desc                     string
+		allowed                  []metav1.GroupVersionKind
+		expectedRemainingContent bool
+	}{
+		{
+			desc:                     "should block the deletion by allowed resource types of the project",
+			allowed:                  []metav1.GroupVersionKind{workspaceGVK},
+			expectedRemainingContent: true,
+		},
+		{
+			desc: "should ignore resource types of the project which are not allowed",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			project := sampleProjectDeleted.DeepCopy()
+			project.Spec.ResourcesBlockingDeletion = []metav1.GroupVersionKind{{Group: workspaceGVK.Group, Version: workspaceGVK.Version, Kind: "workspace"}}
+			remaining := &openmcpv1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Name: "remaining", Namespace: project.Status.Namespace},
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(Scheme).WithObjects(project, remaining).Build()
+			cfg := config.NewFakeSharedInformation(fakeClient, nil, nil, nil)
+			cfg.AllowedProjectBlockingResourcesData = tC.allowed
+			r := NewCommonReconciler(cfg, "test")
+
+			hasRemainingContent, err := r.handleRemainingContentBeforeDelete(newContext(), project, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tC.expectedRemainingContent, hasRemainingContent)
+			if tC.expectedRemainingContent && assert.Len(t, project.Status.Conditions, 1) {
+				assert.Contains(t, project.Status.Conditions[0].Message, project.DeletionBlockingSource()+": 1")
+			}
+		})
+	}
+}
+
+func Test_CommonReconciler_handleRemainingContentBeforeDelete_resetsBlockingResourceCount(t *testing.T) {
+	project := sampleProjectDeleted.DeepCopy()
+	project.Status.BlockingResourceCount = 3
+	project.SetOrUpdateCondition(openmcpv1alpha1.Condition{Type: openmcpv1alpha1.ConditionTypeContentRemaining, Status: openmcpv1alpha1.ConditionStatusTrue})
+
+	fakeClient := fake.NewClientBuilder().WithScheme(Scheme).WithObjects(project).Build()
+	cfg := config.NewFakeSharedInformation(fakeClient, []config.DeletionBlockingResource{
+		{GroupVersionKind: metav1.GroupVersionKind{Group: openmcpv1alpha1.GroupVersion.Group, Version: openmcpv1alpha1.GroupVersion.Version, Kind: "Workspace"}, Source: openmcpv1alpha1.SourceBuiltin},
+	}, nil, nil)
+	r := NewCommonReconciler(cfg, "test")
+
+	hasRemainingContent, err := r.handleRemainingContentBeforeDelete(newContext(), project, nil)
+	assert.NoError(t, err)
+	assert.False(t, hasRemainingContent)
+	assert.Zero(t, project.Status.BlockingResourceCount)
+	assert.Empty(t, project.Status.Conditions)
+}
+
+func Test_CommonReconciler_handleRemainingContentBeforeDelete_blockingResourcePolicies(t *testing.T) {
+	workspace := sampleWorkspaceDeleted.DeepCopy()
+	workspace.Spec.ClassName = "cascading"
+	class := &openmcpv1alpha1.WorkspaceClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "cascading"},
+		Spec: openmcpv1alpha1.WorkspaceClassSpec{
+			ResourcesBlockingDeletion: []metav1.GroupVersionKind{{Version: "v1", Kind: "Secret"}},
+		},
+	}
+	warned := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "warned", Namespace: workspace.Status.Namespace}}
+	cascaded := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "cascaded", Namespace: workspace.Status.Namespace}}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(Scheme).WithObjects(workspace, class, warned, cascaded).Build()
+	cfg := config.NewFakeSharedInformation(fakeClient, nil, []config.DeletionBlockingResource{
+		{GroupVersionKind: metav1.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, Source: "ServiceProvider[foo]", Policy: openmcpv1alpha1.BlockingResourcePolicyWarn},
+	}, nil)
+	cfg.WorkspaceBlockingResourcePoliciesData = []openmcpv1alpha1.BlockingResourcePolicyRule{
+		{Kind: "secret", Policy: openmcpv1alpha1.BlockingResourcePolicyCascade},
+	}
+	r := NewCommonReconciler(cfg, "test")
+
+	// the cascaded resource is deleted, but blocks the deletion until it is gone
+	recorder := events.NewFakeRecorder(10)
+	hasRemainingContent, err := r.handleRemainingContentBeforeDelete(newContext(), workspace, recorder)
+	assert.NoError(t, err)
+	assert.True(t, hasRemainingContent)
+	assert.Equal(t, int32(1), workspace.Status.BlockingResourceCount)
+	assert.True(t, apierrors.IsNotFound(fakeClient.Get(newContext(), client.ObjectKeyFromObject(cascaded), &corev1.Secret{})))
+	if assert.Len(t, workspace.Status.Conditions, 1) {
+		assert.Equal(t, openmcpv1alpha1.ConditionStatusTrue, workspace.Status.Conditions[0].Status)
+		assert.Contains(t, workspace.Status.Conditions[0].Message, "There are 1 remaining resources")
+	}
+
+	// the warned resource does not block the deletion
+	hasRemainingContent, err = r.handleRemainingContentBeforeDelete(newContext(), workspace, recorder)
+	assert.NoError(t, err)
+	assert.False(t, hasRemainingContent)
+	assert.Zero(t, workspace.Status.BlockingResourceCount)
+	assert.NoError(t, fakeClient.Get(newContext(), client.ObjectKeyFromObject(warned), &corev1.ConfigMap{}))
+	if assert.Len(t, workspace.Status.Conditions, 1) {
+		assert.Equal(t, openmcpv1alpha1.ConditionStatusFalse, workspace.Status.Conditions[0].Status)
+		assert.Equal(t, openmcpv1alpha1.ConditionReasonResourcesNotBlocking, workspace.Status.Conditions[0].Reason)
+		assert.Contains(t, workspace.Status.Conditions[0].Message, "ServiceProvider[foo]: 1")
+	}
+
+	close(recorder.Events)
+	recorded := []string{}
+	for e := range recorder.Events {
+		recorded = append(recorded, e)
+	}
+	if assert.Len(t, recorded, 1) {
+		assert.Contains(t, recorded[0], corev1.EventTypeWarning+" "+openmcpv1alpha1.EventReasonNonBlockingResourcesRemaining)
+	}
+}
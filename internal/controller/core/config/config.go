@@ -2,7 +2,9 @@ package config
 
 import (
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -20,27 +22,62 @@ func ToSchemaGVK(g metav1.GroupVersionKind) schema.GroupVersionKind {
 	}
 }
 
+// KindProjectWorkspaceConfig is the kind which a config file with an apiVersion/kind header must specify.
+const KindProjectWorkspaceConfig = "ProjectWorkspaceConfig"
+
+// configDecoders maps the apiVersions which are supported for config files to a function which decodes the file strictly
+// and converts it to the version used by the platform service.
+// When a new version of the ProjectWorkspaceConfig is introduced, the decoders of the previous versions have to convert to it.
+var configDecoders = map[string]func(data []byte) (*pwv1alpha1.ProjectWorkspaceConfig, error){
+	pwv1alpha1.GroupVersion.String(): decodeV1alpha1,
+}
+
+// configHeader is the apiVersion/kind header of a config file.
+type configHeader struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+}
+
 // LoadConfig loads a project workspace configuration from a file.
+// The file either contains a complete ProjectWorkspaceConfig with apiVersion and kind, or only its spec, which is interpreted as the current version.
+// Unknown and duplicate fields are rejected, so that typos do not silently result in a different configuration.
 func LoadConfig(path string) (*pwv1alpha1.ProjectWorkspaceConfig, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("error reading config file: %w", err)
 	}
-	raw := map[string]any{}
-	err = yaml.Unmarshal(data, &raw)
-	if err != nil {
+	header := configHeader{}
+	if err := yaml.Unmarshal(data, &header); err != nil {
 		return nil, fmt.Errorf("error unmarshaling config file: %w", err)
 	}
-	cfg := &pwv1alpha1.ProjectWorkspaceConfig{}
-	_, hasKind := raw["kind"]
-	_, hasApiVersion := raw["apiVersion"]
-	if hasKind && hasApiVersion {
-		err = yaml.Unmarshal(data, cfg)
-	} else {
-		err = yaml.Unmarshal(data, &cfg.Spec)
+
+	if header.APIVersion == "" && header.Kind == "" {
+		cfg := &pwv1alpha1.ProjectWorkspaceConfig{}
+		if err := yaml.UnmarshalStrict(data, &cfg.Spec); err != nil {
+			return nil, fmt.Errorf("error parsing config file as spec of a %s: %w", KindProjectWorkspaceConfig, err)
+		}
+		return cfg, nil
 	}
+
+	if header.Kind != KindProjectWorkspaceConfig {
+		return nil, fmt.Errorf("unsupported kind '%s' in config file, expected '%s'", header.Kind, KindProjectWorkspaceConfig)
+	}
+	decode, ok := configDecoders[header.APIVersion]
+	if !ok {
+		return nil, fmt.Errorf("unsupported apiVersion '%s' in config file, supported versions are %v", header.APIVersion, slices.Sorted(maps.Keys(configDecoders)))
+	}
+	cfg, err := decode(data)
 	if err != nil {
-		return nil, fmt.Errorf("config can neither be parsed as full config nor as spec: %w", err)
+		return nil, fmt.Errorf("error parsing config file as %s %s: %w", header.APIVersion, KindProjectWorkspaceConfig, err)
+	}
+	return cfg, nil
+}
+
+// decodeV1alpha1 strictly decodes a core.openmcp.cloud/v1alpha1 ProjectWorkspaceConfig, which is the current version and needs no conversion.
+func decodeV1alpha1(data []byte) (*pwv1alpha1.ProjectWorkspaceConfig, error) {
+	cfg := &pwv1alpha1.ProjectWorkspaceConfig{}
+	if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+		return nil, err
 	}
 	return cfg, nil
 }
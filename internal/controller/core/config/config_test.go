@@ -45,6 +45,23 @@ func TestLoadConfig(t *testing.T) {
 	}
 }
 
+func TestLoadConfig_strict(t *testing.T) {
+	for file, expectedErr := range map[string]string{
+		"./testdata/config_unknown_field.yaml":       `unknown field "additonalPermissions"`,
+		"./testdata/config_unknown_field2.yaml":      `unknown field "workspce"`,
+		"./testdata/config_unsupported_version.yaml": "unsupported apiVersion 'core.openmcp.cloud/v1'",
+		"./testdata/config_wrong_kind.yaml":          "unsupported kind 'ProjectWorkspaceConfigOverride'",
+	} {
+		t.Run(file, func(t *testing.T) {
+			pwConfig, err := config.LoadConfig(file)
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), expectedErr)
+				assert.Nil(t, pwConfig)
+			}
+		})
+	}
+}
+
 func TestDefaults(t *testing.T) {
 	pwConfig := &pwv1alpha1.ProjectWorkspaceConfig{
 		Spec: pwv1alpha1.ProjectWorkspaceConfigSpec{
@@ -0,0 +1,134 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	pwoerrors "github.com/openmcp-project/platform-service-project-workspace/internal/errors"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+// deletionGracePeriodRemaining returns how long the teardown of the given Project in deletion is still delayed by the configured deletion grace period.
+// Zero or less means that the teardown can start, which is always the case for projects which are not in deletion.
+func (r *ProjectReconciler) deletionGracePeriodRemaining(ctx context.Context, project *pwv1alpha1.Project) (time.Duration, error) {
+	if !utils.WasDeleted(project) || !controllerutil.ContainsFinalizer(project, deleteFinalizer) {
+		return 0, nil
+	}
+	gracePeriod, err := r.Config.ProjectDeletionGracePeriod(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get project deletion grace period from config: %w", err)
+	}
+	return time.Until(project.DeletionTimestamp.Add(gracePeriod)), nil
+}
+
+// setPendingDeletion sets the PendingDeletion condition on the given Project, whose teardown starts after the given remaining grace period.
+// An event is recorded when the condition is added, so that the members are notified about the upcoming teardown.
+func (r *ProjectReconciler) setPendingDeletion(project *pwv1alpha1.Project, remaining time.Duration) {
+	message := fmt.Sprintf("Project will be torn down at %s, add the annotation '%s=true' to cancel the deletion",
+		time.Now().Add(remaining).UTC().Format(time.RFC3339), pwv1alpha1.CancelDeletionAnnotation)
+	if project.GetCondition(pwv1alpha1.ConditionTypePendingDeletion) == nil {
+		r.recordEvent(project, corev1.EventTypeWarning, pwv1alpha1.EventReasonDeletionPending, "Delete", message)
+	}
+	project.SetOrUpdateCondition(pwv1alpha1.Condition{
+		Type:    pwv1alpha1.ConditionTypePendingDeletion,
+		Status:  pwv1alpha1.ConditionStatusTrue,
+		Reason:  pwv1alpha1.ConditionReasonDeletionGracePeriod,
+		Message: message,
+	})
+}
+
+// clearPendingDeletion removes the PendingDeletion condition from the given Project, whose grace period has passed, and records that the teardown starts.
+func (r *ProjectReconciler) clearPendingDeletion(project *pwv1alpha1.Project) {
+	if project.GetCondition(pwv1alpha1.ConditionTypePendingDeletion) == nil {
+		return
+	}
+	project.RemoveCondition(pwv1alpha1.ConditionTypePendingDeletion)
+	r.recordEvent(project, corev1.EventTypeNormal, pwv1alpha1.EventReasonDeletionStarted, "Delete", "Deletion grace period has passed, tearing down the project")
+}
+
+// deletionCancelled returns true if the deletion of the given Project has been cancelled via the CancelDeletionAnnotation.
+func deletionCancelled(project *pwv1alpha1.Project) bool {
+	return project.GetAnnotations()[pwv1alpha1.CancelDeletionAnnotation] == "true"
+}
+
+// cancelDeletion reverts the deletion of the given Project during its grace period.
+// Since Kubernetes cannot revoke a deletion, the finalizer is removed and the Project is created again with the same labels, annotations and spec,
+// except for the CancelDeletionAnnotation. The namespaces of the project and their content have not been touched yet, so the re-created Project adopts them.
+// If the Project has other finalizers, it would not be removed, so the cancellation is blocked until they are gone.
+// The owner reference of the project namespace is removed beforehand, otherwise the garbage collector would delete it together with the old Project.
+func (r *ProjectReconciler) cancelDeletion(ctx context.Context, project *pwv1alpha1.Project) error {
+	log := logging.FromContextOrPanic(ctx)
+
+	if others := slices.DeleteFunc(slices.Clone(project.Finalizers), func(f string) bool { return f == deleteFinalizer }); len(others) > 0 {
+		project.SetOrUpdateCondition(pwv1alpha1.Condition{
+			Type:    pwv1alpha1.ConditionTypePendingDeletion,
+			Status:  pwv1alpha1.ConditionStatusTrue,
+			Reason:  pwv1alpha1.ConditionReasonDeletionCancelled,
+			Message: fmt.Sprintf("Deletion has been cancelled, but the project cannot be re-created while it has other finalizers: %s", strings.Join(others, ", ")),
+		})
+		return pwoerrors.NewBlockedError(fmt.Errorf("waiting for finalizers %s to be removed before re-creating project", strings.Join(others, ", ")))
+	}
+
+	restored := &pwv1alpha1.Project{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        project.Name,
+			Labels:      maps.Clone(project.Labels),
+			Annotations: maps.Clone(project.Annotations),
+		},
+		Spec: *project.Spec.DeepCopy(),
+	}
+	delete(restored.Annotations, pwv1alpha1.CancelDeletionAnnotation)
+
+	c := r.OnboardingStatic.Client()
+	if err := r.releaseProjectNamespace(ctx, project); err != nil {
+		return err
+	}
+	controllerutil.RemoveFinalizer(project, deleteFinalizer)
+	if err := c.Update(ctx, project); err != nil {
+		return fmt.Errorf("failed to remove finalizer: %w", err)
+	}
+	if err := c.Create(ctx, restored); err != nil {
+		r.recordEvent(project, corev1.EventTypeWarning, pwv1alpha1.EventReasonDeletionCancelFailed, "CancelDelete",
+			fmt.Sprintf("Failed to re-create the project after its deletion has been cancelled, its namespaces have been kept and it can be re-created manually: %v", err))
+		return fmt.Errorf("failed to re-create project after its deletion has been cancelled: %w", err)
+	}
+
+	log.Info("Re-created project after its deletion has been cancelled")
+	r.recordEvent(restored, corev1.EventTypeNormal, pwv1alpha1.EventReasonDeletionCancelled, "CancelDelete", "Deletion has been cancelled during the grace period, the project has been re-created")
+	return nil
+}
+
+// releaseProjectNamespace removes the owner reference to the given Project from its namespace, so that the namespace survives the removal of the Project.
+func (r *ProjectReconciler) releaseProjectNamespace(ctx context.Context, project *pwv1alpha1.Project) error {
+	c := r.OnboardingStatic.Client()
+	ns := &corev1.Namespace{}
+	if err := c.Get(ctx, client.ObjectKey{Name: utils.NamespaceForProject(project)}, ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get project namespace: %w", err)
+	}
+	owners := slices.DeleteFunc(slices.Clone(ns.OwnerReferences), func(ref metav1.OwnerReference) bool { return ref.UID == project.UID })
+	if len(owners) == len(ns.OwnerReferences) {
+		return nil
+	}
+	old := ns.DeepCopy()
+	ns.OwnerReferences = owners
+	if err := c.Patch(ctx, ns, client.MergeFrom(old)); err != nil {
+		return fmt.Errorf("failed to remove owner reference from project namespace '%s': %w", ns.Name, err)
+	}
+	return nil
+}
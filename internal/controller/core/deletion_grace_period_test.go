@@ -0,0 +1,145 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+)
+
+func Test_ProjectReconciler_deletionGracePeriod(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		deletedSince   time.Duration
+		annotations    map[string]string
+		finalizers     []string
+		expectRequeue  bool
+		expectedEvents []string
+		validate       func(t *testing.T, ctx context.Context, c client.Client)
+	}{
+		{
+			desc:           "should keep the project and its namespace during the grace period",
+			deletedSince:   time.Minute,
+			expectRequeue:  true,
+			expectedEvents: []string{pwv1alpha1.EventReasonDeletionPending},
+			validate: func(t *testing.T, ctx context.Context, c client.Client) {
+				p := &pwv1alpha1.Project{}
+				require.NoError(t, c.Get(ctx, client.ObjectKey{Name: sampleProject.Name}, p))
+				assert.Contains(t, p.Finalizers, deleteFinalizer)
+				condition := p.GetCondition(pwv1alpha1.ConditionTypePendingDeletion)
+				if assert.NotNil(t, condition) {
+					assert.Equal(t, pwv1alpha1.ConditionReasonDeletionGracePeriod, condition.Reason)
+					assert.Contains(t, condition.Message, pwv1alpha1.CancelDeletionAnnotation)
+				}
+
+				ns := &corev1.Namespace{}
+				require.NoError(t, c.Get(ctx, client.ObjectKey{Name: "project-sample"}, ns))
+				assert.Nil(t, ns.GetDeletionTimestamp())
+				assert.NotContains(t, ns.GetAnnotations(), pwv1alpha1.DeletionRequestedAnnotation)
+			},
+		},
+		{
+			desc:           "should re-create the project if the deletion is cancelled during the grace period",
+			deletedSince:   time.Minute,
+			annotations:    map[string]string{pwv1alpha1.CancelDeletionAnnotation: "true", pwv1alpha1.DisplayNameAnnotation: "Sample"},
+			expectedEvents: []string{pwv1alpha1.EventReasonDeletionCancelled},
+			validate: func(t *testing.T, ctx context.Context, c client.Client) {
+				p := &pwv1alpha1.Project{}
+				require.NoError(t, c.Get(ctx, client.ObjectKey{Name: sampleProject.Name}, p))
+				assert.Nil(t, p.GetDeletionTimestamp())
+				assert.Equal(t, map[string]string{pwv1alpha1.DisplayNameAnnotation: "Sample"}, p.GetAnnotations())
+				assert.Equal(t, sampleProject.Spec, p.Spec)
+
+				ns := &corev1.Namespace{}
+				require.NoError(t, c.Get(ctx, client.ObjectKey{Name: "project-sample"}, ns))
+				assert.Nil(t, ns.GetDeletionTimestamp())
+				assert.Empty(t, ns.GetOwnerReferences(), "the namespace must not be garbage collected together with the deleted project")
+			},
+		},
+		{
+			desc:         "should not re-create the project while it has other finalizers",
+			deletedSince: time.Minute,
+			annotations:  map[string]string{pwv1alpha1.CancelDeletionAnnotation: "true"},
+			finalizers:   []string{"example.com/other"},
+			validate: func(t *testing.T, ctx context.Context, c client.Client) {
+				p := &pwv1alpha1.Project{}
+				require.NoError(t, c.Get(ctx, client.ObjectKey{Name: sampleProject.Name}, p))
+				assert.NotNil(t, p.GetDeletionTimestamp())
+				assert.Contains(t, p.Finalizers, deleteFinalizer)
+				condition := p.GetCondition(pwv1alpha1.ConditionTypePendingDeletion)
+				if assert.NotNil(t, condition) {
+					assert.Equal(t, pwv1alpha1.ConditionReasonDeletionCancelled, condition.Reason)
+					assert.Contains(t, condition.Message, "example.com/other")
+				}
+			},
+		},
+		{
+			desc:         "should tear down the project after the grace period",
+			deletedSince: 2 * time.Hour,
+			annotations:  map[string]string{pwv1alpha1.CancelDeletionAnnotation: "true"},
+			validate: func(t *testing.T, ctx context.Context, c client.Client) {
+				err := c.Get(ctx, client.ObjectKey{Name: sampleProject.Name}, &pwv1alpha1.Project{})
+				assert.True(t, apierrors.IsNotFound(err))
+				err = c.Get(ctx, client.ObjectKey{Name: "project-sample"}, &corev1.Namespace{})
+				assert.True(t, apierrors.IsNotFound(err))
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			project := sampleProject.DeepCopy()
+			project.SetAnnotations(tC.annotations)
+			project.SetDeletionTimestamp(ptr.To(metav1.NewTime(time.Now().Add(-tC.deletedSince))))
+			project.SetFinalizers(append([]string{deleteFinalizer}, tC.finalizers...))
+			project.Status.Namespace = "project-sample"
+			project.UID = "sample-uid"
+			namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "project-sample", Labels: ownedNamespaceLabels(project.Name, ""), OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: pwv1alpha1.GroupVersion.String(), Kind: "Project", Name: project.Name, UID: project.UID},
+			}}}
+
+			c := fake.NewClientBuilder().
+				WithObjects(project, namespace).
+				WithStatusSubresource(project).
+				WithScheme(Scheme).
+				Build()
+			ctx := newContext()
+
+			cfg := sharedconfig.NewFakeSharedInformation(c, nil, nil, nil)
+			cfg.ProjectDeletionGracePeriodData = time.Hour
+			pr, err := NewProjectReconciler(c.Scheme(), NewCommonReconciler(cfg, "test"))
+			require.NoError(t, err)
+			recorder := events.NewFakeRecorder(10)
+			pr.Recorder = recorder
+
+			result, _ := pr.Reconcile(ctx, newRequest(project))
+			if tC.expectRequeue {
+				assert.Greater(t, result.RequeueAfter, 58*time.Minute)
+				assert.LessOrEqual(t, result.RequeueAfter, 59*time.Minute)
+			}
+
+			close(recorder.Events)
+			recorded := []string{}
+			for e := range recorder.Events {
+				recorded = append(recorded, e)
+			}
+			require.Len(t, recorded, len(tC.expectedEvents))
+			for i := range tC.expectedEvents {
+				assert.Contains(t, recorded[i], tC.expectedEvents[i])
+			}
+
+			tC.validate(t, ctx, c)
+		})
+	}
+}
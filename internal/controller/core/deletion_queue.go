@@ -0,0 +1,90 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+// Projects and Workspaces are reconciled by two controllers each, which share the reconciler but have separate work queues:
+// one for objects in deletion and one for all others. Deletions can be blocked for a long time by remaining resources,
+// so bursts of deletions would otherwise delay the setup of new projects and workspaces.
+
+// inDeletionPredicate filters for objects which have a deletion timestamp.
+var inDeletionPredicate = predicate.NewPredicateFuncs(func(obj client.Object) bool {
+	return utils.WasDeleted(obj)
+})
+
+// deletionPhaseFilter returns a reconciler which only passes a request on to the given reconciler if the object is in deletion (for deletion=true)
+// or if it is not (for deletion=false). The predicates of the controllers already split the events, but requests which have been queued
+// or requeued before the object has been deleted would otherwise be processed by both controllers concurrently.
+// Requests for objects which do not exist anymore are passed on, so that the reconciler can clean up.
+// Requests of the regular controller for objects in deletion are sent to handover (may be nil) instead of being dropped,
+// so that the watches of the regular controller, e.g. configuration changes, reach the objects in deletion as well.
+func deletionPhaseFilter(c client.Client, newObj func() client.Object, deletion bool, handover chan<- event.GenericEvent, r reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		obj := newObj()
+		if err := c.Get(ctx, req.NamespacedName, obj); client.IgnoreNotFound(err) != nil {
+			return reconcile.Result{}, fmt.Errorf("error fetching object: %w", err)
+		} else if err == nil && utils.WasDeleted(obj) != deletion {
+			log := logging.FromContextOrDiscard(ctx)
+			if deletion || handover == nil {
+				log.Debug("Skipping request, the object is handled by the other controller", "inDeletion", utils.WasDeleted(obj))
+				return reconcile.Result{}, nil
+			}
+			log.Debug("Handing request over to the deletion controller")
+			select {
+			case handover <- event.GenericEvent{Object: obj}:
+			case <-ctx.Done():
+				return reconcile.Result{}, ctx.Err()
+			}
+			return reconcile.Result{}, nil
+		}
+		return r.Reconcile(ctx, req)
+	})
+}
+
+// blockingResourcesChangedSource returns a source for the deletion controllers, which enqueues all objects of the given kind that are in deletion
+// whenever the resources blocking deletion change. Otherwise, deletions which are not blocked anymore, e.g. because a ServiceProvider has been removed,
+// would only continue with the next requeue.
+func blockingResourcesChangedSource(cfg sharedconfig.SharedInformation, c client.Client, kind string) source.Source {
+	return source.Func(func(ctx context.Context, queue workqueue.TypedRateLimitingInterface[reconcile.Request]) error {
+		changes := cfg.Subscribe(ctx)
+		go func() {
+			log := logging.FromContextOrDiscard(ctx)
+			for change := range changes {
+				if !change.Has(sharedconfig.ChangeBlockingResources) {
+					continue
+				}
+				list := &metav1.PartialObjectMetadataList{}
+				list.SetGroupVersionKind(pwv1alpha1.GroupVersion.WithKind(kind + "List"))
+				if err := c.List(ctx, list); err != nil {
+					log.Error(err, "failed to list resources in deletion after the blocking resources changed", "kind", kind)
+					continue
+				}
+				count := 0
+				for i := range list.Items {
+					if utils.WasDeleted(&list.Items[i]) {
+						queue.Add(reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&list.Items[i])})
+						count++
+					}
+				}
+				log.Info("Blocking resources changed, enqueued all resources in deletion", "kind", kind, "count", count)
+			}
+		}()
+		return nil
+	})
+}
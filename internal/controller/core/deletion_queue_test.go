@@ -0,0 +1,94 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+)
+
+func Test_deletionPhaseFilter(t *testing.T) {
+	active := &pwv1alpha1.Project{ObjectMeta: metav1.ObjectMeta{Name: "active"}}
+	deleting := &pwv1alpha1.Project{ObjectMeta: metav1.ObjectMeta{
+		Name:              "deleting",
+		DeletionTimestamp: &metav1.Time{Time: metav1.Now().Time},
+		Finalizers:        []string{deleteFinalizer},
+	}}
+
+	testCases := []struct {
+		desc           string
+		name           string
+		deletion       bool
+		expected       bool
+		expectHandover bool
+	}{
+		{desc: "should pass active objects to the regular controller", name: active.Name, deletion: false, expected: true},
+		{desc: "should skip active objects in the deletion controller", name: active.Name, deletion: true, expected: false},
+		{desc: "should pass objects in deletion to the deletion controller", name: deleting.Name, deletion: true, expected: true},
+		{desc: "should hand objects in deletion over from the regular controller", name: deleting.Name, deletion: false, expected: false, expectHandover: true},
+		{desc: "should pass missing objects to the regular controller", name: "missing", deletion: false, expected: true},
+		{desc: "should pass missing objects to the deletion controller", name: "missing", deletion: true, expected: true},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(Scheme).WithObjects(active.DeepCopy(), deleting.DeepCopy()).Build()
+			called := false
+			inner := reconcile.Func(func(_ context.Context, _ reconcile.Request) (reconcile.Result, error) {
+				called = true
+				return reconcile.Result{}, nil
+			})
+
+			handover := make(chan event.GenericEvent, 1)
+			r := deletionPhaseFilter(c, func() client.Object { return &pwv1alpha1.Project{} }, tC.deletion, handover, inner)
+			_, err := r.Reconcile(newContext(), reconcile.Request{NamespacedName: client.ObjectKey{Name: tC.name}})
+			require.NoError(t, err)
+			assert.Equal(t, tC.expected, called)
+			if tC.expectHandover {
+				require.Len(t, handover, 1)
+				assert.Equal(t, tC.name, (<-handover).Object.GetName())
+			} else {
+				assert.Empty(t, handover)
+			}
+		})
+	}
+}
+
+func Test_blockingResourcesChangedSource(t *testing.T) {
+	active := &pwv1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "active", Namespace: "project-sample"}}
+	deleting := &pwv1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{
+		Name:              "deleting",
+		Namespace:         "project-sample",
+		DeletionTimestamp: &metav1.Time{Time: metav1.Now().Time},
+		Finalizers:        []string{deleteFinalizer},
+	}}
+	c := fake.NewClientBuilder().WithScheme(Scheme).WithObjects(active, deleting).Build()
+	cfg := sharedconfig.NewFakeSharedInformation(c, nil, nil, nil)
+
+	ctx, cancel := context.WithCancel(newContext())
+	defer cancel()
+	queue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer queue.ShutDown()
+	require.NoError(t, blockingResourcesChangedSource(cfg, c, "Workspace").Start(ctx, queue))
+
+	// other changes do not enqueue anything
+	cfg.Notify(sharedconfig.ChangePermissions)
+	assert.Never(t, func() bool { return queue.Len() > 0 }, 200*time.Millisecond, 10*time.Millisecond)
+
+	// only the workspaces in deletion are enqueued
+	cfg.Notify(sharedconfig.ChangeBlockingResources)
+	require.Eventually(t, func() bool { return queue.Len() > 0 }, time.Second, 10*time.Millisecond)
+	req, _ := queue.Get()
+	assert.Equal(t, client.ObjectKeyFromObject(deleting), req.NamespacedName)
+	assert.Zero(t, queue.Len())
+}
@@ -0,0 +1,48 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/features"
+)
+
+// isFlat returns true if the given workspace is a pure RBAC grouping within the namespace of its project.
+// For workspaces which have been reconciled before, this is taken from their status, so that changes of the configuration or class do not move existing workspaces.
+// Otherwise, the setting of the given class (may be nil) takes precedence over the one from the config. New workspaces are never flat if the feature gate is disabled.
+func (r *WorkspaceReconciler) isFlat(ctx context.Context, ws *pwv1alpha1.Workspace, class *pwv1alpha1.WorkspaceClass) (bool, error) {
+	if ws.Status.Namespace != "" {
+		return ws.IsFlat(), nil
+	}
+	if !features.Enabled(features.FlatWorkspaces) {
+		return false, nil
+	}
+	if class != nil && class.Spec.Flat != nil {
+		return *class.Spec.Flat, nil
+	}
+	flat, err := r.Config.FlatWorkspaces(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get flat workspace setting from config: %w", err)
+	}
+	return flat, nil
+}
+
+// workspaceScopedName returns the name of a namespaced resource which is created for the given workspace.
+// Flat workspaces share the namespace of their project, so the workspace name is appended to avoid conflicts between them.
+func workspaceScopedName(ws *pwv1alpha1.Workspace, name string) string {
+	if !ws.IsFlat() {
+		return name
+	}
+	return fmt.Sprintf("%s--ws-%s", name, ws.Name)
+}
+
+// deleteFlatWorkspace deletes the RBAC resources of the given flat workspace.
+// In contrast to workspaces with a dedicated namespace, they are not removed together with the namespace, since it belongs to the project.
+func (r *WorkspaceReconciler) deleteFlatWorkspace(ctx context.Context, project *pwv1alpha1.Project, ws *pwv1alpha1.Workspace) error {
+	if err := r.deleteWorkspaceResources(ctx, project, ws); err != nil {
+		return err
+	}
+	// without a class, all Roles and RoleBindings for additional permissions of a class are deleted
+	return r.applyWorkspaceClassRoles(ctx, project, ws, nil)
+}
@@ -0,0 +1,120 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+func Test_WorkspaceReconciler_flatWorkspaces(t *testing.T) {
+	dedicatedClass := &pwv1alpha1.WorkspaceClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "dedicated"},
+		Spec: pwv1alpha1.WorkspaceClassSpec{
+			Flat: ptr.To(false),
+		},
+	}
+	flatWs := sampleWorkspace.DeepCopy()
+	dedicatedWs := sampleWorkspace.DeepCopy()
+	dedicatedWs.Name = "dedicated"
+	dedicatedWs.Spec.ClassName = dedicatedClass.Name
+
+	c := fake.NewClientBuilder().
+		WithObjects(flatWs, dedicatedWs, dedicatedClass, projectNamespace, sampleProject).
+		WithStatusSubresource(flatWs, dedicatedWs).
+		WithInterceptorFuncs(reviewAccess(allowAll, interceptor.Funcs{})).
+		WithScheme(Scheme).
+		Build()
+	ctx := newContext()
+
+	cfg := sharedconfig.NewFakeSharedInformation(c, nil, nil, nil)
+	cfg.FlatWorkspacesData = true
+	wr, err := NewWorkspaceReconciler(c.Scheme(), NewCommonReconciler(cfg, "test"))
+	require.NoError(t, err)
+
+	reconcileWorkspace := func(t *testing.T, ws *pwv1alpha1.Workspace) *pwv1alpha1.Workspace {
+		result, err := ctrl.Result{}, error(nil)
+		for range maxReconcileCycles {
+			result, err = wr.Reconcile(ctx, newRequest(ws))
+			if result.RequeueAfter == 0 || err != nil {
+				break
+			}
+		}
+		require.NoError(t, err)
+		res := &pwv1alpha1.Workspace{}
+		err = c.Get(ctx, client.ObjectKeyFromObject(ws), res)
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		require.NoError(t, err)
+		return res
+	}
+	expectedAdmins := []rbacv1.Subject{
+		{
+			APIGroup: rbacv1.GroupName,
+			Kind:     rbacv1.UserKind,
+			Name:     "user@example.com",
+		},
+		{
+			APIGroup: rbacv1.GroupName,
+			Kind:     rbacv1.GroupKind,
+			Name:     "some-group",
+		},
+	}
+
+	t.Run("creates the RBAC resources of a flat workspace in the project namespace", func(t *testing.T) {
+		ws := reconcileWorkspace(t, flatWs)
+		assert.True(t, ws.IsFlat())
+		assert.Equal(t, projectNamespace.Name, ws.Status.Namespace)
+
+		err := c.Get(ctx, client.ObjectKey{Name: utils.NamespaceForWorkspace(ws)}, &corev1.Namespace{})
+		assert.True(t, apierrors.IsNotFound(err), "flat workspaces must not get a dedicated namespace")
+
+		roleBindingCreatedForWorkspace(t, ctx, c, ws, pwv1alpha1.WorkspaceRoleAdmin, true, expectedAdmins)
+		rb := &rbacv1.RoleBinding{}
+		require.NoError(t, c.Get(ctx, client.ObjectKey{Name: "workspace-admin--ws-sample", Namespace: projectNamespace.Name}, rb))
+		assert.Equal(t, utils.ClusterRoleForRole(pwv1alpha1.WorkspaceRoleAdmin), rb.RoleRef.Name)
+		clusterRoleCreatedForWorkspace(t, ctx, c, sampleProject, ws, pwv1alpha1.WorkspaceRoleAdmin, true, 1)
+	})
+
+	t.Run("keeps existing workspaces flat when the config changes", func(t *testing.T) {
+		cfg.FlatWorkspacesData = false
+		defer func() { cfg.FlatWorkspacesData = true }()
+
+		ws := reconcileWorkspace(t, flatWs)
+		assert.True(t, ws.IsFlat())
+		err := c.Get(ctx, client.ObjectKey{Name: utils.NamespaceForWorkspace(ws)}, &corev1.Namespace{})
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+
+	t.Run("creates a dedicated namespace if the class overrides the config", func(t *testing.T) {
+		ws := reconcileWorkspace(t, dedicatedWs)
+		assert.False(t, ws.IsFlat())
+		namespaceCreatedForWorkspace(t, ctx, c, ws, true)
+		roleBindingCreatedForWorkspace(t, ctx, c, ws, pwv1alpha1.WorkspaceRoleAdmin, true, expectedAdmins)
+	})
+
+	t.Run("deletes the RBAC resources of a flat workspace, but not the project namespace", func(t *testing.T) {
+		ws := &pwv1alpha1.Workspace{}
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(flatWs), ws))
+		require.NoError(t, c.Delete(ctx, ws))
+
+		assert.Nil(t, reconcileWorkspace(t, flatWs), "workspace must be deleted")
+		roleBindingCreatedForWorkspace(t, ctx, c, ws, pwv1alpha1.WorkspaceRoleAdmin, false, nil)
+		clusterRoleCreatedForWorkspace(t, ctx, c, sampleProject, ws, pwv1alpha1.WorkspaceRoleAdmin, false, 0)
+		assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(projectNamespace), &corev1.Namespace{}))
+	})
+}
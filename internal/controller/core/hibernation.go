@@ -0,0 +1,110 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+	openmcpcorev2alpha1 "github.com/openmcp-project/openmcp-operator/api/core/v2alpha1"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+// HibernationResourceQuotaName is the name of the ResourceQuota that is created in the namespace of a hibernated workspace.
+const HibernationResourceQuotaName = "workspace-hibernation"
+
+// hibernationResourceQuotaHard returns the hard limits of the ResourceQuota for hibernated workspaces.
+// Already existing resources are not affected, but no new ones can be created.
+func hibernationResourceQuotaHard() corev1.ResourceList {
+	zero := resource.MustParse("0")
+	return corev1.ResourceList{
+		corev1.ResourcePods:                   zero,
+		corev1.ResourceServices:               zero,
+		corev1.ResourcePersistentVolumeClaims: zero,
+		corev1.ResourceName(fmt.Sprintf("count/managedcontrolplanev2s.%s", openmcpcorev2alpha1.GroupName)): zero,
+	}
+}
+
+// reconcileHibernation creates or deletes the hibernation ResourceQuota in the workspace namespace, depending on whether the workspace is hibernated,
+// and updates the hibernation condition accordingly.
+// The RBAC part of the hibernation is handled when computing the subjects for the workspace roles.
+// Flat workspaces share the namespace of their project, so they don't get a ResourceQuota and are only restricted via RBAC.
+func (r *WorkspaceReconciler) reconcileHibernation(ctx context.Context, ws *pwv1alpha1.Workspace) error {
+	log := logging.FromContextOrPanic(ctx)
+
+	if ws.IsFlat() {
+		if !ws.Spec.Hibernated {
+			ws.RemoveCondition(pwv1alpha1.ConditionTypeHibernated)
+			return nil
+		}
+		ws.SetOrUpdateCondition(pwv1alpha1.Condition{
+			Type:    pwv1alpha1.ConditionTypeHibernated,
+			Status:  pwv1alpha1.ConditionStatusTrue,
+			Reason:  pwv1alpha1.ConditionReasonHibernationRequested,
+			Message: "Workspace is hibernated, all members have been reduced to the view role",
+		})
+		return nil
+	}
+
+	quota := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      HibernationResourceQuotaName,
+			Namespace: ws.Status.Namespace,
+		},
+	}
+
+	if !ws.Spec.Hibernated {
+		if _, err := r.deleteIfManaged(ctx, r.OnboardingStatic.Client(), quota); err != nil {
+			return fmt.Errorf("failed to delete hibernation ResourceQuota: %w", err)
+		}
+		ws.RemoveCondition(pwv1alpha1.ConditionTypeHibernated)
+		return nil
+	}
+
+	result, err := controllerutil.CreateOrUpdate(ctx, r.OnboardingStatic.Client(), quota, func() error {
+		if err := r.applyManagementLabel(ctx, quota); err != nil {
+			return err
+		}
+		quota.Spec.Hard = hibernationResourceQuotaHard()
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create or update hibernation ResourceQuota: %w", err)
+	}
+	utils.LogOperationResult(log, logging.INFO, quota, result)
+
+	ws.SetOrUpdateCondition(pwv1alpha1.Condition{
+		Type:    pwv1alpha1.ConditionTypeHibernated,
+		Status:  pwv1alpha1.ConditionStatusTrue,
+		Reason:  pwv1alpha1.ConditionReasonHibernationRequested,
+		Message: fmt.Sprintf("Workspace is hibernated, namespace %s is restricted and all members have been reduced to the view role", ws.Status.Namespace),
+	})
+
+	return nil
+}
+
+// effectiveWorkspaceMemberRoles returns the roles that the given member effectively has.
+// Members of hibernated workspaces are reduced to the 'view' role, the 'auditor' role is kept since it is even more restricted.
+func effectiveWorkspaceMemberRoles(workspace *pwv1alpha1.Workspace, member pwv1alpha1.WorkspaceMember) []pwv1alpha1.WorkspaceMemberRole {
+	if !workspace.Spec.Hibernated || len(member.Roles) == 0 {
+		return member.Roles
+	}
+	res := []pwv1alpha1.WorkspaceMemberRole{}
+	for _, role := range member.Roles {
+		effective := pwv1alpha1.WorkspaceRoleView
+		if role == pwv1alpha1.WorkspaceRoleAuditor {
+			effective = pwv1alpha1.WorkspaceRoleAuditor
+		}
+		if !slices.Contains(res, effective) {
+			res = append(res, effective)
+		}
+	}
+	return res
+}
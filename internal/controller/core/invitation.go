@@ -0,0 +1,237 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/openmcp-project/controller-utils/pkg/clusters"
+	ctrlutils "github.com/openmcp-project/controller-utils/pkg/controller"
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/logconfig"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/metrics"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+const (
+	InvitationControllerName = "invitation"
+
+	// DefaultInvitationRetention is the default time after which a completed Invitation is deleted.
+	DefaultInvitationRetention = 24 * time.Hour
+)
+
+// InvitationReconciler adds the subject of an Invitation to the members of its project or workspace, once the invited user has accepted it.
+// Invitations which are not accepted before their TTL passes expire.
+type InvitationReconciler struct {
+	OnboardingStatic *clusters.Cluster
+	// Retention is the time after which a completed Invitation is deleted, so that they do not pile up.
+	Retention time.Duration
+	*CommonReconciler
+}
+
+func NewInvitationReconciler(cr *CommonReconciler) (*InvitationReconciler, error) {
+	ir := &InvitationReconciler{
+		Retention:        DefaultInvitationRetention,
+		CommonReconciler: cr,
+	}
+
+	onboardingClusterStatic, err := cr.Config.OnboardingClusterStatic(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	ir.OnboardingStatic = onboardingClusterStatic
+
+	return ir, nil
+}
+
+// +kubebuilder:rbac:groups=core.openmcp.cloud,resources=invitations,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups=core.openmcp.cloud,resources=invitations/status,verbs=get;update;patch
+
+func (r *InvitationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logging.FromContextOrPanic(ctx).WithName(InvitationControllerName)
+	ctx = logging.NewContext(ctx, log)
+	log.Debug("Reconcile started")
+
+	invitation := &pwv1alpha1.Invitation{}
+	if err := r.OnboardingStatic.Client().Get(ctx, req.NamespacedName, invitation); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("error fetching Invitation: %w", err)
+	}
+	if !invitation.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	// completed invitations are deleted after the retention time
+	if invitation.IsCompleted() && invitation.Status.CompletionTime != nil {
+		remaining := time.Until(invitation.Status.CompletionTime.Add(r.Retention))
+		if remaining > 0 {
+			return ctrl.Result{RequeueAfter: remaining}, nil
+		}
+		log.Info("Deleting completed Invitation")
+		if err := r.OnboardingStatic.Client().Delete(ctx, invitation); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, fmt.Errorf("error deleting completed Invitation: %w", err)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	old := invitation.DeepCopy()
+	expiration := metav1.NewTime(invitation.ExpirationTime())
+	invitation.Status.ExpirationTime = &expiration
+	var requeueAfter time.Duration
+	switch {
+	case invitation.IsAccepted():
+		// the webhook only allows accepting invitations before they expire
+		message, err := r.addMember(ctx, invitation)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if message != "" {
+			log.Info("Invitation failed", "reason", message)
+			r.complete(invitation, pwv1alpha1.InvitationPhaseFailed, message)
+		} else {
+			log.Info("Invitation accepted", "acceptedBy", invitation.GetAnnotations()[pwv1alpha1.InvitationAcceptedByAnnotation])
+			r.complete(invitation, pwv1alpha1.InvitationPhaseAccepted, "")
+			invitation.Status.AcceptedBy = invitation.GetAnnotations()[pwv1alpha1.InvitationAcceptedByAnnotation]
+		}
+		requeueAfter = r.Retention
+	case !time.Now().Before(expiration.Time):
+		log.Info("Invitation expired")
+		r.complete(invitation, pwv1alpha1.InvitationPhaseExpired, "the invitation has not been accepted in time")
+		requeueAfter = r.Retention
+	default:
+		invitation.Status.Phase = pwv1alpha1.InvitationPhasePending
+		requeueAfter = time.Until(expiration.Time)
+	}
+
+	if err := r.OnboardingStatic.Client().Status().Patch(ctx, invitation, client.MergeFrom(old)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("error updating Invitation status: %w", err)
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// complete sets the given final phase and message and the completion time in the status of the given invitation.
+func (r *InvitationReconciler) complete(invitation *pwv1alpha1.Invitation, phase pwv1alpha1.InvitationPhase, message string) {
+	now := metav1.Now()
+	invitation.Status.Phase = phase
+	invitation.Status.Message = message
+	invitation.Status.CompletionTime = &now
+}
+
+// addMember adds the subject of the given invitation with its roles to the members of the project or workspace. Roles the subject already has are kept.
+// Returns a message why the subject cannot be added, e.g. because the project has been deleted in the meantime, or an empty string on success.
+func (r *InvitationReconciler) addMember(ctx context.Context, invitation *pwv1alpha1.Invitation) (string, error) {
+	project := &pwv1alpha1.Project{}
+	if err := r.OnboardingStatic.Client().Get(ctx, client.ObjectKey{Name: invitation.Spec.Project}, project); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Sprintf("project %s does not exist", invitation.Spec.Project), nil
+		}
+		return "", fmt.Errorf("error fetching project %s: %w", invitation.Spec.Project, err)
+	}
+	if !project.DeletionTimestamp.IsZero() {
+		return fmt.Sprintf("project %s is being deleted", project.Name), nil
+	}
+	subject := invitation.Spec.Subject
+
+	if invitation.Spec.Workspace == "" {
+		if message, err := managedMemberMessage(project, subject); message != "" || err != nil {
+			return message, err
+		}
+		old := project.DeepCopy()
+		project.Spec.Members = addInvitedProjectMember(project.Spec.Members, subject, invitation.Spec.Roles)
+		if err := r.OnboardingStatic.Client().Patch(ctx, project, client.MergeFrom(old)); err != nil {
+			return "", fmt.Errorf("error adding member to project %s: %w", project.Name, err)
+		}
+		return "", nil
+	}
+
+	if project.Status.Namespace == "" {
+		return fmt.Sprintf("workspace %s does not exist", invitation.Spec.Workspace), nil
+	}
+	workspace := &pwv1alpha1.Workspace{}
+	if err := r.OnboardingStatic.Client().Get(ctx, client.ObjectKey{Name: invitation.Spec.Workspace, Namespace: project.Status.Namespace}, workspace); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Sprintf("workspace %s does not exist", invitation.Spec.Workspace), nil
+		}
+		return "", fmt.Errorf("error fetching workspace %s/%s: %w", project.Status.Namespace, invitation.Spec.Workspace, err)
+	}
+	if !workspace.DeletionTimestamp.IsZero() {
+		return fmt.Sprintf("workspace %s is being deleted", workspace.Name), nil
+	}
+	if message, err := managedMemberMessage(workspace, subject); message != "" || err != nil {
+		return message, err
+	}
+	old := workspace.DeepCopy()
+	workspace.Spec.Members = addInvitedWorkspaceMember(workspace.Spec.Members, subject, invitation.Spec.Roles)
+	if err := r.OnboardingStatic.Client().Patch(ctx, workspace, client.MergeFrom(old)); err != nil {
+		return "", fmt.Errorf("error adding member to workspace %s/%s: %w", workspace.Namespace, workspace.Name, err)
+	}
+	return "", nil
+}
+
+// managedMemberMessage returns a message if the given subject is synced from a ProjectMembershipSource to the given project or workspace,
+// since the sync would remove the roles added by the invitation again.
+func managedMemberMessage(obj metav1.Object, subject pwv1alpha1.Subject) (string, error) {
+	source, managed, err := utils.ManagedMembers(obj)
+	if err != nil {
+		return "", err
+	}
+	if slices.Contains(managed, subject) {
+		return fmt.Sprintf("the member is synced from ProjectMembershipSource %s, its roles can only be changed in the source", source), nil
+	}
+	return "", nil
+}
+
+// addInvitedProjectMember returns the given members with the subject having the given roles in addition to the ones it already has.
+func addInvitedProjectMember(members []pwv1alpha1.ProjectMember, subject pwv1alpha1.Subject, roles []string) []pwv1alpha1.ProjectMember {
+	idx := slices.IndexFunc(members, func(m pwv1alpha1.ProjectMember) bool { return m.Subject == subject })
+	if idx < 0 {
+		members = append(members, pwv1alpha1.ProjectMember{Subject: subject})
+		idx = len(members) - 1
+	}
+	for _, role := range roles {
+		if !slices.Contains(members[idx].Roles, pwv1alpha1.ProjectMemberRole(role)) {
+			members[idx].Roles = append(members[idx].Roles, pwv1alpha1.ProjectMemberRole(role))
+		}
+	}
+	return members
+}
+
+// addInvitedWorkspaceMember returns the given members with the subject having the given roles in addition to the ones it already has.
+func addInvitedWorkspaceMember(members []pwv1alpha1.WorkspaceMember, subject pwv1alpha1.Subject, roles []string) []pwv1alpha1.WorkspaceMember {
+	idx := slices.IndexFunc(members, func(m pwv1alpha1.WorkspaceMember) bool { return m.Subject == subject })
+	if idx < 0 {
+		members = append(members, pwv1alpha1.WorkspaceMember{Subject: subject})
+		idx = len(members) - 1
+	}
+	for _, role := range roles {
+		if !slices.Contains(members[idx].Roles, pwv1alpha1.WorkspaceMemberRole(role)) {
+			members[idx].Roles = append(members[idx].Roles, pwv1alpha1.WorkspaceMemberRole(role))
+		}
+	}
+	return members
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// The acceptance of an invitation is an annotation, so it does not change the generation.
+func (r *InvitationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(InvitationControllerName).
+		WithLogConstructor(logconfig.LogConstructor(mgr.GetLogger(), InvitationControllerName)).
+		For(&pwv1alpha1.Invitation{}, builder.WithPredicates(predicate.Or(
+			predicate.GenerationChangedPredicate{},
+			ctrlutils.GotAnnotationPredicate(pwv1alpha1.InvitationAcceptAnnotation, "true"),
+		))).
+		Complete(metrics.ObserveReconciler(InvitationControllerName, r))
+}
@@ -0,0 +1,184 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+func Test_InvitationReconciler(t *testing.T) {
+	alice := pwv1alpha1.Subject{Kind: rbacv1.UserKind, Name: "alice"}
+	newProject := func() *pwv1alpha1.Project {
+		return &pwv1alpha1.Project{
+			ObjectMeta: metav1.ObjectMeta{Name: "alpha"},
+			Spec: pwv1alpha1.ProjectSpec{Members: []pwv1alpha1.ProjectMember{
+				{Subject: pwv1alpha1.Subject{Kind: rbacv1.UserKind, Name: "jane"}, Roles: []pwv1alpha1.ProjectMemberRole{pwv1alpha1.ProjectRoleAdmin}},
+			}},
+			Status: pwv1alpha1.ProjectStatus{Namespace: "project-alpha"},
+		}
+	}
+	newWorkspace := func() *pwv1alpha1.Workspace {
+		return &pwv1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "dev", Namespace: "project-alpha"},
+			Spec: pwv1alpha1.WorkspaceSpec{Members: []pwv1alpha1.WorkspaceMember{
+				{Subject: alice, Roles: []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleView}},
+			}},
+		}
+	}
+	newInvitation := func(workspace string, accepted bool, age time.Duration) *pwv1alpha1.Invitation {
+		invitation := &pwv1alpha1.Invitation{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "invite-alice",
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+			},
+			Spec: pwv1alpha1.InvitationSpec{
+				Project:   "alpha",
+				Workspace: workspace,
+				Subject:   alice,
+				Roles:     []string{"admin"},
+			},
+		}
+		if accepted {
+			invitation.Annotations = map[string]string{
+				pwv1alpha1.InvitationAcceptAnnotation:     "true",
+				pwv1alpha1.InvitationAcceptedByAnnotation: "alice",
+			}
+		}
+		return invitation
+	}
+	managedProject := newProject()
+	require.NoError(t, utils.SetManagedMembers(managedProject, "ldap", []pwv1alpha1.Subject{alice}))
+
+	testCases := []struct {
+		desc              string
+		project           *pwv1alpha1.Project
+		invitation        *pwv1alpha1.Invitation
+		expectedPhase     pwv1alpha1.InvitationPhase
+		expectedMessage   string
+		expectedProject   []pwv1alpha1.ProjectMemberRole
+		expectedWorkspace []pwv1alpha1.WorkspaceMemberRole
+	}{
+		{
+			desc:          "should keep pending invitations until they are accepted",
+			project:       newProject(),
+			invitation:    newInvitation("", false, time.Hour),
+			expectedPhase: pwv1alpha1.InvitationPhasePending,
+		},
+		{
+			desc:            "should add the subject to the project once the invitation is accepted",
+			project:         newProject(),
+			invitation:      newInvitation("", true, time.Hour),
+			expectedPhase:   pwv1alpha1.InvitationPhaseAccepted,
+			expectedProject: []pwv1alpha1.ProjectMemberRole{pwv1alpha1.ProjectRoleAdmin},
+		},
+		{
+			desc:              "should add the roles to an existing workspace member",
+			project:           newProject(),
+			invitation:        newInvitation("dev", true, time.Hour),
+			expectedPhase:     pwv1alpha1.InvitationPhaseAccepted,
+			expectedWorkspace: []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleView, pwv1alpha1.WorkspaceRoleAdmin},
+		},
+		{
+			desc:              "should fail if the workspace does not exist",
+			project:           newProject(),
+			invitation:        newInvitation("prod", true, time.Hour),
+			expectedPhase:     pwv1alpha1.InvitationPhaseFailed,
+			expectedMessage:   "workspace prod does not exist",
+			expectedWorkspace: []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleView},
+		},
+		{
+			desc:            "should fail if the subject is synced from a membership source",
+			project:         managedProject,
+			invitation:      newInvitation("", true, time.Hour),
+			expectedPhase:   pwv1alpha1.InvitationPhaseFailed,
+			expectedMessage: "the member is synced from ProjectMembershipSource ldap, its roles can only be changed in the source",
+		},
+		{
+			desc:            "should expire invitations which have not been accepted in time",
+			project:         newProject(),
+			invitation:      newInvitation("", false, 2*pwv1alpha1.DefaultInvitationTTL),
+			expectedPhase:   pwv1alpha1.InvitationPhaseExpired,
+			expectedMessage: "the invitation has not been accepted in time",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			c := fake.NewClientBuilder().
+				WithScheme(Scheme).
+				WithObjects(tC.project, newWorkspace(), tC.invitation).
+				WithStatusSubresource(&pwv1alpha1.Invitation{}).
+				Build()
+			ctx := newContext()
+
+			ir, err := NewInvitationReconciler(NewCommonReconciler(sharedconfig.NewFakeSharedInformation(c, nil, nil, nil), "test"))
+			require.NoError(t, err)
+			req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(tC.invitation)}
+
+			rr, err := ir.Reconcile(ctx, req)
+			require.NoError(t, err)
+			assert.Positive(t, rr.RequeueAfter)
+
+			actual := &pwv1alpha1.Invitation{}
+			require.NoError(t, c.Get(ctx, req.NamespacedName, actual))
+			assert.Equal(t, tC.expectedPhase, actual.Status.Phase)
+			assert.Equal(t, tC.expectedMessage, actual.Status.Message)
+			assert.NotNil(t, actual.Status.ExpirationTime)
+
+			project := &pwv1alpha1.Project{}
+			require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(tC.project), project))
+			assert.Equal(t, tC.expectedProject, projectRolesOf(project, alice))
+			workspace := &pwv1alpha1.Workspace{}
+			require.NoError(t, c.Get(ctx, client.ObjectKey{Name: "dev", Namespace: "project-alpha"}, workspace))
+			expectedWorkspace := tC.expectedWorkspace
+			if expectedWorkspace == nil {
+				expectedWorkspace = []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleView}
+			}
+			assert.Equal(t, expectedWorkspace, workspaceRolesOf(workspace, alice))
+
+			if !actual.IsCompleted() {
+				assert.Nil(t, actual.Status.CompletionTime)
+				return
+			}
+			if tC.expectedPhase == pwv1alpha1.InvitationPhaseAccepted {
+				assert.Equal(t, "alice", actual.Status.AcceptedBy)
+			}
+
+			// the invitation is deleted once the retention time has passed
+			actual.Status.CompletionTime = &metav1.Time{Time: time.Now().Add(-DefaultInvitationRetention)}
+			require.NoError(t, c.Status().Update(ctx, actual))
+			_, err = ir.Reconcile(ctx, req)
+			require.NoError(t, err)
+			assert.True(t, apierrors.IsNotFound(c.Get(ctx, req.NamespacedName, actual)))
+		})
+	}
+}
+
+func projectRolesOf(project *pwv1alpha1.Project, subject pwv1alpha1.Subject) []pwv1alpha1.ProjectMemberRole {
+	for _, member := range project.Spec.Members {
+		if member.Subject == subject {
+			return member.Roles
+		}
+	}
+	return nil
+}
+
+func workspaceRolesOf(workspace *pwv1alpha1.Workspace, subject pwv1alpha1.Subject) []pwv1alpha1.WorkspaceMemberRole {
+	for _, member := range workspace.Spec.Members {
+		if member.Subject == subject {
+			return member.Roles
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+)
+
+// updateMemberStatuses computes the RBAC status of each effective member of the workspace and stores it in the workspace status.
+// rejected contains the members which are not bound, because they are not allowed by the config, mapped to the reason. They are failed.
+// rbacErr is the error which occurred while applying the role bindings, if any. Members whose current roles have not been applied yet are pending in this case.
+// Members which were already active with the same roles keep their status and last applied time.
+func (r *WorkspaceReconciler) updateMemberStatuses(ctx context.Context, project *pwv1alpha1.Project, ws *pwv1alpha1.Workspace, rejected map[pwv1alpha1.Subject]string, rbacErr error) error {
+	now := metav1.Now()
+	existingNamespaces := map[string]bool{}
+
+	members := ws.EffectiveMembers(project)
+	statuses := make([]pwv1alpha1.WorkspaceMemberStatus, 0, len(members))
+	for _, member := range members {
+		status := pwv1alpha1.WorkspaceMemberStatus{
+			Subject: member.Subject,
+			Roles:   effectiveWorkspaceMemberRoles(ws, member),
+		}
+		previous := findMemberStatus(ws.Status.MemberStatuses, member.Subject)
+		upToDate := previous != nil && previous.Phase == pwv1alpha1.MemberPhaseActive && slices.Equal(previous.Roles, status.Roles)
+
+		if reason, ok := rejected[member.Subject]; ok {
+			status.Phase = pwv1alpha1.MemberPhaseFailed
+			status.Message = reason
+			statuses = append(statuses, status)
+			continue
+		}
+
+		if member.Kind == rbacv1.ServiceAccountKind {
+			exists, ok := existingNamespaces[member.Namespace]
+			if !ok {
+				var err error
+				exists, err = r.namespaceExists(ctx, member.Namespace)
+				if err != nil {
+					return err
+				}
+				existingNamespaces[member.Namespace] = exists
+			}
+			if !exists {
+				status.Phase = pwv1alpha1.MemberPhaseFailed
+				status.Message = fmt.Sprintf("Namespace '%s' of the ServiceAccount does not exist", member.Namespace)
+				statuses = append(statuses, status)
+				continue
+			}
+		}
+
+		switch {
+		case upToDate:
+			status.Phase = pwv1alpha1.MemberPhaseActive
+			status.LastAppliedTime = previous.LastAppliedTime
+		case rbacErr != nil:
+			status.Phase = pwv1alpha1.MemberPhasePending
+			status.Message = fmt.Sprintf("Failed to apply role bindings: %s", rbacErr.Error())
+			if previous != nil {
+				status.LastAppliedTime = previous.LastAppliedTime
+			}
+		default:
+			status.Phase = pwv1alpha1.MemberPhaseActive
+			status.LastAppliedTime = &now
+		}
+		statuses = append(statuses, status)
+	}
+
+	ws.Status.MemberStatuses = statuses
+	return nil
+}
+
+func (r *WorkspaceReconciler) namespaceExists(ctx context.Context, name string) (bool, error) {
+	if err := r.OnboardingStatic.Client().Get(ctx, types.NamespacedName{Name: name}, &corev1.Namespace{}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("error fetching namespace '%s': %w", name, err)
+	}
+	return true, nil
+}
+
+func findMemberStatus(statuses []pwv1alpha1.WorkspaceMemberStatus, subject pwv1alpha1.Subject) *pwv1alpha1.WorkspaceMemberStatus {
+	for i := range statuses {
+		if statuses[i].Subject == subject {
+			return &statuses[i]
+		}
+	}
+	return nil
+}
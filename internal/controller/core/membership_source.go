@@ -0,0 +1,520 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openmcp-project/controller-utils/pkg/clusters"
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/logconfig"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/metrics"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+const (
+	MembershipSourceControllerName = "membership-source"
+
+	// membershipSourceResyncInterval is the interval in which sources are synced again, independent of changes.
+	// This picks up projects and workspaces which have been created after the last sync, and changes of sources
+	// which are not labeled and therefore not watched.
+	membershipSourceResyncInterval = 10 * time.Minute
+)
+
+// membershipSourceContent is the format of the memberships in the ConfigMap or Secret which is referenced by a ProjectMembershipSource.
+type membershipSourceContent struct {
+	Projects []membershipSourceProject `json:"projects"`
+}
+
+// membershipSourceProject contains the members of a project and its workspaces.
+type membershipSourceProject struct {
+	Name       string                      `json:"name"`
+	Members    []pwv1alpha1.ProjectMember  `json:"members,omitempty"`
+	Workspaces []membershipSourceWorkspace `json:"workspaces,omitempty"`
+}
+
+// membershipSourceWorkspace contains the members of a workspace.
+type membershipSourceWorkspace struct {
+	Name    string                       `json:"name"`
+	Members []pwv1alpha1.WorkspaceMember `json:"members,omitempty"`
+}
+
+// errInvalidMembershipSource marks errors which are caused by the content of the source, they are not retried until the source changes.
+var errInvalidMembershipSource = errors.New("invalid membership source")
+
+// ProjectMembershipSourceReconciler syncs the members of projects and workspaces from the ConfigMaps or Secrets referenced by ProjectMembershipSources.
+// Synced members are merged into the existing members and marked as managed via the ManagedMembersAnnotation,
+// the project and workspace webhooks prevent manual changes to them.
+type ProjectMembershipSourceReconciler struct {
+	OnboardingStatic *clusters.Cluster
+	*CommonReconciler
+}
+
+func NewProjectMembershipSourceReconciler(cr *CommonReconciler) (*ProjectMembershipSourceReconciler, error) {
+	pms := &ProjectMembershipSourceReconciler{
+		CommonReconciler: cr,
+	}
+
+	onboardingClusterStatic, err := cr.Config.OnboardingClusterStatic(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	pms.OnboardingStatic = onboardingClusterStatic
+
+	return pms, nil
+}
+
+// +kubebuilder:rbac:groups=core.openmcp.cloud,resources=projectmembershipsources,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=core.openmcp.cloud,resources=projectmembershipsources/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core.openmcp.cloud,resources=projectmembershipsources/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+
+func (r *ProjectMembershipSourceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logging.FromContextOrPanic(ctx).WithName(MembershipSourceControllerName)
+	ctx = logging.NewContext(ctx, log)
+	log.Debug("Reconcile started")
+
+	source := &pwv1alpha1.ProjectMembershipSource{}
+	if err := r.OnboardingStatic.Client().Get(ctx, req.NamespacedName, source); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("error fetching ProjectMembershipSource: %w", err)
+	}
+
+	if utils.WasDeleted(source) {
+		// the members stay in place, they can be changed manually again
+		if !controllerutil.ContainsFinalizer(source, deleteFinalizer) {
+			return ctrl.Result{}, nil
+		}
+		if err := r.releaseTargets(ctx, source.Name, nil, nil); err != nil {
+			return ctrl.Result{}, err
+		}
+		controllerutil.RemoveFinalizer(source, deleteFinalizer)
+		if err := r.OnboardingStatic.Client().Update(ctx, source); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to remove finalizer: %w", err)
+		}
+		return ctrl.Result{}, nil
+	}
+	if err := r.ensureFinalizer(ctx, source); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	old := source.DeepCopy()
+	source.Status.ObservedGeneration = source.Generation
+	content, err := r.readSource(ctx, source.Spec.SourceRef)
+	switch {
+	case apierrors.IsNotFound(err):
+		// GitOps tools may recreate the source, so the members which have been synced before are kept
+		source.SetOrUpdateCondition(pwv1alpha1.Condition{
+			Type:    pwv1alpha1.ConditionTypeSynced,
+			Status:  pwv1alpha1.ConditionStatusFalse,
+			Reason:  pwv1alpha1.ConditionReasonSourceNotFound,
+			Message: fmt.Sprintf("%s %s/%s does not exist", source.Spec.SourceRef.Kind, source.Spec.SourceRef.Namespace, source.Spec.SourceRef.Name),
+		})
+	case errors.Is(err, errInvalidMembershipSource):
+		source.SetOrUpdateCondition(pwv1alpha1.Condition{
+			Type:    pwv1alpha1.ConditionTypeSynced,
+			Status:  pwv1alpha1.ConditionStatusFalse,
+			Reason:  pwv1alpha1.ConditionReasonSourceInvalid,
+			Message: err.Error(),
+		})
+	case err != nil:
+		return ctrl.Result{}, err
+	default:
+		projects, workspaces, skipped, err := r.syncMembers(ctx, source.Name, content)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.releaseTargets(ctx, source.Name, projects, workspaces); err != nil {
+			return ctrl.Result{}, err
+		}
+		source.Status.Projects = projects
+		source.Status.Workspaces = workspaces
+		condition := pwv1alpha1.Condition{
+			Type:   pwv1alpha1.ConditionTypeSynced,
+			Status: pwv1alpha1.ConditionStatusTrue,
+			Reason: pwv1alpha1.ConditionReasonMembersSynced,
+		}
+		if len(skipped) > 0 {
+			condition.Status = pwv1alpha1.ConditionStatusFalse
+			condition.Reason = pwv1alpha1.ConditionReasonTargetsSkipped
+			condition.Message = strings.Join(skipped, ", ")
+		}
+		source.SetOrUpdateCondition(condition)
+	}
+
+	if err := r.OnboardingStatic.Client().Status().Patch(ctx, source, client.MergeFrom(old)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("error updating ProjectMembershipSource status: %w", err)
+	}
+	return ctrl.Result{RequeueAfter: membershipSourceResyncInterval}, nil
+}
+
+// readSource reads and validates the memberships from the referenced ConfigMap or Secret.
+// A NotFound error is returned if the object does not exist, errors caused by its content wrap errInvalidMembershipSource.
+func (r *ProjectMembershipSourceReconciler) readSource(ctx context.Context, ref pwv1alpha1.MembershipSourceReference) (*membershipSourceContent, error) {
+	key := types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}
+	var data []byte
+	var found bool
+	switch ref.Kind {
+	case pwv1alpha1.MembershipSourceKindConfigMap:
+		cm := &corev1.ConfigMap{}
+		if err := r.OnboardingStatic.Client().Get(ctx, key, cm); err != nil {
+			return nil, err
+		}
+		var value string
+		if value, found = cm.Data[ref.DataKey()]; found {
+			data = []byte(value)
+		} else {
+			data, found = cm.BinaryData[ref.DataKey()]
+		}
+	case pwv1alpha1.MembershipSourceKindSecret:
+		secret := &corev1.Secret{}
+		if err := r.OnboardingStatic.Client().Get(ctx, key, secret); err != nil {
+			return nil, err
+		}
+		data, found = secret.Data[ref.DataKey()]
+	default:
+		return nil, fmt.Errorf("%w: unsupported kind '%s'", errInvalidMembershipSource, ref.Kind)
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: %s %s does not contain key '%s'", errInvalidMembershipSource, ref.Kind, key.String(), ref.DataKey())
+	}
+
+	content := &membershipSourceContent{}
+	if err := yaml.UnmarshalStrict(data, content); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse key '%s' of %s %s: %w", errInvalidMembershipSource, ref.DataKey(), ref.Kind, key.String(), err)
+	}
+	if err := content.validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidMembershipSource, err)
+	}
+	return content, nil
+}
+
+// validate checks that projects, workspaces and members are unique, and that all subjects and roles are valid.
+func (c *membershipSourceContent) validate() error {
+	projects := map[string]bool{}
+	for _, p := range c.Projects {
+		if p.Name == "" {
+			return errors.New("project without name")
+		}
+		if projects[p.Name] {
+			return fmt.Errorf("project %s is listed more than once", p.Name)
+		}
+		projects[p.Name] = true
+		if err := validateMembers(p.Name, p.Members, func(m pwv1alpha1.ProjectMember) pwv1alpha1.Subject { return m.Subject }, func(m pwv1alpha1.ProjectMember) error {
+			for _, role := range m.Roles {
+				if !slices.Contains(pwv1alpha1.ProjectMemberRoles(), role) {
+					return fmt.Errorf("unknown project role '%s'", role)
+				}
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		workspaces := map[string]bool{}
+		for _, ws := range p.Workspaces {
+			if ws.Name == "" {
+				return fmt.Errorf("workspace without name in project %s", p.Name)
+			}
+			if workspaces[ws.Name] {
+				return fmt.Errorf("workspace %s/%s is listed more than once", p.Name, ws.Name)
+			}
+			workspaces[ws.Name] = true
+			if err := validateMembers(p.Name+"/"+ws.Name, ws.Members, func(m pwv1alpha1.WorkspaceMember) pwv1alpha1.Subject { return m.Subject }, func(m pwv1alpha1.WorkspaceMember) error {
+				for _, role := range m.Roles {
+					if !slices.Contains(pwv1alpha1.WorkspaceMemberRoles(), role) {
+						return fmt.Errorf("unknown workspace role '%s'", role)
+					}
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateMembers checks the members of the given project or workspace. The roles are validated by the given function.
+func validateMembers[M any](target string, members []M, subjectOf func(M) pwv1alpha1.Subject, validateRoles func(M) error) error {
+	seen := map[pwv1alpha1.Subject]bool{}
+	for _, m := range members {
+		subject := subjectOf(m)
+		if err := validateSubject(subject); err != nil {
+			return fmt.Errorf("invalid member of %s: %w", target, err)
+		}
+		if seen[subject] {
+			return fmt.Errorf("member %s of %s is listed more than once", utils.FormatSubject(subject.RbacV1()), target)
+		}
+		seen[subject] = true
+		if err := validateRoles(m); err != nil {
+			return fmt.Errorf("invalid member %s of %s: %w", utils.FormatSubject(subject.RbacV1()), target, err)
+		}
+	}
+	return nil
+}
+
+// validateSubject applies the validations of the Subject type, which are otherwise done by the API server.
+func validateSubject(s pwv1alpha1.Subject) error {
+	if s.Name == "" {
+		return errors.New("subject without name")
+	}
+	switch s.Kind {
+	case rbacv1.UserKind, rbacv1.GroupKind:
+		if s.Namespace != "" {
+			return fmt.Errorf("namespace must not be specified for %s %s", s.Kind, s.Name)
+		}
+	case rbacv1.ServiceAccountKind:
+		if s.Namespace == "" {
+			return fmt.Errorf("namespace is required for ServiceAccount %s", s.Name)
+		}
+	default:
+		return fmt.Errorf("unsupported kind '%s' of subject %s", s.Kind, s.Name)
+	}
+	return nil
+}
+
+// syncMembers merges the members from the source into the projects and workspaces.
+// It returns the projects and workspaces which are in sync, and messages for those which have been skipped.
+func (r *ProjectMembershipSourceReconciler) syncMembers(ctx context.Context, sourceName string, content *membershipSourceContent) (projects, workspaces, skipped []string, err error) {
+	log := logging.FromContextOrPanic(ctx)
+	c := r.OnboardingStatic.Client()
+
+	for _, entry := range content.Projects {
+		project := &pwv1alpha1.Project{}
+		if err := c.Get(ctx, types.NamespacedName{Name: entry.Name}, project); err != nil {
+			if apierrors.IsNotFound(err) {
+				skipped = append(skipped, fmt.Sprintf("project %s does not exist", entry.Name))
+				continue
+			}
+			return nil, nil, nil, fmt.Errorf("failed to get project %s: %w", entry.Name, err)
+		}
+		changed, reason, err := syncManagedMembers(ctx, c, sourceName, project, &project.Spec.Members, entry.Members, func(m pwv1alpha1.ProjectMember) pwv1alpha1.Subject { return m.Subject })
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to sync members of project %s: %w", entry.Name, err)
+		}
+		if reason != "" {
+			skipped = append(skipped, fmt.Sprintf("project %s %s", entry.Name, reason))
+			continue
+		}
+		if changed {
+			log.Info("Synced project members", "project", entry.Name, "source", sourceName)
+		}
+		projects = append(projects, entry.Name)
+
+		for _, wsEntry := range entry.Workspaces {
+			workspace := &pwv1alpha1.Workspace{}
+			if err := c.Get(ctx, types.NamespacedName{Name: wsEntry.Name, Namespace: utils.NamespaceForProject(project)}, workspace); err != nil {
+				if apierrors.IsNotFound(err) {
+					skipped = append(skipped, fmt.Sprintf("workspace %s/%s does not exist", entry.Name, wsEntry.Name))
+					continue
+				}
+				return nil, nil, nil, fmt.Errorf("failed to get workspace %s/%s: %w", entry.Name, wsEntry.Name, err)
+			}
+			changed, reason, err := syncManagedMembers(ctx, c, sourceName, workspace, &workspace.Spec.Members, wsEntry.Members, func(m pwv1alpha1.WorkspaceMember) pwv1alpha1.Subject { return m.Subject })
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("failed to sync members of workspace %s/%s: %w", entry.Name, wsEntry.Name, err)
+			}
+			if reason != "" {
+				skipped = append(skipped, fmt.Sprintf("workspace %s/%s %s", entry.Name, wsEntry.Name, reason))
+				continue
+			}
+			if changed {
+				log.Info("Synced workspace members", "project", entry.Name, "workspace", wsEntry.Name, "source", sourceName)
+			}
+			workspaces = append(workspaces, entry.Name+"/"+wsEntry.Name)
+		}
+	}
+
+	slices.Sort(projects)
+	slices.Sort(workspaces)
+	return projects, workspaces, skipped, nil
+}
+
+// syncManagedMembers merges the desired members into the members of the given project or workspace and patches it, if anything changed.
+// Members which have been synced before, but are not desired anymore, are removed. Members which have been added manually are kept,
+// unless the source contains them too, in which case they are taken over. If the members are already synced from another source,
+// nothing is changed and the reason is returned.
+func syncManagedMembers[M any](ctx context.Context, c client.Client, sourceName string, obj client.Object, members *[]M, desired []M, subjectOf func(M) pwv1alpha1.Subject) (bool, string, error) {
+	owner, previous, err := utils.ManagedMembers(obj)
+	if err != nil {
+		return false, "", err
+	}
+	if owner != "" && owner != sourceName {
+		return false, fmt.Sprintf("is already synced from ProjectMembershipSource %s", owner), nil
+	}
+
+	old := obj.DeepCopyObject().(client.Object)
+	merged := mergeManagedMembers(*members, desired, subjectOf, previous)
+	managed := make([]pwv1alpha1.Subject, 0, len(desired))
+	for _, m := range desired {
+		managed = append(managed, subjectOf(m))
+	}
+	unchanged := len(*members) == 0 && len(merged) == 0 || reflect.DeepEqual(*members, merged)
+	if owner == sourceName && slices.Equal(previous, managed) && unchanged {
+		return false, "", nil
+	}
+
+	*members = merged
+	if err := utils.SetManagedMembers(obj, sourceName, managed); err != nil {
+		return false, "", err
+	}
+	// the members are a list, so concurrent manual changes would be overwritten without the optimistic lock
+	if err := c.Patch(ctx, obj, client.MergeFromWithOptions(old, client.MergeFromWithOptimisticLock{})); err != nil {
+		return false, "", err
+	}
+	return true, "", nil
+}
+
+// mergeManagedMembers returns the members with the previously managed ones replaced by the desired ones.
+// The order of the existing members is kept, new members are appended.
+func mergeManagedMembers[M any](members, desired []M, subjectOf func(M) pwv1alpha1.Subject, previous []pwv1alpha1.Subject) []M {
+	desiredBySubject := make(map[pwv1alpha1.Subject]M, len(desired))
+	for _, m := range desired {
+		desiredBySubject[subjectOf(m)] = m
+	}
+
+	res := make([]M, 0, len(members)+len(desired))
+	added := map[pwv1alpha1.Subject]bool{}
+	for _, m := range members {
+		subject := subjectOf(m)
+		if d, ok := desiredBySubject[subject]; ok {
+			if !added[subject] {
+				res = append(res, d)
+				added[subject] = true
+			}
+			continue
+		}
+		if slices.Contains(previous, subject) {
+			continue
+		}
+		res = append(res, m)
+	}
+	for _, m := range desired {
+		if !added[subjectOf(m)] {
+			res = append(res, m)
+		}
+	}
+	return res
+}
+
+// releaseTargets removes the marker of the given source from all projects and workspaces which are not in the given lists.
+func (r *ProjectMembershipSourceReconciler) releaseTargets(ctx context.Context, sourceName string, keepProjects, keepWorkspaces []string) error {
+	log := logging.FromContextOrPanic(ctx)
+	c := r.OnboardingStatic.Client()
+
+	projects := &pwv1alpha1.ProjectList{}
+	if err := c.List(ctx, projects, client.MatchingLabels{pwv1alpha1.MembershipSourceLabel: sourceName}); err != nil {
+		return fmt.Errorf("failed to list projects of membership source: %w", err)
+	}
+	for i := range projects.Items {
+		project := &projects.Items[i]
+		if slices.Contains(keepProjects, project.Name) {
+			continue
+		}
+		if err := releaseTarget(ctx, c, project); err != nil {
+			return fmt.Errorf("failed to release members of project %s: %w", project.Name, err)
+		}
+		log.Info("Released project members", "project", project.Name, "source", sourceName)
+	}
+
+	workspaces := &pwv1alpha1.WorkspaceList{}
+	if err := c.List(ctx, workspaces, client.MatchingLabels{pwv1alpha1.MembershipSourceLabel: sourceName}); err != nil {
+		return fmt.Errorf("failed to list workspaces of membership source: %w", err)
+	}
+	for i := range workspaces.Items {
+		workspace := &workspaces.Items[i]
+		// workspaces are listed as '<project>/<workspace>', and live in the namespace of their project
+		name := strings.TrimPrefix(workspace.Namespace, "project-") + "/" + workspace.Name
+		if slices.Contains(keepWorkspaces, name) {
+			continue
+		}
+		if err := releaseTarget(ctx, c, workspace); err != nil {
+			return fmt.Errorf("failed to release members of workspace %s/%s: %w", workspace.Namespace, workspace.Name, err)
+		}
+		log.Info("Released workspace members", "workspace", workspace.Name, "namespace", workspace.Namespace, "source", sourceName)
+	}
+	return nil
+}
+
+// releaseTarget removes the marker of the membership source from the given project or workspace.
+func releaseTarget(ctx context.Context, c client.Client, obj client.Object) error {
+	old := obj.DeepCopyObject().(client.Object)
+	if !utils.ReleaseManagedMembers(obj) {
+		return nil
+	}
+	return c.Patch(ctx, obj, client.MergeFrom(old))
+}
+
+// sourcesReferencing returns reconcile requests for all ProjectMembershipSources which reference the given ConfigMap or Secret.
+func (r *ProjectMembershipSourceReconciler) sourcesReferencing(kind string) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []ctrl.Request {
+		return r.sourcesMatching(ctx, func(ref pwv1alpha1.MembershipSourceReference) bool {
+			return ref.Kind == kind && ref.Name == obj.GetName() && ref.Namespace == obj.GetNamespace()
+		})
+	}
+}
+
+// allSources returns reconcile requests for all ProjectMembershipSources.
+// This is required for members to be synced to projects and workspaces as soon as they are created.
+func (r *ProjectMembershipSourceReconciler) allSources(ctx context.Context, _ client.Object) []ctrl.Request {
+	return r.sourcesMatching(ctx, func(pwv1alpha1.MembershipSourceReference) bool { return true })
+}
+
+func (r *ProjectMembershipSourceReconciler) sourcesMatching(ctx context.Context, matches func(pwv1alpha1.MembershipSourceReference) bool) []ctrl.Request {
+	sources := &pwv1alpha1.ProjectMembershipSourceList{}
+	if err := r.OnboardingStatic.Client().List(ctx, sources); err != nil {
+		logging.FromContextOrDiscard(ctx).Error(err, "failed to list membership sources")
+		return nil
+	}
+
+	requests := []ctrl.Request{}
+	for _, source := range sources.Items {
+		if matches(source.Spec.SourceRef) {
+			requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&source)})
+		}
+	}
+	return requests
+}
+
+// createdPredicate filters for the creation of objects.
+var createdPredicate = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return true },
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+	UpdateFunc:  func(event.UpdateEvent) bool { return false },
+}
+
+func (r *ProjectMembershipSourceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(MembershipSourceControllerName).
+		WithLogConstructor(logconfig.LogConstructor(mgr.GetLogger(), MembershipSourceControllerName)).
+		For(&pwv1alpha1.ProjectMembershipSource{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// only labeled ConfigMaps and Secrets are cached, see CacheByObject
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.sourcesReferencing(pwv1alpha1.MembershipSourceKindConfigMap))).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.sourcesReferencing(pwv1alpha1.MembershipSourceKindSecret))).
+		Watches(&pwv1alpha1.Project{}, handler.EnqueueRequestsFromMapFunc(r.allSources), builder.WithPredicates(createdPredicate)).
+		Watches(&pwv1alpha1.Workspace{}, handler.EnqueueRequestsFromMapFunc(r.allSources), builder.WithPredicates(createdPredicate)).
+		Complete(metrics.ObserveReconciler(MembershipSourceControllerName, r))
+}
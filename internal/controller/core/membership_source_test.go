@@ -0,0 +1,222 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+const membershipsYAML = `projects:
+- name: alpha
+  members:
+  - kind: Group
+    name: alpha-admins
+    roles: [admin]
+  - kind: User
+    name: jane@example.com
+    roles: [view]
+  workspaces:
+  - name: dev
+    members:
+    - kind: Group
+      name: alpha-devs
+      roles: [admin]
+- name: missing
+`
+
+func Test_ProjectMembershipSourceReconciler(t *testing.T) {
+	jane := pwv1alpha1.Subject{Kind: "User", Name: "jane@example.com"}
+	john := pwv1alpha1.Subject{Kind: "User", Name: "john@example.com"}
+	alphaAdmins := pwv1alpha1.Subject{Kind: "Group", Name: "alpha-admins"}
+	alphaDevs := pwv1alpha1.Subject{Kind: "Group", Name: "alpha-devs"}
+
+	alpha := &pwv1alpha1.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: "alpha"},
+		Spec: pwv1alpha1.ProjectSpec{Members: []pwv1alpha1.ProjectMember{
+			{Subject: john, Roles: []pwv1alpha1.ProjectMemberRole{pwv1alpha1.ProjectRoleAdmin}},
+			{Subject: jane, Roles: []pwv1alpha1.ProjectMemberRole{pwv1alpha1.ProjectRoleAdmin}},
+		}},
+	}
+	dev := &pwv1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "dev", Namespace: utils.NamespaceForProject(alpha)}}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "members", Namespace: "gitops"},
+		Data:       map[string]string{pwv1alpha1.DefaultMembershipSourceKey: membershipsYAML},
+	}
+	source := &pwv1alpha1.ProjectMembershipSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "gitops", Generation: 1},
+		Spec: pwv1alpha1.ProjectMembershipSourceSpec{SourceRef: pwv1alpha1.MembershipSourceReference{
+			Kind: pwv1alpha1.MembershipSourceKindConfigMap, Name: cm.Name, Namespace: cm.Namespace,
+		}},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(Scheme).
+		WithObjects(alpha, dev, cm, source).
+		WithStatusSubresource(source).
+		Build()
+	r, err := NewProjectMembershipSourceReconciler(NewCommonReconciler(sharedconfig.NewFakeSharedInformation(c, nil, nil, nil), "test"))
+	require.NoError(t, err)
+
+	reconcileSource := func(t *testing.T) *pwv1alpha1.ProjectMembershipSource {
+		t.Helper()
+		_, err := r.Reconcile(newContext(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(source)})
+		require.NoError(t, err)
+		res := &pwv1alpha1.ProjectMembershipSource{}
+		require.NoError(t, c.Get(newContext(), client.ObjectKeyFromObject(source), res))
+		return res
+	}
+	getProject := func(t *testing.T) *pwv1alpha1.Project {
+		t.Helper()
+		res := &pwv1alpha1.Project{}
+		require.NoError(t, c.Get(newContext(), client.ObjectKeyFromObject(alpha), res))
+		return res
+	}
+	updateContent := func(t *testing.T, content string) {
+		t.Helper()
+		res := &corev1.ConfigMap{}
+		require.NoError(t, c.Get(newContext(), client.ObjectKeyFromObject(cm), res))
+		res.Data[pwv1alpha1.DefaultMembershipSourceKey] = content
+		require.NoError(t, c.Update(newContext(), res))
+	}
+
+	t.Run("should merge the members into projects and workspaces", func(t *testing.T) {
+		res := reconcileSource(t)
+		assert.Contains(t, res.Finalizers, deleteFinalizer)
+		assert.Equal(t, []string{"alpha"}, res.Status.Projects)
+		assert.Equal(t, []string{"alpha/dev"}, res.Status.Workspaces)
+		synced := res.GetCondition(pwv1alpha1.ConditionTypeSynced)
+		require.NotNil(t, synced)
+		assert.Equal(t, pwv1alpha1.ConditionStatusFalse, synced.Status)
+		assert.Equal(t, pwv1alpha1.ConditionReasonTargetsSkipped, synced.Reason)
+		assert.Equal(t, "project missing does not exist", synced.Message)
+
+		project := getProject(t)
+		assert.Equal(t, []pwv1alpha1.ProjectMember{
+			{Subject: john, Roles: []pwv1alpha1.ProjectMemberRole{pwv1alpha1.ProjectRoleAdmin}},
+			{Subject: jane, Roles: []pwv1alpha1.ProjectMemberRole{pwv1alpha1.ProjectRoleView}},
+			{Subject: alphaAdmins, Roles: []pwv1alpha1.ProjectMemberRole{pwv1alpha1.ProjectRoleAdmin}},
+		}, project.Spec.Members)
+		sourceName, managed, err := utils.ManagedMembers(project)
+		require.NoError(t, err)
+		assert.Equal(t, "gitops", sourceName)
+		assert.Equal(t, []pwv1alpha1.Subject{alphaAdmins, jane}, managed)
+
+		workspace := &pwv1alpha1.Workspace{}
+		require.NoError(t, c.Get(newContext(), client.ObjectKeyFromObject(dev), workspace))
+		assert.Equal(t, []pwv1alpha1.WorkspaceMember{
+			{Subject: alphaDevs, Roles: []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleAdmin}},
+		}, workspace.Spec.Members)
+	})
+
+	t.Run("should remove members which have been removed from the source", func(t *testing.T) {
+		updateContent(t, `projects:
+- name: alpha
+  members:
+  - kind: Group
+    name: alpha-admins
+    roles: [admin, view]
+`)
+		res := reconcileSource(t)
+		assert.Equal(t, []string{"alpha"}, res.Status.Projects)
+		assert.Empty(t, res.Status.Workspaces)
+		assert.Equal(t, pwv1alpha1.ConditionStatusTrue, res.GetCondition(pwv1alpha1.ConditionTypeSynced).Status)
+
+		assert.Equal(t, []pwv1alpha1.ProjectMember{
+			{Subject: john, Roles: []pwv1alpha1.ProjectMemberRole{pwv1alpha1.ProjectRoleAdmin}},
+			{Subject: alphaAdmins, Roles: []pwv1alpha1.ProjectMemberRole{pwv1alpha1.ProjectRoleAdmin, pwv1alpha1.ProjectRoleView}},
+		}, getProject(t).Spec.Members)
+
+		// the workspace is not part of the source anymore, its members are kept but not managed anymore
+		workspace := &pwv1alpha1.Workspace{}
+		require.NoError(t, c.Get(newContext(), client.ObjectKeyFromObject(dev), workspace))
+		assert.Len(t, workspace.Spec.Members, 1)
+		assert.NotContains(t, workspace.Labels, pwv1alpha1.MembershipSourceLabel)
+		assert.NotContains(t, workspace.Annotations, pwv1alpha1.ManagedMembersAnnotation)
+	})
+
+	t.Run("should keep the members if the source is invalid", func(t *testing.T) {
+		updateContent(t, `projects:
+- name: alpha
+  members:
+  - kind: Group
+    name: alpha-admins
+    roles: [owner]
+`)
+		res := reconcileSource(t)
+		synced := res.GetCondition(pwv1alpha1.ConditionTypeSynced)
+		assert.Equal(t, pwv1alpha1.ConditionReasonSourceInvalid, synced.Reason)
+		assert.Contains(t, synced.Message, "unknown project role 'owner'")
+		assert.Equal(t, []string{"alpha"}, res.Status.Projects)
+		assert.Len(t, getProject(t).Spec.Members, 2)
+	})
+
+	t.Run("should skip projects which are synced from another source", func(t *testing.T) {
+		other := &pwv1alpha1.ProjectMembershipSource{
+			ObjectMeta: metav1.ObjectMeta{Name: "other"},
+			Spec:       source.Spec,
+		}
+		require.NoError(t, c.Create(newContext(), other))
+		_, err := r.Reconcile(newContext(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(other)})
+		require.NoError(t, err)
+
+		require.NoError(t, c.Get(newContext(), client.ObjectKeyFromObject(other), other))
+		synced := other.GetCondition(pwv1alpha1.ConditionTypeSynced)
+		assert.Equal(t, pwv1alpha1.ConditionReasonSourceInvalid, synced.Reason)
+
+		updateContent(t, "projects:\n- name: alpha\n")
+		_, err = r.Reconcile(newContext(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(other)})
+		require.NoError(t, err)
+		require.NoError(t, c.Get(newContext(), client.ObjectKeyFromObject(other), other))
+		synced = other.GetCondition(pwv1alpha1.ConditionTypeSynced)
+		assert.Equal(t, pwv1alpha1.ConditionReasonTargetsSkipped, synced.Reason)
+		assert.Equal(t, "project alpha is already synced from ProjectMembershipSource gitops", synced.Message)
+		assert.Empty(t, other.Status.Projects)
+	})
+
+	t.Run("should enqueue the sources which reference a ConfigMap", func(t *testing.T) {
+		requests := r.sourcesReferencing(pwv1alpha1.MembershipSourceKindConfigMap)(newContext(), cm)
+		assert.ElementsMatch(t, []reconcile.Request{
+			{NamespacedName: client.ObjectKey{Name: "gitops"}},
+			{NamespacedName: client.ObjectKey{Name: "other"}},
+		}, requests)
+		assert.Empty(t, r.sourcesReferencing(pwv1alpha1.MembershipSourceKindSecret)(newContext(), &corev1.Secret{ObjectMeta: cm.ObjectMeta}))
+	})
+
+	t.Run("should release the members when the source is deleted", func(t *testing.T) {
+		require.NoError(t, c.Delete(newContext(), source))
+		_, err := r.Reconcile(newContext(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(source)})
+		require.NoError(t, err)
+
+		project := getProject(t)
+		assert.Len(t, project.Spec.Members, 2)
+		assert.NotContains(t, project.Labels, pwv1alpha1.MembershipSourceLabel)
+		assert.NotContains(t, project.Annotations, pwv1alpha1.ManagedMembersAnnotation)
+	})
+}
+
+func Test_mergeManagedMembers(t *testing.T) {
+	member := func(name string, roles ...pwv1alpha1.ProjectMemberRole) pwv1alpha1.ProjectMember {
+		return pwv1alpha1.ProjectMember{Subject: pwv1alpha1.Subject{Kind: "Group", Name: name}, Roles: roles}
+	}
+	subjectOf := func(m pwv1alpha1.ProjectMember) pwv1alpha1.Subject { return m.Subject }
+
+	members := []pwv1alpha1.ProjectMember{member("manual"), member("synced-removed"), member("synced", pwv1alpha1.ProjectRoleView), member("taken-over")}
+	desired := []pwv1alpha1.ProjectMember{member("new"), member("synced", pwv1alpha1.ProjectRoleAdmin), member("taken-over", pwv1alpha1.ProjectRoleAdmin)}
+	previous := []pwv1alpha1.Subject{member("synced-removed").Subject, member("synced").Subject}
+
+	assert.Equal(t, []pwv1alpha1.ProjectMember{
+		member("manual"),
+		member("synced", pwv1alpha1.ProjectRoleAdmin),
+		member("taken-over", pwv1alpha1.ProjectRoleAdmin),
+		member("new"),
+	}, mergeManagedMembers(members, desired, subjectOf, previous))
+}
@@ -0,0 +1,90 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+// Namespaces of projects and workspaces are deleted by name. Since anyone with permissions for namespaces could have replaced or relabeled
+// a namespace in the meantime, e.g. to point a workspace at a namespace with someone else's data, the namespaces are only deleted if they
+// still carry the labels which the platform service has set on them: the management labels, the project and workspace labels,
+// and the owner UID label, if it has been set already.
+
+// namespaceOwnershipMismatches returns a description of each label of the given namespace which does not match the given owner, a Project or Workspace.
+// workspaceName is empty for the namespace of a project, which must not carry a workspace label.
+func (r *CommonReconciler) namespaceOwnershipMismatches(ctx context.Context, ns *corev1.Namespace, owner client.Object, projectName, workspaceName string) ([]string, error) {
+	managed, err := r.isManaged(ctx, ns)
+	if err != nil {
+		return nil, err
+	}
+	var mismatches []string
+	if !managed {
+		mismatches = append(mismatches, "management labels missing")
+	}
+	labels := ns.GetLabels()
+	if labels[utils.LabelProject] != projectName {
+		mismatches = append(mismatches, fmt.Sprintf("label %s is '%s' instead of '%s'", utils.LabelProject, labels[utils.LabelProject], projectName))
+	}
+	if labels[utils.LabelWorkspace] != workspaceName {
+		mismatches = append(mismatches, fmt.Sprintf("label %s is '%s' instead of '%s'", utils.LabelWorkspace, labels[utils.LabelWorkspace], workspaceName))
+	}
+	if uid, ok := labels[utils.LabelOwnerUID]; ok && uid != string(owner.GetUID()) {
+		mismatches = append(mismatches, fmt.Sprintf("label %s is '%s' instead of '%s'", utils.LabelOwnerUID, uid, owner.GetUID()))
+	}
+	return mismatches, nil
+}
+
+// deleteNamespaceIfOwned deletes the namespace with the given name, if its labels still identify it as the namespace of the given owner, a Project or Workspace.
+// If they do not, the namespace is kept, a warning event is recorded on the owner via the given recorder (may be nil) and false is returned.
+// The deletion is preconditioned on the UID and resource version of the verified namespace, so that a namespace which has been changed in between is not deleted.
+// Returns true if the namespace has been deleted or is already in deletion, and a NotFound error if it does not exist.
+func (r *CommonReconciler) deleteNamespaceIfOwned(ctx context.Context, recorder events.EventRecorder, owner client.Object, name, projectName, workspaceName string) (bool, error) {
+	onboardingCluster, err := r.Config.OnboardingClusterStatic(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get onboarding cluster access: %w", err)
+	}
+	c := onboardingCluster.Client()
+	ns := &corev1.Namespace{}
+	if err := c.Get(ctx, client.ObjectKey{Name: name}, ns); err != nil {
+		return false, fmt.Errorf("failed to get namespace %s: %w", name, err)
+	}
+	if !ns.DeletionTimestamp.IsZero() {
+		return true, nil
+	}
+
+	mismatches, err := r.namespaceOwnershipMismatches(ctx, ns, owner, projectName, workspaceName)
+	if err != nil {
+		return false, err
+	}
+	if len(mismatches) > 0 {
+		logging.FromContextOrPanic(ctx).Info("Refusing to delete namespace, because its labels have been altered", "namespace", name, "mismatches", mismatches)
+		if recorder != nil {
+			recorder.Eventf(owner, ns, corev1.EventTypeWarning, pwv1alpha1.EventReasonNamespaceOwnershipMismatch, "DeleteNamespace",
+				"Refusing to delete namespace %s, because its labels do not identify it as created for this %s anymore: %s", name, ownerKind(owner), strings.Join(mismatches, ", "))
+		}
+		return false, nil
+	}
+
+	if err := c.Delete(ctx, ns, client.Preconditions{UID: &ns.UID, ResourceVersion: &ns.ResourceVersion}); err != nil {
+		return false, fmt.Errorf("failed to delete namespace %s: %w", name, err)
+	}
+	return true, nil
+}
+
+// ownerKind returns 'workspace' for Workspaces and 'project' otherwise.
+func ownerKind(owner client.Object) string {
+	if _, ok := owner.(*pwv1alpha1.Workspace); ok {
+		return "workspace"
+	}
+	return "project"
+}
@@ -0,0 +1,170 @@
+package core
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+// ownedNamespaceLabels returns the labels which the platform service sets on the namespace of the given project or workspace.
+func ownedNamespaceLabels(projectName, workspaceName string) map[string]string {
+	labels := utils.ManagementLabels("test", pwv1alpha1.ManagementLabelsConfig{})
+	labels[utils.LabelProject] = projectName
+	if workspaceName != "" {
+		labels[utils.LabelWorkspace] = workspaceName
+	}
+	return labels
+}
+
+func Test_deleteNamespaceIfOwned(t *testing.T) {
+	owner := &pwv1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "dev", Namespace: "project-sample", UID: "ws-uid"}}
+	withLabels := func(changes map[string]string) map[string]string {
+		labels := ownedNamespaceLabels("sample", "dev")
+		labels[utils.LabelOwnerUID] = "ws-uid"
+		maps.Copy(labels, changes)
+		for k, v := range labels {
+			if v == "" {
+				delete(labels, k)
+			}
+		}
+		return labels
+	}
+	testCases := []struct {
+		desc            string
+		namespace       *corev1.Namespace
+		expectedDeleted bool
+		expectNotFound  bool
+		expectEvent     bool
+	}{
+		{
+			desc:            "should delete a namespace with the expected labels",
+			namespace:       &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "project-sample--ws-dev", Labels: withLabels(nil)}},
+			expectedDeleted: true,
+		},
+		{
+			desc:            "should delete a namespace without owner UID label",
+			namespace:       &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "project-sample--ws-dev", Labels: withLabels(map[string]string{utils.LabelOwnerUID: ""})}},
+			expectedDeleted: true,
+		},
+		{
+			desc: "should accept a namespace which is already terminating",
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:              "project-sample--ws-dev",
+				DeletionTimestamp: ptr.To(metav1.Now()),
+				Finalizers:        []string{"kubernetes"},
+			}},
+			expectedDeleted: true,
+		},
+		{
+			desc:           "should return NotFound for a missing namespace",
+			expectNotFound: true,
+		},
+		{
+			desc:        "should keep a namespace without management labels",
+			namespace:   &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "project-sample--ws-dev", Labels: map[string]string{utils.LabelProject: "sample", utils.LabelWorkspace: "dev"}}},
+			expectEvent: true,
+		},
+		{
+			desc:        "should keep a namespace labeled for another workspace",
+			namespace:   &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "project-sample--ws-dev", Labels: withLabels(map[string]string{utils.LabelWorkspace: "prod"})}},
+			expectEvent: true,
+		},
+		{
+			desc:        "should keep a namespace labeled for another project",
+			namespace:   &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "project-sample--ws-dev", Labels: withLabels(map[string]string{utils.LabelProject: "other"})}},
+			expectEvent: true,
+		},
+		{
+			desc:        "should keep a namespace created for another owner with the same name",
+			namespace:   &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "project-sample--ws-dev", Labels: withLabels(map[string]string{utils.LabelOwnerUID: "other-uid"})}},
+			expectEvent: true,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(Scheme)
+			if tC.namespace != nil {
+				builder = builder.WithObjects(tC.namespace)
+			}
+			c := builder.Build()
+			ctx := newContext()
+			r := NewCommonReconciler(sharedconfig.NewFakeSharedInformation(c, nil, nil, nil), "test")
+			recorder := events.NewFakeRecorder(10)
+
+			deleted, err := r.deleteNamespaceIfOwned(ctx, recorder, owner, "project-sample--ws-dev", "sample", "dev")
+			if tC.expectNotFound {
+				assert.True(t, apierrors.IsNotFound(err), "expected NotFound, got: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tC.expectedDeleted, deleted)
+
+			ns := &corev1.Namespace{}
+			err = c.Get(ctx, client.ObjectKeyFromObject(tC.namespace), ns)
+			if tC.expectedDeleted && tC.namespace.DeletionTimestamp.IsZero() {
+				assert.True(t, apierrors.IsNotFound(err))
+			} else {
+				assert.NoError(t, err)
+			}
+
+			close(recorder.Events)
+			recorded := []string{}
+			for e := range recorder.Events {
+				recorded = append(recorded, e)
+			}
+			if tC.expectEvent {
+				require.Len(t, recorded, 1)
+				assert.Contains(t, recorded[0], pwv1alpha1.EventReasonNamespaceOwnershipMismatch)
+			} else {
+				assert.Empty(t, recorded)
+			}
+		})
+	}
+}
+
+func Test_ProjectReconciler_keepsNamespaceWithAlteredLabels(t *testing.T) {
+	project := sampleProjectDeleted.DeepCopy()
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   project.Status.Namespace,
+		Labels: map[string]string{utils.LabelProject: "other"},
+	}}
+	c := fake.NewClientBuilder().
+		WithObjects(project, namespace).
+		WithStatusSubresource(project).
+		WithScheme(Scheme).
+		Build()
+	ctx := newContext()
+
+	pr, err := NewProjectReconciler(c.Scheme(), NewCommonReconciler(sharedconfig.NewFakeSharedInformation(c, nil, nil, nil), "test"))
+	require.NoError(t, err)
+	recorder := events.NewFakeRecorder(10)
+	pr.Recorder = recorder
+
+	_, _ = pr.Reconcile(ctx, newRequest(project))
+
+	p := &pwv1alpha1.Project{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(project), p))
+	assert.Contains(t, p.Finalizers, deleteFinalizer)
+	assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(namespace), &corev1.Namespace{}))
+
+	close(recorder.Events)
+	recorded := []string{}
+	for e := range recorder.Events {
+		recorded = append(recorded, e)
+	}
+	require.NotEmpty(t, recorded)
+	assert.Contains(t, recorded[len(recorded)-1], pwv1alpha1.EventReasonNamespaceOwnershipMismatch)
+}
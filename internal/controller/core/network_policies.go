@@ -0,0 +1,110 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/api/v2/metadata"
+	pwoerrors "github.com/openmcp-project/platform-service-project-workspace/internal/errors"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+
+// reconcileNetworkPolicies creates or updates the NetworkPolicies from the config in the workspace namespace.
+// Managed NetworkPolicies which are not part of the config anymore are deleted, NetworkPolicies which are not managed by the platform service are left untouched.
+func (r *WorkspaceReconciler) reconcileNetworkPolicies(ctx context.Context, project *pwv1alpha1.Project, ws *pwv1alpha1.Workspace) error {
+	log := logging.FromContextOrPanic(ctx)
+
+	templates, err := r.Config.WorkspaceNetworkPolicies(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get NetworkPolicies from config: %w", err)
+	}
+	values := pwv1alpha1.NetworkPolicyTemplateValues{
+		Project:          project.Name,
+		ProjectNamespace: ws.Namespace,
+		Workspace:        ws.Name,
+		Namespace:        ws.Status.Namespace,
+	}
+
+	desired := map[string]bool{}
+	for _, tmpl := range templates {
+		spec, err := tmpl.Render(values)
+		if err != nil {
+			// the template is part of the config, retrying does not help until the config is fixed
+			return pwoerrors.NewTerminalError(fmt.Errorf("failed to render NetworkPolicy '%s': %w", tmpl.Name, err))
+		}
+		desired[tmpl.Name] = true
+
+		np := &networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      tmpl.Name,
+				Namespace: ws.Status.Namespace,
+			},
+		}
+		result, err := controllerutil.CreateOrUpdate(ctx, r.OnboardingStatic.Client(), np, func() error {
+			metadata.EnsureLabels(np, tmpl.Labels)
+			// the workspace label restricts the cache of the manager to the NetworkPolicies of workspaces
+			utils.SetWorkspaceLabel(np, ws.Name)
+			utils.SetProjectLabel(np, project.Name)
+			if err := r.applyManagementLabel(ctx, np); err != nil {
+				return err
+			}
+			np.Spec = *spec
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create or update NetworkPolicy '%s': %w", tmpl.Name, err)
+		}
+		utils.LogOperationResult(log, logging.INFO, np, result)
+	}
+
+	existing := &networkingv1.NetworkPolicyList{}
+	if err := r.OnboardingStatic.Client().List(ctx, existing, client.InNamespace(ws.Status.Namespace)); err != nil {
+		return fmt.Errorf("failed to list NetworkPolicies: %w", err)
+	}
+	for i := range existing.Items {
+		np := &existing.Items[i]
+		if desired[np.Name] {
+			continue
+		}
+		managed, err := r.isManaged(ctx, np)
+		if err != nil {
+			return err
+		}
+		if !managed {
+			continue
+		}
+		if err := r.OnboardingStatic.Client().Delete(ctx, np); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete NetworkPolicy '%s': %w", np.Name, err)
+		}
+		log.Info("Deleted NetworkPolicy which is not part of the config anymore", "networkPolicy", np.Name)
+	}
+
+	return nil
+}
+
+// workspaceOfNetworkPolicy returns a reconcile request for the workspace owning the namespace of the given NetworkPolicy, if it is managed by the platform service.
+// This is required to revert manual changes to the NetworkPolicies from the config.
+func (r *WorkspaceReconciler) workspaceOfNetworkPolicy(ctx context.Context, obj client.Object) []ctrl.Request {
+	managed, err := r.isManaged(ctx, obj)
+	if err != nil || !managed {
+		return nil
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := r.OnboardingStatic.Client().Get(ctx, client.ObjectKey{Name: obj.GetNamespace()}, namespace); err != nil {
+		return nil
+	}
+	return r.workspaceOfNamespace(ctx, namespace)
+}
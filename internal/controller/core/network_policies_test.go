@@ -0,0 +1,93 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+func Test_WorkspaceReconciler_reconcileNetworkPolicies(t *testing.T) {
+	ws := sampleWorkspace.DeepCopy()
+	ws.Status.Namespace = utils.NamespaceForWorkspace(ws)
+	wsNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: ws.Status.Namespace,
+			Labels: map[string]string{
+				utils.LabelProject:   sampleProject.Name,
+				utils.LabelWorkspace: ws.Name,
+			},
+		},
+	}
+	managedLabels := utils.ManagementLabels("test", pwv1alpha1.ManagementLabelsConfig{})
+	drifted := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "default-deny", Namespace: ws.Status.Namespace, Labels: managedLabels},
+		Spec: networkingv1.NetworkPolicySpec{
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		},
+	}
+	stale := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "removed-from-config", Namespace: ws.Status.Namespace, Labels: managedLabels},
+	}
+	unmanaged := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "custom", Namespace: ws.Status.Namespace},
+	}
+
+	c := fake.NewClientBuilder().
+		WithObjects(projectNamespace, wsNamespace, drifted, stale, unmanaged).
+		WithScheme(Scheme).
+		Build()
+	ctx := newContext()
+
+	cfg := sharedconfig.NewFakeSharedInformation(c, nil, nil, nil)
+	cfg.WorkspaceNetworkPoliciesData = []pwv1alpha1.NetworkPolicyTemplate{
+		{
+			Name: "default-deny",
+			Spec: runtime.RawExtension{Raw: []byte(`{"podSelector":{},"policyTypes":["Ingress","Egress"]}`)},
+		},
+		{
+			Name:   "allow-from-project",
+			Labels: map[string]string{"example.com/baseline": "true"},
+			Spec:   runtime.RawExtension{Raw: []byte(`{"podSelector":{},"ingress":[{"from":[{"namespaceSelector":{"matchLabels":{"kubernetes.io/metadata.name":"{{ .ProjectNamespace }}"}}}]}]}`)},
+		},
+	}
+	wr, err := NewWorkspaceReconciler(c.Scheme(), NewCommonReconciler(cfg, "test"))
+	require.NoError(t, err)
+
+	require.NoError(t, wr.reconcileNetworkPolicies(ctx, sampleProject, ws))
+
+	np := &networkingv1.NetworkPolicy{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(drifted), np))
+	assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress}, np.Spec.PolicyTypes, "manual changes must be reverted")
+
+	np = &networkingv1.NetworkPolicy{}
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Name: "allow-from-project", Namespace: ws.Status.Namespace}, np))
+	assert.Equal(t, "true", np.Labels["example.com/baseline"])
+	assert.True(t, utils.IsManaged(np, "test", pwv1alpha1.ManagementLabelsConfig{}))
+	assert.Equal(t, ws.Name, np.Labels[utils.LabelWorkspace], "the workspace label is required for the NetworkPolicy to be cached")
+	assert.Equal(t, sampleProject.Name, np.Labels[utils.LabelProject])
+	require.Len(t, np.Spec.Ingress, 1)
+	require.Len(t, np.Spec.Ingress[0].From, 1)
+	assert.Equal(t, map[string]string{"kubernetes.io/metadata.name": projectNamespace.Name}, np.Spec.Ingress[0].From[0].NamespaceSelector.MatchLabels)
+
+	err = c.Get(ctx, client.ObjectKeyFromObject(stale), &networkingv1.NetworkPolicy{})
+	assert.True(t, apierrors.IsNotFound(err), "managed NetworkPolicies which are not part of the config must be deleted")
+	assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(unmanaged), &networkingv1.NetworkPolicy{}), "unmanaged NetworkPolicies must not be deleted")
+
+	t.Run("maps managed NetworkPolicies to their workspace", func(t *testing.T) {
+		assert.Equal(t, []reconcile.Request{newRequest(ws)}, wr.workspaceOfNetworkPolicy(ctx, drifted))
+		assert.Empty(t, wr.workspaceOfNetworkPolicy(ctx, unmanaged))
+	})
+}
@@ -0,0 +1,205 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+// Resources which are created for a Project or Workspace carry the UID of their owner in the utils.LabelOwnerUID label.
+// Owner references cannot be used for all of them, because Workspaces are namespaced and cross-namespace owner references
+// (including references from cluster-scoped resources) are not allowed. The label allows to clean up these resources
+// generically, both when their owner is deleted and, by the OwnershipSweeper, if this has been missed.
+
+const (
+	OwnershipSweeperName = "ownership-sweeper"
+
+	// DefaultOwnershipSweepInterval is the default interval in which the OwnershipSweeper deletes orphaned resources.
+	DefaultOwnershipSweepInterval = 1 * time.Hour
+)
+
+// ownedResourceLists returns empty lists of all resource types which are labeled with the UID of their owner.
+// New resource types which are created for Projects or Workspaces have to be added here to be cleaned up.
+// Bindings come before the roles, so that no binding refers to a missing role.
+func ownedResourceLists() []client.ObjectList {
+	return []client.ObjectList{
+		&rbacv1.ClusterRoleBindingList{},
+		&rbacv1.ClusterRoleList{},
+		&rbacv1.RoleBindingList{},
+		&rbacv1.RoleList{},
+	}
+}
+
+// deleteOwnedResources deletes all managed resources which are labeled with the UID of the given owner.
+// The given legacy objects are deleted by name, if they are managed. This covers resources which have been created
+// before they were labeled with the owner UID.
+func (r *CommonReconciler) deleteOwnedResources(ctx context.Context, c client.Client, owner client.Object, legacy ...client.Object) error {
+	log := logging.FromContextOrPanic(ctx)
+
+	if owner.GetUID() != "" {
+		owned, err := listOwnedResources(ctx, c, client.MatchingLabels{utils.LabelOwnerUID: string(owner.GetUID())})
+		if err != nil {
+			return err
+		}
+		if _, err := r.deleteManagedResources(ctx, c, owned); err != nil {
+			return err
+		}
+	}
+	for _, obj := range legacy {
+		deleted, err := r.deleteIfManaged(ctx, c, obj)
+		if err != nil {
+			return fmt.Errorf("failed to delete %T '%s': %w", obj, client.ObjectKeyFromObject(obj).String(), err)
+		}
+		if deleted {
+			log.Debug("Deleted resource", "kind", fmt.Sprintf("%T", obj), "name", obj.GetName(), "namespace", obj.GetNamespace())
+		}
+	}
+	return nil
+}
+
+// listOwnedResources lists the resources of all owned resource types which match the given selector.
+func listOwnedResources(ctx context.Context, c client.Client, selector client.ListOption) ([]client.Object, error) {
+	res := []client.Object{}
+	for _, list := range ownedResourceLists() {
+		if err := c.List(ctx, list, selector); err != nil {
+			return nil, fmt.Errorf("failed to list %T: %w", list, err)
+		}
+		objs, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract items of %T: %w", list, err)
+		}
+		for _, o := range objs {
+			if obj, ok := o.(client.Object); ok {
+				res = append(res, obj)
+			}
+		}
+	}
+	return res, nil
+}
+
+// deleteManagedResources deletes the given resources, skipping those which are not managed by the platform service.
+// Returns the number of deleted resources.
+func (r *CommonReconciler) deleteManagedResources(ctx context.Context, c client.Client, objs []client.Object) (int, error) {
+	log := logging.FromContextOrPanic(ctx)
+
+	count := 0
+	for _, obj := range objs {
+		managed, err := r.isManaged(ctx, obj)
+		if err != nil {
+			return count, err
+		}
+		if !managed {
+			continue
+		}
+		if err := c.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			return count, fmt.Errorf("failed to delete %T '%s': %w", obj, client.ObjectKeyFromObject(obj).String(), err)
+		}
+		log.Debug("Deleted resource", "kind", fmt.Sprintf("%T", obj), "name", obj.GetName(), "namespace", obj.GetNamespace(), "ownerUID", obj.GetLabels()[utils.LabelOwnerUID])
+		count++
+	}
+	return count, nil
+}
+
+// OwnershipSweeper periodically deletes managed resources which are labeled with the UID of a Project or Workspace that does not exist anymore.
+// Such orphans remain if the cleanup during the deletion of their owner has been skipped, e.g. because its finalizer has been removed manually.
+type OwnershipSweeper struct {
+	*CommonReconciler
+	log logging.Logger
+
+	// Interval is the interval in which orphaned resources are deleted.
+	Interval time.Duration
+}
+
+// NewOwnershipSweeper creates a new OwnershipSweeper.
+func NewOwnershipSweeper(commonReconciler *CommonReconciler) *OwnershipSweeper {
+	return &OwnershipSweeper{
+		CommonReconciler: commonReconciler,
+		log:              logging.Discard(),
+		Interval:         DefaultOwnershipSweepInterval,
+	}
+}
+
+// SetupWithManager adds the sweeper to the manager.
+// Since it is added as a runnable which requires leader election, orphans are only deleted by the leading replica.
+func (s *OwnershipSweeper) SetupWithManager(mgr ctrl.Manager) error {
+	s.log = logging.Wrap(mgr.GetLogger()).WithName(OwnershipSweeperName)
+	return mgr.Add(s)
+}
+
+var _ manager.Runnable = &OwnershipSweeper{}
+
+// Start deletes orphaned resources in the configured interval until the context is cancelled.
+// The first sweep happens after one interval, so that the owners created during the startup are known.
+func (s *OwnershipSweeper) Start(ctx context.Context) error {
+	ctx = logging.NewContext(ctx, s.log)
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if err := s.sweep(ctx); err != nil {
+			s.log.Error(err, "Failed to delete orphaned resources")
+		}
+	}
+}
+
+// sweep deletes all managed resources whose owner UID does not belong to an existing Project or Workspace.
+// The owners are listed after the resources, so that resources of owners which have been created in between are not deleted.
+func (s *OwnershipSweeper) sweep(ctx context.Context) error {
+	log := logging.FromContextOrPanic(ctx)
+
+	onboardingCluster, err := s.Config.OnboardingClusterStatic(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get onboarding cluster access: %w", err)
+	}
+	c := onboardingCluster.Client()
+
+	owned, err := listOwnedResources(ctx, c, client.HasLabels{utils.LabelOwnerUID})
+	if err != nil {
+		return err
+	}
+	if len(owned) == 0 {
+		return nil
+	}
+
+	owners := sets.New[string]()
+	projects := &pwv1alpha1.ProjectList{}
+	if err := c.List(ctx, projects); err != nil {
+		return fmt.Errorf("failed to list projects: %w", err)
+	}
+	for _, p := range projects.Items {
+		owners.Insert(string(p.UID))
+	}
+	workspaces := &pwv1alpha1.WorkspaceList{}
+	if err := c.List(ctx, workspaces); err != nil {
+		return fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	for _, ws := range workspaces.Items {
+		owners.Insert(string(ws.UID))
+	}
+
+	orphans := slices.DeleteFunc(owned, func(obj client.Object) bool {
+		return owners.Has(obj.GetLabels()[utils.LabelOwnerUID])
+	})
+	count, err := s.deleteManagedResources(ctx, c, orphans)
+	if count > 0 {
+		log.Info("Deleted orphaned resources", "count", count)
+	}
+	return err
+}
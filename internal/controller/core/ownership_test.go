@@ -0,0 +1,92 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+// ownedClusterRole returns a ClusterRole which is labeled with the given owner UID and, if managed is true, managed by the 'test' provider.
+func ownedClusterRole(name string, ownerUID types.UID, managed bool) *rbacv1.ClusterRole {
+	cr := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	if managed {
+		utils.SetManagementLabels(cr, "test")
+	}
+	utils.SetOwnerUIDLabel(cr, &metav1.ObjectMeta{UID: ownerUID})
+	return cr
+}
+
+func Test_deleteOwnedResources(t *testing.T) {
+	owner := &pwv1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "project-test", UID: "owner-uid"}}
+	ownedBinding := &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "owned", Namespace: "project-test"}}
+	utils.SetManagementLabels(ownedBinding, "test")
+	utils.SetOwnerUIDLabel(ownedBinding, owner)
+	legacy := &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "legacy"}}
+	utils.SetManagementLabels(legacy, "test")
+
+	c := fake.NewClientBuilder().WithScheme(Scheme).WithObjects(
+		ownedClusterRole("owned", owner.UID, true),
+		ownedClusterRole("unmanaged", owner.UID, false),
+		ownedClusterRole("other-owner", "other-uid", true),
+		ownedBinding,
+		legacy,
+	).Build()
+	r := NewCommonReconciler(sharedconfig.NewFakeSharedInformation(c, nil, nil, nil), "test")
+
+	require.NoError(t, r.deleteOwnedResources(newContext(), c, owner, &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "legacy"}}))
+
+	testCases := []struct {
+		desc   string
+		obj    client.Object
+		exists bool
+	}{
+		{desc: "should delete managed ClusterRoles of the owner", obj: &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "owned"}}, exists: false},
+		{desc: "should delete managed RoleBindings of the owner", obj: &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "owned", Namespace: "project-test"}}, exists: false},
+		{desc: "should delete managed legacy resources by name", obj: &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "legacy"}}, exists: false},
+		{desc: "should keep resources which are not managed", obj: &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "unmanaged"}}, exists: true},
+		{desc: "should keep resources of other owners", obj: &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "other-owner"}}, exists: true},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			err := c.Get(newContext(), client.ObjectKeyFromObject(tC.obj), tC.obj)
+			assert.Equal(t, tC.exists, err == nil, "unexpected result of get: %v", err)
+		})
+	}
+}
+
+func Test_OwnershipSweeper_sweep(t *testing.T) {
+	project := sampleProject.DeepCopy()
+	project.UID = "project-uid"
+	ws := sampleWorkspace.DeepCopy()
+	ws.UID = "workspace-uid"
+
+	c := fake.NewClientBuilder().WithScheme(Scheme).WithObjects(
+		project,
+		ws,
+		ownedClusterRole("of-project", project.UID, true),
+		ownedClusterRole("of-workspace", ws.UID, true),
+		ownedClusterRole("orphaned", "deleted-uid", true),
+		ownedClusterRole("orphaned-unmanaged", "deleted-uid", false),
+	).Build()
+	s := NewOwnershipSweeper(NewCommonReconciler(sharedconfig.NewFakeSharedInformation(c, nil, nil, nil), "test"))
+
+	require.NoError(t, s.sweep(newContext()))
+
+	remaining := &rbacv1.ClusterRoleList{}
+	require.NoError(t, c.List(newContext(), remaining))
+	names := []string{}
+	for _, cr := range remaining.Items {
+		names = append(names, cr.Name)
+	}
+	assert.ElementsMatch(t, []string{"of-project", "of-workspace", "orphaned-unmanaged"}, names)
+}
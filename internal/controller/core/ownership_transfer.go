@@ -0,0 +1,47 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+)
+
+// transferOwnership replaces the creator of the given Project with the user requested via the TransferOwnershipAnnotation and removes the annotation.
+// The webhook only accepts admins of the project as new owner, but members may have changed since, so the request is dropped
+// with a warning event if the new owner is no admin anymore.
+func (r *ProjectReconciler) transferOwnership(ctx context.Context, project *pwv1alpha1.Project) error {
+	newOwner, requested := project.GetAnnotations()[pwv1alpha1.TransferOwnershipAnnotation]
+	if !requested {
+		return nil
+	}
+	log := logging.FromContextOrPanic(ctx)
+
+	old := project.DeepCopy()
+	previousOwner := project.Annotations[pwv1alpha1.CreatedByAnnotation]
+	delete(project.Annotations, pwv1alpha1.TransferOwnershipAnnotation)
+	transfer := newOwner != "" && project.UserInfoHasRole(authenticationv1.UserInfo{Username: newOwner}, pwv1alpha1.ProjectRoleAdmin)
+	if transfer {
+		project.Annotations[pwv1alpha1.CreatedByAnnotation] = newOwner
+	}
+	if err := r.OnboardingStatic.Client().Patch(ctx, project, client.MergeFrom(old)); err != nil {
+		return fmt.Errorf("failed to transfer ownership of project: %w", err)
+	}
+
+	if !transfer {
+		log.Info("Dropping ownership transfer, the new owner is no admin of the project", "newOwner", newOwner)
+		r.recordEvent(project, corev1.EventTypeWarning, pwv1alpha1.EventReasonOwnershipTransferFailed, "TransferOwnership",
+			fmt.Sprintf("Ownership cannot be transferred to '%s', who is no admin of the project", newOwner))
+		return nil
+	}
+	log.Info("Transferred ownership of project", "previousOwner", previousOwner, "newOwner", newOwner)
+	r.recordEvent(project, corev1.EventTypeNormal, pwv1alpha1.EventReasonOwnershipTransferred, "TransferOwnership",
+		fmt.Sprintf("Ownership has been transferred from '%s' to '%s'", previousOwner, newOwner))
+	return nil
+}
@@ -0,0 +1,82 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+)
+
+func Test_ProjectReconciler_transferOwnership(t *testing.T) {
+	testCases := []struct {
+		desc                string
+		annotations         map[string]string
+		expectedAnnotations map[string]string
+		expectedEvent       string
+	}{
+		{
+			desc: "should replace the creator with the new owner",
+			annotations: map[string]string{
+				pwv1alpha1.CreatedByAnnotation:         "former@example.com",
+				pwv1alpha1.TransferOwnershipAnnotation: "user@example.com",
+			},
+			expectedAnnotations: map[string]string{pwv1alpha1.CreatedByAnnotation: "user@example.com"},
+			expectedEvent:       pwv1alpha1.EventReasonOwnershipTransferred,
+		},
+		{
+			desc: "should drop the transfer if the new owner is no admin",
+			annotations: map[string]string{
+				pwv1alpha1.CreatedByAnnotation:         "former@example.com",
+				pwv1alpha1.TransferOwnershipAnnotation: "someone@example.com",
+			},
+			expectedAnnotations: map[string]string{pwv1alpha1.CreatedByAnnotation: "former@example.com"},
+			expectedEvent:       pwv1alpha1.EventReasonOwnershipTransferFailed,
+		},
+		{
+			desc:                "should not touch projects without transfer request",
+			annotations:         map[string]string{pwv1alpha1.CreatedByAnnotation: "former@example.com"},
+			expectedAnnotations: map[string]string{pwv1alpha1.CreatedByAnnotation: "former@example.com"},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			project := sampleProject.DeepCopy()
+			project.SetAnnotations(tC.annotations)
+			c := fake.NewClientBuilder().
+				WithObjects(project).
+				WithScheme(Scheme).
+				Build()
+			ctx := newContext()
+
+			pr, err := NewProjectReconciler(c.Scheme(), NewCommonReconciler(sharedconfig.NewFakeSharedInformation(c, nil, nil, nil), "test"))
+			require.NoError(t, err)
+			recorder := events.NewFakeRecorder(10)
+			pr.Recorder = recorder
+
+			require.NoError(t, pr.transferOwnership(ctx, project))
+
+			actual := &pwv1alpha1.Project{}
+			require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(project), actual))
+			assert.Equal(t, tC.expectedAnnotations, actual.GetAnnotations())
+
+			close(recorder.Events)
+			recorded := []string{}
+			for e := range recorder.Events {
+				recorded = append(recorded, e)
+			}
+			if tC.expectedEvent == "" {
+				assert.Empty(t, recorded)
+				return
+			}
+			if assert.Len(t, recorded, 1) {
+				assert.Contains(t, recorded[0], tC.expectedEvent)
+			}
+		})
+	}
+}
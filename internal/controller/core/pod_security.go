@@ -0,0 +1,97 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/api/v2/metadata"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+// applyPodSecurityLabels sets the Pod Security Admission labels on the given workspace namespace.
+// For each mode, the strictest of the levels from the config, the given project, and the given class (may be nil) is used.
+// The labels of modes without a level are removed. If pod security is not configured, the labels are not touched at all.
+func (r *CommonReconciler) applyPodSecurityLabels(ctx context.Context, project *pwv1alpha1.Project, class *pwv1alpha1.WorkspaceClass, ns *corev1.Namespace) error {
+	cfg, err := r.Config.WorkspacePodSecurity(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get pod security from config: %w", err)
+	}
+	if cfg == nil {
+		return nil
+	}
+	levels := cfg.Stricter(project.Spec.PodSecurity)
+	if class != nil {
+		levels = levels.Stricter(class.Spec.PodSecurity)
+	}
+	for key, value := range cfg.NamespaceLabels(levels) {
+		if value == "" {
+			delete(ns.Labels, key)
+			continue
+		}
+		metadata.SetLabel(ns, key, value)
+	}
+	return nil
+}
+
+// podSecurityLabels returns the Pod Security Admission labels of the given object.
+func podSecurityLabels(obj client.Object) map[string]string {
+	res := map[string]string{}
+	for key, value := range obj.GetLabels() {
+		if strings.HasPrefix(key, pwv1alpha1.PodSecurityLabelPrefix) {
+			res[key] = value
+		}
+	}
+	return res
+}
+
+// podSecurityLabelsChangedPredicate filters for updates of namespaces which change the Pod Security Admission labels.
+var podSecurityLabelsChangedPredicate = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return false },
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return !maps.Equal(podSecurityLabels(e.ObjectOld), podSecurityLabels(e.ObjectNew))
+	},
+}
+
+// podSecurityChangedPredicate filters for updates of Projects which change the pod security levels.
+var podSecurityChangedPredicate = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return false },
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldProject, ok := e.ObjectOld.(*pwv1alpha1.Project)
+		if !ok {
+			return false
+		}
+		newProject, ok := e.ObjectNew.(*pwv1alpha1.Project)
+		if !ok {
+			return false
+		}
+		return ptr.Deref(oldProject.Spec.PodSecurity, pwv1alpha1.PodSecurityLevels{}) != ptr.Deref(newProject.Spec.PodSecurity, pwv1alpha1.PodSecurityLevels{})
+	},
+}
+
+// workspaceOfNamespace returns a reconcile request for the workspace owning the given namespace.
+// This is required to revert manual changes to the Pod Security Admission labels of the workspace namespaces.
+func (r *WorkspaceReconciler) workspaceOfNamespace(_ context.Context, obj client.Object) []ctrl.Request {
+	workspace, project := obj.GetLabels()[utils.LabelWorkspace], obj.GetLabels()[utils.LabelProject]
+	if workspace == "" || project == "" {
+		return nil
+	}
+	return []ctrl.Request{{NamespacedName: client.ObjectKey{
+		Name:      workspace,
+		Namespace: utils.NamespaceForProject(&pwv1alpha1.Project{ObjectMeta: metav1.ObjectMeta{Name: project}}),
+	}}}
+}
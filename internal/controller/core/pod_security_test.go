@@ -0,0 +1,119 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+)
+
+func Test_applyPodSecurityLabels(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		config         *pwv1alpha1.PodSecurityConfig
+		project        *pwv1alpha1.PodSecurityLevels
+		class          *pwv1alpha1.PodSecurityLevels
+		existingLabels map[string]string
+		expectedLabels map[string]string
+	}{
+		{
+			desc:           "should not touch the labels if pod security is not configured",
+			project:        &pwv1alpha1.PodSecurityLevels{Enforce: pwv1alpha1.PodSecurityLevelRestricted},
+			existingLabels: map[string]string{"pod-security.kubernetes.io/enforce": "privileged"},
+			expectedLabels: map[string]string{"pod-security.kubernetes.io/enforce": "privileged"},
+		},
+		{
+			desc: "should set the configured levels",
+			config: &pwv1alpha1.PodSecurityConfig{
+				PodSecurityLevels: pwv1alpha1.PodSecurityLevels{Enforce: pwv1alpha1.PodSecurityLevelBaseline, Warn: pwv1alpha1.PodSecurityLevelRestricted},
+				Version:           "v1.30",
+			},
+			existingLabels: map[string]string{"other": "value"},
+			expectedLabels: map[string]string{
+				"other":                              "value",
+				"pod-security.kubernetes.io/enforce": "baseline",
+				"pod-security.kubernetes.io/enforce-version": "v1.30",
+				"pod-security.kubernetes.io/warn":            "restricted",
+				"pod-security.kubernetes.io/warn-version":    "v1.30",
+			},
+		},
+		{
+			desc: "should apply stricter levels of the project and class, but not weaker ones",
+			config: &pwv1alpha1.PodSecurityConfig{
+				PodSecurityLevels: pwv1alpha1.PodSecurityLevels{Enforce: pwv1alpha1.PodSecurityLevelBaseline, Warn: pwv1alpha1.PodSecurityLevelBaseline},
+			},
+			project: &pwv1alpha1.PodSecurityLevels{Enforce: pwv1alpha1.PodSecurityLevelPrivileged, Warn: pwv1alpha1.PodSecurityLevelRestricted},
+			class:   &pwv1alpha1.PodSecurityLevels{Audit: pwv1alpha1.PodSecurityLevelRestricted},
+			expectedLabels: map[string]string{
+				"pod-security.kubernetes.io/enforce":         "baseline",
+				"pod-security.kubernetes.io/enforce-version": "latest",
+				"pod-security.kubernetes.io/warn":            "restricted",
+				"pod-security.kubernetes.io/warn-version":    "latest",
+				"pod-security.kubernetes.io/audit":           "restricted",
+				"pod-security.kubernetes.io/audit-version":   "latest",
+			},
+		},
+		{
+			desc:   "should revert manual changes and remove labels of modes without a level",
+			config: &pwv1alpha1.PodSecurityConfig{PodSecurityLevels: pwv1alpha1.PodSecurityLevels{Enforce: pwv1alpha1.PodSecurityLevelRestricted}},
+			existingLabels: map[string]string{
+				"pod-security.kubernetes.io/enforce":       "privileged",
+				"pod-security.kubernetes.io/audit":         "baseline",
+				"pod-security.kubernetes.io/audit-version": "v1.29",
+			},
+			expectedLabels: map[string]string{
+				"pod-security.kubernetes.io/enforce":         "restricted",
+				"pod-security.kubernetes.io/enforce-version": "latest",
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(Scheme).Build()
+			cfg := sharedconfig.NewFakeSharedInformation(c, nil, nil, nil)
+			cfg.WorkspacePodSecurityData = tC.config
+			r := NewCommonReconciler(cfg, "test")
+
+			project := &pwv1alpha1.Project{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-project"},
+				Spec:       pwv1alpha1.ProjectSpec{PodSecurity: tC.project},
+			}
+			var class *pwv1alpha1.WorkspaceClass
+			if tC.class != nil {
+				class = &pwv1alpha1.WorkspaceClass{
+					ObjectMeta: metav1.ObjectMeta{Name: "secure"},
+					Spec:       pwv1alpha1.WorkspaceClassSpec{PodSecurity: tC.class},
+				}
+			}
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "project-test-project--ws-test-workspace", Labels: tC.existingLabels}}
+
+			require.NoError(t, r.applyPodSecurityLabels(newContext(), project, class, ns))
+			assert.Equal(t, tC.expectedLabels, ns.Labels)
+		})
+	}
+}
+
+func Test_workspaceOfNamespace(t *testing.T) {
+	r := &WorkspaceReconciler{}
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "project-test-project--ws-test-workspace",
+		Labels: map[string]string{"core.openmcp.cloud/project": "test-project", "core.openmcp.cloud/workspace": "test-workspace"},
+	}}
+	requests := r.workspaceOfNamespace(newContext(), ns)
+	if assert.Len(t, requests, 1) {
+		assert.Equal(t, "test-workspace", requests[0].Name)
+		assert.Equal(t, "project-test-project", requests[0].Namespace)
+	}
+
+	projectNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "project-test-project",
+		Labels: map[string]string{"core.openmcp.cloud/project": "test-project"},
+	}}
+	assert.Empty(t, r.workspaceOfNamespace(newContext(), projectNamespace))
+}
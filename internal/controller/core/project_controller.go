@@ -3,6 +3,7 @@ package core
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -10,11 +11,17 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/openmcp-project/controller-utils/pkg/clusters"
 	ctrlutils "github.com/openmcp-project/controller-utils/pkg/controller"
@@ -22,15 +29,29 @@ import (
 	apiconst "github.com/openmcp-project/openmcp-operator/api/constants"
 
 	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	pwoerrors "github.com/openmcp-project/platform-service-project-workspace/internal/errors"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/logconfig"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/metrics"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/shutdown"
 	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
 )
 
-const ProjectControllerName = "project"
+const (
+	ProjectControllerName = "project"
+	// ProjectDeletionControllerName is the name of the controller handling Projects in deletion.
+	ProjectDeletionControllerName = "project-deletion"
+)
 
 // ProjectReconciler reconciles a Project object
 type ProjectReconciler struct {
 	OnboardingStatic *clusters.Cluster
 	Scheme           *runtime.Scheme
+	// ConfigChanges optionally receives an event for each Project which needs to be reconciled because the configuration changed.
+	ConfigChanges <-chan event.GenericEvent
+	// Recorder is used to record events for Projects, e.g. when a token for the automation ServiceAccount has been issued
+	// or a resource type blocking the deletion has been skipped.
+	// If nil, SetupWithManager uses the event recorder of the manager.
+	Recorder events.EventRecorder
 	*CommonReconciler
 }
 
@@ -52,7 +73,9 @@ func NewProjectReconciler(scheme *runtime.Scheme, cr *CommonReconciler) (*Projec
 // +kubebuilder:rbac:groups=core.openmcp.cloud,resources=projects,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core.openmcp.cloud,resources=projects/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=core.openmcp.cloud,resources=projects/finalizers,verbs=update
-// +kubebuilder:rbac:groups="",resources=namespaces;secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=namespaces;secrets;serviceaccounts,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=serviceaccounts/token,verbs=create
+// +kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles;clusterrolebindings;rolebindings,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -68,7 +91,7 @@ func (r *ProjectReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	return rr, err
 }
 
-func (r *ProjectReconciler) reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *ProjectReconciler) reconcile(ctx context.Context, req ctrl.Request) (rr ctrl.Result, err error) {
 	log := logging.FromContextOrPanic(ctx)
 
 	project := &pwv1alpha1.Project{}
@@ -80,10 +103,13 @@ func (r *ProjectReconciler) reconcile(ctx context.Context, req ctrl.Request) (ct
 	if err := r.OnboardingStatic.Client().Get(ctx, req.NamespacedName, project); err != nil {
 		if apierrors.IsNotFound(err) {
 			log.Info("Project not found")
+			r.status.Forget(project)
 			return sr.StopRequeue()
 		}
-		return sr.ReturnError(fmt.Errorf("error fetching project: %w", err))
+		return reconcileResult(ProjectControllerName, sr, project, fmt.Errorf("error fetching project: %w", err))
 	}
+	// the status is only written if it differs from the one read here
+	original := project.DeepCopy()
 
 	// handle operation annotation
 	if project.GetAnnotations() != nil {
@@ -96,11 +122,41 @@ func (r *ProjectReconciler) reconcile(ctx context.Context, req ctrl.Request) (ct
 			case apiconst.OperationAnnotationValueReconcile:
 				log.Debug("Removing reconcile operation annotation from resource")
 				if err := ctrlutils.EnsureAnnotation(ctx, r.OnboardingStatic.Client(), project, apiconst.OperationAnnotation, "", true, ctrlutils.DELETE); err != nil {
-					return sr.ReturnError(fmt.Errorf("error removing operation annotation: %w", err))
+					return reconcileResult(ProjectControllerName, sr, project, fmt.Errorf("error removing operation annotation: %w", err))
+				}
+			}
+		}
+	}
+
+	if err := r.transferOwnership(ctx, project); err != nil {
+		return reconcileResult(ProjectControllerName, sr, project, err)
+	}
+
+	// Delay the teardown of the project until the deletion grace period has passed, unless the deletion is cancelled in the meantime
+	remaining, err := r.deletionGracePeriodRemaining(ctx, project)
+	if err != nil {
+		return reconcileResult(ProjectControllerName, sr, project, err)
+	}
+	if remaining > 0 {
+		if deletionCancelled(project) {
+			if err := r.cancelDeletion(ctx, project); err != nil {
+				rr, rerr := reconcileResult(ProjectControllerName, sr, project, err)
+				// the project is only kept if the cancellation is blocked by other finalizers
+				if pwoerrors.IsBlocked(err) {
+					rr = r.persistStatus(ctx, r.OnboardingStatic.Client(), original, project, rr)
 				}
+				return rr, rerr
 			}
+			return sr.StopRequeue()
 		}
+
+		r.setPendingDeletion(project, remaining)
+		rr, err := reconcileResult(ProjectControllerName, sr, project, nil)
+		// reconcile again once the grace period has passed
+		rr.RequeueAfter = remaining
+		return r.persistStatus(ctx, r.OnboardingStatic.Client(), original, project, rr), err
 	}
+	r.clearPendingDeletion(project)
 
 	projectNamespace := &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
@@ -110,47 +166,56 @@ func (r *ProjectReconciler) reconcile(ctx context.Context, req ctrl.Request) (ct
 
 	// Check if there are remaining resources in the namespace that are blocking the deletion of the project
 	// If the project is not it deletion, this will return false
-	hasRemainingContent, err := r.handleRemainingContentBeforeDelete(ctx, project)
+	hasRemainingContent, err := r.handleRemainingContentBeforeDelete(ctx, project, r.Recorder)
 	if err != nil {
-		return sr.ReturnError(err)
+		return reconcileResult(ProjectControllerName, sr, project, err)
 	}
 	if hasRemainingContent {
-		if err := r.OnboardingStatic.Client().Status().Update(ctx, project); err != nil {
-			log.Error(err, "failed to update status")
-		}
-
-		return sr.IsStable() // naming is unintuitive, this requeues with increasing backoff
+		rr, err := cleanupResult(ctx, ProjectControllerName, sr, project, cleanupBlocked("resources in namespace "+projectNamespace.Name))
+		return r.persistStatus(ctx, r.OnboardingStatic.Client(), original, project, rr), err
 	}
 
-	deleted, rqt, err := r.handleDelete(ctx, project, func() error {
-		if err := r.OnboardingStatic.Client().Delete(ctx, projectNamespace); err != nil {
-			return client.IgnoreNotFound(err)
+	deleted, cleanup, err := r.handleDelete(ctx, project, func() (CleanupResult, error) {
+		deleted, err := r.deleteNamespaceIfOwned(ctx, r.Recorder, project, projectNamespace.Name, project.Name, "")
+		if client.IgnoreNotFound(err) != nil {
+			return CleanupResult{}, err
+		}
+		if err == nil && !deleted {
+			// the namespace might contain someone else's data now, it has to be checked and cleaned up manually
+			return cleanupBlocked("namespace " + projectNamespace.Name + " with altered labels"), nil
+		}
+
+		// the finalizer must only be removed once the project namespace and all workspace namespaces are actually gone
+		cleanup, err := r.handleRemainingNamespaces(ctx, project, projectNamespace.Name)
+		if err != nil || !cleanup.Done {
+			return cleanup, err
 		}
 
-		return ResourcesRemainingError{}
+		// the owner references would remove the resources as well, but only after the finalizer has been removed
+		if err := r.deleteOwnedResources(ctx, r.OnboardingStatic.Client(), project); err != nil {
+			return CleanupResult{}, err
+		}
+		return cleanupDone(), nil
 	})
-	if deleted || err != nil {
-		switch rqt {
-		case RequeueError:
-			return sr.ReturnError(err)
-		case RequeueWithMinInterval:
-			return sr.IsProgressing()
-		case RequeueWithBackoff:
-			return sr.IsStable()
-		default:
-			return sr.StopRequeue()
+	if err != nil {
+		return reconcileResult(ProjectControllerName, sr, project, err)
+	}
+	if deleted {
+		rr, err := cleanupResult(ctx, ProjectControllerName, sr, project, cleanup)
+		// once the cleanup is done, the finalizer is removed and the project is gone
+		if !cleanup.Done {
+			rr = r.persistStatus(ctx, r.OnboardingStatic.Client(), original, project, rr)
 		}
+		return rr, err
 	}
 
 	if err := r.ensureFinalizer(ctx, project); err != nil {
-		return sr.ReturnError(err)
+		return reconcileResult(ProjectControllerName, sr, project, err)
 	}
 
-	// Always update status
+	// Update the status if it changed
 	defer func() {
-		if err := r.OnboardingStatic.Client().Status().Update(ctx, project); err != nil {
-			log.Error(err, "failed to update status")
-		}
+		rr = r.persistStatus(ctx, r.OnboardingStatic.Client(), original, project, rr)
 	}()
 
 	//
@@ -159,11 +224,18 @@ func (r *ProjectReconciler) reconcile(ctx context.Context, req ctrl.Request) (ct
 
 	result, err := controllerutil.CreateOrUpdate(ctx, r.OnboardingStatic.Client(), projectNamespace, func() error {
 		utils.SetProjectLabel(projectNamespace, project.Name)
-		r.applyManagementLabel(projectNamespace)
-		return nil
+		utils.SetOwnerUIDLabel(projectNamespace, project)
+		if err := r.applyManagementLabel(ctx, projectNamespace); err != nil {
+			return err
+		}
+		if err := r.applySecretStoreAnnotations(ctx, project, projectNamespace); err != nil {
+			return err
+		}
+		// the namespace is deleted by the cleanup above, the owner reference is only a safety net in case the finalizer is removed by someone else
+		return controllerutil.SetOwnerReference(project, projectNamespace, r.Scheme)
 	})
 	if err != nil {
-		return sr.ReturnError(err)
+		return reconcileResult(ProjectControllerName, sr, project, err)
 	}
 	utils.LogOperationResult(log, logging.INFO, projectNamespace, result)
 
@@ -174,35 +246,119 @@ func (r *ProjectReconciler) reconcile(ctx context.Context, req ctrl.Request) (ct
 	//
 
 	if err := r.createOrUpdateClusterRole(ctx, project); err != nil {
-		return sr.ReturnError(err)
+		return reconcileResult(ProjectControllerName, sr, project, err)
 	}
 	if err := r.createOrUpdateRoleBinding(ctx, project, pwv1alpha1.ProjectRoleAdmin); err != nil {
-		return sr.ReturnError(err)
+		return reconcileResult(ProjectControllerName, sr, project, err)
 	}
 	if err := r.createOrUpdateRoleBinding(ctx, project, pwv1alpha1.ProjectRoleView); err != nil {
-		return sr.ReturnError(err)
+		return reconcileResult(ProjectControllerName, sr, project, err)
+	}
+	if err := r.createOrUpdateRoleBinding(ctx, project, pwv1alpha1.ProjectRoleAuditor); err != nil {
+		return reconcileResult(ProjectControllerName, sr, project, err)
+	}
+
+	//
+	// Automation ServiceAccount
+	//
+
+	if err := r.reconcileAutomationServiceAccount(ctx, project); err != nil {
+		return reconcileResult(ProjectControllerName, sr, project, err)
 	}
 
-	return sr.StopRequeue()
+	return reconcileResult(ProjectControllerName, sr, project, nil)
+}
+
+// handleRemainingNamespaces checks whether the given project namespace or any other namespace labeled with the project, e.g. the ones of its workspaces, still exists.
+// If so, the NamespacesTerminating condition listing them is set and an unfinished cleanup result with the namespaces as blockers is returned.
+func (r *ProjectReconciler) handleRemainingNamespaces(ctx context.Context, project *pwv1alpha1.Project, projectNamespace string) (CleanupResult, error) {
+	remaining := sets.New[string]()
+	if err := r.OnboardingStatic.Client().Get(ctx, client.ObjectKey{Name: projectNamespace}, &corev1.Namespace{}); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return CleanupResult{}, fmt.Errorf("error fetching project namespace: %w", err)
+		}
+	} else {
+		remaining.Insert(projectNamespace)
+	}
+	namespaces := &corev1.NamespaceList{}
+	if err := r.OnboardingStatic.Client().List(ctx, namespaces, client.MatchingLabels{utils.LabelProject: project.Name}); err != nil {
+		return CleanupResult{}, fmt.Errorf("error listing namespaces of project: %w", err)
+	}
+	for _, ns := range namespaces.Items {
+		remaining.Insert(ns.Name)
+	}
+
+	if remaining.Len() == 0 {
+		project.RemoveCondition(pwv1alpha1.ConditionTypeNamespacesTerminating)
+		return cleanupDone(), nil
+	}
+
+	names := sets.List(remaining)
+	details, err := json.Marshal(names)
+	if err != nil {
+		return CleanupResult{}, fmt.Errorf("failed to marshal remaining namespaces: %w", err)
+	}
+	project.SetOrUpdateCondition(pwv1alpha1.Condition{
+		Type:    pwv1alpha1.ConditionTypeNamespacesTerminating,
+		Status:  pwv1alpha1.ConditionStatusTrue,
+		Reason:  pwv1alpha1.ConditionReasonNamespacesRemaining,
+		Message: fmt.Sprintf("Waiting for %d namespaces to be deleted: %s", len(names), strings.Join(names, ", ")),
+		Details: details,
+	})
+	blockers := make([]string, 0, len(names))
+	for _, name := range names {
+		blockers = append(blockers, "namespace "+name)
+	}
+	return cleanupBlocked(blockers...), nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
+// Projects in deletion are handled by a separate controller with its own work queue, see deletionPhaseFilter.
 func (r *ProjectReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorder(ProjectControllerName)
+	}
+	relevantChanges := predicate.And(
+		predicate.Or(
+			predicate.GenerationChangedPredicate{},
+			ctrlutils.DeletionTimestampChangedPredicate{},
+			ctrlutils.GotAnnotationPredicate(apiconst.OperationAnnotation, apiconst.OperationAnnotationValueReconcile),
+			ctrlutils.LostAnnotationPredicate(apiconst.OperationAnnotation, apiconst.OperationAnnotationValueIgnore),
+			ctrlutils.GotAnnotationPredicate(pwv1alpha1.AutomationTokenRequestAnnotation, ""),
+			ctrlutils.GotAnnotationPredicate(pwv1alpha1.CancelDeletionAnnotation, "true"),
+			ctrlutils.GotAnnotationPredicate(pwv1alpha1.TransferOwnershipAnnotation, ""),
+		),
+		predicate.Not(
+			ctrlutils.HasAnnotationPredicate(apiconst.OperationAnnotation, apiconst.OperationAnnotationValueIgnore),
+		),
+	)
+	newProject := func() client.Object { return &pwv1alpha1.Project{} }
+
+	b := ctrl.NewControllerManagedBy(mgr).
+		Named(ProjectControllerName).
+		WithLogConstructor(logconfig.LogConstructor(mgr.GetLogger(), ProjectControllerName)).
+		For(&pwv1alpha1.Project{}, builder.WithPredicates(
+			predicate.And(relevantChanges, predicate.Not(inDeletionPredicate)),
+		))
+	if r.ConfigChanges != nil {
+		b = b.WatchesRawSource(source.Channel(r.ConfigChanges, &handler.EnqueueRequestForObject{}))
+	}
+	handover := make(chan event.GenericEvent)
+	rec := deletionPhaseFilter(r.OnboardingStatic.Client(), newProject, false, handover, metrics.ObserveReconciler(ProjectControllerName, r))
+	if err := b.Complete(shutdown.GracefulReconciler(rec, r.ShutdownGracePeriod)); err != nil {
+		return err
+	}
+
+	deletionRec := deletionPhaseFilter(r.OnboardingStatic.Client(), newProject, true, nil, metrics.ObserveReconciler(ProjectDeletionControllerName, r))
 	return ctrl.NewControllerManagedBy(mgr).
+		Named(ProjectDeletionControllerName).
+		WithLogConstructor(logconfig.LogConstructor(mgr.GetLogger(), ProjectDeletionControllerName)).
 		For(&pwv1alpha1.Project{}, builder.WithPredicates(
-			predicate.And(
-				predicate.Or(
-					predicate.GenerationChangedPredicate{},
-					ctrlutils.DeletionTimestampChangedPredicate{},
-					ctrlutils.GotAnnotationPredicate(apiconst.OperationAnnotation, apiconst.OperationAnnotationValueReconcile),
-					ctrlutils.LostAnnotationPredicate(apiconst.OperationAnnotation, apiconst.OperationAnnotationValueIgnore),
-				),
-				predicate.Not(
-					ctrlutils.HasAnnotationPredicate(apiconst.OperationAnnotation, apiconst.OperationAnnotationValueIgnore),
-				),
-			),
+			predicate.And(relevantChanges, inDeletionPredicate),
 		)).
-		Complete(r)
+		WatchesRawSource(blockingResourcesChangedSource(r.Config, r.OnboardingStatic.Client(), "Project")).
+		WatchesRawSource(source.Channel(handover, &handler.EnqueueRequestForObject{})).
+		Complete(shutdown.GracefulReconciler(deletionRec, r.ShutdownGracePeriod))
 }
 
 func (r *ProjectReconciler) createOrUpdateRoleBinding(ctx context.Context, project *pwv1alpha1.Project, role pwv1alpha1.ProjectMemberRole) error {
@@ -216,9 +372,12 @@ func (r *ProjectReconciler) createOrUpdateRoleBinding(ctx context.Context, proje
 	}
 
 	result, err := controllerutil.CreateOrUpdate(ctx, r.OnboardingStatic.Client(), roleBinding, func() error {
-		r.applyManagementLabel(roleBinding)
+		if err := r.applyManagementLabel(ctx, roleBinding); err != nil {
+			return err
+		}
+		utils.SetOwnerUIDLabel(roleBinding, project)
 
-		roleBinding.Subjects = getSubjectsForProjectRole(project, role)
+		utils.SetSubjectsIfChanged(&roleBinding.Subjects, getSubjectsForProjectRole(project, role))
 		roleBinding.RoleRef = rbacv1.RoleRef{
 			APIGroup: rbacv1.GroupName,
 			Kind:     "ClusterRole",
@@ -228,7 +387,11 @@ func (r *ProjectReconciler) createOrUpdateRoleBinding(ctx context.Context, proje
 		return controllerutil.SetOwnerReference(project, roleBinding, r.Scheme)
 	})
 	utils.LogOperationResult(log, logging.INFO, roleBinding, result)
-	return err
+	if err != nil {
+		return err
+	}
+	metrics.RecordRBACUpdate(roleBinding, result)
+	return nil
 }
 
 func getSubjectsForProjectRole(project *pwv1alpha1.Project, role pwv1alpha1.ProjectMemberRole) []rbacv1.Subject {
@@ -253,30 +416,91 @@ func hasProjectRole(member pwv1alpha1.ProjectMember, role pwv1alpha1.ProjectMemb
 	return false
 }
 
+// projectClusterRole describes a ClusterRole and the ClusterRoleBinding of the same name, which grant access to a project.
+type projectClusterRole struct {
+	name     string
+	verbs    []string
+	subjects []rbacv1.Subject
+}
+
+// desiredProjectClusterRoles returns the ClusterRoles which should exist for the given project, as well as the names of the ones which must not exist.
+// If consolidated is true, the 'view' and 'auditor' ClusterRoles are replaced by a single 'member' ClusterRole, which is bound to all members of the project.
+// The ClusterRoles with the legacy names, which have been used before the names were hashed, must not exist either.
+func desiredProjectClusterRoles(project *pwv1alpha1.Project, consolidated bool) ([]projectClusterRole, []string) {
+	legacy := []string{
+		utils.LegacyClusterRoleForEntityAndRole(project, pwv1alpha1.ProjectRoleAdmin),
+		utils.LegacyClusterRoleForEntityAndRole(project, pwv1alpha1.ProjectRoleView),
+		utils.LegacyClusterRoleForEntityAndRole(project, pwv1alpha1.ProjectRoleAuditor),
+		utils.LegacyClusterRoleForEntityMembers(project),
+	}
+	desired := []projectClusterRole{
+		{
+			name:     utils.ClusterRoleForEntityAndRole(project, pwv1alpha1.ProjectRoleAdmin),
+			verbs:    utils.AllVerbs(),
+			subjects: getSubjectsForProjectRole(project, pwv1alpha1.ProjectRoleAdmin),
+		},
+	}
+	memberRoles := []string{
+		utils.ClusterRoleForEntityAndRole(project, pwv1alpha1.ProjectRoleView),
+		utils.ClusterRoleForEntityAndRole(project, pwv1alpha1.ProjectRoleAuditor),
+	}
+	if !consolidated {
+		desired = append(desired,
+			projectClusterRole{
+				name:     memberRoles[0],
+				verbs:    utils.ReadOnlyVerbs(),
+				subjects: getSubjectsForProjectRole(project, pwv1alpha1.ProjectRoleView),
+			},
+			projectClusterRole{
+				name:     memberRoles[1],
+				verbs:    utils.ReadOnlyVerbs(),
+				subjects: getSubjectsForProjectRole(project, pwv1alpha1.ProjectRoleAuditor),
+			},
+		)
+		return desired, append([]string{utils.ClusterRoleForEntityMembers(project)}, legacy...)
+	}
+
+	subjects := make([]rbacv1.Subject, 0, len(project.Spec.Members))
+	for _, member := range project.Spec.Members {
+		subjects = append(subjects, member.RbacV1())
+	}
+	desired = append(desired, projectClusterRole{
+		name:     utils.ClusterRoleForEntityMembers(project),
+		verbs:    utils.ReadOnlyVerbs(),
+		subjects: subjects,
+	})
+	return desired, append(memberRoles, legacy...)
+}
+
 func (r *ProjectReconciler) createOrUpdateClusterRole(ctx context.Context, project *pwv1alpha1.Project) error {
 	log := logging.FromContextOrPanic(ctx)
 
-	projectRoles := map[pwv1alpha1.ProjectMemberRole][]string{
-		pwv1alpha1.ProjectRoleAdmin: utils.AllVerbs(),
-		pwv1alpha1.ProjectRoleView:  utils.ReadOnlyVerbs(),
+	consolidated, err := r.Config.ConsolidatedProjectClusterRoles(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get ClusterRole consolidation from config: %w", err)
 	}
+	desired, stale := desiredProjectClusterRoles(project, consolidated)
 
-	for role, verbs := range projectRoles {
+	for _, pcr := range desired {
 		clusterRole := &rbacv1.ClusterRole{
 			ObjectMeta: metav1.ObjectMeta{
-				Name: utils.ClusterRoleForEntityAndRole(project, role),
+				Name: pcr.name,
 			},
 		}
 
 		result, err := controllerutil.CreateOrUpdate(ctx, r.OnboardingStatic.Client(), clusterRole, func() error {
-			r.applyManagementLabel(clusterRole)
+			if err := r.applyManagementLabel(ctx, clusterRole); err != nil {
+				return err
+			}
+			utils.SetOwnerUIDLabel(clusterRole, project)
+			utils.SetOwnerAnnotation(clusterRole, project)
 
-			clusterRole.Rules = []rbacv1.PolicyRule{
+			utils.SetRulesIfChanged(&clusterRole.Rules, []rbacv1.PolicyRule{
 				{
 					APIGroups:     []string{pwv1alpha1.GroupVersion.Group},
 					Resources:     []string{"projects"},
 					ResourceNames: []string{project.Name},
-					Verbs:         verbs,
+					Verbs:         pcr.verbs,
 				},
 				{
 					APIGroups:     []string{""},
@@ -284,7 +508,7 @@ func (r *ProjectReconciler) createOrUpdateClusterRole(ctx context.Context, proje
 					ResourceNames: []string{project.Status.Namespace},
 					Verbs:         []string{"get"},
 				},
-			}
+			})
 
 			// Delete ClusterRole automatically when Project is deleted.
 			return controllerutil.SetOwnerReference(project, clusterRole, r.Scheme)
@@ -293,17 +517,24 @@ func (r *ProjectReconciler) createOrUpdateClusterRole(ctx context.Context, proje
 			return err
 		}
 		utils.LogOperationResult(log, logging.INFO, clusterRole, result)
+		metrics.RecordRBACUpdate(clusterRole, result)
 
 		clusterRoleBinding := &rbacv1.ClusterRoleBinding{
 			ObjectMeta: metav1.ObjectMeta{
-				Name: utils.ClusterRoleForEntityAndRole(project, role),
+				Name: pcr.name,
 			},
 		}
 
+		var previousSubjects []rbacv1.Subject
 		result, err = controllerutil.CreateOrUpdate(ctx, r.OnboardingStatic.Client(), clusterRoleBinding, func() error {
-			r.applyManagementLabel(clusterRoleBinding)
+			if err := r.applyManagementLabel(ctx, clusterRoleBinding); err != nil {
+				return err
+			}
+			utils.SetOwnerUIDLabel(clusterRoleBinding, project)
+			utils.SetOwnerAnnotation(clusterRoleBinding, project)
 
-			clusterRoleBinding.Subjects = getSubjectsForProjectRole(project, role)
+			previousSubjects = clusterRoleBinding.Subjects
+			utils.SetSubjectsIfChanged(&clusterRoleBinding.Subjects, pcr.subjects)
 			clusterRoleBinding.RoleRef = rbacv1.RoleRef{
 				APIGroup: rbacv1.GroupName,
 				Kind:     "ClusterRole",
@@ -317,6 +548,41 @@ func (r *ProjectReconciler) createOrUpdateClusterRole(ctx context.Context, proje
 			return err
 		}
 		utils.LogOperationResult(log, logging.INFO, clusterRoleBinding, result)
+		metrics.RecordRBACUpdate(clusterRoleBinding, result)
+		if result == controllerutil.OperationResultUpdated {
+			recordSubjectChanges(r.Recorder, project, clusterRoleBinding, previousSubjects)
+		}
+	}
+
+	// Remove the ClusterRoles of the other mode, in case the consolidation has been switched, and the ones with legacy names.
+	// They are only deleted after the desired ones exist, so that the members do not lose access in between.
+	// The bindings are deleted first, so that no binding refers to a missing ClusterRole.
+	for _, name := range stale {
+		clusterRoleBinding := &rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+		}
+		deleted, err := r.deleteIfManaged(ctx, r.OnboardingStatic.Client(), clusterRoleBinding)
+		if err != nil {
+			return err
+		}
+		if deleted {
+			log.Debug("Deleted ClusterRoleBinding", "clusterRoleBinding", clusterRoleBinding.Name)
+		}
+
+		clusterRole := &rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+		}
+		deleted, err = r.deleteIfManaged(ctx, r.OnboardingStatic.Client(), clusterRole)
+		if err != nil {
+			return err
+		}
+		if deleted {
+			log.Debug("Deleted ClusterRole", "clusterRole", clusterRole.Name)
+		}
 	}
 
 	return nil
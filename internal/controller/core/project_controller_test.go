@@ -9,6 +9,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/json"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -22,7 +23,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	apiconst "github.com/openmcp-project/openmcp-operator/api/constants"
+
 	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/api/v2/metadata"
 	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
 	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
 )
@@ -162,13 +166,50 @@ func Test_ProjectReconciler_Reconcile(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			desc: "should reconcile and remove the reconcile operation annotation",
+			initObjs: []client.Object{
+				withAnnotation(sampleProject, apiconst.OperationAnnotation, apiconst.OperationAnnotationValueReconcile),
+			},
+			expectedResult: reconcile.Result{},
+			expectedErr:    nil,
+			validate: func(t *testing.T, ctx context.Context, c client.Client) error {
+				p := &pwv1alpha1.Project{}
+				assert.NoErrorf(t, c.Get(ctx, client.ObjectKeyFromObject(sampleProject), p), "GET failed unexpectedly")
+				assert.NotContains(t, p.GetAnnotations(), apiconst.OperationAnnotation)
+
+				namespaceCreatedForProject(t, ctx, c, p, true)
+
+				return nil
+			},
+		},
+		{
+			desc: "should not reconcile when the ignore operation annotation is set",
+			initObjs: []client.Object{
+				withAnnotation(sampleProject, apiconst.OperationAnnotation, apiconst.OperationAnnotationValueIgnore),
+			},
+			expectedResult: reconcile.Result{},
+			expectedErr:    nil,
+			validate: func(t *testing.T, ctx context.Context, c client.Client) error {
+				p := &pwv1alpha1.Project{}
+				assert.NoErrorf(t, c.Get(ctx, client.ObjectKeyFromObject(sampleProject), p), "GET failed unexpectedly")
+				assert.Equal(t, apiconst.OperationAnnotationValueIgnore, p.GetAnnotations()[apiconst.OperationAnnotation])
+				assert.Empty(t, p.Status.Namespace)
+
+				err := c.Get(ctx, types.NamespacedName{Name: utils.NamespaceForProject(p)}, &corev1.Namespace{})
+				assert.True(t, apierrors.IsNotFound(err))
+
+				return nil
+			},
+		},
 		{
 			desc: "CO-1154 should delete namespace",
 			initObjs: []client.Object{
 				sampleProjectDeleted,
 				&corev1.Namespace{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: sampleProjectDeleted.Status.Namespace,
+						Name:   sampleProjectDeleted.Status.Namespace,
+						Labels: ownedNamespaceLabels(sampleProjectDeleted.Name, ""),
 					},
 				},
 			},
@@ -185,6 +226,40 @@ func Test_ProjectReconciler_Reconcile(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			desc: "should wait for terminating workspace namespaces before removing the finalizer",
+			initObjs: []client.Object{
+				sampleProjectDeleted,
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              "project-sample--ws-sample",
+						Labels:            map[string]string{utils.LabelProject: sampleProjectDeleted.Name},
+						DeletionTimestamp: ptr.To(metav1.Now()),
+						Finalizers:        []string{"kubernetes"},
+					},
+				},
+			},
+			expectedResult: reconcile.Result{RequeueAfter: 5 * time.Second},
+			expectedErr:    nil,
+			validate: func(t *testing.T, ctx context.Context, c client.Client) error {
+				p := &pwv1alpha1.Project{}
+				err := c.Get(ctx, client.ObjectKeyFromObject(sampleProjectDeleted), p)
+				assert.NoError(t, err)
+				assert.Contains(t, p.Finalizers, deleteFinalizer)
+
+				assert.Len(t, p.Status.Conditions, 1)
+				assert.Equal(t, pwv1alpha1.ConditionTypeNamespacesTerminating, p.Status.Conditions[0].Type)
+				assert.Equal(t, pwv1alpha1.ConditionStatusTrue, p.Status.Conditions[0].Status)
+				assert.Equal(t, pwv1alpha1.ConditionReasonNamespacesRemaining, p.Status.Conditions[0].Reason)
+				assert.Contains(t, p.Status.Conditions[0].Message, "project-sample--ws-sample")
+
+				var remainingNamespaces []string
+				assert.NoError(t, json.Unmarshal(p.Status.Conditions[0].Details, &remainingNamespaces))
+				assert.Equal(t, []string{"project-sample--ws-sample"}, remainingNamespaces)
+
+				return nil
+			},
+		},
 		{
 			desc: "CO-1154 should not delete namespace when deletion is blocked by resources",
 			initObjs: []client.Object{
@@ -216,17 +291,22 @@ func Test_ProjectReconciler_Reconcile(t *testing.T) {
 				assert.NotEmpty(t, p.Status.Conditions[0].Message)
 				assert.NotNil(t, p.Status.Conditions[0].Details)
 
-				var remainingResources []pwv1alpha1.RemainingContentResource
-				assert.NoError(t, json.Unmarshal(p.Status.Conditions[0].Details, &remainingResources))
+				var details pwv1alpha1.RemainingContentDetails
+				assert.NoError(t, json.Unmarshal(p.Status.Conditions[0].Details, &details))
+				assert.Equal(t, 1, details.Count)
+				remainingResources := details.Resources
 				assert.Len(t, remainingResources, 1)
 				assert.Equal(t, "v1", remainingResources[0].APIGroup)
 				assert.Equal(t, "Secret", remainingResources[0].Kind)
 				assert.Equal(t, "blocking", remainingResources[0].Name)
+				assert.Equal(t, pwv1alpha1.SourceProjectWorkspaceConfig, remainingResources[0].Source)
+				assert.Contains(t, p.Status.Conditions[0].Message, "ProjectWorkspaceConfig: 1")
 
 				ns := &corev1.Namespace{}
 				err = c.Get(ctx, types.NamespacedName{Name: p.Status.Namespace}, ns)
 				assert.NoError(t, err)
 				assert.Nil(t, ns.GetDeletionTimestamp())
+				assert.Equal(t, "true", ns.GetAnnotations()[pwv1alpha1.DeletionRequestedAnnotation])
 
 				return nil
 			},
@@ -276,6 +356,114 @@ func Test_ProjectReconciler_Reconcile(t *testing.T) {
 	}
 }
 
+func Test_ProjectReconciler_consolidatedClusterRoles(t *testing.T) {
+	p := sampleProject.DeepCopy()
+	c := fake.NewClientBuilder().
+		WithObjects(p).
+		WithStatusSubresource(p).
+		WithScheme(Scheme).
+		Build()
+	ctx := newContext()
+
+	cfg := sharedconfig.NewFakeSharedInformation(c, nil, nil, nil)
+	pr, err := NewProjectReconciler(c.Scheme(), NewCommonReconciler(cfg, "test"))
+	require.NoError(t, err)
+
+	reconcileProject := func(t *testing.T) *pwv1alpha1.Project {
+		result, err := ctrl.Result{}, error(nil)
+		for range maxReconcileCycles {
+			result, err = pr.Reconcile(ctx, newRequest(p))
+			if result.RequeueAfter == 0 || err != nil {
+				break
+			}
+		}
+		require.NoError(t, err)
+		res := &pwv1alpha1.Project{}
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(p), res))
+		return res
+	}
+	memberClusterRoleExists := func(t *testing.T, p *pwv1alpha1.Project) bool {
+		err := c.Get(ctx, types.NamespacedName{Name: utils.ClusterRoleForEntityMembers(p)}, &rbacv1.ClusterRole{})
+		if apierrors.IsNotFound(err) {
+			return false
+		}
+		require.NoError(t, err)
+		return true
+	}
+
+	res := reconcileProject(t)
+	clusterRoleCreatedForProject(t, ctx, c, res, pwv1alpha1.ProjectRoleView, true, 2)
+	clusterRoleCreatedForProject(t, ctx, c, res, pwv1alpha1.ProjectRoleAuditor, true, 2)
+	assert.False(t, memberClusterRoleExists(t, res))
+
+	t.Run("replaces the view and auditor ClusterRoles by a single member ClusterRole", func(t *testing.T) {
+		cfg.ConsolidatedProjectClusterRolesData = true
+		res := reconcileProject(t)
+
+		clusterRoleCreatedForProject(t, ctx, c, res, pwv1alpha1.ProjectRoleAdmin, true, 2)
+		clusterRoleCreatedForProject(t, ctx, c, res, pwv1alpha1.ProjectRoleView, false, 0)
+		clusterRoleBindingCreatedForProject(t, ctx, c, res, pwv1alpha1.ProjectRoleView, false, nil)
+		clusterRoleCreatedForProject(t, ctx, c, res, pwv1alpha1.ProjectRoleAuditor, false, 0)
+		clusterRoleBindingCreatedForProject(t, ctx, c, res, pwv1alpha1.ProjectRoleAuditor, false, nil)
+
+		assert.True(t, memberClusterRoleExists(t, res))
+		crb := &rbacv1.ClusterRoleBinding{}
+		require.NoError(t, c.Get(ctx, types.NamespacedName{Name: utils.ClusterRoleForEntityMembers(res)}, crb))
+		assert.Equal(t, utils.ClusterRoleForEntityMembers(res), crb.RoleRef.Name)
+		assert.Len(t, crb.Subjects, len(res.Spec.Members), "all members must be bound, independent of their roles")
+	})
+
+	t.Run("restores the view and auditor ClusterRoles when the consolidation is disabled", func(t *testing.T) {
+		cfg.ConsolidatedProjectClusterRolesData = false
+		res := reconcileProject(t)
+
+		clusterRoleCreatedForProject(t, ctx, c, res, pwv1alpha1.ProjectRoleView, true, 2)
+		clusterRoleCreatedForProject(t, ctx, c, res, pwv1alpha1.ProjectRoleAuditor, true, 2)
+		assert.False(t, memberClusterRoleExists(t, res))
+	})
+}
+
+func Test_ProjectReconciler_migratesLegacyClusterRoleNames(t *testing.T) {
+	p := sampleProject.DeepCopy()
+	legacyName := utils.LegacyClusterRoleForEntityAndRole(p, pwv1alpha1.ProjectRoleAdmin)
+	managed := utils.ManagementLabels("test", pwv1alpha1.ManagementLabelsConfig{})
+	legacyRole := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: legacyName, Labels: managed}}
+	legacyBinding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: legacyName, Labels: managed},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: legacyName},
+	}
+	foreign := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: utils.LegacyClusterRoleForEntityAndRole(p, pwv1alpha1.ProjectRoleView)}}
+	c := fake.NewClientBuilder().
+		WithObjects(p, legacyRole, legacyBinding, foreign).
+		WithStatusSubresource(p).
+		WithScheme(Scheme).
+		Build()
+	ctx := newContext()
+
+	pr, err := NewProjectReconciler(c.Scheme(), NewCommonReconciler(sharedconfig.NewFakeSharedInformation(c, nil, nil, nil), "test"))
+	require.NoError(t, err)
+	for range maxReconcileCycles {
+		result, err := pr.Reconcile(ctx, newRequest(p))
+		require.NoError(t, err)
+		if result.RequeueAfter == 0 {
+			break
+		}
+	}
+	res := &pwv1alpha1.Project{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(p), res))
+
+	clusterRoleCreatedForProject(t, ctx, c, res, pwv1alpha1.ProjectRoleAdmin, true, 2)
+	cr := &rbacv1.ClusterRole{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: utils.ClusterRoleForEntityAndRole(res, pwv1alpha1.ProjectRoleAdmin)}, cr))
+	assert.Equal(t, "project/"+res.Name, cr.Annotations[utils.AnnotationOwner])
+
+	err = c.Get(ctx, client.ObjectKeyFromObject(legacyBinding), &rbacv1.ClusterRoleBinding{})
+	assert.True(t, apierrors.IsNotFound(err), "expected the legacy ClusterRoleBinding to be deleted, got: %v", err)
+	err = c.Get(ctx, client.ObjectKeyFromObject(legacyRole), &rbacv1.ClusterRole{})
+	assert.True(t, apierrors.IsNotFound(err), "expected the legacy ClusterRole to be deleted, got: %v", err)
+	assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(foreign), &rbacv1.ClusterRole{}), "unmanaged ClusterRoles must not be deleted")
+}
+
 func newContext() context.Context {
 	ctx := context.Background()
 	ctx = log.IntoContext(ctx, log.Log)
@@ -288,12 +476,23 @@ func newRequest(obj client.Object) ctrl.Request {
 	}
 }
 
+// withAnnotation returns a copy of the given object with the given annotation set.
+func withAnnotation[T client.Object](obj T, key, value string) T {
+	res := obj.DeepCopyObject().(T)
+	metadata.SetAnnotation(res, key, value)
+	return res
+}
+
 func namespaceCreatedForProject(t *testing.T, ctx context.Context, c client.Client, p *pwv1alpha1.Project, expectation bool) *corev1.Namespace {
 	ns := &corev1.Namespace{}
 	err := c.Get(ctx, types.NamespacedName{Name: p.Status.Namespace}, ns)
 	if expectation {
 		assert.NoError(t, err)
 		assert.Equal(t, p.Name, ns.Labels[utils.LabelProject])
+		if assert.Len(t, ns.OwnerReferences, 1) {
+			assert.Equal(t, p.UID, ns.OwnerReferences[0].UID)
+			assert.Equal(t, "Project", ns.OwnerReferences[0].Kind)
+		}
 	} else {
 		assert.True(t, apierrors.IsNotFound(err))
 	}
@@ -0,0 +1,125 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/openmcp-project/controller-utils/pkg/clusters"
+	ctrlutils "github.com/openmcp-project/controller-utils/pkg/controller"
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/logconfig"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/metrics"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+const (
+	ProjectQuotaControllerName = "project-quota"
+)
+
+// ProjectQuotaReconciler updates the status of ProjectQuotas with the projects which have been created by their user.
+// The quota itself is enforced by the project webhook, the status only shows the usage.
+type ProjectQuotaReconciler struct {
+	OnboardingStatic *clusters.Cluster
+	*CommonReconciler
+}
+
+func NewProjectQuotaReconciler(cr *CommonReconciler) (*ProjectQuotaReconciler, error) {
+	pq := &ProjectQuotaReconciler{
+		CommonReconciler: cr,
+	}
+
+	onboardingClusterStatic, err := cr.Config.OnboardingClusterStatic(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	pq.OnboardingStatic = onboardingClusterStatic
+
+	return pq, nil
+}
+
+// +kubebuilder:rbac:groups=core.openmcp.cloud,resources=projectquotas,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core.openmcp.cloud,resources=projectquotas/status,verbs=get;update;patch
+
+func (r *ProjectQuotaReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logging.FromContextOrPanic(ctx).WithName(ProjectQuotaControllerName)
+	ctx = logging.NewContext(ctx, log)
+	log.Debug("Reconcile started")
+
+	quota := &pwv1alpha1.ProjectQuota{}
+	if err := r.OnboardingStatic.Client().Get(ctx, req.NamespacedName, quota); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("error fetching ProjectQuota: %w", err)
+	}
+	if utils.WasDeleted(quota) {
+		return ctrl.Result{}, nil
+	}
+
+	projects := &pwv1alpha1.ProjectList{}
+	if err := r.OnboardingStatic.Client().List(ctx, projects); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list projects: %w", err)
+	}
+	created := utils.ProjectsCreatedBy(projects.Items, quota.Spec.User)
+
+	status := pwv1alpha1.ProjectQuotaStatus{
+		ObservedGeneration: quota.Generation,
+		Used:               int32(len(created)),
+		Projects:           created,
+	}
+	if quota.Status.ObservedGeneration == status.ObservedGeneration && quota.Status.Used == status.Used && slices.Equal(quota.Status.Projects, status.Projects) {
+		return ctrl.Result{}, nil
+	}
+	old := quota.DeepCopy()
+	quota.Status = status
+	if err := r.OnboardingStatic.Client().Status().Patch(ctx, quota, client.MergeFrom(old)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("error updating ProjectQuota status: %w", err)
+	}
+	log.Info("Updated project quota usage", "user", quota.Spec.User, "used", status.Used, "max", quota.Spec.MaxProjects)
+	return ctrl.Result{}, nil
+}
+
+// quotasOfCreator returns reconcile requests for all ProjectQuotas of the user who created the given project.
+// This is required for the usage to follow the creation and deletion of projects.
+func (r *ProjectQuotaReconciler) quotasOfCreator(ctx context.Context, obj client.Object) []ctrl.Request {
+	createdBy := obj.GetAnnotations()[pwv1alpha1.CreatedByAnnotation]
+	if createdBy == "" {
+		return nil
+	}
+
+	quotas := &pwv1alpha1.ProjectQuotaList{}
+	if err := r.OnboardingStatic.Client().List(ctx, quotas); err != nil {
+		logging.FromContextOrDiscard(ctx).Error(err, "failed to list quotas of project creator", "project", obj.GetName())
+		return nil
+	}
+
+	requests := []ctrl.Request{}
+	for _, quota := range quotas.Items {
+		if quota.Spec.User == createdBy {
+			requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&quota)})
+		}
+	}
+	return requests
+}
+
+func (r *ProjectQuotaReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(ProjectQuotaControllerName).
+		WithLogConstructor(logconfig.LogConstructor(mgr.GetLogger(), ProjectQuotaControllerName)).
+		For(&pwv1alpha1.ProjectQuota{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// the creator of a project cannot change, so only creations and deletions are relevant
+		Watches(&pwv1alpha1.Project{}, handler.EnqueueRequestsFromMapFunc(r.quotasOfCreator), builder.WithPredicates(
+			ctrlutils.DeletionTimestampChangedPredicate{},
+		)).
+		Complete(metrics.ObserveReconciler(ProjectQuotaControllerName, r))
+}
@@ -0,0 +1,53 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+)
+
+func Test_ProjectQuotaReconciler(t *testing.T) {
+	project := func(name, createdBy string) *pwv1alpha1.Project {
+		return &pwv1alpha1.Project{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{pwv1alpha1.CreatedByAnnotation: createdBy},
+		}}
+	}
+	quota := &pwv1alpha1.ProjectQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "ci-bot", Generation: 2},
+		Spec:       pwv1alpha1.ProjectQuotaSpec{User: "ci-bot@example.com", MaxProjects: 5},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(Scheme).
+		WithObjects(quota, project("beta", "ci-bot@example.com"), project("alpha", "ci-bot@example.com"), project("other", "jane@example.com")).
+		WithStatusSubresource(quota).
+		Build()
+	r, err := NewProjectQuotaReconciler(NewCommonReconciler(sharedconfig.NewFakeSharedInformation(c, nil, nil, nil), "test"))
+	require.NoError(t, err)
+
+	t.Run("should show the projects created by the user in the status", func(t *testing.T) {
+		_, err := r.Reconcile(newContext(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(quota)})
+		require.NoError(t, err)
+
+		res := &pwv1alpha1.ProjectQuota{}
+		require.NoError(t, c.Get(newContext(), client.ObjectKeyFromObject(quota), res))
+		assert.Equal(t, pwv1alpha1.ProjectQuotaStatus{
+			ObservedGeneration: 2,
+			Used:               2,
+			Projects:           []string{"alpha", "beta"},
+		}, res.Status)
+	})
+
+	t.Run("should enqueue the quotas of the creator of a project", func(t *testing.T) {
+		assert.Equal(t, []reconcile.Request{{NamespacedName: client.ObjectKeyFromObject(quota)}}, r.quotasOfCreator(newContext(), project("gamma", "ci-bot@example.com")))
+		assert.Empty(t, r.quotasOfCreator(newContext(), project("delta", "jane@example.com")))
+	})
+}
@@ -0,0 +1,46 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/api/v2/metadata"
+)
+
+// applyProviderHintAnnotations sets an annotation for each provider hint of the given workspace on the given namespace.
+// The annotations of available hints which the workspace does not set are removed.
+// Hints which are not available, because they are not configured or their ServiceProvider is not registered, or whose value is not allowed, are skipped.
+// The webhook rejects such hints, so they only occur if the configuration or the registered ServiceProviders have changed afterwards.
+func (r *CommonReconciler) applyProviderHintAnnotations(ctx context.Context, workspace *pwv1alpha1.Workspace, ns *corev1.Namespace) error {
+	log := logging.FromContextOrPanic(ctx)
+
+	hints, err := r.Config.WorkspaceProviderHints(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get provider hints from config: %w", err)
+	}
+	for _, hint := range hints {
+		value, ok := workspace.Spec.ProviderHints[hint.Key]
+		if !ok {
+			delete(ns.Annotations, hint.AnnotationKey())
+			continue
+		}
+		if !hint.AllowsValue(value) {
+			log.Info("Skipping provider hint with a value which is not allowed", "key", hint.Key, "value", value)
+			delete(ns.Annotations, hint.AnnotationKey())
+			continue
+		}
+		metadata.SetAnnotation(ns, hint.AnnotationKey(), value)
+	}
+	for key := range workspace.Spec.ProviderHints {
+		if !slices.ContainsFunc(hints, func(hint pwv1alpha1.ProviderHintConfig) bool { return hint.Key == key }) {
+			log.Info("Skipping provider hint which is not available", "key", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+)
+
+func Test_applyProviderHintAnnotations(t *testing.T) {
+	hints := []pwv1alpha1.ProviderHintConfig{
+		{Key: "region", ServiceProvider: "mcp", AllowedValues: []string{"eu10", "us10"}},
+		{Key: "tier", ServiceProvider: "mcp", Annotation: "mcp.example.com/tier"},
+	}
+
+	testCases := []struct {
+		desc                string
+		providerHints       map[string]string
+		existingAnnotations map[string]string
+		expectedAnnotations map[string]string
+	}{
+		{
+			desc:          "should annotate the namespace with the provider hints",
+			providerHints: map[string]string{"region": "eu10", "tier": "premium"},
+			expectedAnnotations: map[string]string{
+				"providerhint.core.openmcp.cloud/region": "eu10",
+				"mcp.example.com/tier":                   "premium",
+			},
+		},
+		{
+			desc:          "should remove annotations of hints which are not set anymore",
+			providerHints: map[string]string{"tier": "premium"},
+			existingAnnotations: map[string]string{
+				"providerhint.core.openmcp.cloud/region": "eu10",
+				"other":                                  "value",
+			},
+			expectedAnnotations: map[string]string{
+				"mcp.example.com/tier": "premium",
+				"other":                "value",
+			},
+		},
+		{
+			desc:          "should skip hints which are not available or have a value which is not allowed",
+			providerHints: map[string]string{"region": "ap10", "zone": "a"},
+			existingAnnotations: map[string]string{
+				"providerhint.core.openmcp.cloud/region": "eu10",
+				"providerhint.core.openmcp.cloud/zone":   "b",
+			},
+			expectedAnnotations: map[string]string{
+				"providerhint.core.openmcp.cloud/zone": "b",
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(Scheme).Build()
+			cfg := sharedconfig.NewFakeSharedInformation(c, nil, nil, nil)
+			cfg.WorkspaceProviderHintsData = hints
+			r := NewCommonReconciler(cfg, "test")
+
+			workspace := &pwv1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-workspace", Namespace: "project-test-project"},
+				Spec:       pwv1alpha1.WorkspaceSpec{ProviderHints: tC.providerHints},
+			}
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "project-test-project--ws-test-workspace", Annotations: tC.existingAnnotations}}
+			if ns.Annotations == nil {
+				ns.Annotations = map[string]string{}
+			}
+
+			require.NoError(t, r.applyProviderHintAnnotations(newContext(), workspace, ns))
+			assert.Equal(t, tC.expectedAnnotations, ns.Annotations)
+		})
+	}
+}
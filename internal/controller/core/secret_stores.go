@@ -0,0 +1,96 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/api/v2/metadata"
+)
+
+// applySecretStoreAnnotations sets an annotation for each secret store reference of the given project on the given namespace.
+// The annotations of configured stores which the project does not reference are removed.
+// References to stores which are not configured, or to paths which are not allowed for the project, are skipped.
+// The webhook rejects such references, so they only occur if the configuration has changed afterwards.
+func (r *CommonReconciler) applySecretStoreAnnotations(ctx context.Context, project *pwv1alpha1.Project, ns *corev1.Namespace) error {
+	log := logging.FromContextOrPanic(ctx)
+
+	stores, err := r.Config.SecretStores(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get secret stores from config: %w", err)
+	}
+	for _, store := range stores {
+		idx := slices.IndexFunc(project.Spec.SecretStores, func(ref pwv1alpha1.SecretStoreReference) bool {
+			return ref.Name == store.Name
+		})
+		if idx < 0 {
+			delete(ns.Annotations, store.AnnotationKey())
+			continue
+		}
+		path := project.Spec.SecretStores[idx].Path
+		allowed, err := store.AllowsPath(project.Name, path)
+		if err != nil {
+			return fmt.Errorf("failed to check path of secret store '%s': %w", store.Name, err)
+		}
+		if !allowed {
+			log.Info("Skipping secret store reference with a path which is not allowed", "store", store.Name, "path", path)
+			delete(ns.Annotations, store.AnnotationKey())
+			continue
+		}
+		metadata.SetAnnotation(ns, store.AnnotationKey(), path)
+	}
+	for _, ref := range project.Spec.SecretStores {
+		if !slices.ContainsFunc(stores, func(store pwv1alpha1.SecretStoreConfig) bool { return store.Name == ref.Name }) {
+			log.Info("Skipping reference to secret store which is not configured", "store", ref.Name)
+		}
+	}
+	return nil
+}
+
+// secretStoresChangedPredicate filters for updates of Projects which change the secret store references.
+var secretStoresChangedPredicate = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return false },
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldProject, ok := e.ObjectOld.(*pwv1alpha1.Project)
+		if !ok {
+			return false
+		}
+		newProject, ok := e.ObjectNew.(*pwv1alpha1.Project)
+		if !ok {
+			return false
+		}
+		return !slices.Equal(oldProject.Spec.SecretStores, newProject.Spec.SecretStores)
+	},
+}
+
+// workspacesOfProject returns reconcile requests for all workspaces of the given project.
+// This is required for the annotations and labels of the workspace namespaces to follow changes to the secret store references and pod security levels of the project.
+func (r *WorkspaceReconciler) workspacesOfProject(ctx context.Context, obj client.Object) []ctrl.Request {
+	project, ok := obj.(*pwv1alpha1.Project)
+	if !ok || project.Status.Namespace == "" {
+		return nil
+	}
+
+	workspaces := &pwv1alpha1.WorkspaceList{}
+	if err := r.OnboardingStatic.Client().List(ctx, workspaces, client.InNamespace(project.Status.Namespace)); err != nil {
+		logging.FromContextOrDiscard(ctx).Error(err, "failed to list workspaces of project", "project", project.Name)
+		return nil
+	}
+
+	requests := make([]ctrl.Request, 0, len(workspaces.Items))
+	for _, ws := range workspaces.Items {
+		requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&ws)})
+	}
+	return requests
+}
@@ -0,0 +1,85 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+)
+
+func Test_applySecretStoreAnnotations(t *testing.T) {
+	stores := []pwv1alpha1.SecretStoreConfig{
+		{Name: "vault", PathPrefix: "teams/{{ .Project }}/"},
+		{Name: "aws", Annotation: "secrets.example.com/aws-path"},
+	}
+
+	testCases := []struct {
+		desc                string
+		refs                []pwv1alpha1.SecretStoreReference
+		existingAnnotations map[string]string
+		expectedAnnotations map[string]string
+	}{
+		{
+			desc: "should annotate the namespace with the referenced paths",
+			refs: []pwv1alpha1.SecretStoreReference{
+				{Name: "vault", Path: "teams/test-project/app"},
+				{Name: "aws", Path: "shared/app"},
+			},
+			expectedAnnotations: map[string]string{
+				"secretstore.core.openmcp.cloud/vault": "teams/test-project/app",
+				"secrets.example.com/aws-path":         "shared/app",
+			},
+		},
+		{
+			desc: "should remove annotations of stores which are not referenced anymore",
+			refs: []pwv1alpha1.SecretStoreReference{
+				{Name: "aws", Path: "shared/app"},
+			},
+			existingAnnotations: map[string]string{
+				"secretstore.core.openmcp.cloud/vault": "teams/test-project/app",
+				"other":                                "value",
+			},
+			expectedAnnotations: map[string]string{
+				"secrets.example.com/aws-path": "shared/app",
+				"other":                        "value",
+			},
+		},
+		{
+			desc: "should skip references to stores which are not configured or paths which are not allowed",
+			refs: []pwv1alpha1.SecretStoreReference{
+				{Name: "vault", Path: "teams/other-project/app"},
+				{Name: "unknown", Path: "app"},
+			},
+			existingAnnotations: map[string]string{
+				"secretstore.core.openmcp.cloud/vault": "teams/test-project/app",
+			},
+			expectedAnnotations: map[string]string{},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(Scheme).Build()
+			cfg := sharedconfig.NewFakeSharedInformation(c, nil, nil, nil)
+			cfg.SecretStoresData = stores
+			r := NewCommonReconciler(cfg, "test")
+
+			project := &pwv1alpha1.Project{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-project"},
+				Spec:       pwv1alpha1.ProjectSpec{SecretStores: tC.refs},
+			}
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "project-test-project", Annotations: tC.existingAnnotations}}
+			if ns.Annotations == nil {
+				ns.Annotations = map[string]string{}
+			}
+
+			require.NoError(t, r.applySecretStoreAnnotations(newContext(), project, ns))
+			assert.Equal(t, tC.expectedAnnotations, ns.Annotations)
+		})
+	}
+}
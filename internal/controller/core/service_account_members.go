@@ -0,0 +1,99 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+// rejectedServiceAccountMembers returns the effective members of the given workspace which are ServiceAccounts from namespaces that are not allowed by the config,
+// mapped to the reason why they are rejected.
+// The webhook already rejects such members, but the config or the namespaces might have changed since then, and inherited project members are not validated for workspaces.
+func (r *WorkspaceReconciler) rejectedServiceAccountMembers(ctx context.Context, project *pwv1alpha1.Project, ws *pwv1alpha1.Workspace) (map[pwv1alpha1.Subject]string, error) {
+	policy, err := r.Config.ServiceAccountMembers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ServiceAccount member restrictions from config: %w", err)
+	}
+	if !policy.RestrictToProject {
+		return nil, nil
+	}
+
+	rejected := map[pwv1alpha1.Subject]string{}
+	namespaceProjects := map[string]string{}
+	for _, member := range ws.EffectiveMembers(project) {
+		if member.Kind != rbacv1.ServiceAccountKind {
+			continue
+		}
+		namespaceProject, ok := namespaceProjects[member.Namespace]
+		if !ok {
+			namespaceProject, err = r.projectOfNamespace(ctx, member.Namespace)
+			if err != nil {
+				return nil, err
+			}
+			namespaceProjects[member.Namespace] = namespaceProject
+		}
+		if !policy.AllowsNamespace(member.Namespace, namespaceProject, project.Name) {
+			rejected[member.Subject] = fmt.Sprintf("Namespace '%s' of the ServiceAccount does not belong to project '%s' and is not allowed by the config", member.Namespace, project.Name)
+		}
+	}
+	return rejected, nil
+}
+
+// projectOfNamespace returns the name of the project the given namespace belongs to, or an empty string if it does not exist or does not belong to a project.
+func (r *WorkspaceReconciler) projectOfNamespace(ctx context.Context, name string) (string, error) {
+	namespace := &corev1.Namespace{}
+	if err := r.OnboardingStatic.Client().Get(ctx, types.NamespacedName{Name: name}, namespace); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("error fetching namespace '%s': %w", name, err)
+	}
+	return namespace.Labels[utils.LabelProject], nil
+}
+
+// withoutRejectedMembers returns copies of the given project and workspace without the given rejected members.
+// They are used to generate the RBAC resources, so that rejected members are not bound, neither as workspace members nor as inherited project members.
+func withoutRejectedMembers(project *pwv1alpha1.Project, ws *pwv1alpha1.Workspace, rejected map[pwv1alpha1.Subject]string) (*pwv1alpha1.Project, *pwv1alpha1.Workspace) {
+	if len(rejected) == 0 {
+		return project, ws
+	}
+	project = project.DeepCopy()
+	project.Spec.Members = slices.DeleteFunc(project.Spec.Members, func(m pwv1alpha1.ProjectMember) bool {
+		_, ok := rejected[m.Subject]
+		return ok
+	})
+	ws = ws.DeepCopy()
+	ws.Spec.Members = slices.DeleteFunc(ws.Spec.Members, func(m pwv1alpha1.WorkspaceMember) bool {
+		_, ok := rejected[m.Subject]
+		return ok
+	})
+	return project, ws
+}
+
+// setMembersRejectedCondition sets the MembersRejected condition on the given workspace if any of its members are rejected, and removes it otherwise.
+func setMembersRejectedCondition(ws *pwv1alpha1.Workspace, rejected map[pwv1alpha1.Subject]string) {
+	if len(rejected) == 0 {
+		ws.RemoveCondition(pwv1alpha1.ConditionTypeMembersRejected)
+		return
+	}
+	names := make([]string, 0, len(rejected))
+	for subject := range rejected {
+		names = append(names, fmt.Sprintf("%s/%s", subject.Namespace, subject.Name))
+	}
+	slices.Sort(names)
+	ws.SetOrUpdateCondition(pwv1alpha1.Condition{
+		Type:    pwv1alpha1.ConditionTypeMembersRejected,
+		Status:  pwv1alpha1.ConditionStatusTrue,
+		Reason:  pwv1alpha1.ConditionReasonServiceAccountNamespaceNotAllowed,
+		Message: fmt.Sprintf("ServiceAccounts %s are not bound, because their namespaces do not belong to the project and are not allowed by the config", strings.Join(names, ", ")),
+	})
+}
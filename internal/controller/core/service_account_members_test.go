@@ -0,0 +1,104 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+func Test_WorkspaceReconciler_serviceAccountMembers(t *testing.T) {
+	ws := sampleWorkspace.DeepCopy()
+	serviceAccount := ws.Spec.Members[2].Subject
+	// the namespace of the ServiceAccount does not belong to any project
+	otherNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: serviceAccount.Namespace}}
+
+	c := fake.NewClientBuilder().
+		WithObjects(ws, projectNamespace, sampleProject, otherNamespace).
+		WithStatusSubresource(ws).
+		WithInterceptorFuncs(reviewAccess(allowAll, interceptor.Funcs{})).
+		WithScheme(Scheme).
+		Build()
+	ctx := newContext()
+
+	cfg := sharedconfig.NewFakeSharedInformation(c, nil, nil, nil)
+	cfg.ServiceAccountMembersData = pwv1alpha1.ServiceAccountMembersConfig{RestrictToProject: true}
+	wr, err := NewWorkspaceReconciler(c.Scheme(), NewCommonReconciler(cfg, "test"))
+	require.NoError(t, err)
+
+	reconcileWorkspace := func(t *testing.T) *pwv1alpha1.Workspace {
+		result, err := ctrl.Result{}, error(nil)
+		for range maxReconcileCycles {
+			result, err = wr.Reconcile(ctx, newRequest(ws))
+			if result.RequeueAfter == 0 || err != nil {
+				break
+			}
+		}
+		require.NoError(t, err)
+		res := &pwv1alpha1.Workspace{}
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(ws), res))
+		return res
+	}
+	viewSubjects := func(t *testing.T, ws *pwv1alpha1.Workspace) []rbacv1.Subject {
+		rb := &rbacv1.RoleBinding{}
+		require.NoError(t, c.Get(ctx, client.ObjectKey{Name: utils.RoleBindingForRole(pwv1alpha1.WorkspaceRoleView), Namespace: ws.Status.Namespace}, rb))
+		return rb.Subjects
+	}
+
+	t.Run("does not bind ServiceAccounts from namespaces of other projects", func(t *testing.T) {
+		res := reconcileWorkspace(t)
+
+		assert.NotContains(t, viewSubjects(t, res), serviceAccount.RbacV1())
+		condition := findCondition(res.Status.Conditions, pwv1alpha1.ConditionTypeMembersRejected)
+		if assert.NotNil(t, condition) {
+			assert.Equal(t, pwv1alpha1.ConditionReasonServiceAccountNamespaceNotAllowed, condition.Reason)
+			assert.Contains(t, condition.Message, "default/default")
+		}
+		status := findMemberStatus(res.Status.MemberStatuses, serviceAccount)
+		if assert.NotNil(t, status) {
+			assert.Equal(t, pwv1alpha1.MemberPhaseFailed, status.Phase)
+			assert.Contains(t, status.Message, "is not allowed by the config")
+		}
+	})
+
+	t.Run("binds ServiceAccounts from allowed namespaces", func(t *testing.T) {
+		cfg.ServiceAccountMembersData.AllowedNamespaces = []string{serviceAccount.Namespace}
+		res := reconcileWorkspace(t)
+
+		assert.Contains(t, viewSubjects(t, res), serviceAccount.RbacV1())
+		assert.Nil(t, findCondition(res.Status.Conditions, pwv1alpha1.ConditionTypeMembersRejected))
+		assert.Equal(t, pwv1alpha1.MemberPhaseActive, findMemberStatus(res.Status.MemberStatuses, serviceAccount).Phase)
+	})
+
+	t.Run("binds ServiceAccounts from namespaces of the same project", func(t *testing.T) {
+		cfg.ServiceAccountMembersData.AllowedNamespaces = nil
+		ns := &corev1.Namespace{}
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(otherNamespace), ns))
+		utils.SetProjectLabel(ns, sampleProject.Name)
+		require.NoError(t, c.Update(ctx, ns))
+
+		res := reconcileWorkspace(t)
+		assert.Contains(t, viewSubjects(t, res), serviceAccount.RbacV1())
+		assert.Nil(t, findCondition(res.Status.Conditions, pwv1alpha1.ConditionTypeMembersRejected))
+	})
+}
+
+func findCondition(conditions []pwv1alpha1.Condition, conditionType pwv1alpha1.ConditionType) *pwv1alpha1.Condition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
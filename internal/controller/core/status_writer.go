@@ -0,0 +1,117 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/api/equality"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+)
+
+const (
+	// defaultStatusWriteInterval is the interval in which the status of a single Project or Workspace is written at most, once the burst is used up.
+	defaultStatusWriteInterval = time.Second
+	// defaultStatusWriteBurst is the number of status writes of a single Project or Workspace which are allowed in quick succession.
+	defaultStatusWriteBurst = 5
+)
+
+// StatusWriter persists the status of Projects and Workspaces at the end of a reconciliation.
+// The status is only written if it differs from the one which has been read at the start of the reconciliation,
+// and the writes of each object are rate-limited, so that frequent reconciliations don't cause a write on each of them.
+// A write which exceeds the limit is skipped, the object has to be requeued instead, so that the changes of all reconciliations
+// until then are written at once. It is shared by the project and workspace controllers and is safe for concurrent use.
+type StatusWriter struct {
+	limit rate.Limit
+	burst int
+
+	lock     sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+// NewStatusWriter returns a StatusWriter which writes the status of each object once per interval, after the given burst of writes.
+func NewStatusWriter(interval time.Duration, burst int) *StatusWriter {
+	return &StatusWriter{
+		limit:    rate.Every(interval),
+		burst:    burst,
+		limiters: map[string]*rate.Limiter{},
+	}
+}
+
+// Write updates the status of obj if it differs from the status of original, which must be a copy of obj from the start of the reconciliation.
+// If the status of the object has been written too often recently, nothing is written and the delay after which the next write is allowed is returned.
+func (w *StatusWriter) Write(ctx context.Context, c client.Client, original, obj client.Object) (time.Duration, error) {
+	if original != nil && equality.Semantic.DeepEqual(statusOf(original), statusOf(obj)) {
+		logging.FromContextOrDiscard(ctx).Debug("Skipping status update, because the status did not change")
+		return 0, nil
+	}
+
+	reservation := w.limiterFor(obj).Reserve()
+	if delay := reservation.Delay(); delay > 0 {
+		reservation.Cancel()
+		logging.FromContextOrDiscard(ctx).Debug("Deferring status update, because the status has been updated too often recently", "retryAfter", delay)
+		return delay, nil
+	}
+	if err := c.Status().Update(ctx, obj); err != nil {
+		return 0, fmt.Errorf("failed to update status: %w", err)
+	}
+	return 0, nil
+}
+
+// Forget drops the rate limit of the given object, it should be called once the object has been deleted.
+func (w *StatusWriter) Forget(obj client.Object) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	delete(w.limiters, statusWriterKey(obj))
+}
+
+// limiterFor returns the rate limiter of the given object, it is created if it does not exist yet.
+func (w *StatusWriter) limiterFor(obj client.Object) *rate.Limiter {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	key := statusWriterKey(obj)
+	limiter, ok := w.limiters[key]
+	if !ok {
+		limiter = rate.NewLimiter(w.limit, w.burst)
+		w.limiters[key] = limiter
+	}
+	return limiter
+}
+
+// statusWriterKey returns the key of the rate limiter of the given object, it contains the type, since Projects and Workspaces can have the same name.
+func statusWriterKey(obj client.Object) string {
+	return fmt.Sprintf("%T/%s", obj, client.ObjectKeyFromObject(obj).String())
+}
+
+// statusOf returns the status of the given Project or Workspace, or the object itself for other types, so that they are always written.
+func statusOf(obj client.Object) any {
+	switch o := obj.(type) {
+	case *pwv1alpha1.Project:
+		return o.Status
+	case *pwv1alpha1.Workspace:
+		return o.Status
+	default:
+		return obj
+	}
+}
+
+// persistStatus writes the status of obj with the status writer and returns the given result, which is requeued in time
+// for the next write if the current one has been deferred. Errors are only logged, since the status is written again on the next reconciliation.
+func (r *CommonReconciler) persistStatus(ctx context.Context, c client.Client, original, obj client.Object, rr ctrl.Result) ctrl.Result {
+	delay, err := r.status.Write(ctx, c, original, obj)
+	if err != nil {
+		logging.FromContextOrPanic(ctx).Error(err, "failed to update status")
+		return rr
+	}
+	if delay > 0 && (rr.RequeueAfter == 0 || delay < rr.RequeueAfter) {
+		rr.RequeueAfter = delay
+	}
+	return rr
+}
@@ -0,0 +1,109 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+)
+
+func TestStatusWriter(t *testing.T) {
+	ctx := context.Background()
+
+	newClient := func(updates *int, objs ...client.Object) client.Client {
+		return fake.NewClientBuilder().
+			WithScheme(Scheme).
+			WithObjects(objs...).
+			WithStatusSubresource(objs...).
+			WithInterceptorFuncs(interceptor.Funcs{
+				SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+					*updates++
+					return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+				},
+			}).
+			Build()
+	}
+	getProject := func(t *testing.T, c client.Client, name string) *pwv1alpha1.Project {
+		t.Helper()
+		project := &pwv1alpha1.Project{}
+		require.NoError(t, c.Get(ctx, client.ObjectKey{Name: name}, project))
+		return project
+	}
+
+	t.Run("skips the update if the status did not change", func(t *testing.T) {
+		var updates int
+		c := newClient(&updates, &pwv1alpha1.Project{ObjectMeta: metav1.ObjectMeta{Name: "foo"}, Status: pwv1alpha1.ProjectStatus{Namespace: "project-foo"}})
+		w := NewStatusWriter(time.Hour, 1)
+
+		project := getProject(t, c, "foo")
+		delay, err := w.Write(ctx, c, project.DeepCopy(), project)
+		require.NoError(t, err)
+		assert.Zero(t, delay)
+		assert.Zero(t, updates)
+	})
+
+	t.Run("updates a changed status", func(t *testing.T) {
+		var updates int
+		c := newClient(&updates, &pwv1alpha1.Project{ObjectMeta: metav1.ObjectMeta{Name: "foo"}})
+		w := NewStatusWriter(time.Hour, 1)
+
+		project := getProject(t, c, "foo")
+		original := project.DeepCopy()
+		project.Status.Namespace = "project-foo"
+		delay, err := w.Write(ctx, c, original, project)
+		require.NoError(t, err)
+		assert.Zero(t, delay)
+		assert.Equal(t, 1, updates)
+		assert.Equal(t, "project-foo", getProject(t, c, "foo").Status.Namespace)
+	})
+
+	t.Run("defers updates of an object which exceed the rate limit", func(t *testing.T) {
+		var updates int
+		c := newClient(&updates,
+			&pwv1alpha1.Project{ObjectMeta: metav1.ObjectMeta{Name: "foo"}},
+			&pwv1alpha1.Project{ObjectMeta: metav1.ObjectMeta{Name: "bar"}},
+		)
+		w := NewStatusWriter(time.Hour, 1)
+
+		project := getProject(t, c, "foo")
+		original := project.DeepCopy()
+		project.Status.Namespace = "project-foo"
+		_, err := w.Write(ctx, c, original, project)
+		require.NoError(t, err)
+
+		original = project.DeepCopy()
+		project.Status.Namespace = "project-foo-2"
+		delay, err := w.Write(ctx, c, original, project)
+		require.NoError(t, err)
+		assert.InDelta(t, time.Hour, delay, float64(time.Minute))
+		assert.Equal(t, 1, updates)
+		assert.Equal(t, "project-foo", getProject(t, c, "foo").Status.Namespace)
+
+		// other objects have their own limit
+		other := getProject(t, c, "bar")
+		original = other.DeepCopy()
+		other.Status.Namespace = "project-bar"
+		delay, err = w.Write(ctx, c, original, other)
+		require.NoError(t, err)
+		assert.Zero(t, delay)
+		assert.Equal(t, 2, updates)
+
+		// the limit is reset once the object is gone
+		w.Forget(project)
+		project = getProject(t, c, "foo")
+		original = project.DeepCopy()
+		project.Status.Namespace = "project-foo-2"
+		delay, err = w.Write(ctx, c, original, project)
+		require.NoError(t, err)
+		assert.Zero(t, delay)
+		assert.Equal(t, 3, updates)
+	})
+}
@@ -0,0 +1,41 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+// maxSubjectsInEvent limits the number of added and removed subjects which are listed in an event each,
+// since the note of an event must not exceed 1kB.
+const maxSubjectsInEvent = 10
+
+// recordSubjectChanges records an event on the given Project or Workspace which lists the subjects that have been added to
+// and removed from the given ClusterRoleBinding, so that membership changes can be verified on the object itself.
+// Nothing is recorded if the recorder is nil or the subjects did not change.
+func recordSubjectChanges(recorder events.EventRecorder, owner client.Object, binding *rbacv1.ClusterRoleBinding, previous []rbacv1.Subject) {
+	if recorder == nil {
+		return
+	}
+	added, removed := utils.DiffSubjects(previous, binding.Subjects)
+	if len(added) == 0 && len(removed) == 0 {
+		return
+	}
+	recorder.Eventf(owner, binding, corev1.EventTypeNormal, pwv1alpha1.EventReasonSubjectsChanged, "UpdateClusterRoleBinding",
+		"Subjects of ClusterRoleBinding '%s' changed: added [%s], removed [%s]", binding.Name, joinSubjects(added), joinSubjects(removed))
+}
+
+// joinSubjects joins the given subjects, listing at most maxSubjectsInEvent of them.
+func joinSubjects(subjects []string) string {
+	if len(subjects) <= maxSubjectsInEvent {
+		return strings.Join(subjects, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(subjects[:maxSubjectsInEvent], ", "), len(subjects)-maxSubjectsInEvent)
+}
@@ -0,0 +1,62 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+)
+
+func Test_recordSubjectChanges(t *testing.T) {
+	user := func(name string) rbacv1.Subject {
+		return rbacv1.Subject{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: name}
+	}
+	binding := func(subjects ...rbacv1.Subject) *rbacv1.ClusterRoleBinding {
+		return &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "openmcp:project:test:admin"}, Subjects: subjects}
+	}
+	recorded := func(recorder *events.FakeRecorder) []string {
+		close(recorder.Events)
+		res := []string{}
+		for e := range recorder.Events {
+			res = append(res, e)
+		}
+		return res
+	}
+
+	t.Run("should list the added and removed subjects", func(t *testing.T) {
+		recorder := events.NewFakeRecorder(10)
+		recordSubjectChanges(recorder, sampleProject, binding(user("jane"), user("john")), []rbacv1.Subject{user("jane"), user("max")})
+		assert.Equal(t, []string{
+			"Normal SubjectsChanged Subjects of ClusterRoleBinding 'openmcp:project:test:admin' changed: added [User:john], removed [User:max]",
+		}, recorded(recorder))
+	})
+
+	t.Run("should not record an event if only the order changed", func(t *testing.T) {
+		recorder := events.NewFakeRecorder(10)
+		recordSubjectChanges(recorder, sampleProject, binding(user("john"), user("jane")), []rbacv1.Subject{user("jane"), user("john")})
+		assert.Empty(t, recorded(recorder))
+	})
+
+	t.Run("should limit the number of listed subjects", func(t *testing.T) {
+		recorder := events.NewFakeRecorder(10)
+		subjects := []rbacv1.Subject{}
+		for i := range maxSubjectsInEvent + 3 {
+			subjects = append(subjects, user(fmt.Sprintf("user-%02d", i)))
+		}
+		recordSubjectChanges(recorder, sampleProject, binding(subjects...), nil)
+		recordedEvents := recorded(recorder)
+		if assert.Len(t, recordedEvents, 1) {
+			assert.Contains(t, recordedEvents[0], "User:user-09 and 3 more], removed []")
+			assert.NotContains(t, recordedEvents[0], "user-10")
+		}
+	})
+
+	t.Run("should do nothing without a recorder", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			recordSubjectChanges(nil, sampleProject, binding(user("jane")), nil)
+		})
+	})
+}
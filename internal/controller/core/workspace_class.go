@@ -0,0 +1,175 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	pwoerrors "github.com/openmcp-project/platform-service-project-workspace/internal/errors"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/metrics"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+// WorkspaceClassResourceQuotaName is the name of the ResourceQuota that is created in the namespace of a workspace whose class defines a quota.
+const WorkspaceClassResourceQuotaName = "workspace-class"
+
+// +kubebuilder:rbac:groups=core.openmcp.cloud,resources=workspaceclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=get;list;watch;create;update;patch;delete;escalate;bind
+
+// getWorkspaceClass returns the WorkspaceClass selected by the given workspace, or nil if the workspace does not select a class.
+// A class which does not exist results in a terminal error, the workspace is reconciled again once the class is created.
+func (r *CommonReconciler) getWorkspaceClass(ctx context.Context, ws *pwv1alpha1.Workspace) (*pwv1alpha1.WorkspaceClass, error) {
+	if ws.Spec.ClassName == "" {
+		return nil, nil
+	}
+
+	onboardingCluster, err := r.Config.OnboardingClusterStatic(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get onboarding cluster access: %w", err)
+	}
+
+	class := &pwv1alpha1.WorkspaceClass{}
+	if err := onboardingCluster.Client().Get(ctx, client.ObjectKey{Name: ws.Spec.ClassName}, class); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, pwoerrors.NewTerminalError(fmt.Errorf("WorkspaceClass '%s' does not exist: %w", ws.Spec.ClassName, err))
+		}
+		return nil, fmt.Errorf("failed to get WorkspaceClass '%s': %w", ws.Spec.ClassName, err)
+	}
+	return class, nil
+}
+
+// workspaceClassRoleName returns the name of the Role and RoleBinding which grant the additional permissions of the workspace class to the members of the given workspace with the given role.
+func workspaceClassRoleName(ws *pwv1alpha1.Workspace, role pwv1alpha1.WorkspaceMemberRole) string {
+	return workspaceScopedName(ws, fmt.Sprintf("%s-class", utils.RoleBindingForRole(role)))
+}
+
+// reconcileWorkspaceClassQuota creates or updates the ResourceQuota of the given workspace class in the workspace namespace.
+// If the workspace has no class or the class does not define a quota, a previously created ResourceQuota is deleted.
+// Flat workspaces share the namespace of their project, so the quota is not applied to them.
+func (r *WorkspaceReconciler) reconcileWorkspaceClassQuota(ctx context.Context, ws *pwv1alpha1.Workspace, class *pwv1alpha1.WorkspaceClass) error {
+	log := logging.FromContextOrPanic(ctx)
+
+	if ws.IsFlat() {
+		return nil
+	}
+
+	quota := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      WorkspaceClassResourceQuotaName,
+			Namespace: ws.Status.Namespace,
+		},
+	}
+
+	if class == nil || len(class.Spec.ResourceQuota) == 0 {
+		if _, err := r.deleteIfManaged(ctx, r.OnboardingStatic.Client(), quota); err != nil {
+			return fmt.Errorf("failed to delete workspace class ResourceQuota: %w", err)
+		}
+		return nil
+	}
+
+	result, err := controllerutil.CreateOrUpdate(ctx, r.OnboardingStatic.Client(), quota, func() error {
+		if err := r.applyManagementLabel(ctx, quota); err != nil {
+			return err
+		}
+		quota.Spec.Hard = class.Spec.ResourceQuota.DeepCopy()
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create or update workspace class ResourceQuota: %w", err)
+	}
+	utils.LogOperationResult(log, logging.INFO, quota, result)
+
+	return nil
+}
+
+// applyWorkspaceClassRoles creates or updates a Role and RoleBinding in the workspace namespace for each workspace role the given class defines additional permissions for.
+// The Roles and RoleBindings of roles without additional permissions are deleted, which includes all of them if the workspace has no class.
+func (r *WorkspaceReconciler) applyWorkspaceClassRoles(ctx context.Context, project *pwv1alpha1.Project, ws *pwv1alpha1.Workspace, class *pwv1alpha1.WorkspaceClass) error {
+	log := logging.FromContextOrPanic(ctx)
+
+	for _, role := range []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleAdmin, pwv1alpha1.WorkspaceRoleView, pwv1alpha1.WorkspaceRoleAuditor} {
+		objMeta := metav1.ObjectMeta{
+			Name:      workspaceClassRoleName(ws, role),
+			Namespace: ws.Status.Namespace,
+		}
+		rbacRole := &rbacv1.Role{ObjectMeta: objMeta}
+		roleBinding := &rbacv1.RoleBinding{ObjectMeta: *objMeta.DeepCopy()}
+
+		var rules []rbacv1.PolicyRule
+		if class != nil {
+			rules = class.Spec.AdditionalPermissions[role]
+		}
+		if len(rules) == 0 {
+			if _, err := r.deleteIfManaged(ctx, r.OnboardingStatic.Client(), roleBinding); err != nil {
+				return fmt.Errorf("failed to delete workspace class RoleBinding '%s': %w", roleBinding.Name, err)
+			}
+			if _, err := r.deleteIfManaged(ctx, r.OnboardingStatic.Client(), rbacRole); err != nil {
+				return fmt.Errorf("failed to delete workspace class Role '%s': %w", rbacRole.Name, err)
+			}
+			continue
+		}
+
+		result, err := controllerutil.CreateOrUpdate(ctx, r.OnboardingStatic.Client(), rbacRole, func() error {
+			if err := r.applyManagementLabel(ctx, rbacRole); err != nil {
+				return err
+			}
+			utils.SetOwnerUIDLabel(rbacRole, ws)
+			utils.SetRulesIfChanged(&rbacRole.Rules, rules)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create or update workspace class Role '%s': %w", rbacRole.Name, err)
+		}
+		utils.LogOperationResult(log, logging.INFO, rbacRole, result)
+		metrics.RecordRBACUpdate(rbacRole, result)
+
+		result, err = controllerutil.CreateOrUpdate(ctx, r.OnboardingStatic.Client(), roleBinding, func() error {
+			if err := r.applyManagementLabel(ctx, roleBinding); err != nil {
+				return err
+			}
+			utils.SetOwnerUIDLabel(roleBinding, ws)
+			utils.SetSubjectsIfChanged(&roleBinding.Subjects, getSubjectsForWorkspaceRole(project, ws, role))
+			roleBinding.RoleRef = rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "Role",
+				Name:     rbacRole.Name,
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create or update workspace class RoleBinding '%s': %w", roleBinding.Name, err)
+		}
+		utils.LogOperationResult(log, logging.INFO, roleBinding, result)
+		metrics.RecordRBACUpdate(roleBinding, result)
+	}
+
+	return nil
+}
+
+// workspacesOfClass returns reconcile requests for all workspaces which select the given WorkspaceClass.
+// This is required for the workspaces to follow changes to their class.
+func (r *WorkspaceReconciler) workspacesOfClass(ctx context.Context, obj client.Object) []ctrl.Request {
+	workspaces := &pwv1alpha1.WorkspaceList{}
+	if err := r.OnboardingStatic.Client().List(ctx, workspaces); err != nil {
+		logging.FromContextOrDiscard(ctx).Error(err, "failed to list workspaces of class", "workspaceClass", obj.GetName())
+		return nil
+	}
+
+	requests := []ctrl.Request{}
+	for _, ws := range workspaces.Items {
+		if ws.Spec.ClassName == obj.GetName() {
+			requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&ws)})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,109 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+	pwoerrors "github.com/openmcp-project/platform-service-project-workspace/internal/errors"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+func Test_WorkspaceReconciler_workspaceClass(t *testing.T) {
+	class := &pwv1alpha1.WorkspaceClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "small"},
+		Spec: pwv1alpha1.WorkspaceClassSpec{
+			ResourceQuota: corev1.ResourceList{
+				corev1.ResourcePods: resource.MustParse("10"),
+			},
+			AdditionalPermissions: map[pwv1alpha1.WorkspaceMemberRole][]rbacv1.PolicyRule{
+				pwv1alpha1.WorkspaceRoleAdmin: {
+					{APIGroups: []string{"example.com"}, Resources: []string{"backups"}, Verbs: []string{"*"}},
+				},
+			},
+		},
+	}
+	ws := sampleWorkspace.DeepCopy()
+	ws.Spec.ClassName = class.Name
+	ws.Status.Namespace = utils.NamespaceForWorkspace(ws)
+	otherWs := sampleWorkspace.DeepCopy()
+	otherWs.Name = "other"
+
+	c := fake.NewClientBuilder().
+		WithObjects(projectNamespace, class, ws, otherWs).
+		WithScheme(Scheme).
+		Build()
+	ctx := newContext()
+
+	cfg := sharedconfig.NewFakeSharedInformation(c, nil, nil, nil)
+	wr, err := NewWorkspaceReconciler(c.Scheme(), NewCommonReconciler(cfg, "test"))
+	require.NoError(t, err)
+
+	t.Run("returns the selected class", func(t *testing.T) {
+		got, err := wr.getWorkspaceClass(ctx, ws)
+		require.NoError(t, err)
+		assert.Equal(t, class.Name, got.Name)
+
+		got, err = wr.getWorkspaceClass(ctx, otherWs)
+		require.NoError(t, err)
+		assert.Nil(t, got, "workspaces without class must not get one")
+	})
+
+	t.Run("returns a terminal error if the class does not exist", func(t *testing.T) {
+		missing := ws.DeepCopy()
+		missing.Spec.ClassName = "does-not-exist"
+		_, err := wr.getWorkspaceClass(ctx, missing)
+		assert.True(t, pwoerrors.IsTerminal(err))
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+
+	t.Run("creates and deletes the ResourceQuota of the class", func(t *testing.T) {
+		require.NoError(t, wr.reconcileWorkspaceClassQuota(ctx, ws, class))
+
+		quota := &corev1.ResourceQuota{}
+		require.NoError(t, c.Get(ctx, client.ObjectKey{Name: WorkspaceClassResourceQuotaName, Namespace: ws.Status.Namespace}, quota))
+		assert.True(t, quota.Spec.Hard.Pods().Equal(resource.MustParse("10")))
+
+		require.NoError(t, wr.reconcileWorkspaceClassQuota(ctx, ws, nil))
+		err := c.Get(ctx, client.ObjectKeyFromObject(quota), &corev1.ResourceQuota{})
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+
+	t.Run("creates and deletes the Roles of the class", func(t *testing.T) {
+		require.NoError(t, wr.applyWorkspaceClassRoles(ctx, sampleProject, ws, class))
+
+		role := &rbacv1.Role{}
+		require.NoError(t, c.Get(ctx, client.ObjectKey{Name: workspaceClassRoleName(ws, pwv1alpha1.WorkspaceRoleAdmin), Namespace: ws.Status.Namespace}, role))
+		assert.Equal(t, class.Spec.AdditionalPermissions[pwv1alpha1.WorkspaceRoleAdmin], role.Rules)
+
+		roleBinding := &rbacv1.RoleBinding{}
+		require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(role), roleBinding))
+		assert.Equal(t, "Role", roleBinding.RoleRef.Kind)
+		assert.Equal(t, role.Name, roleBinding.RoleRef.Name)
+		assert.ElementsMatch(t, getSubjectsForWorkspaceRole(sampleProject, ws, pwv1alpha1.WorkspaceRoleAdmin), roleBinding.Subjects)
+
+		err := c.Get(ctx, client.ObjectKey{Name: workspaceClassRoleName(ws, pwv1alpha1.WorkspaceRoleView), Namespace: ws.Status.Namespace}, &rbacv1.Role{})
+		assert.True(t, apierrors.IsNotFound(err), "roles without additional permissions must not get a Role")
+
+		require.NoError(t, wr.applyWorkspaceClassRoles(ctx, sampleProject, ws, nil))
+		err = c.Get(ctx, client.ObjectKeyFromObject(role), &rbacv1.Role{})
+		assert.True(t, apierrors.IsNotFound(err))
+		err = c.Get(ctx, client.ObjectKeyFromObject(roleBinding), &rbacv1.RoleBinding{})
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+
+	t.Run("maps the class to its workspaces", func(t *testing.T) {
+		assert.Equal(t, []reconcile.Request{newRequest(ws)}, wr.workspacesOfClass(ctx, class))
+	})
+}
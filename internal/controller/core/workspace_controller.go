@@ -7,16 +7,20 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/openmcp-project/controller-utils/pkg/clusters"
 	ctrlutils "github.com/openmcp-project/controller-utils/pkg/controller"
@@ -24,20 +28,38 @@ import (
 	apiconst "github.com/openmcp-project/openmcp-operator/api/constants"
 
 	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/api/v2/metadata"
+	pwoerrors "github.com/openmcp-project/platform-service-project-workspace/internal/errors"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/logconfig"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/metrics"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/projectresolver"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/shutdown"
 	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
 )
 
-const WorkspaceControllerName = "workspace"
+const (
+	WorkspaceControllerName = "workspace"
+	// WorkspaceDeletionControllerName is the name of the controller handling Workspaces in deletion.
+	WorkspaceDeletionControllerName = "workspace-deletion"
+)
 
 var (
-	ErrNamespaceHasNoLabels       = errors.New("namespace has no labels, map is nil")
-	ErrNamespaceHasNoProjectLabel = errors.New("namespace has no project label")
+	ErrNamespaceHasNoLabels       = projectresolver.ErrNamespaceHasNoLabels
+	ErrNamespaceHasNoProjectLabel = projectresolver.ErrNamespaceHasNoProjectLabel
 )
 
 // WorkspaceReconciler reconciles a Workspace object
 type WorkspaceReconciler struct {
 	OnboardingStatic *clusters.Cluster
 	Scheme           *runtime.Scheme
+	// ConfigChanges optionally receives an event for each Workspace which needs to be reconciled because the configuration changed.
+	ConfigChanges <-chan event.GenericEvent
+	// Recorder is used to record events for Workspaces, e.g. when a resource type blocking the deletion has been skipped.
+	// If nil, SetupWithManager uses the event recorder of the manager.
+	Recorder events.EventRecorder
+	// Projects resolves the Project a Workspace belongs to via the label of its namespace.
+	// If nil, SetupWithManager uses a resolver which reads from the cache of the manager, Reconcile reads from OnboardingStatic without it.
+	Projects *projectresolver.Resolver
 	*CommonReconciler
 }
 
@@ -59,6 +81,7 @@ func NewWorkspaceReconciler(scheme *runtime.Scheme, cr *CommonReconciler) (*Work
 // +kubebuilder:rbac:groups=core.openmcp.cloud,resources=workspaces,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core.openmcp.cloud,resources=workspaces/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=core.openmcp.cloud,resources=workspaces/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=resourcequotas,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -73,7 +96,7 @@ func (r *WorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	return rr, err
 }
 
-func (r *WorkspaceReconciler) reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *WorkspaceReconciler) reconcile(ctx context.Context, req ctrl.Request) (rr ctrl.Result, err error) {
 	log := logging.FromContextOrPanic(ctx)
 
 	workspace := &pwv1alpha1.Workspace{}
@@ -86,10 +109,13 @@ func (r *WorkspaceReconciler) reconcile(ctx context.Context, req ctrl.Request) (
 	if err := r.OnboardingStatic.Client().Get(ctx, req.NamespacedName, workspace); err != nil {
 		if apierrors.IsNotFound(err) {
 			log.Info("Workspace not found")
+			r.status.Forget(workspace)
 			return sr.StopRequeue()
 		}
-		return sr.ReturnError(fmt.Errorf("error fetching workspace: %w", err))
+		return reconcileResult(WorkspaceControllerName, sr, workspace, fmt.Errorf("error fetching workspace: %w", err))
 	}
+	// the status is only written if it differs from the one read here
+	original := workspace.DeepCopy()
 
 	// handle operation annotation
 	if workspace.GetAnnotations() != nil {
@@ -102,7 +128,7 @@ func (r *WorkspaceReconciler) reconcile(ctx context.Context, req ctrl.Request) (
 			case apiconst.OperationAnnotationValueReconcile:
 				log.Debug("Removing reconcile operation annotation from resource")
 				if err := ctrlutils.EnsureAnnotation(ctx, r.OnboardingStatic.Client(), workspace, apiconst.OperationAnnotation, "", true, ctrlutils.DELETE); err != nil {
-					return sr.ReturnError(fmt.Errorf("error removing operation annotation: %w", err))
+					return reconcileResult(WorkspaceControllerName, sr, workspace, fmt.Errorf("error removing operation annotation: %w", err))
 				}
 			}
 		}
@@ -111,7 +137,7 @@ func (r *WorkspaceReconciler) reconcile(ctx context.Context, req ctrl.Request) (
 	project, err := r.getProjectByNamespace(ctx, workspace.Namespace)
 	if err != nil {
 		log.Error(err, "unable to fetch Project of Workspace")
-		return sr.ReturnError(err)
+		return reconcileResult(WorkspaceControllerName, sr, workspace, err)
 	}
 
 	workspaceNamespace := &corev1.Namespace{
@@ -122,120 +148,221 @@ func (r *WorkspaceReconciler) reconcile(ctx context.Context, req ctrl.Request) (
 
 	// Check if there are remaining resources in the namespace that are blocking the deletion of the Workspace
 	// If the workspace is not it deletion, this will return false
-	hasRemainingContent, err := r.handleRemainingContentBeforeDelete(ctx, workspace)
+	hasRemainingContent, err := r.handleRemainingContentBeforeDelete(ctx, workspace, r.Recorder)
 	if err != nil {
-		return sr.ReturnError(err)
+		return reconcileResult(WorkspaceControllerName, sr, workspace, err)
 	}
 	if hasRemainingContent {
-		if err := r.OnboardingStatic.Client().Status().Update(ctx, workspace); err != nil {
-			log.Error(err, "failed to update status")
-		}
-
-		return sr.IsStable() // naming is unintuitive, this requeues with increasing backoff
+		rr, err := cleanupResult(ctx, WorkspaceControllerName, sr, workspace, cleanupBlocked("resources in namespace "+workspaceNamespace.Name))
+		return r.persistStatus(ctx, r.OnboardingStatic.Client(), original, workspace, rr), err
 	}
 
-	deleted, rqt, err := r.handleDelete(ctx, workspace, func() error {
-		if err := r.OnboardingStatic.Client().Delete(ctx, workspaceNamespace); err != nil {
-			return client.IgnoreNotFound(err)
+	deleted, cleanup, err := r.handleDelete(ctx, workspace, func() (CleanupResult, error) {
+		if workspace.IsFlat() {
+			if err := r.deleteFlatWorkspace(ctx, project, workspace); err != nil {
+				return CleanupResult{}, err
+			}
+			return cleanupDone(), nil
 		}
-		if err := r.deleteClusterRole(ctx, project, workspace); err != nil {
-			return err
+		deleted, nsErr := r.deleteNamespaceIfOwned(ctx, r.Recorder, workspace, workspaceNamespace.Name, project.Name, workspace.Name)
+		if client.IgnoreNotFound(nsErr) != nil {
+			return CleanupResult{}, nsErr
+		}
+		if nsErr == nil && !deleted {
+			// the namespace might contain someone else's data now, it has to be checked and cleaned up manually
+			return cleanupBlocked("namespace " + workspaceNamespace.Name + " with altered labels"), nil
+		}
+		// the RBAC resources are deleted even if the namespace is already gone, they would be orphaned otherwise
+		if err := r.deleteWorkspaceResources(ctx, project, workspace); err != nil {
+			return CleanupResult{}, err
+		}
+		if apierrors.IsNotFound(nsErr) {
+			return cleanupDone(), nil
 		}
 
-		return ResourcesRemainingError{}
+		// the finalizer is removed once the namespace is gone
+		return cleanupBlocked("namespace " + workspaceNamespace.Name), nil
 	})
-	if deleted || err != nil {
-		switch rqt {
-		case RequeueError:
-			return sr.ReturnError(err)
-		case RequeueWithMinInterval:
-			return sr.IsProgressing()
-		case RequeueWithBackoff:
-			return sr.IsStable()
-		default:
-			return sr.StopRequeue()
-		}
+	if err != nil {
+		return reconcileResult(WorkspaceControllerName, sr, workspace, err)
+	}
+	if deleted {
+		return cleanupResult(ctx, WorkspaceControllerName, sr, workspace, cleanup)
 	}
 
 	if err := r.ensureFinalizer(ctx, workspace); err != nil {
-		return sr.ReturnError(err)
+		return reconcileResult(WorkspaceControllerName, sr, workspace, err)
 	}
 
-	// Always update status
+	// Update the status if it changed
 	defer func() {
-		if err := r.OnboardingStatic.Client().Status().Update(ctx, workspace); err != nil {
-			log.Error(err, "failed to update status")
-		}
+		rr = r.persistStatus(ctx, r.OnboardingStatic.Client(), original, workspace, rr)
 	}()
 
+	//
+	// Workspace class
+	//
+
+	class, err := r.getWorkspaceClass(ctx, workspace)
+	if err != nil {
+		return reconcileResult(WorkspaceControllerName, sr, workspace, err)
+	}
+
 	//
 	// Namespace Creation
 	//
 
-	result, err := controllerutil.CreateOrUpdate(ctx, r.OnboardingStatic.Client(), workspaceNamespace, func() error {
-		utils.SetWorkspaceLabel(workspaceNamespace, workspace.Name)
-		utils.SetProjectLabel(workspaceNamespace, project.Name)
-		r.applyManagementLabel(workspaceNamespace)
-		return nil
-	})
+	flat, err := r.isFlat(ctx, workspace, class)
 	if err != nil {
-		return sr.ReturnError(err)
+		return reconcileResult(WorkspaceControllerName, sr, workspace, err)
+	}
+	if flat {
+		// flat workspaces share the namespace of their project
+		workspace.Status.Namespace = workspace.Namespace
+	} else if err := r.createOrUpdateNamespace(ctx, project, workspace, class, workspaceNamespace); err != nil {
+		return reconcileResult(WorkspaceControllerName, sr, workspace, err)
 	}
-	utils.LogOperationResult(log, logging.INFO, workspaceNamespace, result)
 
-	workspace.Status.Namespace = workspaceNamespace.Name
+	//
+	// Hibernation and quotas
+	//
+
+	if err := r.reconcileHibernation(ctx, workspace); err != nil {
+		return reconcileResult(WorkspaceControllerName, sr, workspace, err)
+	}
+	if err := r.reconcileWorkspaceClassQuota(ctx, workspace, class); err != nil {
+		return reconcileResult(WorkspaceControllerName, sr, workspace, err)
+	}
+
+	//
+	// Network policies
+	//
+
+	if !flat {
+		// NetworkPolicies apply to the whole namespace, so they are not created for flat workspaces
+		if err := r.reconcileNetworkPolicies(ctx, project, workspace); err != nil {
+			return reconcileResult(WorkspaceControllerName, sr, workspace, err)
+		}
+	}
+
+	//
+	// Additional resources
+	//
+
+	if !flat {
+		// the additional resources are named per namespace, so they are not created for flat workspaces
+		if err := r.reconcileAdditionalResources(ctx, project, workspace); err != nil {
+			return reconcileResult(WorkspaceControllerName, sr, workspace, err)
+		}
+	}
 
 	//
 	// Role bindings
 	//
 
-	if err := r.createOrUpdateClusterRole(ctx, project, workspace); err != nil {
-		return sr.ReturnError(err)
+	rejected, err := r.rejectedServiceAccountMembers(ctx, project, workspace)
+	if err != nil {
+		return reconcileResult(WorkspaceControllerName, sr, workspace, err)
 	}
-	if err := r.createOrUpdateRoleBinding(ctx, workspace, pwv1alpha1.WorkspaceRoleAdmin); err != nil {
-		return sr.ReturnError(err)
+	setMembersRejectedCondition(workspace, rejected)
+	rbacProject, rbacWorkspace := withoutRejectedMembers(project, workspace, rejected)
+
+	rbacErr := r.applyRoleBindings(ctx, rbacProject, rbacWorkspace, class)
+	if err := r.updateMemberStatuses(ctx, project, workspace, rejected, rbacErr); err != nil {
+		return reconcileResult(WorkspaceControllerName, sr, workspace, err)
 	}
-	if err := r.createOrUpdateRoleBinding(ctx, workspace, pwv1alpha1.WorkspaceRoleView); err != nil {
-		return sr.ReturnError(err)
+	if rbacErr != nil {
+		setAccessNotGranted(workspace, rbacErr)
+		return reconcileResult(WorkspaceControllerName, sr, workspace, rbacErr)
 	}
 
-	return sr.StopRequeue()
+	//
+	// Readiness
+	//
+
+	return reconcileResult(WorkspaceControllerName, sr, workspace, r.verifyAccess(ctx, workspace))
 }
 
-func (r *WorkspaceReconciler) getProjectByNamespace(ctx context.Context, namespaceName string) (*pwv1alpha1.Project, error) {
-	namespace := &corev1.Namespace{}
-	if err := r.OnboardingStatic.Client().Get(ctx, types.NamespacedName{Name: namespaceName}, namespace); err != nil {
-		return nil, err
-	}
+// createOrUpdateNamespace creates or updates the dedicated namespace of the given workspace and sets it in the workspace's status.
+// The labels of the given class (may be nil) are set on the namespace, but cannot override the ones required by the platform service,
+// which include the Pod Security Admission labels if pod security is configured.
+func (r *WorkspaceReconciler) createOrUpdateNamespace(ctx context.Context, project *pwv1alpha1.Project, workspace *pwv1alpha1.Workspace, class *pwv1alpha1.WorkspaceClass, workspaceNamespace *corev1.Namespace) error {
+	log := logging.FromContextOrPanic(ctx)
 
-	if namespace.Labels == nil {
-		return nil, ErrNamespaceHasNoLabels
+	// unlike the project namespace, the workspace namespace cannot be owned by its workspace, because the workspace is namespaced
+	result, err := controllerutil.CreateOrUpdate(ctx, r.OnboardingStatic.Client(), workspaceNamespace, func() error {
+		if class != nil {
+			// set first, so that the labels of the class can't override the ones required by the platform service
+			metadata.EnsureLabels(workspaceNamespace, class.Spec.NamespaceLabels)
+		}
+		utils.SetWorkspaceLabel(workspaceNamespace, workspace.Name)
+		utils.SetProjectLabel(workspaceNamespace, project.Name)
+		utils.SetOwnerUIDLabel(workspaceNamespace, workspace)
+		if err := r.applyManagementLabel(ctx, workspaceNamespace); err != nil {
+			return err
+		}
+		if workspace.Spec.Hibernated {
+			metadata.SetAnnotation(workspaceNamespace, pwv1alpha1.HibernatedAnnotation, "true")
+		} else {
+			delete(workspaceNamespace.Annotations, pwv1alpha1.HibernatedAnnotation)
+		}
+		if err := r.applyPodSecurityLabels(ctx, project, class, workspaceNamespace); err != nil {
+			return err
+		}
+		if err := r.applySecretStoreAnnotations(ctx, project, workspaceNamespace); err != nil {
+			return err
+		}
+		return r.applyProviderHintAnnotations(ctx, workspace, workspaceNamespace)
+	})
+	if err != nil {
+		return err
 	}
+	utils.LogOperationResult(log, logging.INFO, workspaceNamespace, result)
 
-	projectName := namespace.Labels[utils.LabelProject]
-	if projectName == "" {
-		return nil, ErrNamespaceHasNoProjectLabel
-	}
+	workspace.Status.Namespace = workspaceNamespace.Name
+	return nil
+}
 
-	project := &pwv1alpha1.Project{}
-	if err := r.OnboardingStatic.Client().Get(ctx, types.NamespacedName{Name: projectName}, project); err != nil {
-		return nil, err
+// applyRoleBindings creates or updates the ClusterRoles, ClusterRoleBindings, and RoleBindings for all workspace roles,
+// as well as the Roles and RoleBindings for the additional permissions of the given workspace class (may be nil).
+func (r *WorkspaceReconciler) applyRoleBindings(ctx context.Context, project *pwv1alpha1.Project, workspace *pwv1alpha1.Workspace, class *pwv1alpha1.WorkspaceClass) error {
+	if err := r.createOrUpdateClusterRole(ctx, project, workspace); err != nil {
+		return err
 	}
+	for _, role := range []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleAdmin, pwv1alpha1.WorkspaceRoleView, pwv1alpha1.WorkspaceRoleAuditor} {
+		if err := r.createOrUpdateRoleBinding(ctx, project, workspace, role); err != nil {
+			return err
+		}
+	}
+	return r.applyWorkspaceClassRoles(ctx, project, workspace, class)
+}
 
-	return project, nil
+func (r *WorkspaceReconciler) getProjectByNamespace(ctx context.Context, namespaceName string) (*pwv1alpha1.Project, error) {
+	projects := r.Projects
+	if projects == nil {
+		projects = projectresolver.New(nil, r.OnboardingStatic.Client())
+	}
+	project, err := projects.Project(ctx, namespaceName)
+	if errors.Is(err, ErrNamespaceHasNoLabels) || errors.Is(err, ErrNamespaceHasNoProjectLabel) {
+		return nil, pwoerrors.NewTerminalError(err)
+	}
+	return project, err
 }
 
-func (r *WorkspaceReconciler) createOrUpdateRoleBinding(ctx context.Context, workspace *pwv1alpha1.Workspace, workspaceRole pwv1alpha1.WorkspaceMemberRole) error {
+func (r *WorkspaceReconciler) createOrUpdateRoleBinding(ctx context.Context, project *pwv1alpha1.Project, workspace *pwv1alpha1.Workspace, workspaceRole pwv1alpha1.WorkspaceMemberRole) error {
 	log := logging.FromContextOrPanic(ctx)
 	roleBinding := &rbacv1.RoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      utils.RoleBindingForRole(workspaceRole),
+			Name:      workspaceScopedName(workspace, utils.RoleBindingForRole(workspaceRole)),
 			Namespace: workspace.Status.Namespace,
 		},
 	}
 
 	result, err := controllerutil.CreateOrUpdate(ctx, r.OnboardingStatic.Client(), roleBinding, func() error {
-		r.applyManagementLabel(roleBinding)
+		if err := r.applyManagementLabel(ctx, roleBinding); err != nil {
+			return err
+		}
+		utils.SetOwnerUIDLabel(roleBinding, workspace)
 
 		roleBinding.RoleRef = rbacv1.RoleRef{
 			APIGroup: rbacv1.GroupName,
@@ -243,11 +370,15 @@ func (r *WorkspaceReconciler) createOrUpdateRoleBinding(ctx context.Context, wor
 			Name:     utils.ClusterRoleForRole(workspaceRole),
 		}
 
-		roleBinding.Subjects = getSubjectsForWorkspaceRole(workspace, workspaceRole)
+		utils.SetSubjectsIfChanged(&roleBinding.Subjects, getSubjectsForWorkspaceRole(project, workspace, workspaceRole))
 		return nil
 	})
 	utils.LogOperationResult(log, logging.INFO, roleBinding, result)
-	return err
+	if err != nil {
+		return err
+	}
+	metrics.RecordRBACUpdate(roleBinding, result)
+	return nil
 }
 
 // createOrUpdateClusterRole manages the ClusterRole and ClusterRoleBinding granting GET permissions to the namespace belonging to the workspace.
@@ -257,6 +388,7 @@ func (r *WorkspaceReconciler) createOrUpdateClusterRole(ctx context.Context, pro
 	workspaceRoles := []pwv1alpha1.WorkspaceMemberRole{
 		pwv1alpha1.WorkspaceRoleAdmin,
 		pwv1alpha1.WorkspaceRoleView,
+		pwv1alpha1.WorkspaceRoleAuditor,
 	}
 
 	for _, role := range workspaceRoles {
@@ -267,16 +399,20 @@ func (r *WorkspaceReconciler) createOrUpdateClusterRole(ctx context.Context, pro
 		}
 
 		result, err := controllerutil.CreateOrUpdate(ctx, r.OnboardingStatic.Client(), clusterRole, func() error {
-			r.applyManagementLabel(clusterRole)
+			if err := r.applyManagementLabel(ctx, clusterRole); err != nil {
+				return err
+			}
+			utils.SetOwnerUIDLabel(clusterRole, ws)
+			utils.SetOwnerAnnotation(clusterRole, ws)
 
-			clusterRole.Rules = []rbacv1.PolicyRule{
+			utils.SetRulesIfChanged(&clusterRole.Rules, []rbacv1.PolicyRule{
 				{
 					APIGroups:     []string{""},
 					Resources:     []string{"namespaces"},
 					ResourceNames: []string{ws.Status.Namespace},
 					Verbs:         []string{"get"},
 				},
-			}
+			})
 
 			return nil
 		})
@@ -284,6 +420,7 @@ func (r *WorkspaceReconciler) createOrUpdateClusterRole(ctx context.Context, pro
 			return err
 		}
 		utils.LogOperationResult(log, logging.INFO, clusterRole, result)
+		metrics.RecordRBACUpdate(clusterRole, result)
 
 		clusterRoleBinding := &rbacv1.ClusterRoleBinding{
 			ObjectMeta: metav1.ObjectMeta{
@@ -291,10 +428,16 @@ func (r *WorkspaceReconciler) createOrUpdateClusterRole(ctx context.Context, pro
 			},
 		}
 
+		var previousSubjects []rbacv1.Subject
 		result, err = controllerutil.CreateOrUpdate(ctx, r.OnboardingStatic.Client(), clusterRoleBinding, func() error {
-			r.applyManagementLabel(clusterRoleBinding)
+			if err := r.applyManagementLabel(ctx, clusterRoleBinding); err != nil {
+				return err
+			}
+			utils.SetOwnerUIDLabel(clusterRoleBinding, ws)
+			utils.SetOwnerAnnotation(clusterRoleBinding, ws)
 
-			clusterRoleBinding.Subjects = getSubjectsForWorkspaceRole(ws, role)
+			previousSubjects = clusterRoleBinding.Subjects
+			utils.SetSubjectsIfChanged(&clusterRoleBinding.Subjects, getSubjectsForWorkspaceRole(project, ws, role))
 			clusterRoleBinding.RoleRef = rbacv1.RoleRef{
 				APIGroup: rbacv1.GroupName,
 				Kind:     "ClusterRole",
@@ -307,80 +450,154 @@ func (r *WorkspaceReconciler) createOrUpdateClusterRole(ctx context.Context, pro
 			return err
 		}
 		utils.LogOperationResult(log, logging.INFO, clusterRoleBinding, result)
+		metrics.RecordRBACUpdate(clusterRoleBinding, result)
+		if result == controllerutil.OperationResultUpdated {
+			recordSubjectChanges(r.Recorder, ws, clusterRoleBinding, previousSubjects)
+		}
+	}
+
+	// Remove the ClusterRoles with the legacy names, which have been used before the names were hashed.
+	// They are only deleted after the new ones exist, so that the members do not lose access in between.
+	// The bindings are deleted first, so that no binding refers to a missing ClusterRole.
+	for _, obj := range legacyWorkspaceClusterRBAC(project, ws) {
+		deleted, err := r.deleteIfManaged(ctx, r.OnboardingStatic.Client(), obj)
+		if err != nil {
+			return fmt.Errorf("failed to delete %T '%s': %w", obj, obj.GetName(), err)
+		}
+		if deleted {
+			log.Debug("Deleted resource with legacy name", "kind", fmt.Sprintf("%T", obj), "name", obj.GetName())
+		}
 	}
 
 	return nil
 }
 
-// deleteClusterRole deletes the ClusterRole and ClusterRoleBinding that were created for the Workspace.
-// It has to be done explicitly because cross-namespace OwnerReferences are not allowed.
-func (r *WorkspaceReconciler) deleteClusterRole(ctx context.Context, project *pwv1alpha1.Project, ws *pwv1alpha1.Workspace) error {
-	log := logging.FromContextOrPanic(ctx)
-
-	workspaceRoles := []pwv1alpha1.WorkspaceMemberRole{
-		pwv1alpha1.WorkspaceRoleAdmin,
-		pwv1alpha1.WorkspaceRoleView,
+// legacyWorkspaceClusterRBAC returns the ClusterRoleBindings and ClusterRoles of the given workspace with the names which have been used before the names were hashed.
+func legacyWorkspaceClusterRBAC(project *pwv1alpha1.Project, ws *pwv1alpha1.Workspace) []client.Object {
+	bindings, roles := []client.Object{}, []client.Object{}
+	for _, role := range []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleAdmin, pwv1alpha1.WorkspaceRoleView, pwv1alpha1.WorkspaceRoleAuditor} {
+		name := utils.LegacyClusterRoleForEntityAndRoleWithParent(ws, role, project)
+		bindings = append(bindings, &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: name}})
+		roles = append(roles, &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: name}})
 	}
+	return append(bindings, roles...)
+}
 
-	for _, role := range workspaceRoles {
-		clusterRole := &rbacv1.ClusterRole{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: utils.ClusterRoleForEntityAndRoleWithParent(ws, role, project),
-			},
+// deleteWorkspaceResources deletes the resources which have been created for the Workspace outside of its namespace, e.g. the ClusterRoles and ClusterRoleBindings.
+// It has to be done explicitly because cross-namespace OwnerReferences are not allowed.
+func (r *WorkspaceReconciler) deleteWorkspaceResources(ctx context.Context, project *pwv1alpha1.Project, ws *pwv1alpha1.Workspace) error {
+	// resources created before they were labeled with the owner UID are deleted by name, they might still have the legacy names
+	legacy := legacyWorkspaceClusterRBAC(project, ws)
+	for _, role := range []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleAdmin, pwv1alpha1.WorkspaceRoleView, pwv1alpha1.WorkspaceRoleAuditor} {
+		name := utils.ClusterRoleForEntityAndRoleWithParent(ws, role, project)
+		legacy = append(legacy, &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: name}}, &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: name}})
+		if ws.IsFlat() {
+			legacy = append(legacy, &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{
+				Name:      workspaceScopedName(ws, utils.RoleBindingForRole(role)),
+				Namespace: ws.Status.Namespace,
+			}})
 		}
+	}
+	return r.deleteOwnedResources(ctx, r.OnboardingStatic.Client(), ws, legacy...)
+}
 
-		if err := r.OnboardingStatic.Client().Delete(ctx, clusterRole); err != nil {
-			if !apierrors.IsNotFound(err) {
-				return err
-			}
-			log.Debug("ClusterRole already deleted, nothing to do", "clusterRole", clusterRole.Name)
-		} else {
-			log.Debug("Deleted ClusterRole", "clusterRole", clusterRole.Name)
-		}
+// workspacesInheritingMembersOf returns reconcile requests for all workspaces of the given project which inherit the project members.
+// This is required for the workspace RBAC to follow changes to the project members.
+func (r *WorkspaceReconciler) workspacesInheritingMembersOf(ctx context.Context, obj client.Object) []ctrl.Request {
+	project, ok := obj.(*pwv1alpha1.Project)
+	if !ok || project.Status.Namespace == "" {
+		return nil
+	}
 
-		clusterRoleBinding := &rbacv1.ClusterRoleBinding{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: utils.ClusterRoleForEntityAndRoleWithParent(ws, role, project),
-			},
-		}
+	workspaces := &pwv1alpha1.WorkspaceList{}
+	if err := r.OnboardingStatic.Client().List(ctx, workspaces, client.InNamespace(project.Status.Namespace)); err != nil {
+		logging.FromContextOrDiscard(ctx).Error(err, "failed to list workspaces of project", "project", project.Name)
+		return nil
+	}
 
-		if err := r.OnboardingStatic.Client().Delete(ctx, clusterRoleBinding); err != nil {
-			if !apierrors.IsNotFound(err) {
-				return err
-			}
-			log.Debug("ClusterRoleBinding already deleted, nothing to do", "clusterRoleBinding", clusterRoleBinding.Name)
-		} else {
-			log.Debug("Deleted ClusterRoleBinding", "clusterRoleBinding", clusterRoleBinding.Name)
+	requests := []ctrl.Request{}
+	for _, ws := range workspaces.Items {
+		if ws.InheritsProjectMembers() {
+			requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&ws)})
 		}
 	}
-
-	return nil
+	return requests
 }
 
 // SetupWithManager sets up the controller with the Manager.
+// Workspaces in deletion are handled by a separate controller with its own work queue, see deletionPhaseFilter.
 func (r *WorkspaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorder(WorkspaceControllerName)
+	}
+	if r.Projects == nil {
+		r.Projects = projectresolver.New(mgr.GetCache(), r.OnboardingStatic.Client())
+	}
+	relevantChanges := predicate.And(
+		predicate.Or(
+			predicate.GenerationChangedPredicate{},
+			ctrlutils.DeletionTimestampChangedPredicate{},
+			ctrlutils.GotAnnotationPredicate(apiconst.OperationAnnotation, apiconst.OperationAnnotationValueReconcile),
+			ctrlutils.LostAnnotationPredicate(apiconst.OperationAnnotation, apiconst.OperationAnnotationValueIgnore),
+		),
+		predicate.Not(
+			ctrlutils.HasAnnotationPredicate(apiconst.OperationAnnotation, apiconst.OperationAnnotationValueIgnore),
+		),
+	)
+	newWorkspace := func() client.Object { return &pwv1alpha1.Workspace{} }
+
+	b := ctrl.NewControllerManagedBy(mgr).
+		Named(WorkspaceControllerName).
+		WithLogConstructor(logconfig.LogConstructor(mgr.GetLogger(), WorkspaceControllerName)).
 		For(&pwv1alpha1.Workspace{}, builder.WithPredicates(
-			predicate.And(
-				predicate.Or(
-					predicate.GenerationChangedPredicate{},
-					ctrlutils.DeletionTimestampChangedPredicate{},
-					ctrlutils.GotAnnotationPredicate(apiconst.OperationAnnotation, apiconst.OperationAnnotationValueReconcile),
-					ctrlutils.LostAnnotationPredicate(apiconst.OperationAnnotation, apiconst.OperationAnnotationValueIgnore),
-				),
-				predicate.Not(
-					ctrlutils.HasAnnotationPredicate(apiconst.OperationAnnotation, apiconst.OperationAnnotationValueIgnore),
-				),
+			predicate.And(relevantChanges, predicate.Not(inDeletionPredicate)),
+		)).
+		Watches(&pwv1alpha1.Project{}, handler.EnqueueRequestsFromMapFunc(r.workspacesInheritingMembersOf), builder.WithPredicates(
+			predicate.GenerationChangedPredicate{},
+		)).
+		Watches(&pwv1alpha1.Project{}, handler.EnqueueRequestsFromMapFunc(r.workspacesOfProject), builder.WithPredicates(
+			predicate.Or(secretStoresChangedPredicate, podSecurityChangedPredicate),
+		)).
+		Watches(&pwv1alpha1.WorkspaceClass{}, handler.EnqueueRequestsFromMapFunc(r.workspacesOfClass), builder.WithPredicates(
+			predicate.GenerationChangedPredicate{},
+		)).
+		Watches(&networkingv1.NetworkPolicy{}, handler.EnqueueRequestsFromMapFunc(r.workspaceOfNetworkPolicy), builder.WithPredicates(
+			predicate.Or(
+				predicate.GenerationChangedPredicate{},
+				predicate.LabelChangedPredicate{},
 			),
 		)).
-		Complete(r)
+		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.workspaceOfNamespace), builder.WithPredicates(
+			podSecurityLabelsChangedPredicate,
+		))
+	if r.ConfigChanges != nil {
+		b = b.WatchesRawSource(source.Channel(r.ConfigChanges, &handler.EnqueueRequestForObject{}))
+	}
+	handover := make(chan event.GenericEvent)
+	rec := deletionPhaseFilter(r.OnboardingStatic.Client(), newWorkspace, false, handover, metrics.ObserveReconciler(WorkspaceControllerName, r))
+	if err := b.Complete(shutdown.GracefulReconciler(rec, r.ShutdownGracePeriod)); err != nil {
+		return err
+	}
+
+	deletionRec := deletionPhaseFilter(r.OnboardingStatic.Client(), newWorkspace, true, nil, metrics.ObserveReconciler(WorkspaceDeletionControllerName, r))
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(WorkspaceDeletionControllerName).
+		WithLogConstructor(logconfig.LogConstructor(mgr.GetLogger(), WorkspaceDeletionControllerName)).
+		For(&pwv1alpha1.Workspace{}, builder.WithPredicates(
+			predicate.And(relevantChanges, inDeletionPredicate),
+		)).
+		WatchesRawSource(blockingResourcesChangedSource(r.Config, r.OnboardingStatic.Client(), "Workspace")).
+		WatchesRawSource(source.Channel(handover, &handler.EnqueueRequestForObject{})).
+		Complete(shutdown.GracefulReconciler(deletionRec, r.ShutdownGracePeriod))
 }
 
-func getSubjectsForWorkspaceRole(workspace *pwv1alpha1.Workspace, role pwv1alpha1.WorkspaceMemberRole) []rbacv1.Subject {
+// getSubjectsForWorkspaceRole returns the subjects of all workspace members which effectively have the given role.
+// This includes the members inherited from the project, if the workspace inherits the project members.
+func getSubjectsForWorkspaceRole(project *pwv1alpha1.Project, workspace *pwv1alpha1.Workspace, role pwv1alpha1.WorkspaceMemberRole) []rbacv1.Subject {
 	subjects := []rbacv1.Subject{}
 
-	for _, member := range workspace.Spec.Members {
-		if hasWorkspaceRole(member, role) {
+	for _, member := range workspace.EffectiveMembers(project) {
+		if hasWorkspaceRole(workspace, member, role) {
 			subjects = append(subjects, member.RbacV1())
 		}
 	}
@@ -388,8 +605,8 @@ func getSubjectsForWorkspaceRole(workspace *pwv1alpha1.Workspace, role pwv1alpha
 	return subjects
 }
 
-func hasWorkspaceRole(member pwv1alpha1.WorkspaceMember, role pwv1alpha1.WorkspaceMemberRole) bool {
-	for _, memberRole := range member.Roles {
+func hasWorkspaceRole(workspace *pwv1alpha1.Workspace, member pwv1alpha1.WorkspaceMember, role pwv1alpha1.WorkspaceMemberRole) bool {
+	for _, memberRole := range effectiveWorkspaceMemberRoles(workspace, member) {
 		if memberRole == role {
 			return true
 		}
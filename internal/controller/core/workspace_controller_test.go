@@ -20,6 +20,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	apiconst "github.com/openmcp-project/openmcp-operator/api/constants"
+
 	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
 	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
 	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
@@ -66,6 +68,66 @@ var (
 			},
 		},
 	}
+	sampleWorkspaceHibernated = &pwv1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "hibernated",
+			Namespace: projectNamespace.Name,
+		},
+		Spec: pwv1alpha1.WorkspaceSpec{
+			Hibernated: true,
+			Members: []pwv1alpha1.WorkspaceMember{
+				{
+					Subject: pwv1alpha1.Subject{
+						Kind: rbacv1.UserKind,
+						Name: "user@example.com",
+					},
+					Roles: []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleAdmin},
+				},
+				{
+					Subject: pwv1alpha1.Subject{
+						Kind:      "ServiceAccount",
+						Name:      "default",
+						Namespace: "default",
+					},
+					Roles: []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleView},
+				},
+				{
+					Subject: pwv1alpha1.Subject{
+						Kind: rbacv1.UserKind,
+						Name: "auditor@example.com",
+					},
+					Roles: []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleAuditor},
+				},
+			},
+		},
+	}
+	sampleWorkspaceInheriting = &pwv1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "inheriting",
+			Namespace: projectNamespace.Name,
+		},
+		Spec: pwv1alpha1.WorkspaceSpec{
+			Members: []pwv1alpha1.WorkspaceMember{
+				{
+					Subject: pwv1alpha1.Subject{
+						Kind: rbacv1.UserKind,
+						Name: "ws-user@example.com",
+					},
+					Roles: []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleAdmin},
+				},
+				{
+					Subject: pwv1alpha1.Subject{
+						Kind: rbacv1.UserKind,
+						Name: "user@example.com",
+					},
+					Roles: []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleView},
+				},
+			},
+			InheritProjectMembers: &pwv1alpha1.InheritProjectMembers{
+				Enabled: true,
+			},
+		},
+	}
 	sampleWorkspaceDeleted = &pwv1alpha1.Workspace{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:              "sample",
@@ -195,6 +257,243 @@ func Test_WorkspaceReconciler_Reconcile(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			desc: "should track the RBAC status of each member",
+			initObjs: []client.Object{
+				sampleWorkspace,
+				projectNamespace,
+				sampleProject,
+			},
+			expectedResult: reconcile.Result{},
+			expectedErr:    nil,
+			validate: func(t *testing.T, ctx context.Context, c client.Client) error {
+				ws := &pwv1alpha1.Workspace{}
+				assert.NoErrorf(t, c.Get(ctx, client.ObjectKeyFromObject(sampleWorkspace), ws), "GET failed unexpectedly")
+
+				assert.Len(t, ws.Status.MemberStatuses, 3)
+				for _, status := range ws.Status.MemberStatuses[:2] {
+					assert.Equal(t, pwv1alpha1.MemberPhaseActive, status.Phase)
+					assert.Equal(t, []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleAdmin}, status.Roles)
+					assert.NotNil(t, status.LastAppliedTime)
+				}
+				// the namespace of the ServiceAccount does not exist
+				assert.Equal(t, rbacv1.ServiceAccountKind, ws.Status.MemberStatuses[2].Kind)
+				assert.Equal(t, pwv1alpha1.MemberPhaseFailed, ws.Status.MemberStatuses[2].Phase)
+				assert.Nil(t, ws.Status.MemberStatuses[2].LastAppliedTime)
+				assert.Contains(t, ws.Status.MemberStatuses[2].Message, "'default'")
+
+				return nil
+			},
+		},
+		{
+			desc: "should hibernate workspace",
+			initObjs: []client.Object{
+				sampleWorkspaceHibernated,
+				projectNamespace,
+				sampleProject,
+			},
+			expectedResult: reconcile.Result{},
+			expectedErr:    nil,
+			validate: func(t *testing.T, ctx context.Context, c client.Client) error {
+				ws := &pwv1alpha1.Workspace{}
+				assert.NoErrorf(t, c.Get(ctx, client.ObjectKeyFromObject(sampleWorkspaceHibernated), ws), "GET failed unexpectedly")
+
+				ns := namespaceCreatedForWorkspace(t, ctx, c, ws, true)
+				assert.Equal(t, "true", ns.Annotations[pwv1alpha1.HibernatedAnnotation])
+
+				quota := &corev1.ResourceQuota{}
+				assert.NoError(t, c.Get(ctx, types.NamespacedName{Name: HibernationResourceQuotaName, Namespace: ws.Status.Namespace}, quota))
+				for name, quantity := range quota.Spec.Hard {
+					assert.Truef(t, quantity.IsZero(), "expected hard limit for %s to be zero", name)
+				}
+
+				assert.Len(t, ws.Status.Conditions, 2)
+				if condition := findCondition(ws.Status.Conditions, pwv1alpha1.ConditionTypeHibernated); assert.NotNil(t, condition) {
+					assert.Equal(t, pwv1alpha1.ConditionStatusTrue, condition.Status)
+				}
+				// the access is verified with the reduced roles of the members
+				if condition := findCondition(ws.Status.Conditions, pwv1alpha1.ConditionTypeReady); assert.NotNil(t, condition) {
+					assert.Equal(t, pwv1alpha1.ConditionStatusTrue, condition.Status)
+				}
+
+				expectedViewers := []rbacv1.Subject{
+					{
+						APIGroup: rbacv1.GroupName,
+						Kind:     rbacv1.UserKind,
+						Name:     "user@example.com",
+					},
+					{
+						Kind:      rbacv1.ServiceAccountKind,
+						Name:      "default",
+						Namespace: "default",
+					},
+				}
+				// all admins are reduced to viewers, the admin RoleBinding is kept without subjects
+				roleBindingCreatedForWorkspace(t, ctx, c, ws, pwv1alpha1.WorkspaceRoleAdmin, true, nil)
+				roleBindingCreatedForWorkspace(t, ctx, c, ws, pwv1alpha1.WorkspaceRoleView, true, expectedViewers)
+				// auditors keep their role, since it is more restricted than the 'view' role
+				roleBindingCreatedForWorkspace(t, ctx, c, ws, pwv1alpha1.WorkspaceRoleAuditor, true, []rbacv1.Subject{
+					{
+						APIGroup: rbacv1.GroupName,
+						Kind:     rbacv1.UserKind,
+						Name:     "auditor@example.com",
+					},
+				})
+
+				return nil
+			},
+		},
+		{
+			desc: "should restore workspace from hibernation",
+			initObjs: []client.Object{
+				sampleWorkspace,
+				projectNamespace,
+				sampleProject,
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: utils.NamespaceForWorkspace(sampleWorkspace),
+						Annotations: map[string]string{
+							pwv1alpha1.HibernatedAnnotation: "true",
+						},
+					},
+				},
+				&corev1.ResourceQuota{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      HibernationResourceQuotaName,
+						Namespace: utils.NamespaceForWorkspace(sampleWorkspace),
+						Labels:    utils.ManagementLabels("test", pwv1alpha1.ManagementLabelsConfig{}),
+					},
+				},
+			},
+			expectedResult: reconcile.Result{},
+			expectedErr:    nil,
+			validate: func(t *testing.T, ctx context.Context, c client.Client) error {
+				ws := &pwv1alpha1.Workspace{}
+				assert.NoErrorf(t, c.Get(ctx, client.ObjectKeyFromObject(sampleWorkspace), ws), "GET failed unexpectedly")
+
+				ns := namespaceCreatedForWorkspace(t, ctx, c, ws, true)
+				assert.NotContains(t, ns.Annotations, pwv1alpha1.HibernatedAnnotation)
+
+				err := c.Get(ctx, types.NamespacedName{Name: HibernationResourceQuotaName, Namespace: ws.Status.Namespace}, &corev1.ResourceQuota{})
+				assert.True(t, apierrors.IsNotFound(err))
+
+				assert.Nil(t, findCondition(ws.Status.Conditions, pwv1alpha1.ConditionTypeHibernated))
+				if condition := findCondition(ws.Status.Conditions, pwv1alpha1.ConditionTypeReady); assert.NotNil(t, condition) {
+					assert.Equal(t, pwv1alpha1.ConditionReasonAccessVerified, condition.Reason)
+				}
+
+				return nil
+			},
+		},
+		{
+			desc: "should merge the inherited project members into the workspace RBAC",
+			initObjs: []client.Object{
+				sampleWorkspaceInheriting,
+				projectNamespace,
+				sampleProject,
+			},
+			expectedResult: reconcile.Result{},
+			expectedErr:    nil,
+			validate: func(t *testing.T, ctx context.Context, c client.Client) error {
+				ws := &pwv1alpha1.Workspace{}
+				assert.NoErrorf(t, c.Get(ctx, client.ObjectKeyFromObject(sampleWorkspaceInheriting), ws), "GET failed unexpectedly")
+
+				expectedAdmins := []rbacv1.Subject{
+					{
+						APIGroup: rbacv1.GroupName,
+						Kind:     rbacv1.UserKind,
+						Name:     "ws-user@example.com",
+					},
+					{
+						APIGroup: rbacv1.GroupName,
+						Kind:     rbacv1.UserKind,
+						Name:     "user@example.com",
+					},
+					{
+						APIGroup: rbacv1.GroupName,
+						Kind:     rbacv1.GroupKind,
+						Name:     "some-group",
+					},
+				}
+				clusterRoleBindingCreatedForWorkspace(t, ctx, c, sampleProject, ws, pwv1alpha1.WorkspaceRoleAdmin, true, expectedAdmins)
+				roleBindingCreatedForWorkspace(t, ctx, c, ws, pwv1alpha1.WorkspaceRoleAdmin, true, expectedAdmins)
+
+				expectedViewers := []rbacv1.Subject{
+					{
+						APIGroup: rbacv1.GroupName,
+						Kind:     rbacv1.UserKind,
+						Name:     "user@example.com",
+					},
+					{
+						Kind:      rbacv1.ServiceAccountKind,
+						Name:      "default",
+						Namespace: "default",
+					},
+				}
+				clusterRoleBindingCreatedForWorkspace(t, ctx, c, sampleProject, ws, pwv1alpha1.WorkspaceRoleView, true, expectedViewers)
+				roleBindingCreatedForWorkspace(t, ctx, c, ws, pwv1alpha1.WorkspaceRoleView, true, expectedViewers)
+
+				return nil
+			},
+		},
+		{
+			desc: "should map the roles of inherited project members",
+			initObjs: []client.Object{
+				withInheritedRoleMapping(sampleWorkspaceInheriting, map[pwv1alpha1.ProjectMemberRole]pwv1alpha1.WorkspaceMemberRole{
+					pwv1alpha1.ProjectRoleAdmin: pwv1alpha1.WorkspaceRoleView,
+				}),
+				projectNamespace,
+				sampleProject,
+			},
+			expectedResult: reconcile.Result{},
+			expectedErr:    nil,
+			validate: func(t *testing.T, ctx context.Context, c client.Client) error {
+				ws := &pwv1alpha1.Workspace{}
+				assert.NoErrorf(t, c.Get(ctx, client.ObjectKeyFromObject(sampleWorkspaceInheriting), ws), "GET failed unexpectedly")
+
+				roleBindingCreatedForWorkspace(t, ctx, c, ws, pwv1alpha1.WorkspaceRoleAdmin, true, []rbacv1.Subject{
+					{
+						APIGroup: rbacv1.GroupName,
+						Kind:     rbacv1.UserKind,
+						Name:     "ws-user@example.com",
+					},
+				})
+				// project viewers are not inherited, because their role is not part of the mapping
+				roleBindingCreatedForWorkspace(t, ctx, c, ws, pwv1alpha1.WorkspaceRoleView, true, []rbacv1.Subject{
+					{
+						APIGroup: rbacv1.GroupName,
+						Kind:     rbacv1.UserKind,
+						Name:     "user@example.com",
+					},
+					{
+						APIGroup: rbacv1.GroupName,
+						Kind:     rbacv1.GroupKind,
+						Name:     "some-group",
+					},
+				})
+
+				return nil
+			},
+		},
+		{
+			desc: "should reconcile and remove the reconcile operation annotation",
+			initObjs: []client.Object{
+				withAnnotation(sampleWorkspace, apiconst.OperationAnnotation, apiconst.OperationAnnotationValueReconcile),
+				projectNamespace,
+				sampleProject,
+			},
+			expectedResult: reconcile.Result{},
+			expectedErr:    nil,
+			validate: func(t *testing.T, ctx context.Context, c client.Client) error {
+				ws := &pwv1alpha1.Workspace{}
+				assert.NoErrorf(t, c.Get(ctx, client.ObjectKeyFromObject(sampleWorkspace), ws), "GET failed unexpectedly")
+				assert.NotContains(t, ws.GetAnnotations(), apiconst.OperationAnnotation)
+
+				namespaceCreatedForWorkspace(t, ctx, c, ws, true)
+
+				return nil
+			},
+		},
 		{
 			desc: "CO-1154 should delete namespace",
 			initObjs: []client.Object{
@@ -203,7 +502,8 @@ func Test_WorkspaceReconciler_Reconcile(t *testing.T) {
 				sampleProject,
 				&corev1.Namespace{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: sampleWorkspaceDeleted.Status.Namespace,
+						Name:   sampleWorkspaceDeleted.Status.Namespace,
+						Labels: ownedNamespaceLabels(sampleProject.Name, sampleWorkspaceDeleted.Name),
 					},
 				},
 			},
@@ -226,6 +526,44 @@ func Test_WorkspaceReconciler_Reconcile(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			desc: "should only delete ClusterRoles which are managed by the platform service",
+			initObjs: []client.Object{
+				sampleWorkspaceDeleted,
+				projectNamespace,
+				sampleProject,
+				&rbacv1.ClusterRole{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: utils.LegacyClusterRoleForEntityAndRoleWithParent(sampleWorkspaceDeleted, pwv1alpha1.WorkspaceRoleAdmin, sampleProject),
+						Labels: map[string]string{
+							"app.kubernetes.io/managed-by": "legacy-operator",
+						},
+					},
+				},
+				&rbacv1.ClusterRole{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: utils.LegacyClusterRoleForEntityAndRoleWithParent(sampleWorkspaceDeleted, pwv1alpha1.WorkspaceRoleView, sampleProject),
+						Labels: map[string]string{
+							"app.kubernetes.io/managed-by": "someone-else",
+						},
+					},
+				},
+			},
+			expectedResult: reconcile.Result{},
+			expectedErr:    nil,
+			validate: func(t *testing.T, ctx context.Context, c client.Client) error {
+				err := c.Get(ctx, client.ObjectKeyFromObject(sampleWorkspaceDeleted), &pwv1alpha1.Workspace{})
+				assert.True(t, apierrors.IsNotFound(err))
+
+				err = c.Get(ctx, types.NamespacedName{Name: utils.LegacyClusterRoleForEntityAndRoleWithParent(sampleWorkspaceDeleted, pwv1alpha1.WorkspaceRoleAdmin, sampleProject)}, &rbacv1.ClusterRole{})
+				assert.True(t, apierrors.IsNotFound(err), "ClusterRole with previous management labels should have been deleted")
+
+				err = c.Get(ctx, types.NamespacedName{Name: utils.LegacyClusterRoleForEntityAndRoleWithParent(sampleWorkspaceDeleted, pwv1alpha1.WorkspaceRoleView, sampleProject)}, &rbacv1.ClusterRole{})
+				assert.NoError(t, err, "ClusterRole which is not managed by the platform service should not have been deleted")
+
+				return nil
+			},
+		},
 		{
 			desc: "CO-1154 should not delete namespace when deletion is blocked by resources",
 			initObjs: []client.Object{
@@ -260,17 +598,78 @@ func Test_WorkspaceReconciler_Reconcile(t *testing.T) {
 				assert.NotEmpty(t, ws.Status.Conditions[0].Message)
 				assert.NotNil(t, ws.Status.Conditions[0].Details)
 
-				var remainingResources []pwv1alpha1.RemainingContentResource
-				assert.NoError(t, json.Unmarshal(ws.Status.Conditions[0].Details, &remainingResources))
+				var details pwv1alpha1.RemainingContentDetails
+				assert.NoError(t, json.Unmarshal(ws.Status.Conditions[0].Details, &details))
+				assert.Equal(t, 1, details.Count)
+				remainingResources := details.Resources
 				assert.Len(t, remainingResources, 1)
 				assert.Equal(t, "v1", remainingResources[0].APIGroup)
 				assert.Equal(t, "Secret", remainingResources[0].Kind)
 				assert.Equal(t, "blocking", remainingResources[0].Name)
+				assert.Equal(t, pwv1alpha1.SourceProjectWorkspaceConfig, remainingResources[0].Source)
+				assert.Contains(t, ws.Status.Conditions[0].Message, "ProjectWorkspaceConfig: 1")
 
 				ns := &corev1.Namespace{}
 				err = c.Get(ctx, types.NamespacedName{Name: ws.Status.Namespace}, ns)
 				assert.NoError(t, err)
 				assert.Nil(t, ns.GetDeletionTimestamp())
+				assert.Equal(t, "true", ns.GetAnnotations()[pwv1alpha1.DeletionRequestedAnnotation])
+
+				return nil
+			},
+		},
+		{
+			desc: "should delete namespace when remaining resources are ignored",
+			initObjs: []client.Object{
+				sampleWorkspaceDeleted,
+				projectNamespace,
+				sampleProject,
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   sampleWorkspaceDeleted.Status.Namespace,
+						Labels: ownedNamespaceLabels(sampleProject.Name, sampleWorkspaceDeleted.Name),
+					},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "default-token-abcde",
+						Namespace: sampleWorkspaceDeleted.Status.Namespace,
+					},
+				},
+			},
+			expectedResult: reconcile.Result{},
+			expectedErr:    nil,
+			validate: func(t *testing.T, ctx context.Context, c client.Client) error {
+				ws := &pwv1alpha1.Workspace{}
+				err := c.Get(ctx, client.ObjectKeyFromObject(sampleWorkspaceDeleted), ws)
+				assert.True(t, apierrors.IsNotFound(err))
+
+				namespaceCreatedForWorkspace(t, ctx, c, sampleWorkspaceDeleted, false)
+
+				return nil
+			},
+		},
+		{
+			desc: "should not delete an unlabeled namespace",
+			initObjs: []client.Object{
+				sampleWorkspaceDeleted,
+				projectNamespace,
+				sampleProject,
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: sampleWorkspaceDeleted.Status.Namespace,
+					},
+				},
+			},
+			expectedResult: reconcile.Result{RequeueAfter: 5 * time.Second},
+			expectedErr:    nil,
+			validate: func(t *testing.T, ctx context.Context, c client.Client) error {
+				ws := &pwv1alpha1.Workspace{}
+				assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(sampleWorkspaceDeleted), ws))
+				assert.Contains(t, ws.Finalizers, deleteFinalizer)
+
+				// the namespace is not identified as created for the workspace, so it is kept
+				assert.NoError(t, c.Get(ctx, types.NamespacedName{Name: sampleWorkspaceDeleted.Status.Namespace}, &corev1.Namespace{}))
 
 				return nil
 			},
@@ -280,26 +679,38 @@ func Test_WorkspaceReconciler_Reconcile(t *testing.T) {
 		t.Run(tC.desc, func(t *testing.T) {
 			c := fake.NewClientBuilder().
 				WithObjects(tC.initObjs...).
-				WithInterceptorFuncs(tC.interceptorFuncs).
+				WithInterceptorFuncs(reviewAccess(allowAll, tC.interceptorFuncs)).
 				WithStatusSubresource(tC.initObjs[0]).
 				WithScheme(Scheme).
 				Build()
 			ctx := newContext()
 			req := newRequest(tC.initObjs[0])
 
-			sr, err := NewWorkspaceReconciler(c.Scheme(), NewCommonReconciler(
-				sharedconfig.NewFakeSharedInformation(c, nil, []sharedconfig.DeletionBlockingResource{
+			cfg := sharedconfig.NewFakeSharedInformation(c, nil, []sharedconfig.DeletionBlockingResource{
+				{
+					GroupVersionKind: metav1.GroupVersionKind{
+						Group:   "",
+						Version: "v1",
+						Kind:    "Secret",
+					},
+					Source: pwv1alpha1.SourceProjectWorkspaceConfig,
+				},
+			}, nil)
+			cfg.WorkspaceDeletionIgnoreRulesData = []pwv1alpha1.DeletionIgnoreRule{
+				{
+					Kind:         "Secret",
+					NamePatterns: []string{"default-token-*"},
+				},
+			}
+			cfg.ManagementLabelsData = pwv1alpha1.ManagementLabelsConfig{
+				PreviousLabels: []map[string]string{
 					{
-						GroupVersionKind: metav1.GroupVersionKind{
-							Group:   "",
-							Version: "v1",
-							Kind:    "Secret",
-						},
-						Source: pwv1alpha1.SourceProjectWorkspaceConfig,
+						"app.kubernetes.io/managed-by": "legacy-operator",
 					},
-				}, nil),
-				"test",
-			))
+				},
+			}
+
+			sr, err := NewWorkspaceReconciler(c.Scheme(), NewCommonReconciler(cfg, "test"))
 			assert.NoError(t, err)
 
 			result, err := ctrl.Result{}, error(nil)
@@ -320,6 +731,93 @@ func Test_WorkspaceReconciler_Reconcile(t *testing.T) {
 	}
 }
 
+func Test_WorkspaceReconciler_workspacesInheritingMembersOf(t *testing.T) {
+	project := sampleProject.DeepCopy()
+	project.Status.Namespace = projectNamespace.Name
+	c := fake.NewClientBuilder().
+		WithObjects(project, projectNamespace, sampleWorkspace, sampleWorkspaceInheriting).
+		WithScheme(Scheme).
+		Build()
+	ctx := newContext()
+
+	wr, err := NewWorkspaceReconciler(c.Scheme(), NewCommonReconciler(sharedconfig.NewFakeSharedInformation(c, nil, nil, nil), "test"))
+	assert.NoError(t, err)
+
+	requests := wr.workspacesInheritingMembersOf(ctx, project)
+	assert.Equal(t, []reconcile.Request{newRequest(sampleWorkspaceInheriting)}, requests)
+}
+
+func Test_WorkspaceReconciler_updateMemberStatuses(t *testing.T) {
+	lastApplied := metav1.NewTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	user := pwv1alpha1.Subject{Kind: rbacv1.UserKind, Name: "user@example.com"}
+	group := pwv1alpha1.Subject{Kind: rbacv1.GroupKind, Name: "some-group"}
+
+	testCases := []struct {
+		desc     string
+		previous []pwv1alpha1.WorkspaceMemberStatus
+		rbacErr  error
+		validate func(t *testing.T, statuses []pwv1alpha1.WorkspaceMemberStatus)
+	}{
+		{
+			desc: "should keep the last applied time of members whose roles did not change",
+			previous: []pwv1alpha1.WorkspaceMemberStatus{
+				{Subject: user, Phase: pwv1alpha1.MemberPhaseActive, Roles: []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleAdmin}, LastAppliedTime: &lastApplied},
+				{Subject: group, Phase: pwv1alpha1.MemberPhaseActive, Roles: []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleView}, LastAppliedTime: &lastApplied},
+			},
+			validate: func(t *testing.T, statuses []pwv1alpha1.WorkspaceMemberStatus) {
+				assert.Equal(t, pwv1alpha1.MemberPhaseActive, statuses[0].Phase)
+				assert.Equal(t, &lastApplied, statuses[0].LastAppliedTime)
+				// the role of the group changed from 'view' to 'admin'
+				assert.Equal(t, pwv1alpha1.MemberPhaseActive, statuses[1].Phase)
+				assert.True(t, statuses[1].LastAppliedTime.After(lastApplied.Time))
+			},
+		},
+		{
+			desc: "should mark members as pending if the role bindings could not be applied",
+			previous: []pwv1alpha1.WorkspaceMemberStatus{
+				{Subject: user, Phase: pwv1alpha1.MemberPhaseActive, Roles: []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleAdmin}, LastAppliedTime: &lastApplied},
+			},
+			rbacErr: errFake,
+			validate: func(t *testing.T, statuses []pwv1alpha1.WorkspaceMemberStatus) {
+				assert.Equal(t, pwv1alpha1.MemberPhaseActive, statuses[0].Phase)
+				assert.Equal(t, pwv1alpha1.MemberPhasePending, statuses[1].Phase)
+				assert.Nil(t, statuses[1].LastAppliedTime)
+				assert.Contains(t, statuses[1].Message, errFake.Error())
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			c := fake.NewClientBuilder().
+				WithObjects(projectNamespace, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}).
+				WithScheme(Scheme).
+				Build()
+			ctx := newContext()
+
+			wr, err := NewWorkspaceReconciler(c.Scheme(), NewCommonReconciler(sharedconfig.NewFakeSharedInformation(c, nil, nil, nil), "test"))
+			assert.NoError(t, err)
+
+			ws := sampleWorkspace.DeepCopy()
+			ws.Status.MemberStatuses = tC.previous
+			assert.NoError(t, wr.updateMemberStatuses(ctx, sampleProject, ws, nil, tC.rbacErr))
+
+			assert.Len(t, ws.Status.MemberStatuses, 3)
+			assert.Equal(t, user, ws.Status.MemberStatuses[0].Subject)
+			assert.Equal(t, group, ws.Status.MemberStatuses[1].Subject)
+			// the namespace of the ServiceAccount exists
+			assert.NotEqual(t, pwv1alpha1.MemberPhaseFailed, ws.Status.MemberStatuses[2].Phase)
+			tC.validate(t, ws.Status.MemberStatuses)
+		})
+	}
+}
+
+// withInheritedRoleMapping returns a copy of the given inheriting workspace with the given role mapping.
+func withInheritedRoleMapping(ws *pwv1alpha1.Workspace, mapping map[pwv1alpha1.ProjectMemberRole]pwv1alpha1.WorkspaceMemberRole) *pwv1alpha1.Workspace {
+	res := ws.DeepCopy()
+	res.Spec.InheritProjectMembers.RoleMapping = mapping
+	return res
+}
+
 func namespaceCreatedForWorkspace(t *testing.T, ctx context.Context, c client.Client, ws *pwv1alpha1.Workspace, expectation bool) *corev1.Namespace {
 	ns := &corev1.Namespace{}
 	err := c.Get(ctx, types.NamespacedName{Name: ws.Status.Namespace}, ns)
@@ -356,7 +854,7 @@ func clusterRoleBindingCreatedForWorkspace(t *testing.T, ctx context.Context, c
 
 func roleBindingCreatedForWorkspace(t *testing.T, ctx context.Context, c client.Client, ws *pwv1alpha1.Workspace, role pwv1alpha1.WorkspaceMemberRole, expectation bool, expectedSubjects []rbacv1.Subject) {
 	rb := &rbacv1.RoleBinding{}
-	err := c.Get(ctx, types.NamespacedName{Name: utils.RoleBindingForRole(role), Namespace: ws.Status.Namespace}, rb)
+	err := c.Get(ctx, types.NamespacedName{Name: workspaceScopedName(ws, utils.RoleBindingForRole(role)), Namespace: ws.Status.Namespace}, rb)
 	if expectation {
 		assert.NoError(t, err)
 		assert.Equal(t, expectedSubjects, rb.Subjects)
@@ -0,0 +1,382 @@
+package eventsink
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlcache "sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/openmcp-project/controller-utils/pkg/clusters"
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/export"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/metrics"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+// Static Stuff //
+
+const (
+	ControllerName = "event-sink"
+
+	// SignatureHeader is the request header which contains the HMAC-SHA256 signature of the request body, in the format 'sha256=<hex>'.
+	SignatureHeader = "X-Signature-256"
+	// EventIDHeader is the request header which contains the ID of the event.
+	// The ID stays the same across retries, so that receivers can deduplicate deliveries.
+	EventIDHeader = "X-Event-ID"
+
+	// DefaultRetryBaseDelay is the default delay before the first retry of a failed delivery. It is doubled with each retry.
+	DefaultRetryBaseDelay = 1 * time.Second
+	// DefaultRetryMaxDelay is the default maximum delay between two retries of a failed delivery.
+	DefaultRetryMaxDelay = 5 * time.Minute
+
+	KindProject   = "Project"
+	KindWorkspace = "Workspace"
+)
+
+// EventType describes the kind of change an Event reports.
+type EventType string
+
+const (
+	// EventTypeCreated is sent for projects and workspaces which have been created.
+	EventTypeCreated EventType = "Created"
+	// EventTypeUpdated is sent for projects and workspaces whose exported representation has changed.
+	EventTypeUpdated EventType = "Updated"
+	// EventTypeDeleted is sent for projects and workspaces which have been deleted.
+	EventTypeDeleted EventType = "Deleted"
+	// EventTypeSynced is sent for all existing projects and workspaces when the controller starts,
+	// so that changes which happened while no event could be sent are picked up by the receiver.
+	EventTypeSynced EventType = "Synced"
+	// EventTypeDeletionPending is sent for projects whose deletion has been requested, but whose teardown is delayed by the deletion grace period.
+	// It is followed by a Deleted event once the project is gone, which also happens if the deletion is cancelled and the project is re-created.
+	EventTypeDeletionPending EventType = "DeletionPending"
+)
+
+// Event is the normalized representation of a change of a project or workspace, which is sent to the event sink.
+// Exactly one of Project and Workspace is set, depending on the kind.
+type Event struct {
+	ID              string            `json:"id"`
+	Type            EventType         `json:"type"`
+	Kind            string            `json:"kind"`
+	Name            string            `json:"name"`
+	Namespace       string            `json:"namespace,omitempty"`
+	UID             string            `json:"uid"`
+	ResourceVersion string            `json:"resourceVersion"`
+	Timestamp       time.Time         `json:"timestamp"`
+	Project         *export.Project   `json:"project,omitempty"`
+	Workspace       *export.Workspace `json:"workspace,omitempty"`
+}
+
+// Sign returns the HMAC-SHA256 signature of the given body with the given key, in the format of the SignatureHeader.
+func Sign(key, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Setup //
+
+// EventSinkController sends an event to the configured event sink for each created, updated or deleted project and workspace.
+// Failed deliveries are retried with exponential backoff, until the configured number of retries is exhausted.
+type EventSinkController struct {
+	cfg             pwv1alpha1.EventSinkConfig
+	platformCluster *clusters.Cluster
+	podNamespace    string
+	log             logging.Logger
+	informers       ctrlcache.Informers
+	reader          client.Reader
+	queue           workqueue.TypedRateLimitingInterface[*Event]
+
+	// HTTPClient is used to send the events. Defaults to a client with the configured timeout.
+	HTTPClient *http.Client
+	// ShutdownGracePeriod is the time the queued events get to be delivered when the controller is stopped.
+	// Events which cannot be delivered within this time are dropped.
+	ShutdownGracePeriod time.Duration
+}
+
+// NewEventSinkController creates a new EventSinkController for the given configuration.
+// The signing key is read from the configured secret in the given namespace on the platform cluster before each delivery, so that it can be rotated at runtime.
+// An error is returned if the configuration is invalid, e.g. if the URL does not use https, so that no events are sent unencrypted.
+func NewEventSinkController(cfg pwv1alpha1.EventSinkConfig, platformCluster *clusters.Cluster, podNamespace string) (*EventSinkController, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid event sink configuration: %w", err)
+	}
+	return &EventSinkController{
+		cfg:             cfg,
+		platformCluster: platformCluster,
+		podNamespace:    podNamespace,
+		log:             logging.Discard(),
+		queue: workqueue.NewTypedRateLimitingQueueWithConfig(
+			workqueue.NewTypedItemExponentialFailureRateLimiter[*Event](DefaultRetryBaseDelay, DefaultRetryMaxDelay),
+			workqueue.TypedRateLimitingQueueConfig[*Event]{Name: ControllerName},
+		),
+		HTTPClient: &http.Client{Timeout: cfg.GetTimeout()},
+	}, nil
+}
+
+// SetupWithManager adds the controller to the manager.
+// Since it is added as a runnable which requires leader election, the events are only sent by the leading replica.
+func (c *EventSinkController) SetupWithManager(mgr ctrl.Manager) error {
+	c.log = logging.Wrap(mgr.GetLogger()).WithName(ControllerName)
+	c.informers = mgr.GetCache()
+	c.reader = mgr.GetCache()
+	return mgr.Add(c)
+}
+
+var _ manager.Runnable = &EventSinkController{}
+
+// Start registers the event handlers for projects and workspaces and delivers the queued events until the context is cancelled.
+// Events are delivered one after another, in the order in which the changes have been observed, except for retried deliveries.
+// Once the context is cancelled, the events which are already queued are still delivered within the shutdown grace period, but failed deliveries are not retried anymore.
+func (c *EventSinkController) Start(ctx context.Context) error {
+	ctx = logging.NewContext(ctx, c.log)
+	defer c.queue.ShutDown()
+
+	for _, obj := range []client.Object{&pwv1alpha1.Project{}, &pwv1alpha1.Workspace{}} {
+		if err := c.registerHandlers(ctx, obj); err != nil {
+			return err
+		}
+	}
+
+	deliveryCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		c.queue.ShutDown()
+		if remaining := c.queue.Len(); remaining > 0 {
+			c.log.Info("Flushing queued events before shutdown", "count", remaining, "gracePeriod", c.ShutdownGracePeriod.String())
+		}
+		timer := time.NewTimer(c.ShutdownGracePeriod)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			cancel()
+		case <-deliveryCtx.Done():
+		}
+	}()
+	for c.processNextEvent(deliveryCtx) {
+	}
+	return nil
+}
+
+// registerHandlers adds handlers to the informer for the type of the given object, which enqueue an event for each change.
+func (c *EventSinkController) registerHandlers(ctx context.Context, obj client.Object) error {
+	informer, err := c.informers.GetInformer(ctx, obj)
+	if err != nil {
+		return fmt.Errorf("failed to get informer for %T: %w", obj, err)
+	}
+	_, err = informer.AddEventHandler(toolscache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj any, isInInitialList bool) {
+			eventType := EventTypeCreated
+			if isInInitialList {
+				eventType = EventTypeSynced
+			}
+			c.enqueue(ctx, eventType, obj)
+		},
+		UpdateFunc: func(oldObj, newObj any) {
+			c.enqueueUpdate(ctx, oldObj, newObj)
+		},
+		DeleteFunc: func(obj any) {
+			if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			c.enqueue(ctx, EventTypeDeleted, obj)
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add event handler for %T: %w", obj, err)
+	}
+	return nil
+}
+
+// Logic //
+
+// enqueue builds an event of the given type for the given object and adds it to the queue.
+func (c *EventSinkController) enqueue(ctx context.Context, eventType EventType, obj any) {
+	e, err := c.newEvent(ctx, eventType, obj)
+	if err != nil {
+		c.log.Error(err, "Failed to build event", "type", eventType)
+		return
+	}
+	c.queue.Add(e)
+}
+
+// enqueueUpdate adds an update event to the queue, if the exported representation of the object has changed.
+// Resyncs and changes which are not part of the exported representation, e.g. most status updates, are skipped.
+// A DeletionPending event is added when a project enters its deletion grace period.
+func (c *EventSinkController) enqueueUpdate(ctx context.Context, oldObj, newObj any) {
+	if !deletionPending(oldObj) && deletionPending(newObj) {
+		c.enqueue(ctx, EventTypeDeletionPending, newObj)
+	}
+
+	oldEvent, err := c.newEvent(ctx, EventTypeUpdated, oldObj)
+	if err != nil {
+		c.log.Error(err, "Failed to build event", "type", EventTypeUpdated)
+		return
+	}
+	newEvent, err := c.newEvent(ctx, EventTypeUpdated, newObj)
+	if err != nil {
+		c.log.Error(err, "Failed to build event", "type", EventTypeUpdated)
+		return
+	}
+	if reflect.DeepEqual(oldEvent.Project, newEvent.Project) && reflect.DeepEqual(oldEvent.Workspace, newEvent.Workspace) {
+		return
+	}
+	c.queue.Add(newEvent)
+}
+
+// deletionPending returns true if the given object is a Project whose teardown is delayed by the deletion grace period.
+func deletionPending(obj any) bool {
+	project, ok := obj.(*pwv1alpha1.Project)
+	if !ok {
+		return false
+	}
+	condition := project.GetCondition(pwv1alpha1.ConditionTypePendingDeletion)
+	return condition != nil && condition.Status == pwv1alpha1.ConditionStatusTrue
+}
+
+// newEvent builds an event of the given type for the given Project or Workspace.
+// Workspaces are exported together with the members inherited from the project owning their namespace.
+func (c *EventSinkController) newEvent(ctx context.Context, eventType EventType, obj any) (*Event, error) {
+	o, ok := obj.(client.Object)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object of type %T", obj)
+	}
+	e := &Event{
+		ID:              string(uuid.NewUUID()),
+		Type:            eventType,
+		Name:            o.GetName(),
+		Namespace:       o.GetNamespace(),
+		UID:             string(o.GetUID()),
+		ResourceVersion: o.GetResourceVersion(),
+		Timestamp:       time.Now().UTC(),
+	}
+	switch typed := o.(type) {
+	case *pwv1alpha1.Project:
+		exported := export.NewProject(typed)
+		e.Kind = KindProject
+		e.Project = &exported
+	case *pwv1alpha1.Workspace:
+		project, err := c.projectOf(ctx, typed)
+		if err != nil {
+			return nil, err
+		}
+		exported := export.NewWorkspace(typed, project)
+		e.Kind = KindWorkspace
+		e.Workspace = &exported
+	default:
+		return nil, fmt.Errorf("unexpected object of type %T", obj)
+	}
+	return e, nil
+}
+
+// projectOf returns the Project owning the namespace of the given Workspace, or nil if it does not exist (anymore).
+func (c *EventSinkController) projectOf(ctx context.Context, ws *pwv1alpha1.Workspace) (*pwv1alpha1.Project, error) {
+	projects := &pwv1alpha1.ProjectList{}
+	if err := c.reader.List(ctx, projects); err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+	for i := range projects.Items {
+		if utils.NamespaceForProject(&projects.Items[i]) == ws.Namespace {
+			return &projects.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// processNextEvent delivers the next event from the queue and schedules a retry, if the delivery failed.
+// Returns false if the queue has been shut down.
+func (c *EventSinkController) processNextEvent(ctx context.Context) bool {
+	e, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer c.queue.Done(e)
+
+	log := c.log.WithValues("id", e.ID, "type", e.Type, "kind", e.Kind, "name", e.Name, "namespace", e.Namespace)
+	if err := c.deliver(ctx, e); err != nil {
+		if c.queue.ShuttingDown() {
+			log.Error(err, "Failed to deliver event during shutdown, dropping it")
+			metrics.EventSinkDeliveries.WithLabelValues(metrics.EventSinkResultDropped).Inc()
+			c.queue.Forget(e)
+			return true
+		}
+		if retries := c.queue.NumRequeues(e); retries < c.cfg.GetMaxRetries() {
+			log.Info("Failed to deliver event, retrying", "error", err.Error(), "retries", retries)
+			metrics.EventSinkDeliveries.WithLabelValues(metrics.EventSinkResultRetried).Inc()
+			c.queue.AddRateLimited(e)
+			return true
+		}
+		log.Error(err, "Failed to deliver event, dropping it after exhausting all retries")
+		metrics.EventSinkDeliveries.WithLabelValues(metrics.EventSinkResultDropped).Inc()
+		c.queue.Forget(e)
+		return true
+	}
+	log.Debug("Delivered event")
+	metrics.EventSinkDeliveries.WithLabelValues(metrics.EventSinkResultDelivered).Inc()
+	c.queue.Forget(e)
+	return true
+}
+
+// deliver POSTs the given event as JSON to the event sink, signed with the current signing key.
+// Responses with a status code outside of the 2xx range are treated as failed deliveries.
+func (c *EventSinkController) deliver(ctx context.Context, e *Event) error {
+	key, err := c.signingKey(ctx)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventIDHeader, e.ID)
+	req.Header.Set(SignatureHeader, Sign(key, body))
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send event: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("event sink responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// signingKey reads the signing key from the configured secret on the platform cluster.
+func (c *EventSinkController) signingKey(ctx context.Context) ([]byte, error) {
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Name: c.cfg.SigningSecretName, Namespace: c.podNamespace}
+	if err := c.platformCluster.Client().Get(ctx, key, secret); err != nil {
+		return nil, fmt.Errorf("failed to get signing secret '%s': %w", key.String(), err)
+	}
+	signingKey := secret.Data[pwv1alpha1.EventSinkSigningKeyKey]
+	if len(signingKey) == 0 {
+		return nil, fmt.Errorf("signing secret '%s' does not contain key '%s'", key.String(), pwv1alpha1.EventSinkSigningKeyKey)
+	}
+	return signingKey, nil
+}
@@ -0,0 +1,302 @@
+package eventsink
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openmcp-project/controller-utils/pkg/clusters"
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/api/v2/install"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/export"
+)
+
+const (
+	podNamespace      = "openmcp-system"
+	signingSecretName = "event-sink"
+)
+
+var (
+	signingKey = []byte("s3cr3t")
+
+	onboardingScheme = install.InstallOperatorAPIsOnboarding(runtime.NewScheme())
+
+	sampleProject = &pwv1alpha1.Project{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "sample",
+			UID:         "project-uid",
+			Annotations: map[string]string{pwv1alpha1.ChargingTargetAnnotation: "cc-1234"},
+		},
+		Spec: pwv1alpha1.ProjectSpec{
+			Members: []pwv1alpha1.ProjectMember{
+				{
+					Subject: pwv1alpha1.Subject{Kind: rbacv1.UserKind, Name: "admin@example.com"},
+					Roles:   []pwv1alpha1.ProjectMemberRole{pwv1alpha1.ProjectRoleAdmin},
+				},
+			},
+		},
+		Status: pwv1alpha1.ProjectStatus{Namespace: "project-sample"},
+	}
+
+	sampleWorkspace = &pwv1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "dev",
+			Namespace:       "project-sample",
+			UID:             "workspace-uid",
+			ResourceVersion: "1",
+		},
+		Spec: pwv1alpha1.WorkspaceSpec{
+			InheritProjectMembers: &pwv1alpha1.InheritProjectMembers{Enabled: true},
+		},
+	}
+)
+
+func newTestController(t *testing.T, url string, objs ...client.Object) *EventSinkController {
+	t.Helper()
+	platformClient := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: signingSecretName, Namespace: podNamespace},
+		Data:       map[string][]byte{pwv1alpha1.EventSinkSigningKeyKey: signingKey},
+	}).Build()
+	onboardingClient := fake.NewClientBuilder().WithScheme(onboardingScheme).WithObjects(objs...).Build()
+
+	c, err := NewEventSinkController(pwv1alpha1.EventSinkConfig{
+		URL:               url,
+		SigningSecretName: signingSecretName,
+		MaxRetries:        ptr.To[int32](1),
+	}, clusters.NewTestClusterFromClient("platform", platformClient), podNamespace)
+	require.NoError(t, err)
+	c.reader = onboardingClient
+	c.queue = workqueue.NewTypedRateLimitingQueue(workqueue.NewTypedItemExponentialFailureRateLimiter[*Event](time.Millisecond, time.Millisecond))
+	t.Cleanup(c.queue.ShutDown)
+	return c
+}
+
+func Test_NewEventSinkController(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		url         string
+		expectError bool
+	}{
+		{
+			desc: "should accept an https URL",
+			url:  "https://cmdb.example.com/events",
+		},
+		{
+			desc:        "should reject an http URL",
+			url:         "http://cmdb.example.com/events",
+			expectError: true,
+		},
+		{
+			desc:        "should reject a relative URL",
+			url:         "/events",
+			expectError: true,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			_, err := NewEventSinkController(pwv1alpha1.EventSinkConfig{
+				URL:               tC.url,
+				SigningSecretName: signingSecretName,
+			}, clusters.NewTestClusterFromClient("platform", fake.NewClientBuilder().Build()), podNamespace)
+			if tC.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func newContext() context.Context {
+	return logging.NewContext(context.Background(), logging.Discard())
+}
+
+func Test_EventSinkController_deliver(t *testing.T) {
+	var received []*Event
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, Sign(signingKey, body), r.Header.Get(SignatureHeader), "the body must be signed with the key from the secret")
+
+		e := &Event{}
+		require.NoError(t, json.Unmarshal(body, e))
+		assert.Equal(t, e.ID, r.Header.Get(EventIDHeader))
+		received = append(received, e)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	ctx := newContext()
+	c := newTestController(t, server.URL, sampleProject)
+	c.HTTPClient = server.Client()
+
+	e, err := c.newEvent(ctx, EventTypeCreated, sampleWorkspace)
+	require.NoError(t, err)
+	require.NoError(t, c.deliver(ctx, e))
+
+	require.Len(t, received, 1)
+	assert.Equal(t, EventTypeCreated, received[0].Type)
+	assert.Equal(t, KindWorkspace, received[0].Kind)
+	assert.Equal(t, "workspace-uid", received[0].UID)
+	assert.Nil(t, received[0].Project)
+	assert.Equal(t, &export.Workspace{
+		Name:                   "dev",
+		Project:                "sample",
+		ChargingTarget:         "cc-1234",
+		InheritsProjectMembers: true,
+		Members: []export.Member{
+			{Kind: rbacv1.UserKind, Name: "admin@example.com", Roles: []string{"admin"}},
+		},
+	}, received[0].Workspace, "the workspace must contain the members and charging target inherited from the project")
+
+	t.Run("fails if the signing key is missing", func(t *testing.T) {
+		c := newTestController(t, server.URL)
+		c.HTTPClient = server.Client()
+		c.cfg.SigningSecretName = "missing"
+		assert.Error(t, c.deliver(ctx, e))
+	})
+}
+
+func Test_EventSinkController_enqueueUpdate(t *testing.T) {
+	ctx := newContext()
+
+	testCases := []struct {
+		desc         string
+		modify       func(p *pwv1alpha1.Project)
+		expected     int
+		expectedType EventType
+	}{
+		{
+			desc:     "should skip resyncs",
+			modify:   func(p *pwv1alpha1.Project) {},
+			expected: 0,
+		},
+		{
+			desc: "should skip changes which are not exported",
+			modify: func(p *pwv1alpha1.Project) {
+				p.ResourceVersion = "2"
+				p.Status.Conditions = []pwv1alpha1.Condition{{Type: pwv1alpha1.ConditionTypeContentRemaining, Status: pwv1alpha1.ConditionStatusTrue}}
+			},
+			expected: 0,
+		},
+		{
+			desc: "should send changes of the members",
+			modify: func(p *pwv1alpha1.Project) {
+				p.ResourceVersion = "2"
+				p.Spec.Members[0].Roles = append(p.Spec.Members[0].Roles, pwv1alpha1.ProjectRoleView)
+			},
+			expected: 1,
+		},
+		{
+			desc: "should send changes of the namespace in the status",
+			modify: func(p *pwv1alpha1.Project) {
+				p.ResourceVersion = "2"
+				p.Status.Namespace = "changed"
+			},
+			expected: 1,
+		},
+		{
+			desc: "should send projects entering the deletion grace period",
+			modify: func(p *pwv1alpha1.Project) {
+				p.ResourceVersion = "2"
+				p.DeletionTimestamp = ptr.To(metav1.Now())
+				p.Status.Conditions = []pwv1alpha1.Condition{{Type: pwv1alpha1.ConditionTypePendingDeletion, Status: pwv1alpha1.ConditionStatusTrue}}
+			},
+			expected:     1,
+			expectedType: EventTypeDeletionPending,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			c := newTestController(t, "https://cmdb.example.com")
+			updated := sampleProject.DeepCopy()
+			tC.modify(updated)
+
+			c.enqueueUpdate(ctx, sampleProject, updated)
+			assert.Equal(t, tC.expected, c.queue.Len())
+			if tC.expectedType != "" {
+				e, _ := c.queue.Get()
+				assert.Equal(t, tC.expectedType, e.Type)
+				c.queue.Done(e)
+			}
+		})
+	}
+}
+
+func Test_EventSinkController_processNextEvent(t *testing.T) {
+	status := atomic.Int32{}
+	status.Store(http.StatusInternalServerError)
+	requests := atomic.Int32{}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer server.Close()
+
+	ctx := newContext()
+	c := newTestController(t, server.URL)
+	c.HTTPClient = server.Client()
+
+	c.enqueue(ctx, EventTypeDeleted, sampleProject)
+	require.Equal(t, 1, c.queue.Len())
+
+	// the first delivery fails and is retried
+	assert.True(t, c.processNextEvent(ctx))
+	assert.Eventually(t, func() bool { return c.queue.Len() == 1 }, time.Second, time.Millisecond, "failed deliveries must be retried")
+
+	// the retry fails as well and the event is dropped, because only one retry is configured
+	assert.True(t, c.processNextEvent(ctx))
+	assert.Never(t, func() bool { return c.queue.Len() != 0 }, 50*time.Millisecond, time.Millisecond, "events must be dropped after exhausting all retries")
+	assert.Equal(t, int32(2), requests.Load())
+
+	status.Store(http.StatusOK)
+	c.enqueue(ctx, EventTypeCreated, sampleProject)
+	assert.True(t, c.processNextEvent(ctx))
+	assert.Equal(t, 0, c.queue.Len())
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func Test_EventSinkController_processNextEvent_shutdown(t *testing.T) {
+	requests := atomic.Int32{}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// only the first delivery succeeds
+		if requests.Add(1) > 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	ctx := newContext()
+	c := newTestController(t, server.URL)
+	c.HTTPClient = server.Client()
+
+	c.enqueue(ctx, EventTypeCreated, sampleProject)
+	c.enqueue(ctx, EventTypeCreated, sampleWorkspace)
+	c.queue.ShutDown()
+
+	// queued events are still delivered after the shutdown, but failed deliveries are not retried
+	assert.True(t, c.processNextEvent(ctx))
+	assert.True(t, c.processNextEvent(ctx))
+	assert.False(t, c.processNextEvent(ctx))
+	assert.Equal(t, int32(2), requests.Load())
+}
@@ -0,0 +1,384 @@
+package health
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/openmcp-project/controller-utils/pkg/clusters"
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+	openmcpconst "github.com/openmcp-project/openmcp-operator/api/constants"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/core"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/metrics"
+)
+
+// Static Stuff //
+
+const (
+	ControllerName = "pwo-health"
+
+	// DefaultInterval is the default interval in which the PWOHealth status is updated.
+	DefaultInterval = 1 * time.Minute
+	// DefaultErrorRateThreshold is the default ratio of failed reconciles since the previous update above which a controller is considered unhealthy.
+	DefaultErrorRateThreshold = 0.5
+	// DefaultCertificateExpiryThreshold is the default remaining validity of the webhook certificate below which it is reported as expiring.
+	DefaultCertificateExpiryThreshold = 14 * 24 * time.Hour
+
+	reconcileTotalMetric = "controller_runtime_reconcile_total"
+	reconcileResultError = "error"
+)
+
+// CertificateGetter returns the certificate whose expiry date should be reported.
+type CertificateGetter func(ctx context.Context) (*x509.Certificate, error)
+
+// SecretCertificate returns a CertificateGetter which reads the certificate from the given TLS secret.
+func SecretCertificate(c client.Client, key client.ObjectKey) CertificateGetter {
+	return func(ctx context.Context) (*x509.Certificate, error) {
+		secret := &corev1.Secret{}
+		if err := c.Get(ctx, key, secret); err != nil {
+			return nil, fmt.Errorf("failed to get secret '%s': %w", key.String(), err)
+		}
+		block, _ := pem.Decode(secret.Data[corev1.TLSCertKey])
+		if block == nil {
+			return nil, fmt.Errorf("secret '%s' does not contain a PEM encoded certificate in key '%s'", key.String(), corev1.TLSCertKey)
+		}
+		return x509.ParseCertificate(block.Bytes)
+	}
+}
+
+// TLSCertificate returns a CertificateGetter which uses the given function to fetch the certificate, e.g. from a certwatcher.CertWatcher.
+func TLSCertificate(getCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)) CertificateGetter {
+	return func(_ context.Context) (*x509.Certificate, error) {
+		cert, err := getCertificate(nil)
+		if err != nil {
+			return nil, err
+		}
+		if cert == nil || len(cert.Certificate) == 0 {
+			return nil, fmt.Errorf("no certificate loaded")
+		}
+		if cert.Leaf != nil {
+			return cert.Leaf, nil
+		}
+		return x509.ParseCertificate(cert.Certificate[0])
+	}
+}
+
+// Setup //
+
+// HealthController periodically updates the status of the PWOHealth resource on the platform cluster.
+// The resource has the same name as the ProjectWorkspaceConfig and is created if it does not exist.
+type HealthController struct {
+	providerName    string
+	platformCluster *clusters.Cluster
+	podNamespace    string
+	controllers     []string
+	log             logging.Logger
+
+	webhookCertificate       CertificateGetter
+	webhookCertificateSource string
+
+	// Gatherer is used to read the controller metrics. Defaults to the controller-runtime metrics registry.
+	Gatherer prometheus.Gatherer
+	// Interval is the interval in which the status is updated.
+	Interval time.Duration
+	// ErrorRateThreshold is the ratio of failed reconciles since the previous update above which a controller is considered unhealthy.
+	ErrorRateThreshold float64
+	// CertificateExpiryThreshold is the remaining validity of the webhook certificate below which it is reported as expiring.
+	CertificateExpiryThreshold time.Duration
+
+	// previous holds the reconcile counts per controller from the previous update
+	previous map[string]reconcileCounts
+}
+
+type reconcileCounts struct {
+	total  int64
+	errors int64
+}
+
+// NewHealthController creates a new HealthController which reports the given controllers.
+// The names must match the names the controllers are registered with at the manager.
+func NewHealthController(providerName string, platformCluster *clusters.Cluster, podNamespace string, controllers ...string) *HealthController {
+	return &HealthController{
+		providerName:               providerName,
+		platformCluster:            platformCluster,
+		podNamespace:               podNamespace,
+		controllers:                controllers,
+		log:                        logging.Discard(),
+		Gatherer:                   ctrlmetrics.Registry,
+		Interval:                   DefaultInterval,
+		ErrorRateThreshold:         DefaultErrorRateThreshold,
+		CertificateExpiryThreshold: DefaultCertificateExpiryThreshold,
+		previous:                   map[string]reconcileCounts{},
+	}
+}
+
+// WithWebhookCertificate configures the certificate of the webhooks whose expiry date should be reported.
+// The source is a human-readable description of where the certificate is read from.
+func (c *HealthController) WithWebhookCertificate(source string, getter CertificateGetter) *HealthController {
+	c.webhookCertificateSource = source
+	c.webhookCertificate = getter
+	return c
+}
+
+// SetupWithManager adds the controller to the manager.
+// Since it is added as a runnable which requires leader election, the status is only updated by the leading replica.
+func (c *HealthController) SetupWithManager(mgr ctrl.Manager) error {
+	c.log = logging.Wrap(mgr.GetLogger()).WithName(ControllerName)
+	return mgr.Add(c)
+}
+
+var _ manager.Runnable = &HealthController{}
+
+// Start updates the status of the PWOHealth resource in the configured interval until the context is cancelled.
+func (c *HealthController) Start(ctx context.Context) error {
+	ctx = logging.NewContext(ctx, c.log)
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+	for {
+		if err := c.update(ctx); err != nil {
+			c.log.Error(err, "Failed to update PWOHealth status")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// update fetches or creates the PWOHealth resource and updates its status.
+func (c *HealthController) update(ctx context.Context) error {
+	log := logging.FromContextOrPanic(ctx)
+
+	health := &pwv1alpha1.PWOHealth{}
+	health.Name = c.providerName
+	if err := c.platformCluster.Client().Get(ctx, client.ObjectKeyFromObject(health), health); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get PWOHealth '%s': %w", c.providerName, err)
+		}
+		log.Info("Creating PWOHealth", "name", c.providerName)
+		if err := c.platformCluster.Client().Create(ctx, health); err != nil {
+			return fmt.Errorf("failed to create PWOHealth '%s': %w", c.providerName, err)
+		}
+	}
+
+	if err := c.updateControllers(health); err != nil {
+		return err
+	}
+	c.updateWebhookCertificate(ctx, health)
+	c.updateAccessRequests(ctx, health)
+	health.Status.LastUpdateTime = metav1.Now()
+
+	if err := c.platformCluster.Client().Status().Update(ctx, health); err != nil {
+		return fmt.Errorf("failed to update status of PWOHealth '%s': %w", c.providerName, err)
+	}
+	log.Debug("Updated PWOHealth status", "name", c.providerName)
+	return nil
+}
+
+// updateControllers computes the reconcile statistics of the controllers from the gathered metrics.
+func (c *HealthController) updateControllers(health *pwv1alpha1.PWOHealth) error {
+	families, err := c.Gatherer.Gather()
+	if err != nil {
+		return fmt.Errorf("failed to gather metrics: %w", err)
+	}
+
+	counts := map[string]reconcileCounts{}
+	lastSuccess := map[string]time.Time{}
+	for _, family := range families {
+		switch family.GetName() {
+		case reconcileTotalMetric:
+			for _, m := range family.GetMetric() {
+				name := labelValue(m, "controller")
+				cnt := counts[name]
+				value := int64(m.GetCounter().GetValue())
+				cnt.total += value
+				if labelValue(m, "result") == reconcileResultError {
+					cnt.errors += value
+				}
+				counts[name] = cnt
+			}
+		case metrics.LastSuccessfulReconcileMetricName:
+			for _, m := range family.GetMetric() {
+				if ts := m.GetGauge().GetValue(); ts > 0 {
+					lastSuccess[labelValue(m, "controller")] = time.Unix(0, int64(ts*float64(time.Second)))
+				}
+			}
+		}
+	}
+
+	unhealthy := []string{}
+	health.Status.Controllers = make([]pwv1alpha1.ControllerHealth, 0, len(c.controllers))
+	for _, name := range c.controllers {
+		cnt := counts[name]
+		prev := c.previous[name]
+		ch := pwv1alpha1.ControllerHealth{
+			Name:             name,
+			Reconciles:       cnt.total,
+			Errors:           cnt.errors,
+			RecentReconciles: cnt.total - prev.total,
+			RecentErrors:     cnt.errors - prev.errors,
+		}
+		if ts, ok := lastSuccess[name]; ok {
+			ch.LastSuccessfulReconcileTime = &metav1.Time{Time: ts}
+		}
+		if ch.RecentReconciles > 0 && float64(ch.RecentErrors)/float64(ch.RecentReconciles) > c.ErrorRateThreshold {
+			unhealthy = append(unhealthy, name)
+		}
+		health.Status.Controllers = append(health.Status.Controllers, ch)
+		c.previous[name] = cnt
+	}
+
+	if len(unhealthy) > 0 {
+		health.SetOrUpdateCondition(pwv1alpha1.Condition{
+			Type:    pwv1alpha1.ConditionTypeControllersHealthy,
+			Status:  pwv1alpha1.ConditionStatusFalse,
+			Reason:  pwv1alpha1.ConditionReasonReconcileErrors,
+			Message: fmt.Sprintf("More than %.0f%% of the recent reconciles failed for controllers: %s", c.ErrorRateThreshold*100, strings.Join(unhealthy, ", ")),
+		})
+	} else {
+		health.SetOrUpdateCondition(pwv1alpha1.Condition{
+			Type:    pwv1alpha1.ConditionTypeControllersHealthy,
+			Status:  pwv1alpha1.ConditionStatusTrue,
+			Reason:  pwv1alpha1.ConditionReasonHealthy,
+			Message: "All controllers are reconciling successfully",
+		})
+	}
+	return nil
+}
+
+// updateWebhookCertificate reports the expiry date of the webhook certificate, if configured.
+func (c *HealthController) updateWebhookCertificate(ctx context.Context, health *pwv1alpha1.PWOHealth) {
+	if c.webhookCertificate == nil {
+		health.Status.WebhookCertificate = nil
+		health.RemoveCondition(pwv1alpha1.ConditionTypeWebhookCertificateValid)
+		return
+	}
+
+	health.Status.WebhookCertificate = &pwv1alpha1.CertificateHealth{
+		Source: c.webhookCertificateSource,
+	}
+	cert, err := c.webhookCertificate(ctx)
+	if err != nil {
+		health.Status.WebhookCertificate.Error = err.Error()
+		health.SetOrUpdateCondition(pwv1alpha1.Condition{
+			Type:    pwv1alpha1.ConditionTypeWebhookCertificateValid,
+			Status:  pwv1alpha1.ConditionStatusUnknown,
+			Reason:  pwv1alpha1.ConditionReasonCertificateUnavailable,
+			Message: fmt.Sprintf("Unable to read webhook certificate: %s", err.Error()),
+		})
+		return
+	}
+
+	notAfter := metav1.NewTime(cert.NotAfter)
+	health.Status.WebhookCertificate.NotAfter = &notAfter
+	condition := pwv1alpha1.Condition{
+		Type:    pwv1alpha1.ConditionTypeWebhookCertificateValid,
+		Status:  pwv1alpha1.ConditionStatusTrue,
+		Reason:  pwv1alpha1.ConditionReasonHealthy,
+		Message: fmt.Sprintf("Webhook certificate is valid until %s", cert.NotAfter.UTC().Format(time.RFC3339)),
+	}
+	switch remaining := time.Until(cert.NotAfter); {
+	case remaining <= 0:
+		condition.Status = pwv1alpha1.ConditionStatusFalse
+		condition.Reason = pwv1alpha1.ConditionReasonCertificateExpired
+		condition.Message = fmt.Sprintf("Webhook certificate expired at %s", cert.NotAfter.UTC().Format(time.RFC3339))
+	case remaining < c.CertificateExpiryThreshold:
+		condition.Status = pwv1alpha1.ConditionStatusFalse
+		condition.Reason = pwv1alpha1.ConditionReasonCertificateExpiring
+		condition.Message = fmt.Sprintf("Webhook certificate expires at %s", cert.NotAfter.UTC().Format(time.RFC3339))
+	}
+	health.SetOrUpdateCondition(condition)
+}
+
+// updateAccessRequests reports the state of the AccessRequests which are used to access the onboarding cluster.
+func (c *HealthController) updateAccessRequests(ctx context.Context, health *pwv1alpha1.PWOHealth) {
+	ars := &clustersv1alpha1.AccessRequestList{}
+	err := c.listAccessRequests(ctx, ars)
+	if err != nil {
+		health.Status.AccessRequests = nil
+		health.SetOrUpdateCondition(pwv1alpha1.Condition{
+			Type:    pwv1alpha1.ConditionTypeAccessRequestsGranted,
+			Status:  pwv1alpha1.ConditionStatusUnknown,
+			Reason:  pwv1alpha1.ConditionReasonAccessRequestsUnavailable,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	slices.SortFunc(ars.Items, func(a, b clustersv1alpha1.AccessRequest) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	notGranted := []string{}
+	health.Status.AccessRequests = make([]pwv1alpha1.AccessRequestHealth, 0, len(ars.Items))
+	for _, ar := range ars.Items {
+		health.Status.AccessRequests = append(health.Status.AccessRequests, pwv1alpha1.AccessRequestHealth{
+			Name:      ar.Name,
+			Namespace: ar.Namespace,
+			Phase:     ar.Status.Phase,
+		})
+		if !ar.Status.IsGranted() {
+			notGranted = append(notGranted, fmt.Sprintf("%s (%s)", ar.Name, ar.Status.Phase))
+		}
+	}
+
+	if len(notGranted) > 0 {
+		health.SetOrUpdateCondition(pwv1alpha1.Condition{
+			Type:    pwv1alpha1.ConditionTypeAccessRequestsGranted,
+			Status:  pwv1alpha1.ConditionStatusFalse,
+			Reason:  pwv1alpha1.ConditionReasonAccessRequestsNotGranted,
+			Message: fmt.Sprintf("AccessRequests not granted: %s", strings.Join(notGranted, ", ")),
+		})
+	} else {
+		health.SetOrUpdateCondition(pwv1alpha1.Condition{
+			Type:    pwv1alpha1.ConditionTypeAccessRequestsGranted,
+			Status:  pwv1alpha1.ConditionStatusTrue,
+			Reason:  pwv1alpha1.ConditionReasonHealthy,
+			Message: "All AccessRequests are granted",
+		})
+	}
+}
+
+// listAccessRequests lists the AccessRequests in the pod namespace which have been created for the platform service.
+// These are the one for the static onboarding cluster access and the one managed by the ProjectWorkspaceConfig controller.
+func (c *HealthController) listAccessRequests(ctx context.Context, ars *clustersv1alpha1.AccessRequestList) error {
+	req, err := labels.NewRequirement(openmcpconst.ManagedByLabel, selection.In, []string{core.ControllerName, sharedconfig.ControllerName})
+	if err != nil {
+		return fmt.Errorf("failed to build label selector for AccessRequests: %w", err)
+	}
+	if err := c.platformCluster.Client().List(ctx, ars, client.InNamespace(c.podNamespace), client.MatchingLabelsSelector{Selector: labels.NewSelector().Add(*req)}); err != nil {
+		return fmt.Errorf("failed to list AccessRequests: %w", err)
+	}
+	return nil
+}
+
+// labelValue returns the value of the label with the given name, or an empty string if the metric does not have it.
+func labelValue(m *dto.Metric, name string) string {
+	for _, lp := range m.GetLabel() {
+		if lp.GetName() == name {
+			return lp.GetValue()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,270 @@
+package health
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openmcp-project/controller-utils/pkg/clusters"
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+	commonapi "github.com/openmcp-project/openmcp-operator/api/common"
+	openmcpconst "github.com/openmcp-project/openmcp-operator/api/constants"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/api/v2/install"
+	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/core"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/metrics"
+)
+
+const (
+	providerName = "project-workspace"
+	podNamespace = "openmcp-system"
+)
+
+var platformScheme = install.InstallOperatorAPIsPlatform(runtime.NewScheme())
+
+type testEnv struct {
+	client          client.Client
+	controller      *HealthController
+	reconcileTotal  *prometheus.CounterVec
+	lastSuccessTime *prometheus.GaugeVec
+}
+
+func newTestEnv(t *testing.T, objs ...client.Object) *testEnv {
+	t.Helper()
+	c := fake.NewClientBuilder().WithScheme(platformScheme).WithObjects(objs...).WithStatusSubresource(&pwv1alpha1.PWOHealth{}).Build()
+
+	registry := prometheus.NewRegistry()
+	reconcileTotal := prometheus.NewCounterVec(prometheus.CounterOpts{Name: reconcileTotalMetric}, []string{"controller", "result"})
+	lastSuccessTime := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: metrics.LastSuccessfulReconcileMetricName}, []string{"controller"})
+	registry.MustRegister(reconcileTotal, lastSuccessTime)
+
+	hc := NewHealthController(providerName, clusters.NewTestClusterFromClient("platform", c), podNamespace, core.ProjectControllerName, core.WorkspaceControllerName)
+	hc.Gatherer = registry
+
+	return &testEnv{
+		client:          c,
+		controller:      hc,
+		reconcileTotal:  reconcileTotal,
+		lastSuccessTime: lastSuccessTime,
+	}
+}
+
+func (env *testEnv) update(t *testing.T) *pwv1alpha1.PWOHealth {
+	t.Helper()
+	ctx := logging.NewContext(context.Background(), logging.Discard())
+	require.NoError(t, env.controller.update(ctx))
+	health := &pwv1alpha1.PWOHealth{}
+	require.NoError(t, env.client.Get(ctx, client.ObjectKey{Name: providerName}, health))
+	return health
+}
+
+func findCondition(health *pwv1alpha1.PWOHealth, conditionType pwv1alpha1.ConditionType) *pwv1alpha1.Condition {
+	for i := range health.Status.Conditions {
+		if health.Status.Conditions[i].Type == conditionType {
+			return &health.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+func certificatePEM(t *testing.T, notAfter time.Time) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "webhook"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestUpdateControllers(t *testing.T) {
+	env := newTestEnv(t)
+	env.reconcileTotal.WithLabelValues(core.ProjectControllerName, "success").Add(3)
+	env.reconcileTotal.WithLabelValues(core.ProjectControllerName, "requeue_after").Add(1)
+	env.reconcileTotal.WithLabelValues(core.WorkspaceControllerName, "error").Add(2)
+	env.reconcileTotal.WithLabelValues(sharedconfig.ReconcilerName, "error").Add(5)
+	lastSuccess := time.Unix(1700000000, 0)
+	env.lastSuccessTime.WithLabelValues(core.ProjectControllerName).Set(float64(lastSuccess.Unix()))
+
+	health := env.update(t)
+	require.Len(t, health.Status.Controllers, 2, "only the configured controllers should be reported")
+	assert.Equal(t, core.ProjectControllerName, health.Status.Controllers[0].Name)
+	assert.Equal(t, int64(4), health.Status.Controllers[0].Reconciles)
+	assert.Equal(t, int64(0), health.Status.Controllers[0].Errors)
+	require.NotNil(t, health.Status.Controllers[0].LastSuccessfulReconcileTime)
+	assert.True(t, lastSuccess.Equal(health.Status.Controllers[0].LastSuccessfulReconcileTime.Time))
+	assert.Nil(t, health.Status.Controllers[1].LastSuccessfulReconcileTime)
+	assert.Equal(t, int64(2), health.Status.Controllers[1].RecentErrors)
+
+	condition := findCondition(health, pwv1alpha1.ConditionTypeControllersHealthy)
+	require.NotNil(t, condition)
+	assert.Equal(t, pwv1alpha1.ConditionStatusFalse, condition.Status)
+	assert.Equal(t, pwv1alpha1.ConditionReasonReconcileErrors, condition.Reason)
+	assert.Contains(t, condition.Message, core.WorkspaceControllerName)
+	assert.NotContains(t, condition.Message, core.ProjectControllerName)
+
+	// only the reconciles since the previous update count towards the error rate
+	env.reconcileTotal.WithLabelValues(core.WorkspaceControllerName, "success").Add(4)
+	env.reconcileTotal.WithLabelValues(core.WorkspaceControllerName, "error").Add(1)
+
+	health = env.update(t)
+	assert.Equal(t, int64(7), health.Status.Controllers[1].Reconciles)
+	assert.Equal(t, int64(3), health.Status.Controllers[1].Errors)
+	assert.Equal(t, int64(5), health.Status.Controllers[1].RecentReconciles)
+	assert.Equal(t, int64(1), health.Status.Controllers[1].RecentErrors)
+	assert.Equal(t, int64(0), health.Status.Controllers[0].RecentReconciles)
+
+	condition = findCondition(health, pwv1alpha1.ConditionTypeControllersHealthy)
+	require.NotNil(t, condition)
+	assert.Equal(t, pwv1alpha1.ConditionStatusTrue, condition.Status)
+}
+
+func TestUpdateWebhookCertificate(t *testing.T) {
+	secretKey := client.ObjectKey{Name: "webhook-tls", Namespace: podNamespace}
+
+	tests := []struct {
+		description    string
+		secret         *corev1.Secret
+		expectedStatus pwv1alpha1.ConditionStatus
+		expectedReason pwv1alpha1.ConditionReason
+		expectNotAfter bool
+	}{
+		{
+			description: "reports a valid certificate",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: secretKey.Name, Namespace: secretKey.Namespace},
+				Data:       map[string][]byte{corev1.TLSCertKey: certificatePEM(t, time.Now().Add(90*24*time.Hour))},
+			},
+			expectedStatus: pwv1alpha1.ConditionStatusTrue,
+			expectedReason: pwv1alpha1.ConditionReasonHealthy,
+			expectNotAfter: true,
+		},
+		{
+			description: "reports a certificate which expires soon",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: secretKey.Name, Namespace: secretKey.Namespace},
+				Data:       map[string][]byte{corev1.TLSCertKey: certificatePEM(t, time.Now().Add(24*time.Hour))},
+			},
+			expectedStatus: pwv1alpha1.ConditionStatusFalse,
+			expectedReason: pwv1alpha1.ConditionReasonCertificateExpiring,
+			expectNotAfter: true,
+		},
+		{
+			description: "reports an expired certificate",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: secretKey.Name, Namespace: secretKey.Namespace},
+				Data:       map[string][]byte{corev1.TLSCertKey: certificatePEM(t, time.Now().Add(-time.Minute))},
+			},
+			expectedStatus: pwv1alpha1.ConditionStatusFalse,
+			expectedReason: pwv1alpha1.ConditionReasonCertificateExpired,
+			expectNotAfter: true,
+		},
+		{
+			description:    "reports a missing certificate as unknown",
+			expectedStatus: pwv1alpha1.ConditionStatusUnknown,
+			expectedReason: pwv1alpha1.ConditionReasonCertificateUnavailable,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			objs := []client.Object{}
+			if test.secret != nil {
+				objs = append(objs, test.secret)
+			}
+			env := newTestEnv(t, objs...)
+			env.controller.WithWebhookCertificate("secret "+secretKey.String(), SecretCertificate(env.client, secretKey))
+
+			health := env.update(t)
+			require.NotNil(t, health.Status.WebhookCertificate)
+			assert.Equal(t, "secret "+secretKey.String(), health.Status.WebhookCertificate.Source)
+			assert.Equal(t, test.expectNotAfter, health.Status.WebhookCertificate.NotAfter != nil)
+			assert.Equal(t, !test.expectNotAfter, health.Status.WebhookCertificate.Error != "")
+
+			condition := findCondition(health, pwv1alpha1.ConditionTypeWebhookCertificateValid)
+			require.NotNil(t, condition)
+			assert.Equal(t, test.expectedStatus, condition.Status)
+			assert.Equal(t, test.expectedReason, condition.Reason)
+		})
+	}
+
+	t.Run("does not report a certificate if the webhooks are disabled", func(t *testing.T) {
+		health := newTestEnv(t).update(t)
+		assert.Nil(t, health.Status.WebhookCertificate)
+		assert.Nil(t, findCondition(health, pwv1alpha1.ConditionTypeWebhookCertificateValid))
+	})
+}
+
+func TestUpdateAccessRequests(t *testing.T) {
+	accessRequest := func(name, managedBy, phase string) *clustersv1alpha1.AccessRequest {
+		return &clustersv1alpha1.AccessRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: podNamespace,
+				Labels: map[string]string{
+					openmcpconst.ManagedByLabel: managedBy,
+				},
+			},
+			Status: clustersv1alpha1.AccessRequestStatus{
+				Status: commonapi.Status{
+					Phase: phase,
+				},
+			},
+		}
+	}
+
+	t.Run("reports granted AccessRequests as healthy", func(t *testing.T) {
+		env := newTestEnv(t,
+			accessRequest("onboarding", core.ControllerName, clustersv1alpha1.REQUEST_GRANTED),
+			accessRequest("onboarding-dynamic", sharedconfig.ControllerName, clustersv1alpha1.REQUEST_GRANTED),
+			accessRequest("other", "other-controller", clustersv1alpha1.REQUEST_DENIED),
+		)
+		health := env.update(t)
+		assert.Equal(t, []pwv1alpha1.AccessRequestHealth{
+			{Name: "onboarding", Namespace: podNamespace, Phase: clustersv1alpha1.REQUEST_GRANTED},
+			{Name: "onboarding-dynamic", Namespace: podNamespace, Phase: clustersv1alpha1.REQUEST_GRANTED},
+		}, health.Status.AccessRequests)
+
+		condition := findCondition(health, pwv1alpha1.ConditionTypeAccessRequestsGranted)
+		require.NotNil(t, condition)
+		assert.Equal(t, pwv1alpha1.ConditionStatusTrue, condition.Status)
+	})
+
+	t.Run("reports pending AccessRequests", func(t *testing.T) {
+		env := newTestEnv(t,
+			accessRequest("onboarding", core.ControllerName, clustersv1alpha1.REQUEST_GRANTED),
+			accessRequest("onboarding-dynamic", sharedconfig.ControllerName, clustersv1alpha1.REQUEST_PENDING),
+		)
+		health := env.update(t)
+
+		condition := findCondition(health, pwv1alpha1.ConditionTypeAccessRequestsGranted)
+		require.NotNil(t, condition)
+		assert.Equal(t, pwv1alpha1.ConditionStatusFalse, condition.Status)
+		assert.Equal(t, pwv1alpha1.ConditionReasonAccessRequestsNotGranted, condition.Reason)
+		assert.Contains(t, condition.Message, "onboarding-dynamic (Pending)")
+	})
+}
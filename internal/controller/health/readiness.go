@@ -0,0 +1,49 @@
+package health
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+// WebhookCertificateReadyzCheckName is the name of the readiness check for the webhook certificate.
+const WebhookCertificateReadyzCheckName = "webhook-certificate"
+
+// CertificateReadyzCheck returns a readiness check which fails if the certificate returned by the given getter cannot be read or is not valid at the time of the check.
+// In contrast to the status of the PWOHealth resource, which is only updated by the leading replica, this keeps each replica unready until it can serve valid certificates.
+func CertificateReadyzCheck(getter CertificateGetter) healthz.Checker {
+	return func(req *http.Request) error {
+		cert, err := getter(req.Context())
+		if err != nil {
+			return fmt.Errorf("webhook certificate is not available: %w", err)
+		}
+		now := time.Now()
+		if now.Before(cert.NotBefore) {
+			return fmt.Errorf("webhook certificate is not valid before %s", cert.NotBefore.UTC().Format(time.RFC3339))
+		}
+		if now.After(cert.NotAfter) {
+			return fmt.Errorf("webhook certificate expired at %s", cert.NotAfter.UTC().Format(time.RFC3339))
+		}
+		return nil
+	}
+}
+
+// WaitForCertificateFiles waits until the given certificate and key files exist and contain a valid key pair.
+// This is used at startup, because mounted certificates may not be available yet when the pod starts.
+// Returns the last error if the files are not available within the given timeout.
+func WaitForCertificateFiles(ctx context.Context, certFile, keyFile string, interval, timeout time.Duration) error {
+	var lastErr error
+	err := wait.PollUntilContextTimeout(ctx, interval, timeout, true, func(_ context.Context) (bool, error) {
+		_, lastErr = tls.LoadX509KeyPair(certFile, keyFile)
+		return lastErr == nil, nil
+	})
+	if err != nil && lastErr != nil {
+		return fmt.Errorf("certificate files '%s' and '%s' are not available: %w", certFile, keyFile, lastErr)
+	}
+	return err
+}
@@ -0,0 +1,98 @@
+package health
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertificateReadyzCheck(t *testing.T) {
+	tests := []struct {
+		description string
+		getter      CertificateGetter
+		expectedErr string
+	}{
+		{
+			description: "ready for a valid certificate",
+			getter: func(_ context.Context) (*x509.Certificate, error) {
+				return &x509.Certificate{NotBefore: time.Now().Add(-time.Hour), NotAfter: time.Now().Add(time.Hour)}, nil
+			},
+		},
+		{
+			description: "not ready if the certificate cannot be read",
+			getter: func(_ context.Context) (*x509.Certificate, error) {
+				return nil, errors.New("no certificate loaded")
+			},
+			expectedErr: "no certificate loaded",
+		},
+		{
+			description: "not ready for a certificate which is not valid yet",
+			getter: func(_ context.Context) (*x509.Certificate, error) {
+				return &x509.Certificate{NotBefore: time.Now().Add(time.Hour), NotAfter: time.Now().Add(2 * time.Hour)}, nil
+			},
+			expectedErr: "not valid before",
+		},
+		{
+			description: "not ready for an expired certificate",
+			getter: func(_ context.Context) (*x509.Certificate, error) {
+				return &x509.Certificate{NotBefore: time.Now().Add(-2 * time.Hour), NotAfter: time.Now().Add(-time.Hour)}, nil
+			},
+			expectedErr: "expired",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := CertificateReadyzCheck(test.getter)(httptest.NewRequest("GET", "/readyz", nil))
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.expectedErr)
+			}
+		})
+	}
+}
+
+func TestWaitForCertificateFiles(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+
+	err := WaitForCertificateFiles(context.Background(), certFile, keyFile, 10*time.Millisecond, 50*time.Millisecond)
+	assert.ErrorContains(t, err, "are not available")
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "webhook"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	// the files appear while waiting, e.g. because the secret is mounted after the pod has started
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600)
+		_ = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	}()
+	assert.NoError(t, WaitForCertificateFiles(context.Background(), certFile, keyFile, 10*time.Millisecond, 5*time.Second))
+}
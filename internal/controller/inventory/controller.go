@@ -0,0 +1,203 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/openmcp-project/controller-utils/pkg/clusters"
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/export"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/logconfig"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/metrics"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+// ControllerName is the name the inventory controller is registered with at the manager.
+const ControllerName = "inventory"
+
+// InventoryController maintains a ProjectInventory on the platform cluster for each Project on the onboarding cluster,
+// which contains a read-only copy of the project and its workspaces for platform-side consumers.
+// ProjectInventories are deleted once their project is gone, and changes to them are reverted.
+type InventoryController struct {
+	cfg              pwv1alpha1.InventoryConfig
+	providerName     string
+	platformCluster  *clusters.Cluster
+	onboardingClient client.Client
+	shared           sharedconfig.SharedInformation
+}
+
+// NewInventoryController creates a new InventoryController for the given configuration.
+// Projects and workspaces are read with the given onboarding client, the ProjectInventories are written to the given platform cluster.
+func NewInventoryController(cfg pwv1alpha1.InventoryConfig, providerName string, platformCluster *clusters.Cluster, onboardingClient client.Client, shared sharedconfig.SharedInformation) *InventoryController {
+	return &InventoryController{
+		cfg:              cfg,
+		providerName:     providerName,
+		platformCluster:  platformCluster,
+		onboardingClient: onboardingClient,
+		shared:           shared,
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// A project is reconciled when it, one of its workspaces or its ProjectInventory changes.
+// Since the ProjectInventories are watched as well, the ones of projects which have been deleted while the controller was not running are removed on startup.
+func (c *InventoryController) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(ControllerName).
+		WithLogConstructor(logconfig.LogConstructor(mgr.GetLogger(), ControllerName)).
+		For(&pwv1alpha1.Project{}).
+		Watches(&pwv1alpha1.Workspace{}, handler.EnqueueRequestsFromMapFunc(func(_ context.Context, obj client.Object) []ctrl.Request {
+			project, ok := projectNameForNamespace(obj.GetNamespace())
+			if !ok {
+				return nil
+			}
+			return []ctrl.Request{{NamespacedName: types.NamespacedName{Name: project}}}
+		})).
+		WatchesRawSource(source.Kind(c.platformCluster.Cluster().GetCache(), &pwv1alpha1.ProjectInventory{}, &handler.TypedEnqueueRequestForObject[*pwv1alpha1.ProjectInventory]{})).
+		Complete(metrics.ObserveReconciler(ControllerName, c))
+}
+
+// projectNameForNamespace returns the name of the project owning the given namespace, see utils.NamespaceForProject.
+func projectNameForNamespace(namespace string) (string, bool) {
+	return strings.CutPrefix(namespace, utils.NamespaceForProject(&pwv1alpha1.Project{}))
+}
+
+var _ reconcile.Reconciler = &InventoryController{}
+
+// Reconcile creates or updates the ProjectInventory of the project with the requested name, or deletes it if the project does not exist.
+// Each project is reconciled again after the resync interval, to heal changes which have been missed.
+func (c *InventoryController) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := logging.FromContextOrPanic(ctx).WithName(ControllerName)
+	ctx = logging.NewContext(ctx, log)
+
+	labels, err := c.shared.ManagementLabels(ctx)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	project := &pwv1alpha1.Project{}
+	if err := c.onboardingClient.Get(ctx, client.ObjectKey{Name: req.Name}, project); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return reconcile.Result{}, fmt.Errorf("failed to get Project: %w", err)
+		}
+		return reconcile.Result{}, c.deleteInventory(ctx, req.Name, labels)
+	}
+
+	workspaces := &pwv1alpha1.WorkspaceList{}
+	if err := c.onboardingClient.List(ctx, workspaces, client.InNamespace(utils.NamespaceForProject(project))); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to list Workspaces of Project: %w", err)
+	}
+
+	inv := &pwv1alpha1.ProjectInventory{ObjectMeta: metav1.ObjectMeta{Name: project.Name}}
+	if err := c.platformCluster.Client().Get(ctx, client.ObjectKeyFromObject(inv), inv); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return reconcile.Result{}, fmt.Errorf("failed to get ProjectInventory: %w", err)
+		}
+		utils.ApplyManagementLabels(inv, c.providerName, labels)
+		inv.Spec = NewProjectInventorySpec(project, workspaces.Items)
+		if err := c.platformCluster.Client().Create(ctx, inv); err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to create ProjectInventory: %w", err)
+		}
+		log.Info("Created ProjectInventory")
+		return reconcile.Result{RequeueAfter: c.cfg.GetResyncInterval()}, nil
+	}
+
+	if !utils.IsManaged(inv, c.providerName, labels) {
+		log.Info("Skipping ProjectInventory which is not managed by this platform service")
+		return reconcile.Result{}, nil
+	}
+	old := inv.DeepCopy()
+	utils.ApplyManagementLabels(inv, c.providerName, labels)
+	inv.Spec = NewProjectInventorySpec(project, workspaces.Items)
+	if !equality.Semantic.DeepEqual(old.Labels, inv.Labels) || !equality.Semantic.DeepEqual(old.Spec, inv.Spec) {
+		if err := c.platformCluster.Client().Update(ctx, inv); err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to update ProjectInventory: %w", err)
+		}
+		log.Debug("Updated ProjectInventory")
+	}
+	return reconcile.Result{RequeueAfter: c.cfg.GetResyncInterval()}, nil
+}
+
+// deleteInventory deletes the ProjectInventory with the given name, if it exists and is managed by this platform service.
+func (c *InventoryController) deleteInventory(ctx context.Context, name string, labels pwv1alpha1.ManagementLabelsConfig) error {
+	inv := &pwv1alpha1.ProjectInventory{}
+	if err := c.platformCluster.Client().Get(ctx, client.ObjectKey{Name: name}, inv); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get ProjectInventory: %w", err)
+	}
+	if !utils.IsManaged(inv, c.providerName, labels) || !inv.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	if err := c.platformCluster.Client().Delete(ctx, inv); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete ProjectInventory: %w", err)
+	}
+	logging.FromContextOrDiscard(ctx).Info("Deleted ProjectInventory of deleted Project")
+	return nil
+}
+
+// NewProjectInventorySpec returns the inventory of the given project and its workspaces.
+// The workspaces are sorted by name, so that the spec does not change with the order in which they are listed.
+func NewProjectInventorySpec(project *pwv1alpha1.Project, workspaces []pwv1alpha1.Workspace) pwv1alpha1.ProjectInventorySpec {
+	exported := export.NewProject(project)
+	spec := pwv1alpha1.ProjectInventorySpec{
+		UID:               string(project.UID),
+		DisplayName:       exported.DisplayName,
+		Namespace:         exported.Namespace,
+		CreatedBy:         exported.CreatedBy,
+		ChargingTarget:    exported.ChargingTarget,
+		Members:           newMembers(exported.Members),
+		DeletionTimestamp: project.DeletionTimestamp,
+	}
+	for i := range workspaces {
+		ws := &workspaces[i]
+		exportedWs := export.NewWorkspace(ws, project)
+		spec.Workspaces = append(spec.Workspaces, pwv1alpha1.WorkspaceInventory{
+			Name:                   ws.Name,
+			UID:                    string(ws.UID),
+			DisplayName:            exportedWs.DisplayName,
+			Namespace:              exportedWs.Namespace,
+			CreatedBy:              exportedWs.CreatedBy,
+			ChargingTarget:         exportedWs.ChargingTarget,
+			InheritsProjectMembers: exportedWs.InheritsProjectMembers,
+			Members:                newMembers(exportedWs.Members),
+			DeletionTimestamp:      ws.DeletionTimestamp,
+		})
+	}
+	slices.SortFunc(spec.Workspaces, func(a, b pwv1alpha1.WorkspaceInventory) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return spec
+}
+
+func newMembers(members []export.Member) []pwv1alpha1.InventoryMember {
+	if len(members) == 0 {
+		return nil
+	}
+	res := make([]pwv1alpha1.InventoryMember, 0, len(members))
+	for _, m := range members {
+		res = append(res, pwv1alpha1.InventoryMember{
+			Kind:      m.Kind,
+			Name:      m.Name,
+			Namespace: m.Namespace,
+			Roles:     m.Roles,
+		})
+	}
+	return res
+}
@@ -0,0 +1,145 @@
+package inventory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openmcp-project/controller-utils/pkg/clusters"
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/inventory"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+func TestInventoryController(t *testing.T) {
+	ctx := logging.NewContext(context.Background(), logging.Discard())
+	scheme := runtime.NewScheme()
+	require.NoError(t, pwv1alpha1.AddToScheme(scheme))
+
+	alice := pwv1alpha1.Subject{Kind: rbacv1.UserKind, Name: "alice"}
+	project := &pwv1alpha1.Project{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "sample",
+			UID:         "project-uid",
+			Annotations: map[string]string{pwv1alpha1.ChargingTargetAnnotation: "cost-center"},
+		},
+		Spec: pwv1alpha1.ProjectSpec{
+			Members: []pwv1alpha1.ProjectMember{{Subject: alice, Roles: []pwv1alpha1.ProjectMemberRole{pwv1alpha1.ProjectRoleAdmin}}},
+		},
+		Status: pwv1alpha1.ProjectStatus{Namespace: "project-sample"},
+	}
+	newWorkspace := func(name string) *pwv1alpha1.Workspace {
+		return &pwv1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "project-sample", UID: types.UID("ws-" + name)},
+			Status:     pwv1alpha1.WorkspaceStatus{Namespace: "project-sample--ws-" + name},
+		}
+	}
+	managed := func(name string) *pwv1alpha1.ProjectInventory {
+		return &pwv1alpha1.ProjectInventory{ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: utils.ManagementLabels("test", pwv1alpha1.ManagementLabelsConfig{}),
+		}}
+	}
+
+	reconcileProject := func(t *testing.T, name string, onboarding client.Client, platform client.Client) reconcile.Result {
+		t.Helper()
+		c := inventory.NewInventoryController(pwv1alpha1.InventoryConfig{}, "test", clusters.NewTestClusterFromClient("platform", platform), onboarding, sharedconfig.NewFakeSharedInformation(onboarding, nil, nil, nil))
+		res, err := c.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKey{Name: name}})
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("creates the inventory of a project with its workspaces", func(t *testing.T) {
+		onboarding := fake.NewClientBuilder().WithScheme(scheme).WithObjects(project, newWorkspace("prod"), newWorkspace("dev")).Build()
+		platform := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		res := reconcileProject(t, "sample", onboarding, platform)
+		assert.Equal(t, pwv1alpha1.DefaultInventoryResyncInterval, res.RequeueAfter)
+
+		inv := &pwv1alpha1.ProjectInventory{}
+		require.NoError(t, platform.Get(ctx, client.ObjectKey{Name: "sample"}, inv))
+		assert.True(t, utils.IsManaged(inv, "test", pwv1alpha1.ManagementLabelsConfig{}))
+		assert.Equal(t, "project-uid", inv.Spec.UID)
+		assert.Equal(t, "project-sample", inv.Spec.Namespace)
+		assert.Equal(t, "cost-center", inv.Spec.ChargingTarget)
+		assert.Equal(t, []pwv1alpha1.InventoryMember{{Kind: rbacv1.UserKind, Name: "alice", Roles: []string{string(pwv1alpha1.ProjectRoleAdmin)}}}, inv.Spec.Members)
+		if assert.Len(t, inv.Spec.Workspaces, 2) {
+			assert.Equal(t, "dev", inv.Spec.Workspaces[0].Name)
+			assert.Equal(t, "ws-dev", inv.Spec.Workspaces[0].UID)
+			assert.Equal(t, "project-sample--ws-dev", inv.Spec.Workspaces[0].Namespace)
+			assert.Equal(t, "cost-center", inv.Spec.Workspaces[0].ChargingTarget)
+			assert.Equal(t, "prod", inv.Spec.Workspaces[1].Name)
+		}
+	})
+
+	t.Run("reverts changes to the inventory", func(t *testing.T) {
+		onboarding := fake.NewClientBuilder().WithScheme(scheme).WithObjects(project).Build()
+		changed := managed("sample")
+		changed.Spec.UID = "other"
+		changed.Spec.Workspaces = []pwv1alpha1.WorkspaceInventory{{Name: "gone", UID: "ws-gone"}}
+		platform := fake.NewClientBuilder().WithScheme(scheme).WithObjects(changed).Build()
+
+		reconcileProject(t, "sample", onboarding, platform)
+
+		inv := &pwv1alpha1.ProjectInventory{}
+		require.NoError(t, platform.Get(ctx, client.ObjectKey{Name: "sample"}, inv))
+		assert.Equal(t, "project-uid", inv.Spec.UID)
+		assert.Empty(t, inv.Spec.Workspaces)
+	})
+
+	t.Run("reports the deletion of a project", func(t *testing.T) {
+		deleting := project.DeepCopy()
+		deleting.DeletionTimestamp = &metav1.Time{Time: time.Now().Truncate(time.Second)}
+		deleting.Finalizers = []string{"test"}
+		onboarding := fake.NewClientBuilder().WithScheme(scheme).WithObjects(deleting).Build()
+		platform := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		reconcileProject(t, "sample", onboarding, platform)
+
+		inv := &pwv1alpha1.ProjectInventory{}
+		require.NoError(t, platform.Get(ctx, client.ObjectKey{Name: "sample"}, inv))
+		require.NotNil(t, inv.Spec.DeletionTimestamp)
+		assert.True(t, deleting.DeletionTimestamp.Equal(inv.Spec.DeletionTimestamp))
+	})
+
+	t.Run("deletes the inventory of a deleted project", func(t *testing.T) {
+		onboarding := fake.NewClientBuilder().WithScheme(scheme).Build()
+		platform := fake.NewClientBuilder().WithScheme(scheme).WithObjects(managed("sample")).Build()
+
+		res := reconcileProject(t, "sample", onboarding, platform)
+		assert.Zero(t, res.RequeueAfter)
+
+		err := platform.Get(ctx, client.ObjectKey{Name: "sample"}, &pwv1alpha1.ProjectInventory{})
+		assert.True(t, apierrors.IsNotFound(err), "expected NotFound, got: %v", err)
+	})
+
+	t.Run("keeps inventories which are not managed by the platform service", func(t *testing.T) {
+		foreign := &pwv1alpha1.ProjectInventory{ObjectMeta: metav1.ObjectMeta{Name: "sample"}, Spec: pwv1alpha1.ProjectInventorySpec{UID: "other"}}
+
+		onboarding := fake.NewClientBuilder().WithScheme(scheme).WithObjects(project).Build()
+		platform := fake.NewClientBuilder().WithScheme(scheme).WithObjects(foreign.DeepCopy()).Build()
+		reconcileProject(t, "sample", onboarding, platform)
+		inv := &pwv1alpha1.ProjectInventory{}
+		require.NoError(t, platform.Get(ctx, client.ObjectKey{Name: "sample"}, inv))
+		assert.Equal(t, "other", inv.Spec.UID)
+
+		onboarding = fake.NewClientBuilder().WithScheme(scheme).Build()
+		platform = fake.NewClientBuilder().WithScheme(scheme).WithObjects(foreign.DeepCopy()).Build()
+		reconcileProject(t, "sample", onboarding, platform)
+		assert.NoError(t, platform.Get(ctx, client.ObjectKey{Name: "sample"}, &pwv1alpha1.ProjectInventory{}))
+	})
+}
@@ -0,0 +1,295 @@
+package webhookcert
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/openmcp-project/controller-utils/pkg/clusters"
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	"github.com/openmcp-project/platform-service-project-workspace/internal/webhookconfig"
+)
+
+// Static Stuff //
+
+const (
+	ControllerName = "webhook-cert"
+
+	// DefaultInterval is the default interval in which the webhook certificate and the webhook configurations are checked.
+	DefaultInterval = 10 * time.Minute
+	// DefaultRotationThreshold is the default remaining validity of the webhook certificate below which it is rotated.
+	DefaultRotationThreshold = 30 * 24 * time.Hour
+	// DefaultCertificateValidity is the default validity of rotated certificates. It matches the validity of the certificate generated by the init command.
+	DefaultCertificateValidity = 10 * 365 * 24 * time.Hour
+)
+
+// Setup //
+
+// CertRotator periodically checks the webhook certificate and the CA bundles of the webhook configurations.
+// It rotates the certificate before it expires and ensures that the webhook configurations trust the current certificate.
+// The certificate is self-signed, so it is its own CA.
+type CertRotator struct {
+	platformCluster   *clusters.Cluster
+	onboardingCluster *clusters.Cluster
+	secret            client.ObjectKey
+	scope             webhookconfig.Scope
+	types             []schema.GroupVersionKind
+	log               logging.Logger
+
+	// Interval is the interval in which the certificate and the webhook configurations are checked.
+	Interval time.Duration
+	// RotationThreshold is the remaining validity of the certificate below which it is rotated.
+	RotationThreshold time.Duration
+	// CertificateValidity is the validity of rotated certificates.
+	CertificateValidity time.Duration
+}
+
+// NewCertRotator creates a new CertRotator for the webhook certificate in the given secret on the platform cluster
+// and the webhook configurations of the given instance for the given types on the onboarding cluster.
+func NewCertRotator(platformCluster, onboardingCluster *clusters.Cluster, secret client.ObjectKey, scope webhookconfig.Scope, types ...schema.GroupVersionKind) *CertRotator {
+	return &CertRotator{
+		platformCluster:     platformCluster,
+		onboardingCluster:   onboardingCluster,
+		secret:              secret,
+		scope:               scope,
+		types:               types,
+		log:                 logging.Discard(),
+		Interval:            DefaultInterval,
+		RotationThreshold:   DefaultRotationThreshold,
+		CertificateValidity: DefaultCertificateValidity,
+	}
+}
+
+// SetupWithManager adds the controller to the manager.
+// Since it is added as a runnable which requires leader election, only the leading replica rotates the certificate.
+func (c *CertRotator) SetupWithManager(mgr ctrl.Manager) error {
+	c.log = logging.Wrap(mgr.GetLogger()).WithName(ControllerName)
+	return mgr.Add(c)
+}
+
+var _ manager.Runnable = &CertRotator{}
+
+// Start checks the certificate and the webhook configurations in the configured interval until the context is cancelled.
+func (c *CertRotator) Start(ctx context.Context) error {
+	ctx = logging.NewContext(ctx, c.log)
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+	for {
+		if err := c.update(ctx); err != nil {
+			c.log.Error(err, "Failed to check webhook certificate")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// update rotates the certificate if required and updates the CA bundles of the webhook configurations.
+// The secret is updated before the webhook configurations, because it takes a while until the webhook server picks up the new certificate.
+// Previous certificates are kept in the CA bundles until they expire, so that the webhooks keep working in the meantime.
+func (c *CertRotator) update(ctx context.Context) error {
+	log := logging.FromContextOrPanic(ctx)
+
+	secret := &corev1.Secret{}
+	if err := c.platformCluster.Client().Get(ctx, c.secret, secret); err != nil {
+		return fmt.Errorf("failed to get webhook secret '%s': %w", c.secret.String(), err)
+	}
+	certs, err := parseCertificates(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return fmt.Errorf("failed to parse certificate from webhook secret '%s': %w", c.secret.String(), err)
+	}
+	if len(certs) == 0 {
+		return fmt.Errorf("webhook secret '%s' does not contain a certificate, the init command needs to create it", c.secret.String())
+	}
+	current := certs[0]
+
+	if remaining := time.Until(current.NotAfter); remaining < c.RotationThreshold {
+		log.Info("Rotating webhook certificate", "secret", c.secret.String(), "notAfter", current.NotAfter.UTC().Format(time.RFC3339))
+		certPEM, keyPEM, err := generateCertificate(current, c.CertificateValidity)
+		if err != nil {
+			return fmt.Errorf("failed to generate webhook certificate: %w", err)
+		}
+		secret.Data[corev1.TLSCertKey] = certPEM
+		secret.Data[corev1.TLSPrivateKeyKey] = keyPEM
+		if err := c.platformCluster.Client().Update(ctx, secret); err != nil {
+			return fmt.Errorf("failed to update webhook secret '%s': %w", c.secret.String(), err)
+		}
+		certs, err = parseCertificates(certPEM)
+		if err != nil {
+			return fmt.Errorf("failed to parse generated webhook certificate: %w", err)
+		}
+		current = certs[0]
+		log.Info("Rotated webhook certificate", "secret", c.secret.String(), "notAfter", current.NotAfter.UTC().Format(time.RFC3339))
+	}
+
+	for _, gvk := range c.types {
+		validating, mutating := c.scope.Names(gvk)
+		if err := c.updateValidatingWebhookConfiguration(ctx, validating, current); err != nil {
+			return err
+		}
+		if err := c.updateMutatingWebhookConfiguration(ctx, mutating, current); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateValidatingWebhookConfiguration ensures that all webhooks of the given ValidatingWebhookConfiguration trust the given certificate.
+// Missing configurations are ignored, because they are created by the init command.
+func (c *CertRotator) updateValidatingWebhookConfiguration(ctx context.Context, name string, current *x509.Certificate) error {
+	log := logging.FromContextOrPanic(ctx)
+
+	whc := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	if err := c.onboardingCluster.Client().Get(ctx, client.ObjectKey{Name: name}, whc); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Debug("ValidatingWebhookConfiguration not found", "name", name)
+			return nil
+		}
+		return fmt.Errorf("failed to get ValidatingWebhookConfiguration '%s': %w", name, err)
+	}
+	changed := false
+	for i := range whc.Webhooks {
+		bundle, updated := caBundle(whc.Webhooks[i].ClientConfig.CABundle, current)
+		if updated {
+			whc.Webhooks[i].ClientConfig.CABundle = bundle
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	log.Info("Updating CA bundle of ValidatingWebhookConfiguration", "name", name)
+	if err := c.onboardingCluster.Client().Update(ctx, whc); err != nil {
+		return fmt.Errorf("failed to update ValidatingWebhookConfiguration '%s': %w", name, err)
+	}
+	return nil
+}
+
+// updateMutatingWebhookConfiguration ensures that all webhooks of the given MutatingWebhookConfiguration trust the given certificate.
+// Missing configurations are ignored, because they are created by the init command.
+func (c *CertRotator) updateMutatingWebhookConfiguration(ctx context.Context, name string, current *x509.Certificate) error {
+	log := logging.FromContextOrPanic(ctx)
+
+	whc := &admissionregistrationv1.MutatingWebhookConfiguration{}
+	if err := c.onboardingCluster.Client().Get(ctx, client.ObjectKey{Name: name}, whc); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Debug("MutatingWebhookConfiguration not found", "name", name)
+			return nil
+		}
+		return fmt.Errorf("failed to get MutatingWebhookConfiguration '%s': %w", name, err)
+	}
+	changed := false
+	for i := range whc.Webhooks {
+		bundle, updated := caBundle(whc.Webhooks[i].ClientConfig.CABundle, current)
+		if updated {
+			whc.Webhooks[i].ClientConfig.CABundle = bundle
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	log.Info("Updating CA bundle of MutatingWebhookConfiguration", "name", name)
+	if err := c.onboardingCluster.Client().Update(ctx, whc); err != nil {
+		return fmt.Errorf("failed to update MutatingWebhookConfiguration '%s': %w", name, err)
+	}
+	return nil
+}
+
+// caBundle returns the CA bundle which should replace the given one and whether it differs from the given one.
+// The result contains the current certificate, followed by all certificates from the given bundle which have not yet expired.
+// Invalid PEM data in the given bundle is dropped.
+func caBundle(existing []byte, current *x509.Certificate) ([]byte, bool) {
+	res := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: current.Raw})
+	now := time.Now()
+	rest := existing
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil || cert.Equal(current) || now.After(cert.NotAfter) {
+			continue
+		}
+		res = append(res, pem.EncodeToMemory(block)...)
+	}
+	return res, !bytes.Equal(res, existing)
+}
+
+// parseCertificates parses all PEM encoded certificates from the given data.
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	res := []*x509.Certificate{}
+	rest := data
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return res, nil
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, cert)
+	}
+}
+
+// generateCertificate generates a new self-signed certificate with the subject and DNS names of the given certificate.
+// It returns the PEM encoded certificate and private key.
+func generateCertificate(previous *x509.Certificate, validity time.Duration) ([]byte, []byte, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).SetInt64(math.MaxInt64))
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now().UTC()
+	tmpl := &x509.Certificate{
+		Subject:      pkix.Name{CommonName: previous.Subject.CommonName},
+		DNSNames:     previous.DNSNames,
+		NotBefore:    now,
+		NotAfter:     now.Add(validity),
+		KeyUsage:     x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		SerialNumber: serial,
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 4096)
+	if err != nil {
+		return nil, nil, err
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyBytes, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certBytes}), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyBytes}), nil
+}
@@ -0,0 +1,182 @@
+package webhookcert
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openmcp-project/controller-utils/pkg/clusters"
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	"github.com/openmcp-project/platform-service-project-workspace/internal/webhookconfig"
+)
+
+var (
+	testSecret = client.ObjectKey{Name: "project-workspace-webhook-tls", Namespace: "openmcp-system"}
+	testGVK    = schema.GroupVersionKind{Group: "core.openmcp.cloud", Version: "v1alpha1", Kind: "Project"}
+	testScope  = webhookconfig.Scope{ProviderName: "project-workspace", Environment: "dev"}
+)
+
+// testCertificate generates a certificate for the webhook service with the given validity.
+func testCertificate(t *testing.T, validity time.Duration) ([]byte, []byte, *x509.Certificate) {
+	t.Helper()
+	certPEM, keyPEM, err := generateCertificate(&x509.Certificate{
+		Subject:  pkix.Name{CommonName: "project-workspace-webhook.openmcp-system.svc"},
+		DNSNames: []string{"project-workspace-webhook.openmcp-system.svc", "pwo-webhooks.example.com"},
+	}, validity)
+	require.NoError(t, err)
+	certs, err := parseCertificates(certPEM)
+	require.NoError(t, err)
+	require.Len(t, certs, 1)
+	return certPEM, keyPEM, certs[0]
+}
+
+func newTestRotator(t *testing.T, certPEM, keyPEM, caBundle []byte) (*CertRotator, client.Client, client.Client) {
+	t.Helper()
+	platformClient := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: testSecret.Name, Namespace: testSecret.Namespace},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       certPEM,
+			corev1.TLSPrivateKeyKey: keyPEM,
+		},
+	}).Build()
+	validating, mutating := testScope.Names(testGVK)
+	onboardingClient := fake.NewClientBuilder().WithObjects(
+		&admissionregistrationv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: validating},
+			Webhooks: []admissionregistrationv1.ValidatingWebhook{
+				{Name: "vproject.core.openmcp.cloud", ClientConfig: admissionregistrationv1.WebhookClientConfig{CABundle: caBundle}},
+			},
+		},
+		&admissionregistrationv1.MutatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: mutating},
+			Webhooks: []admissionregistrationv1.MutatingWebhook{
+				{Name: "mproject.core.openmcp.cloud", ClientConfig: admissionregistrationv1.WebhookClientConfig{CABundle: caBundle}},
+			},
+		},
+	).Build()
+	c := NewCertRotator(clusters.NewTestClusterFromClient("platform", platformClient), clusters.NewTestClusterFromClient("onboarding", onboardingClient), testSecret, testScope, testGVK)
+	return c, platformClient, onboardingClient
+}
+
+// caBundles returns the CA bundles of the validating and mutating webhook configurations.
+func caBundles(t *testing.T, c client.Client) ([]byte, []byte) {
+	t.Helper()
+	validating, mutating := testScope.Names(testGVK)
+	vwc := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Name: validating}, vwc))
+	mwc := &admissionregistrationv1.MutatingWebhookConfiguration{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKey{Name: mutating}, mwc))
+	return vwc.Webhooks[0].ClientConfig.CABundle, mwc.Webhooks[0].ClientConfig.CABundle
+}
+
+func TestUpdate(t *testing.T) {
+	ctx := logging.NewContext(context.Background(), logging.Discard())
+	validCertPEM, validKeyPEM, _ := testCertificate(t, 365*24*time.Hour)
+	expiringCertPEM, expiringKeyPEM, expiringCert := testCertificate(t, 24*time.Hour)
+
+	t.Run("keeps a valid certificate and fresh CA bundles", func(t *testing.T) {
+		c, platformClient, onboardingClient := newTestRotator(t, validCertPEM, validKeyPEM, validCertPEM)
+		require.NoError(t, c.update(ctx))
+
+		secret := &corev1.Secret{}
+		require.NoError(t, platformClient.Get(ctx, testSecret, secret))
+		assert.Equal(t, validCertPEM, secret.Data[corev1.TLSCertKey])
+		assert.Equal(t, validKeyPEM, secret.Data[corev1.TLSPrivateKeyKey])
+		validating, mutating := caBundles(t, onboardingClient)
+		assert.Equal(t, validCertPEM, validating)
+		assert.Equal(t, validCertPEM, mutating)
+	})
+
+	t.Run("updates outdated CA bundles", func(t *testing.T) {
+		c, _, onboardingClient := newTestRotator(t, validCertPEM, validKeyPEM, []byte("outdated"))
+		require.NoError(t, c.update(ctx))
+
+		validating, mutating := caBundles(t, onboardingClient)
+		assert.Equal(t, validCertPEM, validating)
+		assert.Equal(t, validCertPEM, mutating)
+	})
+
+	t.Run("rotates an expiring certificate and keeps the previous one in the CA bundles", func(t *testing.T) {
+		c, platformClient, onboardingClient := newTestRotator(t, expiringCertPEM, expiringKeyPEM, expiringCertPEM)
+		require.NoError(t, c.update(ctx))
+
+		secret := &corev1.Secret{}
+		require.NoError(t, platformClient.Get(ctx, testSecret, secret))
+		assert.NotEqual(t, expiringKeyPEM, secret.Data[corev1.TLSPrivateKeyKey])
+		certs, err := parseCertificates(secret.Data[corev1.TLSCertKey])
+		require.NoError(t, err)
+		require.Len(t, certs, 1)
+		assert.Equal(t, expiringCert.DNSNames, certs[0].DNSNames)
+		assert.Equal(t, expiringCert.Subject.CommonName, certs[0].Subject.CommonName)
+		assert.WithinDuration(t, time.Now().Add(DefaultCertificateValidity), certs[0].NotAfter, time.Minute)
+
+		expectedBundle := append(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certs[0].Raw}), expiringCertPEM...)
+		validating, mutating := caBundles(t, onboardingClient)
+		assert.Equal(t, expectedBundle, validating)
+		assert.Equal(t, expectedBundle, mutating)
+	})
+
+	t.Run("fails if the secret does not contain a certificate", func(t *testing.T) {
+		c, _, _ := newTestRotator(t, nil, nil, nil)
+		assert.Error(t, c.update(ctx))
+	})
+}
+
+func TestCABundle(t *testing.T) {
+	_, _, current := testCertificate(t, 365*24*time.Hour)
+	otherPEM, _, _ := testCertificate(t, 365*24*time.Hour)
+	currentPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: current.Raw})
+
+	tests := []struct {
+		description     string
+		existing        []byte
+		expected        []byte
+		expectedUpdated bool
+	}{
+		{
+			description:     "keeps a bundle which only contains the current certificate",
+			existing:        currentPEM,
+			expected:        currentPEM,
+			expectedUpdated: false,
+		},
+		{
+			description:     "adds the current certificate to an empty bundle",
+			existing:        nil,
+			expected:        currentPEM,
+			expectedUpdated: true,
+		},
+		{
+			description:     "keeps other valid certificates after the current one",
+			existing:        otherPEM,
+			expected:        append(append([]byte{}, currentPEM...), otherPEM...),
+			expectedUpdated: true,
+		},
+		{
+			description:     "removes duplicates of the current certificate and invalid data",
+			existing:        append(append(append([]byte{}, otherPEM...), currentPEM...), []byte("garbage")...),
+			expected:        append(append([]byte{}, currentPEM...), otherPEM...),
+			expectedUpdated: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			bundle, updated := caBundle(test.existing, current)
+			assert.Equal(t, test.expected, bundle)
+			assert.Equal(t, test.expectedUpdated, updated)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package crdcheck
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	openmcpconst "github.com/openmcp-project/openmcp-operator/api/constants"
+)
+
+// SkewPolicy determines how the platform service reacts to installed CRDs which do not match the ones it has been built with.
+type SkewPolicy string
+
+const (
+	// SkewPolicyFail refuses to start the platform service.
+	SkewPolicyFail SkewPolicy = "fail"
+	// SkewPolicyWarn only logs the differences, e.g. while the CRDs are intentionally updated separately.
+	SkewPolicyWarn SkewPolicy = "warn"
+)
+
+// Validate returns an error if the policy is unknown.
+func (p SkewPolicy) Validate() error {
+	switch p {
+	case SkewPolicyFail, SkewPolicyWarn:
+		return nil
+	}
+	return fmt.Errorf("unknown CRD skew policy '%s', must be one of '%s', '%s'", p, SkewPolicyFail, SkewPolicyWarn)
+}
+
+// ForCluster returns the CRDs which are deployed to the cluster with the given purpose, according to their openmcp cluster label.
+func ForCluster(crds []*apiextv1.CustomResourceDefinition, purpose string) []*apiextv1.CustomResourceDefinition {
+	var res []*apiextv1.CustomResourceDefinition
+	for _, crd := range crds {
+		if crd.Labels[openmcpconst.ClusterLabel] == purpose {
+			res = append(res, crd)
+		}
+	}
+	return res
+}
+
+// StorageVersion returns the name of the version in which the resources of the given CRD are stored, or an empty string if there is none.
+func StorageVersion(crd *apiextv1.CustomResourceDefinition) string {
+	for _, v := range crd.Spec.Versions {
+		if v.Storage {
+			return v.Name
+		}
+	}
+	return ""
+}
+
+// versionFingerprint contains the parts of a CRD version which affect the validation of its resources.
+type versionFingerprint struct {
+	Name    string                             `json:"name"`
+	Served  bool                               `json:"served"`
+	Storage bool                               `json:"storage"`
+	Schema  *apiextv1.CustomResourceValidation `json:"schema,omitempty"`
+}
+
+// SchemaHash returns a hash of the versions of the given CRD and their schemas, which is independent of the order of the versions.
+// Printer columns, annotations and the status of the CRD are not taken into account, since they do not change how resources are validated.
+func SchemaHash(crd *apiextv1.CustomResourceDefinition) (string, error) {
+	fingerprints := make([]versionFingerprint, 0, len(crd.Spec.Versions))
+	for _, v := range crd.Spec.Versions {
+		fingerprints = append(fingerprints, versionFingerprint{Name: v.Name, Served: v.Served, Storage: v.Storage, Schema: v.Schema})
+	}
+	slices.SortFunc(fingerprints, func(a, b versionFingerprint) int { return strings.Compare(a.Name, b.Name) })
+	data, err := json.Marshal(fingerprints)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal versions of CRD '%s': %w", crd.Name, err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Verify compares the CRDs installed in the cluster of the given client with the expected ones, usually those embedded in the binary.
+// It returns an error describing all CRDs which are missing, have another storage version or another schema.
+// Such a skew usually means that the CRDs have been updated by another version of the platform service, e.g. after a partial upgrade or rollback.
+func Verify(ctx context.Context, c client.Client, expected []*apiextv1.CustomResourceDefinition) error {
+	var skews []error
+	for _, crd := range expected {
+		installed := &apiextv1.CustomResourceDefinition{}
+		if err := c.Get(ctx, client.ObjectKey{Name: crd.Name}, installed); err != nil {
+			if apierrors.IsNotFound(err) {
+				skews = append(skews, fmt.Errorf("CRD '%s' is not installed", crd.Name))
+				continue
+			}
+			return fmt.Errorf("failed to get CRD '%s': %w", crd.Name, err)
+		}
+		if want, got := StorageVersion(crd), StorageVersion(installed); want != got {
+			skews = append(skews, fmt.Errorf("CRD '%s' stores version '%s', but '%s' is expected", crd.Name, got, want))
+			continue
+		}
+		want, err := SchemaHash(crd)
+		if err != nil {
+			return err
+		}
+		got, err := SchemaHash(installed)
+		if err != nil {
+			return err
+		}
+		if want != got {
+			skews = append(skews, fmt.Errorf("CRD '%s' has schema hash '%s', but '%s' is expected", crd.Name, got, want))
+		}
+	}
+	if len(skews) > 0 {
+		return fmt.Errorf("installed CRDs do not match this version of the platform service, run the init command of this version to update them: %w", errors.Join(skews...))
+	}
+	return nil
+}
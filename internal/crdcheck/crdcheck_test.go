@@ -0,0 +1,124 @@
+package crdcheck_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+
+	"github.com/openmcp-project/platform-service-project-workspace/api/v2/crds"
+	providerscheme "github.com/openmcp-project/platform-service-project-workspace/api/v2/install"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/crdcheck"
+)
+
+func TestVerify(t *testing.T) {
+	ctx := context.Background()
+	all, err := crds.CRDs()
+	require.NoError(t, err)
+	expected := crdcheck.ForCluster(all, clustersv1alpha1.PURPOSE_ONBOARDING)
+	require.NotEmpty(t, expected)
+	assert.Less(t, len(expected), len(all), "platform CRDs must not be expected on the onboarding cluster")
+
+	var projects *apiextv1.CustomResourceDefinition
+	for _, crd := range expected {
+		if crd.Name == "projects.core.openmcp.cloud" {
+			projects = crd
+		}
+	}
+	require.NotNil(t, projects)
+
+	install := func(modify func(crd *apiextv1.CustomResourceDefinition)) client.Client {
+		objs := make([]client.Object, 0, len(expected))
+		for _, crd := range expected {
+			crd = crd.DeepCopy()
+			if crd.Name == projects.Name && modify != nil {
+				modify(crd)
+			}
+			objs = append(objs, crd)
+		}
+		return fake.NewClientBuilder().WithScheme(providerscheme.InstallOperatorAPIsOnboarding(runtime.NewScheme())).WithObjects(objs...).Build()
+	}
+
+	tests := []struct {
+		description string
+		modify      func(crd *apiextv1.CustomResourceDefinition)
+		expectedErr string
+	}{
+		{
+			description: "accepts matching CRDs",
+		},
+		{
+			description: "ignores printer columns and annotations",
+			modify: func(crd *apiextv1.CustomResourceDefinition) {
+				crd.Annotations = map[string]string{"foo": "bar"}
+				crd.Spec.Versions[0].AdditionalPrinterColumns = nil
+			},
+		},
+		{
+			description: "rejects another storage version",
+			modify: func(crd *apiextv1.CustomResourceDefinition) {
+				crd.Spec.Versions[0].Storage = false
+				crd.Spec.Versions = append(crd.Spec.Versions, apiextv1.CustomResourceDefinitionVersion{Name: "v1beta1", Served: true, Storage: true})
+			},
+			expectedErr: "CRD 'projects.core.openmcp.cloud' stores version 'v1beta1', but 'v1alpha1' is expected",
+		},
+		{
+			description: "rejects another schema",
+			modify: func(crd *apiextv1.CustomResourceDefinition) {
+				delete(crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties, "spec")
+			},
+			expectedErr: "CRD 'projects.core.openmcp.cloud' has schema hash",
+		},
+		{
+			description: "rejects missing CRDs",
+			modify: func(crd *apiextv1.CustomResourceDefinition) {
+				crd.Name = "other.core.openmcp.cloud"
+			},
+			expectedErr: "CRD 'projects.core.openmcp.cloud' is not installed",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := crdcheck.Verify(ctx, install(test.modify), expected)
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.expectedErr)
+			}
+		})
+	}
+}
+
+func TestSchemaHash(t *testing.T) {
+	crd := &apiextv1.CustomResourceDefinition{Spec: apiextv1.CustomResourceDefinitionSpec{Versions: []apiextv1.CustomResourceDefinitionVersion{
+		{Name: "v1alpha1", Served: true},
+		{Name: "v1beta1", Served: true, Storage: true},
+	}}}
+	hash, err := crdcheck.SchemaHash(crd)
+	require.NoError(t, err)
+
+	reordered := crd.DeepCopy()
+	reordered.Spec.Versions[0], reordered.Spec.Versions[1] = reordered.Spec.Versions[1], reordered.Spec.Versions[0]
+	reorderedHash, err := crdcheck.SchemaHash(reordered)
+	require.NoError(t, err)
+	assert.Equal(t, hash, reorderedHash)
+
+	unserved := crd.DeepCopy()
+	unserved.Spec.Versions[0].Served = false
+	unservedHash, err := crdcheck.SchemaHash(unserved)
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, unservedHash)
+}
+
+func TestSkewPolicyValidate(t *testing.T) {
+	assert.NoError(t, crdcheck.SkewPolicyFail.Validate())
+	assert.NoError(t, crdcheck.SkewPolicyWarn.Validate())
+	assert.Error(t, crdcheck.SkewPolicy("readonly").Validate())
+}
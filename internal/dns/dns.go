@@ -3,261 +3,119 @@ package dns
 import (
 	"context"
 	"fmt"
+	"net/netip"
+	"slices"
 	"time"
 
 	"github.com/openmcp-project/controller-utils/pkg/clusters"
-	"github.com/openmcp-project/controller-utils/pkg/collections/filters"
-	"github.com/openmcp-project/controller-utils/pkg/logging"
-	"k8s.io/apimachinery/pkg/api/errors"
-	controllerruntime "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
+	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
-	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
-	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
 )
 
 const (
-	DefaultGatewayName      = "default"
-	DefaultGatewayNamespace = "openmcp-system"
-	DNSAnnotationKey        = "dns.openmcp.cloud/base-domain"
-	RequeueInterval         = 20 * time.Second
+	RequeueInterval = 20 * time.Second
 )
 
-// Reconciler is a reconciler for managing DNS records using Gateway API resources.
-type Reconciler struct {
+// Provider exposes a service instance under a host name.
+type Provider interface {
+	// ReconcileEndpoint verifies that the prerequisites of the provider are met and returns the host name and port under which the instance will be reachable.
+	// If Result.RequeueAfter is set, the prerequisites are not yet met and the call should be retried.
+	ReconcileEndpoint(ctx context.Context, instance *Instance, targetCluster *clusters.Cluster) (EndpointReconcileResult, error)
+	// ReconcileRoute ensures that the resources which route the traffic for the host name to the instance exist.
+	ReconcileRoute(ctx context.Context, instance *Instance, targetCluster *clusters.Cluster) error
+	// IsRouteReady checks whether the traffic for the host name is routed to the instance.
+	IsRouteReady(ctx context.Context, instance *Instance, targetCluster *clusters.Cluster) (bool, error)
+	// DeleteRoute deletes the resources created by ReconcileRoute.
+	DeleteRoute(ctx context.Context, instance *Instance, targetCluster *clusters.Cluster) error
 }
 
-// Instance represents a service instance for that the TLSRoute will be managed.
+// Instance represents a service instance which is exposed by a Provider.
 type Instance struct {
-	// Namespace in which the TLSRoute will be created.
+	// Namespace in which the routing resources will be created.
 	Namespace string
-	// Name of the TLSRoute.
+	// Name of the routing resources.
 	Name string
 	// SubDomainPrefix is the prefix for the subdomain that will be created for the instance.
 	SubDomainPrefix string
-	// BackendName is the name of the backend service to which the TLSRoute will route traffic.
+	// HostName is the host name under which the instance is exposed.
+	// If empty, it is generated from the SubDomainPrefix and the base domain of the provider.
+	HostName string
+	// BackendName is the name of the backend service to which the traffic will be routed.
 	BackendName string
-	// BackendPort is the port of the backend service to which the TLSRoute will route traffic.
+	// BackendPort is the port of the backend service to which the traffic will be routed.
 	BackendPort int32
+	// BackendSelector selects the pods of the backend.
+	// Only used by providers which route the traffic to the pods directly.
+	BackendSelector map[string]string
+	// BackendTargetPort is the port of the backend pods.
+	// Only used by providers which route the traffic to the pods directly.
+	BackendTargetPort int32
 }
 
-// GatewayReconcileResult is the result of a gateway reconciliation.
-// If Result.Requeue is not set, the gateway is ready and the HostName can be used.
-type GatewayReconcileResult struct {
+// EndpointReconcileResult is the result of an endpoint reconciliation.
+// If Result.RequeueAfter is not set, the endpoint is ready and the HostName can be used.
+type EndpointReconcileResult struct {
 	// HostName is the hostname that was created for the instance and can be used for DNS records.
 	HostName string
-	// TLSPort is the port under which the gateway accepts TLS traffic.
+	// TLSPort is the port under which the instance accepts TLS traffic.
 	TLSPort int32
 	// Result is the result of the reconciliation.
 	reconcile.Result
 }
 
-// NewReconciler creates a new DNS reconciler.
-func NewReconciler() *Reconciler {
-	return &Reconciler{}
-}
-
-// ReconcileGateway ensures that the default gateway exists and retrieves the base domain from its annotations.
-// It returns the full hostname for the given instance that can be used for DNS records.
-// If the default gateway is not found, it will requeue after a predefined interval.
-func (r *Reconciler) ReconcileGateway(ctx context.Context, instance *Instance, targetCluster *clusters.Cluster) (GatewayReconcileResult, error) {
-	log := logging.FromContextOrDiscard(ctx)
-
-	var err error
-
-	// get default gateway
-
-	gateway := &gatewayv1.Gateway{}
-	gateway.SetName(DefaultGatewayName)
-	gateway.SetNamespace(DefaultGatewayNamespace)
-
-	if err = targetCluster.Client().Get(ctx, client.ObjectKeyFromObject(gateway), gateway); err != nil {
-		if errors.IsNotFound(err) {
-			log.Debug("Default gateway not found, requeueing...")
-			// default gateway not found
-			return GatewayReconcileResult{
-				Result: reconcile.Result{
-					RequeueAfter: RequeueInterval,
-				},
-			}, nil
-		}
-
-		return GatewayReconcileResult{Result: reconcile.Result{}}, fmt.Errorf("failed to get default gateway: %w", err)
-	}
-
-	log.Debug("Default Gateway available")
-
-	baseDomain, hasBaseDomain := getBaseDomain(gateway)
-	if !hasBaseDomain {
-		return GatewayReconcileResult{Result: reconcile.Result{}}, fmt.Errorf("gateway is missing the %s annotation", DNSAnnotationKey)
-	}
-
-	log.Debug("Base domain found", "baseDomain", baseDomain)
-
-	tlsPort, hasTLSPort := getTLSPort(gateway)
-	if !hasTLSPort {
-		return GatewayReconcileResult{Result: reconcile.Result{}}, fmt.Errorf("gateway either does not have any listeners with TLS protocol or it has multiple ones and none is named 'tls'")
-	}
-
-	log.Debug("TLS port found", "tlsPort", tlsPort)
-
-	hostName := getHostName(baseDomain, instance)
-
-	return GatewayReconcileResult{
-		HostName: hostName,
-		TLSPort:  tlsPort,
-		Result:   reconcile.Result{},
-	}, nil
-}
-
-// ReconcileTLSRoute ensures that a TLSRoute exists for the given instance, pointing to the default gateway.
-func (r *Reconciler) ReconcileTLSRoute(ctx context.Context, instance *Instance, targetCluster *clusters.Cluster) error {
-	// get default gateway
-
-	var err error
-
-	gateway := &gatewayv1.Gateway{}
-	gateway.SetName(DefaultGatewayName)
-	gateway.SetNamespace(DefaultGatewayNamespace)
-
-	if err = targetCluster.Client().Get(ctx, client.ObjectKeyFromObject(gateway), gateway); err != nil {
-		return fmt.Errorf("failed to get default gateway: %w", err)
-	}
-
-	baseDomain, hasBaseDomain := getBaseDomain(gateway)
-	if !hasBaseDomain {
-		return fmt.Errorf("gateway is missing the %s annotation", DNSAnnotationKey)
-	}
-
-	hostName := getHostName(baseDomain, instance)
-
-	tlsRoute := &gatewayv1alpha2.TLSRoute{}
-	tlsRoute.SetName(instance.Name)
-	tlsRoute.SetNamespace(instance.Namespace)
-
-	_, err = controllerruntime.CreateOrUpdate(ctx, targetCluster.Client(), tlsRoute, func() error {
-		tlsRoute.Spec = gatewayv1alpha2.TLSRouteSpec{
-			CommonRouteSpec: gatewayv1alpha2.CommonRouteSpec{
-				ParentRefs: []gatewayv1alpha2.ParentReference{
-					{
-						Name:      gatewayv1.ObjectName(gateway.Name),
-						Namespace: new(gatewayv1.Namespace(gateway.Namespace)),
-					},
-				},
-			},
-			Hostnames: []gatewayv1alpha2.Hostname{
-				gatewayv1alpha2.Hostname(hostName),
-			},
-			Rules: []gatewayv1alpha2.TLSRouteRule{
-				{
-					BackendRefs: []gatewayv1alpha2.BackendRef{
-						{
-							BackendObjectReference: gatewayv1alpha2.BackendObjectReference{
-								Name: gatewayv1.ObjectName(instance.BackendName),
-								Port: new(instance.BackendPort),
-							},
-						},
-					},
-				},
-			},
-		}
-		return nil
-	})
-
-	if err != nil {
-		return fmt.Errorf("failed to create or update TLSRoute: %w", err)
-	}
-
-	return nil
-}
-
-// IsTLSRouteReady checks if the TLSRoute for the given instance is accepted by the default gateway.
-func (r *Reconciler) IsTLSRouteReady(ctx context.Context, instance *Instance, targetCluster *clusters.Cluster) (bool, error) {
-	log := logging.FromContextOrDiscard(ctx)
-
-	var err error
-
-	tlsRoute := &gatewayv1alpha2.TLSRoute{}
-	tlsRoute.SetName(instance.Name)
-	tlsRoute.SetNamespace(instance.Namespace)
-
-	if err = targetCluster.Client().Get(ctx, client.ObjectKeyFromObject(tlsRoute), tlsRoute); err != nil {
-		return false, fmt.Errorf("failed to get TLSRoute: %w", err)
-	}
-
-	for _, parent := range tlsRoute.Status.Parents {
-		if parent.ParentRef.Name == DefaultGatewayName && parent.ParentRef.Namespace != nil && *parent.ParentRef.Namespace == DefaultGatewayNamespace {
-			for _, cond := range parent.Conditions {
-				if cond.Type == string(gatewayv1alpha2.RouteConditionAccepted) && cond.Status == "True" {
-					log.Debug("TLSRoute is accepted by the gateway")
-					return true, nil
-				}
+// NewProvider creates the Provider which is selected by the given configuration.
+func NewProvider(cfg pwv1alpha1.DNSConfig) (Provider, error) {
+	switch cfg.Provider {
+	case "", pwv1alpha1.DNSProviderGateway:
+		p := NewGatewayProvider()
+		if cfg.Gateway != nil {
+			if cfg.Gateway.Name != "" {
+				p.GatewayName = cfg.Gateway.Name
+			}
+			if cfg.Gateway.Namespace != "" {
+				p.GatewayNamespace = cfg.Gateway.Namespace
 			}
+			p.ListenerName = cfg.Gateway.ListenerName
 		}
-	}
-
-	return false, nil
-}
-
-// DeleteTLSRoute deletes the TLSRoute for the given instance.
-func (r *Reconciler) DeleteTLSRoute(ctx context.Context, instance *Instance, targetCluster *clusters.Cluster) error {
-	log := logging.FromContextOrDiscard(ctx)
-
-	tlsRoute := &gatewayv1alpha2.TLSRoute{}
-	tlsRoute.SetName(instance.Name)
-	tlsRoute.SetNamespace(instance.Namespace)
-
-	if err := targetCluster.Client().Get(ctx, client.ObjectKeyFromObject(tlsRoute), tlsRoute); err != nil {
-		if errors.IsNotFound(err) {
-			log.Debug("TLSRoute already deleted")
-			return nil
+		p.Port = cfg.Port
+		p.IPFamilies = cfg.IPFamilies
+		return p, nil
+	case pwv1alpha1.DNSProviderLoadBalancer:
+		if cfg.BaseDomain == "" {
+			return nil, fmt.Errorf("base domain is required for DNS provider '%s'", cfg.Provider)
 		}
-		return fmt.Errorf("failed to get TLSRoute: %w", err)
-	}
-
-	if err := targetCluster.Client().Delete(ctx, tlsRoute); client.IgnoreNotFound(err) != nil {
-		return fmt.Errorf("failed to delete TLSRoute: %w", err)
+		p := NewLoadBalancerProvider(cfg.BaseDomain, cfg.ServiceAnnotations)
+		p.Port = cfg.Port
+		p.IPFamilies = cfg.IPFamilies
+		return p, nil
+	default:
+		return nil, fmt.Errorf("unknown DNS provider '%s'", cfg.Provider)
 	}
-
-	log.Info("TLSRoute deleted")
-
-	return nil
-}
-
-func getBaseDomain(gateway *gatewayv1.Gateway) (string, bool) {
-	annotations := gateway.GetAnnotations()
-	if len(annotations) == 0 {
-		return "", false
-	}
-
-	baseDomain, hasBaseDomain := annotations[DNSAnnotationKey]
-	return baseDomain, hasBaseDomain
 }
 
 func getHostName(baseDomain string, instance *Instance) string {
+	if instance.HostName != "" {
+		return instance.HostName
+	}
 	return fmt.Sprintf("%s.%s", instance.SubDomainPrefix, baseDomain)
 }
 
-// retrieves the TLS port from the gateway and a boolean indicating whether a TLS port was found
-// logic as follows:
-// - if the gateway has a single listener with TLS protocol, its port (and true) is returned
-// - if the gateway has multiple TLS listeners and one is named "tls", its port (and true) is returned
-// - in all other cases, (0, false) is returned
-func getTLSPort(gateway *gatewayv1.Gateway) (int32, bool) {
-	tlsListeners := filters.FilterSlice(gateway.Spec.Listeners, func(args ...any) bool {
-		elem := args[0].(gatewayv1.Listener)
-		return elem.Protocol == gatewayv1.TLSProtocolType
-	})
-	if len(tlsListeners) == 0 {
-		return 0, false
-	}
-	if len(tlsListeners) == 1 {
-		return tlsListeners[0].Port, true
-	}
-	for _, listener := range tlsListeners {
-		if listener.Name == "tls" {
-			return listener.Port, true
+// missingIPFamilies returns the IP families from families for which the given addresses do not contain an IP address.
+// Addresses which are not IP addresses, e.g. host names of load balancers, can resolve to any family, so that no family is missing if there is such an address.
+func missingIPFamilies(families []corev1.IPFamily, addresses []string) []corev1.IPFamily {
+	found := map[corev1.IPFamily]bool{}
+	for _, address := range addresses {
+		ip, err := netip.ParseAddr(address)
+		switch {
+		case err != nil:
+			return nil
+		case ip.Unmap().Is4():
+			found[corev1.IPv4Protocol] = true
+		default:
+			found[corev1.IPv6Protocol] = true
 		}
 	}
-	return 0, false
+	return slices.DeleteFunc(slices.Clone(families), func(family corev1.IPFamily) bool { return found[family] })
 }
@@ -0,0 +1,487 @@
+package dns_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openmcp-project/controller-utils/pkg/clusters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/dns"
+)
+
+func TestNewProvider(t *testing.T) {
+	tests := []struct {
+		description string
+		cfg         pwv1alpha1.DNSConfig
+		expected    dns.Provider
+		expectErr   bool
+	}{
+		{
+			description: "defaults to the gateway provider",
+			cfg:         pwv1alpha1.DNSConfig{},
+			expected:    dns.NewGatewayProvider(),
+		},
+		{
+			description: "creates the gateway provider",
+			cfg:         pwv1alpha1.DNSConfig{Provider: pwv1alpha1.DNSProviderGateway},
+			expected:    dns.NewGatewayProvider(),
+		},
+		{
+			description: "creates the load balancer provider",
+			cfg: pwv1alpha1.DNSConfig{
+				Provider:           pwv1alpha1.DNSProviderLoadBalancer,
+				BaseDomain:         "example.com",
+				ServiceAnnotations: map[string]string{"foo": "bar"},
+			},
+			expected: dns.NewLoadBalancerProvider("example.com", map[string]string{"foo": "bar"}),
+		},
+		{
+			description: "configures the gateway provider",
+			cfg: pwv1alpha1.DNSConfig{
+				Provider:   pwv1alpha1.DNSProviderGateway,
+				Port:       8443,
+				IPFamilies: []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol},
+				Gateway:    &pwv1alpha1.GatewayDNSConfig{Namespace: "gateways", ListenerName: "webhooks"},
+			},
+			expected: &dns.GatewayProvider{
+				GatewayName:      dns.DefaultGatewayName,
+				GatewayNamespace: "gateways",
+				ListenerName:     "webhooks",
+				Port:             8443,
+				IPFamilies:       []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol},
+			},
+		},
+		{
+			description: "fails for the load balancer provider without base domain",
+			cfg:         pwv1alpha1.DNSConfig{Provider: pwv1alpha1.DNSProviderLoadBalancer},
+			expectErr:   true,
+		},
+		{
+			description: "fails for unknown providers",
+			cfg:         pwv1alpha1.DNSConfig{Provider: "Ingress"},
+			expectErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			provider, err := dns.NewProvider(test.cfg)
+			if test.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, provider)
+		})
+	}
+}
+
+func TestLoadBalancerProvider(t *testing.T) {
+	ctx := context.Background()
+	cluster := clusters.NewTestClusterFromClient("platform", fake.NewClientBuilder().WithStatusSubresource(&corev1.Service{}).Build())
+	instance := &dns.Instance{
+		Name:              "project-workspace-webhook",
+		Namespace:         "openmcp-system",
+		SubDomainPrefix:   "pwo-webhooks",
+		BackendName:       "project-workspace-webhook",
+		BackendPort:       443,
+		BackendSelector:   map[string]string{"app": "pwo"},
+		BackendTargetPort: 9443,
+	}
+	provider := dns.NewLoadBalancerProvider("example.com", map[string]string{"service.beta.kubernetes.io/aws-load-balancer-type": "nlb"})
+
+	result, err := provider.ReconcileEndpoint(ctx, instance, cluster)
+	require.NoError(t, err)
+	assert.Equal(t, "pwo-webhooks.example.com", result.HostName)
+	assert.Equal(t, int32(443), result.TLSPort)
+	assert.Zero(t, result.RequeueAfter)
+
+	require.NoError(t, provider.ReconcileRoute(ctx, instance, cluster))
+	svc := &corev1.Service{}
+	require.NoError(t, cluster.Client().Get(ctx, client.ObjectKey{Name: "project-workspace-webhook-lb", Namespace: "openmcp-system"}, svc))
+	assert.Equal(t, corev1.ServiceTypeLoadBalancer, svc.Spec.Type)
+	assert.Equal(t, instance.BackendSelector, svc.Spec.Selector)
+	require.Len(t, svc.Spec.Ports, 1)
+	assert.Equal(t, int32(443), svc.Spec.Ports[0].Port)
+	assert.Equal(t, intstr.FromInt32(9443), svc.Spec.Ports[0].TargetPort)
+	assert.Equal(t, map[string]string{
+		"service.beta.kubernetes.io/aws-load-balancer-type": "nlb",
+		dns.ExternalDNSHostnameAnnotationKey:                "pwo-webhooks.example.com",
+	}, svc.GetAnnotations())
+
+	ready, err := provider.IsRouteReady(ctx, instance, cluster)
+	require.NoError(t, err)
+	assert.False(t, ready, "route must not be ready before the load balancer has been provisioned")
+
+	svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "203.0.113.10"}}
+	require.NoError(t, cluster.Client().Status().Update(ctx, svc))
+	ready, err = provider.IsRouteReady(ctx, instance, cluster)
+	require.NoError(t, err)
+	assert.True(t, ready)
+
+	require.NoError(t, provider.DeleteRoute(ctx, instance, cluster))
+	assert.True(t, apierrors.IsNotFound(cluster.Client().Get(ctx, client.ObjectKeyFromObject(svc), svc)), "service must be deleted")
+	require.NoError(t, provider.DeleteRoute(ctx, instance, cluster), "deleting a missing route must not fail")
+
+	instance.BackendSelector = nil
+	assert.Error(t, provider.ReconcileRoute(ctx, instance, cluster), "a backend selector is required")
+}
+
+func TestLoadBalancerProviderDualStack(t *testing.T) {
+	ctx := context.Background()
+	cluster := clusters.NewTestClusterFromClient("platform", fake.NewClientBuilder().WithStatusSubresource(&corev1.Service{}).Build())
+	instance := &dns.Instance{
+		Name:              "project-workspace-webhook",
+		Namespace:         "openmcp-system",
+		HostName:          "webhooks.canary.example.com",
+		BackendPort:       443,
+		BackendSelector:   map[string]string{"app": "pwo"},
+		BackendTargetPort: 9443,
+	}
+	provider := dns.NewLoadBalancerProvider("example.com", nil)
+	provider.Port = 8443
+	provider.IPFamilies = []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol}
+
+	result, err := provider.ReconcileEndpoint(ctx, instance, cluster)
+	require.NoError(t, err)
+	assert.Equal(t, "webhooks.canary.example.com", result.HostName)
+	assert.Equal(t, int32(8443), result.TLSPort)
+
+	require.NoError(t, provider.ReconcileRoute(ctx, instance, cluster))
+	svc := &corev1.Service{}
+	require.NoError(t, cluster.Client().Get(ctx, client.ObjectKey{Name: "project-workspace-webhook-lb", Namespace: "openmcp-system"}, svc))
+	assert.Equal(t, "webhooks.canary.example.com", svc.GetAnnotations()[dns.ExternalDNSHostnameAnnotationKey])
+	require.Len(t, svc.Spec.Ports, 1)
+	assert.Equal(t, int32(8443), svc.Spec.Ports[0].Port)
+	assert.Equal(t, []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol}, svc.Spec.IPFamilies)
+	if assert.NotNil(t, svc.Spec.IPFamilyPolicy) {
+		assert.Equal(t, corev1.IPFamilyPolicyRequireDualStack, *svc.Spec.IPFamilyPolicy)
+	}
+
+	svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "203.0.113.10"}}
+	require.NoError(t, cluster.Client().Status().Update(ctx, svc))
+	ready, err := provider.IsRouteReady(ctx, instance, cluster)
+	require.NoError(t, err)
+	assert.False(t, ready, "route must not be ready before the load balancer has an IPv6 address")
+
+	svc.Status.LoadBalancer.Ingress = append(svc.Status.LoadBalancer.Ingress, corev1.LoadBalancerIngress{IP: "2001:db8::10"})
+	require.NoError(t, cluster.Client().Status().Update(ctx, svc))
+	ready, err = provider.IsRouteReady(ctx, instance, cluster)
+	require.NoError(t, err)
+	assert.True(t, ready)
+}
+
+func TestGatewayProvider(t *testing.T) {
+	newGateway := func() *gatewayv1.Gateway {
+		return &gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "webhooks",
+				Namespace:   "gateways",
+				Annotations: map[string]string{dns.DNSAnnotationKey: "example.com"},
+			},
+			Spec: gatewayv1.GatewaySpec{
+				Listeners: []gatewayv1.Listener{
+					{Name: "https", Protocol: gatewayv1.HTTPSProtocolType, Port: 443},
+					{Name: "tls", Protocol: gatewayv1.TLSProtocolType, Port: 443},
+					{Name: "tls-alt", Protocol: gatewayv1.TLSProtocolType, Port: 8443},
+				},
+			},
+		}
+	}
+	instance := &dns.Instance{
+		Name:            "project-workspace-webhook",
+		Namespace:       "openmcp-system",
+		SubDomainPrefix: "pwo-webhooks",
+		BackendName:     "project-workspace-webhook",
+		BackendPort:     443,
+	}
+
+	tests := []struct {
+		description     string
+		provider        *dns.GatewayProvider
+		hostName        string
+		expectErr       bool
+		expectedHost    string
+		expectedPort    int32
+		expectedSection string
+	}{
+		{
+			description:  "uses the listener named 'tls' if there are multiple TLS listeners",
+			provider:     &dns.GatewayProvider{GatewayName: "webhooks", GatewayNamespace: "gateways"},
+			expectedHost: "pwo-webhooks.example.com",
+			expectedPort: 443,
+		},
+		{
+			description:     "selects the listener by name",
+			provider:        &dns.GatewayProvider{GatewayName: "webhooks", GatewayNamespace: "gateways", ListenerName: "tls-alt"},
+			expectedHost:    "pwo-webhooks.example.com",
+			expectedPort:    8443,
+			expectedSection: "tls-alt",
+		},
+		{
+			description:     "selects the listener by port",
+			provider:        &dns.GatewayProvider{GatewayName: "webhooks", GatewayNamespace: "gateways", Port: 8443},
+			hostName:        "webhooks.canary.example.com",
+			expectedHost:    "webhooks.canary.example.com",
+			expectedPort:    8443,
+			expectedSection: "tls-alt",
+		},
+		{
+			description: "fails if the listener does not use the TLS protocol",
+			provider:    &dns.GatewayProvider{GatewayName: "webhooks", GatewayNamespace: "gateways", ListenerName: "https"},
+			expectErr:   true,
+		},
+		{
+			description: "fails if there is no TLS listener with the port",
+			provider:    &dns.GatewayProvider{GatewayName: "webhooks", GatewayNamespace: "gateways", Port: 9443},
+			expectErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			require.NoError(t, gatewayv1.Install(scheme))
+			require.NoError(t, gatewayv1alpha2.Install(scheme))
+			cluster := clusters.NewTestClusterFromClient("platform", fake.NewClientBuilder().WithScheme(scheme).WithObjects(newGateway()).Build())
+			inst := *instance
+			inst.HostName = test.hostName
+
+			result, err := test.provider.ReconcileEndpoint(ctx, &inst, cluster)
+			if test.expectErr {
+				assert.Error(t, err)
+				assert.Error(t, test.provider.ReconcileRoute(ctx, &inst, cluster))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedHost, result.HostName)
+			assert.Equal(t, test.expectedPort, result.TLSPort)
+
+			require.NoError(t, test.provider.ReconcileRoute(ctx, &inst, cluster))
+			route := &gatewayv1alpha2.TLSRoute{}
+			require.NoError(t, cluster.Client().Get(ctx, client.ObjectKey{Name: inst.Name, Namespace: inst.Namespace}, route))
+			assert.Equal(t, []gatewayv1alpha2.Hostname{gatewayv1alpha2.Hostname(test.expectedHost)}, route.Spec.Hostnames)
+			require.Len(t, route.Spec.ParentRefs, 1)
+			assert.Equal(t, gatewayv1.ObjectName("webhooks"), route.Spec.ParentRefs[0].Name)
+			if test.expectedSection == "" {
+				assert.Nil(t, route.Spec.ParentRefs[0].SectionName)
+			} else if assert.NotNil(t, route.Spec.ParentRefs[0].SectionName) {
+				assert.Equal(t, gatewayv1.SectionName(test.expectedSection), *route.Spec.ParentRefs[0].SectionName)
+			}
+		})
+	}
+}
+
+func TestGatewayProviderDualStack(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, gatewayv1.Install(scheme))
+	require.NoError(t, gatewayv1alpha2.Install(scheme))
+	gateway := &gatewayv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: dns.DefaultGatewayName, Namespace: dns.DefaultGatewayNamespace}}
+	gateway.Status.Addresses = []gatewayv1.GatewayStatusAddress{{Value: "203.0.113.10"}}
+	route := &gatewayv1alpha2.TLSRoute{ObjectMeta: metav1.ObjectMeta{Name: "project-workspace-webhook", Namespace: "openmcp-system"}}
+	route.Status.Parents = []gatewayv1alpha2.RouteParentStatus{
+		{
+			ParentRef: gatewayv1alpha2.ParentReference{
+				Name:      dns.DefaultGatewayName,
+				Namespace: new(gatewayv1.Namespace(dns.DefaultGatewayNamespace)),
+			},
+			Conditions: []metav1.Condition{{Type: string(gatewayv1alpha2.RouteConditionAccepted), Status: metav1.ConditionTrue}},
+		},
+	}
+	cluster := clusters.NewTestClusterFromClient("platform", fake.NewClientBuilder().WithScheme(scheme).WithObjects(gateway, route).WithStatusSubresource(gateway, route).Build())
+	instance := &dns.Instance{Name: route.Name, Namespace: route.Namespace}
+
+	provider := dns.NewGatewayProvider()
+	ready, err := provider.IsRouteReady(ctx, instance, cluster)
+	require.NoError(t, err)
+	assert.True(t, ready, "IP families must not be checked if none are configured")
+
+	provider.IPFamilies = []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol}
+	ready, err = provider.IsRouteReady(ctx, instance, cluster)
+	require.NoError(t, err)
+	assert.False(t, ready, "route must not be ready before the gateway has an IPv6 address")
+
+	require.NoError(t, cluster.Client().Get(ctx, client.ObjectKeyFromObject(gateway), gateway))
+	gateway.Status.Addresses = append(gateway.Status.Addresses, gatewayv1.GatewayStatusAddress{Value: "2001:db8::10"})
+	require.NoError(t, cluster.Client().Status().Update(ctx, gateway))
+	ready, err = provider.IsRouteReady(ctx, instance, cluster)
+	require.NoError(t, err)
+	assert.True(t, ready)
+}
+
+func TestGatewayProviderLifecycle(t *testing.T) {
+	ctx := context.Background()
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        dns.DefaultGatewayName,
+			Namespace:   dns.DefaultGatewayNamespace,
+			Annotations: map[string]string{dns.DNSAnnotationKey: "example.com"},
+		},
+		Spec: gatewayv1.GatewaySpec{
+			Listeners: []gatewayv1.Listener{{Name: "tls", Protocol: gatewayv1.TLSProtocolType, Port: 443}},
+		},
+	}
+	instance := &dns.Instance{
+		Name:            "project-workspace-webhook",
+		Namespace:       "openmcp-system",
+		SubDomainPrefix: "pwo-webhooks",
+		BackendName:     "project-workspace-webhook",
+		BackendPort:     443,
+	}
+
+	t.Run("requeues until the gateway exists", func(t *testing.T) {
+		controller, err := dns.NewFakeGatewayController(gateway)
+		require.NoError(t, err)
+		require.NoError(t, controller.Cluster.Client().Delete(ctx, gateway.DeepCopy()))
+
+		result, err := dns.NewGatewayProvider().ReconcileEndpoint(ctx, instance, controller.Cluster)
+		require.NoError(t, err)
+		assert.Equal(t, dns.RequeueInterval, result.RequeueAfter)
+	})
+
+	t.Run("fails without base domain annotation if the instance has no host name", func(t *testing.T) {
+		withoutDomain := gateway.DeepCopy()
+		withoutDomain.Annotations = nil
+		controller, err := dns.NewFakeGatewayController(withoutDomain)
+		require.NoError(t, err)
+
+		_, err = dns.NewGatewayProvider().ReconcileEndpoint(ctx, instance, controller.Cluster)
+		assert.Error(t, err)
+
+		inst := *instance
+		inst.HostName = "webhooks.example.com"
+		result, err := dns.NewGatewayProvider().ReconcileEndpoint(ctx, &inst, controller.Cluster)
+		require.NoError(t, err)
+		assert.Equal(t, "webhooks.example.com", result.HostName)
+	})
+
+	t.Run("creates a route which becomes ready once it has been accepted", func(t *testing.T) {
+		controller, err := dns.NewFakeGatewayController(gateway)
+		require.NoError(t, err)
+		provider := dns.NewGatewayProvider()
+
+		result, err := provider.ReconcileEndpoint(ctx, instance, controller.Cluster)
+		require.NoError(t, err)
+		assert.Zero(t, result.RequeueAfter)
+		assert.Equal(t, "pwo-webhooks.example.com", result.HostName)
+
+		require.NoError(t, provider.ReconcileRoute(ctx, instance, controller.Cluster))
+		ready, err := provider.IsRouteReady(ctx, instance, controller.Cluster)
+		require.NoError(t, err)
+		assert.False(t, ready, "route must not be ready before it has been accepted")
+
+		require.NoError(t, controller.Reconcile(ctx))
+		ready, err = provider.IsRouteReady(ctx, instance, controller.Cluster)
+		require.NoError(t, err)
+		assert.True(t, ready)
+
+		require.NoError(t, provider.ReconcileRoute(ctx, instance, controller.Cluster), "updating an existing route must not fail")
+
+		require.NoError(t, provider.DeleteRoute(ctx, instance, controller.Cluster))
+		err = controller.Cluster.Client().Get(ctx, client.ObjectKey{Name: instance.Name, Namespace: instance.Namespace}, &gatewayv1alpha2.TLSRoute{})
+		assert.True(t, apierrors.IsNotFound(err), "route must be deleted")
+		require.NoError(t, provider.DeleteRoute(ctx, instance, controller.Cluster), "deleting a missing route must not fail")
+		_, err = provider.IsRouteReady(ctx, instance, controller.Cluster)
+		assert.Error(t, err, "a missing route must not be reported as ready")
+	})
+
+	t.Run("does not report routes as ready which are accepted by another gateway", func(t *testing.T) {
+		controller, err := dns.NewFakeGatewayController(gateway)
+		require.NoError(t, err)
+		require.NoError(t, dns.NewGatewayProvider().ReconcileRoute(ctx, instance, controller.Cluster))
+		require.NoError(t, controller.Reconcile(ctx))
+
+		other := dns.NewGatewayProvider()
+		other.GatewayNamespace = "other"
+		ready, err := other.IsRouteReady(ctx, instance, controller.Cluster)
+		require.NoError(t, err)
+		assert.False(t, ready)
+	})
+
+	t.Run("applies the route mutator and readiness check", func(t *testing.T) {
+		controller, err := dns.NewFakeGatewayController(gateway)
+		require.NoError(t, err)
+		programmed := false
+		provider := dns.NewGatewayProvider()
+		provider.RouteMutator = func(route *gatewayv1alpha2.TLSRoute, gw *gatewayv1.Gateway) error {
+			route.SetAnnotations(map[string]string{"gateway.example.com/class": gw.Name})
+			return nil
+		}
+		provider.ReadinessCheck = func(_ context.Context, route *gatewayv1alpha2.TLSRoute, gw *gatewayv1.Gateway) (bool, error) {
+			assert.Equal(t, instance.Name, route.Name)
+			assert.Equal(t, gateway.Name, gw.Name)
+			return programmed, nil
+		}
+
+		require.NoError(t, provider.ReconcileRoute(ctx, instance, controller.Cluster))
+		route := &gatewayv1alpha2.TLSRoute{}
+		require.NoError(t, controller.Cluster.Client().Get(ctx, client.ObjectKey{Name: instance.Name, Namespace: instance.Namespace}, route))
+		assert.Equal(t, gateway.Name, route.GetAnnotations()["gateway.example.com/class"])
+
+		require.NoError(t, controller.Reconcile(ctx))
+		ready, err := provider.IsRouteReady(ctx, instance, controller.Cluster)
+		require.NoError(t, err)
+		assert.False(t, ready, "route must not be ready before the readiness check succeeds")
+
+		programmed = true
+		ready, err = provider.IsRouteReady(ctx, instance, controller.Cluster)
+		require.NoError(t, err)
+		assert.True(t, ready)
+	})
+}
+
+func TestFakeGatewayController(t *testing.T) {
+	ctx := context.Background()
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhooks", Namespace: "gateways"},
+		Spec: gatewayv1.GatewaySpec{
+			Listeners: []gatewayv1.Listener{
+				{Name: "https", Protocol: gatewayv1.HTTPSProtocolType, Port: 443},
+				{Name: "tls", Protocol: gatewayv1.TLSProtocolType, Port: 443},
+			},
+		},
+	}
+	newRoute := func(name string, sectionName *gatewayv1.SectionName) *gatewayv1alpha2.TLSRoute {
+		route := &gatewayv1alpha2.TLSRoute{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "openmcp-system"}}
+		route.Spec.ParentRefs = []gatewayv1alpha2.ParentReference{{
+			Name:        "webhooks",
+			Namespace:   new(gatewayv1.Namespace("gateways")),
+			SectionName: sectionName,
+		}}
+		return route
+	}
+	unattached := &gatewayv1alpha2.TLSRoute{ObjectMeta: metav1.ObjectMeta{Name: "unattached", Namespace: "openmcp-system"}}
+
+	controller, err := dns.NewFakeGatewayController(gateway, newRoute("all", nil), newRoute("tls", new(gatewayv1.SectionName("tls"))), newRoute("https", new(gatewayv1.SectionName("https"))), unattached)
+	require.NoError(t, err)
+	controller.Addresses = []string{"203.0.113.10", "2001:db8::10"}
+	require.NoError(t, controller.Reconcile(ctx))
+
+	expected := map[string]bool{"all": true, "tls": true, "https": false}
+	for name, accepted := range expected {
+		route := &gatewayv1alpha2.TLSRoute{}
+		require.NoError(t, controller.Cluster.Client().Get(ctx, client.ObjectKey{Name: name, Namespace: "openmcp-system"}, route))
+		assert.Equal(t, accepted, dns.IsRouteAccepted(route, "webhooks", "gateways"), "route %s", name)
+	}
+	route := &gatewayv1alpha2.TLSRoute{}
+	require.NoError(t, controller.Cluster.Client().Get(ctx, client.ObjectKeyFromObject(unattached), route))
+	assert.Empty(t, route.Status.Parents, "routes which are not attached to the gateway must not get a status")
+
+	gw := &gatewayv1.Gateway{}
+	require.NoError(t, controller.Cluster.Client().Get(ctx, client.ObjectKeyFromObject(gateway), gw))
+	assert.Len(t, gw.Status.Addresses, 2)
+}
@@ -0,0 +1,114 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openmcp-project/controller-utils/pkg/clusters"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+// FakeGatewayController simulates the controller of a Gateway API implementation, so that the GatewayProvider can be tested without a live cluster.
+// It is meant for unit tests and should not be used anywhere else.
+type FakeGatewayController struct {
+	// Cluster contains the gateway and the TLSRoutes.
+	Cluster *clusters.Cluster
+	// GatewayName is the name of the gateway which is served by the controller.
+	GatewayName string
+	// GatewayNamespace is the namespace of the gateway which is served by the controller.
+	GatewayNamespace string
+	// Addresses are reported in the status of the gateway.
+	Addresses []string
+}
+
+// NewFakeGatewayController creates a FakeGatewayController for the given gateway.
+// The cluster uses a fake client whose scheme contains the Gateway API types and which contains the gateway and the given objects.
+func NewFakeGatewayController(gateway *gatewayv1.Gateway, objs ...client.Object) (*FakeGatewayController, error) {
+	scheme := runtime.NewScheme()
+	if err := gatewayv1.Install(scheme); err != nil {
+		return nil, fmt.Errorf("failed to install Gateway API v1 types: %w", err)
+	}
+	if err := gatewayv1alpha2.Install(scheme); err != nil {
+		return nil, fmt.Errorf("failed to install Gateway API v1alpha2 types: %w", err)
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(append([]client.Object{gateway}, objs...)...).
+		WithStatusSubresource(&gatewayv1.Gateway{}, &gatewayv1alpha2.TLSRoute{}).
+		Build()
+	return &FakeGatewayController{
+		Cluster:          clusters.NewTestClusterFromClient("platform", c),
+		GatewayName:      gateway.Name,
+		GatewayNamespace: gateway.Namespace,
+	}, nil
+}
+
+// Reconcile updates the status of the gateway and of all TLSRoutes which are attached to it, like a Gateway API implementation would.
+// A route is accepted if it does not select a listener or selects a listener of the gateway with TLS protocol.
+func (c *FakeGatewayController) Reconcile(ctx context.Context) error {
+	gateway := &gatewayv1.Gateway{}
+	if err := c.Cluster.Client().Get(ctx, client.ObjectKey{Name: c.GatewayName, Namespace: c.GatewayNamespace}, gateway); err != nil {
+		return fmt.Errorf("failed to get gateway: %w", err)
+	}
+	gateway.Status.Addresses = make([]gatewayv1.GatewayStatusAddress, 0, len(c.Addresses))
+	for _, address := range c.Addresses {
+		gateway.Status.Addresses = append(gateway.Status.Addresses, gatewayv1.GatewayStatusAddress{Value: address})
+	}
+	if err := c.Cluster.Client().Status().Update(ctx, gateway); err != nil {
+		return fmt.Errorf("failed to update status of gateway: %w", err)
+	}
+
+	routes := &gatewayv1alpha2.TLSRouteList{}
+	if err := c.Cluster.Client().List(ctx, routes); err != nil {
+		return fmt.Errorf("failed to list TLSRoutes: %w", err)
+	}
+	for i := range routes.Items {
+		route := &routes.Items[i]
+		parents := make([]gatewayv1alpha2.RouteParentStatus, 0, len(route.Spec.ParentRefs))
+		for _, ref := range route.Spec.ParentRefs {
+			if string(ref.Name) != gateway.Name || ref.Namespace == nil || string(*ref.Namespace) != gateway.Namespace {
+				continue
+			}
+			condition := metav1.Condition{
+				Type:               string(gatewayv1alpha2.RouteConditionAccepted),
+				Status:             metav1.ConditionTrue,
+				Reason:             string(gatewayv1alpha2.RouteReasonAccepted),
+				ObservedGeneration: route.Generation,
+			}
+			if ref.SectionName != nil && !hasTLSListener(gateway, *ref.SectionName) {
+				condition.Status = metav1.ConditionFalse
+				condition.Reason = string(gatewayv1.RouteReasonNoMatchingParent)
+			}
+			status := gatewayv1alpha2.RouteParentStatus{
+				ParentRef:      ref,
+				ControllerName: "fake.openmcp.cloud/gateway-controller",
+			}
+			meta.SetStatusCondition(&status.Conditions, condition)
+			parents = append(parents, status)
+		}
+		if len(parents) == 0 {
+			continue
+		}
+		route.Status.Parents = parents
+		if err := c.Cluster.Client().Status().Update(ctx, route); err != nil {
+			return fmt.Errorf("failed to update status of TLSRoute '%s': %w", client.ObjectKeyFromObject(route).String(), err)
+		}
+	}
+	return nil
+}
+
+// hasTLSListener returns true if the given gateway has a listener with TLS protocol and the given name.
+func hasTLSListener(gateway *gatewayv1.Gateway, name gatewayv1.SectionName) bool {
+	for _, listener := range gateway.Spec.Listeners {
+		if listener.Name == name && listener.Protocol == gatewayv1.TLSProtocolType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,337 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/openmcp-project/controller-utils/pkg/clusters"
+	"github.com/openmcp-project/controller-utils/pkg/collections/filters"
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+const (
+	DefaultGatewayName      = "default"
+	DefaultGatewayNamespace = "openmcp-system"
+	DNSAnnotationKey        = "dns.openmcp.cloud/base-domain"
+)
+
+// GatewayProvider is a Provider which manages DNS records using Gateway API resources.
+// The instance is exposed via a TLSRoute which is attached to the configured gateway, which is the default gateway unless configured otherwise.
+type GatewayProvider struct {
+	// GatewayName is the name of the gateway to which the TLSRoute is attached.
+	GatewayName string
+	// GatewayNamespace is the namespace of the gateway to which the TLSRoute is attached.
+	GatewayNamespace string
+	// ListenerName selects the TLS listener of the gateway by name.
+	ListenerName string
+	// Port selects the TLS listener of the gateway by port, if no ListenerName is set.
+	Port int32
+	// IPFamilies are the IP families for which the gateway needs to have an address before the route is considered ready.
+	IPFamilies []corev1.IPFamily
+	// RouteMutator is called whenever the TLSRoute is created or updated, after its spec has been set.
+	// It allows to adapt the route to gateway implementations which require additional configuration, e.g. annotations. Optional.
+	RouteMutator RouteMutator
+	// ReadinessCheck is called by IsRouteReady once the TLSRoute has been accepted and the gateway has an address of each configured IP family.
+	// It allows to wait for conditions which are specific to the gateway implementation, e.g. the programming of the route. Optional.
+	ReadinessCheck RouteReadinessCheck
+}
+
+// RouteMutator adapts the TLSRoute which is created for an instance to the given gateway.
+type RouteMutator func(route *gatewayv1alpha2.TLSRoute, gateway *gatewayv1.Gateway) error
+
+// RouteReadinessCheck checks whether the given TLSRoute, which has been accepted by the given gateway, is ready to route traffic.
+type RouteReadinessCheck func(ctx context.Context, route *gatewayv1alpha2.TLSRoute, gateway *gatewayv1.Gateway) (bool, error)
+
+var _ Provider = &GatewayProvider{}
+
+// NewGatewayProvider creates a new Gateway API based DNS provider which uses the default gateway.
+func NewGatewayProvider() *GatewayProvider {
+	return &GatewayProvider{
+		GatewayName:      DefaultGatewayName,
+		GatewayNamespace: DefaultGatewayNamespace,
+	}
+}
+
+// ReconcileEndpoint ensures that the gateway exists and retrieves the base domain from its annotations.
+// It returns the full hostname for the given instance that can be used for DNS records, and the port of the selected TLS listener.
+// If the gateway is not found, it will requeue after a predefined interval.
+func (r *GatewayProvider) ReconcileEndpoint(ctx context.Context, instance *Instance, targetCluster *clusters.Cluster) (EndpointReconcileResult, error) {
+	log := logging.FromContextOrDiscard(ctx)
+
+	var err error
+
+	// get gateway
+
+	gateway := r.gateway()
+
+	if err = targetCluster.Client().Get(ctx, client.ObjectKeyFromObject(gateway), gateway); err != nil {
+		if errors.IsNotFound(err) {
+			log.Debug("Gateway not found, requeueing...", "gateway", client.ObjectKeyFromObject(gateway).String())
+			// gateway not found
+			return EndpointReconcileResult{
+				Result: reconcile.Result{
+					RequeueAfter: RequeueInterval,
+				},
+			}, nil
+		}
+
+		return EndpointReconcileResult{Result: reconcile.Result{}}, fmt.Errorf("failed to get gateway: %w", err)
+	}
+
+	log.Debug("Gateway available", "gateway", client.ObjectKeyFromObject(gateway).String())
+
+	hostName, err := r.hostName(gateway, instance)
+	if err != nil {
+		return EndpointReconcileResult{Result: reconcile.Result{}}, err
+	}
+
+	log.Debug("Host name determined", "hostName", hostName)
+
+	listener, err := r.getTLSListener(gateway)
+	if err != nil {
+		return EndpointReconcileResult{Result: reconcile.Result{}}, err
+	}
+
+	log.Debug("TLS listener found", "listener", listener.Name, "tlsPort", listener.Port)
+
+	return EndpointReconcileResult{
+		HostName: hostName,
+		TLSPort:  listener.Port,
+		Result:   reconcile.Result{},
+	}, nil
+}
+
+// ReconcileRoute ensures that a TLSRoute exists for the given instance, pointing to the gateway.
+// If a listener is selected by name or port, the TLSRoute is attached to this listener only.
+func (r *GatewayProvider) ReconcileRoute(ctx context.Context, instance *Instance, targetCluster *clusters.Cluster) error {
+	// get gateway
+
+	var err error
+
+	gateway := r.gateway()
+
+	if err = targetCluster.Client().Get(ctx, client.ObjectKeyFromObject(gateway), gateway); err != nil {
+		return fmt.Errorf("failed to get gateway: %w", err)
+	}
+
+	hostName, err := r.hostName(gateway, instance)
+	if err != nil {
+		return err
+	}
+
+	parentRef := gatewayv1alpha2.ParentReference{
+		Name:      gatewayv1.ObjectName(gateway.Name),
+		Namespace: new(gatewayv1.Namespace(gateway.Namespace)),
+	}
+	if r.ListenerName != "" || r.Port != 0 {
+		listener, err := r.getTLSListener(gateway)
+		if err != nil {
+			return err
+		}
+		parentRef.SectionName = new(listener.Name)
+	}
+
+	tlsRoute := &gatewayv1alpha2.TLSRoute{}
+	tlsRoute.SetName(instance.Name)
+	tlsRoute.SetNamespace(instance.Namespace)
+
+	_, err = controllerruntime.CreateOrUpdate(ctx, targetCluster.Client(), tlsRoute, func() error {
+		tlsRoute.Spec = gatewayv1alpha2.TLSRouteSpec{
+			CommonRouteSpec: gatewayv1alpha2.CommonRouteSpec{
+				ParentRefs: []gatewayv1alpha2.ParentReference{parentRef},
+			},
+			Hostnames: []gatewayv1alpha2.Hostname{
+				gatewayv1alpha2.Hostname(hostName),
+			},
+			Rules: []gatewayv1alpha2.TLSRouteRule{
+				{
+					BackendRefs: []gatewayv1alpha2.BackendRef{
+						{
+							BackendObjectReference: gatewayv1alpha2.BackendObjectReference{
+								Name: gatewayv1.ObjectName(instance.BackendName),
+								Port: new(instance.BackendPort),
+							},
+						},
+					},
+				},
+			},
+		}
+		if r.RouteMutator != nil {
+			return r.RouteMutator(tlsRoute, gateway)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to create or update TLSRoute: %w", err)
+	}
+
+	return nil
+}
+
+// IsRouteReady checks if the TLSRoute for the given instance is accepted by the gateway.
+// If IP families are configured, the gateway additionally needs to have an address of each family, and the ReadinessCheck needs to succeed, if set.
+func (r *GatewayProvider) IsRouteReady(ctx context.Context, instance *Instance, targetCluster *clusters.Cluster) (bool, error) {
+	log := logging.FromContextOrDiscard(ctx)
+
+	var err error
+
+	tlsRoute := &gatewayv1alpha2.TLSRoute{}
+	tlsRoute.SetName(instance.Name)
+	tlsRoute.SetNamespace(instance.Namespace)
+
+	if err = targetCluster.Client().Get(ctx, client.ObjectKeyFromObject(tlsRoute), tlsRoute); err != nil {
+		return false, fmt.Errorf("failed to get TLSRoute: %w", err)
+	}
+
+	if !IsRouteAccepted(tlsRoute, r.GatewayName, r.GatewayNamespace) {
+		return false, nil
+	}
+	log.Debug("TLSRoute is accepted by the gateway")
+
+	if len(r.IPFamilies) == 0 && r.ReadinessCheck == nil {
+		return true, nil
+	}
+	gateway := r.gateway()
+	if err = targetCluster.Client().Get(ctx, client.ObjectKeyFromObject(gateway), gateway); err != nil {
+		return false, fmt.Errorf("failed to get gateway: %w", err)
+	}
+	if len(r.IPFamilies) > 0 {
+		addresses := make([]string, 0, len(gateway.Status.Addresses))
+		for _, address := range gateway.Status.Addresses {
+			addresses = append(addresses, address.Value)
+		}
+		if missing := missingIPFamilies(r.IPFamilies, addresses); len(missing) > 0 {
+			log.Debug("Gateway does not have an address of each IP family yet", "missing", missing)
+			return false, nil
+		}
+	}
+	if r.ReadinessCheck != nil {
+		ready, err := r.ReadinessCheck(ctx, tlsRoute, gateway)
+		if err != nil {
+			return false, fmt.Errorf("failed to check readiness of TLSRoute: %w", err)
+		}
+		if !ready {
+			log.Debug("TLSRoute is not ready according to the readiness check")
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// IsRouteAccepted returns true if the given TLSRoute has been accepted by the gateway with the given name and namespace, according to its status.
+func IsRouteAccepted(route *gatewayv1alpha2.TLSRoute, gatewayName, gatewayNamespace string) bool {
+	for _, parent := range route.Status.Parents {
+		if string(parent.ParentRef.Name) != gatewayName || parent.ParentRef.Namespace == nil || string(*parent.ParentRef.Namespace) != gatewayNamespace {
+			continue
+		}
+		for _, cond := range parent.Conditions {
+			if cond.Type == string(gatewayv1alpha2.RouteConditionAccepted) && cond.Status == metav1.ConditionTrue {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DeleteRoute deletes the TLSRoute for the given instance.
+func (r *GatewayProvider) DeleteRoute(ctx context.Context, instance *Instance, targetCluster *clusters.Cluster) error {
+	log := logging.FromContextOrDiscard(ctx)
+
+	tlsRoute := &gatewayv1alpha2.TLSRoute{}
+	tlsRoute.SetName(instance.Name)
+	tlsRoute.SetNamespace(instance.Namespace)
+
+	if err := targetCluster.Client().Get(ctx, client.ObjectKeyFromObject(tlsRoute), tlsRoute); err != nil {
+		if errors.IsNotFound(err) {
+			log.Debug("TLSRoute already deleted")
+			return nil
+		}
+		return fmt.Errorf("failed to get TLSRoute: %w", err)
+	}
+
+	if err := targetCluster.Client().Delete(ctx, tlsRoute); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete TLSRoute: %w", err)
+	}
+
+	log.Info("TLSRoute deleted")
+
+	return nil
+}
+
+func getBaseDomain(gateway *gatewayv1.Gateway) (string, bool) {
+	annotations := gateway.GetAnnotations()
+	if len(annotations) == 0 {
+		return "", false
+	}
+
+	baseDomain, hasBaseDomain := annotations[DNSAnnotationKey]
+	return baseDomain, hasBaseDomain
+}
+
+// gateway returns an empty Gateway with the configured name and namespace.
+func (r *GatewayProvider) gateway() *gatewayv1.Gateway {
+	gateway := &gatewayv1.Gateway{}
+	gateway.SetName(r.GatewayName)
+	gateway.SetNamespace(r.GatewayNamespace)
+	return gateway
+}
+
+// hostName returns the host name of the given instance. The base domain annotation of the gateway is only required if the instance does not have a fixed host name.
+func (r *GatewayProvider) hostName(gateway *gatewayv1.Gateway, instance *Instance) (string, error) {
+	if instance.HostName != "" {
+		return instance.HostName, nil
+	}
+	baseDomain, hasBaseDomain := getBaseDomain(gateway)
+	if !hasBaseDomain {
+		return "", fmt.Errorf("gateway is missing the %s annotation", DNSAnnotationKey)
+	}
+	return getHostName(baseDomain, instance), nil
+}
+
+// getTLSListener returns the TLS listener of the gateway to which the route is attached.
+// logic as follows:
+// - if a listener name is configured, the TLS listener with this name is returned
+// - if a port is configured, only TLS listeners with this port are considered
+// - if a single TLS listener remains, it is returned
+// - if multiple TLS listeners remain and one is named "tls", it is returned
+// - in all other cases, an error is returned
+func (r *GatewayProvider) getTLSListener(gateway *gatewayv1.Gateway) (*gatewayv1.Listener, error) {
+	tlsListeners := filters.FilterSlice(gateway.Spec.Listeners, func(args ...any) bool {
+		elem := args[0].(gatewayv1.Listener)
+		return elem.Protocol == gatewayv1.TLSProtocolType
+	})
+	if r.ListenerName != "" {
+		for _, listener := range tlsListeners {
+			if string(listener.Name) == r.ListenerName {
+				return &listener, nil
+			}
+		}
+		return nil, fmt.Errorf("gateway does not have a listener with TLS protocol named '%s'", r.ListenerName)
+	}
+	if r.Port != 0 {
+		tlsListeners = slices.DeleteFunc(tlsListeners, func(listener gatewayv1.Listener) bool { return listener.Port != r.Port })
+		if len(tlsListeners) == 0 {
+			return nil, fmt.Errorf("gateway does not have a listener with TLS protocol on port %d", r.Port)
+		}
+	}
+	if len(tlsListeners) == 1 {
+		return &tlsListeners[0], nil
+	}
+	for _, listener := range tlsListeners {
+		if listener.Name == "tls" {
+			return &listener, nil
+		}
+	}
+	return nil, fmt.Errorf("gateway either does not have any listeners with TLS protocol or it has multiple ones and none is named 'tls', configure the listener name to select one")
+}
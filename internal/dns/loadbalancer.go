@@ -0,0 +1,164 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openmcp-project/controller-utils/pkg/clusters"
+	ctrlutils "github.com/openmcp-project/controller-utils/pkg/controller"
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openmcp-project/platform-service-project-workspace/api/v2/metadata"
+)
+
+const (
+	// ExternalDNSHostnameAnnotationKey is the annotation which tells external-dns to create a DNS record for a Service.
+	ExternalDNSHostnameAnnotationKey = "external-dns.alpha.kubernetes.io/hostname"
+
+	loadBalancerServiceSuffix = "-lb"
+)
+
+// LoadBalancerProvider is a Provider which exposes the instance via a Service of type LoadBalancer.
+// The DNS record is expected to be created by external-dns, based on the annotation of the Service.
+// In contrast to the GatewayProvider, the traffic is routed to the pods of the backend directly, so Instance.BackendSelector and Instance.BackendTargetPort need to be set.
+type LoadBalancerProvider struct {
+	// BaseDomain is the domain under which the host names for the instances are created.
+	BaseDomain string
+	// Annotations are added to the Service, in addition to the external-dns annotation.
+	Annotations map[string]string
+	// Port is the port of the Service. Defaults to Instance.BackendPort.
+	Port int32
+	// IPFamilies are the IP families of the Service. If empty, the defaults of the cluster are used.
+	IPFamilies []corev1.IPFamily
+}
+
+var _ Provider = &LoadBalancerProvider{}
+
+// NewLoadBalancerProvider creates a new LoadBalancer Service based DNS provider.
+func NewLoadBalancerProvider(baseDomain string, annotations map[string]string) *LoadBalancerProvider {
+	return &LoadBalancerProvider{
+		BaseDomain:  baseDomain,
+		Annotations: annotations,
+	}
+}
+
+// ReconcileEndpoint returns the full hostname for the given instance that can be used for DNS records.
+// There are no prerequisites for this provider, so it never requeues.
+func (r *LoadBalancerProvider) ReconcileEndpoint(_ context.Context, instance *Instance, _ *clusters.Cluster) (EndpointReconcileResult, error) {
+	return EndpointReconcileResult{
+		HostName: getHostName(r.BaseDomain, instance),
+		TLSPort:  r.port(instance),
+		Result:   reconcile.Result{},
+	}, nil
+}
+
+// ReconcileRoute ensures that a Service of type LoadBalancer exists for the given instance, which is annotated with the hostname for external-dns.
+func (r *LoadBalancerProvider) ReconcileRoute(ctx context.Context, instance *Instance, targetCluster *clusters.Cluster) error {
+	if len(instance.BackendSelector) == 0 {
+		return fmt.Errorf("backend selector is required for exposing instance '%s/%s' via a LoadBalancer service", instance.Namespace, instance.Name)
+	}
+
+	svc := loadBalancerService(instance)
+	_, err := controllerruntime.CreateOrUpdate(ctx, targetCluster.Client(), svc, func() error {
+		metadata.EnsureAnnotations(svc, r.Annotations)
+		metadata.SetAnnotation(svc, ExternalDNSHostnameAnnotationKey, getHostName(r.BaseDomain, instance))
+
+		svc.Spec.Type = corev1.ServiceTypeLoadBalancer
+		svc.Spec.Selector = instance.BackendSelector
+		svc.Spec.Ports = []corev1.ServicePort{
+			{
+				Name:       "https",
+				Protocol:   corev1.ProtocolTCP,
+				Port:       r.port(instance),
+				TargetPort: intstr.FromInt32(instance.BackendTargetPort),
+			},
+		}
+		if len(r.IPFamilies) > 0 {
+			svc.Spec.IPFamilies = r.IPFamilies
+			svc.Spec.IPFamilyPolicy = new(corev1.IPFamilyPolicySingleStack)
+			if len(r.IPFamilies) > 1 {
+				svc.Spec.IPFamilyPolicy = new(corev1.IPFamilyPolicyRequireDualStack)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create or update LoadBalancer service: %w", err)
+	}
+
+	return nil
+}
+
+// IsRouteReady checks if the load balancer for the Service of the given instance has been provisioned.
+// If IP families are configured, the load balancer needs to have an address of each family.
+func (r *LoadBalancerProvider) IsRouteReady(ctx context.Context, instance *Instance, targetCluster *clusters.Cluster) (bool, error) {
+	log := logging.FromContextOrDiscard(ctx)
+
+	svc := loadBalancerService(instance)
+	if err := targetCluster.Client().Get(ctx, client.ObjectKeyFromObject(svc), svc); err != nil {
+		return false, fmt.Errorf("failed to get LoadBalancer service: %w", err)
+	}
+
+	if len(svc.Status.LoadBalancer.Ingress) == 0 {
+		return false, nil
+	}
+	if len(r.IPFamilies) > 0 {
+		addresses := make([]string, 0, len(svc.Status.LoadBalancer.Ingress))
+		for _, ingress := range svc.Status.LoadBalancer.Ingress {
+			if ingress.Hostname != "" {
+				addresses = append(addresses, ingress.Hostname)
+			}
+			if ingress.IP != "" {
+				addresses = append(addresses, ingress.IP)
+			}
+		}
+		if missing := missingIPFamilies(r.IPFamilies, addresses); len(missing) > 0 {
+			log.Debug("LoadBalancer service does not have an address of each IP family yet", "missing", missing)
+			return false, nil
+		}
+	}
+
+	log.Debug("LoadBalancer service has been provisioned")
+	return true, nil
+}
+
+// DeleteRoute deletes the Service of type LoadBalancer for the given instance.
+func (r *LoadBalancerProvider) DeleteRoute(ctx context.Context, instance *Instance, targetCluster *clusters.Cluster) error {
+	log := logging.FromContextOrDiscard(ctx)
+
+	svc := loadBalancerService(instance)
+	if err := targetCluster.Client().Delete(ctx, svc); err != nil {
+		if errors.IsNotFound(err) {
+			log.Debug("LoadBalancer service already deleted")
+			return nil
+		}
+		return fmt.Errorf("failed to delete LoadBalancer service: %w", err)
+	}
+
+	log.Info("LoadBalancer service deleted")
+
+	return nil
+}
+
+// port returns the port of the Service for the given instance.
+func (r *LoadBalancerProvider) port(instance *Instance) int32 {
+	if r.Port != 0 {
+		return r.Port
+	}
+	return instance.BackendPort
+}
+
+// loadBalancerService returns an empty Service with the name and namespace of the LoadBalancer Service for the given instance.
+// The name differs from the instance name, because the instance name is usually also used for the ClusterIP Service of the backend.
+func loadBalancerService(instance *Instance) *corev1.Service {
+	svc := &corev1.Service{}
+	svc.SetName(ctrlutils.ShortenToXCharactersUnsafe(instance.Name, ctrlutils.K8sMaxNameLength-len(loadBalancerServiceSuffix)) + loadBalancerServiceSuffix)
+	svc.SetNamespace(instance.Namespace)
+	return svc
+}
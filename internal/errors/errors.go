@@ -0,0 +1,103 @@
+// Package errors contains typed reconcile errors, which tell the reconcilers how to react to a failed reconciliation.
+// Errors which are not wrapped in one of these types are treated as retriable.
+package errors
+
+import (
+	"errors"
+)
+
+// Kind classifies a reconcile error.
+type Kind string
+
+const (
+	// KindRetriable is the kind of transient errors, e.g. failed API calls. The reconciliation is retried with backoff.
+	KindRetriable Kind = "Retriable"
+	// KindTerminal is the kind of errors which cannot be resolved by retrying, e.g. an invalid configuration.
+	// The reconciliation is not retried until the object or the configuration changes.
+	KindTerminal Kind = "Terminal"
+	// KindBlocked is the kind of errors which indicate that the reconciliation waits for something else to happen, e.g. for remaining resources to be deleted.
+	// The reconciliation is retried with backoff, but this is not considered a failure.
+	KindBlocked Kind = "Blocked"
+)
+
+// RetriableError is a transient error. The reconciliation is retried with backoff.
+type RetriableError struct {
+	Err error
+}
+
+func (e *RetriableError) Error() string { return e.Err.Error() }
+func (e *RetriableError) Unwrap() error { return e.Err }
+
+// TerminalError is an error which cannot be resolved by retrying. The reconciliation is not retried until the object or the configuration changes.
+type TerminalError struct {
+	Err error
+}
+
+func (e *TerminalError) Error() string { return e.Err.Error() }
+func (e *TerminalError) Unwrap() error { return e.Err }
+
+// BlockedError indicates that the reconciliation waits for something else to happen. The reconciliation is retried with backoff.
+type BlockedError struct {
+	Err error
+}
+
+func (e *BlockedError) Error() string { return e.Err.Error() }
+func (e *BlockedError) Unwrap() error { return e.Err }
+
+// NewRetriableError wraps the given error in a RetriableError. Returns nil if err is nil.
+func NewRetriableError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &RetriableError{Err: err}
+}
+
+// NewTerminalError wraps the given error in a TerminalError. Returns nil if err is nil.
+func NewTerminalError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &TerminalError{Err: err}
+}
+
+// NewBlockedError wraps the given error in a BlockedError. Returns nil if err is nil.
+func NewBlockedError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &BlockedError{Err: err}
+}
+
+// Classify returns the kind of the given error. The outermost typed error in the chain determines the kind,
+// errors which are not wrapped in any of the typed errors are retriable. Returns an empty kind if err is nil.
+func Classify(err error) Kind {
+	if err == nil {
+		return ""
+	}
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		switch e.(type) {
+		case *RetriableError:
+			return KindRetriable
+		case *TerminalError:
+			return KindTerminal
+		case *BlockedError:
+			return KindBlocked
+		}
+	}
+	return KindRetriable
+}
+
+// IsRetriable returns true if the given error is not nil and not classified as terminal or blocked.
+func IsRetriable(err error) bool {
+	return Classify(err) == KindRetriable
+}
+
+// IsTerminal returns true if the given error is classified as terminal.
+func IsTerminal(err error) bool {
+	return Classify(err) == KindTerminal
+}
+
+// IsBlocked returns true if the given error is classified as blocked.
+func IsBlocked(err error) bool {
+	return Classify(err) == KindBlocked
+}
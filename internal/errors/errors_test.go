@@ -0,0 +1,59 @@
+package errors_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	pwoerrors "github.com/openmcp-project/platform-service-project-workspace/internal/errors"
+)
+
+func TestClassify(t *testing.T) {
+	errFake := errors.New("fake")
+
+	testCases := []struct {
+		desc     string
+		err      error
+		expected pwoerrors.Kind
+	}{
+		{
+			desc:     "nil error has no kind",
+			err:      nil,
+			expected: "",
+		},
+		{
+			desc:     "untyped errors are retriable",
+			err:      errFake,
+			expected: pwoerrors.KindRetriable,
+		},
+		{
+			desc:     "terminal error",
+			err:      pwoerrors.NewTerminalError(errFake),
+			expected: pwoerrors.KindTerminal,
+		},
+		{
+			desc:     "blocked error wrapped with fmt.Errorf",
+			err:      fmt.Errorf("failed to delete: %w", pwoerrors.NewBlockedError(errFake)),
+			expected: pwoerrors.KindBlocked,
+		},
+		{
+			desc:     "outermost typed error wins",
+			err:      pwoerrors.NewRetriableError(fmt.Errorf("wrapped: %w", pwoerrors.NewTerminalError(errFake))),
+			expected: pwoerrors.KindRetriable,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			assert.Equal(t, tC.expected, pwoerrors.Classify(tC.err))
+			if tC.err != nil {
+				assert.ErrorIs(t, tC.err, errFake, "typed errors must unwrap to the original error")
+			}
+		})
+	}
+
+	assert.NoError(t, pwoerrors.NewTerminalError(nil))
+	assert.True(t, pwoerrors.IsBlocked(pwoerrors.NewBlockedError(errFake)))
+	assert.False(t, pwoerrors.IsRetriable(nil))
+}
@@ -0,0 +1,226 @@
+// Package export assembles reports about all projects and workspaces and their members, e.g. for compliance reporting.
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+// Format is the output format of a report.
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatCSV  Format = "csv"
+)
+
+// Formats returns all supported output formats.
+func Formats() []Format {
+	return []Format{FormatJSON, FormatCSV}
+}
+
+// Report contains all projects and workspaces with their members.
+type Report struct {
+	Projects   []Project   `json:"projects"`
+	Workspaces []Workspace `json:"workspaces"`
+}
+
+// Project is the exported representation of a Project.
+type Project struct {
+	Name           string   `json:"name"`
+	DisplayName    string   `json:"displayName,omitempty"`
+	Namespace      string   `json:"namespace,omitempty"`
+	CreatedBy      string   `json:"createdBy,omitempty"`
+	ChargingTarget string   `json:"chargingTarget,omitempty"`
+	Members        []Member `json:"members"`
+}
+
+// Workspace is the exported representation of a Workspace.
+// The members contain the members inherited from the project, if the workspace inherits the project members.
+type Workspace struct {
+	Name                   string   `json:"name"`
+	Project                string   `json:"project,omitempty"`
+	DisplayName            string   `json:"displayName,omitempty"`
+	Namespace              string   `json:"namespace,omitempty"`
+	CreatedBy              string   `json:"createdBy,omitempty"`
+	ChargingTarget         string   `json:"chargingTarget,omitempty"`
+	InheritsProjectMembers bool     `json:"inheritsProjectMembers,omitempty"`
+	Members                []Member `json:"members"`
+}
+
+// Member is the exported representation of a project or workspace member.
+type Member struct {
+	Kind      string   `json:"kind"`
+	Name      string   `json:"name"`
+	Namespace string   `json:"namespace,omitempty"`
+	Roles     []string `json:"roles"`
+}
+
+// Collect lists all projects and workspaces with the given client and assembles the report.
+// Workspaces are assigned to the project which owns their namespace. Workspaces without a charging target get the one of their project.
+func Collect(ctx context.Context, c client.Client) (*Report, error) {
+	projects := &pwv1alpha1.ProjectList{}
+	if err := c.List(ctx, projects); err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+	workspaces := &pwv1alpha1.WorkspaceList{}
+	if err := c.List(ctx, workspaces); err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+
+	report := &Report{
+		Projects:   make([]Project, 0, len(projects.Items)),
+		Workspaces: make([]Workspace, 0, len(workspaces.Items)),
+	}
+	projectsByNamespace := map[string]*pwv1alpha1.Project{}
+	for i := range projects.Items {
+		p := &projects.Items[i]
+		projectsByNamespace[utils.NamespaceForProject(p)] = p
+		report.Projects = append(report.Projects, NewProject(p))
+	}
+
+	for i := range workspaces.Items {
+		ws := &workspaces.Items[i]
+		report.Workspaces = append(report.Workspaces, NewWorkspace(ws, projectsByNamespace[ws.Namespace]))
+	}
+
+	slices.SortFunc(report.Projects, func(a, b Project) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	slices.SortFunc(report.Workspaces, func(a, b Workspace) int {
+		if res := strings.Compare(a.Project, b.Project); res != 0 {
+			return res
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return report, nil
+}
+
+// Write writes the report in the given format.
+func (r *Report) Write(w io.Writer, format Format) error {
+	switch format {
+	case FormatJSON:
+		return r.WriteJSON(w)
+	case FormatCSV:
+		return r.WriteCSV(w)
+	default:
+		return fmt.Errorf("unsupported format '%s'", format)
+	}
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r); err != nil {
+		return fmt.Errorf("failed to encode report as JSON: %w", err)
+	}
+	return nil
+}
+
+// CSVHeader is the header row of the CSV format.
+var CSVHeader = []string{"type", "project", "workspace", "displayName", "namespace", "createdBy", "chargingTarget", "memberKind", "memberName", "memberNamespace", "roles"}
+
+// WriteCSV writes the report as CSV with one row per member of each project and workspace.
+// Projects and workspaces without members get a single row with empty member columns. Multiple roles are separated by ';'.
+func (r *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(CSVHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	writeRows := func(prefix []string, members []Member) error {
+		if len(members) == 0 {
+			return cw.Write(append(prefix, "", "", "", ""))
+		}
+		for _, m := range members {
+			if err := cw.Write(append(slices.Clone(prefix), m.Kind, m.Name, m.Namespace, strings.Join(m.Roles, ";"))); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, p := range r.Projects {
+		if err := writeRows([]string{"project", p.Name, "", p.DisplayName, p.Namespace, p.CreatedBy, p.ChargingTarget}, p.Members); err != nil {
+			return fmt.Errorf("failed to write CSV rows for project '%s': %w", p.Name, err)
+		}
+	}
+	for _, ws := range r.Workspaces {
+		if err := writeRows([]string{"workspace", ws.Project, ws.Name, ws.DisplayName, ws.Namespace, ws.CreatedBy, ws.ChargingTarget}, ws.Members); err != nil {
+			return fmt.Errorf("failed to write CSV rows for workspace '%s': %w", ws.Name, err)
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// NewProject returns the exported representation of the given Project.
+func NewProject(p *pwv1alpha1.Project) Project {
+	members := make([]Member, 0, len(p.Spec.Members))
+	for _, m := range p.Spec.Members {
+		members = append(members, newMember(m.Subject, m.Roles))
+	}
+	return Project{
+		Name:           p.Name,
+		DisplayName:    p.GetAnnotations()[pwv1alpha1.DisplayNameAnnotation],
+		Namespace:      p.Status.Namespace,
+		CreatedBy:      p.GetAnnotations()[pwv1alpha1.CreatedByAnnotation],
+		ChargingTarget: p.GetAnnotations()[pwv1alpha1.ChargingTargetAnnotation],
+		Members:        members,
+	}
+}
+
+// NewWorkspace returns the exported representation of the given Workspace.
+// project is the Project owning the namespace of the workspace and may be nil, if it is not known.
+// It is used to resolve the inherited members and the charging target.
+func NewWorkspace(ws *pwv1alpha1.Workspace, project *pwv1alpha1.Project) Workspace {
+	exported := Workspace{
+		Name:                   ws.Name,
+		DisplayName:            ws.GetAnnotations()[pwv1alpha1.DisplayNameAnnotation],
+		Namespace:              ws.Status.Namespace,
+		CreatedBy:              ws.GetAnnotations()[pwv1alpha1.CreatedByAnnotation],
+		ChargingTarget:         ws.GetAnnotations()[pwv1alpha1.ChargingTargetAnnotation],
+		InheritsProjectMembers: ws.InheritsProjectMembers(),
+	}
+	if project != nil {
+		exported.Project = project.Name
+		if exported.ChargingTarget == "" {
+			exported.ChargingTarget = project.GetAnnotations()[pwv1alpha1.ChargingTargetAnnotation]
+		}
+	}
+	effectiveMembers := ws.EffectiveMembers(project)
+	exported.Members = make([]Member, 0, len(effectiveMembers))
+	for _, m := range effectiveMembers {
+		exported.Members = append(exported.Members, newMember(m.Subject, m.Roles))
+	}
+	return exported
+}
+
+func newMember[R ~string](subject pwv1alpha1.Subject, roles []R) Member {
+	res := Member{
+		Kind:      subject.Kind,
+		Name:      subject.Name,
+		Namespace: subject.Namespace,
+		Roles:     make([]string, 0, len(roles)),
+	}
+	for _, role := range roles {
+		res.Roles = append(res.Roles, string(role))
+	}
+	return res
+}
@@ -0,0 +1,142 @@
+package export_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/api/v2/install"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/export"
+)
+
+func testReport(t *testing.T) *export.Report {
+	project := &pwv1alpha1.Project{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "sample",
+			Annotations: map[string]string{
+				pwv1alpha1.DisplayNameAnnotation:    "Sample Project",
+				pwv1alpha1.CreatedByAnnotation:      "creator@example.com",
+				pwv1alpha1.ChargingTargetAnnotation: "cc-1234",
+			},
+		},
+		Spec: pwv1alpha1.ProjectSpec{
+			Members: []pwv1alpha1.ProjectMember{
+				{
+					Subject: pwv1alpha1.Subject{Kind: rbacv1.UserKind, Name: "admin@example.com"},
+					Roles:   []pwv1alpha1.ProjectMemberRole{pwv1alpha1.ProjectRoleAdmin},
+				},
+			},
+		},
+		Status: pwv1alpha1.ProjectStatus{
+			Namespace: "project-sample",
+		},
+	}
+	inheriting := &pwv1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "inheriting",
+			Namespace: "project-sample",
+		},
+		Spec: pwv1alpha1.WorkspaceSpec{
+			Members: []pwv1alpha1.WorkspaceMember{
+				{
+					Subject: pwv1alpha1.Subject{Kind: rbacv1.GroupKind, Name: "devs"},
+					Roles:   []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleView},
+				},
+			},
+			InheritProjectMembers: &pwv1alpha1.InheritProjectMembers{
+				Enabled: true,
+			},
+		},
+	}
+	charged := &pwv1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "charged",
+			Namespace: "project-sample",
+			Annotations: map[string]string{
+				pwv1alpha1.ChargingTargetAnnotation: "cc-5678",
+			},
+		},
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(install.InstallOperatorAPIsOnboarding(runtime.NewScheme())).
+		WithObjects(project, inheriting, charged).
+		Build()
+
+	report, err := export.Collect(context.Background(), c)
+	require.NoError(t, err)
+	return report
+}
+
+func TestCollect(t *testing.T) {
+	report := testReport(t)
+
+	assert.Equal(t, []export.Project{
+		{
+			Name:           "sample",
+			DisplayName:    "Sample Project",
+			Namespace:      "project-sample",
+			CreatedBy:      "creator@example.com",
+			ChargingTarget: "cc-1234",
+			Members: []export.Member{
+				{Kind: rbacv1.UserKind, Name: "admin@example.com", Roles: []string{"admin"}},
+			},
+		},
+	}, report.Projects)
+
+	assert.Equal(t, []export.Workspace{
+		{
+			Name:           "charged",
+			Project:        "sample",
+			ChargingTarget: "cc-5678",
+			Members:        []export.Member{},
+		},
+		{
+			Name:                   "inheriting",
+			Project:                "sample",
+			ChargingTarget:         "cc-1234",
+			InheritsProjectMembers: true,
+			Members: []export.Member{
+				{Kind: rbacv1.GroupKind, Name: "devs", Roles: []string{"view"}},
+				{Kind: rbacv1.UserKind, Name: "admin@example.com", Roles: []string{"admin"}},
+			},
+		},
+	}, report.Workspaces)
+}
+
+func TestReport_Write(t *testing.T) {
+	report := testReport(t)
+
+	t.Run("json", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, report.Write(buf, export.FormatJSON))
+
+		decoded := &export.Report{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), decoded))
+		assert.Equal(t, report, decoded)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, report.Write(buf, export.FormatCSV))
+		assert.Equal(t, `type,project,workspace,displayName,namespace,createdBy,chargingTarget,memberKind,memberName,memberNamespace,roles
+project,sample,,Sample Project,project-sample,creator@example.com,cc-1234,User,admin@example.com,,admin
+workspace,sample,charged,,,,cc-5678,,,,
+workspace,sample,inheriting,,,,cc-1234,Group,devs,,view
+workspace,sample,inheriting,,,,cc-1234,User,admin@example.com,,admin
+`, buf.String())
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		assert.Error(t, report.Write(&bytes.Buffer{}, export.Format("yaml")))
+	})
+}
@@ -0,0 +1,139 @@
+package features
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Feature is the name of a feature gate.
+type Feature string
+
+// Stage is the maturity of a feature.
+type Stage string
+
+const (
+	// Alpha features are experimental and disabled by default.
+	Alpha Stage = "ALPHA"
+	// Beta features are enabled by default, but can still be disabled.
+	Beta Stage = "BETA"
+	// GA features are stable, their gates are kept for a while before they are removed.
+	GA Stage = "GA"
+)
+
+// FeatureSpec describes the default and the maturity of a feature.
+type FeatureSpec struct {
+	Default bool
+	Stage   Stage
+}
+
+const (
+	// FlatWorkspaces allows workspaces without a dedicated namespace, if enabled in the config or the WorkspaceClass.
+	// Disabling it does not affect existing flat workspaces.
+	FlatWorkspaces Feature = "FlatWorkspaces"
+	// CreatorAsAdmin allows the webhooks to add the creator of a project or workspace without admins as admin, if enabled in the config.
+	CreatorAsAdmin Feature = "CreatorAsAdmin"
+	// WorkspaceDeletionAdmission makes the workspace webhook reject the deletion of workspaces whose namespace still contains resources blocking the deletion.
+	WorkspaceDeletionAdmission Feature = "WorkspaceDeletionAdmission"
+)
+
+// defaultFeatures contains all known features of the platform service.
+var defaultFeatures = map[Feature]FeatureSpec{
+	FlatWorkspaces:             {Default: true, Stage: Beta},
+	CreatorAsAdmin:             {Default: true, Stage: Beta},
+	WorkspaceDeletionAdmission: {Default: true, Stage: Beta},
+}
+
+// DefaultGate is the feature gate of the platform service, which is set via the --feature-gates flag.
+var DefaultGate = NewGate(defaultFeatures)
+
+// Enabled returns true if the given feature is enabled in the DefaultGate.
+func Enabled(f Feature) bool {
+	return DefaultGate.Enabled(f)
+}
+
+// Gate holds the state of a set of known features.
+// It implements pflag.Value, so that it can be set from a comma-separated list of key=value pairs, following the conventions of Kubernetes components.
+type Gate struct {
+	known map[Feature]FeatureSpec
+
+	lock    sync.RWMutex
+	enabled map[Feature]bool
+}
+
+// NewGate creates a new Gate for the given features, which are all set to their defaults.
+func NewGate(known map[Feature]FeatureSpec) *Gate {
+	return &Gate{
+		known:   maps.Clone(known),
+		enabled: map[Feature]bool{},
+	}
+}
+
+// Enabled returns true if the given feature is enabled. Unknown features are disabled.
+func (g *Gate) Enabled(f Feature) bool {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	if enabled, ok := g.enabled[f]; ok {
+		return enabled
+	}
+	return g.known[f].Default
+}
+
+// Set parses a comma-separated list of key=value pairs, e.g. 'FlatWorkspaces=false,CreatorAsAdmin=true', and overrides the defaults of the given features.
+// Nothing is changed if any of the pairs is invalid or refers to an unknown feature.
+func (g *Gate) Set(value string) error {
+	overrides := map[Feature]bool{}
+	for pair := range strings.SplitSeq(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, raw, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("missing bool value for feature gate %s", key)
+		}
+		f := Feature(strings.TrimSpace(key))
+		if _, known := g.known[f]; !known {
+			return fmt.Errorf("unrecognized feature gate: %s", f)
+		}
+		enabled, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("invalid value of feature gate %s: %w", f, err)
+		}
+		overrides[f] = enabled
+	}
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	maps.Copy(g.enabled, overrides)
+	return nil
+}
+
+// String returns the overridden features as comma-separated list of key=value pairs, sorted by name.
+func (g *Gate) String() string {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	pairs := make([]string, 0, len(g.enabled))
+	for _, f := range slices.Sorted(maps.Keys(g.enabled)) {
+		pairs = append(pairs, fmt.Sprintf("%s=%t", f, g.enabled[f]))
+	}
+	return strings.Join(pairs, ",")
+}
+
+// Type implements pflag.Value.
+func (g *Gate) Type() string {
+	return "mapStringBool"
+}
+
+// KnownFeatures returns a description of each known feature for the help text of the flag, sorted by name.
+func (g *Gate) KnownFeatures() []string {
+	res := make([]string, 0, len(g.known))
+	for _, f := range slices.Sorted(maps.Keys(g.known)) {
+		spec := g.known[f]
+		res = append(res, fmt.Sprintf("%s=true|false (%s - default=%t)", f, spec.Stage, spec.Default))
+	}
+	return res
+}
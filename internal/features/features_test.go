@@ -0,0 +1,77 @@
+package features
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Gate_Set(t *testing.T) {
+	known := map[Feature]FeatureSpec{
+		"Experimental": {Default: false, Stage: Alpha},
+		"Preview":      {Default: true, Stage: Beta},
+	}
+
+	testCases := []struct {
+		desc     string
+		value    string
+		expected map[Feature]bool
+		wantErr  bool
+	}{
+		{
+			desc:     "should use the defaults without overrides",
+			value:    "",
+			expected: map[Feature]bool{"Experimental": false, "Preview": true},
+		},
+		{
+			desc:     "should override the defaults",
+			value:    "Experimental=true, Preview=false",
+			expected: map[Feature]bool{"Experimental": true, "Preview": false},
+		},
+		{
+			desc:     "should reject unknown features without changing anything",
+			value:    "Experimental=true,Unknown=true",
+			expected: map[Feature]bool{"Experimental": false, "Preview": true},
+			wantErr:  true,
+		},
+		{
+			desc:     "should reject invalid values",
+			value:    "Preview=maybe",
+			expected: map[Feature]bool{"Experimental": false, "Preview": true},
+			wantErr:  true,
+		},
+		{
+			desc:     "should reject features without value",
+			value:    "Preview",
+			expected: map[Feature]bool{"Experimental": false, "Preview": true},
+			wantErr:  true,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			g := NewGate(known)
+			err := g.Set(tC.value)
+			if tC.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			for f, enabled := range tC.expected {
+				assert.Equal(t, enabled, g.Enabled(f), "feature %s", f)
+			}
+			assert.False(t, g.Enabled("Unknown"))
+		})
+	}
+}
+
+func Test_Gate_String(t *testing.T) {
+	g := NewGate(defaultFeatures)
+	assert.Empty(t, g.String())
+
+	// repeated flags are merged
+	require.NoError(t, g.Set("WorkspaceDeletionAdmission=false"))
+	require.NoError(t, g.Set("FlatWorkspaces=false"))
+	assert.Equal(t, "FlatWorkspaces=false,WorkspaceDeletionAdmission=false", g.String())
+	assert.Contains(t, g.KnownFeatures(), "CreatorAsAdmin=true|false (BETA - default=true)")
+}
@@ -0,0 +1,228 @@
+package integration_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+var _ = Describe("Project and Workspace lifecycle", Ordered, func() {
+	adminMember := pwv1alpha1.Subject{
+		Kind: rbacv1.UserKind,
+		Name: adminUser,
+	}
+	viewerMember := pwv1alpha1.Subject{
+		Kind: rbacv1.UserKind,
+		Name: "viewer",
+	}
+
+	project := &pwv1alpha1.Project{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "lifecycle",
+		},
+		Spec: pwv1alpha1.ProjectSpec{
+			Members: []pwv1alpha1.ProjectMember{
+				{
+					Subject: adminMember,
+					Roles:   []pwv1alpha1.ProjectMemberRole{pwv1alpha1.ProjectRoleAdmin},
+				},
+			},
+		},
+	}
+	projectNamespace := utils.NamespaceForProject(project)
+
+	workspace := &pwv1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dev",
+			Namespace: projectNamespace,
+		},
+		Spec: pwv1alpha1.WorkspaceSpec{
+			Members: []pwv1alpha1.WorkspaceMember{
+				{
+					Subject: adminMember,
+					Roles:   []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleAdmin},
+				},
+			},
+		},
+	}
+	workspaceNamespace := utils.NamespaceForWorkspace(workspace)
+
+	blockingSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "blocking",
+			Namespace: workspaceNamespace,
+		},
+	}
+
+	It("should create namespace and RoleBindings for a new project", func() {
+		Expect(adminClient.Create(ctx, project)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			p := &pwv1alpha1.Project{}
+			g.Expect(onboardingClient.Get(ctx, client.ObjectKeyFromObject(project), p)).To(Succeed())
+			g.Expect(p.Status.Namespace).To(Equal(projectNamespace))
+			g.Expect(p.Finalizers).To(ContainElement(pwv1alpha1.GroupVersion.Group))
+		}).Should(Succeed())
+
+		ns := &corev1.Namespace{}
+		Expect(onboardingClient.Get(ctx, client.ObjectKey{Name: projectNamespace}, ns)).To(Succeed())
+		Expect(utils.IsManaged(ns, providerName, pwv1alpha1.ManagementLabelsConfig{})).To(BeTrue())
+
+		Expect(roleBindingSubjects(projectNamespace, utils.RoleBindingForRole(pwv1alpha1.ProjectRoleAdmin))).To(ConsistOf(adminMember.RbacV1()))
+	})
+
+	It("should create namespace and RoleBindings for a new workspace", func() {
+		Expect(adminClient.Create(ctx, workspace)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			ws := &pwv1alpha1.Workspace{}
+			g.Expect(onboardingClient.Get(ctx, client.ObjectKeyFromObject(workspace), ws)).To(Succeed())
+			g.Expect(ws.Status.Namespace).To(Equal(workspaceNamespace))
+		}).Should(Succeed())
+
+		ns := &corev1.Namespace{}
+		Expect(onboardingClient.Get(ctx, client.ObjectKey{Name: workspaceNamespace}, ns)).To(Succeed())
+		Expect(utils.IsManaged(ns, providerName, pwv1alpha1.ManagementLabelsConfig{})).To(BeTrue())
+
+		Expect(roleBindingSubjects(workspaceNamespace, utils.RoleBindingForRole(pwv1alpha1.WorkspaceRoleAdmin))).To(ConsistOf(adminMember.RbacV1()))
+	})
+
+	It("should update the RoleBindings when members change", func() {
+		p := &pwv1alpha1.Project{}
+		Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(project), p)).To(Succeed())
+		p.Spec.Members = append(p.Spec.Members, pwv1alpha1.ProjectMember{
+			Subject: viewerMember,
+			Roles:   []pwv1alpha1.ProjectMemberRole{pwv1alpha1.ProjectRoleView},
+		})
+		Expect(adminClient.Update(ctx, p)).To(Succeed())
+
+		ws := &pwv1alpha1.Workspace{}
+		Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(workspace), ws)).To(Succeed())
+		ws.Spec.Members = append(ws.Spec.Members, pwv1alpha1.WorkspaceMember{
+			Subject: viewerMember,
+			Roles:   []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleView},
+		})
+		Expect(adminClient.Update(ctx, ws)).To(Succeed())
+
+		Eventually(func() ([]rbacv1.Subject, error) {
+			return roleBindingSubjects(projectNamespace, utils.RoleBindingForRole(pwv1alpha1.ProjectRoleView))
+		}).Should(ConsistOf(viewerMember.RbacV1()))
+		Eventually(func() ([]rbacv1.Subject, error) {
+			return roleBindingSubjects(workspaceNamespace, utils.RoleBindingForRole(pwv1alpha1.WorkspaceRoleView))
+		}).Should(ConsistOf(viewerMember.RbacV1()))
+		Expect(roleBindingSubjects(projectNamespace, utils.RoleBindingForRole(pwv1alpha1.ProjectRoleAdmin))).To(ConsistOf(adminMember.RbacV1()))
+	})
+
+	It("should reject member changes by users without admin access", func() {
+		p := &pwv1alpha1.Project{}
+		Expect(intruderClient.Get(ctx, client.ObjectKeyFromObject(project), p)).To(Succeed())
+		p.Spec.Members = append(p.Spec.Members, pwv1alpha1.ProjectMember{
+			Subject: pwv1alpha1.Subject{
+				Kind: rbacv1.UserKind,
+				Name: intruderUser,
+			},
+			Roles: []pwv1alpha1.ProjectMemberRole{pwv1alpha1.ProjectRoleAdmin},
+		})
+		Expect(intruderClient.Update(ctx, p)).To(MatchError(ContainSubstring(intruderUser)))
+
+		// admins must not be able to lock themselves out
+		Expect(adminClient.Get(ctx, client.ObjectKeyFromObject(project), p)).To(Succeed())
+		p.Spec.Members = p.Spec.Members[1:]
+		Expect(adminClient.Update(ctx, p)).NotTo(Succeed())
+	})
+
+	It("should block workspace deletion while blocking resources remain", func() {
+		Expect(onboardingClient.Create(ctx, blockingSecret)).To(Succeed())
+		Expect(adminClient.Delete(ctx, workspace)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			ws := &pwv1alpha1.Workspace{}
+			g.Expect(onboardingClient.Get(ctx, client.ObjectKeyFromObject(workspace), ws)).To(Succeed())
+			condition := contentRemainingCondition(ws.Status.Conditions)
+			g.Expect(condition).NotTo(BeNil())
+			g.Expect(condition.Message).To(ContainSubstring("ProjectWorkspaceConfig: 1"))
+
+			var details pwv1alpha1.RemainingContentDetails
+			g.Expect(json.Unmarshal(condition.Details, &details)).To(Succeed())
+			g.Expect(details.Count).To(Equal(1))
+			g.Expect(details.Resources).To(ConsistOf(pwv1alpha1.RemainingContentResource{
+				APIGroup:  "v1",
+				Kind:      "Secret",
+				Name:      blockingSecret.Name,
+				Namespace: workspaceNamespace,
+				Source:    pwv1alpha1.SourceProjectWorkspaceConfig,
+			}))
+		}).Should(Succeed())
+
+		// ServiceProviders are signaled via the namespace, which must not be deleted yet
+		ns := &corev1.Namespace{}
+		Expect(onboardingClient.Get(ctx, client.ObjectKey{Name: workspaceNamespace}, ns)).To(Succeed())
+		Expect(ns.DeletionTimestamp.IsZero()).To(BeTrue())
+		Expect(ns.Annotations).To(HaveKeyWithValue(pwv1alpha1.DeletionRequestedAnnotation, "true"))
+	})
+
+	It("should block project deletion while workspaces remain", func() {
+		Expect(adminClient.Delete(ctx, project)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			p := &pwv1alpha1.Project{}
+			g.Expect(onboardingClient.Get(ctx, client.ObjectKeyFromObject(project), p)).To(Succeed())
+			condition := contentRemainingCondition(p.Status.Conditions)
+			g.Expect(condition).NotTo(BeNil())
+			g.Expect(condition.Message).To(ContainSubstring("Builtin: 1"))
+		}).Should(Succeed())
+
+		ns := &corev1.Namespace{}
+		Expect(onboardingClient.Get(ctx, client.ObjectKey{Name: projectNamespace}, ns)).To(Succeed())
+		Expect(ns.DeletionTimestamp.IsZero()).To(BeTrue())
+	})
+
+	It("should finish deletion once the blocking resources are gone", func() {
+		Expect(onboardingClient.Delete(ctx, blockingSecret)).To(Succeed())
+
+		Eventually(func() error {
+			return finalizeNamespace(workspaceNamespace)
+		}).Should(Succeed())
+		Eventually(func() bool {
+			err := onboardingClient.Get(ctx, client.ObjectKeyFromObject(workspace), &pwv1alpha1.Workspace{})
+			return apierrors.IsNotFound(err)
+		}).Should(BeTrue(), "workspace should have been deleted")
+
+		Eventually(func() error {
+			return finalizeNamespace(projectNamespace)
+		}).Should(Succeed())
+		Eventually(func() bool {
+			err := onboardingClient.Get(ctx, client.ObjectKeyFromObject(project), &pwv1alpha1.Project{})
+			return apierrors.IsNotFound(err)
+		}).Should(BeTrue(), "project should have been deleted")
+	})
+})
+
+// roleBindingSubjects returns the subjects of the RoleBinding with the given name in the given namespace.
+func roleBindingSubjects(namespace, name string) ([]rbacv1.Subject, error) {
+	rb := &rbacv1.RoleBinding{}
+	if err := onboardingClient.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, rb); err != nil {
+		return nil, err
+	}
+	return rb.Subjects, nil
+}
+
+// contentRemainingCondition returns the ContentRemaining condition from the given list, or nil if there is none.
+func contentRemainingCondition(conditions []pwv1alpha1.Condition) *pwv1alpha1.Condition {
+	for i := range conditions {
+		if conditions[i].Type == pwv1alpha1.ConditionTypeContentRemaining && conditions[i].Status == pwv1alpha1.ConditionStatusTrue {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,302 @@
+package integration_test
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/openmcp-project/controller-utils/pkg/clusters"
+	envtestutil "github.com/openmcp-project/controller-utils/pkg/envtest"
+	clustersv1alpha1 "github.com/openmcp-project/openmcp-operator/api/clusters/v1alpha1"
+	commonapi "github.com/openmcp-project/openmcp-operator/api/common"
+	openmcpcrds "github.com/openmcp-project/openmcp-operator/api/crds"
+	"github.com/openmcp-project/openmcp-operator/lib/clusteraccess/advanced"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	pwcrds "github.com/openmcp-project/platform-service-project-workspace/api/v2/crds"
+	"github.com/openmcp-project/platform-service-project-workspace/api/v2/install"
+	sharedconfig "github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/core"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/webhookconfig"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/webhooks"
+)
+
+// This suite runs the ProjectWorkspaceConfig controller, the Project and Workspace controllers and the webhooks together.
+// The onboarding cluster is an envtest control plane, the platform cluster is a fake client,
+// since it only holds the ProjectWorkspaceConfig and the AccessRequests, whose readiness is faked.
+
+const (
+	platformClusterID   = "platform"
+	onboardingClusterID = "onboarding"
+
+	providerName = "project-workspace"
+	podNamespace = "openmcp-system"
+
+	// adminUser is the end-user which creates the projects and workspaces in the tests
+	adminUser = "admin"
+	// intruderUser is an end-user which is not a member of any project or workspace
+	intruderUser = "intruder"
+)
+
+var (
+	ctx     context.Context
+	cancel  context.CancelFunc
+	testEnv *envtest.Environment
+	restCfg *rest.Config
+
+	// onboardingClient uses the identity of the platform service itself, which is excluded from webhook validation
+	onboardingClient client.Client
+	adminClient      client.Client
+	intruderClient   client.Client
+	clientset        kubernetes.Interface
+
+	platformClient client.Client
+	cfgCtrl        *sharedconfig.PWOConfigController
+)
+
+func TestIntegration(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Integration Suite")
+}
+
+var _ = BeforeSuite(func() {
+	logf.SetLogger(zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true)))
+	SetDefaultEventuallyTimeout(time.Minute)
+	SetDefaultEventuallyPollingInterval(250 * time.Millisecond)
+
+	ctx, cancel = context.WithCancel(logf.IntoContext(context.TODO(), logf.Log))
+
+	By("bootstrapping the onboarding cluster")
+	Expect(envtestutil.Install("latest")).To(Succeed())
+
+	crds, err := pwcrds.CRDs()
+	Expect(err).NotTo(HaveOccurred())
+	// the MCP CRD is required because ManagedControlPlaneV2 resources are blocking workspace deletion by default
+	openmcpCRDs, err := openmcpcrds.CRDs()
+	Expect(err).NotTo(HaveOccurred())
+
+	testEnv = &envtest.Environment{
+		CRDs: append(crds, openmcpCRDs...),
+		WebhookInstallOptions: envtest.WebhookInstallOptions{
+			Paths: []string{filepath.Join("..", "..", "config", "webhook")},
+		},
+	}
+	restCfg, err = testEnv.Start()
+	Expect(err).NotTo(HaveOccurred())
+	Expect(restCfg).NotTo(BeNil())
+
+	onboardingScheme := install.InstallOperatorAPIsOnboarding(runtime.NewScheme())
+	onboardingCluster := clusters.New(onboardingClusterID).WithRESTConfig(restCfg)
+	Expect(onboardingCluster.InitializeClient(onboardingScheme)).To(Succeed())
+	onboardingClient = onboardingCluster.Client()
+
+	clientset, err = kubernetes.NewForConfig(restCfg)
+	Expect(err).NotTo(HaveOccurred())
+
+	adminClient = impersonate(adminUser, onboardingScheme)
+	intruderClient = impersonate(intruderUser, onboardingScheme)
+	grantEndUserAccess()
+
+	// figure out own identity, the same way the platform service does it
+	identity, err := webhooks.NewOwnIdentity(ctx, onboardingClient, "")
+	Expect(err).NotTo(HaveOccurred())
+
+	By("setting up the platform cluster")
+	platformClient = fake.NewClientBuilder().
+		WithScheme(install.InstallOperatorAPIsPlatform(runtime.NewScheme())).
+		WithObjects(
+			projectWorkspaceConfig(),
+			&clustersv1alpha1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "onboarding",
+					Namespace: "default",
+				},
+			},
+		).
+		WithStatusSubresource(&clustersv1alpha1.AccessRequest{}).
+		Build()
+
+	By("loading the ProjectWorkspaceConfig")
+	cfgCtrl, err = sharedconfig.NewPWConfigController(providerName, clusters.NewTestClusterFromClient(platformClusterID, platformClient), onboardingCluster, &commonapi.ObjectReference{Name: "onboarding", Namespace: "default"}, nil, podNamespace)
+	Expect(err).NotTo(HaveOccurred())
+	cfgCtrl.Car.WithFakingCallback(advanced.FakingCallback_WaitingForAccessRequestReadiness, advanced.FakeAccessRequestReadiness())
+	cfgCtrl.Car.WithFakingCallback(advanced.FakingCallback_WaitingForAccessRequestDeletion, advanced.FakeAccessRequestDeletion([]string{"clusterprovider"}, nil))
+	cfgCtrl.Car.WithFakeClientGenerator(func(_ context.Context, _ []byte, _ *runtime.Scheme, _ ...any) (client.Client, error) {
+		// the dynamic onboarding cluster access is the same cluster, just with different permissions
+		return onboardingClient, nil
+	})
+	// the platform cluster is faked and has no cache to watch, so the config is reconciled explicitly instead of via the manager
+	reconcileConfig()
+
+	By("starting the controllers and webhooks")
+	webhookInstallOptions := &testEnv.WebhookInstallOptions
+	mgr, err := ctrl.NewManager(restCfg, ctrl.Options{
+		Scheme: onboardingScheme,
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Host:    webhookInstallOptions.LocalServingHost,
+			Port:    webhookInstallOptions.LocalServingPort,
+			CertDir: webhookInstallOptions.LocalServingCertDir,
+		}),
+		LeaderElection: false,
+		Metrics:        metricsserver.Options{BindAddress: "0"},
+	})
+	Expect(err).NotTo(HaveOccurred())
+
+	Expect(webhooks.SetupProjectWebhookWithManager(ctx, mgr, identity, cfgCtrl, nil, webhookconfig.Scope{})).To(Succeed())
+	Expect(webhooks.SetupWorkspaceWebhookWithManager(ctx, mgr, identity, cfgCtrl, nil, webhookconfig.Scope{})).To(Succeed())
+
+	commonReconciler := core.NewCommonReconciler(cfgCtrl, providerName)
+	pr, err := core.NewProjectReconciler(mgr.GetScheme(), commonReconciler)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(pr.SetupWithManager(mgr)).To(Succeed())
+	wr, err := core.NewWorkspaceReconciler(mgr.GetScheme(), commonReconciler)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(wr.SetupWithManager(mgr)).To(Succeed())
+
+	go func() {
+		defer GinkgoRecover()
+		Expect(mgr.Start(ctx)).To(Succeed())
+	}()
+
+	// wait for the webhook server to get ready
+	dialer := &net.Dialer{Timeout: time.Second}
+	addrPort := fmt.Sprintf("%s:%d", webhookInstallOptions.LocalServingHost, webhookInstallOptions.LocalServingPort)
+	Eventually(func() error {
+		conn, err := tls.DialWithDialer(dialer, "tcp", addrPort, &tls.Config{InsecureSkipVerify: true})
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}).Should(Succeed())
+})
+
+var _ = AfterSuite(func() {
+	if cancel != nil {
+		cancel()
+	}
+	By("tearing down the test environment")
+	Expect(testEnv.Stop()).To(Succeed())
+})
+
+// projectWorkspaceConfig returns the config used throughout the suite.
+// Secrets are configured to block workspace deletion, so tests can easily create blocking resources.
+func projectWorkspaceConfig() *pwv1alpha1.ProjectWorkspaceConfig {
+	return &pwv1alpha1.ProjectWorkspaceConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: providerName,
+		},
+		Spec: pwv1alpha1.ProjectWorkspaceConfigSpec{
+			Workspace: pwv1alpha1.WorkspaceConfig{
+				ResourcesBlockingDeletion: []metav1.GroupVersionKind{
+					{
+						Version: "v1",
+						Kind:    "Secret",
+					},
+				},
+			},
+		},
+	}
+}
+
+// reconcileConfig reconciles the ProjectWorkspaceConfig until the dynamic onboarding cluster access is available.
+func reconcileConfig() {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: providerName}}
+	EventuallyWithOffset(1, func() (time.Duration, error) {
+		rr, err := cfgCtrl.Reconcile(ctx, req)
+		return rr.RequeueAfter, err
+	}).Should(BeZero())
+}
+
+// impersonate returns a client for the onboarding cluster which acts as the given authenticated user.
+func impersonate(userName string, scheme *runtime.Scheme) client.Client {
+	cfg := rest.CopyConfig(restCfg)
+	cfg.Impersonate = rest.ImpersonationConfig{
+		UserName: userName,
+		Groups:   []string{"system:authenticated"},
+	}
+
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	return c
+}
+
+// grantEndUserAccess allows the end-users to manage projects and workspaces,
+// so that the requests are actually evaluated by the webhooks instead of being rejected by RBAC.
+func grantEndUserAccess() {
+	clusterRole := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "integration:end-user",
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{pwv1alpha1.GroupVersion.Group},
+				Resources: []string{"projects", "workspaces"},
+				Verbs:     []string{"*"},
+			},
+		},
+	}
+	ExpectWithOffset(1, onboardingClient.Create(ctx, clusterRole)).To(Succeed())
+
+	clusterRoleBinding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "integration:end-user",
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     clusterRole.Name,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				APIGroup: rbacv1.GroupName,
+				Kind:     rbacv1.UserKind,
+				Name:     adminUser,
+			},
+			{
+				APIGroup: rbacv1.GroupName,
+				Kind:     rbacv1.UserKind,
+				Name:     intruderUser,
+			},
+		},
+	}
+	ExpectWithOffset(1, onboardingClient.Create(ctx, clusterRoleBinding)).To(Succeed())
+}
+
+// finalizeNamespace emulates the namespace controller, which is not running in envtest.
+// Namespaces in deletion would otherwise stay in the 'Terminating' phase forever.
+func finalizeNamespace(name string) error {
+	ns, err := clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if ns.DeletionTimestamp.IsZero() {
+		return fmt.Errorf("namespace '%s' is not in deletion", name)
+	}
+	ns.Spec.Finalizers = nil
+	_, err = clientset.CoreV1().Namespaces().Finalize(ctx, ns, metav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,151 @@
+// Package logconfig configures the loggers of the platform service, so that the log levels of individual controllers and webhooks can be changed at runtime
+// and all logs of a reconciliation carry the same context.
+package logconfig
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+)
+
+const (
+	// ControllerKey is the key of the controller name in the logs, as used by controller-runtime.
+	ControllerKey = "controller"
+	// ObjectKey is the key of the reconciled or validated object in the logs, as used by the controller-runtime webhooks.
+	ObjectKey = "object"
+	// NamespaceKey is the key of the namespace of the reconciled or validated object in the logs.
+	NamespaceKey = "namespace"
+	// NameKey is the key of the name of the reconciled or validated object in the logs.
+	NameKey = "name"
+	// EnvironmentKey is the key of the environment of the platform service in the logs.
+	EnvironmentKey = "environment"
+	// ProviderNameKey is the key of the provider name of the platform service in the logs.
+	ProviderNameKey = "providerName"
+)
+
+// Levels contains the log levels which are configured in the ProjectWorkspaceConfig.
+// Loggers returned by Wrap look up the level of their controller for each log line, so that changes take effect immediately.
+// The levels can only reduce the verbosity of the wrapped logger, which is configured via the '--verbosity' flag.
+type Levels struct {
+	lock sync.RWMutex
+	// defaultLevel is the zero value if no default level is configured.
+	defaultLevel logging.LogLevel
+	controllers  map[string]logging.LogLevel
+}
+
+// NewLevels returns Levels without any configured level, which do not filter any logs.
+func NewLevels() *Levels {
+	return &Levels{}
+}
+
+// Apply replaces the configured levels with the ones from the given config. A nil config removes all levels.
+func (l *Levels) Apply(cfg *pwv1alpha1.LoggingConfig) error {
+	var defaultLevel logging.LogLevel
+	controllers := map[string]logging.LogLevel{}
+	if cfg != nil {
+		if cfg.DefaultLevel != "" {
+			level, err := logging.ParseLogLevel(string(cfg.DefaultLevel))
+			if err != nil {
+				return fmt.Errorf("invalid default log level: %w", err)
+			}
+			defaultLevel = level
+		}
+		for name, raw := range cfg.Controllers {
+			level, err := logging.ParseLogLevel(string(raw))
+			if err != nil {
+				return fmt.Errorf("invalid log level for controller '%s': %w", name, err)
+			}
+			controllers[name] = level
+		}
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.defaultLevel = defaultLevel
+	l.controllers = controllers
+	return nil
+}
+
+// enabled returns whether logs with the given logr verbosity are enabled for a logger with the given controller names.
+// Later names are more specific, the level of the last name with a configured level is used, the default level otherwise.
+func (l *Levels) enabled(names []string, verbosity int) bool {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	level := l.defaultLevel
+	for _, name := range names {
+		if configured, ok := l.controllers[name]; ok {
+			level = configured
+		}
+	}
+	var unset logging.LogLevel
+	if level == unset {
+		return true
+	}
+	return verbosity <= logging.LevelToVerbosity(level)
+}
+
+// Wrap returns a logger which drops all logs that are not enabled by the configured levels.
+// The controller of a logger is determined by its names and by the values added with the ControllerKey.
+func (l *Levels) Wrap(log logging.Logger) logging.Logger {
+	return logging.Wrap(log.Logr().WithSink(levelFilter{LogSink: log.Logr().GetSink(), levels: l}))
+}
+
+// levelFilter is a logr.LogSink which disables all logs that are not enabled for the controllers of the logger.
+// Only Enabled is overwritten, logr checks it before each Info call, so that the caller information of the wrapped sink stays correct.
+// Errors are always logged.
+type levelFilter struct {
+	logr.LogSink
+	levels *Levels
+	names  []string
+}
+
+var _ logr.LogSink = levelFilter{}
+
+func (f levelFilter) Enabled(level int) bool {
+	return f.levels.enabled(f.names, level) && f.LogSink.Enabled(level)
+}
+
+func (f levelFilter) WithName(name string) logr.LogSink {
+	return f.with(f.LogSink.WithName(name), name)
+}
+
+func (f levelFilter) WithValues(keysAndValues ...any) logr.LogSink {
+	var names []string
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		if keysAndValues[i] != ControllerKey {
+			continue
+		}
+		if name, ok := keysAndValues[i+1].(string); ok {
+			names = append(names, name)
+		}
+	}
+	return f.with(f.LogSink.WithValues(keysAndValues...), names...)
+}
+
+func (f levelFilter) with(sink logr.LogSink, names ...string) levelFilter {
+	return levelFilter{
+		LogSink: sink,
+		levels:  f.levels,
+		names:   append(slices.Clip(f.names), names...),
+	}
+}
+
+// LogConstructor returns a function which creates the logger for each reconciliation of the controller with the given name.
+// All logs of a reconciliation contain the controller and the reconciled object, with the same keys as the logs of the webhooks.
+func LogConstructor(log logr.Logger, controllerName string) func(*reconcile.Request) logr.Logger {
+	log = log.WithValues(ControllerKey, controllerName)
+	return func(req *reconcile.Request) logr.Logger {
+		if req == nil {
+			return log
+		}
+		return log.WithValues(ObjectKey, klog.KRef(req.Namespace, req.Name), NamespaceKey, req.Namespace, NameKey, req.Name)
+	}
+}
@@ -0,0 +1,134 @@
+package logconfig
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+)
+
+// newTestLogger returns a logger with debug verbosity which appends the prefix and message of each log line to the returned slice.
+func newTestLogger() (logging.Logger, *[]string) {
+	lines := &[]string{}
+	log := funcr.New(func(prefix, args string) {
+		*lines = append(*lines, prefix+" "+args)
+	}, funcr.Options{Verbosity: 1})
+	return logging.Wrap(log), lines
+}
+
+func Test_Levels(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		cfg      *pwv1alpha1.LoggingConfig
+		log      func(log logging.Logger)
+		expected int
+	}{
+		{
+			desc: "should not filter anything without config",
+			log: func(log logging.Logger) {
+				log.WithName("project").Debug("debug")
+				log.WithName("workspace").Info("info")
+			},
+			expected: 2,
+		},
+		{
+			desc: "should filter with the default level",
+			cfg:  &pwv1alpha1.LoggingConfig{DefaultLevel: pwv1alpha1.LogLevelInfo},
+			log: func(log logging.Logger) {
+				log.WithName("project").Debug("debug")
+				log.WithName("project").Info("info")
+			},
+			expected: 1,
+		},
+		{
+			desc: "should prefer the level of the controller over the default level",
+			cfg: &pwv1alpha1.LoggingConfig{
+				DefaultLevel: pwv1alpha1.LogLevelInfo,
+				Controllers:  map[string]pwv1alpha1.LogLevel{"project": pwv1alpha1.LogLevelDebug},
+			},
+			log: func(log logging.Logger) {
+				log.WithName("project").Debug("debug")
+				log.WithName("workspace").Debug("debug")
+			},
+			expected: 1,
+		},
+		{
+			desc: "should detect the controller from the logger values",
+			cfg: &pwv1alpha1.LoggingConfig{
+				Controllers: map[string]pwv1alpha1.LogLevel{"workspace": pwv1alpha1.LogLevelError},
+			},
+			log: func(log logging.Logger) {
+				wlog := log.WithValues(ControllerKey, "workspace")
+				wlog.Info("info")
+				wlog.Error(nil, "error")
+				log.WithValues(ControllerKey, "project").Info("info")
+			},
+			expected: 2,
+		},
+		{
+			desc: "should use the most specific controller name",
+			cfg: &pwv1alpha1.LoggingConfig{
+				Controllers: map[string]pwv1alpha1.LogLevel{
+					"projectworkspaceconfig": pwv1alpha1.LogLevelError,
+					"pw-config":              pwv1alpha1.LogLevelDebug,
+				},
+			},
+			log: func(log logging.Logger) {
+				log.WithValues(ControllerKey, "projectworkspaceconfig").WithName("pw-config").Debug("debug")
+				log.WithValues(ControllerKey, "projectworkspaceconfig").Info("info")
+			},
+			expected: 1,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			levels := NewLevels()
+			require.NoError(t, levels.Apply(tC.cfg))
+			log, lines := newTestLogger()
+			tC.log(levels.Wrap(log))
+			assert.Len(t, *lines, tC.expected, "logged lines: %v", *lines)
+		})
+	}
+}
+
+func Test_Levels_Apply(t *testing.T) {
+	levels := NewLevels()
+	log, lines := newTestLogger()
+	plog := levels.Wrap(log).WithName("project")
+
+	require.NoError(t, levels.Apply(&pwv1alpha1.LoggingConfig{DefaultLevel: pwv1alpha1.LogLevelError}))
+	plog.Info("info")
+	assert.Empty(t, *lines)
+
+	// existing loggers pick up changed levels
+	require.NoError(t, levels.Apply(nil))
+	plog.Info("info")
+	assert.Len(t, *lines, 1)
+
+	assert.Error(t, levels.Apply(&pwv1alpha1.LoggingConfig{Controllers: map[string]pwv1alpha1.LogLevel{"project": "verbose"}}))
+}
+
+func Test_LogConstructor(t *testing.T) {
+	var line string
+	log := funcr.New(func(_, args string) { line = args }, funcr.Options{})
+	constructor := LogConstructor(log, "project")
+
+	constructor(nil).Info("setup")
+	assert.Contains(t, line, `"controller"="project"`)
+	assert.NotContains(t, line, `"object"`)
+
+	req := &reconcile.Request{}
+	req.Name = "sample"
+	req.Namespace = "project-sample"
+	constructor(req).Info("reconcile")
+	assert.Contains(t, line, `"controller"="project"`)
+	assert.Contains(t, line, `"object"=`)
+	assert.Contains(t, line, `"namespace"="project-sample"`)
+	assert.Contains(t, line, `"name"="sample"`)
+}
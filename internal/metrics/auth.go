@@ -0,0 +1,133 @@
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+)
+
+// AuthMode determines against what requests to the secured metrics endpoint are authenticated and authorized.
+type AuthMode string
+
+const (
+	// AuthModeOnboarding authenticates and authorizes requests via TokenReviews and SubjectAccessReviews on the onboarding cluster,
+	// which the manager runs against.
+	AuthModeOnboarding AuthMode = "onboarding"
+	// AuthModePlatform authenticates and authorizes requests via TokenReviews and SubjectAccessReviews on the platform cluster,
+	// so that the Prometheus of the platform cluster can scrape the metrics without permissions on the onboarding cluster.
+	AuthModePlatform AuthMode = "platform"
+	// AuthModeToken only accepts requests whose bearer token is contained in a token file, without involving any cluster.
+	AuthModeToken AuthMode = "token"
+)
+
+// AuthModes returns all supported auth modes.
+func AuthModes() []AuthMode {
+	return []AuthMode{AuthModeOnboarding, AuthModePlatform, AuthModeToken}
+}
+
+// FilterProvider is the signature of the metricsserver.Options.FilterProvider.
+// It is called with the REST config and HTTP client of the manager, i.e. of the onboarding cluster.
+type FilterProvider func(cfg *rest.Config, httpClient *http.Client) (metricsserver.Filter, error)
+
+// AuthFilterProvider returns the filter provider which protects the metrics endpoint according to the given mode.
+// platformConfig is required for AuthModePlatform, tokenFile for AuthModeToken.
+func AuthFilterProvider(mode AuthMode, platformConfig *rest.Config, tokenFile string) (FilterProvider, error) {
+	switch mode {
+	case AuthModeOnboarding:
+		return filters.WithAuthenticationAndAuthorization, nil
+	case AuthModePlatform:
+		if platformConfig == nil {
+			return nil, fmt.Errorf("metrics auth mode '%s' requires the platform cluster config", mode)
+		}
+		return func(_ *rest.Config, _ *http.Client) (metricsserver.Filter, error) {
+			httpClient, err := rest.HTTPClientFor(platformConfig)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create HTTP client for platform cluster: %w", err)
+			}
+			return filters.WithAuthenticationAndAuthorization(platformConfig, httpClient)
+		}, nil
+	case AuthModeToken:
+		if tokenFile == "" {
+			return nil, fmt.Errorf("metrics auth mode '%s' requires a token file", mode)
+		}
+		if _, err := readTokens(tokenFile); err != nil {
+			return nil, err
+		}
+		return func(_ *rest.Config, _ *http.Client) (metricsserver.Filter, error) {
+			return WithTokenAllowlist(tokenFile), nil
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown metrics auth mode '%s', must be one of %v", mode, AuthModes())
+	}
+}
+
+// WithTokenAllowlist returns a filter which only accepts requests with a bearer token from the given file.
+// The file contains one token per line, empty lines and lines starting with '#' are ignored.
+// It is read for each request, so that rotated tokens take effect without a restart.
+func WithTokenAllowlist(tokenFile string) metricsserver.Filter {
+	return func(log logr.Logger, handler http.Handler) (http.Handler, error) {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+			if !ok || token == "" {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			allowed, err := readTokens(tokenFile)
+			if err != nil {
+				log.Error(err, "Failed to read metrics token file")
+				http.Error(w, "Authorization failed", http.StatusInternalServerError)
+				return
+			}
+			if !containsToken(allowed, token) {
+				log.V(4).Info("Metrics token not allowed")
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+			handler.ServeHTTP(w, req)
+		}), nil
+	}
+}
+
+// readTokens reads the allowed tokens from the given file.
+func readTokens(tokenFile string) ([][]byte, error) {
+	data, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metrics token file '%s': %w", tokenFile, err)
+	}
+	var tokens [][]byte
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		tokens = append(tokens, bytes.Clone(line))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to parse metrics token file '%s': %w", tokenFile, err)
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("metrics token file '%s' does not contain any token", tokenFile)
+	}
+	return tokens, nil
+}
+
+// containsToken compares the given token with all allowed ones in constant time.
+func containsToken(allowed [][]byte, token string) bool {
+	found := false
+	for _, a := range allowed {
+		if subtle.ConstantTimeCompare(a, []byte(token)) == 1 {
+			found = true
+		}
+	}
+	return found
+}
@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+)
+
+func Test_WithTokenAllowlist(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "tokens")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("# platform prometheus\nfirst-token\n\n  second-token  \n"), 0o600))
+
+	handler, err := WithTokenAllowlist(tokenFile)(logr.Discard(), http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc          string
+		authorization string
+		expected      int
+	}{
+		{desc: "allowed token", authorization: "Bearer first-token", expected: http.StatusOK},
+		{desc: "allowed token with surrounding whitespace in the file", authorization: "Bearer second-token", expected: http.StatusOK},
+		{desc: "unknown token", authorization: "Bearer other-token", expected: http.StatusForbidden},
+		{desc: "comment is no token", authorization: "Bearer # platform prometheus", expected: http.StatusForbidden},
+		{desc: "missing token", expected: http.StatusUnauthorized},
+		{desc: "basic auth", authorization: "Basic Zmlyc3QtdG9rZW4=", expected: http.StatusUnauthorized},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expected, rec.Code)
+		})
+	}
+
+	t.Run("should pick up rotated tokens", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(tokenFile, []byte("rotated-token\n"), 0o600))
+
+		for token, expected := range map[string]int{"rotated-token": http.StatusOK, "first-token": http.StatusForbidden} {
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			assert.Equal(t, expected, rec.Code, token)
+		}
+	})
+}
+
+func Test_AuthFilterProvider(t *testing.T) {
+	emptyFile := filepath.Join(t.TempDir(), "empty")
+	require.NoError(t, os.WriteFile(emptyFile, []byte("# no tokens\n"), 0o600))
+
+	testCases := []struct {
+		desc           string
+		mode           AuthMode
+		platformConfig *rest.Config
+		tokenFile      string
+		valid          bool
+	}{
+		{desc: "onboarding", mode: AuthModeOnboarding, valid: true},
+		{desc: "platform", mode: AuthModePlatform, platformConfig: &rest.Config{Host: "https://platform.example.com"}, valid: true},
+		{desc: "platform without config", mode: AuthModePlatform},
+		{desc: "token without file", mode: AuthModeToken},
+		{desc: "token with file without tokens", mode: AuthModeToken, tokenFile: emptyFile},
+		{desc: "unknown mode", mode: "none"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			provider, err := AuthFilterProvider(tc.mode, tc.platformConfig, tc.tokenFile)
+			if tc.valid {
+				assert.NoError(t, err)
+				assert.NotNil(t, provider)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package metrics
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// LastSuccessfulReconcileMetricName is the name of the LastSuccessfulReconcile metric.
+	LastSuccessfulReconcileMetricName = "project_workspace_last_successful_reconcile_timestamp_seconds"
+
+	RBACUpdateResultCreated = "created"
+	RBACUpdateResultUpdated = "updated"
+	RBACUpdateResultSkipped = "skipped"
+
+	EventSinkResultDelivered = "delivered"
+	EventSinkResultRetried   = "retried"
+	EventSinkResultDropped   = "dropped"
+
+	CacheResultHit  = "hit"
+	CacheResultMiss = "miss"
+)
+
+// RBACUpdates counts the create/update calls for RBAC resources (ClusterRoles, ClusterRoleBindings, RoleBindings),
+// partitioned by the kind of the resource and whether a write was performed or skipped because nothing changed.
+var RBACUpdates = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "project_workspace_rbac_updates_total",
+		Help: "Number of create/update operations on RBAC resources, partitioned by resource kind and result (created, updated, skipped).",
+	},
+	[]string{"kind", "result"},
+)
+
+// LastSuccessfulReconcile holds the unix timestamp of the last reconcile which did not return an error, partitioned by controller.
+// The controller label uses the same names as the controller-runtime reconcile metrics.
+var LastSuccessfulReconcile = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: LastSuccessfulReconcileMetricName,
+		Help: "Unix timestamp of the last reconcile which did not return an error, partitioned by controller.",
+	},
+	[]string{"controller"},
+)
+
+// EventSinkDeliveries counts the delivery attempts of project and workspace events to the configured event sink,
+// partitioned by the result (delivered, retried, dropped).
+var EventSinkDeliveries = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "project_workspace_event_sink_deliveries_total",
+		Help: "Number of delivery attempts of project and workspace events to the event sink, partitioned by result (delivered, retried, dropped).",
+	},
+	[]string{"result"},
+)
+
+// ReconcileErrors counts the errors returned by the reconcilers, partitioned by controller and the kind of the error
+// (Retriable, Terminal, Blocked), see the internal errors package.
+var ReconcileErrors = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "project_workspace_reconcile_errors_total",
+		Help: "Number of reconcile errors, partitioned by controller and error kind (Retriable, Terminal, Blocked).",
+	},
+	[]string{"controller", "kind"},
+)
+
+// OnboardingAccessFailures counts the failed reconciliations of the AccessRequest for the dynamic onboarding cluster access.
+var OnboardingAccessFailures = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "project_workspace_onboarding_access_failures_total",
+		Help: "Number of failed reconciliations of the AccessRequest for the dynamic onboarding cluster access.",
+	},
+)
+
+// OnboardingAccessCircuitBreakerOpen is 1 while the circuit breaker for the dynamic onboarding cluster access is open and 0 otherwise.
+var OnboardingAccessCircuitBreakerOpen = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "project_workspace_onboarding_access_circuit_breaker_open",
+		Help: "Whether the reconciliation of the AccessRequest for the dynamic onboarding cluster access is paused after repeated failures (1) or not (0).",
+	},
+)
+
+// WebhookMemberOverridesLookups counts the lookups of the MemberOverrides by the webhooks, partitioned by whether they were served from the cache (hit)
+// or from the state of the config controller, because the cache did not contain the ProjectWorkspaceConfig (miss).
+var WebhookMemberOverridesLookups = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "project_workspace_webhook_member_overrides_lookups_total",
+		Help: "Number of lookups of the member overrides by the webhooks, partitioned by result (hit if served from the cache, miss otherwise).",
+	},
+	[]string{"result"},
+)
+
+// WebhookMemberOverridesCacheLastUpdate holds the unix timestamp of the last update of the ProjectWorkspaceConfig or its override which the cache of the webhooks received.
+// The time since then is the maximum staleness of the MemberOverrides which the webhooks evaluate.
+var WebhookMemberOverridesCacheLastUpdate = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "project_workspace_webhook_member_overrides_cache_last_update_timestamp_seconds",
+		Help: "Unix timestamp of the last update of the ProjectWorkspaceConfig or its override which the member overrides cache of the webhooks received.",
+	},
+)
+
+// WebhookLookupFailures counts the lookups of the webhooks which failed after all retries or because the time budget of the request was exhausted,
+// partitioned by the check and whether the request was rejected (Fail) or the check was skipped (Ignore).
+var WebhookLookupFailures = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "project_workspace_webhook_lookup_failures_total",
+		Help: "Number of failed lookups of the webhooks, partitioned by check and failure policy.",
+	},
+	[]string{"check", "policy"},
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(RBACUpdates, LastSuccessfulReconcile, EventSinkDeliveries, ReconcileErrors, OnboardingAccessFailures, OnboardingAccessCircuitBreakerOpen,
+		WebhookMemberOverridesLookups, WebhookMemberOverridesCacheLastUpdate, WebhookLookupFailures)
+}
+
+// RecordRBACUpdate increments the RBACUpdates counter for the given object and operation result.
+// It must only be called if the create/update operation succeeded, since controllerutil.CreateOrUpdate returns OperationResultNone in case of an error.
+func RecordRBACUpdate(obj client.Object, result controllerutil.OperationResult) {
+	var res string
+	switch result {
+	case controllerutil.OperationResultNone:
+		res = RBACUpdateResultSkipped
+	case controllerutil.OperationResultCreated:
+		res = RBACUpdateResultCreated
+	case controllerutil.OperationResultUpdated, controllerutil.OperationResultUpdatedStatus, controllerutil.OperationResultUpdatedStatusOnly:
+		res = RBACUpdateResultUpdated
+	default:
+		return
+	}
+	RBACUpdates.WithLabelValues(reflect.ValueOf(obj).Elem().Type().Name(), res).Inc()
+}
+
+// ObserveReconciler wraps the given reconciler and updates the LastSuccessfulReconcile gauge of the given controller
+// whenever a reconcile finishes without an error.
+func ObserveReconciler(controller string, r reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		res, err := r.Reconcile(ctx, req)
+		if err == nil {
+			LastSuccessfulReconcile.WithLabelValues(controller).SetToCurrentTime()
+		}
+		return res, err
+	})
+}
@@ -0,0 +1,80 @@
+package projectresolver
+
+import (
+	"context"
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+var (
+	// ErrNamespaceHasNoLabels is returned if the namespace has no labels at all.
+	ErrNamespaceHasNoLabels = errors.New("namespace has no labels, map is nil")
+	// ErrNamespaceHasNoProjectLabel is returned if the namespace has labels, but no project label.
+	ErrNamespaceHasNoProjectLabel = errors.New("namespace has no project label")
+)
+
+// Resolver determines the Project a namespace belongs to, e.g. the owning Project of a Workspace, via the project label of the namespace.
+// It reads from the informer cache of the manager, which only contains the namespaces with a project label, so that bursts of lookups,
+// e.g. when all Workspaces are reconciled after a config change, do not cause requests to the API server.
+// If the namespace or the Project is not in the cache, e.g. because it has just been created, it is read from the API server instead.
+type Resolver struct {
+	cache     client.Reader
+	apiReader client.Reader
+}
+
+// New creates a Resolver which reads from the given cache and falls back to the given API reader for objects which are not in the cache.
+// cache may be nil, then all objects are read via the API reader.
+func New(cache, apiReader client.Reader) *Resolver {
+	return &Resolver{
+		cache:     cache,
+		apiReader: apiReader,
+	}
+}
+
+// ProjectName returns the name of the Project the given namespace belongs to.
+// It returns ErrNamespaceHasNoLabels or ErrNamespaceHasNoProjectLabel if the namespace does not belong to any Project,
+// and a NotFound error if the namespace does not exist.
+func (r *Resolver) ProjectName(ctx context.Context, namespaceName string) (string, error) {
+	namespace := &corev1.Namespace{}
+	if err := r.get(ctx, client.ObjectKey{Name: namespaceName}, namespace); err != nil {
+		return "", err
+	}
+	if namespace.Labels == nil {
+		return "", ErrNamespaceHasNoLabels
+	}
+	projectName := namespace.Labels[utils.LabelProject]
+	if projectName == "" {
+		return "", ErrNamespaceHasNoProjectLabel
+	}
+	return projectName, nil
+}
+
+// Project returns the Project the given namespace belongs to, the errors are the same as for ProjectName.
+// A NotFound error is returned if the Project does not exist.
+func (r *Resolver) Project(ctx context.Context, namespaceName string) (*pwv1alpha1.Project, error) {
+	projectName, err := r.ProjectName(ctx, namespaceName)
+	if err != nil {
+		return nil, err
+	}
+	project := &pwv1alpha1.Project{}
+	if err := r.get(ctx, client.ObjectKey{Name: projectName}, project); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// get reads the object from the cache, or from the API server if it is not found in the cache.
+func (r *Resolver) get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	if r.cache != nil {
+		if err := r.cache.Get(ctx, key, obj); !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return r.apiReader.Get(ctx, key, obj)
+}
@@ -0,0 +1,95 @@
+package projectresolver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/api/v2/install"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/projectresolver"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+func TestResolver(t *testing.T) {
+	scheme := install.InstallOperatorAPIsOnboarding(runtime.NewScheme())
+	projectNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "project-sample", Labels: map[string]string{utils.LabelProject: "sample"}}}
+	project := &pwv1alpha1.Project{ObjectMeta: metav1.ObjectMeta{Name: "sample"}}
+
+	testCases := []struct {
+		desc          string
+		cached        []client.Object
+		uncached      []client.Object
+		namespace     string
+		expectedName  string
+		expectedErr   error
+		expectedCalls int
+	}{
+		{
+			desc:          "should read the namespace and the project from the cache",
+			cached:        []client.Object{projectNamespace, project},
+			namespace:     "project-sample",
+			expectedName:  "sample",
+			expectedCalls: 0,
+		},
+		{
+			desc:          "should fall back to the API server for objects which are not cached yet",
+			cached:        []client.Object{projectNamespace},
+			uncached:      []client.Object{projectNamespace, project},
+			namespace:     "project-sample",
+			expectedName:  "sample",
+			expectedCalls: 1,
+		},
+		{
+			desc:          "should return an error if the namespace has no labels",
+			uncached:      []client.Object{&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}},
+			namespace:     "default",
+			expectedErr:   projectresolver.ErrNamespaceHasNoLabels,
+			expectedCalls: 1,
+		},
+		{
+			desc:          "should return an error if the namespace has no project label",
+			uncached:      []client.Object{&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", Labels: map[string]string{"foo": "bar"}}}},
+			namespace:     "default",
+			expectedErr:   projectresolver.ErrNamespaceHasNoProjectLabel,
+			expectedCalls: 1,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			ctx := context.Background()
+			cache := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tC.cached...).Build()
+			calls := 0
+			apiReader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tC.uncached...).WithInterceptorFuncs(interceptor.Funcs{
+				Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					calls++
+					return c.Get(ctx, key, obj, opts...)
+				},
+			}).Build()
+
+			actual, err := projectresolver.New(cache, apiReader).Project(ctx, tC.namespace)
+			assert.Equal(t, tC.expectedCalls, calls, "unexpected number of requests to the API server")
+			if tC.expectedErr != nil {
+				assert.ErrorIs(t, err, tC.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tC.expectedName, actual.Name)
+		})
+	}
+
+	t.Run("should return a NotFound error if the namespace does not exist", func(t *testing.T) {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+		_, err := projectresolver.New(nil, c).ProjectName(context.Background(), "missing")
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+}
@@ -0,0 +1,135 @@
+// Package restconfig adjusts the REST configs of the onboarding cluster for networks in which the cluster can only be reached
+// via a proxy, e.g. corporate proxies which intercept TLS with a custom CA.
+package restconfig
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"k8s.io/client-go/rest"
+
+	"github.com/openmcp-project/controller-utils/pkg/clusters"
+)
+
+// ConnectionOptions are additional connection settings for a cluster, which are applied on top of the REST config from its kubeconfig.
+// The zero value does not change anything.
+type ConnectionOptions struct {
+	// ProxyURL is the URL of the proxy through which the cluster is reached, e.g. 'http://proxy.example.com:3128'.
+	ProxyURL string `json:"proxy-url"`
+	// CABundleFile is the path of a file containing PEM encoded CA certificates, which are trusted in addition to the CA from the kubeconfig.
+	// If the kubeconfig does not contain a CA, the system roots are not used anymore, so the bundle has to contain all required CAs.
+	// The file is read whenever the options are applied, so that a rotated bundle is picked up with new cluster accesses.
+	CABundleFile string `json:"ca-bundle-file"`
+	// TLSServerName overrides the server name which is used to verify the certificate of the API server,
+	// e.g. if the API server is reached via an address which is not part of its certificate.
+	TLSServerName string `json:"tls-server-name"`
+}
+
+// IsEmpty returns true if no connection setting is configured.
+func (o ConnectionOptions) IsEmpty() bool {
+	return o.ProxyURL == "" && o.CABundleFile == "" && o.TLSServerName == ""
+}
+
+// Validate checks that the proxy URL is valid and that the CA bundle can be read.
+func (o ConnectionOptions) Validate() error {
+	if _, err := o.proxyURL(); err != nil {
+		return err
+	}
+	if _, err := o.caBundle(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Apply returns a copy of the given REST config with the connection settings applied.
+func (o ConnectionOptions) Apply(cfg *rest.Config) (*rest.Config, error) {
+	res := rest.CopyConfig(cfg)
+	if o.IsEmpty() {
+		return res, nil
+	}
+
+	proxyURL, err := o.proxyURL()
+	if err != nil {
+		return nil, err
+	}
+	if proxyURL != nil {
+		res.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	bundle, err := o.caBundle()
+	if err != nil {
+		return nil, err
+	}
+	if len(bundle) > 0 {
+		// the CA from the kubeconfig may be referenced by path, it has to be loaded to be merged with the bundle
+		if err := rest.LoadTLSFiles(res); err != nil {
+			return nil, fmt.Errorf("failed to load TLS files of REST config: %w", err)
+		}
+		if len(res.CAData) > 0 {
+			res.CAData = append(append(res.CAData, '\n'), bundle...)
+		} else {
+			res.CAData = bundle
+		}
+	}
+
+	if o.TLSServerName != "" {
+		res.ServerName = o.TLSServerName
+	}
+
+	return res, nil
+}
+
+// ApplyToCluster returns a new cluster with the connection settings applied to the REST config of the given one.
+// The client of the new cluster is initialized with the scheme of the given cluster.
+// The given cluster is returned unchanged if no connection setting is configured.
+func (o ConnectionOptions) ApplyToCluster(c *clusters.Cluster) (*clusters.Cluster, error) {
+	if o.IsEmpty() {
+		return c, nil
+	}
+	cfg, err := o.Apply(c.RESTConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply connection settings to '%s' cluster: %w", c.ID(), err)
+	}
+	res := clusters.New(c.ID()).WithRESTConfig(cfg)
+	if err := res.InitializeClient(c.Scheme()); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// proxyURL parses the proxy URL, it returns nil if no proxy is configured.
+func (o ConnectionOptions) proxyURL() (*url.URL, error) {
+	if o.ProxyURL == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(o.ProxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL '%s': %w", o.ProxyURL, err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("invalid proxy URL '%s': scheme must be one of 'http', 'https', or 'socks5'", o.ProxyURL)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL '%s': host must not be empty", o.ProxyURL)
+	}
+	return u, nil
+}
+
+// caBundle reads the CA bundle, it returns nil if no bundle is configured.
+func (o ConnectionOptions) caBundle() ([]byte, error) {
+	if o.CABundleFile == "" {
+		return nil, nil
+	}
+	bundle, err := os.ReadFile(o.CABundleFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA bundle file '%s': %w", o.CABundleFile, err)
+	}
+	if len(bundle) == 0 {
+		return nil, fmt.Errorf("CA bundle file '%s' is empty", o.CABundleFile)
+	}
+	return bundle, nil
+}
@@ -0,0 +1,95 @@
+package restconfig
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	clusterCA = "-----BEGIN CERTIFICATE-----\ncluster\n-----END CERTIFICATE-----"
+	proxyCA   = "-----BEGIN CERTIFICATE-----\nproxy\n-----END CERTIFICATE-----"
+)
+
+func Test_ConnectionOptions_Apply(t *testing.T) {
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "cluster-ca.crt")
+	require.NoError(t, os.WriteFile(caFile, []byte(clusterCA), 0o600))
+	bundleFile := filepath.Join(dir, "bundle.crt")
+	require.NoError(t, os.WriteFile(bundleFile, []byte(proxyCA), 0o600))
+
+	t.Run("should not change the config if no setting is configured", func(t *testing.T) {
+		cfg := &rest.Config{Host: "https://api.example.com", TLSClientConfig: rest.TLSClientConfig{CAData: []byte(clusterCA)}}
+
+		res, err := ConnectionOptions{}.Apply(cfg)
+		require.NoError(t, err)
+		assert.Equal(t, cfg, res)
+		assert.NotSame(t, cfg, res)
+	})
+
+	t.Run("should apply all settings without modifying the given config", func(t *testing.T) {
+		cfg := &rest.Config{Host: "https://10.0.0.1", TLSClientConfig: rest.TLSClientConfig{CAData: []byte(clusterCA)}}
+		opts := ConnectionOptions{ProxyURL: "http://proxy.example.com:3128", CABundleFile: bundleFile, TLSServerName: "api.example.com"}
+
+		res, err := opts.Apply(cfg)
+		require.NoError(t, err)
+		assert.Equal(t, clusterCA+"\n"+proxyCA, string(res.CAData))
+		assert.Equal(t, "api.example.com", res.ServerName)
+		require.NotNil(t, res.Proxy)
+		proxy, err := res.Proxy(&http.Request{})
+		require.NoError(t, err)
+		assert.Equal(t, "http://proxy.example.com:3128", proxy.String())
+
+		assert.Equal(t, clusterCA, string(cfg.CAData))
+		assert.Empty(t, cfg.ServerName)
+		assert.Nil(t, cfg.Proxy)
+	})
+
+	t.Run("should merge the bundle with a CA which is referenced by path", func(t *testing.T) {
+		cfg := &rest.Config{Host: "https://api.example.com", TLSClientConfig: rest.TLSClientConfig{CAFile: caFile}}
+
+		res, err := ConnectionOptions{CABundleFile: bundleFile}.Apply(cfg)
+		require.NoError(t, err)
+		assert.Equal(t, clusterCA+"\n"+proxyCA, string(res.CAData))
+	})
+
+	t.Run("should use the bundle if the config has no CA", func(t *testing.T) {
+		res, err := ConnectionOptions{CABundleFile: bundleFile}.Apply(&rest.Config{Host: "https://api.example.com"})
+		require.NoError(t, err)
+		assert.Equal(t, proxyCA, string(res.CAData))
+	})
+}
+
+func Test_ConnectionOptions_Validate(t *testing.T) {
+	emptyFile := filepath.Join(t.TempDir(), "empty.crt")
+	require.NoError(t, os.WriteFile(emptyFile, nil, 0o600))
+
+	testCases := []struct {
+		desc  string
+		opts  ConnectionOptions
+		valid bool
+	}{
+		{desc: "no settings", valid: true},
+		{desc: "http proxy", opts: ConnectionOptions{ProxyURL: "http://proxy:3128"}, valid: true},
+		{desc: "socks5 proxy", opts: ConnectionOptions{ProxyURL: "socks5://proxy:1080"}, valid: true},
+		{desc: "proxy without scheme", opts: ConnectionOptions{ProxyURL: "proxy:3128"}},
+		{desc: "proxy without host", opts: ConnectionOptions{ProxyURL: "http://"}},
+		{desc: "missing CA bundle", opts: ConnectionOptions{CABundleFile: filepath.Join(t.TempDir(), "missing.crt")}},
+		{desc: "empty CA bundle", opts: ConnectionOptions{CABundleFile: emptyFile}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := tc.opts.Validate()
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
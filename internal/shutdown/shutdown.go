@@ -0,0 +1,78 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+)
+
+// errShuttingDown is returned by the readiness check once the shutdown has started.
+var errShuttingDown = errors.New("shutting down")
+
+// Sequence controls the shutdown of the platform service on termination signals.
+// The replica first reports itself as not ready, so that no new webhook requests are routed to it, and keeps serving for the configured delay,
+// until the endpoints have been updated. Only then the manager is stopped.
+type Sequence struct {
+	delay        time.Duration
+	log          logging.Logger
+	shuttingDown atomic.Bool
+}
+
+// NewSequence creates a new shutdown Sequence with the given delay between the termination signal and the stop of the manager.
+func NewSequence(delay time.Duration, log logging.Logger) *Sequence {
+	return &Sequence{
+		delay: delay,
+		log:   log,
+	}
+}
+
+// Context returns a context for the manager, which is cancelled after the delay once the given signal context is done.
+func (s *Sequence) Context(signalCtx context.Context) context.Context {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(signalCtx))
+	context.AfterFunc(signalCtx, func() {
+		s.shuttingDown.Store(true)
+		s.log.Info("Received termination signal, reporting not ready before stopping", "delay", s.delay.String())
+		time.Sleep(s.delay)
+		s.log.Info("Stopping manager")
+		cancel()
+	})
+	return ctx
+}
+
+// ReadyzCheck is a healthz.Checker which fails once the shutdown has started.
+func (s *Sequence) ReadyzCheck(_ *http.Request) error {
+	if s.shuttingDown.Load() {
+		return errShuttingDown
+	}
+	return nil
+}
+
+// GracefulReconciler returns a reconciler which runs the given one with a context that is only cancelled after the given grace period
+// once the manager is stopped, so that in-flight reconciliations can complete their changes and status updates instead of being aborted halfway.
+// The reconciler is returned as is if the grace period is not positive.
+func GracefulReconciler(r reconcile.Reconciler, gracePeriod time.Duration) reconcile.Reconciler {
+	if gracePeriod <= 0 {
+		return r
+	}
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		reconcileCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		defer cancel()
+		stop := context.AfterFunc(ctx, func() {
+			timer := time.NewTimer(gracePeriod)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+				cancel()
+			case <-reconcileCtx.Done():
+			}
+		})
+		defer stop()
+		return r.Reconcile(reconcileCtx, req)
+	})
+}
@@ -0,0 +1,77 @@
+package shutdown
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+)
+
+func Test_Sequence(t *testing.T) {
+	s := NewSequence(100*time.Millisecond, logging.Discard())
+	signalCtx, signal := context.WithCancel(context.Background())
+	ctx := s.Context(signalCtx)
+	require.NoError(t, s.ReadyzCheck(nil))
+
+	signal()
+	assert.Eventually(t, func() bool { return s.ReadyzCheck(nil) != nil }, time.Second, 10*time.Millisecond)
+	assert.NoError(t, ctx.Err(), "the manager must keep running during the delay")
+	assert.Eventually(t, func() bool { return ctx.Err() != nil }, time.Second, 10*time.Millisecond)
+}
+
+func Test_GracefulReconciler(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		gracePeriod time.Duration
+		duration    time.Duration
+		expectErr   bool
+	}{
+		{
+			desc:        "should complete in-flight reconciliations within the grace period",
+			gracePeriod: time.Second,
+			duration:    100 * time.Millisecond,
+		},
+		{
+			desc:        "should abort reconciliations exceeding the grace period",
+			gracePeriod: 100 * time.Millisecond,
+			duration:    time.Second,
+			expectErr:   true,
+		},
+		{
+			desc:      "should abort reconciliations right away without grace period",
+			duration:  time.Second,
+			expectErr: true,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			started := make(chan struct{})
+			r := GracefulReconciler(reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+				close(started)
+				select {
+				case <-time.After(tC.duration):
+					return reconcile.Result{}, nil
+				case <-ctx.Done():
+					return reconcile.Result{}, ctx.Err()
+				}
+			}), tC.gracePeriod)
+
+			ctx, stop := context.WithCancel(context.Background())
+			go func() {
+				<-started
+				stop()
+			}()
+			_, err := r.Reconcile(ctx, reconcile.Request{})
+			if tC.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package systemnamespace
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	ctrlutils "github.com/openmcp-project/controller-utils/pkg/controller"
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+	openmcpconst "github.com/openmcp-project/openmcp-operator/api/constants"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+const (
+	networkPolicySuffix = "-ingress"
+)
+
+var (
+	// PlatformServiceLabel is set on the provider system namespace and contains the name of the platform service which runs in it.
+	// It allows network policies of other components to select the namespace.
+	PlatformServiceLabel = fmt.Sprintf("%s/platform-service", pwv1alpha1.GroupName)
+)
+
+// Setup describes the resources in the provider system namespace on the platform cluster, which the platform service relies on.
+// The namespace itself is usually created by the openmcp-operator together with the platform service deployment,
+// the webhook Service and Secret are handled by the webhook initialization.
+type Setup struct {
+	// Client is the client for the platform cluster.
+	Client client.Client
+	// Namespace is the provider system namespace, i.e. the namespace in which the platform service pods run.
+	Namespace string
+	// ProviderName is the name of the platform service.
+	ProviderName string
+	// ManagedBy is the value of the managed-by label of the created resources.
+	ManagedBy string
+	// PodSelector selects the pods of the platform service.
+	PodSelector map[string]string
+	// IngressPorts are the ports of the platform service pods which accept traffic from anywhere, e.g. the webhook and metrics ports.
+	// If empty, all ingress traffic to the pods is denied, except for the one from their node.
+	IngressPorts []int32
+}
+
+// NetworkPolicyName returns the name of the NetworkPolicy which restricts the ingress traffic to the platform service pods.
+func (s *Setup) NetworkPolicyName() string {
+	return ctrlutils.ShortenToXCharactersUnsafe(s.ProviderName, ctrlutils.K8sMaxNameLength-len(networkPolicySuffix)) + networkPolicySuffix
+}
+
+// Ensure creates the provider system namespace if it does not exist and brings its labels and the NetworkPolicy for the platform service pods up to date.
+func (s *Setup) Ensure(ctx context.Context) error {
+	log := logging.FromContextOrDiscard(ctx)
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: s.Namespace}}
+	result, err := controllerutil.CreateOrPatch(ctx, s.Client, ns, func() error {
+		if ns.Labels == nil {
+			ns.Labels = map[string]string{}
+		}
+		ns.Labels[PlatformServiceLabel] = s.ProviderName
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create or update provider system namespace '%s': %w", s.Namespace, err)
+	}
+	utils.LogOperationResult(log, logging.INFO, ns, result)
+
+	np := &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: s.NetworkPolicyName(), Namespace: s.Namespace}}
+	result, err = controllerutil.CreateOrUpdate(ctx, s.Client, np, func() error {
+		if np.Labels == nil {
+			np.Labels = map[string]string{}
+		}
+		np.Labels[openmcpconst.ManagedByLabel] = s.ManagedBy
+		np.Spec = s.networkPolicySpec()
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create or update NetworkPolicy '%s/%s': %w", np.Namespace, np.Name, err)
+	}
+	utils.LogOperationResult(log, logging.INFO, np, result)
+	return nil
+}
+
+// networkPolicySpec returns the spec of the NetworkPolicy, which only allows ingress traffic to the platform service pods on the ingress ports.
+// The webhooks are called by the API server of the onboarding cluster, which might run anywhere, so the sources are not restricted.
+func (s *Setup) networkPolicySpec() networkingv1.NetworkPolicySpec {
+	spec := networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{MatchLabels: maps.Clone(s.PodSelector)},
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		Ingress:     []networkingv1.NetworkPolicyIngressRule{},
+	}
+	if len(s.IngressPorts) == 0 {
+		return spec
+	}
+	rule := networkingv1.NetworkPolicyIngressRule{}
+	for _, port := range s.IngressPorts {
+		protocol := corev1.ProtocolTCP
+		p := intstr.FromInt32(port)
+		rule.Ports = append(rule.Ports, networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &p})
+	}
+	spec.Ingress = append(spec.Ingress, rule)
+	return spec
+}
+
+// Cleanup removes what Ensure has added to the provider system namespace.
+// The namespace itself is kept, because it is owned by the openmcp-operator and still contains the platform service deployment.
+func (s *Setup) Cleanup(ctx context.Context) error {
+	log := logging.FromContextOrDiscard(ctx)
+
+	np := &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: s.NetworkPolicyName(), Namespace: s.Namespace}}
+	if err := s.Client.Delete(ctx, np); err == nil {
+		log.Info("Deleted NetworkPolicy", "name", np.Name, "namespace", np.Namespace)
+	} else if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete NetworkPolicy '%s/%s': %w", np.Namespace, np.Name, err)
+	}
+
+	ns := &corev1.Namespace{}
+	if err := s.Client.Get(ctx, client.ObjectKey{Name: s.Namespace}, ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get provider system namespace '%s': %w", s.Namespace, err)
+	}
+	if _, ok := ns.Labels[PlatformServiceLabel]; !ok {
+		return nil
+	}
+	old := ns.DeepCopy()
+	delete(ns.Labels, PlatformServiceLabel)
+	if err := s.Client.Patch(ctx, ns, client.MergeFrom(old)); err != nil {
+		return fmt.Errorf("failed to remove label '%s' from provider system namespace '%s': %w", PlatformServiceLabel, s.Namespace, err)
+	}
+	log.Info("Removed label from provider system namespace", "label", PlatformServiceLabel, "namespace", s.Namespace)
+	return nil
+}
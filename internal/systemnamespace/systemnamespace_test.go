@@ -0,0 +1,85 @@
+package systemnamespace_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+	openmcpconst "github.com/openmcp-project/openmcp-operator/api/constants"
+
+	"github.com/openmcp-project/platform-service-project-workspace/internal/systemnamespace"
+)
+
+func TestSetup(t *testing.T) {
+	ctx := logging.NewContext(context.Background(), logging.Discard())
+	c := fake.NewClientBuilder().WithObjects(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "openmcp-system", Labels: map[string]string{"foo": "bar"}},
+	}).Build()
+	setup := &systemnamespace.Setup{
+		Client:       c,
+		Namespace:    "openmcp-system",
+		ProviderName: "project-workspace",
+		ManagedBy:    "platform-service-project-workspace",
+		PodSelector:  map[string]string{"app.kubernetes.io/instance": "project-workspace"},
+		IngressPorts: []int32{9443, 8443},
+	}
+
+	require.NoError(t, setup.Ensure(ctx))
+	ns := &corev1.Namespace{}
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Name: "openmcp-system"}, ns))
+	assert.Equal(t, map[string]string{"foo": "bar", systemnamespace.PlatformServiceLabel: "project-workspace"}, ns.Labels)
+
+	np := &networkingv1.NetworkPolicy{}
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Name: "project-workspace-ingress", Namespace: "openmcp-system"}, np))
+	assert.Equal(t, "platform-service-project-workspace", np.Labels[openmcpconst.ManagedByLabel])
+	assert.Equal(t, map[string]string{"app.kubernetes.io/instance": "project-workspace"}, np.Spec.PodSelector.MatchLabels)
+	assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, np.Spec.PolicyTypes)
+	if assert.Len(t, np.Spec.Ingress, 1) {
+		var ports []intstr.IntOrString
+		for _, p := range np.Spec.Ingress[0].Ports {
+			ports = append(ports, *p.Port)
+		}
+		assert.Equal(t, []intstr.IntOrString{intstr.FromInt32(9443), intstr.FromInt32(8443)}, ports)
+		assert.Empty(t, np.Spec.Ingress[0].From)
+	}
+
+	t.Run("denies all ingress without ports", func(t *testing.T) {
+		setup := *setup
+		setup.IngressPorts = nil
+		require.NoError(t, setup.Ensure(ctx))
+		np := &networkingv1.NetworkPolicy{}
+		require.NoError(t, c.Get(ctx, client.ObjectKey{Name: "project-workspace-ingress", Namespace: "openmcp-system"}, np))
+		assert.Empty(t, np.Spec.Ingress)
+	})
+
+	t.Run("creates a missing namespace", func(t *testing.T) {
+		setup := *setup
+		setup.Namespace = "other-system"
+		require.NoError(t, setup.Ensure(ctx))
+		ns := &corev1.Namespace{}
+		require.NoError(t, c.Get(ctx, client.ObjectKey{Name: "other-system"}, ns))
+		assert.Equal(t, "project-workspace", ns.Labels[systemnamespace.PlatformServiceLabel])
+	})
+
+	t.Run("cleans up the namespace", func(t *testing.T) {
+		require.NoError(t, setup.Cleanup(ctx))
+		ns := &corev1.Namespace{}
+		require.NoError(t, c.Get(ctx, client.ObjectKey{Name: "openmcp-system"}, ns))
+		assert.Equal(t, map[string]string{"foo": "bar"}, ns.Labels)
+		err := c.Get(ctx, client.ObjectKey{Name: "project-workspace-ingress", Namespace: "openmcp-system"}, &networkingv1.NetworkPolicy{})
+		assert.True(t, apierrors.IsNotFound(err))
+
+		// cleaning up twice is a no-op
+		assert.NoError(t, setup.Cleanup(ctx))
+	})
+}
@@ -1,29 +1,114 @@
 package utils
 
 import (
+	"maps"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	openmcpconst "github.com/openmcp-project/openmcp-operator/api/constants"
 
 	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/api/v2/metadata"
 )
 
 const (
 	LabelProject   = pwv1alpha1.GroupName + "/project"
 	LabelWorkspace = pwv1alpha1.GroupName + "/workspace"
+	// LabelOwnerUID holds the UID of the Project or Workspace a resource has been created for.
+	// It allows to find and clean up resources which cannot have an owner reference, e.g. cluster-scoped resources of a workspace.
+	LabelOwnerUID = pwv1alpha1.GroupName + "/owner-uid"
+	// AnnotationOwner references the Project or Workspace a cluster-scoped resource has been created for, in the format '<type>/<name>' or '<type>/<namespace>/<name>'.
+	// It allows to look up the owner of resources whose names do not contain the complete name of their owner, e.g. the ClusterRoles of projects and workspaces.
+	AnnotationOwner = pwv1alpha1.GroupName + "/owner"
+	// LabelEnvironment marks projects, workspaces, and invitations as belonging to the platform service instance with the given environment.
+	// The validating webhooks of an instance only match resources with its environment, the mutating webhooks set the label on resources without it.
+	LabelEnvironment = pwv1alpha1.GroupName + "/environment"
+	// LabelBaseClusterRole marks the ClusterRoles of the project and workspace roles, which are referenced by the RoleBindings of all projects and workspaces.
+	// Its value is the provider name of the platform service. Other than the management labels, it is not configurable, so that these ClusterRoles can be watched with a fixed selector.
+	LabelBaseClusterRole = pwv1alpha1.GroupName + "/base-cluster-role"
 
 	Purpose = "project-workspace-management"
 )
 
 func SetManagementLabels(obj metav1.Object, providerName string) {
-	SetMetaDataLabel(obj, openmcpconst.ManagedByLabel, providerName)
-	SetMetaDataLabel(obj, openmcpconst.ManagedPurposeLabel, Purpose)
+	ApplyManagementLabels(obj, providerName, pwv1alpha1.ManagementLabelsConfig{})
+}
+
+// ManagementLabels returns the labels which mark resources as managed by the platform service.
+// These are the default 'managed-by' and 'managed-purpose' labels, merged with the labels from the given config.
+func ManagementLabels(providerName string, cfg pwv1alpha1.ManagementLabelsConfig) map[string]string {
+	res := map[string]string{
+		openmcpconst.ManagedByLabel:      providerName,
+		openmcpconst.ManagedPurposeLabel: Purpose,
+	}
+	maps.Copy(res, cfg.Labels)
+	return res
+}
+
+// ApplyManagementLabels sets the management labels on the given object.
+// Labels from previous label sets which are not part of the current one are removed, if they still have their previous value.
+func ApplyManagementLabels(obj metav1.Object, providerName string, cfg pwv1alpha1.ManagementLabelsConfig) {
+	current := ManagementLabels(providerName, cfg)
+	labels := obj.GetLabels()
+	for _, previous := range cfg.PreviousLabels {
+		for k, v := range previous {
+			if _, ok := current[k]; !ok && labels[k] == v {
+				delete(labels, k)
+			}
+		}
+	}
+	if labels != nil {
+		obj.SetLabels(labels)
+	}
+	metadata.EnsureLabels(obj, current)
+}
+
+// IsManaged returns true if the given object carries either all current management labels or all labels of any of the previous label sets.
+func IsManaged(obj metav1.Object, providerName string, cfg pwv1alpha1.ManagementLabelsConfig) bool {
+	if metadata.HasLabels(obj, ManagementLabels(providerName, cfg)) {
+		return true
+	}
+	for _, previous := range cfg.PreviousLabels {
+		if len(previous) > 0 && metadata.HasLabels(obj, previous) {
+			return true
+		}
+	}
+	return false
 }
 
 func SetProjectLabel(obj metav1.Object, project string) {
-	SetMetaDataLabel(obj, LabelProject, project)
+	metadata.SetLabel(obj, LabelProject, project)
 }
 
 func SetWorkspaceLabel(obj metav1.Object, workspace string) {
-	SetMetaDataLabel(obj, LabelWorkspace, workspace)
+	metadata.SetLabel(obj, LabelWorkspace, workspace)
+}
+
+// SetBaseClusterRoleLabel marks the given ClusterRole as one of the ClusterRoles of the project and workspace roles, see LabelBaseClusterRole.
+func SetBaseClusterRoleLabel(obj metav1.Object, providerName string) {
+	metadata.SetLabel(obj, LabelBaseClusterRole, providerName)
+}
+
+// SetOwnerUIDLabel sets the owner UID label to the UID of the given owner.
+// Nothing is done if the owner does not have a UID yet.
+func SetOwnerUIDLabel(obj metav1.Object, owner metav1.Object) {
+	if owner.GetUID() == "" {
+		return
+	}
+	metadata.SetLabel(obj, LabelOwnerUID, string(owner.GetUID()))
+}
+
+// OwnerEntity is a Project or Workspace which owns the resources created for it.
+type OwnerEntity interface {
+	metav1.Object
+	TypeIdentifier() string
+}
+
+// SetOwnerAnnotation sets the owner annotation to the reference of the given owner, see AnnotationOwner.
+func SetOwnerAnnotation(obj metav1.Object, owner OwnerEntity) {
+	ref := owner.TypeIdentifier() + "/" + owner.GetName()
+	if owner.GetNamespace() != "" {
+		ref = owner.TypeIdentifier() + "/" + owner.GetNamespace() + "/" + owner.GetName()
+	}
+	metadata.SetAnnotation(obj, AnnotationOwner, ref)
 }
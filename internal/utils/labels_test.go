@@ -10,6 +10,7 @@ import (
 
 	openmcpconst "github.com/openmcp-project/openmcp-operator/api/constants"
 
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
 	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
 )
 
@@ -46,6 +47,104 @@ func TestSetManagementLabels(t *testing.T) {
 	})
 }
 
+func TestApplyManagementLabels(t *testing.T) {
+	cfg := pwv1alpha1.ManagementLabelsConfig{
+		Labels: map[string]string{
+			"app.kubernetes.io/part-of":      "openmcp",
+			openmcpconst.ManagedPurposeLabel: "custom",
+		},
+		PreviousLabels: []map[string]string{
+			{
+				"app.kubernetes.io/managed-by": "legacy-operator",
+				"app.kubernetes.io/part-of":    "legacy",
+			},
+		},
+	}
+
+	t.Run("merges the configured labels with the default ones", func(t *testing.T) {
+		var obj metav1.ObjectMeta
+
+		utils.ApplyManagementLabels(&obj, "test", cfg)
+
+		assert.Equal(t, map[string]string{openmcpconst.ManagedByLabel: "test", openmcpconst.ManagedPurposeLabel: "custom", "app.kubernetes.io/part-of": "openmcp"}, obj.Labels)
+	})
+	t.Run("removes previous labels which are not part of the current label set", func(t *testing.T) {
+		var obj metav1.ObjectMeta
+		obj.Labels = map[string]string{
+			"app.kubernetes.io/managed-by": "legacy-operator",
+			"app.kubernetes.io/part-of":    "legacy",
+			"existing":                     "shouldn't be touched",
+		}
+
+		utils.ApplyManagementLabels(&obj, "test", cfg)
+
+		assert.Equal(t, map[string]string{openmcpconst.ManagedByLabel: "test", openmcpconst.ManagedPurposeLabel: "custom", "app.kubernetes.io/part-of": "openmcp", "existing": "shouldn't be touched"}, obj.Labels)
+	})
+	t.Run("doesn't remove previous label keys with a different value", func(t *testing.T) {
+		var obj metav1.ObjectMeta
+		obj.Labels = map[string]string{
+			"app.kubernetes.io/managed-by": "someone-else",
+		}
+
+		utils.ApplyManagementLabels(&obj, "test", cfg)
+
+		assert.Equal(t, "someone-else", obj.Labels["app.kubernetes.io/managed-by"])
+	})
+}
+
+func TestIsManaged(t *testing.T) {
+	cfg := pwv1alpha1.ManagementLabelsConfig{
+		PreviousLabels: []map[string]string{
+			{
+				"app.kubernetes.io/managed-by": "legacy-operator",
+			},
+		},
+	}
+
+	tests := []struct {
+		description string
+		labels      map[string]string
+		expected    bool
+	}{
+		{
+			description: "returns 'true' if the current management labels are set",
+			labels:      utils.ManagementLabels("test", cfg),
+			expected:    true,
+		},
+		{
+			description: "returns 'true' if the labels of a previous label set are set",
+			labels: map[string]string{
+				"app.kubernetes.io/managed-by": "legacy-operator",
+			},
+			expected: true,
+		},
+		{
+			description: "returns 'false' if only some of the current management labels are set",
+			labels: map[string]string{
+				openmcpconst.ManagedByLabel: "test",
+			},
+		},
+		{
+			description: "returns 'false' if the object is managed by someone else",
+			labels: map[string]string{
+				openmcpconst.ManagedByLabel:      "other",
+				openmcpconst.ManagedPurposeLabel: utils.Purpose,
+			},
+		},
+		{
+			description: "returns 'false' if there are no labels",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			obj := metav1.ObjectMeta{Labels: test.labels}
+
+			assert.Equal(t, test.expected, utils.IsManaged(&obj, "test", cfg))
+		})
+	}
+}
+
 func TestSetProjectLabel(t *testing.T) {
 	t.Run("set's the 'openmcp.cloud/project' label", func(t *testing.T) {
 		var obj metav1.ObjectMeta
@@ -113,3 +212,21 @@ func TestSetWorkspaceLabel(t *testing.T) {
 		assert.Equal(t, map[string]string{utils.LabelWorkspace: "test", "existing": "shouldn't be touched"}, obj.Labels)
 	})
 }
+
+func TestSetOwnerUIDLabel(t *testing.T) {
+	t.Run("sets the label to the UID of the owner", func(t *testing.T) {
+		var obj rbacv1.ClusterRole
+		owner := metav1.ObjectMeta{UID: "1234"}
+
+		utils.SetOwnerUIDLabel(&obj, &owner)
+
+		assert.Equal(t, map[string]string{utils.LabelOwnerUID: "1234"}, obj.Labels)
+	})
+	t.Run("does nothing if the owner has no UID", func(t *testing.T) {
+		var obj rbacv1.ClusterRole
+
+		utils.SetOwnerUIDLabel(&obj, &metav1.ObjectMeta{})
+
+		assert.Empty(t, obj.Labels)
+	})
+}
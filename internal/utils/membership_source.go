@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/api/v2/metadata"
+)
+
+// ManagedMembers returns the name of the ProjectMembershipSource the members of the given project or workspace are synced from,
+// and the subjects which are managed by it. The name is empty if no members are synced from a source.
+func ManagedMembers(obj metav1.Object) (string, []pwv1alpha1.Subject, error) {
+	source := obj.GetLabels()[pwv1alpha1.MembershipSourceLabel]
+	value, ok := obj.GetAnnotations()[pwv1alpha1.ManagedMembersAnnotation]
+	if !ok || value == "" {
+		return source, nil, nil
+	}
+	var subjects []pwv1alpha1.Subject
+	if err := json.Unmarshal([]byte(value), &subjects); err != nil {
+		return source, nil, fmt.Errorf("failed to parse annotation %s: %w", pwv1alpha1.ManagedMembersAnnotation, err)
+	}
+	return source, subjects, nil
+}
+
+// SetManagedMembers marks the given subjects as members of the project or workspace which are synced from the given ProjectMembershipSource.
+func SetManagedMembers(obj metav1.Object, source string, subjects []pwv1alpha1.Subject) error {
+	if subjects == nil {
+		subjects = []pwv1alpha1.Subject{}
+	}
+	value, err := json.Marshal(subjects)
+	if err != nil {
+		return fmt.Errorf("failed to marshal managed members: %w", err)
+	}
+	metadata.SetLabel(obj, pwv1alpha1.MembershipSourceLabel, source)
+	metadata.SetAnnotation(obj, pwv1alpha1.ManagedMembersAnnotation, string(value))
+	return nil
+}
+
+// ReleaseManagedMembers removes the marker of the ProjectMembershipSource from the given project or workspace.
+// The members themselves are kept and can be changed manually afterwards. Returns true if the object has been changed.
+func ReleaseManagedMembers(obj metav1.Object) bool {
+	labels, annotations := obj.GetLabels(), obj.GetAnnotations()
+	_, hasLabel := labels[pwv1alpha1.MembershipSourceLabel]
+	_, hasAnnotation := annotations[pwv1alpha1.ManagedMembersAnnotation]
+	if hasLabel {
+		delete(labels, pwv1alpha1.MembershipSourceLabel)
+		obj.SetLabels(labels)
+	}
+	if hasAnnotation {
+		delete(annotations, pwv1alpha1.ManagedMembersAnnotation)
+		obj.SetAnnotations(annotations)
+	}
+	return hasLabel || hasAnnotation
+}
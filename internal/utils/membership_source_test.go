@@ -0,0 +1,53 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+func TestManagedMembers(t *testing.T) {
+	subjects := []pwv1alpha1.Subject{
+		{Kind: "Group", Name: "devs"},
+		{Kind: "ServiceAccount", Name: "ci", Namespace: "tools"},
+	}
+
+	t.Run("returns the source and subjects which have been set", func(t *testing.T) {
+		obj := metav1.ObjectMeta{Labels: map[string]string{"other": "label"}}
+		require.NoError(t, utils.SetManagedMembers(&obj, "gitops", subjects))
+
+		source, managed, err := utils.ManagedMembers(&obj)
+		require.NoError(t, err)
+		assert.Equal(t, "gitops", source)
+		assert.Equal(t, subjects, managed)
+		assert.Equal(t, "label", obj.Labels["other"])
+	})
+
+	t.Run("returns nothing for an object without managed members", func(t *testing.T) {
+		source, managed, err := utils.ManagedMembers(&metav1.ObjectMeta{})
+		require.NoError(t, err)
+		assert.Empty(t, source)
+		assert.Empty(t, managed)
+	})
+
+	t.Run("returns an error for an invalid annotation", func(t *testing.T) {
+		obj := metav1.ObjectMeta{Annotations: map[string]string{pwv1alpha1.ManagedMembersAnnotation: "devs"}}
+		_, _, err := utils.ManagedMembers(&obj)
+		assert.Error(t, err)
+	})
+
+	t.Run("release removes the marker, but keeps other metadata", func(t *testing.T) {
+		obj := metav1.ObjectMeta{Labels: map[string]string{"other": "label"}, Annotations: map[string]string{"other": "annotation"}}
+		require.NoError(t, utils.SetManagedMembers(&obj, "gitops", subjects))
+
+		assert.True(t, utils.ReleaseManagedMembers(&obj))
+		assert.Equal(t, map[string]string{"other": "label"}, obj.Labels)
+		assert.Equal(t, map[string]string{"other": "annotation"}, obj.Annotations)
+		assert.False(t, utils.ReleaseManagedMembers(&obj))
+	})
+}
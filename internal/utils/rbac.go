@@ -1,6 +1,12 @@
 package utils
 
 import (
+	"fmt"
+	"slices"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+
 	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
 )
 
@@ -26,14 +32,116 @@ func ReadOnlyVerbs() []string {
 
 func ProjectRolesWithVerbs() map[pwv1alpha1.ProjectMemberRole][]string {
 	return map[pwv1alpha1.ProjectMemberRole][]string{
-		pwv1alpha1.ProjectRoleAdmin: AllVerbs(),
-		pwv1alpha1.ProjectRoleView:  ReadOnlyVerbs(),
+		pwv1alpha1.ProjectRoleAdmin:   AllVerbs(),
+		pwv1alpha1.ProjectRoleView:    ReadOnlyVerbs(),
+		pwv1alpha1.ProjectRoleAuditor: ReadOnlyVerbs(),
 	}
 }
 
 func WorkspaceRolesWithVerbs() map[pwv1alpha1.WorkspaceMemberRole][]string {
 	return map[pwv1alpha1.WorkspaceMemberRole][]string{
-		pwv1alpha1.WorkspaceRoleAdmin: AllVerbs(),
-		pwv1alpha1.WorkspaceRoleView:  ReadOnlyVerbs(),
+		pwv1alpha1.WorkspaceRoleAdmin:   AllVerbs(),
+		pwv1alpha1.WorkspaceRoleView:    ReadOnlyVerbs(),
+		pwv1alpha1.WorkspaceRoleAuditor: ReadOnlyVerbs(),
+	}
+}
+
+// EquivalentPolicyRules returns true if both lists contain the same rules, ignoring the order of the rules as well as the order of the values within each rule.
+// Nil and empty lists are considered equivalent.
+func EquivalentPolicyRules(a, b []rbacv1.PolicyRule) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	return slices.Equal(policyRuleKeys(a), policyRuleKeys(b))
+}
+
+// EquivalentSubjects returns true if both lists contain the same subjects, ignoring their order.
+// Nil and empty lists are considered equivalent.
+func EquivalentSubjects(a, b []rbacv1.Subject) bool {
+	if len(a) != len(b) {
+		return false
 	}
+	return slices.Equal(subjectKeys(a), subjectKeys(b))
+}
+
+// SetRulesIfChanged overwrites the given rules with the desired ones, unless they are already equivalent.
+// This avoids no-op updates of RBAC resources which would otherwise be caused by a differing order.
+func SetRulesIfChanged(rules *[]rbacv1.PolicyRule, desired []rbacv1.PolicyRule) {
+	if !EquivalentPolicyRules(*rules, desired) {
+		*rules = desired
+	}
+}
+
+// SetSubjectsIfChanged overwrites the given subjects with the desired ones, unless they are already equivalent.
+// This avoids no-op updates of RBAC resources which would otherwise be caused by a differing order.
+func SetSubjectsIfChanged(subjects *[]rbacv1.Subject, desired []rbacv1.Subject) {
+	if !EquivalentSubjects(*subjects, desired) {
+		*subjects = desired
+	}
+}
+
+// DiffSubjects returns the subjects which are contained in the desired but not in the existing list (added) and vice versa (removed).
+// The subjects are formatted with FormatSubject and sorted.
+func DiffSubjects(existing, desired []rbacv1.Subject) (added, removed []string) {
+	existingSet := make(map[string]bool, len(existing))
+	for _, s := range existing {
+		existingSet[FormatSubject(s)] = true
+	}
+	desiredSet := make(map[string]bool, len(desired))
+	for _, s := range desired {
+		desiredSet[FormatSubject(s)] = true
+	}
+	for k := range desiredSet {
+		if !existingSet[k] {
+			added = append(added, k)
+		}
+	}
+	for k := range existingSet {
+		if !desiredSet[k] {
+			removed = append(removed, k)
+		}
+	}
+	slices.Sort(added)
+	slices.Sort(removed)
+	return added, removed
+}
+
+// FormatSubject returns a human-readable representation of the given subject, e.g. 'User:jane.doe@example.com' or 'ServiceAccount:my-namespace/my-sa'.
+func FormatSubject(s rbacv1.Subject) string {
+	if s.Namespace != "" {
+		return fmt.Sprintf("%s:%s/%s", s.Kind, s.Namespace, s.Name)
+	}
+	return fmt.Sprintf("%s:%s", s.Kind, s.Name)
+}
+
+// policyRuleKeys returns a sorted list of normalized string representations of the given rules.
+func policyRuleKeys(rules []rbacv1.PolicyRule) []string {
+	keys := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		keys = append(keys, fmt.Sprintf("%s|%s|%s|%s|%s",
+			sortedJoin(rule.APIGroups),
+			sortedJoin(rule.Resources),
+			sortedJoin(rule.ResourceNames),
+			sortedJoin(rule.NonResourceURLs),
+			sortedJoin(rule.Verbs),
+		))
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// subjectKeys returns a sorted list of string representations of the given subjects.
+func subjectKeys(subjects []rbacv1.Subject) []string {
+	keys := make([]string, 0, len(subjects))
+	for _, s := range subjects {
+		keys = append(keys, fmt.Sprintf("%s|%s|%s|%s", s.Kind, s.APIGroup, s.Namespace, s.Name))
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func sortedJoin(values []string) string {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	return strings.Join(sorted, ",")
 }
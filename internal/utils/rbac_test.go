@@ -0,0 +1,182 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+func TestEquivalentPolicyRules(t *testing.T) {
+	ruleA := rbacv1.PolicyRule{
+		APIGroups: []string{""},
+		Resources: []string{"secrets", "configmaps"},
+		Verbs:     []string{"get", "list", "watch"},
+	}
+	ruleB := rbacv1.PolicyRule{
+		APIGroups:     []string{""},
+		Resources:     []string{"namespaces"},
+		ResourceNames: []string{"project-test"},
+		Verbs:         []string{"get"},
+	}
+
+	tests := []struct {
+		description string
+		a           []rbacv1.PolicyRule
+		b           []rbacv1.PolicyRule
+		expected    bool
+	}{
+		{
+			description: "nil and empty lists are equivalent",
+			a:           nil,
+			b:           []rbacv1.PolicyRule{},
+			expected:    true,
+		},
+		{
+			description: "identical lists are equivalent",
+			a:           []rbacv1.PolicyRule{ruleA, ruleB},
+			b:           []rbacv1.PolicyRule{ruleA, ruleB},
+			expected:    true,
+		},
+		{
+			description: "order of rules is ignored",
+			a:           []rbacv1.PolicyRule{ruleA, ruleB},
+			b:           []rbacv1.PolicyRule{ruleB, ruleA},
+			expected:    true,
+		},
+		{
+			description: "order of values within a rule is ignored",
+			a:           []rbacv1.PolicyRule{ruleA},
+			b: []rbacv1.PolicyRule{
+				{
+					APIGroups: []string{""},
+					Resources: []string{"configmaps", "secrets"},
+					Verbs:     []string{"watch", "list", "get"},
+				},
+			},
+			expected: true,
+		},
+		{
+			description: "different verbs are not equivalent",
+			a:           []rbacv1.PolicyRule{ruleA},
+			b: []rbacv1.PolicyRule{
+				{
+					APIGroups: []string{""},
+					Resources: []string{"secrets", "configmaps"},
+					Verbs:     []string{"get", "list"},
+				},
+			},
+		},
+		{
+			description: "different number of rules is not equivalent",
+			a:           []rbacv1.PolicyRule{ruleA, ruleB},
+			b:           []rbacv1.PolicyRule{ruleA},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expected, utils.EquivalentPolicyRules(test.a, test.b))
+		})
+	}
+}
+
+func TestEquivalentSubjects(t *testing.T) {
+	user := rbacv1.Subject{
+		APIGroup: rbacv1.GroupName,
+		Kind:     rbacv1.UserKind,
+		Name:     "user@example.com",
+	}
+	sa := rbacv1.Subject{
+		Kind:      rbacv1.ServiceAccountKind,
+		Name:      "default",
+		Namespace: "default",
+	}
+
+	tests := []struct {
+		description string
+		a           []rbacv1.Subject
+		b           []rbacv1.Subject
+		expected    bool
+	}{
+		{
+			description: "nil and empty lists are equivalent",
+			a:           nil,
+			b:           []rbacv1.Subject{},
+			expected:    true,
+		},
+		{
+			description: "order of subjects is ignored",
+			a:           []rbacv1.Subject{user, sa},
+			b:           []rbacv1.Subject{sa, user},
+			expected:    true,
+		},
+		{
+			description: "different subjects are not equivalent",
+			a:           []rbacv1.Subject{user},
+			b:           []rbacv1.Subject{sa},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expected, utils.EquivalentSubjects(test.a, test.b))
+		})
+	}
+}
+
+func TestSetRulesIfChanged(t *testing.T) {
+	existing := []rbacv1.PolicyRule{
+		{
+			APIGroups: []string{""},
+			Resources: []string{"secrets"},
+			Verbs:     []string{"list", "get"},
+		},
+	}
+
+	t.Run("keeps existing rules if they are equivalent", func(t *testing.T) {
+		rules := existing
+		utils.SetRulesIfChanged(&rules, []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{""},
+				Resources: []string{"secrets"},
+				Verbs:     []string{"get", "list"},
+			},
+		})
+		assert.Equal(t, existing, rules)
+	})
+	t.Run("overwrites existing rules if they differ", func(t *testing.T) {
+		rules := existing
+		desired := []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{""},
+				Resources: []string{"secrets"},
+				Verbs:     []string{"get"},
+			},
+		}
+		utils.SetRulesIfChanged(&rules, desired)
+		assert.Equal(t, desired, rules)
+	})
+}
+
+func TestDiffSubjects(t *testing.T) {
+	jane := rbacv1.Subject{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: "jane.doe@example.com"}
+	john := rbacv1.Subject{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: "john.doe@example.com"}
+	devs := rbacv1.Subject{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: "devs"}
+	bot := rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Namespace: "project-foo", Name: "bot"}
+
+	added, removed := utils.DiffSubjects([]rbacv1.Subject{jane, devs, bot}, []rbacv1.Subject{john, jane, bot})
+	assert.Equal(t, []string{"User:john.doe@example.com"}, added)
+	assert.Equal(t, []string{"Group:devs"}, removed)
+
+	added, removed = utils.DiffSubjects(nil, []rbacv1.Subject{jane, bot})
+	assert.Equal(t, []string{"ServiceAccount:project-foo/bot", "User:jane.doe@example.com"}, added)
+	assert.Empty(t, removed)
+
+	added, removed = utils.DiffSubjects([]rbacv1.Subject{jane, devs}, []rbacv1.Subject{devs, jane})
+	assert.Empty(t, added)
+	assert.Empty(t, removed)
+}
@@ -1,8 +1,11 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -28,15 +31,17 @@ func WasDeleted(o metav1.Object) bool {
 	return !o.GetDeletionTimestamp().IsZero()
 }
 
-// SetMetaDataLabel sets the key value pair in the labels section of the given Object.
-// If the given Object did not yet have labels, they are initialized.
-func SetMetaDataLabel(meta metav1.Object, key, value string) {
-	labels := meta.GetLabels()
-	if labels == nil {
-		labels = make(map[string]string)
+// ProjectsCreatedBy returns the sorted names of the given projects which have been created by the given user and are not in deletion.
+// These are the projects which count against the user's project quota.
+func ProjectsCreatedBy(projects []pwv1alpha1.Project, username string) []string {
+	res := []string{}
+	for _, p := range projects {
+		if p.GetAnnotations()[pwv1alpha1.CreatedByAnnotation] == username && !WasDeleted(&p) {
+			res = append(res, p.Name)
+		}
 	}
-	labels[key] = value
-	meta.SetLabels(labels)
+	slices.Sort(res)
+	return res
 }
 
 func LogOperationResult(log logging.Logger, level logging.LogLevel, obj client.Object, result controllerutil.OperationResult, additionalKeysAndValues ...any) {
@@ -49,8 +54,9 @@ func LogOperationResult(log logging.Logger, level logging.LogLevel, obj client.O
 }
 
 const (
-	AdminRoleID  = "admin"
-	ViewerRoleID = "viewer"
+	AdminRoleID   = "admin"
+	ViewerRoleID  = "viewer"
+	AuditorRoleID = "auditor"
 )
 
 func ProjectMemberRoleToRoleID(role pwv1alpha1.ProjectMemberRole) string {
@@ -59,6 +65,8 @@ func ProjectMemberRoleToRoleID(role pwv1alpha1.ProjectMemberRole) string {
 		return AdminRoleID
 	case pwv1alpha1.ProjectRoleView:
 		return ViewerRoleID
+	case pwv1alpha1.ProjectRoleAuditor:
+		return AuditorRoleID
 	default:
 		return ""
 	}
@@ -70,12 +78,30 @@ func WorkspaceMemberRoleToRoleID(role pwv1alpha1.WorkspaceMemberRole) string {
 		return AdminRoleID
 	case pwv1alpha1.WorkspaceRoleView:
 		return ViewerRoleID
+	case pwv1alpha1.WorkspaceRoleAuditor:
+		return AuditorRoleID
 	default:
 		return ""
 	}
 }
 
+const (
+	// MaxClusterRoleNameLength is the maximum length of the names of the ClusterRoles and ClusterRoleBindings which are created for a single project or workspace.
+	// It keeps the names short enough to be used as label values.
+	MaxClusterRoleNameLength = 63
+	// clusterRoleNameHashLength is the length of the hash suffix of these names.
+	clusterRoleNameHashLength = 10
+)
+
+// ClusterRoleForEntityAndRole returns the name of the ClusterRole and ClusterRoleBinding which grant the given role for the given entity.
+// It consists of a readable prefix, which is truncated if necessary, and a hash of the legacy name, see hashedClusterRoleName.
 func ClusterRoleForEntityAndRole(entity entities.AccessEntity, role entities.AccessRole) string {
+	return hashedClusterRoleName(LegacyClusterRoleForEntityAndRole(entity, role))
+}
+
+// LegacyClusterRoleForEntityAndRole returns the name which has been used for the ClusterRole of the given entity and role before the names were hashed.
+// It is only required to migrate existing ClusterRoles.
+func LegacyClusterRoleForEntityAndRole(entity entities.AccessEntity, role entities.AccessRole) string {
 	if reflect.TypeOf(entity) != reflect.TypeOf(role.EntityType()) {
 		panic("AccessEntity/AccessRole mismatch")
 	}
@@ -86,6 +112,20 @@ func ClusterRoleForEntityAndRole(entity entities.AccessEntity, role entities.Acc
 	}, ":")
 }
 
+// ClusterRoleForEntityMembers returns the name of the consolidated ClusterRole which grants read access to the given entity to all of its members, independent of their roles.
+func ClusterRoleForEntityMembers(entity entities.AccessEntity) string {
+	return hashedClusterRoleName(LegacyClusterRoleForEntityMembers(entity))
+}
+
+// LegacyClusterRoleForEntityMembers returns the name which has been used for the consolidated ClusterRole of the given entity before the names were hashed.
+func LegacyClusterRoleForEntityMembers(entity entities.AccessEntity) string {
+	return strings.Join([]string{
+		entity.TypeIdentifier(),
+		entity.GetName(),
+		"member",
+	}, ":")
+}
+
 func ClusterRoleForRole(role entities.AccessRole) string {
 	return strings.Join([]string{
 		role.EntityType().TypeIdentifier(),
@@ -98,13 +138,32 @@ func RoleBindingForRole(role entities.AccessRole) string {
 	return ClusterRoleForRole(role)
 }
 
+// ClusterRoleForEntityAndRoleWithParent returns the name of the ClusterRole and ClusterRoleBinding which grant the given role for the given entity,
+// e.g. a workspace, within the given parent, e.g. its project.
 func ClusterRoleForEntityAndRoleWithParent(entity entities.AccessEntity, role entities.AccessRole, parent entities.AccessEntity) string {
+	return hashedClusterRoleName(LegacyClusterRoleForEntityAndRoleWithParent(entity, role, parent))
+}
+
+// LegacyClusterRoleForEntityAndRoleWithParent returns the name which has been used for the ClusterRole of the given entity, role and parent before the names were hashed.
+func LegacyClusterRoleForEntityAndRoleWithParent(entity entities.AccessEntity, role entities.AccessRole, parent entities.AccessEntity) string {
 	if reflect.TypeOf(entity) == reflect.TypeOf(parent) {
 		panic("AccessEntity/Parent must not be of same type")
 	}
 	return strings.Join([]string{
 		parent.TypeIdentifier(),
 		parent.GetName(),
-		ClusterRoleForEntityAndRole(entity, role),
+		LegacyClusterRoleForEntityAndRole(entity, role),
 	}, ":")
 }
+
+// hashedClusterRoleName appends a hash of the given name to it, truncating the name so that the result does not exceed MaxClusterRoleNameLength.
+// Since the hash is computed from the complete name, names which only differ in the truncated part do not collide.
+func hashedClusterRoleName(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	hash := hex.EncodeToString(sum[:])[:clusterRoleNameHashLength]
+	prefix := name
+	if maxPrefix := MaxClusterRoleNameLength - clusterRoleNameHashLength - 1; len(prefix) > maxPrefix {
+		prefix = strings.TrimRight(prefix[:maxPrefix], ":-")
+	}
+	return prefix + "-" + hash
+}
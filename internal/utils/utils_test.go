@@ -1,6 +1,7 @@
 package utils_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -101,28 +102,71 @@ func TestWasDeleted(t *testing.T) {
 	})
 }
 
-func TestSetMetaDataLabel(t *testing.T) {
-	t.Run("set's the label on an object which has no other labels set", func(t *testing.T) {
-		var obj metav1.ObjectMeta
-
-		utils.SetMetaDataLabel(&obj, "test", "abc")
-
-		assert.Equal(t, map[string]string{"test": "abc"}, obj.GetLabels())
-	})
-	t.Run("overwrites the label on an object if it was set before", func(t *testing.T) {
-		var obj metav1.ObjectMeta
+func TestProjectsCreatedBy(t *testing.T) {
+	project := func(name, createdBy string, deleted bool) pwv1alpha1.Project {
+		p := pwv1alpha1.Project{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if createdBy != "" {
+			p.Annotations = map[string]string{pwv1alpha1.CreatedByAnnotation: createdBy}
+		}
+		if deleted {
+			now := metav1.Now()
+			p.DeletionTimestamp = &now
+		}
+		return p
+	}
+	projects := []pwv1alpha1.Project{
+		project("b", "user@example.com", false),
+		project("a", "user@example.com", false),
+		project("deleted", "user@example.com", true),
+		project("other", "other@example.com", false),
+		project("unknown", "", false),
+	}
 
-		utils.SetMetaDataLabel(&obj, "test", "abc")
-		utils.SetMetaDataLabel(&obj, "test", "def")
+	assert.Equal(t, []string{"a", "b"}, utils.ProjectsCreatedBy(projects, "user@example.com"))
+	assert.Empty(t, utils.ProjectsCreatedBy(projects, "nobody@example.com"))
+}
 
-		assert.Equal(t, map[string]string{"test": "def"}, obj.GetLabels())
-	})
-	t.Run("doesn't modify other labels", func(t *testing.T) {
-		var obj metav1.ObjectMeta
+func TestClusterRoleNames(t *testing.T) {
+	longName := strings.Repeat("a", 55)
+	tests := []struct {
+		description string
+		name        string
+		legacy      string
+		expected    string
+	}{
+		{
+			description: "appends a hash of the legacy name to a project ClusterRole",
+			name:        utils.ClusterRoleForEntityAndRole(newTestProject("sample"), pwv1alpha1.ProjectRoleAdmin),
+			legacy:      utils.LegacyClusterRoleForEntityAndRole(newTestProject("sample"), pwv1alpha1.ProjectRoleAdmin),
+			expected:    "project:sample:admin-bd6e7afbcb",
+		},
+		{
+			description: "appends a hash of the legacy name to a workspace ClusterRole",
+			name:        utils.ClusterRoleForEntityAndRoleWithParent(newTestWorkspace("project-sample", "dev"), pwv1alpha1.WorkspaceRoleView, newTestProject("sample")),
+			legacy:      utils.LegacyClusterRoleForEntityAndRoleWithParent(newTestWorkspace("project-sample", "dev"), pwv1alpha1.WorkspaceRoleView, newTestProject("sample")),
+			expected:    "project:sample:workspace:dev:view-e41a2f14a5",
+		},
+		{
+			description: "truncates long names",
+			name:        utils.ClusterRoleForEntityAndRoleWithParent(newTestWorkspace("project-"+longName, longName), pwv1alpha1.WorkspaceRoleAdmin, newTestProject(longName)),
+			legacy:      utils.LegacyClusterRoleForEntityAndRoleWithParent(newTestWorkspace("project-"+longName, longName), pwv1alpha1.WorkspaceRoleAdmin, newTestProject(longName)),
+		},
+	}
 
-		utils.SetMetaDataLabel(&obj, "a", "abc")
-		utils.SetMetaDataLabel(&obj, "b", "def")
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			assert.LessOrEqual(t, len(test.name), utils.MaxClusterRoleNameLength)
+			assert.NotEqual(t, test.legacy, test.name)
+			if test.expected != "" {
+				assert.Equal(t, test.expected, test.name)
+			}
+		})
+	}
 
-		assert.Equal(t, map[string]string{"a": "abc", "b": "def"}, obj.GetLabels())
+	t.Run("does not collide for names which only differ in the truncated part", func(t *testing.T) {
+		admin := utils.ClusterRoleForEntityAndRoleWithParent(newTestWorkspace("project-"+longName, longName), pwv1alpha1.WorkspaceRoleAdmin, newTestProject(longName))
+		view := utils.ClusterRoleForEntityAndRoleWithParent(newTestWorkspace("project-"+longName, longName), pwv1alpha1.WorkspaceRoleView, newTestProject(longName))
+		assert.NotEqual(t, admin, view)
+		assert.Equal(t, admin[:52], view[:52], "the readable prefix is expected to be truncated")
 	})
 }
@@ -0,0 +1,236 @@
+// Package webhookconfig manages the ValidatingWebhookConfigurations and MutatingWebhookConfigurations of the platform service on the onboarding cluster.
+// Multiple instances of the platform service with different environments can watch the same onboarding cluster,
+// so the names of the configurations are scoped by the provider name and the environment of the instance,
+// and the validating webhooks only match resources which are labeled for the environment of the instance.
+// The mutating webhooks match all resources, since they label the resources without environment label.
+package webhookconfig
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	"github.com/openmcp-project/platform-service-project-workspace/api/v2/metadata"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+)
+
+// Scope identifies the platform service instance to which webhook configurations belong.
+type Scope struct {
+	ProviderName string
+	Environment  string
+}
+
+// Names returns the names of the validating and mutating webhook configurations of the instance for the given type.
+func (s Scope) Names(gvk schema.GroupVersionKind) (validating, mutating string) {
+	validating, mutating = LegacyNames(gvk)
+	prefix := s.ProviderName + "-" + s.Environment + "-"
+	return prefix + validating, prefix + mutating
+}
+
+// ObjectSelector returns the selector which restricts the validating webhooks to resources which are labeled for the environment of the instance.
+func (s Scope) ObjectSelector() *metav1.LabelSelector {
+	return &metav1.LabelSelector{
+		MatchLabels: map[string]string{utils.LabelEnvironment: s.Environment},
+	}
+}
+
+// LegacyNames returns the names of the webhook configurations for the given type which have been created before the names were scoped.
+// They are shared by all instances watching the same onboarding cluster.
+func LegacyNames(gvk schema.GroupVersionKind) (validating, mutating string) {
+	suffix := typeSuffix(gvk)
+	return "validate-" + suffix, "mutate-" + suffix
+}
+
+// typeSuffix returns the suffix of the names and paths of the webhooks for the given type.
+func typeSuffix(gvk schema.GroupVersionKind) string {
+	return strings.ReplaceAll(gvk.Group, ".", "-") + "-" + gvk.Version + "-" + strings.ToLower(gvk.Kind)
+}
+
+// Installer creates, updates, and deletes the webhook configurations of a platform service instance.
+type Installer struct {
+	Scope
+	// Client is the client for the onboarding cluster.
+	Client client.Client
+	// Service is the service which exposes the webhooks. It is used if BaseURL is empty.
+	Service types.NamespacedName
+	// ServicePort is the port of the service.
+	ServicePort int32
+	// BaseURL is the URL under which the webhooks are exposed, if the onboarding cluster cannot reach the service.
+	BaseURL string
+	// CABundle is the CA bundle which the API server uses to verify the certificate of the webhook server.
+	CABundle []byte
+}
+
+// Install creates or updates the webhook configurations for the given types.
+// The mutating webhooks are installed first, then the existing resources without environment label are labeled for the environment of the instance,
+// so that no resource is left which the validating webhooks do not match. Resources created in between are labeled by the mutating webhooks.
+// Configurations with the legacy names are removed if they belong to this instance, since they would call the webhooks a second time.
+func (i *Installer) Install(ctx context.Context, gvks ...schema.GroupVersionKind) error {
+	log := logging.FromContextOrDiscard(ctx)
+	for _, gvk := range gvks {
+		validating, mutating := i.Names(gvk)
+		mwc := &admissionregistrationv1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: mutating}}
+		result, err := controllerutil.CreateOrUpdate(ctx, i.Client, mwc, func() error {
+			mwc.Webhooks = []admissionregistrationv1.MutatingWebhook{{
+				Name:                    strings.ToLower("m" + gvk.Kind + "." + gvk.Group),
+				FailurePolicy:           ptr.To(admissionregistrationv1.Fail),
+				SideEffects:             ptr.To(admissionregistrationv1.SideEffectClassNone),
+				AdmissionReviewVersions: []string{"v1"},
+				ClientConfig:            i.clientConfig("/mutate-" + typeSuffix(gvk)),
+				Rules:                   rules(gvk, admissionregistrationv1.Create, admissionregistrationv1.Update),
+			}}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create or update MutatingWebhookConfiguration '%s': %w", mutating, err)
+		}
+		log.Info("MutatingWebhookConfiguration", "name", mutating, "result", result)
+
+		if err := i.labelExisting(ctx, gvk); err != nil {
+			return err
+		}
+
+		vwc := &admissionregistrationv1.ValidatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: validating}}
+		result, err = controllerutil.CreateOrUpdate(ctx, i.Client, vwc, func() error {
+			vwc.Webhooks = []admissionregistrationv1.ValidatingWebhook{{
+				Name:                    strings.ToLower("v" + gvk.Kind + "." + gvk.Group),
+				FailurePolicy:           ptr.To(admissionregistrationv1.Fail),
+				SideEffects:             ptr.To(admissionregistrationv1.SideEffectClassNone),
+				AdmissionReviewVersions: []string{"v1"},
+				ClientConfig:            i.clientConfig("/validate-" + typeSuffix(gvk)),
+				Rules:                   rules(gvk, admissionregistrationv1.Create, admissionregistrationv1.Update, admissionregistrationv1.Delete),
+				ObjectSelector:          i.ObjectSelector(),
+			}}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create or update ValidatingWebhookConfiguration '%s': %w", validating, err)
+		}
+		log.Info("ValidatingWebhookConfiguration", "name", validating, "result", result)
+	}
+	return i.removeLegacy(ctx, gvks)
+}
+
+// labelExisting sets the environment label of the instance on all resources of the given type which do not have an environment label yet,
+// e.g. the ones which have been created before the webhooks were restricted to an environment.
+func (i *Installer) labelExisting(ctx context.Context, gvk schema.GroupVersionKind) error {
+	log := logging.FromContextOrDiscard(ctx)
+	list := &metav1.PartialObjectMetadataList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := i.Client.List(ctx, list); err != nil {
+		return fmt.Errorf("failed to list %s resources: %w", gvk.Kind, err)
+	}
+	count := 0
+	for j := range list.Items {
+		obj := &list.Items[j]
+		if obj.GetLabels()[utils.LabelEnvironment] != "" {
+			continue
+		}
+		old := obj.DeepCopy()
+		metadata.SetLabel(obj, utils.LabelEnvironment, i.Environment)
+		if err := i.Client.Patch(ctx, obj, client.MergeFrom(old)); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to set environment label on %s '%s': %w", gvk.Kind, client.ObjectKeyFromObject(obj), err)
+		}
+		count++
+	}
+	log.Info("Labeled existing resources for the environment", "kind", gvk.Kind, "environment", i.Environment, "count", count)
+	return nil
+}
+
+// Uninstall deletes the webhook configurations for the given types, including the ones with the legacy names which belong to this instance.
+func (i *Installer) Uninstall(ctx context.Context, gvks ...schema.GroupVersionKind) error {
+	log := logging.FromContextOrDiscard(ctx)
+	for _, gvk := range gvks {
+		validating, mutating := i.Names(gvk)
+		if err := client.IgnoreNotFound(i.Client.Delete(ctx, &admissionregistrationv1.ValidatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: validating}})); err != nil {
+			return fmt.Errorf("failed to delete ValidatingWebhookConfiguration '%s': %w", validating, err)
+		}
+		if err := client.IgnoreNotFound(i.Client.Delete(ctx, &admissionregistrationv1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: mutating}})); err != nil {
+			return fmt.Errorf("failed to delete MutatingWebhookConfiguration '%s': %w", mutating, err)
+		}
+		log.Info("Removed webhook configurations", "validating", validating, "mutating", mutating)
+	}
+	return i.removeLegacy(ctx, gvks)
+}
+
+// removeLegacy deletes the webhook configurations with the legacy names for the given types, if their webhooks call this instance.
+// Configurations which call another instance are kept, that instance removes them once it has been updated.
+func (i *Installer) removeLegacy(ctx context.Context, gvks []schema.GroupVersionKind) error {
+	log := logging.FromContextOrDiscard(ctx)
+	for _, gvk := range gvks {
+		validating, mutating := LegacyNames(gvk)
+		vwc := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+		if err := i.Client.Get(ctx, client.ObjectKey{Name: validating}, vwc); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return fmt.Errorf("failed to get ValidatingWebhookConfiguration '%s': %w", validating, err)
+			}
+		} else if len(vwc.Webhooks) > 0 && i.calls(vwc.Webhooks[0].ClientConfig) {
+			log.Info("Removing ValidatingWebhookConfiguration with legacy name", "name", validating)
+			if err := client.IgnoreNotFound(i.Client.Delete(ctx, vwc)); err != nil {
+				return fmt.Errorf("failed to delete ValidatingWebhookConfiguration '%s': %w", validating, err)
+			}
+		}
+
+		mwc := &admissionregistrationv1.MutatingWebhookConfiguration{}
+		if err := i.Client.Get(ctx, client.ObjectKey{Name: mutating}, mwc); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return fmt.Errorf("failed to get MutatingWebhookConfiguration '%s': %w", mutating, err)
+			}
+		} else if len(mwc.Webhooks) > 0 && i.calls(mwc.Webhooks[0].ClientConfig) {
+			log.Info("Removing MutatingWebhookConfiguration with legacy name", "name", mutating)
+			if err := client.IgnoreNotFound(i.Client.Delete(ctx, mwc)); err != nil {
+				return fmt.Errorf("failed to delete MutatingWebhookConfiguration '%s': %w", mutating, err)
+			}
+		}
+	}
+	return nil
+}
+
+// clientConfig returns the client config for the webhook with the given path.
+func (i *Installer) clientConfig(path string) admissionregistrationv1.WebhookClientConfig {
+	res := admissionregistrationv1.WebhookClientConfig{
+		CABundle: i.CABundle,
+	}
+	if i.BaseURL != "" {
+		res.URL = ptr.To(i.BaseURL + path)
+	} else {
+		res.Service = &admissionregistrationv1.ServiceReference{
+			Name:      i.Service.Name,
+			Namespace: i.Service.Namespace,
+			Path:      ptr.To(path),
+			Port:      ptr.To(i.ServicePort),
+		}
+	}
+	return res
+}
+
+// calls returns true if the given client config calls the webhooks of this instance.
+func (i *Installer) calls(cc admissionregistrationv1.WebhookClientConfig) bool {
+	if cc.URL != nil {
+		return i.BaseURL != "" && strings.HasPrefix(*cc.URL, i.BaseURL+"/")
+	}
+	return cc.Service != nil && cc.Service.Name == i.Service.Name && cc.Service.Namespace == i.Service.Namespace
+}
+
+// rules returns the rules which match the given type for the given operations.
+func rules(gvk schema.GroupVersionKind, operations ...admissionregistrationv1.OperationType) []admissionregistrationv1.RuleWithOperations {
+	return []admissionregistrationv1.RuleWithOperations{{
+		Operations: operations,
+		Rule: admissionregistrationv1.Rule{
+			APIGroups:   []string{gvk.Group},
+			APIVersions: []string{gvk.Version},
+			Resources:   []string{strings.ToLower(gvk.Kind) + "s"},
+		},
+	}}
+}
@@ -0,0 +1,187 @@
+package webhookconfig_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/webhookconfig"
+)
+
+var (
+	testGVK     = schema.GroupVersionKind{Group: "core.openmcp.cloud", Version: "v1alpha1", Kind: "Project"}
+	testService = types.NamespacedName{Name: "project-workspace-webhook", Namespace: "openmcp-system"}
+)
+
+func TestNames(t *testing.T) {
+	validating, mutating := webhookconfig.Scope{ProviderName: "project-workspace", Environment: "dev"}.Names(testGVK)
+	assert.Equal(t, "project-workspace-dev-validate-core-openmcp-cloud-v1alpha1-project", validating)
+	assert.Equal(t, "project-workspace-dev-mutate-core-openmcp-cloud-v1alpha1-project", mutating)
+
+	validating, mutating = webhookconfig.LegacyNames(testGVK)
+	assert.Equal(t, "validate-core-openmcp-cloud-v1alpha1-project", validating)
+	assert.Equal(t, "mutate-core-openmcp-cloud-v1alpha1-project", mutating)
+}
+
+// newClient returns a fake client for the onboarding cluster with the given objects.
+func newClient(objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(pwv1alpha1.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+// legacyConfigs returns the webhook configurations with the legacy names, whose webhooks call the given service.
+func legacyConfigs(service types.NamespacedName) []client.Object {
+	validating, mutating := webhookconfig.LegacyNames(testGVK)
+	cc := admissionregistrationv1.WebhookClientConfig{Service: &admissionregistrationv1.ServiceReference{Name: service.Name, Namespace: service.Namespace}}
+	return []client.Object{
+		&admissionregistrationv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: validating},
+			Webhooks:   []admissionregistrationv1.ValidatingWebhook{{Name: "vproject.core.openmcp.cloud", ClientConfig: cc}},
+		},
+		&admissionregistrationv1.MutatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: mutating},
+			Webhooks:   []admissionregistrationv1.MutatingWebhook{{Name: "mproject.core.openmcp.cloud", ClientConfig: cc}},
+		},
+	}
+}
+
+func TestInstall(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should create the configurations of the environment", func(t *testing.T) {
+		c := newClient()
+		i := &webhookconfig.Installer{
+			Scope:       webhookconfig.Scope{ProviderName: "project-workspace", Environment: "dev"},
+			Client:      c,
+			Service:     testService,
+			ServicePort: 443,
+			CABundle:    []byte("ca"),
+		}
+		require.NoError(t, i.Install(ctx, testGVK))
+
+		validating, mutating := i.Names(testGVK)
+		vwc := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+		require.NoError(t, c.Get(ctx, client.ObjectKey{Name: validating}, vwc))
+		require.Len(t, vwc.Webhooks, 1)
+		assert.Equal(t, "vproject.core.openmcp.cloud", vwc.Webhooks[0].Name)
+		assert.Equal(t, []byte("ca"), vwc.Webhooks[0].ClientConfig.CABundle)
+		assert.Equal(t, &admissionregistrationv1.ServiceReference{
+			Name:      testService.Name,
+			Namespace: testService.Namespace,
+			Path:      ptr.To("/validate-core-openmcp-cloud-v1alpha1-project"),
+			Port:      ptr.To[int32](443),
+		}, vwc.Webhooks[0].ClientConfig.Service)
+		assert.Equal(t, []string{"projects"}, vwc.Webhooks[0].Rules[0].Resources)
+		assert.Equal(t, &metav1.LabelSelector{MatchLabels: map[string]string{utils.LabelEnvironment: "dev"}}, vwc.Webhooks[0].ObjectSelector)
+
+		mwc := &admissionregistrationv1.MutatingWebhookConfiguration{}
+		require.NoError(t, c.Get(ctx, client.ObjectKey{Name: mutating}, mwc))
+		require.Len(t, mwc.Webhooks, 1)
+		assert.Equal(t, "mproject.core.openmcp.cloud", mwc.Webhooks[0].Name)
+		assert.Equal(t, []admissionregistrationv1.OperationType{admissionregistrationv1.Create, admissionregistrationv1.Update}, mwc.Webhooks[0].Rules[0].Operations)
+		assert.Nil(t, mwc.Webhooks[0].ObjectSelector, "the mutating webhook has to label resources of all environments")
+	})
+
+	t.Run("should use the base URL", func(t *testing.T) {
+		c := newClient()
+		i := &webhookconfig.Installer{
+			Scope:   webhookconfig.Scope{ProviderName: "project-workspace", Environment: "dev"},
+			Client:  c,
+			BaseURL: "https://pwo-webhooks.example.com:443",
+		}
+		require.NoError(t, i.Install(ctx, testGVK))
+
+		validating, _ := i.Names(testGVK)
+		vwc := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+		require.NoError(t, c.Get(ctx, client.ObjectKey{Name: validating}, vwc))
+		assert.Equal(t, ptr.To("https://pwo-webhooks.example.com:443/validate-core-openmcp-cloud-v1alpha1-project"), vwc.Webhooks[0].ClientConfig.URL)
+		assert.Nil(t, vwc.Webhooks[0].ClientConfig.Service)
+	})
+
+	t.Run("should label the existing resources without environment", func(t *testing.T) {
+		c := newClient(
+			&pwv1alpha1.Project{ObjectMeta: metav1.ObjectMeta{Name: "unlabeled"}},
+			&pwv1alpha1.Project{ObjectMeta: metav1.ObjectMeta{Name: "other", Labels: map[string]string{utils.LabelEnvironment: "prod"}}},
+		)
+		i := &webhookconfig.Installer{
+			Scope:   webhookconfig.Scope{ProviderName: "project-workspace", Environment: "dev"},
+			Client:  c,
+			Service: testService,
+		}
+		require.NoError(t, i.Install(ctx, testGVK))
+
+		for name, expected := range map[string]string{"unlabeled": "dev", "other": "prod"} {
+			project := &pwv1alpha1.Project{}
+			require.NoError(t, c.Get(ctx, client.ObjectKey{Name: name}, project))
+			assert.Equal(t, expected, project.Labels[utils.LabelEnvironment], "environment of project %s", name)
+		}
+	})
+
+	t.Run("should only remove the legacy configurations which call this instance", func(t *testing.T) {
+		validating, mutating := webhookconfig.LegacyNames(testGVK)
+		for _, tt := range []struct {
+			service types.NamespacedName
+			removed bool
+		}{
+			{service: testService, removed: true},
+			{service: types.NamespacedName{Name: "project-workspace-webhook", Namespace: "other-system"}, removed: false},
+		} {
+			c := newClient(legacyConfigs(tt.service)...)
+			i := &webhookconfig.Installer{
+				Scope:   webhookconfig.Scope{ProviderName: "project-workspace", Environment: "dev"},
+				Client:  c,
+				Service: testService,
+			}
+			require.NoError(t, i.Install(ctx, testGVK))
+
+			err := c.Get(ctx, client.ObjectKey{Name: validating}, &admissionregistrationv1.ValidatingWebhookConfiguration{})
+			assert.Equal(t, tt.removed, apierrors.IsNotFound(err), "validating webhook configuration of service %s", tt.service)
+			err = c.Get(ctx, client.ObjectKey{Name: mutating}, &admissionregistrationv1.MutatingWebhookConfiguration{})
+			assert.Equal(t, tt.removed, apierrors.IsNotFound(err), "mutating webhook configuration of service %s", tt.service)
+		}
+	})
+}
+
+func TestUninstall(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(legacyConfigs(testService)...)
+	other := &webhookconfig.Installer{
+		Scope:   webhookconfig.Scope{ProviderName: "project-workspace", Environment: "prod"},
+		Client:  c,
+		Service: types.NamespacedName{Name: "project-workspace-webhook", Namespace: "prod-system"},
+	}
+	require.NoError(t, other.Install(ctx, testGVK))
+	i := &webhookconfig.Installer{
+		Scope:   webhookconfig.Scope{ProviderName: "project-workspace", Environment: "dev"},
+		Client:  c,
+		Service: testService,
+	}
+	require.NoError(t, i.Install(ctx, testGVK))
+
+	require.NoError(t, i.Uninstall(ctx, testGVK))
+	vwcs := &admissionregistrationv1.ValidatingWebhookConfigurationList{}
+	require.NoError(t, c.List(ctx, vwcs))
+	require.Len(t, vwcs.Items, 1, "only the configuration of the other environment should be left")
+	validating, _ := other.Names(testGVK)
+	assert.Equal(t, validating, vwcs.Items[0].Name)
+	mwcs := &admissionregistrationv1.MutatingWebhookConfigurationList{}
+	require.NoError(t, c.List(ctx, mwcs))
+	require.Len(t, mwcs.Items, 1, "only the configuration of the other environment should be left")
+}
@@ -3,60 +3,449 @@ package webhooks
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	authv1 "k8s.io/api/authentication/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/api/v2/metadata"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/features"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
 )
 
 var (
-	// errCreatedByImmutable is the error that is returned when the value of the resource creator annotation has been changed by the user.
-	errCreatedByImmutable = fmt.Errorf("annotation %s is immutable", pwv1alpha1.CreatedByAnnotation)
+	// errAnnotationImmutable is the error that is returned when the value of an immutable annotation, e.g. the one of the resource creator, has been changed by the user.
+	errAnnotationImmutable = func(key string) error {
+		return fmt.Errorf("annotation %s is immutable", key)
+	}
+
+	// errLabelImmutable is the error that is returned when the value of an immutable label, e.g. the environment label, has been changed by the user.
+	errLabelImmutable = func(key string) error {
+		return fmt.Errorf("label %s is immutable", key)
+	}
+
+	// errCreatedBySpoofed is the error that is returned when a new resource names someone other than the requesting user as its creator.
+	errCreatedBySpoofed = func(createdBy, username string) error {
+		return fmt.Errorf("annotation %s must contain the requesting user %s, but contains %s", pwv1alpha1.CreatedByAnnotation, username, createdBy)
+	}
 
 	// errRequestingUserNoAccess is the error that is returned when the user who is creating/updating a project or workspace would lock themselves out.
 	errRequestingUserNoAccess = func(username string) error {
 		return fmt.Errorf("requesting user %s will not be able to manage the created/updated resource. please check the list of members again or use MemberOverrides", username)
 	}
+
+	// errNamespaceNotManagedByProject is the error that is returned when a workspace is created in a namespace which does not belong to a project.
+	errNamespaceNotManagedByProject = func(namespace string) error {
+		return fmt.Errorf("namespace %s is not managed by a project. workspaces must be created in the namespace of a project, which can be found in the project's status", namespace)
+	}
+
+	// errWorkspaceClassNotFound is the error that is returned when a workspace selects a WorkspaceClass which does not exist.
+	errWorkspaceClassNotFound = func(className string) error {
+		return fmt.Errorf("WorkspaceClass %s does not exist", className)
+	}
+
+	// errUnknownRoles is the error that is returned when a project or workspace contains roles which are not known to this version of the platform service.
+	// This usually happens if a client or the CRDs are newer than the platform service, which would otherwise ignore these roles when generating the RBAC resources.
+	errUnknownRoles = func(unknown []string) error {
+		return fmt.Errorf("unknown roles: %s. they might have been introduced by a newer version of the platform service, which is not deployed yet", strings.Join(unknown, ", "))
+	}
+
+	// errServiceAccountNamespacesNotAllowed is the error that is returned when a workspace contains ServiceAccount members from namespaces which are not allowed by the config.
+	errServiceAccountNamespacesNotAllowed = func(project string, subjects []string) error {
+		return fmt.Errorf("ServiceAccounts %s are not allowed as members, because their namespaces do not belong to project %s", strings.Join(subjects, ", "), project)
+	}
+
+	// errServiceAccountNamespacesDenied is the error that is returned when ServiceAccounts from namespaces which are denied by the config are added as members.
+	errServiceAccountNamespacesDenied = func(resource string, subjects []string) error {
+		return fmt.Errorf("ServiceAccounts %s cannot be added as members of a %s, because their namespaces are denied by the platform operators. only admins by member override can add them", strings.Join(subjects, ", "), resource)
+	}
+
+	// errChargingTargetRequired is the error that is returned when a project without charging target is created, although the config requires one.
+	errChargingTargetRequired = fmt.Errorf("annotation %s is required", pwv1alpha1.ChargingTargetAnnotation)
+
+	// errChargingTargetInvalid is the error that is returned when the charging target of a project or workspace is not valid according to the config.
+	errChargingTargetInvalid = func(value, reason string) error {
+		return fmt.Errorf("invalid value '%s' of annotation %s: %s", value, pwv1alpha1.ChargingTargetAnnotation, reason)
+	}
+
+	// errTooManyMembers is the error that is returned when a project or workspace would have more members than allowed by the config.
+	errTooManyMembers = func(resource string, count int, maxMembers int32) error {
+		return fmt.Errorf("%s has %d members, but at most %d are allowed. grant access to groups instead of individual members", resource, count, maxMembers)
+	}
+
+	// errUserMembersNotAllowed is the error that is returned when User members are added to a project or workspace, although the config only allows groups.
+	errUserMembersNotAllowed = func(resource string, users []string) error {
+		return fmt.Errorf("users %s cannot be added as members, access to a %s must be granted via groups", strings.Join(users, ", "), resource)
+	}
+
+	// errInheritedAdminsRemoved is the error that is returned when a workspace update would remove the admin role from project members who inherited it.
+	errInheritedAdminsRemoved = func(subjects []string) error {
+		return fmt.Errorf("the update would remove the inherited admin role from project members %s. only project admins can do this", strings.Join(subjects, ", "))
+	}
+
+	// errDeletionBlocked is the error that is returned when a workspace is deleted while its namespace still contains resources blocking the deletion.
+	errDeletionBlocked = func(namespace string, blockers []string) error {
+		return fmt.Errorf("namespace %s still contains resources which block the deletion: %s. delete them first", namespace, strings.Join(blockers, ", "))
+	}
+
+	// errDisplayNameInvalid is the error that is returned when the display name of a resource does not meet the formal requirements.
+	errDisplayNameInvalid = func(value, reason string) error {
+		return fmt.Errorf("invalid value '%s' of annotation %s: %s", value, pwv1alpha1.DisplayNameAnnotation, reason)
+	}
+
+	// errProjectLabelMismatch is the error that is returned when a project is labeled with the name of another project.
+	errProjectLabelMismatch = func(project, value string) error {
+		return fmt.Errorf("label %s must match the name of project %s, but contains %s. a project cannot be repurposed for another one", utils.LabelProject, project, value)
+	}
+
+	// errSecretStoreNotConfigured is the error that is returned when a project references a secret store which is not configured.
+	errSecretStoreNotConfigured = func(store string) error {
+		return fmt.Errorf("secret store %s is not configured, ask the platform operators for the available stores", store)
+	}
+
+	// errResourceBlockingDeletionNotAllowed is the error that is returned when a project adds a resource type blocking its deletion which is not allowed in the config.
+	errResourceBlockingDeletionNotAllowed = func(gvk string) error {
+		return fmt.Errorf("resource type %s is not allowed to block the deletion of projects, ask the platform operators for the allowed types", gvk)
+	}
+
+	// errSecretStorePathNotAllowed is the error that is returned when a project references a path of a secret store which is not allowed for it.
+	errSecretStorePathNotAllowed = func(store, path string) error {
+		return fmt.Errorf("path '%s' of secret store %s is not allowed for this project", path, store)
+	}
+
+	// errProviderHintNotAvailable is the error that is returned when a workspace sets a provider hint which is not configured or whose ServiceProvider is not registered.
+	errProviderHintNotAvailable = func(key string) error {
+		return fmt.Errorf("provider hint %s is not available, ask the platform operators for the available hints", key)
+	}
+
+	// errProviderHintValueNotAllowed is the error that is returned when a workspace sets a value for a provider hint which is not allowed by the config.
+	errProviderHintValueNotAllowed = func(key, value string, allowed []string) error {
+		return fmt.Errorf("value '%s' of provider hint %s is not allowed, must be one of %s", value, key, strings.Join(allowed, ", "))
+	}
+
+	// errManagedMembersChanged is the error that is returned when members of a project or workspace which are synced from a ProjectMembershipSource are changed manually.
+	errManagedMembersChanged = func(source string, subjects []string) error {
+		return fmt.Errorf("members %s are synced from ProjectMembershipSource %s and can only be changed in its source", strings.Join(subjects, ", "), source)
+	}
+
+	// errMembershipSourceMarkerChanged is the error that is returned when the label or annotation which mark synced members are changed manually.
+	errMembershipSourceMarkerChanged = func(key string) error {
+		return fmt.Errorf("%s is managed by the platform service and cannot be changed", key)
+	}
+
+	// errProjectQuotaExceeded is the error that is returned when a user creates a project although they have already created as many projects as they are allowed to.
+	errProjectQuotaExceeded = func(username string, limit int32) error {
+		return fmt.Errorf("user %s has already created the maximum number of %d projects, delete unused projects or ask the platform operators to raise the limit", username, limit)
+	}
+
+	// errOwnerNotAdmin is the error that is returned when a project is deleted whose creator is no admin of it anymore and whose ownership has not been transferred.
+	errOwnerNotAdmin = func(owner string) error {
+		return fmt.Errorf("the creator %s of the project is no admin anymore. an admin granted by member overrides has to transfer the ownership to a current admin via annotation %s before the project can be deleted", owner, pwv1alpha1.TransferOwnershipAnnotation)
+	}
+
+	// errForegroundDeletion is the error that is returned when a project is deleted with foreground propagation, which would delete its namespace without the checks of the project controller.
+	errForegroundDeletion = fmt.Errorf("projects cannot be deleted with propagation policy %s, use %s instead", metav1.DeletePropagationForeground, metav1.DeletePropagationBackground)
+
+	// errOwnershipTransferNotAllowed is the error that is returned when a user who is no admin by member override requests the transfer of the ownership of a project.
+	errOwnershipTransferNotAllowed = func(username string) error {
+		return fmt.Errorf("user %s cannot transfer the ownership of the project, only admins granted by member overrides can set annotation %s", username, pwv1alpha1.TransferOwnershipAnnotation)
+	}
+
+	// errNewOwnerNotAdmin is the error that is returned when the ownership of a project is transferred to a user who is no admin of the project.
+	errNewOwnerNotAdmin = func(newOwner string) error {
+		return fmt.Errorf("the ownership of the project cannot be transferred to %s, who is no admin of the project", newOwner)
+	}
+
+	// errInvitationTargetNotFound is the error that is returned when an invitation is created for a project or workspace which does not exist.
+	errInvitationTargetNotFound = func(resource, name string) error {
+		return fmt.Errorf("%s %s does not exist", resource, name)
+	}
+
+	// errInvitationNotAllowed is the error that is returned when a user who is no admin of a project or workspace creates or changes an invitation to it.
+	errInvitationNotAllowed = func(username, resource, name string) error {
+		return fmt.Errorf("user %s cannot manage invitations to %s %s, only its admins can", username, resource, name)
+	}
+
+	// errInvitationAcceptedOnCreate is the error that is returned when an invitation is created which is already accepted.
+	errInvitationAcceptedOnCreate = fmt.Errorf("annotation %s cannot be set on creation, only the invited user can accept an invitation", pwv1alpha1.InvitationAcceptAnnotation)
+
+	// errNotInvited is the error that is returned when a user accepts an invitation for someone else.
+	errNotInvited = func(username string) error {
+		return fmt.Errorf("user %s is not invited and cannot accept the invitation", username)
+	}
+
+	// errInvitationNotPending is the error that is returned when an invitation is accepted which has already been completed or has expired.
+	errInvitationNotPending = fmt.Errorf("the invitation cannot be accepted anymore, ask an admin for a new one")
+
+	// errAcceptanceRevoked is the error that is returned when the acceptance of an invitation is removed.
+	errAcceptanceRevoked = fmt.Errorf("annotation %s cannot be removed once the invitation has been accepted", pwv1alpha1.InvitationAcceptAnnotation)
+
+	// errAcceptedBySpoofed is the error that is returned when an invitation is accepted on behalf of another user.
+	errAcceptedBySpoofed = func(acceptedBy, username string) error {
+		return fmt.Errorf("annotation %s must contain the requesting user %s, but contains %s", pwv1alpha1.InvitationAcceptedByAnnotation, username, acceptedBy)
+	}
 )
 
-// compareStringMapValue compares the value of string values identified by a key in two maps.
-// Returns "true" if the value is the same.
-func compareStringMapValue(a, b map[string]string, key string) bool {
-	return a[key] == b[key]
-}
+// maxDisplayNameLength is the maximum number of characters of a display name.
+const maxDisplayNameLength = 64
 
-// verifyCreatedByUnchanged checks if the value of the annotation that contains the name of the resource creator has been changed.
-// Returns an error if the value has been changed or "nil" if it's the same.
-func verifyCreatedByUnchanged(old, new metav1.Object) error {
-	if compareStringMapValue(old.GetAnnotations(), new.GetAnnotations(), pwv1alpha1.CreatedByAnnotation) {
-		return nil
+// verifyImmutableAnnotationsUnchanged checks if the value of any immutable annotation, e.g. the one that contains the name of the resource creator, has been changed.
+// Returns an error for the first changed annotation or "nil" if all of them are the same.
+func verifyImmutableAnnotationsUnchanged(old, new metav1.Object) error {
+	if changed := metadata.ChangedKeys(old.GetAnnotations(), new.GetAnnotations(), pwv1alpha1.ImmutableAnnotations...); len(changed) > 0 {
+		return errAnnotationImmutable(changed[0])
 	}
 
-	return errCreatedByImmutable
+	return nil
 }
 
 // setCreatedBy sets an annotation that contains the name of the user who created the resource.
-// The value is only set when the "Operation" is "Create".
-func setCreatedBy(obj metav1.Object, req admission.Request) {
+// The value is only set when the "Operation" is "Create". A value provided by the user is overwritten, so that nobody can pose as the creator of a resource.
+// Only excluded identities may keep a provided value, since they are trusted to create resources on behalf of other users.
+func setCreatedBy(ctx context.Context, si config.SharedInformation, ownIdentity *OwnIdentity, obj metav1.Object, req admission.Request) error {
 	if req.Operation != admissionv1.Create {
+		return nil
+	}
+
+	if obj.GetAnnotations()[pwv1alpha1.CreatedByAnnotation] != "" {
+		excluded, err := isExcludedIdentity(ctx, si, ownIdentity, req.UserInfo.Username)
+		if err != nil {
+			return err
+		}
+		if excluded {
+			return nil
+		}
+	}
+
+	metadata.SetAnnotation(obj, pwv1alpha1.CreatedByAnnotation, req.UserInfo.Username)
+	return nil
+}
+
+// verifyCreatedByRequester checks that a new resource names the requesting user as its creator.
+// This protects against a modified created-by annotation, e.g. by another mutating webhook, since the defaulter already overwrites provided values.
+// Excluded identities may name other users as creator.
+func verifyCreatedByRequester(ctx context.Context, si config.SharedInformation, ownIdentity *OwnIdentity, obj metav1.Object, username string) error {
+	createdBy := obj.GetAnnotations()[pwv1alpha1.CreatedByAnnotation]
+	if createdBy == username {
+		return nil
+	}
+
+	excluded, err := isExcludedIdentity(ctx, si, ownIdentity, username)
+	if err != nil {
+		return err
+	}
+	if excluded {
+		return nil
+	}
+	return errCreatedBySpoofed(createdBy, username)
+}
+
+// verifyChargingTarget checks the charging target annotation of a new or updated project or workspace against the config.
+// The value is only validated if it is set or changed, so that existing resources can still be updated after the allowed values have changed.
+// If checkRequired is true, the annotation must not be missing on new resources or be removed from existing ones, if the config requires it.
+// oldObj must be nil for new resources.
+func verifyChargingTarget(ctx context.Context, si config.SharedInformation, oldObj, obj metav1.Object, checkRequired bool) error {
+	cfg, err := si.ChargingTarget(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get charging target config: %w", err)
+	}
+
+	value := obj.GetAnnotations()[pwv1alpha1.ChargingTargetAnnotation]
+	oldValue := ""
+	if oldObj != nil {
+		oldValue = oldObj.GetAnnotations()[pwv1alpha1.ChargingTargetAnnotation]
+	}
+	if value == "" {
+		if checkRequired && cfg.Required && (oldObj == nil || oldValue != "") {
+			return errChargingTargetRequired
+		}
+		return nil
+	}
+	if oldObj != nil && value == oldValue {
+		return nil
+	}
+
+	matches, err := cfg.MatchesPattern(value)
+	if err != nil {
+		return err
+	}
+	if !matches {
+		return errChargingTargetInvalid(value, fmt.Sprintf("it does not match the pattern '%s'", cfg.Pattern))
+	}
+	allowed, err := si.AllowedChargingTargets(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get allowed charging targets: %w", err)
+	}
+	if allowed != nil && !allowed.Has(value) {
+		return errChargingTargetInvalid(value, "it is not one of the allowed charging targets")
+	}
+	return nil
+}
+
+// setDisplayName sets the display name annotation of the given resource to its name, if it does not have a display name yet.
+func setDisplayName(obj metav1.Object) {
+	if obj.GetAnnotations()[pwv1alpha1.DisplayNameAnnotation] != "" {
 		return
 	}
 
-	setMetaDataAnnotation(obj, pwv1alpha1.CreatedByAnnotation, req.UserInfo.Username)
+	metadata.SetAnnotation(obj, pwv1alpha1.DisplayNameAnnotation, obj.GetName())
+}
+
+// verifyDisplayName checks the display name annotation of a new or updated resource.
+// A display name must not exceed maxDisplayNameLength characters, must not start or end with whitespace, and must only contain printable characters.
+// The value is only validated if it is set or changed, so that existing resources with a display name from before this check can still be updated.
+// oldObj must be nil for new resources.
+func verifyDisplayName(oldObj, obj metav1.Object) error {
+	value, ok := obj.GetAnnotations()[pwv1alpha1.DisplayNameAnnotation]
+	if !ok {
+		return nil
+	}
+	if oldObj != nil && value == oldObj.GetAnnotations()[pwv1alpha1.DisplayNameAnnotation] {
+		return nil
+	}
+
+	if strings.TrimSpace(value) == "" {
+		return errDisplayNameInvalid(value, "it must not be empty")
+	}
+	if length := utf8.RuneCountInString(value); length > maxDisplayNameLength {
+		return errDisplayNameInvalid(value, fmt.Sprintf("it has %d characters, but at most %d are allowed", length, maxDisplayNameLength))
+	}
+	if strings.TrimSpace(value) != value {
+		return errDisplayNameInvalid(value, "it must not start or end with whitespace")
+	}
+	for _, r := range value {
+		if !unicode.IsPrint(r) {
+			return errDisplayNameInvalid(value, fmt.Sprintf("it contains the non-printable character %U", r))
+		}
+	}
+	return nil
+}
+
+// verifyMemberPolicy checks the members of a new or updated project or workspace against the member policy from the config.
+// On update, only violations which are introduced by the change are rejected: the number of members must not grow beyond the maximum,
+// and User members which have been added are rejected if only groups are allowed. Excluded identities are exempt from the policy.
+// resource is either 'project' or 'workspace', oldMembers must be nil for new resources.
+func verifyMemberPolicy(ctx context.Context, si config.SharedInformation, ownIdentity *OwnIdentity, username, resource string, oldMembers, members []pwv1alpha1.Subject) error {
+	policy, err := si.MemberPolicy(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get member policy: %w", err)
+	}
+	maxMembers := policy.MaxProjectMembers
+	if resource == workspaceResource {
+		maxMembers = policy.MaxWorkspaceMembers
+	}
+
+	tooMany := maxMembers > 0 && len(members) > int(maxMembers) && len(members) > len(oldMembers)
+	var addedUsers []string
+	if policy.GroupsOnly {
+		for _, subject := range members {
+			if subject.Kind == rbacv1.UserKind && !slices.Contains(oldMembers, subject) {
+				addedUsers = append(addedUsers, subject.Name)
+			}
+		}
+	}
+	if !tooMany && len(addedUsers) == 0 {
+		return nil
+	}
+
+	excluded, err := isExcludedIdentity(ctx, si, ownIdentity, username)
+	if err != nil || excluded {
+		return err
+	}
+	if tooMany {
+		return errTooManyMembers(resource, len(members), maxMembers)
+	}
+	return errUserMembersNotAllowed(resource, addedUsers)
+}
+
+// verifyServiceAccountNamespacesNotDenied returns an error if ServiceAccounts from namespaces which are denied by the config, e.g. 'kube-system',
+// have been added to the members of a project or workspace. Excluded identities and admins by member override of the project or workspace are exempt.
+// resource is either 'project' or 'workspace', name and kind identify the project or workspace in the member overrides. oldMembers must be nil for new resources.
+func verifyServiceAccountNamespacesNotDenied(ctx context.Context, si config.SharedInformation, overridesCache *MemberOverridesCache, ownIdentity *OwnIdentity, userInfo authv1.UserInfo,
+	resource, name, kind string, oldMembers, members []pwv1alpha1.Subject) error {
+	policy, err := si.ServiceAccountMembers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get ServiceAccount member restrictions: %w", err)
+	}
+	var denied []string
+	for _, subject := range members {
+		if subject.Kind == rbacv1.ServiceAccountKind && policy.DeniesNamespace(subject.Namespace) && !slices.Contains(oldMembers, subject) {
+			denied = append(denied, fmt.Sprintf("%s/%s", subject.Namespace, subject.Name))
+		}
+	}
+	if len(denied) == 0 {
+		return nil
+	}
+
+	excluded, err := isExcludedIdentity(ctx, si, ownIdentity, userInfo.Username)
+	if err != nil || excluded {
+		return err
+	}
+	overrides, err := getMemberOverrides(ctx, overridesCache, si)
+	if err != nil {
+		return fmt.Errorf("failed to get member overrides: %w", err)
+	}
+	if overrides.HasAdminOverrideForResource(&userInfo, name, kind) {
+		return nil
+	}
+	return errServiceAccountNamespacesDenied(resource, denied)
+}
+
+// verifyManagedMembersUnchanged returns an error if members of a project or workspace which are synced from a ProjectMembershipSource have been
+// removed or their roles have been changed, or if the label and annotation which mark these members have been changed.
+// Such changes have to be made in the source, only excluded identities (like the platform service syncing the source) may make them directly.
+// The roles of the members are passed by subject, since projects and workspaces have different member types. oldObj must be nil for new resources.
+func verifyManagedMembersUnchanged(ctx context.Context, si config.SharedInformation, ownIdentity *OwnIdentity, username string, oldObj, newObj metav1.Object, oldRoles, newRoles map[pwv1alpha1.Subject][]string) error {
+	var oldLabels, oldAnnotations map[string]string
+	var source string
+	var managed []pwv1alpha1.Subject
+	if oldObj != nil {
+		oldLabels, oldAnnotations = oldObj.GetLabels(), oldObj.GetAnnotations()
+		var err error
+		if source, managed, err = utils.ManagedMembers(oldObj); err != nil {
+			return err
+		}
+	}
+	changedMarker := slices.Concat(
+		metadata.ChangedKeys(oldLabels, newObj.GetLabels(), pwv1alpha1.MembershipSourceLabel),
+		metadata.ChangedKeys(oldAnnotations, newObj.GetAnnotations(), pwv1alpha1.ManagedMembersAnnotation),
+	)
+	var changedMembers []string
+	for _, subject := range managed {
+		if !slices.Equal(oldRoles[subject], newRoles[subject]) {
+			changedMembers = append(changedMembers, utils.FormatSubject(subject.RbacV1()))
+		}
+	}
+	if len(changedMarker) == 0 && len(changedMembers) == 0 {
+		return nil
+	}
+
+	excluded, err := isExcludedIdentity(ctx, si, ownIdentity, username)
+	if err != nil || excluded {
+		return err
+	}
+	if len(changedMarker) > 0 {
+		return errMembershipSourceMarkerChanged(changedMarker[0])
+	}
+	return errManagedMembersChanged(source, changedMembers)
 }
 
-// setMetaDataAnnotation sets the annotation on the given object.
-// If the given Object did not yet have annotations, they are initialized.
-func setMetaDataAnnotation(meta metav1.Object, key, value string) { // TODO move to utils package
-	labels := meta.GetAnnotations()
-	if labels == nil {
-		labels = make(map[string]string)
+// sortedRoles returns the given roles as sorted strings, so that the roles of project and workspace members can be compared independent of their order.
+func sortedRoles[R ~string](roles []R) []string {
+	res := make([]string, 0, len(roles))
+	for _, role := range roles {
+		res = append(res, string(role))
 	}
-	labels[key] = value
-	meta.SetAnnotations(labels)
+	slices.Sort(res)
+	return res
 }
 
 // userInfoFromContext extracts the authv1.UserInfo from the admission.Request available in the context. Returns an error if the request can't be found.
@@ -68,3 +457,98 @@ func userInfoFromContext(ctx context.Context) (authv1.UserInfo, error) {
 
 	return req.UserInfo, nil
 }
+
+// isExcludedIdentity returns true if the given username is either the platform service's own identity, one of the selected operator identities,
+// or matches one of the identities which are excluded from validation via the config.
+func isExcludedIdentity(ctx context.Context, si config.SharedInformation, ownIdentity *OwnIdentity, username string) (bool, error) {
+	if ownIdentity.Matches(username) {
+		return true, nil
+	}
+
+	// other replicas of the platform service might use different identities
+	operatorIdentities, err := si.OperatorIdentities(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get operator identities: %w", err)
+	}
+	if operatorIdentities.Has(username) {
+		return true, nil
+	}
+
+	excludedIdentities, err := si.ExcludedWebhookIdentities(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get excluded identities: %w", err)
+	}
+	for _, im := range excludedIdentities {
+		if im.Matches(username) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// shouldAddCreatorAsAdmin returns true if the requesting user should be added as admin to a new project or workspace, because it does not have any admin member.
+// This is only done if it is enabled in the config and by the feature gate. Excluded identities are never added, since they can manage the resource without being a member.
+// Users are not added if the member policy only allows groups, since the validating webhooks would reject them.
+func shouldAddCreatorAsAdmin(ctx context.Context, si config.SharedInformation, ownIdentity *OwnIdentity, req admission.Request, hasAdmin bool) (bool, error) {
+	if req.Operation != admissionv1.Create || hasAdmin || !features.Enabled(features.CreatorAsAdmin) {
+		return false, nil
+	}
+	enabled, err := si.AddCreatorAsAdmin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get webhook config: %w", err)
+	}
+	if !enabled {
+		return false, nil
+	}
+	excluded, err := isExcludedIdentity(ctx, si, ownIdentity, req.UserInfo.Username)
+	if err != nil || excluded {
+		return false, err
+	}
+	if subjectForUsername(req.UserInfo.Username).Kind == rbacv1.UserKind {
+		policy, err := si.MemberPolicy(ctx)
+		if err != nil {
+			return false, fmt.Errorf("failed to get member policy: %w", err)
+		}
+		return !policy.GroupsOnly, nil
+	}
+	return true, nil
+}
+
+// subjectForUsername returns the member subject for the given username.
+// ServiceAccount usernames ('system:serviceaccount:<namespace>:<name>') result in a ServiceAccount subject, all other usernames in a User subject.
+func subjectForUsername(username string) pwv1alpha1.Subject {
+	if nsAndName, ok := strings.CutPrefix(username, "system:serviceaccount:"); ok {
+		if namespace, name, ok := strings.Cut(nsAndName, ":"); ok && namespace != "" && name != "" {
+			return pwv1alpha1.Subject{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      name,
+				Namespace: namespace,
+			}
+		}
+	}
+	return pwv1alpha1.Subject{
+		Kind: rbacv1.UserKind,
+		Name: username,
+	}
+}
+
+// unknownMemberRoles returns a description of each of the given roles of a member which is not contained in the given known roles.
+func unknownMemberRoles[R ~string](subject pwv1alpha1.Subject, roles []R, knownRoles []R) []string {
+	var unknown []string
+	for _, role := range roles {
+		if !slices.Contains(knownRoles, role) {
+			unknown = append(unknown, fmt.Sprintf("role '%s' of %s %s", role, subject.Kind, subject.Name))
+		}
+	}
+	return unknown
+}
+
+// redundantAuditorRoleWarning returns a warning if the given roles contain the given auditor role together with any other role.
+// Since every other role grants at least the permissions of the auditor role, the auditor role is redundant in this case.
+// Returns an empty string if the auditor role is not redundant.
+func redundantAuditorRoleWarning[R ~string](subject pwv1alpha1.Subject, roles []R, auditorRole R) string {
+	if len(roles) < 2 || !slices.Contains(roles, auditorRole) {
+		return ""
+	}
+	return fmt.Sprintf("role '%s' of %s %s is redundant, since the member's other roles already grant read access", auditorRole, subject.Kind, subject.Name)
+}
@@ -9,73 +9,15 @@ import (
 	admissionv1 "k8s.io/api/admission/v1"
 	authv1 "k8s.io/api/authentication/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
 )
 
-func TestCompareStringMapValue(t *testing.T) {
-	tests := []struct {
-		description    string
-		mapA           map[string]string
-		mapB           map[string]string
-		key            string
-		expectedResult bool
-	}{
-		{
-			description: "returns 'true' if both maps contain map entries for the given key with the same value",
-			mapA: map[string]string{
-				"test": "test",
-			},
-			mapB: map[string]string{
-				"test": "test",
-			},
-			key:            "test",
-			expectedResult: true,
-		},
-		{
-			description:    "returns 'true' if both maps don't contain a map entry for the given key",
-			key:            "test",
-			expectedResult: true,
-		},
-		{
-			description: "returns 'false' if both maps contain map entries for the given key but with different values",
-			mapA: map[string]string{
-				"test": "test1",
-			},
-			mapB: map[string]string{
-				"test": "test2",
-			},
-			key: "test",
-		},
-		{
-			description: "returns 'false' if mapA doesn't contain a map entry for the given key",
-			mapB: map[string]string{
-				"test": "test",
-			},
-			key: "test",
-		},
-		{
-			description: "returns false if mapB doesn't contain a map entry for the given key",
-			mapA: map[string]string{
-				"test": "test",
-			},
-			key: "test",
-		},
-	}
-
-	for _, test := range tests {
-		t.Run(test.description, func(t *testing.T) {
-			actualResult := compareStringMapValue(test.mapA, test.mapB, test.key)
-
-			assert.Equal(t, test.expectedResult, actualResult)
-		})
-	}
-
-}
-
-func TestVerifyCreatedByUnchanged(t *testing.T) {
+func TestVerifyImmutableAnnotationsUnchanged(t *testing.T) {
 	tests := []struct {
 		description string
 		objA        metav1.ObjectMeta
@@ -112,11 +54,26 @@ func TestVerifyCreatedByUnchanged(t *testing.T) {
 			},
 			expectError: true,
 		},
+		{
+			description: "returns no error if only other annotations are changed",
+			objA: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					pwv1alpha1.CreatedByAnnotation:   "test",
+					pwv1alpha1.DisplayNameAnnotation: "old",
+				},
+			},
+			objB: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					pwv1alpha1.CreatedByAnnotation:   "test",
+					pwv1alpha1.DisplayNameAnnotation: "new",
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.description, func(t *testing.T) {
-			err := verifyCreatedByUnchanged(&test.objA, &test.objB)
+			err := verifyImmutableAnnotationsUnchanged(&test.objA, &test.objB)
 
 			if test.expectError {
 				assert.Error(t, err)
@@ -128,9 +85,17 @@ func TestVerifyCreatedByUnchanged(t *testing.T) {
 }
 
 func TestSetCreatedBy(t *testing.T) {
+	si := config.NewFakeSharedInformation(nil, nil, nil, nil)
+	si.ExcludedWebhookIdentitiesData = []pwv1alpha1.IdentityMatcher{
+		{
+			Name: "system:serviceaccount:portal:backend",
+		},
+	}
+
 	tests := []struct {
 		description         string
 		request             admissionv1.AdmissionRequest
+		annotations         map[string]string
 		expectedAnnotations map[string]string
 	}{
 		{
@@ -145,6 +110,36 @@ func TestSetCreatedBy(t *testing.T) {
 				pwv1alpha1.CreatedByAnnotation: "john.doe@test.com",
 			},
 		},
+		{
+			description: "overwrites a CreatedBy annotation provided by the user during create",
+			request: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				UserInfo: authv1.UserInfo{
+					Username: "john.doe@test.com",
+				},
+			},
+			annotations: map[string]string{
+				pwv1alpha1.CreatedByAnnotation: "admin",
+			},
+			expectedAnnotations: map[string]string{
+				pwv1alpha1.CreatedByAnnotation: "john.doe@test.com",
+			},
+		},
+		{
+			description: "keeps a CreatedBy annotation provided by an excluded identity during create",
+			request: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				UserInfo: authv1.UserInfo{
+					Username: "system:serviceaccount:portal:backend",
+				},
+			},
+			annotations: map[string]string{
+				pwv1alpha1.CreatedByAnnotation: "john.doe@test.com",
+			},
+			expectedAnnotations: map[string]string{
+				pwv1alpha1.CreatedByAnnotation: "john.doe@test.com",
+			},
+		},
 		{
 			description: "doesn't set the CreatedBy annotation if operation is NOT create",
 			request: admissionv1.AdmissionRequest{
@@ -158,17 +153,76 @@ func TestSetCreatedBy(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.description, func(t *testing.T) {
-			var uut metav1.ObjectMeta
+			uut := metav1.ObjectMeta{
+				Annotations: test.annotations,
+			}
 
-			setCreatedBy(&uut, admission.Request{
+			err := setCreatedBy(context.Background(), si, NewStaticOwnIdentity("system:serviceaccount:pwo:operator"), &uut, admission.Request{
 				AdmissionRequest: test.request,
 			})
 
+			assert.NoError(t, err)
 			assert.Equal(t, test.expectedAnnotations, uut.GetAnnotations())
 		})
 	}
 }
 
+func TestVerifyCreatedByRequester(t *testing.T) {
+	si := config.NewFakeSharedInformation(nil, nil, nil, nil)
+	si.ExcludedWebhookIdentitiesData = []pwv1alpha1.IdentityMatcher{
+		{
+			Name: "system:serviceaccount:portal:backend",
+		},
+	}
+
+	tests := []struct {
+		description string
+		createdBy   string
+		username    string
+		expectError bool
+	}{
+		{
+			description: "returns no error if the requesting user is the creator",
+			createdBy:   "john.doe@test.com",
+			username:    "john.doe@test.com",
+		},
+		{
+			description: "returns an error if another user is named as creator",
+			createdBy:   "admin",
+			username:    "john.doe@test.com",
+			expectError: true,
+		},
+		{
+			description: "returns no error if an excluded identity names another user as creator",
+			createdBy:   "john.doe@test.com",
+			username:    "system:serviceaccount:portal:backend",
+		},
+		{
+			description: "returns no error if the platform service names another user as creator",
+			createdBy:   "john.doe@test.com",
+			username:    "system:serviceaccount:pwo:operator",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			obj := &metav1.ObjectMeta{
+				Annotations: map[string]string{
+					pwv1alpha1.CreatedByAnnotation: test.createdBy,
+				},
+			}
+
+			err := verifyCreatedByRequester(context.Background(), si, NewStaticOwnIdentity("system:serviceaccount:pwo:operator"), obj, test.username)
+
+			if test.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestUserInfoFromContext(t *testing.T) {
 	t.Run("returns the userinfo from the admission.Request in the context", func(t *testing.T) {
 		userInfo := authv1.UserInfo{}
@@ -192,3 +246,479 @@ func TestUserInfoFromContext(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestIsExcludedIdentity(t *testing.T) {
+	si := config.NewFakeSharedInformation(nil, nil, nil, nil)
+	si.ExcludedWebhookIdentitiesData = []pwv1alpha1.IdentityMatcher{
+		{
+			Name: "system:serviceaccount:flux-system:kustomize-controller",
+		},
+		{
+			Prefix: "system:serviceaccount:migration:",
+		},
+	}
+	si.OperatorIdentitiesData = sets.New("system:serviceaccount:pwo-zone-b:operator")
+
+	tests := []struct {
+		description    string
+		username       string
+		expectedResult bool
+	}{
+		{
+			description:    "returns 'true' for the platform service's own identity",
+			username:       "system:serviceaccount:pwo:operator",
+			expectedResult: true,
+		},
+		{
+			description:    "returns 'true' for the identity of another replica of the platform service",
+			username:       "system:serviceaccount:pwo-zone-b:operator",
+			expectedResult: true,
+		},
+		{
+			description:    "returns 'true' for an identity matching by name",
+			username:       "system:serviceaccount:flux-system:kustomize-controller",
+			expectedResult: true,
+		},
+		{
+			description:    "returns 'true' for an identity matching by prefix",
+			username:       "system:serviceaccount:migration:job-1",
+			expectedResult: true,
+		},
+		{
+			description: "returns 'false' for an identity which only shares a prefix with a name entry",
+			username:    "system:serviceaccount:flux-system:kustomize-controller-2",
+		},
+		{
+			description: "returns 'false' for an identity which is not excluded",
+			username:    "admin",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			actualResult, err := isExcludedIdentity(context.Background(), si, NewStaticOwnIdentity("system:serviceaccount:pwo:operator"), test.username)
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, test.expectedResult, actualResult)
+			}
+		})
+	}
+}
+
+func TestRedundantAuditorRoleWarning(t *testing.T) {
+	subject := pwv1alpha1.Subject{Kind: "User", Name: "user@example.com"}
+
+	tests := []struct {
+		description     string
+		roles           []pwv1alpha1.WorkspaceMemberRole
+		expectedWarning bool
+	}{
+		{
+			description: "no warning for the auditor role alone",
+			roles:       []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleAuditor},
+		},
+		{
+			description: "no warning for other roles without the auditor role",
+			roles:       []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleAdmin, pwv1alpha1.WorkspaceRoleView},
+		},
+		{
+			description:     "warning for the auditor role together with another role",
+			roles:           []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleView, pwv1alpha1.WorkspaceRoleAuditor},
+			expectedWarning: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			warning := redundantAuditorRoleWarning(subject, test.roles, pwv1alpha1.WorkspaceRoleAuditor)
+			if test.expectedWarning {
+				assert.Contains(t, warning, subject.Name)
+			} else {
+				assert.Empty(t, warning)
+			}
+		})
+	}
+}
+
+func TestVerifyKnownRoles(t *testing.T) {
+	subject := pwv1alpha1.Subject{Kind: "User", Name: "user@example.com"}
+
+	t.Run("project", func(t *testing.T) {
+		project := &pwv1alpha1.Project{
+			Spec: pwv1alpha1.ProjectSpec{
+				Members: []pwv1alpha1.ProjectMember{
+					{Subject: subject, Roles: []pwv1alpha1.ProjectMemberRole{pwv1alpha1.ProjectRoleAdmin, pwv1alpha1.ProjectRoleAuditor}},
+				},
+			},
+		}
+		assert.NoError(t, verifyKnownProjectRoles(project))
+
+		project.Spec.Members[0].Roles = append(project.Spec.Members[0].Roles, "owner")
+		err := verifyKnownProjectRoles(project)
+		assert.ErrorContains(t, err, "role 'owner' of User user@example.com")
+	})
+
+	t.Run("workspace", func(t *testing.T) {
+		workspace := &pwv1alpha1.Workspace{
+			Spec: pwv1alpha1.WorkspaceSpec{
+				Members: []pwv1alpha1.WorkspaceMember{
+					{Subject: subject, Roles: []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleView}},
+				},
+				InheritProjectMembers: &pwv1alpha1.InheritProjectMembers{
+					Enabled: true,
+					RoleMapping: map[pwv1alpha1.ProjectMemberRole]pwv1alpha1.WorkspaceMemberRole{
+						pwv1alpha1.ProjectRoleAdmin: pwv1alpha1.WorkspaceRoleAdmin,
+					},
+				},
+			},
+		}
+		assert.NoError(t, verifyKnownWorkspaceRoles(workspace))
+
+		workspace.Spec.Members[0].Roles = append(workspace.Spec.Members[0].Roles, "owner")
+		workspace.Spec.InheritProjectMembers.RoleMapping["billing"] = pwv1alpha1.WorkspaceRoleView
+		workspace.Spec.InheritProjectMembers.RoleMapping[pwv1alpha1.ProjectRoleView] = "reader"
+		err := verifyKnownWorkspaceRoles(workspace)
+		assert.ErrorContains(t, err, "unknown roles: role 'owner' of User user@example.com, project role 'billing' in the role mapping, workspace role 'reader' in the role mapping")
+	})
+}
+
+func TestVerifyChargingTarget(t *testing.T) {
+	si := config.NewFakeSharedInformation(nil, nil, nil, nil)
+	si.ChargingTargetData = pwv1alpha1.ChargingTargetConfig{
+		Required: true,
+		Pattern:  "cc-[0-9]{4}",
+	}
+	si.AllowedChargingTargetsData = sets.New("cc-1234", "cc-5678")
+
+	withChargingTarget := func(value string) *metav1.ObjectMeta {
+		obj := &metav1.ObjectMeta{}
+		if value != "" {
+			obj.Annotations = map[string]string{pwv1alpha1.ChargingTargetAnnotation: value}
+		}
+		return obj
+	}
+
+	tests := []struct {
+		description   string
+		oldObj        metav1.Object
+		obj           metav1.Object
+		checkRequired bool
+		expectedErr   string
+	}{
+		{
+			description: "accepts an allowed charging target",
+			obj:         withChargingTarget("cc-1234"),
+		},
+		{
+			description: "rejects a charging target which does not match the pattern",
+			obj:         withChargingTarget("cc-12345"),
+			expectedErr: "does not match the pattern",
+		},
+		{
+			description: "rejects a charging target which is not allowed",
+			obj:         withChargingTarget("cc-9999"),
+			expectedErr: "is not one of the allowed charging targets",
+		},
+		{
+			description:   "rejects a missing charging target if it is required",
+			obj:           withChargingTarget(""),
+			checkRequired: true,
+			expectedErr:   "is required",
+		},
+		{
+			description: "accepts a missing charging target if it is not checked, e.g. for workspaces",
+			obj:         withChargingTarget(""),
+		},
+		{
+			description:   "rejects the removal of a required charging target",
+			oldObj:        withChargingTarget("cc-1234"),
+			obj:           withChargingTarget(""),
+			checkRequired: true,
+			expectedErr:   "is required",
+		},
+		{
+			description:   "accepts updates of existing resources without charging target",
+			oldObj:        withChargingTarget(""),
+			obj:           withChargingTarget(""),
+			checkRequired: true,
+		},
+		{
+			description: "accepts an unchanged charging target which is not allowed anymore",
+			oldObj:      withChargingTarget("cc-9999"),
+			obj:         withChargingTarget("cc-9999"),
+		},
+		{
+			description: "rejects a changed charging target which is not allowed",
+			oldObj:      withChargingTarget("cc-1234"),
+			obj:         withChargingTarget("cc-9999"),
+			expectedErr: "is not one of the allowed charging targets",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := verifyChargingTarget(context.Background(), si, test.oldObj, test.obj, test.checkRequired)
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.expectedErr)
+			}
+		})
+	}
+}
+
+func TestVerifyMemberPolicy(t *testing.T) {
+	si := config.NewFakeSharedInformation(nil, nil, nil, nil)
+	si.ExcludedWebhookIdentitiesData = []pwv1alpha1.IdentityMatcher{
+		{
+			Name: "system:serviceaccount:portal:backend",
+		},
+	}
+	si.MemberPolicyData = pwv1alpha1.MemberPolicyConfig{
+		MaxProjectMembers:   2,
+		MaxWorkspaceMembers: 3,
+		GroupsOnly:          true,
+	}
+
+	group := func(name string) pwv1alpha1.Subject { return pwv1alpha1.Subject{Kind: "Group", Name: name} }
+	user := func(name string) pwv1alpha1.Subject { return pwv1alpha1.Subject{Kind: "User", Name: name} }
+	sa := pwv1alpha1.Subject{Kind: "ServiceAccount", Name: "deployer", Namespace: "project-sample"}
+
+	tests := []struct {
+		description string
+		username    string
+		resource    string
+		oldMembers  []pwv1alpha1.Subject
+		members     []pwv1alpha1.Subject
+		expectedErr string
+	}{
+		{
+			description: "accepts groups and ServiceAccounts within the limit",
+			resource:    projectResource,
+			members:     []pwv1alpha1.Subject{group("admins"), sa},
+		},
+		{
+			description: "rejects more members than allowed",
+			resource:    projectResource,
+			members:     []pwv1alpha1.Subject{group("admins"), group("viewers"), sa},
+			expectedErr: "project has 3 members, but at most 2 are allowed",
+		},
+		{
+			description: "uses the limit of workspaces for workspaces",
+			resource:    workspaceResource,
+			members:     []pwv1alpha1.Subject{group("admins"), group("viewers"), sa},
+		},
+		{
+			description: "accepts updates of resources with too many members which do not add members",
+			resource:    projectResource,
+			oldMembers:  []pwv1alpha1.Subject{group("admins"), group("viewers"), group("auditors")},
+			members:     []pwv1alpha1.Subject{group("admins"), group("viewers"), sa},
+		},
+		{
+			description: "rejects user members",
+			resource:    workspaceResource,
+			members:     []pwv1alpha1.Subject{group("admins"), user("john.doe@test.com")},
+			expectedErr: "users john.doe@test.com cannot be added as members",
+		},
+		{
+			description: "accepts existing user members",
+			resource:    projectResource,
+			oldMembers:  []pwv1alpha1.Subject{user("john.doe@test.com")},
+			members:     []pwv1alpha1.Subject{user("john.doe@test.com"), group("admins")},
+		},
+		{
+			description: "accepts violations by excluded identities",
+			username:    "system:serviceaccount:portal:backend",
+			resource:    projectResource,
+			members:     []pwv1alpha1.Subject{group("admins"), group("viewers"), user("john.doe@test.com")},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			username := test.username
+			if username == "" {
+				username = "admin"
+			}
+			err := verifyMemberPolicy(context.Background(), si, NewStaticOwnIdentity("system:serviceaccount:pwo:operator"), username, test.resource, test.oldMembers, test.members)
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.expectedErr)
+			}
+		})
+	}
+}
+
+func TestVerifyServiceAccountNamespacesNotDenied(t *testing.T) {
+	si := config.NewFakeSharedInformation(nil, nil, nil, pwv1alpha1.MemberOverrides{
+		{Subject: pwv1alpha1.Subject{Kind: "User", Name: "support"}, Roles: []pwv1alpha1.OverrideRole{pwv1alpha1.OverrideRoleAdmin}},
+		{
+			Subject:   pwv1alpha1.Subject{Kind: "User", Name: "sample-admin"},
+			Roles:     []pwv1alpha1.OverrideRole{pwv1alpha1.OverrideRoleAdmin},
+			Resources: []pwv1alpha1.OverrideResource{{Kind: pwv1alpha1.OverrideResourceKindProject, Name: "sample"}},
+		},
+	})
+	si.ExcludedWebhookIdentitiesData = []pwv1alpha1.IdentityMatcher{{Name: "system:serviceaccount:portal:backend"}}
+	si.ServiceAccountMembersData = pwv1alpha1.ServiceAccountMembersConfig{DeniedNamespaces: []string{"kube-system"}}
+
+	kubeSystem := pwv1alpha1.Subject{Kind: "ServiceAccount", Name: "admin", Namespace: "kube-system"}
+	deployer := pwv1alpha1.Subject{Kind: "ServiceAccount", Name: "deployer", Namespace: "project-sample"}
+	group := pwv1alpha1.Subject{Kind: "Group", Name: "kube-system"}
+
+	tests := []struct {
+		description string
+		username    string
+		kind        string
+		oldMembers  []pwv1alpha1.Subject
+		members     []pwv1alpha1.Subject
+		expectedErr string
+	}{
+		{
+			description: "accepts ServiceAccounts from other namespaces",
+			members:     []pwv1alpha1.Subject{deployer, group},
+		},
+		{
+			description: "rejects ServiceAccounts from denied namespaces",
+			members:     []pwv1alpha1.Subject{deployer, kubeSystem},
+			expectedErr: "ServiceAccounts kube-system/admin cannot be added as members of a project",
+		},
+		{
+			description: "accepts existing ServiceAccounts from denied namespaces",
+			oldMembers:  []pwv1alpha1.Subject{kubeSystem},
+			members:     []pwv1alpha1.Subject{kubeSystem, deployer},
+		},
+		{
+			description: "accepts ServiceAccounts added by admins by member override",
+			username:    "support",
+			members:     []pwv1alpha1.Subject{kubeSystem},
+		},
+		{
+			description: "accepts ServiceAccounts added by admins by member override of the resource",
+			username:    "sample-admin",
+			members:     []pwv1alpha1.Subject{kubeSystem},
+		},
+		{
+			description: "rejects ServiceAccounts added by admins by member override of another resource",
+			username:    "sample-admin",
+			kind:        pwv1alpha1.OverrideResourceKindWorkspace,
+			members:     []pwv1alpha1.Subject{kubeSystem},
+			expectedErr: "ServiceAccounts kube-system/admin cannot be added",
+		},
+		{
+			description: "accepts ServiceAccounts added by excluded identities",
+			username:    "system:serviceaccount:portal:backend",
+			members:     []pwv1alpha1.Subject{kubeSystem},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			username := test.username
+			if username == "" {
+				username = "admin"
+			}
+			kind := test.kind
+			if kind == "" {
+				kind = pwv1alpha1.OverrideResourceKindProject
+			}
+			err := verifyServiceAccountNamespacesNotDenied(context.Background(), si, nil, NewStaticOwnIdentity("system:serviceaccount:pwo:operator"), authv1.UserInfo{Username: username},
+				projectResource, "sample", kind, test.oldMembers, test.members)
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.expectedErr)
+			}
+		})
+	}
+}
+
+func TestVerifyManagedMembersUnchanged(t *testing.T) {
+	si := config.NewFakeSharedInformation(nil, nil, nil, nil)
+
+	devs := pwv1alpha1.Subject{Kind: "Group", Name: "devs"}
+	jane := pwv1alpha1.Subject{Kind: "User", Name: "jane@example.com"}
+	project := func(managed bool, members ...pwv1alpha1.ProjectMember) *pwv1alpha1.Project {
+		p := &pwv1alpha1.Project{ObjectMeta: metav1.ObjectMeta{Name: "sample"}, Spec: pwv1alpha1.ProjectSpec{Members: members}}
+		if managed {
+			p.Labels = map[string]string{pwv1alpha1.MembershipSourceLabel: "gitops"}
+			p.Annotations = map[string]string{pwv1alpha1.ManagedMembersAnnotation: `[{"kind":"Group","name":"devs"}]`}
+		}
+		return p
+	}
+	member := func(subject pwv1alpha1.Subject, roles ...pwv1alpha1.ProjectMemberRole) pwv1alpha1.ProjectMember {
+		return pwv1alpha1.ProjectMember{Subject: subject, Roles: roles}
+	}
+
+	tests := []struct {
+		description string
+		username    string
+		oldProject  *pwv1alpha1.Project
+		project     *pwv1alpha1.Project
+		expectedErr string
+	}{
+		{
+			description: "accepts new projects without marker",
+			project:     project(false, member(devs, pwv1alpha1.ProjectRoleAdmin)),
+		},
+		{
+			description: "rejects new projects with marker",
+			project:     project(true, member(devs, pwv1alpha1.ProjectRoleAdmin)),
+			expectedErr: pwv1alpha1.MembershipSourceLabel + " is managed by the platform service",
+		},
+		{
+			description: "accepts changes of members which are not managed",
+			oldProject:  project(true, member(devs, pwv1alpha1.ProjectRoleAdmin), member(jane, pwv1alpha1.ProjectRoleView)),
+			project:     project(true, member(devs, pwv1alpha1.ProjectRoleAdmin), member(jane, pwv1alpha1.ProjectRoleAdmin)),
+		},
+		{
+			description: "accepts reordered roles of managed members",
+			oldProject:  project(true, member(devs, pwv1alpha1.ProjectRoleAdmin, pwv1alpha1.ProjectRoleView)),
+			project:     project(true, member(devs, pwv1alpha1.ProjectRoleView, pwv1alpha1.ProjectRoleAdmin)),
+		},
+		{
+			description: "rejects changed roles of managed members",
+			oldProject:  project(true, member(devs, pwv1alpha1.ProjectRoleAdmin)),
+			project:     project(true, member(devs, pwv1alpha1.ProjectRoleView)),
+			expectedErr: "members Group:devs are synced from ProjectMembershipSource gitops",
+		},
+		{
+			description: "rejects removed managed members",
+			oldProject:  project(true, member(devs, pwv1alpha1.ProjectRoleAdmin), member(jane, pwv1alpha1.ProjectRoleView)),
+			project:     project(true, member(jane, pwv1alpha1.ProjectRoleView)),
+			expectedErr: "members Group:devs are synced from ProjectMembershipSource gitops",
+		},
+		{
+			description: "rejects the removal of the marker",
+			oldProject:  project(true, member(devs, pwv1alpha1.ProjectRoleAdmin)),
+			project:     project(false, member(devs, pwv1alpha1.ProjectRoleAdmin)),
+			expectedErr: pwv1alpha1.MembershipSourceLabel + " is managed by the platform service",
+		},
+		{
+			description: "accepts all changes by the platform service",
+			username:    "system:serviceaccount:pwo:operator",
+			oldProject:  project(true, member(devs, pwv1alpha1.ProjectRoleAdmin)),
+			project:     project(false),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			username := test.username
+			if username == "" {
+				username = "admin"
+			}
+			var err error
+			if test.oldProject == nil {
+				err = verifyManagedMembersUnchanged(context.Background(), si, NewStaticOwnIdentity("system:serviceaccount:pwo:operator"), username, nil, test.project, nil, projectMemberRoles(test.project))
+			} else {
+				err = verifyManagedMembersUnchanged(context.Background(), si, NewStaticOwnIdentity("system:serviceaccount:pwo:operator"), username, test.oldProject, test.project, projectMemberRoles(test.oldProject), projectMemberRoles(test.project))
+			}
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.expectedErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	"github.com/openmcp-project/platform-service-project-workspace/api/v2/metadata"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/webhookconfig"
+)
+
+// The validating webhooks of a platform service instance only match resources with the environment label of the instance,
+// so that several instances with different environments can watch the same onboarding cluster. The mutating webhooks match
+// all resources instead and label the ones without environment label, so that no resource skips the validation.
+// If several instances watch the same onboarding cluster, the instance whose mutating webhook is called first labels the resource.
+
+// errUnknownEnvironment is the error that is returned when a resource is labeled for an environment without platform service instance.
+var errUnknownEnvironment = func(environment string) error {
+	return fmt.Errorf("label %s must name the environment of a platform service instance, but there is none for environment %s", utils.LabelEnvironment, environment)
+}
+
+type environmentDefaulter[T client.Object] struct {
+	defaulter admission.Defaulter[T]
+	scope     webhookconfig.Scope
+	gvk       schema.GroupVersionKind
+	reader    client.Reader
+}
+
+// withEnvironment wraps the given defaulter for resources of the given type, so that resources without environment label are labeled for the environment of the given scope.
+// Resources of other environments are passed through unchanged, if the validating webhook configuration of their environment exists, which is read via the given reader.
+// The label of resources of the environment of the scope cannot be changed. If the environment is empty, the defaulter is returned as it is.
+func withEnvironment[T client.Object](defaulter admission.Defaulter[T], scope webhookconfig.Scope, gvk schema.GroupVersionKind, reader client.Reader) admission.Defaulter[T] {
+	if scope.Environment == "" {
+		return defaulter
+	}
+	return &environmentDefaulter[T]{defaulter: defaulter, scope: scope, gvk: gvk, reader: reader}
+}
+
+// Default implements admission.Defaulter.
+func (e *environmentDefaulter[T]) Default(ctx context.Context, obj T) error {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	previous := ""
+	if req.Operation == admissionv1.Update {
+		old := &metav1.PartialObjectMetadata{}
+		if err := json.Unmarshal(req.OldObject.Raw, old); err != nil {
+			return fmt.Errorf("failed to decode old object: %w", err)
+		}
+		previous = old.GetLabels()[utils.LabelEnvironment]
+	}
+
+	current := obj.GetLabels()[utils.LabelEnvironment]
+	switch {
+	case previous != "" && previous != e.scope.Environment:
+		// the resource belongs to another instance, which validates the change of the label as well
+		return nil
+	case previous != "" && current != previous:
+		return errLabelImmutable(utils.LabelEnvironment)
+	case current == "":
+		logging.FromContextOrPanic(ctx).Info("Setting environment label", "environment", e.scope.Environment)
+		metadata.SetLabel(obj, utils.LabelEnvironment, e.scope.Environment)
+	case current != e.scope.Environment:
+		// the resource has been assigned to another instance, which validates it, if it exists
+		return e.verifyEnvironmentExists(ctx, current)
+	}
+	return e.defaulter.Default(ctx, obj)
+}
+
+// verifyEnvironmentExists returns an error if there is no validating webhook configuration for the given environment,
+// since resources labeled for this environment would not be validated at all.
+func (e *environmentDefaulter[T]) verifyEnvironmentExists(ctx context.Context, environment string) error {
+	validating, _ := webhookconfig.Scope{ProviderName: e.scope.ProviderName, Environment: environment}.Names(e.gvk)
+	if err := e.reader.Get(ctx, client.ObjectKey{Name: validating}, &admissionregistrationv1.ValidatingWebhookConfiguration{}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return errUnknownEnvironment(environment)
+		}
+		return fmt.Errorf("failed to get ValidatingWebhookConfiguration '%s': %w", validating, err)
+	}
+	return nil
+}
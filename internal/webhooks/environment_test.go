@@ -0,0 +1,129 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/webhookconfig"
+)
+
+// recordingDefaulter records whether it has been called.
+type recordingDefaulter struct {
+	called bool
+}
+
+func (r *recordingDefaulter) Default(_ context.Context, _ *pwv1alpha1.Project) error {
+	r.called = true
+	return nil
+}
+
+func TestWithEnvironment(t *testing.T) {
+	scope := webhookconfig.Scope{ProviderName: "project-workspace", Environment: "dev"}
+	gvk := pwv1alpha1.GroupVersion.WithKind("Project")
+	// only the instance of the prod environment exists besides the one of the dev environment
+	prodValidating, _ := webhookconfig.Scope{ProviderName: "project-workspace", Environment: "prod"}.Names(gvk)
+	reader := fake.NewClientBuilder().WithObjects(&admissionregistrationv1.ValidatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: prodValidating}}).Build()
+	projectWithEnvironment := func(environment string) *pwv1alpha1.Project {
+		project := &pwv1alpha1.Project{ObjectMeta: metav1.ObjectMeta{Name: "sample"}}
+		if environment != "" {
+			project.Labels = map[string]string{utils.LabelEnvironment: environment}
+		}
+		return project
+	}
+	testCases := []struct {
+		desc                string
+		oldEnvironment      *string
+		environment         string
+		expectedEnvironment string
+		expectCalled        bool
+		expectError         bool
+	}{
+		{
+			desc:                "should label a new project without environment",
+			expectedEnvironment: "dev",
+			expectCalled:        true,
+		},
+		{
+			desc:                "should default a new project of the environment",
+			environment:         "dev",
+			expectedEnvironment: "dev",
+			expectCalled:        true,
+		},
+		{
+			desc:                "should pass a new project of another environment through",
+			environment:         "prod",
+			expectedEnvironment: "prod",
+		},
+		{
+			desc:        "should reject a new project of an environment without platform service instance",
+			environment: "unknown",
+			expectError: true,
+		},
+		{
+			desc:                "should label an existing project without environment",
+			oldEnvironment:      ptr.To(""),
+			expectedEnvironment: "dev",
+			expectCalled:        true,
+		},
+		{
+			desc:           "should reject removing the environment label",
+			oldEnvironment: ptr.To("dev"),
+			expectError:    true,
+		},
+		{
+			desc:           "should reject moving a project to another environment",
+			oldEnvironment: ptr.To("dev"),
+			environment:    "prod",
+			expectError:    true,
+		},
+		{
+			desc:                "should pass a project of another environment through",
+			oldEnvironment:      ptr.To("prod"),
+			expectedEnvironment: "",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{Operation: admissionv1.Create}}
+			if tC.oldEnvironment != nil {
+				raw, err := json.Marshal(projectWithEnvironment(*tC.oldEnvironment))
+				require.NoError(t, err)
+				req.Operation = admissionv1.Update
+				req.OldObject = runtime.RawExtension{Raw: raw}
+			}
+			ctx := admission.NewContextWithRequest(logging.NewContext(context.Background(), logging.Discard()), req)
+			inner := &recordingDefaulter{}
+			project := projectWithEnvironment(tC.environment)
+
+			err := withEnvironment[*pwv1alpha1.Project](inner, scope, gvk, reader).Default(ctx, project)
+			if tC.expectError {
+				assert.Error(t, err)
+				assert.False(t, inner.called)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tC.expectedEnvironment, project.Labels[utils.LabelEnvironment])
+			assert.Equal(t, tC.expectCalled, inner.called)
+		})
+	}
+
+	t.Run("should not wrap the defaulter without environment", func(t *testing.T) {
+		inner := &recordingDefaulter{}
+		assert.Same(t, inner, withEnvironment[*pwv1alpha1.Project](inner, webhookconfig.Scope{ProviderName: "project-workspace"}, gvk, reader))
+	})
+}
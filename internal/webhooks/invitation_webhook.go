@@ -0,0 +1,321 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	authv1 "k8s.io/api/authentication/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/api/v2/metadata"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/webhookconfig"
+)
+
+const (
+	InvitationWebhookName = "invitation-webhook"
+)
+
+// +kubebuilder:object:generate=false
+type InvitationWebhook struct {
+	client.Client
+	// APIReader is used to fetch the project or workspace of an invitation.
+	// It reads directly from the API server, because they might have been created just before the invitation.
+	APIReader client.Reader
+
+	// Identity is the identity of the entity (usually a service account) the platform-service-project-workspace uses to access the onboarding cluster.
+	// It is required to exclude the operator's own identity from validation checks.
+	// Further identities can be excluded via the ProjectWorkspaceConfig.
+	Identity          *OwnIdentity
+	SharedInformation config.SharedInformation
+	// MemberOverridesCache optionally serves the MemberOverrides from the cache of the platform cluster, instead of from the SharedInformation.
+	MemberOverridesCache *MemberOverridesCache
+}
+
+// SetupInvitationWebhookWithManager registers the Invitation webhook at the given manager. overrides may be nil.
+// scope identifies the platform service instance, whose environment is set as label on Invitations without one, see withEnvironment.
+func SetupInvitationWebhookWithManager(ctx context.Context, mgr ctrl.Manager, identity *OwnIdentity, si config.SharedInformation, overrides *MemberOverridesCache, scope webhookconfig.Scope) error {
+	iwh := &InvitationWebhook{
+		Client:               mgr.GetClient(),
+		APIReader:            mgr.GetAPIReader(),
+		SharedInformation:    si,
+		Identity:             identity,
+		MemberOverridesCache: overrides,
+	}
+
+	return ctrl.NewWebhookManagedBy(mgr, &pwv1alpha1.Invitation{}).
+		WithDefaulter(withEnvironment[*pwv1alpha1.Invitation](iwh, scope, pwv1alpha1.GroupVersion.WithKind("Invitation"), mgr.GetAPIReader())).
+		WithValidator(withLookupBudget[*pwv1alpha1.Invitation](iwh, si)).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-core-openmcp-cloud-v1alpha1-invitation,mutating=true,failurePolicy=fail,sideEffects=None,groups=core.openmcp.cloud,resources=invitations,verbs=create;update,versions=v1alpha1,name=minvitation.openmcp.cloud,admissionReviewVersions=v1
+
+var _ admission.Defaulter[*pwv1alpha1.Invitation] = &InvitationWebhook{}
+
+// Default implements admission.Defaulter so a webhook will be registered for the type
+func (i *InvitationWebhook) Default(ctx context.Context, obj *pwv1alpha1.Invitation) error {
+	invitation, err := expectInvitation(obj)
+	if err != nil {
+		return err
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := setCreatedBy(ctx, i.SharedInformation, i.Identity, invitation, req); err != nil {
+		return err
+	}
+	setAcceptedBy(invitation, req)
+	return nil
+}
+
+// setAcceptedBy records the user who accepted the invitation, if it is accepted and nobody has been recorded yet.
+// The validating webhook ensures that the recorded user is the one who accepted it and that it is not changed afterwards.
+func setAcceptedBy(invitation *pwv1alpha1.Invitation, req admission.Request) {
+	if !invitation.IsAccepted() || invitation.GetAnnotations()[pwv1alpha1.InvitationAcceptedByAnnotation] != "" {
+		return
+	}
+
+	metadata.SetAnnotation(invitation, pwv1alpha1.InvitationAcceptedByAnnotation, req.UserInfo.Username)
+}
+
+// +kubebuilder:webhook:path=/validate-core-openmcp-cloud-v1alpha1-invitation,mutating=false,failurePolicy=fail,sideEffects=None,groups=core.openmcp.cloud,resources=invitations,verbs=create;update;delete,versions=v1alpha1,name=vinvitation.openmcp.cloud,admissionReviewVersions=v1
+
+var _ admission.Validator[*pwv1alpha1.Invitation] = &InvitationWebhook{}
+
+// ValidateCreate implements admission.Validator so a webhook will be registered for the type
+func (v *InvitationWebhook) ValidateCreate(ctx context.Context, obj *pwv1alpha1.Invitation) (warnings admission.Warnings, err error) {
+	log := logging.FromContextOrPanic(ctx).WithName(InvitationWebhookName)
+	ctx = logging.NewContext(ctx, log)
+	invitation, err := expectInvitation(obj)
+	if err != nil {
+		return
+	}
+	log.Info("Validate create")
+
+	userInfo, err := userInfoFromContext(ctx)
+	if err != nil {
+		return
+	}
+	excluded, err := isExcludedIdentity(ctx, v.SharedInformation, v.Identity, userInfo.Username)
+	if err != nil || excluded {
+		return
+	}
+	if err = verifyCreatedByRequester(ctx, v.SharedInformation, v.Identity, invitation, userInfo.Username); err != nil {
+		return
+	}
+	if invitation.IsAccepted() {
+		return warnings, errInvitationAcceptedOnCreate
+	}
+
+	project, workspace, err := v.invitationTarget(ctx, invitation)
+	if err != nil {
+		return
+	}
+	if project == nil {
+		return warnings, errInvitationTargetNotFound(projectResource, invitation.Spec.Project)
+	}
+	if invitation.Spec.Workspace != "" && workspace == nil {
+		return warnings, errInvitationTargetNotFound(workspaceResource, invitation.Spec.Workspace)
+	}
+	if err = v.ensureAdmin(ctx, userInfo, project, workspace); err != nil {
+		return
+	}
+
+	var target metav1.Object = project
+	resource, members := projectResource, projectSubjects(project)
+	if workspace != nil {
+		resource, members, target = workspaceResource, workspaceSubjects(workspace), workspace
+	}
+	if err = verifyInvitedSubjectNotManaged(target, invitation.Spec.Subject); err != nil {
+		return
+	}
+	newMembers := members
+	if !slices.Contains(members, invitation.Spec.Subject) {
+		newMembers = append(slices.Clone(members), invitation.Spec.Subject)
+	}
+	err = verifyMemberPolicy(ctx, v.SharedInformation, v.Identity, userInfo.Username, resource, members, newMembers)
+	return
+}
+
+// ValidateUpdate implements admission.Validator so a webhook will be registered for the type
+func (v *InvitationWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj *pwv1alpha1.Invitation) (warnings admission.Warnings, err error) {
+	log := logging.FromContextOrPanic(ctx).WithName(InvitationWebhookName)
+	ctx = logging.NewContext(ctx, log)
+	oldInvitation, err := expectInvitation(oldObj)
+	if err != nil {
+		return
+	}
+	newInvitation, err := expectInvitation(newObj)
+	if err != nil {
+		return
+	}
+	log.Info("Validate update")
+
+	userInfo, err := userInfoFromContext(ctx)
+	if err != nil {
+		return
+	}
+	excluded, err := isExcludedIdentity(ctx, v.SharedInformation, v.Identity, userInfo.Username)
+	if err != nil || excluded {
+		return
+	}
+	if err = verifyImmutableAnnotationsUnchanged(oldInvitation, newInvitation); err != nil {
+		return
+	}
+
+	switch {
+	case !oldInvitation.IsAccepted() && newInvitation.IsAccepted():
+		// the invited user is not a member yet, so the acceptance is the only change which does not require admin rights
+		err = verifyAcceptance(newInvitation, userInfo, time.Now())
+		return
+	case oldInvitation.IsAccepted() && !newInvitation.IsAccepted():
+		return warnings, errAcceptanceRevoked
+	}
+	if changed := metadata.ChangedKeys(oldInvitation.GetAnnotations(), newInvitation.GetAnnotations(), pwv1alpha1.InvitationAcceptedByAnnotation); len(changed) > 0 {
+		return warnings, errAnnotationImmutable(changed[0])
+	}
+
+	project, workspace, err := v.invitationTarget(ctx, newInvitation)
+	if err != nil || project == nil {
+		return
+	}
+	err = v.ensureAdmin(ctx, userInfo, project, workspace)
+	return
+}
+
+// ValidateDelete implements admission.Validator so a webhook will be registered for the type
+func (v *InvitationWebhook) ValidateDelete(ctx context.Context, obj *pwv1alpha1.Invitation) (warnings admission.Warnings, err error) {
+	log := logging.FromContextOrPanic(ctx).WithName(InvitationWebhookName)
+	ctx = logging.NewContext(ctx, log)
+	invitation, err := expectInvitation(obj)
+	if err != nil {
+		return
+	}
+	log.Info("Validate delete")
+
+	userInfo, err := userInfoFromContext(ctx)
+	if err != nil {
+		return
+	}
+	// the invited user declines the invitation by deleting it
+	if invitation.IsInvited(userInfo) {
+		return
+	}
+	excluded, err := isExcludedIdentity(ctx, v.SharedInformation, v.Identity, userInfo.Username)
+	if err != nil || excluded {
+		return
+	}
+
+	// invitations to projects or workspaces which do not exist anymore can be deleted by everyone who can delete invitations
+	project, workspace, err := v.invitationTarget(ctx, invitation)
+	if err != nil || project == nil || (invitation.Spec.Workspace != "" && workspace == nil) {
+		return
+	}
+	err = v.ensureAdmin(ctx, userInfo, project, workspace)
+	return
+}
+
+// expectInvitation casts the given runtime.Object to *Invitation. Returns an error in case the object can't be casted.
+func expectInvitation(obj runtime.Object) (*pwv1alpha1.Invitation, error) {
+	invitation, ok := obj.(*pwv1alpha1.Invitation)
+	if !ok {
+		return nil, fmt.Errorf("expected an Invitation but got a %T", obj)
+	}
+	return invitation, nil
+}
+
+// verifyAcceptance checks that the given invitation, which has just been accepted, is accepted by the invited user on their own behalf
+// and that it can still be accepted.
+func verifyAcceptance(invitation *pwv1alpha1.Invitation, userInfo authv1.UserInfo, now time.Time) error {
+	if !invitation.IsInvited(userInfo) {
+		return errNotInvited(userInfo.Username)
+	}
+	if acceptedBy := invitation.GetAnnotations()[pwv1alpha1.InvitationAcceptedByAnnotation]; acceptedBy != userInfo.Username {
+		return errAcceptedBySpoofed(acceptedBy, userInfo.Username)
+	}
+	if invitation.IsCompleted() || !now.Before(invitation.ExpirationTime()) {
+		return errInvitationNotPending
+	}
+	return nil
+}
+
+// verifyInvitedSubjectNotManaged returns an error if the invited subject is synced from a ProjectMembershipSource to the given project or workspace.
+// Its roles could only be changed in the source, the sync would remove the roles added by the invitation again.
+func verifyInvitedSubjectNotManaged(target metav1.Object, subject pwv1alpha1.Subject) error {
+	source, managed, err := utils.ManagedMembers(target)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(managed, subject) {
+		return errManagedMembersChanged(source, []string{utils.FormatSubject(subject.RbacV1())})
+	}
+	return nil
+}
+
+// invitationTarget returns the project of the given invitation and the workspace, if the invitation is for a workspace.
+// The project or workspace is nil if it does not exist (anymore).
+func (v *InvitationWebhook) invitationTarget(ctx context.Context, invitation *pwv1alpha1.Invitation) (*pwv1alpha1.Project, *pwv1alpha1.Workspace, error) {
+	project := &pwv1alpha1.Project{}
+	if err := lookup(ctx, v.SharedInformation, checkMembership, func(ctx context.Context) error {
+		return v.APIReader.Get(ctx, client.ObjectKey{Name: invitation.Spec.Project}, project)
+	}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to get project %s: %w", invitation.Spec.Project, err)
+	}
+	project.SetGroupVersionKind(pwv1alpha1.GroupVersion.WithKind("Project"))
+	if invitation.Spec.Workspace == "" || project.Status.Namespace == "" {
+		return project, nil, nil
+	}
+
+	workspace := &pwv1alpha1.Workspace{}
+	if err := lookup(ctx, v.SharedInformation, checkMembership, func(ctx context.Context) error {
+		return v.APIReader.Get(ctx, client.ObjectKey{Name: invitation.Spec.Workspace, Namespace: project.Status.Namespace}, workspace)
+	}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return project, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to get workspace %s/%s: %w", project.Status.Namespace, invitation.Spec.Workspace, err)
+	}
+	workspace.SetGroupVersionKind(pwv1alpha1.GroupVersion.WithKind("Workspace"))
+	return project, workspace, nil
+}
+
+// ensureAdmin returns an error if the requesting user is not allowed to manage the given project, or the given workspace if it is not nil.
+// The same rules as for changing the members of the project or workspace apply, since an accepted invitation adds a member.
+func (v *InvitationWebhook) ensureAdmin(ctx context.Context, userInfo authv1.UserInfo, project *pwv1alpha1.Project, workspace *pwv1alpha1.Workspace) error {
+	var valid bool
+	var err error
+	resource, name := projectResource, project.Name
+	if workspace == nil {
+		pwh := &ProjectWebhook{Client: v.Client, Identity: v.Identity, SharedInformation: v.SharedInformation, MemberOverridesCache: v.MemberOverridesCache}
+		valid, err = pwh.ensureValidRole(ctx, project)
+	} else {
+		resource, name = workspaceResource, workspace.Name
+		wwh := &WorkspaceWebhook{Client: v.Client, APIReader: v.APIReader, Identity: v.Identity, SharedInformation: v.SharedInformation, MemberOverridesCache: v.MemberOverridesCache}
+		valid, err = wwh.ensureValidRole(ctx, workspace)
+	}
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return errInvitationNotAllowed(userInfo.Username, resource, name)
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/api/v2/webhooktest"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+)
+
+func TestInvitationWebhookAdmission(t *testing.T) {
+	const operator = "system:serviceaccount:pwo:operator"
+	project := &pwv1alpha1.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+		Spec: pwv1alpha1.ProjectSpec{Members: []pwv1alpha1.ProjectMember{
+			{Subject: pwv1alpha1.Subject{Kind: "User", Name: "jane"}, Roles: []pwv1alpha1.ProjectMemberRole{pwv1alpha1.ProjectRoleAdmin}},
+			{Subject: pwv1alpha1.Subject{Kind: "User", Name: "john"}, Roles: []pwv1alpha1.ProjectMemberRole{pwv1alpha1.ProjectRoleView}},
+		}},
+		Status: pwv1alpha1.ProjectStatus{Namespace: "project-foo"},
+	}
+	workspace := &pwv1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "dev", Namespace: "project-foo"},
+		Spec: pwv1alpha1.WorkspaceSpec{Members: []pwv1alpha1.WorkspaceMember{
+			{Subject: pwv1alpha1.Subject{Kind: "User", Name: "john"}, Roles: []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleAdmin}},
+		}},
+	}
+	newInvitation := func(workspace string, annotations ...string) *pwv1alpha1.Invitation {
+		invitation := &pwv1alpha1.Invitation{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "invite-alice",
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour)),
+				Annotations:       map[string]string{pwv1alpha1.CreatedByAnnotation: "jane"},
+			},
+			Spec: pwv1alpha1.InvitationSpec{
+				Project:   "foo",
+				Workspace: workspace,
+				Subject:   pwv1alpha1.Subject{Kind: "User", Name: "alice"},
+				Roles:     []string{"view"},
+			},
+		}
+		for i := 0; i+1 < len(annotations); i += 2 {
+			invitation.Annotations[annotations[i]] = annotations[i+1]
+		}
+		return invitation
+	}
+	expired := newInvitation("")
+	expired.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * pwv1alpha1.DefaultInvitationTTL))
+	acceptedExpired := expired.DeepCopy()
+	acceptedExpired.Annotations[pwv1alpha1.InvitationAcceptAnnotation] = "true"
+	groupInvitation := newInvitation("")
+	groupInvitation.Spec.Subject = pwv1alpha1.Subject{Kind: "Group", Name: "team"}
+	acceptedGroupInvitation := groupInvitation.DeepCopy()
+	acceptedGroupInvitation.Annotations[pwv1alpha1.InvitationAcceptAnnotation] = "true"
+
+	tests := []struct {
+		description   string
+		request       admission.Request
+		expectMessage string
+	}{
+		{
+			description: "allows a project admin to invite to the project",
+			request:     webhooktest.CreateRequest(newInvitation(""), webhooktest.AsUser("jane")),
+		},
+		{
+			description:   "denies invitations to the project by non-admins",
+			request:       webhooktest.CreateRequest(newInvitation(""), webhooktest.AsUser("john")),
+			expectMessage: errInvitationNotAllowed("john", projectResource, "foo").Error(),
+		},
+		{
+			description: "allows a workspace admin to invite to the workspace",
+			request:     webhooktest.CreateRequest(newInvitation("dev"), webhooktest.AsUser("john")),
+		},
+		{
+			description:   "denies invitations to a project which does not exist",
+			request:       webhooktest.CreateRequest(func() *pwv1alpha1.Invitation { inv := newInvitation(""); inv.Spec.Project = "bar"; return inv }(), webhooktest.AsUser("jane")),
+			expectMessage: errInvitationTargetNotFound(projectResource, "bar").Error(),
+		},
+		{
+			description:   "denies invitations to a workspace which does not exist",
+			request:       webhooktest.CreateRequest(newInvitation("prod"), webhooktest.AsUser("jane")),
+			expectMessage: errInvitationTargetNotFound(workspaceResource, "prod").Error(),
+		},
+		{
+			description:   "denies invitations which are accepted on creation",
+			request:       webhooktest.CreateRequest(newInvitation("", pwv1alpha1.InvitationAcceptAnnotation, "true"), webhooktest.AsUser("jane")),
+			expectMessage: errInvitationAcceptedOnCreate.Error(),
+		},
+		{
+			description: "allows the invited user to accept the invitation",
+			request:     webhooktest.UpdateRequest(newInvitation(""), newInvitation("", pwv1alpha1.InvitationAcceptAnnotation, "true"), webhooktest.AsUser("alice")),
+		},
+		{
+			description: "allows members of the invited group to accept the invitation",
+			request:     webhooktest.UpdateRequest(groupInvitation, acceptedGroupInvitation, webhooktest.AsUser("bob", "team")),
+		},
+		{
+			description:   "denies the acceptance by other users",
+			request:       webhooktest.UpdateRequest(newInvitation(""), newInvitation("", pwv1alpha1.InvitationAcceptAnnotation, "true"), webhooktest.AsUser("jane")),
+			expectMessage: errNotInvited("jane").Error(),
+		},
+		{
+			description: "denies the acceptance on behalf of another user",
+			request: webhooktest.UpdateRequest(groupInvitation, func() *pwv1alpha1.Invitation {
+				inv := acceptedGroupInvitation.DeepCopy()
+				inv.Annotations[pwv1alpha1.InvitationAcceptedByAnnotation] = "carol"
+				return inv
+			}(), webhooktest.AsUser("bob", "team")),
+			expectMessage: errAcceptedBySpoofed("carol", "bob").Error(),
+		},
+		{
+			description:   "denies the acceptance of an expired invitation",
+			request:       webhooktest.UpdateRequest(expired, acceptedExpired, webhooktest.AsUser("alice")),
+			expectMessage: errInvitationNotPending.Error(),
+		},
+		{
+			description:   "denies removing the acceptance",
+			request:       webhooktest.UpdateRequest(newInvitation("", pwv1alpha1.InvitationAcceptAnnotation, "true", pwv1alpha1.InvitationAcceptedByAnnotation, "alice"), newInvitation(""), webhooktest.AsUser("jane")),
+			expectMessage: errAcceptanceRevoked.Error(),
+		},
+		{
+			description:   "denies other updates by the invited user",
+			request:       webhooktest.UpdateRequest(newInvitation(""), newInvitation("", "foo", "bar"), webhooktest.AsUser("alice")),
+			expectMessage: errInvitationNotAllowed("alice", projectResource, "foo").Error(),
+		},
+		{
+			description: "allows the invited user to decline the invitation",
+			request:     webhooktest.DeleteRequest(newInvitation(""), webhooktest.AsUser("alice")),
+		},
+		{
+			description: "allows admins to withdraw the invitation",
+			request:     webhooktest.DeleteRequest(newInvitation(""), webhooktest.AsUser("jane")),
+		},
+		{
+			description:   "denies the deletion by other users",
+			request:       webhooktest.DeleteRequest(newInvitation(""), webhooktest.AsUser("john")),
+			expectMessage: errInvitationNotAllowed("john", projectResource, "foo").Error(),
+		},
+		{
+			description: "allows the operator to delete completed invitations",
+			request:     webhooktest.DeleteRequest(newInvitation(""), webhooktest.AsUser(operator)),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			c := webhooktest.NewFakeClient(project, workspace)
+			iwh := &InvitationWebhook{
+				Client:            c,
+				APIReader:         c,
+				Identity:          NewStaticOwnIdentity(operator),
+				SharedInformation: config.NewFakeSharedInformation(nil, nil, nil, nil),
+			}
+			h := webhooktest.NewHarness[*pwv1alpha1.Invitation](iwh, iwh)
+
+			_, res := h.Admit(context.Background(), test.request)
+			if test.expectMessage == "" {
+				assert.True(t, res.Allowed, res.Result)
+				return
+			}
+			if assert.False(t, res.Allowed) {
+				assert.Equal(t, test.expectMessage, res.Result.Message)
+			}
+		})
+	}
+}
+
+func TestInvitationWebhookDefault(t *testing.T) {
+	iwh := &InvitationWebhook{Identity: NewStaticOwnIdentity("operator"), SharedInformation: config.NewFakeSharedInformation(nil, nil, nil, nil)}
+	h := webhooktest.NewHarness[*pwv1alpha1.Invitation](iwh, nil)
+	invitation := &pwv1alpha1.Invitation{
+		ObjectMeta: metav1.ObjectMeta{Name: "invite-alice", Annotations: map[string]string{pwv1alpha1.CreatedByAnnotation: "jane"}},
+		Spec:       pwv1alpha1.InvitationSpec{Project: "foo", Subject: pwv1alpha1.Subject{Kind: "User", Name: "alice"}, Roles: []string{"view"}},
+	}
+	accepted := invitation.DeepCopy()
+	accepted.Annotations[pwv1alpha1.InvitationAcceptAnnotation] = "true"
+
+	created, err := h.Default(context.Background(), webhooktest.CreateRequest(invitation, webhooktest.AsUser("jane")))
+	assert.NoError(t, err)
+	assert.Equal(t, "jane", created.Annotations[pwv1alpha1.CreatedByAnnotation])
+	assert.NotContains(t, created.Annotations, pwv1alpha1.InvitationAcceptedByAnnotation)
+
+	updated, err := h.Default(context.Background(), webhooktest.UpdateRequest(invitation, accepted, webhooktest.AsUser("alice")))
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", updated.Annotations[pwv1alpha1.InvitationAcceptedByAnnotation])
+	assert.Equal(t, "jane", updated.Annotations[pwv1alpha1.CreatedByAnnotation])
+}
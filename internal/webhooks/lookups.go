@@ -0,0 +1,129 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/metrics"
+)
+
+// checkMembership identifies the lookups which decide whether the requesting user may change a resource, e.g. of the MemberOverrides or the parent project of a workspace.
+// It cannot be configured in the failure policies, so these lookups always fail closed.
+const checkMembership pwv1alpha1.WebhookCheck = "Membership"
+
+// lookupRetryDelay is the time between the attempts of a lookup.
+const lookupRetryDelay = 100 * time.Millisecond
+
+// budgetedValidator limits the time which the wrapped validator spends on lookups for a single admission request to the budget from the config.
+// The budget is applied via the deadline of the context, which is passed to all lookups.
+// +kubebuilder:object:generate=false
+type budgetedValidator[T runtime.Object] struct {
+	validator admission.Validator[T]
+	si        config.SharedInformation
+}
+
+// withLookupBudget wraps the given validator, so that the lookups of each admission request are limited to the time budget from the config.
+func withLookupBudget[T runtime.Object](validator admission.Validator[T], si config.SharedInformation) admission.Validator[T] {
+	return &budgetedValidator[T]{validator: validator, si: si}
+}
+
+// ValidateCreate implements admission.Validator.
+func (b *budgetedValidator[T]) ValidateCreate(ctx context.Context, obj T) (admission.Warnings, error) {
+	ctx, cancel := b.withTimeout(ctx)
+	defer cancel()
+	return b.validator.ValidateCreate(ctx, obj)
+}
+
+// ValidateUpdate implements admission.Validator.
+func (b *budgetedValidator[T]) ValidateUpdate(ctx context.Context, oldObj, newObj T) (admission.Warnings, error) {
+	ctx, cancel := b.withTimeout(ctx)
+	defer cancel()
+	return b.validator.ValidateUpdate(ctx, oldObj, newObj)
+}
+
+// ValidateDelete implements admission.Validator.
+func (b *budgetedValidator[T]) ValidateDelete(ctx context.Context, obj T) (admission.Warnings, error) {
+	ctx, cancel := b.withTimeout(ctx)
+	defer cancel()
+	return b.validator.ValidateDelete(ctx, obj)
+}
+
+// withTimeout returns a context which is cancelled once the time budget from the config has passed.
+// If the config cannot be read, the default budget is used, since the validator reports the missing config itself.
+func (b *budgetedValidator[T]) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	cfg, err := b.si.WebhookLookups(ctx)
+	if err != nil {
+		return context.WithTimeout(ctx, pwv1alpha1.DefaultWebhookLookupTimeout)
+	}
+	return context.WithTimeout(ctx, cfg.GetTimeout())
+}
+
+// lookup calls fn until it succeeds, fails with an error which is not transient, the retries from the config are used up or the time budget of the request is exhausted.
+// fn has to pass the given context to its requests, so that they are cancelled with the budget. Lookups which still fail are counted in the WebhookLookupFailures metric
+// with the failure policy of the given check, except for errors about missing resources or resource types, which are a valid result of most lookups.
+func lookup(ctx context.Context, si config.SharedInformation, check pwv1alpha1.WebhookCheck, fn func(ctx context.Context) error) error {
+	cfg, err := si.WebhookLookups(ctx)
+	if err != nil {
+		return err
+	}
+	for attempt := 0; ; attempt++ {
+		err = fn(ctx)
+		if err == nil || attempt >= cfg.GetRetries() || !isTransient(err) {
+			break
+		}
+		logging.FromContextOrPanic(ctx).Debug("Retrying failed lookup", "check", check, "attempt", attempt+1, "error", err.Error())
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(lookupRetryDelay):
+		}
+	}
+	if err != nil && !isAbsent(err) {
+		metrics.WebhookLookupFailures.WithLabelValues(string(check), string(cfg.FailurePolicyFor(check))).Inc()
+	}
+	return err
+}
+
+// isTransient returns true if a lookup which failed with the given error might succeed when it is retried.
+// Errors without API status, e.g. connection errors, are considered transient. Exhausting the time budget is not, since a retry would fail immediately.
+func isTransient(err error) bool {
+	if isAbsent(err) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) {
+		return true
+	}
+	return apierrors.IsTimeout(err) || apierrors.IsServerTimeout(err) || apierrors.IsTooManyRequests(err) ||
+		apierrors.IsServiceUnavailable(err) || apierrors.IsInternalError(err)
+}
+
+// isAbsent returns true if the given error reports that the looked up resource or its resource type does not exist.
+func isAbsent(err error) bool {
+	return apierrors.IsNotFound(err) || meta.IsNoMatchError(err)
+}
+
+// failsOpen returns true if the given check should be skipped, because its lookups failed with the given error and its failure policy is 'Ignore'.
+// Skipped checks are logged.
+// The membership lookups always fail closed, regardless of the configuration.
+func failsOpen(ctx context.Context, si config.SharedInformation, check pwv1alpha1.WebhookCheck, err error) bool {
+	if check == checkMembership {
+		return false
+	}
+	cfg, cfgErr := si.WebhookLookups(ctx)
+	if cfgErr != nil || cfg.FailurePolicyFor(check) != pwv1alpha1.WebhookFailurePolicyIgnore {
+		return false
+	}
+	logging.FromContextOrPanic(ctx).Info("Skipping check, because its lookups failed", "check", check, "error", err.Error())
+	return true
+}
@@ -0,0 +1,179 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+)
+
+func TestLookup(t *testing.T) {
+	projects := schema.GroupResource{Group: pwv1alpha1.GroupVersion.Group, Resource: "projects"}
+	testCases := []struct {
+		desc          string
+		retries       *int32
+		errs          []error
+		expectedCalls int
+		expectedErr   bool
+	}{
+		{
+			desc:          "should not retry successful lookups",
+			expectedCalls: 1,
+		},
+		{
+			desc:          "should retry transient errors",
+			errs:          []error{apierrors.NewServerTimeout(projects, "get", 1)},
+			expectedCalls: 2,
+		},
+		{
+			desc:          "should fail once the retries are used up",
+			errs:          []error{apierrors.NewTooManyRequests("slow down", 1), apierrors.NewServiceUnavailable("down")},
+			expectedCalls: 2,
+			expectedErr:   true,
+		},
+		{
+			desc:          "should use the configured number of retries",
+			retries:       ptr.To[int32](0),
+			errs:          []error{errors.New("connection refused")},
+			expectedCalls: 1,
+			expectedErr:   true,
+		},
+		{
+			desc:          "should not retry missing resources",
+			errs:          []error{apierrors.NewNotFound(projects, "alpha")},
+			expectedCalls: 1,
+			expectedErr:   true,
+		},
+		{
+			desc:          "should not retry denied requests",
+			errs:          []error{apierrors.NewForbidden(projects, "alpha", errors.New("denied"))},
+			expectedCalls: 1,
+			expectedErr:   true,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			si := config.NewFakeSharedInformation(nil, nil, nil, nil)
+			si.WebhookLookupsData = pwv1alpha1.WebhookLookupsConfig{Retries: tC.retries}
+			ctx := logging.NewContext(context.Background(), logging.Discard())
+
+			calls := 0
+			err := lookup(ctx, si, pwv1alpha1.WebhookCheckProjectQuota, func(ctx context.Context) error {
+				calls++
+				if calls <= len(tC.errs) {
+					return tC.errs[calls-1]
+				}
+				return nil
+			})
+			assert.Equal(t, tC.expectedErr, err != nil)
+			assert.Equal(t, tC.expectedCalls, calls)
+		})
+	}
+}
+
+func TestLookupStopsWhenBudgetIsExhausted(t *testing.T) {
+	si := config.NewFakeSharedInformation(nil, nil, nil, nil)
+	si.WebhookLookupsData = pwv1alpha1.WebhookLookupsConfig{Retries: ptr.To[int32](10)}
+	ctx, cancel := context.WithTimeout(logging.NewContext(context.Background(), logging.Discard()), lookupRetryDelay/2)
+	defer cancel()
+
+	calls := 0
+	err := lookup(ctx, si, checkMembership, func(ctx context.Context) error {
+		calls++
+		return errors.New("connection refused")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestFailsOpen(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		policies map[pwv1alpha1.WebhookCheck]pwv1alpha1.WebhookFailurePolicy
+		check    pwv1alpha1.WebhookCheck
+		expected bool
+	}{
+		{
+			desc:  "should fail closed by default",
+			check: pwv1alpha1.WebhookCheckProjectQuota,
+		},
+		{
+			desc:     "should fail open for deletion blockers by default",
+			check:    pwv1alpha1.WebhookCheckDeletionBlockers,
+			expected: true,
+		},
+		{
+			desc:     "should fail open if configured",
+			policies: map[pwv1alpha1.WebhookCheck]pwv1alpha1.WebhookFailurePolicy{pwv1alpha1.WebhookCheckWorkspaceClass: pwv1alpha1.WebhookFailurePolicyIgnore},
+			check:    pwv1alpha1.WebhookCheckWorkspaceClass,
+			expected: true,
+		},
+		{
+			desc:     "should fail closed if configured",
+			policies: map[pwv1alpha1.WebhookCheck]pwv1alpha1.WebhookFailurePolicy{pwv1alpha1.WebhookCheckDeletionBlockers: pwv1alpha1.WebhookFailurePolicyFail},
+			check:    pwv1alpha1.WebhookCheckDeletionBlockers,
+		},
+		{
+			desc:     "should always fail closed for membership lookups",
+			policies: map[pwv1alpha1.WebhookCheck]pwv1alpha1.WebhookFailurePolicy{checkMembership: pwv1alpha1.WebhookFailurePolicyIgnore},
+			check:    checkMembership,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			si := config.NewFakeSharedInformation(nil, nil, nil, nil)
+			si.WebhookLookupsData = pwv1alpha1.WebhookLookupsConfig{FailurePolicies: tC.policies}
+			ctx := logging.NewContext(context.Background(), logging.Discard())
+
+			assert.Equal(t, tC.expected, failsOpen(ctx, si, tC.check, errors.New("connection refused")))
+		})
+	}
+}
+
+type deadlineValidator struct {
+	deadline time.Time
+}
+
+func (d *deadlineValidator) ValidateCreate(ctx context.Context, _ *pwv1alpha1.Project) (admission.Warnings, error) {
+	d.deadline, _ = ctx.Deadline()
+	return nil, nil
+}
+
+func (d *deadlineValidator) ValidateUpdate(ctx context.Context, _, _ *pwv1alpha1.Project) (admission.Warnings, error) {
+	return d.ValidateCreate(ctx, nil)
+}
+
+func (d *deadlineValidator) ValidateDelete(ctx context.Context, _ *pwv1alpha1.Project) (admission.Warnings, error) {
+	return d.ValidateCreate(ctx, nil)
+}
+
+func TestWithLookupBudget(t *testing.T) {
+	si := config.NewFakeSharedInformation(nil, nil, nil, nil)
+	si.WebhookLookupsData = pwv1alpha1.WebhookLookupsConfig{Timeout: &metav1.Duration{Duration: time.Minute}}
+	inner := &deadlineValidator{}
+	validator := withLookupBudget[*pwv1alpha1.Project](inner, si)
+
+	start := time.Now()
+	_, err := validator.ValidateCreate(context.Background(), &pwv1alpha1.Project{})
+	require.NoError(t, err)
+	assert.WithinDuration(t, start.Add(time.Minute), inner.deadline, time.Second)
+
+	si.WebhookLookupsData = pwv1alpha1.WebhookLookupsConfig{}
+	start = time.Now()
+	_, err = validator.ValidateDelete(context.Background(), &pwv1alpha1.Project{})
+	require.NoError(t, err)
+	assert.WithinDuration(t, start.Add(pwv1alpha1.DefaultWebhookLookupTimeout), inner.deadline, time.Second)
+}
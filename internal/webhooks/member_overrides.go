@@ -0,0 +1,130 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	toolscache "k8s.io/client-go/tools/cache"
+	ctrlcache "sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/metrics"
+)
+
+// MemberOverridesCache reads the MemberOverrides of the ProjectWorkspaceConfig, merged with the override for the environment and the selected MemberOverrideSets,
+// from the informer-backed cache of the platform cluster.
+// With leader election, the config controller only runs on the leading replica, while all replicas serve webhook requests.
+// The cache is started on every replica, so the webhooks can be scaled horizontally without issuing requests to the API server for each admission request.
+// +kubebuilder:object:generate=false
+type MemberOverridesCache struct {
+	reader       client.Reader
+	providerName string
+	environment  string
+	podNamespace string
+}
+
+// NewMemberOverridesCache returns a MemberOverridesCache which reads from the given cache of the platform cluster.
+// The environment and pod namespace identify the ProjectWorkspaceConfigOverride, the environment may be empty.
+// Event handlers are added to the informers of all three resources, which track the time of the last update in the WebhookMemberOverridesCacheLastUpdate metric.
+func NewMemberOverridesCache(ctx context.Context, cache ctrlcache.Cache, providerName, environment, podNamespace string) (*MemberOverridesCache, error) {
+	c := &MemberOverridesCache{
+		reader:       cache,
+		providerName: providerName,
+		environment:  environment,
+		podNamespace: podNamespace,
+	}
+	if err := c.registerHandler(ctx, cache, &pwv1alpha1.ProjectWorkspaceConfig{}, hasKey(client.ObjectKey{Name: providerName})); err != nil {
+		return nil, err
+	}
+	if environment != "" {
+		if err := c.registerHandler(ctx, cache, &pwv1alpha1.ProjectWorkspaceConfigOverride{}, hasKey(client.ObjectKey{Name: environment, Namespace: podNamespace})); err != nil {
+			return nil, err
+		}
+	}
+	// whether a set is selected depends on the config, so changes of all sets are recorded
+	if err := c.registerHandler(ctx, cache, &pwv1alpha1.MemberOverrideSet{}, func(client.Object) bool { return true }); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// hasKey returns a function which matches the object with the given key.
+func hasKey(key client.ObjectKey) func(client.Object) bool {
+	return func(o client.Object) bool {
+		return client.ObjectKeyFromObject(o) == key
+	}
+}
+
+// registerHandler adds a handler to the informer for the type of the given object, which updates the WebhookMemberOverridesCacheLastUpdate metric on each change of an object which matches.
+func (c *MemberOverridesCache) registerHandler(ctx context.Context, cache ctrlcache.Informers, obj client.Object, matches func(client.Object) bool) error {
+	informer, err := cache.GetInformer(ctx, obj, ctrlcache.BlockUntilSynced(false))
+	if err != nil {
+		return fmt.Errorf("failed to get informer for %T: %w", obj, err)
+	}
+	record := func(obj any) {
+		if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		if o, ok := obj.(client.Object); ok && matches(o) {
+			metrics.WebhookMemberOverridesCacheLastUpdate.SetToCurrentTime()
+		}
+	}
+	_, err = informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc:    record,
+		UpdateFunc: func(_, newObj any) { record(newObj) },
+		DeleteFunc: record,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add event handler for %T: %w", obj, err)
+	}
+	return nil
+}
+
+// Get returns the MemberOverrides from the cache.
+// It returns false if the cache does not contain the ProjectWorkspaceConfig, e.g. because it has not been started yet or the fallback config is used.
+func (c *MemberOverridesCache) Get(ctx context.Context) (pwv1alpha1.MemberOverrides, bool, error) {
+	pwc := &pwv1alpha1.ProjectWorkspaceConfig{}
+	if err := c.reader.Get(ctx, client.ObjectKey{Name: c.providerName}, pwc); err != nil {
+		if apierrors.IsNotFound(err) || errors.As(err, new(*ctrlcache.ErrCacheNotStarted)) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to get ProjectWorkspaceConfig '%s' from cache: %w", c.providerName, err)
+	}
+	var override *pwv1alpha1.ProjectWorkspaceConfigOverride
+	if c.environment != "" {
+		override = &pwv1alpha1.ProjectWorkspaceConfigOverride{}
+		if err := c.reader.Get(ctx, client.ObjectKey{Name: c.environment, Namespace: c.podNamespace}, override); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, false, fmt.Errorf("failed to get ProjectWorkspaceConfigOverride '%s/%s' from cache: %w", c.podNamespace, c.environment, err)
+			}
+			override = nil
+		} else if !override.DeletionTimestamp.IsZero() {
+			override = nil
+		}
+	}
+	overrides, err := config.EffectiveMemberOverrides(ctx, c.reader, config.MergeOverride(pwc, override))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get MemberOverrideSets from cache: %w", err)
+	}
+	return overrides, true, nil
+}
+
+// getMemberOverrides returns the MemberOverrides from the given cache (may be nil), or from the shared information if the cache does not contain them.
+func getMemberOverrides(ctx context.Context, cache *MemberOverridesCache, si config.SharedInformation) (pwv1alpha1.MemberOverrides, error) {
+	if cache != nil {
+		overrides, ok, err := cache.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			metrics.WebhookMemberOverridesLookups.WithLabelValues(metrics.CacheResultHit).Inc()
+			return overrides, nil
+		}
+		metrics.WebhookMemberOverridesLookups.WithLabelValues(metrics.CacheResultMiss).Inc()
+	}
+	return si.MemberOverrides(ctx)
+}
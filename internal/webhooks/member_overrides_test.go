@@ -0,0 +1,97 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+)
+
+func TestGetMemberOverrides(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, pwv1alpha1.AddToScheme(scheme))
+
+	fromConfig := pwv1alpha1.MemberOverrides{{
+		Subject: pwv1alpha1.Subject{Kind: rbacv1.UserKind, Name: "config-admin"},
+		Roles:   []pwv1alpha1.OverrideRole{pwv1alpha1.OverrideRoleAdmin},
+	}}
+	fromOverride := pwv1alpha1.MemberOverrides{{
+		Subject: pwv1alpha1.Subject{Kind: rbacv1.UserKind, Name: "override-admin"},
+		Roles:   []pwv1alpha1.OverrideRole{pwv1alpha1.OverrideRoleAdmin},
+	}}
+	fromState := pwv1alpha1.MemberOverrides{{
+		Subject: pwv1alpha1.Subject{Kind: rbacv1.UserKind, Name: "state-admin"},
+		Roles:   []pwv1alpha1.OverrideRole{pwv1alpha1.OverrideRoleAdmin},
+	}}
+	pwc := &pwv1alpha1.ProjectWorkspaceConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "project-workspace"},
+		Spec:       pwv1alpha1.ProjectWorkspaceConfigSpec{MemberOverrides: fromConfig},
+	}
+	override := &pwv1alpha1.ProjectWorkspaceConfigOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "live", Namespace: "openmcp-system"},
+		Spec:       pwv1alpha1.ProjectWorkspaceConfigOverrideSpec{MemberOverrides: fromOverride},
+	}
+
+	testCases := []struct {
+		desc        string
+		objects     []client.Object
+		environment string
+		noCache     bool
+		expected    pwv1alpha1.MemberOverrides
+	}{
+		{
+			desc:     "should serve the member overrides of the config from the cache",
+			objects:  []client.Object{pwc},
+			expected: fromConfig,
+		},
+		{
+			desc:        "should merge the override of the environment",
+			objects:     []client.Object{pwc, override},
+			environment: "live",
+			expected:    fromOverride,
+		},
+		{
+			desc:        "should ignore overrides of other environments",
+			objects:     []client.Object{pwc, override},
+			environment: "canary",
+			expected:    fromConfig,
+		},
+		{
+			desc:     "should fall back to the shared information if the config is not cached",
+			expected: fromState,
+		},
+		{
+			desc:     "should use the shared information without a cache",
+			objects:  []client.Object{pwc},
+			noCache:  true,
+			expected: fromState,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			var cache *MemberOverridesCache
+			if !tC.noCache {
+				cache = &MemberOverridesCache{
+					reader:       fake.NewClientBuilder().WithScheme(scheme).WithObjects(tC.objects...).Build(),
+					providerName: "project-workspace",
+					environment:  tC.environment,
+					podNamespace: "openmcp-system",
+				}
+			}
+			si := config.NewFakeSharedInformation(nil, nil, nil, fromState)
+
+			overrides, err := getMemberOverrides(context.Background(), cache, si)
+			require.NoError(t, err)
+			assert.Equal(t, tC.expected, overrides)
+		})
+	}
+}
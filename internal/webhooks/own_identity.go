@@ -0,0 +1,199 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/openmcp-project/controller-utils/pkg/logging"
+)
+
+const (
+	OwnIdentityName = "own-identity"
+
+	// DefaultOwnIdentityRefreshInterval is the default interval in which the own identity is determined again.
+	DefaultOwnIdentityRefreshInterval = 10 * time.Minute
+
+	// ownIdentityMinRefreshDelay is the minimum time between two refreshes which are triggered by authentication errors,
+	// so that a burst of failing requests does not cause a burst of SelfSubjectReviews.
+	ownIdentityMinRefreshDelay = 10 * time.Second
+
+	serviceAccountUsernamePrefix = "system:serviceaccount:"
+)
+
+// OwnIdentity is the identity of the platform service on the onboarding cluster, which is excluded from the validation of the webhooks.
+// It is determined via a SelfSubjectReview at startup and again periodically and after authentication errors, since the username changes
+// when the access to the onboarding cluster is re-issued with another ServiceAccount. Until then, requests with the new credentials
+// would be rejected, unless their username matches the optional prefix.
+// +kubebuilder:object:generate=false
+type OwnIdentity struct {
+	client client.Client
+	prefix string
+	log    logging.Logger
+	// refresh is signalled when the identity should be determined again before the next interval.
+	refresh chan struct{}
+
+	lock     sync.RWMutex
+	username string
+
+	// RefreshInterval is the interval in which the identity is determined again, 0 disables the periodic refresh.
+	RefreshInterval time.Duration
+}
+
+// NewOwnIdentity determines the identity of the given client via a SelfSubjectReview.
+// If the given prefix is not empty, all usernames starting with it are treated as the own identity as well,
+// it has to be the username prefix of ServiceAccounts in a single namespace, i.e. 'system:serviceaccount:<namespace>:<name prefix>'.
+func NewOwnIdentity(ctx context.Context, c client.Client, prefix string) (*OwnIdentity, error) {
+	if err := ValidateOwnIdentityPrefix(prefix); err != nil {
+		return nil, err
+	}
+	oi := &OwnIdentity{
+		client:          c,
+		prefix:          prefix,
+		log:             logging.Discard(),
+		refresh:         make(chan struct{}, 1),
+		RefreshInterval: DefaultOwnIdentityRefreshInterval,
+	}
+	username, err := oi.review(ctx)
+	if err != nil {
+		return nil, err
+	}
+	oi.username = username
+	return oi, nil
+}
+
+// NewStaticOwnIdentity returns an OwnIdentity with the given username, which is never determined again.
+func NewStaticOwnIdentity(username string) *OwnIdentity {
+	return &OwnIdentity{username: username}
+}
+
+// ValidateOwnIdentityPrefix returns an error if the given prefix is not empty and does not select ServiceAccounts in a single namespace by name prefix.
+func ValidateOwnIdentityPrefix(prefix string) error {
+	if prefix == "" {
+		return nil
+	}
+	namespace, name, ok := strings.Cut(strings.TrimPrefix(prefix, serviceAccountUsernamePrefix), ":")
+	if !strings.HasPrefix(prefix, serviceAccountUsernamePrefix) || !ok || namespace == "" || name == "" {
+		return fmt.Errorf("invalid own identity prefix '%s', must have the format '%s<namespace>:<name prefix>'", prefix, serviceAccountUsernamePrefix)
+	}
+	return nil
+}
+
+// Username returns the current username of the own identity.
+func (oi *OwnIdentity) Username() string {
+	if oi == nil {
+		return ""
+	}
+	oi.lock.RLock()
+	defer oi.lock.RUnlock()
+	return oi.username
+}
+
+// Matches returns true if the given username is the own identity or starts with the configured prefix.
+func (oi *OwnIdentity) Matches(username string) bool {
+	if oi == nil || username == "" {
+		return false
+	}
+	if oi.prefix != "" && strings.HasPrefix(username, oi.prefix) {
+		return true
+	}
+	return username == oi.Username()
+}
+
+// Refresh determines the own identity again. The previous username is kept if this fails.
+func (oi *OwnIdentity) Refresh(ctx context.Context) error {
+	if oi.client == nil {
+		return nil
+	}
+	username, err := oi.review(ctx)
+	if err != nil {
+		return err
+	}
+	oi.lock.Lock()
+	defer oi.lock.Unlock()
+	if username != oi.username {
+		oi.log.Info("Own identity has changed", "previous", oi.username, "identity", username)
+		oi.username = username
+	}
+	return nil
+}
+
+// review returns the username of the client from a SelfSubjectReview.
+func (oi *OwnIdentity) review(ctx context.Context) (string, error) {
+	review := &authenticationv1.SelfSubjectReview{}
+	if err := oi.client.Create(ctx, review); err != nil {
+		return "", fmt.Errorf("failed to get own identity: %w", err)
+	}
+	return review.Status.UserInfo.Username, nil
+}
+
+// WrapTransport wraps the given transport, so that the own identity is determined again if a request fails with '401 Unauthorized',
+// since the credentials might have been rotated. It can be passed to rest.Config.Wrap.
+func (oi *OwnIdentity) WrapTransport(rt http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := rt.RoundTrip(req)
+		if err == nil && resp.StatusCode == http.StatusUnauthorized {
+			select {
+			case oi.refresh <- struct{}{}:
+			default:
+			}
+		}
+		return resp, err
+	})
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// SetupWithManager adds the periodic refresh to the manager.
+func (oi *OwnIdentity) SetupWithManager(mgr ctrl.Manager) error {
+	oi.log = logging.Wrap(mgr.GetLogger()).WithName(OwnIdentityName)
+	return mgr.Add(oi)
+}
+
+var _ manager.Runnable = &OwnIdentity{}
+var _ manager.LeaderElectionRunnable = &OwnIdentity{}
+
+// Start determines the own identity in the configured interval and after authentication errors until the context is cancelled.
+func (oi *OwnIdentity) Start(ctx context.Context) error {
+	var tick <-chan time.Time
+	if oi.RefreshInterval > 0 {
+		ticker := time.NewTicker(oi.RefreshInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-tick:
+		case <-oi.refresh:
+			oi.log.Debug("Determining own identity again after an authentication error")
+		}
+		if err := oi.Refresh(ctx); err != nil {
+			oi.log.Error(err, "failed to determine own identity, keeping the previous one", "identity", oi.Username())
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(ownIdentityMinRefreshDelay):
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+// Every replica serves webhook requests, so every replica has to keep its own identity up to date.
+func (oi *OwnIdentity) NeedLeaderElection() bool {
+	return false
+}
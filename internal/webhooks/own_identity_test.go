@@ -0,0 +1,105 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestValidateOwnIdentityPrefix(t *testing.T) {
+	tests := []struct {
+		prefix      string
+		expectError bool
+	}{
+		{prefix: ""},
+		{prefix: "system:serviceaccount:pwo:onboarding-"},
+		{prefix: "system:serviceaccount:pwo:", expectError: true},
+		{prefix: "system:serviceaccount:pwo", expectError: true},
+		{prefix: "system:serviceaccount::onboarding-", expectError: true},
+		{prefix: "system:", expectError: true},
+		{prefix: "jane", expectError: true},
+	}
+	for _, test := range tests {
+		t.Run(test.prefix, func(t *testing.T) {
+			err := ValidateOwnIdentityPrefix(test.prefix)
+			assert.Equal(t, test.expectError, err != nil, "unexpected error: %v", err)
+		})
+	}
+}
+
+func TestOwnIdentity(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, authenticationv1.AddToScheme(scheme))
+	username := "system:serviceaccount:pwo:onboarding-a1"
+	var reviewErr error
+	c := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, _ client.WithWatch, obj client.Object, _ ...client.CreateOption) error {
+			if reviewErr != nil {
+				return reviewErr
+			}
+			obj.(*authenticationv1.SelfSubjectReview).Status.UserInfo.Username = username
+			return nil
+		},
+	}).Build()
+	ctx := context.Background()
+
+	identity, err := NewOwnIdentity(ctx, c, "")
+	require.NoError(t, err)
+	assert.Equal(t, "system:serviceaccount:pwo:onboarding-a1", identity.Username())
+	assert.True(t, identity.Matches("system:serviceaccount:pwo:onboarding-a1"))
+	assert.False(t, identity.Matches("system:serviceaccount:pwo:onboarding-b2"))
+
+	// the access has been re-issued with another ServiceAccount
+	username = "system:serviceaccount:pwo:onboarding-b2"
+	require.NoError(t, identity.Refresh(ctx))
+	assert.False(t, identity.Matches("system:serviceaccount:pwo:onboarding-a1"))
+	assert.True(t, identity.Matches("system:serviceaccount:pwo:onboarding-b2"))
+
+	// the previous identity is kept if the review fails
+	reviewErr = errors.New("connection refused")
+	assert.Error(t, identity.Refresh(ctx))
+	assert.Equal(t, "system:serviceaccount:pwo:onboarding-b2", identity.Username())
+
+	// with a prefix, the rotated ServiceAccounts match before the identity is determined again
+	reviewErr = nil
+	prefixed, err := NewOwnIdentity(ctx, c, "system:serviceaccount:pwo:onboarding-")
+	require.NoError(t, err)
+	assert.True(t, prefixed.Matches("system:serviceaccount:pwo:onboarding-c3"))
+	assert.False(t, prefixed.Matches("system:serviceaccount:other:onboarding-c3"))
+	assert.False(t, prefixed.Matches("jane"))
+
+	_, err = NewOwnIdentity(ctx, c, "system:")
+	assert.Error(t, err)
+
+	var unset *OwnIdentity
+	assert.False(t, unset.Matches("system:serviceaccount:pwo:onboarding-b2"))
+}
+
+func TestOwnIdentityWrapTransport(t *testing.T) {
+	identity := &OwnIdentity{refresh: make(chan struct{}, 1)}
+	status := http.StatusOK
+	rt := identity.WrapTransport(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: status}, nil
+	}))
+
+	_, err := rt.RoundTrip(&http.Request{})
+	require.NoError(t, err)
+	assert.Empty(t, identity.refresh)
+
+	// repeated authentication errors only trigger a single refresh
+	status = http.StatusUnauthorized
+	for range 3 {
+		_, err = rt.RoundTrip(&http.Request{})
+		require.NoError(t, err)
+	}
+	assert.Len(t, identity.refresh, 1)
+}
@@ -2,9 +2,14 @@ package webhooks
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"slices"
 
+	authv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -12,31 +17,44 @@ import (
 	"github.com/openmcp-project/controller-utils/pkg/logging"
 
 	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/api/v2/metadata"
 	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/webhookconfig"
 )
 
-const ProjectWebhookName = "project-webhook"
+const (
+	ProjectWebhookName = "project-webhook"
+	// projectResource is the name of projects in error messages.
+	projectResource = "project"
+)
 
 // +kubebuilder:object:generate=false
 type ProjectWebhook struct {
 	client.Client
 
-	// Identity is the name of the entity (usually a service account) the platform-service-project-workspace uses to access the onboarding cluster.
+	// Identity is the identity of the entity (usually a service account) the platform-service-project-workspace uses to access the onboarding cluster.
 	// It is required to exclude the operator's own identity from validation checks.
-	Identity          string
+	// Further identities can be excluded via the ProjectWorkspaceConfig.
+	Identity          *OwnIdentity
 	SharedInformation config.SharedInformation
+	// MemberOverridesCache optionally serves the MemberOverrides from the cache of the platform cluster, instead of from the SharedInformation.
+	MemberOverridesCache *MemberOverridesCache
 }
 
-func SetupProjectWebhookWithManager(ctx context.Context, mgr ctrl.Manager, identity string, si config.SharedInformation) error {
+// SetupProjectWebhookWithManager registers the Project webhook at the given manager. overrides may be nil.
+// scope identifies the platform service instance, whose environment is set as label on Projects without one, see withEnvironment.
+func SetupProjectWebhookWithManager(ctx context.Context, mgr ctrl.Manager, identity *OwnIdentity, si config.SharedInformation, overrides *MemberOverridesCache, scope webhookconfig.Scope) error {
 	pwh := &ProjectWebhook{
-		Client:            mgr.GetClient(),
-		SharedInformation: si,
-		Identity:          identity,
+		Client:               mgr.GetClient(),
+		SharedInformation:    si,
+		Identity:             identity,
+		MemberOverridesCache: overrides,
 	}
 
 	return ctrl.NewWebhookManagedBy(mgr, &pwv1alpha1.Project{}).
-		WithDefaulter(pwh).
-		WithValidator(pwh).
+		WithDefaulter(withEnvironment[*pwv1alpha1.Project](pwh, scope, pwv1alpha1.GroupVersion.WithKind("Project"), mgr.GetAPIReader())).
+		WithValidator(withLookupBudget[*pwv1alpha1.Project](pwh, si)).
 		Complete()
 }
 
@@ -59,11 +77,51 @@ func (p *ProjectWebhook) Default(ctx context.Context, obj *pwv1alpha1.Project) e
 		return err
 	}
 
-	setCreatedBy(project, req)
+	if err := setCreatedBy(ctx, p.SharedInformation, p.Identity, project, req); err != nil {
+		return err
+	}
+	setAutomationTokenRequestedBy(project, req)
+	setDisplayName(project)
+
+	return p.addCreatorAsAdmin(ctx, project, req)
+}
+
+// addCreatorAsAdmin adds the requesting user as admin to a new project without admin members, if this is enabled in the config.
+// If the user is already a member, the admin role is added to the existing member.
+func (p *ProjectWebhook) addCreatorAsAdmin(ctx context.Context, project *pwv1alpha1.Project, req admission.Request) error {
+	hasAdmin := slices.ContainsFunc(project.Spec.Members, func(m pwv1alpha1.ProjectMember) bool {
+		return slices.Contains(m.Roles, pwv1alpha1.ProjectRoleAdmin)
+	})
+	add, err := shouldAddCreatorAsAdmin(ctx, p.SharedInformation, p.Identity, req, hasAdmin)
+	if err != nil || !add {
+		return err
+	}
 
+	subject := subjectForUsername(req.UserInfo.Username)
+	logging.FromContextOrPanic(ctx).Info("Adding requesting user as admin", "kind", subject.Kind, "name", subject.Name)
+	for i := range project.Spec.Members {
+		if project.Spec.Members[i].Subject == subject {
+			project.Spec.Members[i].Roles = append(project.Spec.Members[i].Roles, pwv1alpha1.ProjectRoleAdmin)
+			return nil
+		}
+	}
+	project.Spec.Members = append(project.Spec.Members, pwv1alpha1.ProjectMember{
+		Subject: subject,
+		Roles:   []pwv1alpha1.ProjectMemberRole{pwv1alpha1.ProjectRoleAdmin},
+	})
 	return nil
 }
 
+// setAutomationTokenRequestedBy records the user who requested a token for the project's automation ServiceAccount.
+// The annotation is always overwritten, so that it cannot be spoofed.
+func setAutomationTokenRequestedBy(project *pwv1alpha1.Project, req admission.Request) {
+	if _, ok := project.GetAnnotations()[pwv1alpha1.AutomationTokenRequestAnnotation]; !ok {
+		return
+	}
+
+	metadata.SetAnnotation(project, pwv1alpha1.AutomationTokenRequestedByAnnotation, req.UserInfo.Username)
+}
+
 // +kubebuilder:webhook:path=/validate-core-openmcp-cloud-v1alpha1-project,mutating=false,failurePolicy=fail,sideEffects=None,groups=core.openmcp.cloud,resources=projects,verbs=create;update;delete,versions=v1alpha1,name=vproject.openmcp.cloud,admissionReviewVersions=v1
 
 var _ admission.Validator[*pwv1alpha1.Project] = &ProjectWebhook{}
@@ -77,11 +135,46 @@ func (v *ProjectWebhook) ValidateCreate(ctx context.Context, obj *pwv1alpha1.Pro
 		return
 	}
 	log.Info("Validate create")
+	warnings = projectMemberWarnings(project)
+
+	if err = verifyKnownProjectRoles(project); err != nil {
+		return
+	}
+	if err = verifyChargingTarget(ctx, v.SharedInformation, nil, project, true); err != nil {
+		return
+	}
+	if err = verifyDisplayName(nil, project); err != nil {
+		return
+	}
+	if err = verifyProjectLabel(nil, project); err != nil {
+		return
+	}
+	if err = verifySecretStores(ctx, v.SharedInformation, nil, project); err != nil {
+		return
+	}
+	if err = verifyResourcesBlockingDeletion(ctx, v.SharedInformation, nil, project); err != nil {
+		return
+	}
 
 	userInfo, err := userInfoFromContext(ctx)
 	if err != nil {
 		return
 	}
+	if err = verifyCreatedByRequester(ctx, v.SharedInformation, v.Identity, project, userInfo.Username); err != nil {
+		return
+	}
+	if err = verifyProjectQuota(ctx, v.Client, v.SharedInformation, v.Identity, userInfo.Username); err != nil {
+		return
+	}
+	if err = verifyMemberPolicy(ctx, v.SharedInformation, v.Identity, userInfo.Username, projectResource, nil, projectSubjects(project)); err != nil {
+		return
+	}
+	if err = verifyServiceAccountNamespacesNotDenied(ctx, v.SharedInformation, v.MemberOverridesCache, v.Identity, userInfo, projectResource, project.Name, pwv1alpha1.OverrideResourceKindProject, nil, projectSubjects(project)); err != nil {
+		return
+	}
+	if err = verifyManagedMembersUnchanged(ctx, v.SharedInformation, v.Identity, userInfo.Username, nil, project, nil, projectMemberRoles(project)); err != nil {
+		return
+	}
 
 	validRole, err := v.ensureValidRole(ctx, project)
 	if err != nil {
@@ -108,8 +201,29 @@ func (v *ProjectWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj *pwv
 		return
 	}
 	log.Info("Validate update")
+	warnings = projectMemberWarnings(newProject)
 
-	if err = verifyCreatedByUnchanged(oldProject, newProject); err != nil {
+	if err = verifyKnownProjectRoles(newProject); err != nil {
+		return
+	}
+	if !v.isOwnershipTransfer(ctx, oldProject, newProject) {
+		if err = verifyImmutableAnnotationsUnchanged(oldProject, newProject); err != nil {
+			return
+		}
+	}
+	if err = verifyChargingTarget(ctx, v.SharedInformation, oldProject, newProject, true); err != nil {
+		return
+	}
+	if err = verifyDisplayName(oldProject, newProject); err != nil {
+		return
+	}
+	if err = verifyProjectLabel(oldProject, newProject); err != nil {
+		return
+	}
+	if err = verifySecretStores(ctx, v.SharedInformation, oldProject, newProject); err != nil {
+		return
+	}
+	if err = verifyResourcesBlockingDeletion(ctx, v.SharedInformation, oldProject, newProject); err != nil {
 		return
 	}
 
@@ -117,6 +231,18 @@ func (v *ProjectWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj *pwv
 	if err != nil {
 		return
 	}
+	if err = verifyMemberPolicy(ctx, v.SharedInformation, v.Identity, userInfo.Username, projectResource, projectSubjects(oldProject), projectSubjects(newProject)); err != nil {
+		return
+	}
+	if err = verifyServiceAccountNamespacesNotDenied(ctx, v.SharedInformation, v.MemberOverridesCache, v.Identity, userInfo, projectResource, newProject.Name, pwv1alpha1.OverrideResourceKindProject, projectSubjects(oldProject), projectSubjects(newProject)); err != nil {
+		return
+	}
+	if err = verifyManagedMembersUnchanged(ctx, v.SharedInformation, v.Identity, userInfo.Username, oldProject, newProject, projectMemberRoles(oldProject), projectMemberRoles(newProject)); err != nil {
+		return
+	}
+	if err = v.verifyOwnershipTransfer(ctx, oldProject, newProject); err != nil {
+		return
+	}
 	validRole, err := v.ensureValidRole(ctx, oldProject)
 	if err != nil {
 		return warnings, err
@@ -150,9 +276,110 @@ func (v *ProjectWebhook) ValidateDelete(ctx context.Context, obj *pwv1alpha1.Pro
 	if validRole, err := v.ensureValidRole(ctx, project); !validRole {
 		return warnings, err
 	}
+	if err = verifyNoForegroundDeletion(ctx); err != nil {
+		return
+	}
+	err = v.verifyOwnerIsAdmin(ctx, project)
 	return
 }
 
+// verifyNoForegroundDeletion rejects the foreground deletion of a project. The project owns its namespace, so the garbage collector
+// would delete the namespace right away, without the grace period and the checks for blocking resources of the project controller.
+func verifyNoForegroundDeletion(ctx context.Context) error {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if len(req.Options.Raw) == 0 {
+		return nil
+	}
+	opts := &metav1.DeleteOptions{}
+	if err := json.Unmarshal(req.Options.Raw, opts); err != nil {
+		return fmt.Errorf("failed to decode delete options: %w", err)
+	}
+	if ptr.Deref(opts.PropagationPolicy, "") == metav1.DeletePropagationForeground {
+		logging.FromContextOrPanic(ctx).Info("Rejecting foreground deletion")
+		return errForegroundDeletion
+	}
+	return nil
+}
+
+// verifyOwnerIsAdmin checks that the creator of the given project, which is about to be deleted, is still an admin of it, so that nobody deletes a project
+// whose owner has left without someone taking over the responsibility for it. Projects whose ownership is being transferred can be deleted,
+// as well as projects without creator. Excluded identities are not restricted.
+func (v *ProjectWebhook) verifyOwnerIsAdmin(ctx context.Context, project *pwv1alpha1.Project) error {
+	owner := project.GetAnnotations()[pwv1alpha1.CreatedByAnnotation]
+	if owner == "" || project.UserInfoHasRole(authv1.UserInfo{Username: owner}, pwv1alpha1.ProjectRoleAdmin) {
+		return nil
+	}
+	if _, transferred := project.GetAnnotations()[pwv1alpha1.TransferOwnershipAnnotation]; transferred {
+		return nil
+	}
+
+	userInfo, err := userInfoFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	excluded, err := isExcludedIdentity(ctx, v.SharedInformation, v.Identity, userInfo.Username)
+	if err != nil || excluded {
+		return err
+	}
+	logging.FromContextOrPanic(ctx).Info("Rejecting deletion, the creator of the project is no admin anymore", "creator", owner)
+	return errOwnerNotAdmin(owner)
+}
+
+// verifyOwnershipTransfer checks that a transfer of the ownership of the given project, which has been requested or changed by the update,
+// is requested by an admin granted by member overrides or an excluded identity, and that the new owner is an admin of the project.
+func (v *ProjectWebhook) verifyOwnershipTransfer(ctx context.Context, oldProject, project *pwv1alpha1.Project) error {
+	newOwner, requested := project.GetAnnotations()[pwv1alpha1.TransferOwnershipAnnotation]
+	previous, wasRequested := oldProject.GetAnnotations()[pwv1alpha1.TransferOwnershipAnnotation]
+	if !requested || (wasRequested && previous == newOwner) {
+		return nil
+	}
+
+	userInfo, err := userInfoFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	excluded, err := isExcludedIdentity(ctx, v.SharedInformation, v.Identity, userInfo.Username)
+	if err != nil {
+		return err
+	}
+	if !excluded {
+		overrides, err := getMemberOverrides(ctx, v.MemberOverridesCache, v.SharedInformation)
+		if err != nil {
+			return fmt.Errorf("failed to get member overrides: %w", err)
+		}
+		if !overrides.HasAdminOverrideForResource(&userInfo, project.Name, project.Kind) {
+			return errOwnershipTransferNotAllowed(userInfo.Username)
+		}
+	}
+
+	if newOwner == "" || !project.UserInfoHasRole(authv1.UserInfo{Username: newOwner}, pwv1alpha1.ProjectRoleAdmin) {
+		return errNewOwnerNotAdmin(newOwner)
+	}
+	return nil
+}
+
+// isOwnershipTransfer returns true if the update is the project controller completing a requested ownership transfer,
+// i.e. it replaces the creator of the project with the requested new owner. Only then the immutable creator may change.
+func (v *ProjectWebhook) isOwnershipTransfer(ctx context.Context, oldProject, project *pwv1alpha1.Project) bool {
+	newOwner := oldProject.GetAnnotations()[pwv1alpha1.TransferOwnershipAnnotation]
+	if newOwner == "" || project.GetAnnotations()[pwv1alpha1.CreatedByAnnotation] != newOwner {
+		return false
+	}
+	userInfo, err := userInfoFromContext(ctx)
+	if err != nil {
+		return false
+	}
+	if v.Identity.Matches(userInfo.Username) {
+		return true
+	}
+	// other replicas of the platform service might use different identities
+	operatorIdentities, err := v.SharedInformation.OperatorIdentities(ctx)
+	return err == nil && operatorIdentities.Has(userInfo.Username)
+}
+
 // expectProject casts the given runtime.Object to *Project. Returns an error in case the object can't be casted.
 func expectProject(obj runtime.Object) (*pwv1alpha1.Project, error) {
 	project, ok := obj.(*pwv1alpha1.Project)
@@ -162,16 +389,180 @@ func expectProject(obj runtime.Object) (*pwv1alpha1.Project, error) {
 	return project, nil
 }
 
+// projectSubjects returns the subjects of the members of the given project.
+func projectSubjects(project *pwv1alpha1.Project) []pwv1alpha1.Subject {
+	subjects := make([]pwv1alpha1.Subject, 0, len(project.Spec.Members))
+	for _, member := range project.Spec.Members {
+		subjects = append(subjects, member.Subject)
+	}
+	return subjects
+}
+
+// projectMemberRoles returns the sorted roles of the members of the given project by subject.
+func projectMemberRoles(project *pwv1alpha1.Project) map[pwv1alpha1.Subject][]string {
+	roles := make(map[pwv1alpha1.Subject][]string, len(project.Spec.Members))
+	for _, member := range project.Spec.Members {
+		roles[member.Subject] = sortedRoles(member.Roles)
+	}
+	return roles
+}
+
+// projectMemberWarnings returns admission warnings for members of the given project with redundant roles.
+func projectMemberWarnings(project *pwv1alpha1.Project) admission.Warnings {
+	var warnings admission.Warnings
+	for _, member := range project.Spec.Members {
+		if w := redundantAuditorRoleWarning(member.Subject, member.Roles, pwv1alpha1.ProjectRoleAuditor); w != "" {
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
+
+// verifyKnownProjectRoles returns an error if any member of the given project has a role which is not known to this version of the platform service.
+func verifyKnownProjectRoles(project *pwv1alpha1.Project) error {
+	var unknown []string
+	for _, member := range project.Spec.Members {
+		unknown = append(unknown, unknownMemberRoles(member.Subject, member.Roles, pwv1alpha1.ProjectMemberRoles())...)
+	}
+	if len(unknown) > 0 {
+		return errUnknownRoles(unknown)
+	}
+	return nil
+}
+
+// verifyProjectLabel returns an error if a new or updated project is labeled as belonging to another project.
+// The platform service identifies the namespaces of a project via this label, and tools relying on it would attribute the project to the other one.
+// The label is only validated if it is set or changed, so that existing projects can still be updated and deleted. oldProject must be nil for new projects.
+func verifyProjectLabel(oldProject, project *pwv1alpha1.Project) error {
+	value, ok := project.GetLabels()[utils.LabelProject]
+	if !ok || value == project.Name {
+		return nil
+	}
+	if oldProject != nil && value == oldProject.GetLabels()[utils.LabelProject] {
+		return nil
+	}
+	return errProjectLabelMismatch(project.Name, value)
+}
+
+// verifySecretStores returns an error if a new or changed secret store reference of the given project refers to a store which is not configured
+// or to a path which is not allowed for the project. Unchanged references are not validated, so that existing projects can still be updated
+// after the configuration has changed. oldProject must be nil for new projects.
+func verifySecretStores(ctx context.Context, si config.SharedInformation, oldProject, project *pwv1alpha1.Project) error {
+	if len(project.Spec.SecretStores) == 0 {
+		return nil
+	}
+	stores, err := si.SecretStores(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get secret stores from config: %w", err)
+	}
+	for _, ref := range project.Spec.SecretStores {
+		if oldProject != nil && slices.Contains(oldProject.Spec.SecretStores, ref) {
+			continue
+		}
+		idx := slices.IndexFunc(stores, func(store pwv1alpha1.SecretStoreConfig) bool { return store.Name == ref.Name })
+		if idx < 0 {
+			return errSecretStoreNotConfigured(ref.Name)
+		}
+		allowed, err := stores[idx].AllowsPath(project.Name, ref.Path)
+		if err != nil {
+			return fmt.Errorf("failed to check path of secret store %s: %w", ref.Name, err)
+		}
+		if !allowed {
+			return errSecretStorePathNotAllowed(ref.Name, ref.Path)
+		}
+	}
+	return nil
+}
+
+// verifyResourcesBlockingDeletion returns an error if the given project adds a resource type blocking its deletion which is not allowed in the config.
+// Resource types which the old project already contains are not validated, so that existing projects can still be updated after the allowlist has changed.
+// oldProject must be nil for new projects.
+func verifyResourcesBlockingDeletion(ctx context.Context, si config.SharedInformation, oldProject, project *pwv1alpha1.Project) error {
+	if len(project.Spec.ResourcesBlockingDeletion) == 0 {
+		return nil
+	}
+	allowed, err := si.AllowedProjectResourcesBlockingDeletion(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get allowed resources blocking project deletion from config: %w", err)
+	}
+	for _, gvk := range project.Spec.ResourcesBlockingDeletion {
+		if oldProject != nil && slices.Contains(oldProject.Spec.ResourcesBlockingDeletion, gvk) {
+			continue
+		}
+		if !slices.Contains(allowed, pwv1alpha1.NormalizeGroupVersionKind(gvk)) {
+			return errResourceBlockingDeletionNotAllowed(gvk.String())
+		}
+	}
+	return nil
+}
+
+// verifyProjectQuota returns an error if the given user has already created as many projects as they are allowed to.
+// The limit is taken from the ProjectQuotas for the user, the lowest one wins, or from the config if there is none. Excluded identities are not limited.
+// Concurrent requests are not serialized, so the limit can be exceeded slightly by parallel creations. If the lookups fail, the check is skipped if its failure policy is 'Ignore'.
+func verifyProjectQuota(ctx context.Context, c client.Client, si config.SharedInformation, ownIdentity *OwnIdentity, username string) error {
+	limit, err := si.MaxProjectsPerCreator(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get maximum number of projects per creator from config: %w", err)
+	}
+	quotas := &pwv1alpha1.ProjectQuotaList{}
+	if err := lookup(ctx, si, pwv1alpha1.WebhookCheckProjectQuota, func(ctx context.Context) error { return c.List(ctx, quotas) }); err != nil {
+		if failsOpen(ctx, si, pwv1alpha1.WebhookCheckProjectQuota, err) {
+			return nil
+		}
+		return fmt.Errorf("failed to list project quotas: %w", err)
+	}
+	fromQuota := false
+	for _, quota := range quotas.Items {
+		if quota.Spec.User == username && (!fromQuota || quota.Spec.MaxProjects < *limit) {
+			limit = ptr.To(quota.Spec.MaxProjects)
+			fromQuota = true
+		}
+	}
+	if limit == nil {
+		return nil
+	}
+
+	excluded, err := isExcludedIdentity(ctx, si, ownIdentity, username)
+	if err != nil {
+		return err
+	}
+	if excluded {
+		return nil
+	}
+
+	projects := &pwv1alpha1.ProjectList{}
+	if err := lookup(ctx, si, pwv1alpha1.WebhookCheckProjectQuota, func(ctx context.Context) error { return c.List(ctx, projects) }); err != nil {
+		if failsOpen(ctx, si, pwv1alpha1.WebhookCheckProjectQuota, err) {
+			return nil
+		}
+		return fmt.Errorf("failed to list projects: %w", err)
+	}
+	used := len(utils.ProjectsCreatedBy(projects.Items, username))
+	if used >= int(*limit) {
+		logging.FromContextOrPanic(ctx).Info("Rejecting project, the quota of the creator is exhausted", "user", username, "used", used, "limit", *limit)
+		return errProjectQuotaExceeded(username, *limit)
+	}
+	return nil
+}
+
 func (v *ProjectWebhook) ensureValidRole(ctx context.Context, project *pwv1alpha1.Project) (bool, error) {
 	userInfo, err := userInfoFromContext(ctx)
 	if err != nil {
 		return false, fmt.Errorf("failed to get userInfo")
 	}
-	if project.UserInfoHasRole(userInfo, pwv1alpha1.ProjectRoleAdmin) || userInfo.Username == v.Identity {
+	if project.UserInfoHasRole(userInfo, pwv1alpha1.ProjectRoleAdmin) {
+		return true, nil
+	}
+
+	excluded, err := isExcludedIdentity(ctx, v.SharedInformation, v.Identity, userInfo.Username)
+	if err != nil {
+		return false, err
+	}
+	if excluded {
 		return true, nil
 	}
 
-	overrides, err := v.SharedInformation.MemberOverrides(ctx)
+	overrides, err := getMemberOverrides(ctx, v.MemberOverridesCache, v.SharedInformation)
 	if err != nil {
 		return false, fmt.Errorf("failed to get member overrides: %w", err)
 	}
@@ -0,0 +1,189 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/api/v2/webhooktest"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+)
+
+func TestProjectWebhookAdmission(t *testing.T) {
+	newProject := func(name string, admin string) *pwv1alpha1.Project {
+		return &pwv1alpha1.Project{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: pwv1alpha1.ProjectSpec{Members: []pwv1alpha1.ProjectMember{
+				{Subject: pwv1alpha1.Subject{Kind: "User", Name: admin}, Roles: []pwv1alpha1.ProjectMemberRole{pwv1alpha1.ProjectRoleAdmin}},
+			}},
+		}
+	}
+	existing := newProject("existing", "jane")
+	existing.Annotations = map[string]string{pwv1alpha1.CreatedByAnnotation: "jane"}
+
+	tests := []struct {
+		description   string
+		objects       []client.Object
+		maxProjects   *int32
+		project       *pwv1alpha1.Project
+		user          string
+		expectAllowed bool
+		expectMessage string
+	}{
+		{
+			description:   "allows an admin member to create the project",
+			project:       newProject("foo", "jane"),
+			user:          "jane",
+			expectAllowed: true,
+		},
+		{
+			description:   "denies the creation by a user who is not an admin of the project",
+			project:       newProject("foo", "jane"),
+			user:          "john",
+			expectMessage: errRequestingUserNoAccess("john").Error(),
+		},
+		{
+			description:   "denies the creation if the creator has exhausted the limit",
+			objects:       []client.Object{existing},
+			maxProjects:   ptr.To(int32(1)),
+			project:       newProject("foo", "jane"),
+			user:          "jane",
+			expectMessage: errProjectQuotaExceeded("jane", 1).Error(),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			si := config.NewFakeSharedInformation(nil, nil, nil, nil)
+			si.MaxProjectsPerCreatorData = test.maxProjects
+			pwh := &ProjectWebhook{
+				Client:            webhooktest.NewFakeClient(test.objects...),
+				Identity:          NewStaticOwnIdentity("system:serviceaccount:pwo:operator"),
+				SharedInformation: si,
+			}
+			h := webhooktest.NewHarness[*pwv1alpha1.Project](pwh, pwh)
+
+			project, res := h.Admit(context.Background(), webhooktest.CreateRequest(test.project, webhooktest.AsUser(test.user)))
+			assert.Equal(t, test.expectAllowed, res.Allowed)
+			if !test.expectAllowed {
+				assert.Equal(t, test.expectMessage, res.Result.Message)
+				return
+			}
+			assert.Equal(t, test.user, project.Annotations[pwv1alpha1.CreatedByAnnotation])
+			assert.Equal(t, test.project.Name, project.Annotations[pwv1alpha1.DisplayNameAnnotation])
+		})
+	}
+}
+
+func TestProjectOwnershipTransferAdmission(t *testing.T) {
+	const operator = "system:serviceaccount:pwo:operator"
+	newProject := func(createdBy string, annotations ...string) *pwv1alpha1.Project {
+		project := &pwv1alpha1.Project{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "foo",
+				Annotations: map[string]string{pwv1alpha1.CreatedByAnnotation: createdBy},
+			},
+			Spec: pwv1alpha1.ProjectSpec{Members: []pwv1alpha1.ProjectMember{
+				{Subject: pwv1alpha1.Subject{Kind: "User", Name: "jane"}, Roles: []pwv1alpha1.ProjectMemberRole{pwv1alpha1.ProjectRoleAdmin}},
+				{Subject: pwv1alpha1.Subject{Kind: "User", Name: "john"}, Roles: []pwv1alpha1.ProjectMemberRole{pwv1alpha1.ProjectRoleView}},
+			}},
+		}
+		for i := 0; i+1 < len(annotations); i += 2 {
+			project.Annotations[annotations[i]] = annotations[i+1]
+		}
+		return project
+	}
+	overrides := pwv1alpha1.MemberOverrides{
+		{Subject: pwv1alpha1.Subject{Kind: "User", Name: "support"}, Roles: []pwv1alpha1.OverrideRole{pwv1alpha1.OverrideRoleAdmin}},
+	}
+
+	tests := []struct {
+		description   string
+		request       admission.Request
+		expectMessage string
+	}{
+		{
+			description: "allows deleting a project whose creator is an admin",
+			request:     webhooktest.DeleteRequest(newProject("jane"), webhooktest.AsUser("jane")),
+		},
+		{
+			description:   "denies deleting a project whose creator is no admin anymore",
+			request:       webhooktest.DeleteRequest(newProject("former"), webhooktest.AsUser("jane")),
+			expectMessage: errOwnerNotAdmin("former").Error(),
+		},
+		{
+			description: "allows deleting a project whose ownership is being transferred",
+			request:     webhooktest.DeleteRequest(newProject("former", pwv1alpha1.TransferOwnershipAnnotation, "jane"), webhooktest.AsUser("jane")),
+		},
+		{
+			description:   "denies the foreground deletion of a project",
+			request:       webhooktest.DeleteRequest(newProject("jane"), webhooktest.AsUser("jane"), withPropagationPolicy(metav1.DeletePropagationForeground)),
+			expectMessage: errForegroundDeletion.Error(),
+		},
+		{
+			description: "allows the background deletion of a project",
+			request:     webhooktest.DeleteRequest(newProject("jane"), webhooktest.AsUser("jane"), withPropagationPolicy(metav1.DeletePropagationBackground)),
+		},
+		{
+			description: "allows override admins to transfer the ownership to an admin",
+			request:     webhooktest.UpdateRequest(newProject("former"), newProject("former", pwv1alpha1.TransferOwnershipAnnotation, "jane"), webhooktest.AsUser("support")),
+		},
+		{
+			description:   "denies the transfer by project admins without override",
+			request:       webhooktest.UpdateRequest(newProject("former"), newProject("former", pwv1alpha1.TransferOwnershipAnnotation, "jane"), webhooktest.AsUser("jane")),
+			expectMessage: errOwnershipTransferNotAllowed("jane").Error(),
+		},
+		{
+			description:   "denies the transfer to a user who is no admin",
+			request:       webhooktest.UpdateRequest(newProject("former"), newProject("former", pwv1alpha1.TransferOwnershipAnnotation, "john"), webhooktest.AsUser("support")),
+			expectMessage: errNewOwnerNotAdmin("john").Error(),
+		},
+		{
+			description: "allows the operator to replace the creator with the new owner",
+			request:     webhooktest.UpdateRequest(newProject("former", pwv1alpha1.TransferOwnershipAnnotation, "jane"), newProject("jane"), webhooktest.AsUser(operator)),
+		},
+		{
+			description:   "denies replacing the creator without transfer",
+			request:       webhooktest.UpdateRequest(newProject("former"), newProject("jane"), webhooktest.AsUser(operator)),
+			expectMessage: errAnnotationImmutable(pwv1alpha1.CreatedByAnnotation).Error(),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			pwh := &ProjectWebhook{
+				Client:            webhooktest.NewFakeClient(),
+				Identity:          NewStaticOwnIdentity(operator),
+				SharedInformation: config.NewFakeSharedInformation(nil, nil, nil, overrides),
+			}
+			h := webhooktest.NewHarness[*pwv1alpha1.Project](pwh, pwh)
+
+			res := h.Validate(context.Background(), test.request)
+			if test.expectMessage == "" {
+				assert.True(t, res.Allowed, res.Result)
+				return
+			}
+			if assert.False(t, res.Allowed) {
+				assert.Equal(t, test.expectMessage, res.Result.Message)
+			}
+		})
+	}
+}
+
+// withPropagationPolicy sets delete options with the given propagation policy on the request.
+func withPropagationPolicy(policy metav1.DeletionPropagation) webhooktest.RequestOption {
+	return func(req *admission.Request) {
+		raw, err := json.Marshal(&metav1.DeleteOptions{PropagationPolicy: &policy})
+		if err != nil {
+			panic(err)
+		}
+		req.Options.Raw = raw
+	}
+}
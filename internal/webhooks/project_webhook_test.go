@@ -1,17 +1,27 @@
 package webhooks
 
 import (
+	"strings"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
 )
 
 var _ = Describe("Project Webhook", func() {
 	BeforeEach(func() {
 		sharedInformationForTests.MemberOverridesData = nil
+		sharedInformationForTests.AddCreatorAsAdminData = false
+		sharedInformationForTests.MemberPolicyData = pwv1alpha1.MemberPolicyConfig{}
+		sharedInformationForTests.SecretStoresData = nil
+		sharedInformationForTests.AllowedProjectBlockingResourcesData = nil
+		sharedInformationForTests.MaxProjectsPerCreatorData = nil
 	})
 
 	Context("When creating a Project", func() {
@@ -275,6 +285,347 @@ var _ = Describe("Project Webhook", func() {
 			err = realUserClient.Create(ctx, project)
 			Expect(err).To(HaveOccurred())
 		})
+
+		It("Should add the creator as admin to a project without admins if enabled", func() {
+			var err error
+
+			sharedInformationForTests.AddCreatorAsAdminData = true
+
+			project := &pwv1alpha1.Project{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: uniqueName(),
+				},
+				Spec: pwv1alpha1.ProjectSpec{
+					Members: []pwv1alpha1.ProjectMember{
+						{
+							Subject: pwv1alpha1.Subject{
+								Kind: "User",
+								Name: "viewer",
+							},
+							Roles: []pwv1alpha1.ProjectMemberRole{
+								pwv1alpha1.ProjectRoleView,
+							},
+						},
+					},
+				},
+			}
+
+			err = realUserClient.Create(ctx, project)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(project.Spec.Members).To(ContainElement(pwv1alpha1.ProjectMember{
+				Subject: pwv1alpha1.Subject{
+					Kind: "User",
+					Name: "admin",
+				},
+				Roles: []pwv1alpha1.ProjectMemberRole{
+					pwv1alpha1.ProjectRoleAdmin,
+				},
+			}))
+		})
+
+		It("Should add the admin role to the creator if it is already a project member and adding is enabled", func() {
+			var err error
+
+			sharedInformationForTests.AddCreatorAsAdminData = true
+
+			project := &pwv1alpha1.Project{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: uniqueName(),
+				},
+				Spec: pwv1alpha1.ProjectSpec{
+					Members: []pwv1alpha1.ProjectMember{
+						{
+							Subject: pwv1alpha1.Subject{
+								Kind: "User",
+								Name: "admin",
+							},
+							Roles: []pwv1alpha1.ProjectMemberRole{
+								pwv1alpha1.ProjectRoleView,
+							},
+						},
+					},
+				},
+			}
+
+			err = realUserClient.Create(ctx, project)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(project.Spec.Members).To(HaveLen(1))
+			Expect(project.Spec.Members[0].Roles).To(ConsistOf(pwv1alpha1.ProjectRoleView, pwv1alpha1.ProjectRoleAdmin))
+		})
+
+		It("should deny to create a project without admins if adding the creator is disabled", func() {
+			var err error
+
+			project := &pwv1alpha1.Project{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: uniqueName(),
+				},
+				Spec: pwv1alpha1.ProjectSpec{
+					Members: []pwv1alpha1.ProjectMember{
+						{
+							Subject: pwv1alpha1.Subject{
+								Kind: "User",
+								Name: "viewer",
+							},
+							Roles: []pwv1alpha1.ProjectMemberRole{
+								pwv1alpha1.ProjectRoleView,
+							},
+						},
+					},
+				},
+			}
+
+			err = realUserClient.Create(ctx, project)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should deny to create a project with user members if only groups are allowed", func() {
+			sharedInformationForTests.MemberPolicyData = pwv1alpha1.MemberPolicyConfig{GroupsOnly: true}
+
+			project := &pwv1alpha1.Project{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: uniqueName(),
+				},
+				Spec: pwv1alpha1.ProjectSpec{
+					Members: []pwv1alpha1.ProjectMember{
+						{
+							Subject: pwv1alpha1.Subject{
+								Kind: "User",
+								Name: "admin",
+							},
+							Roles: []pwv1alpha1.ProjectMemberRole{
+								pwv1alpha1.ProjectRoleAdmin,
+							},
+						},
+					},
+				},
+			}
+
+			err := realUserClient.Create(ctx, project)
+			Expect(err).To(MatchError(ContainSubstring("access to a project must be granted via groups")))
+		})
+
+		It("should default the display name to the project name", func() {
+			project := &pwv1alpha1.Project{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: uniqueName(),
+				},
+				Spec: pwv1alpha1.ProjectSpec{
+					Members: []pwv1alpha1.ProjectMember{
+						{
+							Subject: pwv1alpha1.Subject{
+								Kind: "User",
+								Name: "admin",
+							},
+							Roles: []pwv1alpha1.ProjectMemberRole{
+								pwv1alpha1.ProjectRoleAdmin,
+							},
+						},
+					},
+				},
+			}
+
+			err := realUserClient.Create(ctx, project)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(project.GetAnnotations()).To(HaveKeyWithValue(pwv1alpha1.DisplayNameAnnotation, project.Name))
+		})
+
+		It("should deny to create a project with an invalid display name", func() {
+			for _, displayName := range []string{" ", " Leading Space", "Line\nBreak", strings.Repeat("x", 65)} {
+				project := &pwv1alpha1.Project{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: uniqueName(),
+						Annotations: map[string]string{
+							pwv1alpha1.DisplayNameAnnotation: displayName,
+						},
+					},
+					Spec: pwv1alpha1.ProjectSpec{
+						Members: []pwv1alpha1.ProjectMember{
+							{
+								Subject: pwv1alpha1.Subject{
+									Kind: "User",
+									Name: "admin",
+								},
+								Roles: []pwv1alpha1.ProjectMemberRole{
+									pwv1alpha1.ProjectRoleAdmin,
+								},
+							},
+						},
+					},
+				}
+
+				err := realUserClient.Create(ctx, project)
+				Expect(err).To(MatchError(ContainSubstring("invalid value")), "display name %q", displayName)
+			}
+		})
+
+		It("should deny to create a project labeled with another project", func() {
+			project := &pwv1alpha1.Project{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: uniqueName(),
+					Labels: map[string]string{
+						utils.LabelProject: "another-project",
+					},
+				},
+				Spec: pwv1alpha1.ProjectSpec{
+					Members: []pwv1alpha1.ProjectMember{
+						{
+							Subject: pwv1alpha1.Subject{
+								Kind: "User",
+								Name: "admin",
+							},
+							Roles: []pwv1alpha1.ProjectMemberRole{
+								pwv1alpha1.ProjectRoleAdmin,
+							},
+						},
+					},
+				},
+			}
+
+			err := realUserClient.Create(ctx, project)
+			Expect(err).To(MatchError(ContainSubstring("cannot be repurposed")))
+		})
+
+		It("should only allow references to configured secret stores and paths", func() {
+			sharedInformationForTests.SecretStoresData = []pwv1alpha1.SecretStoreConfig{
+				{Name: "vault", PathPrefix: "teams/{{ .Project }}/"},
+			}
+			newProject := func(ref pwv1alpha1.SecretStoreReference) *pwv1alpha1.Project {
+				return &pwv1alpha1.Project{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: uniqueName(),
+					},
+					Spec: pwv1alpha1.ProjectSpec{
+						Members: []pwv1alpha1.ProjectMember{
+							{
+								Subject: pwv1alpha1.Subject{
+									Kind: "User",
+									Name: "admin",
+								},
+								Roles: []pwv1alpha1.ProjectMemberRole{
+									pwv1alpha1.ProjectRoleAdmin,
+								},
+							},
+						},
+						SecretStores: []pwv1alpha1.SecretStoreReference{ref},
+					},
+				}
+			}
+
+			project := newProject(pwv1alpha1.SecretStoreReference{Name: "vault"})
+			project.Spec.SecretStores[0].Path = "teams/" + project.Name + "/app"
+			Expect(realUserClient.Create(ctx, project)).To(Succeed())
+
+			project = newProject(pwv1alpha1.SecretStoreReference{Name: "other", Path: "app"})
+			Expect(realUserClient.Create(ctx, project)).To(MatchError(ContainSubstring("is not configured")))
+
+			project = newProject(pwv1alpha1.SecretStoreReference{Name: "vault", Path: "teams/someone-else/app"})
+			Expect(realUserClient.Create(ctx, project)).To(MatchError(ContainSubstring("is not allowed")))
+
+			project = newProject(pwv1alpha1.SecretStoreReference{Name: "vault"})
+			project.Spec.SecretStores[0].Path = "teams/" + project.Name + "/../someone-else"
+			Expect(realUserClient.Create(ctx, project)).To(MatchError(ContainSubstring("is not allowed")))
+		})
+
+		It("should only allow resource types blocking deletion which are allowed in the config", func() {
+			sharedInformationForTests.AllowedProjectBlockingResourcesData = []metav1.GroupVersionKind{
+				{Group: "example.com", Version: "v1", Kind: "Database"},
+			}
+			newProject := func(gvk metav1.GroupVersionKind) *pwv1alpha1.Project {
+				return &pwv1alpha1.Project{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: uniqueName(),
+					},
+					Spec: pwv1alpha1.ProjectSpec{
+						Members: []pwv1alpha1.ProjectMember{
+							{
+								Subject: pwv1alpha1.Subject{
+									Kind: "User",
+									Name: "admin",
+								},
+								Roles: []pwv1alpha1.ProjectMemberRole{
+									pwv1alpha1.ProjectRoleAdmin,
+								},
+							},
+						},
+						ResourcesBlockingDeletion: []metav1.GroupVersionKind{gvk},
+					},
+				}
+			}
+
+			project := newProject(metav1.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "database"})
+			Expect(realUserClient.Create(ctx, project)).To(Succeed())
+
+			Expect(realUserClient.Create(ctx, newProject(metav1.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Cache"}))).
+				To(MatchError(ContainSubstring("is not allowed to block the deletion")))
+
+			// resource types which have been removed from the allowlist do not prevent updates
+			sharedInformationForTests.AllowedProjectBlockingResourcesData = nil
+			Expect(realUserClient.Get(ctx, client.ObjectKeyFromObject(project), project)).To(Succeed())
+			metav1.SetMetaDataLabel(&project.ObjectMeta, "team", "a")
+			Expect(realUserClient.Update(ctx, project)).To(Succeed())
+		})
+
+		Context("with project quotas", func() {
+			newProject := func() *pwv1alpha1.Project {
+				return &pwv1alpha1.Project{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: uniqueName(),
+					},
+					Spec: pwv1alpha1.ProjectSpec{
+						Members: []pwv1alpha1.ProjectMember{
+							{
+								Subject: pwv1alpha1.Subject{
+									Kind: "User",
+									Name: "admin",
+								},
+								Roles: []pwv1alpha1.ProjectMemberRole{
+									pwv1alpha1.ProjectRoleAdmin,
+								},
+							},
+						},
+					},
+				}
+			}
+			createQuota := func(maxProjects int32) {
+				quota := &pwv1alpha1.ProjectQuota{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: uniqueName(),
+					},
+					Spec: pwv1alpha1.ProjectQuotaSpec{
+						User:        "admin",
+						MaxProjects: maxProjects,
+					},
+				}
+				Expect(k8sClient.Create(ctx, quota)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, quota))).To(Succeed())
+				})
+			}
+
+			It("should reject projects of users who have exhausted their quota", func() {
+				createQuota(0)
+
+				Eventually(func() error {
+					return realUserClient.Create(ctx, newProject())
+				}).Should(MatchError(ContainSubstring("maximum number of 0 projects")))
+			})
+
+			It("should apply the limit from the config to users without a quota", func() {
+				sharedInformationForTests.MaxProjectsPerCreatorData = ptr.To(int32(0))
+
+				Expect(realUserClient.Create(ctx, newProject())).To(MatchError(ContainSubstring("maximum number of 0 projects")))
+			})
+
+			It("should prefer the quota of the user over the limit from the config", func() {
+				sharedInformationForTests.MaxProjectsPerCreatorData = ptr.To(int32(0))
+				createQuota(100000)
+
+				Eventually(func() error {
+					return realUserClient.Create(ctx, newProject())
+				}).Should(Succeed())
+			})
+		})
 	})
 
 	Context("When updating a Project", func() {
@@ -309,6 +660,102 @@ var _ = Describe("Project Webhook", func() {
 			Expect(err).To(HaveOccurred())
 		})
 
+		It("should record the user who requested an automation token", func() {
+			var err error
+
+			project := &pwv1alpha1.Project{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: uniqueName(),
+				},
+				Spec: pwv1alpha1.ProjectSpec{
+					Members: []pwv1alpha1.ProjectMember{
+						{
+							Subject: pwv1alpha1.Subject{
+								Kind: "User",
+								Name: "admin",
+							},
+							Roles: []pwv1alpha1.ProjectMemberRole{
+								pwv1alpha1.ProjectRoleAdmin,
+							},
+						},
+					},
+				},
+			}
+
+			err = realUserClient.Create(ctx, project)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			project.Annotations[pwv1alpha1.AutomationTokenRequestAnnotation] = "30m"
+			project.Annotations[pwv1alpha1.AutomationTokenRequestedByAnnotation] = "someone-else"
+
+			err = realUserClient.Update(ctx, project)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(project.GetAnnotations()).To(HaveKeyWithValue(pwv1alpha1.AutomationTokenRequestedByAnnotation, "admin"))
+		})
+
+		It("should deny to change the display name to an invalid value", func() {
+			project := &pwv1alpha1.Project{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: uniqueName(),
+					Annotations: map[string]string{
+						pwv1alpha1.DisplayNameAnnotation: "My Project",
+					},
+				},
+				Spec: pwv1alpha1.ProjectSpec{
+					Members: []pwv1alpha1.ProjectMember{
+						{
+							Subject: pwv1alpha1.Subject{
+								Kind: "User",
+								Name: "admin",
+							},
+							Roles: []pwv1alpha1.ProjectMemberRole{
+								pwv1alpha1.ProjectRoleAdmin,
+							},
+						},
+					},
+				},
+			}
+
+			err := realUserClient.Create(ctx, project)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			project.Annotations[pwv1alpha1.DisplayNameAnnotation] = "My Project\t"
+
+			err = realUserClient.Update(ctx, project)
+			Expect(err).To(MatchError(ContainSubstring("invalid value")))
+		})
+
+		It("should keep existing secret store references which are not allowed anymore", func() {
+			sharedInformationForTests.SecretStoresData = []pwv1alpha1.SecretStoreConfig{{Name: "vault"}}
+			project := &pwv1alpha1.Project{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: uniqueName(),
+				},
+				Spec: pwv1alpha1.ProjectSpec{
+					Members: []pwv1alpha1.ProjectMember{
+						{
+							Subject: pwv1alpha1.Subject{
+								Kind: "User",
+								Name: "admin",
+							},
+							Roles: []pwv1alpha1.ProjectMemberRole{
+								pwv1alpha1.ProjectRoleAdmin,
+							},
+						},
+					},
+					SecretStores: []pwv1alpha1.SecretStoreReference{{Name: "vault", Path: "app"}},
+				},
+			}
+			Expect(realUserClient.Create(ctx, project)).To(Succeed())
+
+			sharedInformationForTests.SecretStoresData = nil
+			project.Spec.Members[0].Roles = append(project.Spec.Members[0].Roles, pwv1alpha1.ProjectRoleView)
+			Expect(realUserClient.Update(ctx, project)).To(Succeed())
+
+			project.Spec.SecretStores[0].Path = "other-app"
+			Expect(realUserClient.Update(ctx, project)).To(MatchError(ContainSubstring("is not configured")))
+		})
+
 		It("Should allow to update the project by a user in MemberOverrides", func() {
 			var err error
 			var projectName = uniqueName()
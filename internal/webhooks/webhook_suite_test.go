@@ -34,6 +34,8 @@ import (
 
 	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
 	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/webhookconfig"
 )
 
 // These tests use Ginkgo (BDD-style Go testing framework). Refer to
@@ -50,6 +52,9 @@ var testScheme *apimachineryruntime.Scheme
 
 var sharedInformationForTests *config.FakeSharedInformation
 
+// testProjectNamespace is a project namespace in which workspaces can be created.
+var testProjectNamespace *corev1.Namespace
+
 func TestAPIs(t *testing.T) {
 	RegisterFailHandler(Fail)
 
@@ -97,7 +102,7 @@ var _ = BeforeSuite(func() {
 	Expect(err).NotTo(HaveOccurred())
 	Expect(k8sClient).NotTo(BeNil())
 
-	identity := "nobody"
+	identity := NewStaticOwnIdentity("nobody")
 
 	// start webhook server using Manager
 	webhookInstallOptions := &testEnv.WebhookInstallOptions
@@ -115,10 +120,10 @@ var _ = BeforeSuite(func() {
 
 	sharedInformationForTests = config.NewFakeSharedInformation(nil, nil, nil, nil)
 
-	err = SetupProjectWebhookWithManager(ctx, mgr, identity, sharedInformationForTests)
+	err = SetupProjectWebhookWithManager(ctx, mgr, identity, sharedInformationForTests, nil, webhookconfig.Scope{})
 	Expect(err).NotTo(HaveOccurred())
 
-	err = SetupWorkspaceWebhookWithManager(ctx, mgr, identity, sharedInformationForTests)
+	err = SetupWorkspaceWebhookWithManager(ctx, mgr, identity, sharedInformationForTests, nil, webhookconfig.Scope{})
 	Expect(err).NotTo(HaveOccurred())
 
 	// +kubebuilder:scaffold:webhook
@@ -206,6 +211,10 @@ var _ = BeforeSuite(func() {
 			Fail("Failed to create/update cluster role binding")
 		}
 	}
+
+	testProjectNamespace = projectNamespace("webhook-test")
+	err = k8sClient.Create(ctx, testProjectNamespace)
+	Expect(err).NotTo(HaveOccurred())
 })
 
 var _ = AfterSuite(func() {
@@ -235,3 +244,15 @@ func uniqueName() string {
 	uuidStr := string(uuidHex[:25])
 	return uuidStr
 }
+
+// projectNamespace returns a namespace which belongs to the project with the given name.
+func projectNamespace(projectName string) *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "project-" + projectName,
+			Labels: map[string]string{
+				utils.LabelProject: projectName,
+			},
+		},
+	}
+}
@@ -2,10 +2,18 @@ package webhooks
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -14,30 +22,50 @@ import (
 
 	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
 	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/features"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/projectresolver"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/utils"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/webhookconfig"
 )
 
-const WorkspaceWebhookName = "workspace-webhook"
+const (
+	WorkspaceWebhookName = "workspace-webhook"
+	// workspaceResource is the name of workspaces in error messages.
+	workspaceResource = "workspace"
+	// maxDeletionBlockers is the maximum number of resources blocking the deletion of a workspace which are listed in the error message.
+	maxDeletionBlockers = 10
+)
 
 // +kubebuilder:object:generate=false
 type WorkspaceWebhook struct {
 	client.Client
+	// APIReader is used to fetch the namespace and the WorkspaceClass of a new workspace.
+	// It reads directly from the API server, because they might have been created just before the workspace.
+	APIReader client.Reader
 
-	// Identity is the name of the entity (usually a service account) the platform-service-project-workspace uses to access the onboarding cluster.
+	// Identity is the identity of the entity (usually a service account) the platform-service-project-workspace uses to access the onboarding cluster.
 	// It is required to exclude the operator's own identity from validation checks.
-	Identity          string
+	// Further identities can be excluded via the ProjectWorkspaceConfig.
+	Identity          *OwnIdentity
 	SharedInformation config.SharedInformation
+	// MemberOverridesCache optionally serves the MemberOverrides from the cache of the platform cluster, instead of from the SharedInformation.
+	MemberOverridesCache *MemberOverridesCache
 }
 
-func SetupWorkspaceWebhookWithManager(ctx context.Context, mgr ctrl.Manager, identity string, si config.SharedInformation) error {
+// SetupWorkspaceWebhookWithManager registers the Workspace webhook at the given manager. overrides may be nil.
+// scope identifies the platform service instance, whose environment is set as label on Workspaces without one, see withEnvironment.
+func SetupWorkspaceWebhookWithManager(ctx context.Context, mgr ctrl.Manager, identity *OwnIdentity, si config.SharedInformation, overrides *MemberOverridesCache, scope webhookconfig.Scope) error {
 	wswh := &WorkspaceWebhook{
-		Client:            mgr.GetClient(),
-		SharedInformation: si,
-		Identity:          identity,
+		Client:               mgr.GetClient(),
+		APIReader:            mgr.GetAPIReader(),
+		SharedInformation:    si,
+		Identity:             identity,
+		MemberOverridesCache: overrides,
 	}
 
 	return ctrl.NewWebhookManagedBy(mgr, &pwv1alpha1.Workspace{}).
-		WithDefaulter(wswh).
-		WithValidator(wswh).
+		WithDefaulter(withEnvironment[*pwv1alpha1.Workspace](wswh, scope, pwv1alpha1.GroupVersion.WithKind("Workspace"), mgr.GetAPIReader())).
+		WithValidator(withLookupBudget[*pwv1alpha1.Workspace](wswh, si)).
 		Complete()
 }
 
@@ -57,8 +85,37 @@ func (w *WorkspaceWebhook) Default(ctx context.Context, obj *pwv1alpha1.Workspac
 		return err
 	}
 
-	setCreatedBy(workspace, req)
+	if err := setCreatedBy(ctx, w.SharedInformation, w.Identity, workspace, req); err != nil {
+		return err
+	}
+
+	return w.addCreatorAsAdmin(ctx, workspace, req)
+}
+
+// addCreatorAsAdmin adds the requesting user as admin to a new workspace without admin members, if this is enabled in the config.
+// If the user is already a member, the admin role is added to the existing member.
+// Workspaces which inherit the project members are not modified, since they get their admins from the project.
+func (w *WorkspaceWebhook) addCreatorAsAdmin(ctx context.Context, workspace *pwv1alpha1.Workspace, req admission.Request) error {
+	hasAdmin := workspace.InheritsProjectMembers() || slices.ContainsFunc(workspace.Spec.Members, func(m pwv1alpha1.WorkspaceMember) bool {
+		return slices.Contains(m.Roles, pwv1alpha1.WorkspaceRoleAdmin)
+	})
+	add, err := shouldAddCreatorAsAdmin(ctx, w.SharedInformation, w.Identity, req, hasAdmin)
+	if err != nil || !add {
+		return err
+	}
 
+	subject := subjectForUsername(req.UserInfo.Username)
+	logging.FromContextOrPanic(ctx).Info("Adding requesting user as admin", "kind", subject.Kind, "name", subject.Name)
+	for i := range workspace.Spec.Members {
+		if workspace.Spec.Members[i].Subject == subject {
+			workspace.Spec.Members[i].Roles = append(workspace.Spec.Members[i].Roles, pwv1alpha1.WorkspaceRoleAdmin)
+			return nil
+		}
+	}
+	workspace.Spec.Members = append(workspace.Spec.Members, pwv1alpha1.WorkspaceMember{
+		Subject: subject,
+		Roles:   []pwv1alpha1.WorkspaceMemberRole{pwv1alpha1.WorkspaceRoleAdmin},
+	})
 	return nil
 }
 
@@ -75,11 +132,43 @@ func (v *WorkspaceWebhook) ValidateCreate(ctx context.Context, obj *pwv1alpha1.W
 		return
 	}
 	log.Info("Validate create")
+	warnings = workspaceMemberWarnings(workspace)
+
+	if err = verifyKnownWorkspaceRoles(workspace); err != nil {
+		return
+	}
+	if err = verifyChargingTarget(ctx, v.SharedInformation, nil, workspace, false); err != nil {
+		return
+	}
+	if err = verifyProviderHints(ctx, v.SharedInformation, nil, workspace); err != nil {
+		return
+	}
+	if err = v.ensureProjectNamespace(ctx, workspace); err != nil {
+		return
+	}
+	if err = v.ensureWorkspaceClassExists(ctx, workspace); err != nil {
+		return
+	}
+	if err = v.ensureServiceAccountMembersAllowed(ctx, nil, workspace); err != nil {
+		return
+	}
 
 	userInfo, err := userInfoFromContext(ctx)
 	if err != nil {
 		return
 	}
+	if err = verifyCreatedByRequester(ctx, v.SharedInformation, v.Identity, workspace, userInfo.Username); err != nil {
+		return
+	}
+	if err = verifyMemberPolicy(ctx, v.SharedInformation, v.Identity, userInfo.Username, workspaceResource, nil, workspaceSubjects(workspace)); err != nil {
+		return
+	}
+	if err = verifyServiceAccountNamespacesNotDenied(ctx, v.SharedInformation, v.MemberOverridesCache, v.Identity, userInfo, workspaceResource, workspace.Name, pwv1alpha1.OverrideResourceKindWorkspace, nil, workspaceSubjects(workspace)); err != nil {
+		return
+	}
+	if err = verifyManagedMembersUnchanged(ctx, v.SharedInformation, v.Identity, userInfo.Username, nil, workspace, nil, workspaceMemberRoles(workspace)); err != nil {
+		return
+	}
 	validRole, err := v.ensureValidRole(ctx, workspace)
 	if err != nil {
 		return warnings, err
@@ -106,8 +195,26 @@ func (v *WorkspaceWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj *p
 	}
 
 	log.Info("Validate update")
+	warnings = workspaceMemberWarnings(newWorkspace)
 
-	if err = verifyCreatedByUnchanged(oldWorkspace, newWorkspace); err != nil {
+	if err = verifyKnownWorkspaceRoles(newWorkspace); err != nil {
+		return
+	}
+	if err = verifyImmutableAnnotationsUnchanged(oldWorkspace, newWorkspace); err != nil {
+		return
+	}
+	if err = verifyChargingTarget(ctx, v.SharedInformation, oldWorkspace, newWorkspace, false); err != nil {
+		return
+	}
+	if err = verifyProviderHints(ctx, v.SharedInformation, oldWorkspace, newWorkspace); err != nil {
+		return
+	}
+	if oldWorkspace.Spec.ClassName != newWorkspace.Spec.ClassName {
+		if err = v.ensureWorkspaceClassExists(ctx, newWorkspace); err != nil {
+			return
+		}
+	}
+	if err = v.ensureServiceAccountMembersAllowed(ctx, oldWorkspace, newWorkspace); err != nil {
 		return
 	}
 
@@ -115,6 +222,15 @@ func (v *WorkspaceWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj *p
 	if err != nil {
 		return
 	}
+	if err = verifyMemberPolicy(ctx, v.SharedInformation, v.Identity, userInfo.Username, workspaceResource, workspaceSubjects(oldWorkspace), workspaceSubjects(newWorkspace)); err != nil {
+		return
+	}
+	if err = verifyServiceAccountNamespacesNotDenied(ctx, v.SharedInformation, v.MemberOverridesCache, v.Identity, userInfo, workspaceResource, newWorkspace.Name, pwv1alpha1.OverrideResourceKindWorkspace, workspaceSubjects(oldWorkspace), workspaceSubjects(newWorkspace)); err != nil {
+		return
+	}
+	if err = verifyManagedMembersUnchanged(ctx, v.SharedInformation, v.Identity, userInfo.Username, oldWorkspace, newWorkspace, workspaceMemberRoles(oldWorkspace), workspaceMemberRoles(newWorkspace)); err != nil {
+		return
+	}
 	validRole, err := v.ensureValidRole(ctx, oldWorkspace)
 	if err != nil {
 		return warnings, err
@@ -130,6 +246,9 @@ func (v *WorkspaceWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj *p
 	if !validNewRole {
 		return warnings, errRequestingUserNoAccess(userInfo.Username)
 	}
+	if err = v.ensureInheritedAdminsKept(ctx, oldWorkspace, newWorkspace); err != nil {
+		return
+	}
 
 	return
 }
@@ -148,6 +267,7 @@ func (v *WorkspaceWebhook) ValidateDelete(ctx context.Context, obj *pwv1alpha1.W
 	if validRole, err := v.ensureValidRole(ctx, workspace); !validRole {
 		return warnings, err
 	}
+	warnings, err = v.ensureNoResourcesBlockingDeletion(ctx, workspace)
 	return
 }
 
@@ -160,16 +280,236 @@ func expectWorkspace(obj runtime.Object) (*pwv1alpha1.Workspace, error) {
 	return workspace, nil
 }
 
+// workspaceMemberWarnings returns admission warnings for members of the given workspace with redundant roles.
+func workspaceMemberWarnings(workspace *pwv1alpha1.Workspace) admission.Warnings {
+	var warnings admission.Warnings
+	for _, member := range workspace.Spec.Members {
+		if w := redundantAuditorRoleWarning(member.Subject, member.Roles, pwv1alpha1.WorkspaceRoleAuditor); w != "" {
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
+
+// verifyKnownWorkspaceRoles returns an error if any member or the role mapping for inherited project members of the given workspace
+// contains a role which is not known to this version of the platform service.
+func verifyKnownWorkspaceRoles(workspace *pwv1alpha1.Workspace) error {
+	var unknown []string
+	for _, member := range workspace.Spec.Members {
+		unknown = append(unknown, unknownMemberRoles(member.Subject, member.Roles, pwv1alpha1.WorkspaceMemberRoles())...)
+	}
+	if workspace.Spec.InheritProjectMembers != nil {
+		mapping := workspace.Spec.InheritProjectMembers.RoleMapping
+		for _, projectRole := range slices.Sorted(maps.Keys(mapping)) {
+			if !slices.Contains(pwv1alpha1.ProjectMemberRoles(), projectRole) {
+				unknown = append(unknown, fmt.Sprintf("project role '%s' in the role mapping", projectRole))
+			}
+			if workspaceRole := mapping[projectRole]; !slices.Contains(pwv1alpha1.WorkspaceMemberRoles(), workspaceRole) {
+				unknown = append(unknown, fmt.Sprintf("workspace role '%s' in the role mapping", workspaceRole))
+			}
+		}
+	}
+	if len(unknown) > 0 {
+		return errUnknownRoles(unknown)
+	}
+	return nil
+}
+
+// verifyProviderHints returns an error if a new or changed provider hint of the given workspace is not available, because it is not configured
+// or its ServiceProvider is not registered, or if its value is not allowed. Unchanged hints are not validated, so that existing workspaces
+// can still be updated after the configuration has changed. oldWorkspace must be nil for new workspaces.
+func verifyProviderHints(ctx context.Context, si config.SharedInformation, oldWorkspace, workspace *pwv1alpha1.Workspace) error {
+	if len(workspace.Spec.ProviderHints) == 0 {
+		return nil
+	}
+	hints, err := si.WorkspaceProviderHints(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get provider hints from config: %w", err)
+	}
+	// sort the keys, so that the same error is returned for each request
+	for _, key := range slices.Sorted(maps.Keys(workspace.Spec.ProviderHints)) {
+		value := workspace.Spec.ProviderHints[key]
+		if oldWorkspace != nil {
+			if oldValue, ok := oldWorkspace.Spec.ProviderHints[key]; ok && oldValue == value {
+				continue
+			}
+		}
+		idx := slices.IndexFunc(hints, func(hint pwv1alpha1.ProviderHintConfig) bool { return hint.Key == key })
+		if idx < 0 {
+			return errProviderHintNotAvailable(key)
+		}
+		if !hints[idx].AllowsValue(value) {
+			return errProviderHintValueNotAllowed(key, value, hints[idx].AllowedValues)
+		}
+	}
+	return nil
+}
+
+// ensureProjectNamespace returns an error if the namespace of the given workspace does not belong to a project.
+// Workspaces in such namespaces could not be reconciled, because the owning project cannot be determined.
+func (v *WorkspaceWebhook) ensureProjectNamespace(ctx context.Context, workspace *pwv1alpha1.Workspace) error {
+	if _, err := v.projectName(ctx, workspace.Namespace); err != nil {
+		if apierrors.IsNotFound(err) || isNoProjectNamespace(err) {
+			return errNamespaceNotManagedByProject(workspace.Namespace)
+		}
+		return fmt.Errorf("failed to get namespace %s: %w", workspace.Namespace, err)
+	}
+	return nil
+}
+
+// projectName returns the name of the project the given namespace belongs to, see projectresolver.Resolver.ProjectName.
+// The namespace is always read via the APIReader instead of the cache, since the project decides whether the request is allowed
+// and a stale project label must not grant the members of the previous project access to the workspace.
+func (v *WorkspaceWebhook) projectName(ctx context.Context, namespace string) (string, error) {
+	projects := projectresolver.New(nil, v.APIReader)
+	var projectName string
+	var noProjectErr error
+	if err := lookup(ctx, v.SharedInformation, checkMembership, func(ctx context.Context) error {
+		name, err := projects.ProjectName(ctx, namespace)
+		if isNoProjectNamespace(err) {
+			// a namespace without project is a valid result, which must neither be retried nor counted as failed lookup
+			noProjectErr = err
+			return nil
+		}
+		projectName = name
+		return err
+	}); err != nil {
+		return "", err
+	}
+	return projectName, noProjectErr
+}
+
+// isNoProjectNamespace returns true if the error indicates that a namespace does not belong to any project.
+func isNoProjectNamespace(err error) bool {
+	return errors.Is(err, projectresolver.ErrNamespaceHasNoLabels) || errors.Is(err, projectresolver.ErrNamespaceHasNoProjectLabel)
+}
+
+// workspaceSubjects returns the subjects of the members of the given workspace.
+func workspaceSubjects(workspace *pwv1alpha1.Workspace) []pwv1alpha1.Subject {
+	subjects := make([]pwv1alpha1.Subject, 0, len(workspace.Spec.Members))
+	for _, member := range workspace.Spec.Members {
+		subjects = append(subjects, member.Subject)
+	}
+	return subjects
+}
+
+// workspaceMemberRoles returns the sorted roles of the members of the given workspace by subject.
+func workspaceMemberRoles(workspace *pwv1alpha1.Workspace) map[pwv1alpha1.Subject][]string {
+	roles := make(map[pwv1alpha1.Subject][]string, len(workspace.Spec.Members))
+	for _, member := range workspace.Spec.Members {
+		roles[member.Subject] = sortedRoles(member.Roles)
+	}
+	return roles
+}
+
+// ensureServiceAccountMembersAllowed returns an error if the given workspace contains ServiceAccount members from namespaces
+// which neither belong to the project of the workspace nor are allowed by the config.
+// On update, only members which have been added are validated, so that workspaces created before the restriction was enabled can still be modified.
+// The workspace controller re-validates all members and does not bind rejected ones, since the config or the namespaces might change afterwards.
+func (v *WorkspaceWebhook) ensureServiceAccountMembersAllowed(ctx context.Context, oldWorkspace, workspace *pwv1alpha1.Workspace) error {
+	policy, err := v.SharedInformation.ServiceAccountMembers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get ServiceAccount member restrictions: %w", err)
+	}
+	if !policy.RestrictToProject {
+		return nil
+	}
+
+	var projectName string
+	namespaceProjects := map[string]string{}
+	rejected := []string{}
+	for _, member := range workspace.Spec.Members {
+		if member.Kind != rbacv1.ServiceAccountKind {
+			continue
+		}
+		if oldWorkspace != nil && slices.ContainsFunc(oldWorkspace.Spec.Members, func(m pwv1alpha1.WorkspaceMember) bool {
+			return m.Subject == member.Subject
+		}) {
+			continue
+		}
+		for _, name := range []string{workspace.Namespace, member.Namespace} {
+			if _, ok := namespaceProjects[name]; ok {
+				continue
+			}
+			namespace := &corev1.Namespace{}
+			if err := v.APIReader.Get(ctx, client.ObjectKey{Name: name}, namespace); err != nil && !apierrors.IsNotFound(err) {
+				return fmt.Errorf("failed to get namespace %s: %w", name, err)
+			}
+			namespaceProjects[name] = namespace.Labels[utils.LabelProject]
+		}
+		projectName = namespaceProjects[workspace.Namespace]
+		if !policy.AllowsNamespace(member.Namespace, namespaceProjects[member.Namespace], projectName) {
+			rejected = append(rejected, fmt.Sprintf("%s/%s", member.Namespace, member.Name))
+		}
+	}
+	if len(rejected) > 0 {
+		return errServiceAccountNamespacesNotAllowed(projectName, rejected)
+	}
+	return nil
+}
+
+// ensureWorkspaceClassExists returns an error if the given workspace selects a WorkspaceClass which does not exist.
+// Classes are only validated when they are selected, a class which is deleted afterwards is reported by the workspace controller.
+// If the class cannot be looked up, the check is skipped if its failure policy is 'Ignore', the workspace controller reports a missing class then.
+func (v *WorkspaceWebhook) ensureWorkspaceClassExists(ctx context.Context, workspace *pwv1alpha1.Workspace) error {
+	if workspace.Spec.ClassName == "" {
+		return nil
+	}
+	if err := lookup(ctx, v.SharedInformation, pwv1alpha1.WebhookCheckWorkspaceClass, func(ctx context.Context) error {
+		return v.APIReader.Get(ctx, client.ObjectKey{Name: workspace.Spec.ClassName}, &pwv1alpha1.WorkspaceClass{})
+	}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return errWorkspaceClassNotFound(workspace.Spec.ClassName)
+		}
+		if failsOpen(ctx, v.SharedInformation, pwv1alpha1.WebhookCheckWorkspaceClass, err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get WorkspaceClass %s: %w", workspace.Spec.ClassName, err)
+	}
+	return nil
+}
+
 func (v *WorkspaceWebhook) ensureValidRole(ctx context.Context, workspace *pwv1alpha1.Workspace) (bool, error) {
 	userInfo, err := userInfoFromContext(ctx)
 	if err != nil {
 		return false, fmt.Errorf("failed to get userInfo")
 	}
-	if workspace.UserInfoHasRole(userInfo, pwv1alpha1.WorkspaceRoleAdmin) || userInfo.Username == v.Identity {
+	if workspace.UserInfoHasRole(userInfo, pwv1alpha1.WorkspaceRoleAdmin) {
 		return true, nil
 	}
 
-	overrides, err := v.SharedInformation.MemberOverrides(ctx)
+	projectAdminsAllowed, err := v.SharedInformation.ProjectAdminsManageWorkspaces(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get whether project admins manage workspaces: %w", err)
+	}
+	if workspace.InheritsProjectMembers() || projectAdminsAllowed {
+		project, err := v.projectOfWorkspace(ctx, workspace)
+		if err != nil {
+			return false, err
+		}
+		if workspace.InheritsProjectMembers() {
+			effective := workspace.DeepCopy()
+			effective.Spec.Members = workspace.EffectiveMembers(project)
+			if effective.UserInfoHasRole(userInfo, pwv1alpha1.WorkspaceRoleAdmin) {
+				return true, nil
+			}
+		}
+		// admins of the parent project are implicitly allowed to manage its workspaces, if enabled in the config
+		if projectAdminsAllowed && project != nil && project.UserInfoHasRole(userInfo, pwv1alpha1.ProjectRoleAdmin) {
+			logging.FromContextOrPanic(ctx).Debug("Accepting request from admin of the parent project", "project", project.Name)
+			return true, nil
+		}
+	}
+
+	excluded, err := isExcludedIdentity(ctx, v.SharedInformation, v.Identity, userInfo.Username)
+	if err != nil {
+		return false, err
+	}
+	if excluded {
+		return true, nil
+	}
+
+	overrides, err := getMemberOverrides(ctx, v.MemberOverridesCache, v.SharedInformation)
 	if err != nil {
 		return false, fmt.Errorf("failed to get member overrides: %w", err)
 	}
@@ -192,3 +532,190 @@ func (v *WorkspaceWebhook) ensureValidRole(ctx context.Context, workspace *pwv1a
 
 	return false, nil
 }
+
+// projectOfWorkspace returns the project which owns the namespace of the given workspace.
+// It returns nil if the namespace or the project does not exist (anymore).
+// The project itself is always read via the APIReader, since its members decide whether the request is allowed.
+func (v *WorkspaceWebhook) projectOfWorkspace(ctx context.Context, workspace *pwv1alpha1.Workspace) (*pwv1alpha1.Project, error) {
+	projectName, err := v.projectName(ctx, workspace.Namespace)
+	if err != nil {
+		if apierrors.IsNotFound(err) || isNoProjectNamespace(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get namespace %s: %w", workspace.Namespace, err)
+	}
+
+	project := &pwv1alpha1.Project{}
+	if err := lookup(ctx, v.SharedInformation, checkMembership, func(ctx context.Context) error {
+		return v.APIReader.Get(ctx, client.ObjectKey{Name: projectName}, project)
+	}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get project %s: %w", projectName, err)
+	}
+	return project, nil
+}
+
+// ensureInheritedAdminsKept returns an error if the update removes the admin role from project members who inherited it,
+// e.g. by disabling the inheritance or by changing the role mapping.
+// Only project admins and excluded identities are allowed to do this, because workspace admins must not be able to lock out the admins of the project.
+func (v *WorkspaceWebhook) ensureInheritedAdminsKept(ctx context.Context, oldWorkspace, newWorkspace *pwv1alpha1.Workspace) error {
+	if !oldWorkspace.InheritsProjectMembers() {
+		return nil
+	}
+	project, err := v.projectOfWorkspace(ctx, oldWorkspace)
+	if err != nil || project == nil {
+		return err
+	}
+
+	removed := []string{}
+	for _, member := range project.Spec.Members {
+		if slices.Contains(oldWorkspace.InheritedRoles(member.Roles), pwv1alpha1.WorkspaceRoleAdmin) && !slices.Contains(newWorkspace.InheritedRoles(member.Roles), pwv1alpha1.WorkspaceRoleAdmin) {
+			removed = append(removed, fmt.Sprintf("%s/%s", member.Kind, member.Name))
+		}
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+
+	userInfo, err := userInfoFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get userInfo")
+	}
+	if project.UserInfoHasRole(userInfo, pwv1alpha1.ProjectRoleAdmin) {
+		return nil
+	}
+	excluded, err := isExcludedIdentity(ctx, v.SharedInformation, v.Identity, userInfo.Username)
+	if err != nil {
+		return err
+	}
+	if excluded {
+		return nil
+	}
+	return errInheritedAdminsRemoved(removed)
+}
+
+// ensureNoResourcesBlockingDeletion returns an error listing the resources which block the deletion of the given workspace, if its namespace still contains any.
+// This gives synchronous feedback, the workspace controller keeps the workspace in deletion until these resources are gone anyway.
+// Excluded identities can delete the workspace nonetheless, e.g. the namespace controller when the project is deleted.
+// If the check cannot be performed, the deletion is allowed with a warning, since it is enforced by the finalizer of the workspace,
+// unless the failure policy of the check is 'Fail'.
+func (v *WorkspaceWebhook) ensureNoResourcesBlockingDeletion(ctx context.Context, workspace *pwv1alpha1.Workspace) (admission.Warnings, error) {
+	// flat workspaces share the namespace of their project, its content does not belong to them
+	namespace := workspace.Status.Namespace
+	if !features.Enabled(features.WorkspaceDeletionAdmission) || !workspace.DeletionTimestamp.IsZero() || workspace.IsFlat() || namespace == "" {
+		return nil, nil
+	}
+	userInfo, err := userInfoFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	excluded, err := isExcludedIdentity(ctx, v.SharedInformation, v.Identity, userInfo.Username)
+	if err != nil {
+		return nil, err
+	}
+	if excluded {
+		return nil, nil
+	}
+
+	blockers, err := v.resourcesBlockingDeletion(ctx, workspace, namespace)
+	if err != nil {
+		if !failsOpen(ctx, v.SharedInformation, pwv1alpha1.WebhookCheckDeletionBlockers, err) {
+			return nil, fmt.Errorf("failed to check for resources blocking the deletion: %w", err)
+		}
+		return admission.Warnings{fmt.Sprintf("failed to check for resources blocking the deletion, the workspace stays in deletion until they are gone: %s", err.Error())}, nil
+	}
+	if len(blockers) > 0 {
+		return nil, errDeletionBlocked(namespace, blockers)
+	}
+	return nil, nil
+}
+
+// resourcesBlockingDeletion returns up to maxDeletionBlockers resources in the given namespace which block the deletion of the given workspace,
+// considering the resource types and ignore rules from the config as well as the resource types of its WorkspaceClass.
+// Resource types whose CRD is not installed are skipped, as well as the ones whose blocking resource policy lets the workspace controller handle the remaining resources.
+func (v *WorkspaceWebhook) resourcesBlockingDeletion(ctx context.Context, workspace *pwv1alpha1.Workspace, namespace string) ([]string, error) {
+	resources, err := v.SharedInformation.ResourcesBlockingWorkspaceDeletion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resources blocking workspace deletion: %w", err)
+	}
+	if workspace.Spec.ClassName != "" {
+		class := &pwv1alpha1.WorkspaceClass{}
+		err := lookup(ctx, v.SharedInformation, pwv1alpha1.WebhookCheckDeletionBlockers, func(ctx context.Context) error {
+			return v.APIReader.Get(ctx, client.ObjectKey{Name: workspace.Spec.ClassName}, class)
+		})
+		if client.IgnoreNotFound(err) != nil {
+			return nil, fmt.Errorf("failed to get WorkspaceClass %s: %w", workspace.Spec.ClassName, err)
+		}
+		policies, err := v.SharedInformation.WorkspaceBlockingResourcePolicies(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get blocking resource policies for workspace deletion: %w", err)
+		}
+		for _, gvk := range class.Spec.ResourcesBlockingDeletion {
+			resources = append(resources, config.DeletionBlockingResource{
+				GroupVersionKind: gvk,
+				Source:           class.DeletionBlockingSource(),
+				Policy:           pwv1alpha1.BlockingResourcePolicyFor(policies, gvk.Group, gvk.Kind),
+			})
+		}
+	}
+	// remaining resources with policy 'Warn' do not block the deletion and the ones with policy 'Cascade' are deleted by the workspace controller
+	resources = slices.DeleteFunc(resources, func(br config.DeletionBlockingResource) bool {
+		return br.Policy != "" && br.Policy != pwv1alpha1.BlockingResourcePolicyBlock
+	})
+	if len(resources) == 0 {
+		return nil, nil
+	}
+	ignoreRules, err := v.SharedInformation.WorkspaceDeletionIgnoreRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ignore rules for workspace deletion: %w", err)
+	}
+	// only the dynamic onboarding cluster access has permissions for the resource types registered by ServiceProviders
+	dynamicCluster, dynamicErr := v.SharedInformation.OnboardingClusterDynamic(ctx)
+
+	var blockers []string
+	for _, br := range resources {
+		var c client.Client
+		switch {
+		case dynamicErr == nil:
+			c = dynamicCluster.Client()
+		case br.Source == pwv1alpha1.SourceBuiltin:
+			// builtin resource types are covered by the permissions of the static access
+			staticCluster, err := v.SharedInformation.OnboardingClusterStatic(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get onboarding cluster access: %w", err)
+			}
+			c = staticCluster.Client()
+		default:
+			return nil, fmt.Errorf("failed to get dynamic onboarding cluster access for resource type %s: %w", br.GroupVersionKind.String(), dynamicErr)
+		}
+
+		gvk := schema.GroupVersionKind{Group: br.Group, Version: br.Version, Kind: br.Kind}
+		list := &metav1.PartialObjectMetadataList{}
+		list.SetGroupVersionKind(gvk)
+		if err := lookup(ctx, v.SharedInformation, pwv1alpha1.WebhookCheckDeletionBlockers, func(ctx context.Context) error {
+			return c.List(ctx, list, client.InNamespace(namespace))
+		}); err != nil {
+			if isAbsent(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to list %s: %w", gvk.String(), err)
+		}
+		for i := range list.Items {
+			item := &list.Items[i]
+			ignored, err := config.IsIgnoredForDeletion(ignoreRules, item, br.Group, br.Kind)
+			if err != nil {
+				return nil, err
+			}
+			if ignored {
+				continue
+			}
+			blockers = append(blockers, fmt.Sprintf("%s '%s'", br.Kind, item.GetName()))
+			if len(blockers) == maxDeletionBlockers {
+				return blockers, nil
+			}
+		}
+	}
+	return blockers, nil
+}
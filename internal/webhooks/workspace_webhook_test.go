@@ -7,14 +7,64 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/openmcp-project/controller-utils/pkg/clusters"
+
 	pwv1alpha1 "github.com/openmcp-project/platform-service-project-workspace/api/v2/core/v1alpha1"
+	"github.com/openmcp-project/platform-service-project-workspace/internal/controller/config"
 )
 
 var _ = Describe("Workspace Webhook", func() {
 	BeforeEach(func() {
 		sharedInformationForTests.MemberOverridesData = nil
+		sharedInformationForTests.AddCreatorAsAdminData = false
+		sharedInformationForTests.ServiceAccountMembersData = pwv1alpha1.ServiceAccountMembersConfig{}
+		sharedInformationForTests.ProjectAdminsManageWorkspacesData = false
 	})
 
+	// createProject creates a project with the given members and its namespace.
+	// The requesting user is granted an admin override for the project, so that it does not need to be a project member.
+	createProject := func(members ...pwv1alpha1.ProjectMember) *pwv1alpha1.Project {
+		project := &pwv1alpha1.Project{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: uniqueName(),
+			},
+			Spec: pwv1alpha1.ProjectSpec{
+				Members: members,
+			},
+		}
+		sharedInformationForTests.MemberOverridesData = pwv1alpha1.MemberOverrides{
+			{
+				Subject: pwv1alpha1.Subject{
+					Kind: "User",
+					Name: "admin",
+				},
+				Roles: []pwv1alpha1.OverrideRole{
+					pwv1alpha1.OverrideRoleAdmin,
+				},
+				Resources: []pwv1alpha1.OverrideResource{
+					{
+						Kind: pwv1alpha1.OverrideResourceKindProject,
+						Name: project.Name,
+					},
+				},
+			},
+		}
+		Expect(realUserClient.Create(ctx, project)).To(Succeed())
+		sharedInformationForTests.MemberOverridesData = nil
+		Expect(k8sClient.Create(ctx, projectNamespace(project.Name))).To(Succeed())
+		return project
+	}
+
+	projectMember := func(name string, role pwv1alpha1.ProjectMemberRole) pwv1alpha1.ProjectMember {
+		return pwv1alpha1.ProjectMember{
+			Subject: pwv1alpha1.Subject{
+				Kind: "User",
+				Name: name,
+			},
+			Roles: []pwv1alpha1.ProjectMemberRole{role},
+		}
+	}
+
 	Context("When creating a Workspace", func() {
 		It("Should allow to create the workspace by the admin user", func() {
 			var err error
@@ -22,7 +72,7 @@ var _ = Describe("Workspace Webhook", func() {
 			workspace := &pwv1alpha1.Workspace{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      uniqueName(),
-					Namespace: "default",
+					Namespace: testProjectNamespace.Name,
 				},
 				Spec: pwv1alpha1.WorkspaceSpec{
 					Members: []pwv1alpha1.WorkspaceMember{
@@ -49,7 +99,7 @@ var _ = Describe("Workspace Webhook", func() {
 			workspace := &pwv1alpha1.Workspace{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      uniqueName(),
-					Namespace: "default",
+					Namespace: testProjectNamespace.Name,
 				},
 				Spec: pwv1alpha1.WorkspaceSpec{
 					Members: []pwv1alpha1.WorkspaceMember{
@@ -77,7 +127,7 @@ var _ = Describe("Workspace Webhook", func() {
 			workspace := &pwv1alpha1.Workspace{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      uniqueName(),
-					Namespace: "default",
+					Namespace: testProjectNamespace.Name,
 				},
 				Spec: pwv1alpha1.WorkspaceSpec{
 					Members: []pwv1alpha1.WorkspaceMember{
@@ -102,11 +152,7 @@ var _ = Describe("Workspace Webhook", func() {
 			var err error
 			var workspaceName = uniqueName()
 
-			namespace := &corev1.Namespace{
-				ObjectMeta: metav1.ObjectMeta{
-					Name: "project-test",
-				},
-			}
+			namespace := projectNamespace("test")
 
 			err = k8sClient.Create(ctx, namespace)
 			Expect(err).ShouldNot(HaveOccurred())
@@ -161,11 +207,7 @@ var _ = Describe("Workspace Webhook", func() {
 			var err error
 			var workspaceName = uniqueName()
 
-			namespace := &corev1.Namespace{
-				ObjectMeta: metav1.ObjectMeta{
-					Name: "project-test-sa",
-				},
-			}
+			namespace := projectNamespace("test-sa")
 
 			err = k8sClient.Create(ctx, namespace)
 			Expect(err).ShouldNot(HaveOccurred())
@@ -221,11 +263,7 @@ var _ = Describe("Workspace Webhook", func() {
 			var err error
 			var workspaceName = uniqueName()
 
-			namespace := &corev1.Namespace{
-				ObjectMeta: metav1.ObjectMeta{
-					Name: "project-test-group",
-				},
-			}
+			namespace := projectNamespace("test-group")
 
 			err = k8sClient.Create(ctx, namespace)
 			Expect(err).ShouldNot(HaveOccurred())
@@ -280,11 +318,7 @@ var _ = Describe("Workspace Webhook", func() {
 			var err error
 			var workspaceName = uniqueName()
 
-			namespace := &corev1.Namespace{
-				ObjectMeta: metav1.ObjectMeta{
-					Name: "project-test3",
-				},
-			}
+			namespace := projectNamespace("test3")
 
 			err = k8sClient.Create(ctx, namespace)
 			Expect(err).ShouldNot(HaveOccurred())
@@ -334,6 +368,240 @@ var _ = Describe("Workspace Webhook", func() {
 			err = realUserClient.Create(ctx, workspace)
 			Expect(err).To(HaveOccurred())
 		})
+
+		It("Should add the creator as admin to a workspace without admins if enabled", func() {
+			var err error
+
+			sharedInformationForTests.AddCreatorAsAdminData = true
+
+			workspace := &pwv1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      uniqueName(),
+					Namespace: testProjectNamespace.Name,
+				},
+				Spec: pwv1alpha1.WorkspaceSpec{
+					Members: []pwv1alpha1.WorkspaceMember{
+						{
+							Subject: pwv1alpha1.Subject{
+								Kind: "User",
+								Name: "viewer",
+							},
+							Roles: []pwv1alpha1.WorkspaceMemberRole{
+								pwv1alpha1.WorkspaceRoleView,
+							},
+						},
+					},
+				},
+			}
+
+			err = realUserClient.Create(ctx, workspace)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(workspace.Spec.Members).To(ContainElement(pwv1alpha1.WorkspaceMember{
+				Subject: pwv1alpha1.Subject{
+					Kind: "User",
+					Name: "admin",
+				},
+				Roles: []pwv1alpha1.WorkspaceMemberRole{
+					pwv1alpha1.WorkspaceRoleAdmin,
+				},
+			}))
+		})
+	})
+
+	Context("When creating a Workspace outside of a project namespace", func() {
+		It("should deny to create the workspace in a namespace without project label", func() {
+			workspace := &pwv1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      uniqueName(),
+					Namespace: "default",
+				},
+				Spec: pwv1alpha1.WorkspaceSpec{
+					Members: []pwv1alpha1.WorkspaceMember{
+						{
+							Subject: pwv1alpha1.Subject{
+								Kind: "User",
+								Name: "admin",
+							},
+							Roles: []pwv1alpha1.WorkspaceMemberRole{
+								pwv1alpha1.WorkspaceRoleAdmin,
+							},
+						},
+					},
+				},
+			}
+
+			err := realUserClient.Create(ctx, workspace)
+			Expect(err).To(MatchError(ContainSubstring("namespace default is not managed by a project")))
+		})
+	})
+
+	Context("When ServiceAccount members are restricted to the project", func() {
+		newWorkspaceWithServiceAccount := func(namespace string) *pwv1alpha1.Workspace {
+			return &pwv1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      uniqueName(),
+					Namespace: testProjectNamespace.Name,
+				},
+				Spec: pwv1alpha1.WorkspaceSpec{
+					Members: []pwv1alpha1.WorkspaceMember{
+						{
+							Subject: pwv1alpha1.Subject{
+								Kind: "User",
+								Name: "admin",
+							},
+							Roles: []pwv1alpha1.WorkspaceMemberRole{
+								pwv1alpha1.WorkspaceRoleAdmin,
+							},
+						},
+						{
+							Subject: pwv1alpha1.Subject{
+								Kind:      "ServiceAccount",
+								Name:      "deployer",
+								Namespace: namespace,
+							},
+							Roles: []pwv1alpha1.WorkspaceMemberRole{
+								pwv1alpha1.WorkspaceRoleView,
+							},
+						},
+					},
+				},
+			}
+		}
+
+		BeforeEach(func() {
+			sharedInformationForTests.ServiceAccountMembersData = pwv1alpha1.ServiceAccountMembersConfig{RestrictToProject: true}
+		})
+
+		It("should allow ServiceAccounts from the project namespace", func() {
+			err := realUserClient.Create(ctx, newWorkspaceWithServiceAccount(testProjectNamespace.Name))
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should deny ServiceAccounts from namespaces of other projects", func() {
+			err := realUserClient.Create(ctx, newWorkspaceWithServiceAccount("kube-system"))
+			Expect(err).To(MatchError(ContainSubstring("ServiceAccounts kube-system/deployer are not allowed as members")))
+		})
+
+		It("should allow ServiceAccounts from allowed namespaces", func() {
+			sharedInformationForTests.ServiceAccountMembersData.AllowedNamespaces = []string{"kube-system"}
+			err := realUserClient.Create(ctx, newWorkspaceWithServiceAccount("kube-system"))
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should allow updates which keep existing ServiceAccount members", func() {
+			sharedInformationForTests.ServiceAccountMembersData = pwv1alpha1.ServiceAccountMembersConfig{}
+			workspace := newWorkspaceWithServiceAccount("kube-system")
+			Expect(realUserClient.Create(ctx, workspace)).To(Succeed())
+
+			sharedInformationForTests.ServiceAccountMembersData = pwv1alpha1.ServiceAccountMembersConfig{RestrictToProject: true}
+			workspace.Spec.Members[1].Roles = append(workspace.Spec.Members[1].Roles, pwv1alpha1.WorkspaceRoleAdmin)
+			Expect(realUserClient.Update(ctx, workspace)).To(Succeed())
+		})
+	})
+
+	Context("When a Workspace sets provider hints", func() {
+		newWorkspaceWithHints := func(hints map[string]string) *pwv1alpha1.Workspace {
+			return &pwv1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      uniqueName(),
+					Namespace: testProjectNamespace.Name,
+				},
+				Spec: pwv1alpha1.WorkspaceSpec{
+					Members: []pwv1alpha1.WorkspaceMember{
+						{
+							Subject: pwv1alpha1.Subject{
+								Kind: "User",
+								Name: "admin",
+							},
+							Roles: []pwv1alpha1.WorkspaceMemberRole{
+								pwv1alpha1.WorkspaceRoleAdmin,
+							},
+						},
+					},
+					ProviderHints: hints,
+				},
+			}
+		}
+
+		BeforeEach(func() {
+			sharedInformationForTests.WorkspaceProviderHintsData = []pwv1alpha1.ProviderHintConfig{
+				{Key: "region", ServiceProvider: "mcp", AllowedValues: []string{"eu10", "us10"}},
+				{Key: "tier", ServiceProvider: "mcp"},
+			}
+		})
+
+		AfterEach(func() {
+			sharedInformationForTests.WorkspaceProviderHintsData = nil
+		})
+
+		It("should allow available hints with allowed values", func() {
+			err := realUserClient.Create(ctx, newWorkspaceWithHints(map[string]string{"region": "eu10", "tier": "premium"}))
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should deny hints which are not available", func() {
+			err := realUserClient.Create(ctx, newWorkspaceWithHints(map[string]string{"zone": "a"}))
+			Expect(err).To(MatchError(ContainSubstring("provider hint zone is not available")))
+		})
+
+		It("should deny values which are not allowed", func() {
+			err := realUserClient.Create(ctx, newWorkspaceWithHints(map[string]string{"region": "ap10"}))
+			Expect(err).To(MatchError(ContainSubstring("value 'ap10' of provider hint region is not allowed")))
+		})
+
+		It("should allow updates which keep hints that are not available anymore", func() {
+			workspace := newWorkspaceWithHints(map[string]string{"region": "eu10"})
+			Expect(realUserClient.Create(ctx, workspace)).To(Succeed())
+
+			sharedInformationForTests.WorkspaceProviderHintsData = nil
+			workspace.Spec.Hibernated = true
+			Expect(realUserClient.Update(ctx, workspace)).To(Succeed())
+
+			workspace.Spec.ProviderHints["region"] = "us10"
+			Expect(realUserClient.Update(ctx, workspace)).To(MatchError(ContainSubstring("provider hint region is not available")))
+		})
+	})
+
+	Context("When creating a Workspace with a WorkspaceClass", func() {
+		newWorkspaceOfClass := func(className string) *pwv1alpha1.Workspace {
+			return &pwv1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      uniqueName(),
+					Namespace: testProjectNamespace.Name,
+				},
+				Spec: pwv1alpha1.WorkspaceSpec{
+					ClassName: className,
+					Members: []pwv1alpha1.WorkspaceMember{
+						{
+							Subject: pwv1alpha1.Subject{
+								Kind: "User",
+								Name: "admin",
+							},
+							Roles: []pwv1alpha1.WorkspaceMemberRole{
+								pwv1alpha1.WorkspaceRoleAdmin,
+							},
+						},
+					},
+				},
+			}
+		}
+
+		It("should allow to create the workspace if the class exists", func() {
+			class := &pwv1alpha1.WorkspaceClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: uniqueName(),
+				},
+			}
+			Expect(k8sClient.Create(ctx, class)).To(Succeed())
+
+			err := realUserClient.Create(ctx, newWorkspaceOfClass(class.Name))
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should deny to create the workspace if the class does not exist", func() {
+			err := realUserClient.Create(ctx, newWorkspaceOfClass("does-not-exist"))
+			Expect(err).To(MatchError(ContainSubstring("WorkspaceClass does-not-exist does not exist")))
+		})
 	})
 
 	Context("When updating a Workspace", func() {
@@ -343,7 +611,7 @@ var _ = Describe("Workspace Webhook", func() {
 			workspace := &pwv1alpha1.Workspace{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      uniqueName(),
-					Namespace: "default",
+					Namespace: testProjectNamespace.Name,
 				},
 				Spec: pwv1alpha1.WorkspaceSpec{
 					Members: []pwv1alpha1.WorkspaceMember{
@@ -374,11 +642,7 @@ var _ = Describe("Workspace Webhook", func() {
 			var err error
 			var workspaceName = uniqueName()
 
-			namespace := &corev1.Namespace{
-				ObjectMeta: metav1.ObjectMeta{
-					Name: "project-test-parent",
-				},
-			}
+			namespace := projectNamespace("test-parent")
 
 			err = k8sClient.Create(ctx, namespace)
 			Expect(err).ShouldNot(HaveOccurred())
@@ -452,4 +716,226 @@ var _ = Describe("Workspace Webhook", func() {
 			Expect(err).To(HaveOccurred())
 		})
 	})
+
+	Context("When a Workspace inherits the project members", func() {
+		It("should allow project admins to manage the workspace without being listed as workspace members", func() {
+			project := createProject(projectMember("admin", pwv1alpha1.ProjectRoleAdmin))
+
+			workspace := &pwv1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      uniqueName(),
+					Namespace: projectNamespace(project.Name).Name,
+				},
+				Spec: pwv1alpha1.WorkspaceSpec{
+					InheritProjectMembers: &pwv1alpha1.InheritProjectMembers{
+						Enabled: true,
+					},
+				},
+			}
+			Expect(realUserClient.Create(ctx, workspace)).To(Succeed())
+
+			workspace.Labels = map[string]string{"key": "value"}
+			Expect(realUserClient.Update(ctx, workspace)).To(Succeed())
+		})
+
+		It("should deny workspace admins to remove the inherited admin role from project admins", func() {
+			project := createProject(projectMember("project-admin", pwv1alpha1.ProjectRoleAdmin))
+
+			workspace := &pwv1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      uniqueName(),
+					Namespace: projectNamespace(project.Name).Name,
+				},
+				Spec: pwv1alpha1.WorkspaceSpec{
+					Members: []pwv1alpha1.WorkspaceMember{
+						{
+							Subject: pwv1alpha1.Subject{
+								Kind: "User",
+								Name: "admin",
+							},
+							Roles: []pwv1alpha1.WorkspaceMemberRole{
+								pwv1alpha1.WorkspaceRoleAdmin,
+							},
+						},
+					},
+					InheritProjectMembers: &pwv1alpha1.InheritProjectMembers{
+						Enabled: true,
+					},
+				},
+			}
+			Expect(realUserClient.Create(ctx, workspace)).To(Succeed())
+
+			workspace.Spec.InheritProjectMembers.RoleMapping = map[pwv1alpha1.ProjectMemberRole]pwv1alpha1.WorkspaceMemberRole{
+				pwv1alpha1.ProjectRoleAdmin: pwv1alpha1.WorkspaceRoleView,
+			}
+			err := realUserClient.Update(ctx, workspace)
+			Expect(err).To(MatchError(ContainSubstring("User/project-admin")))
+
+			workspace.Spec.InheritProjectMembers = nil
+			err = realUserClient.Update(ctx, workspace)
+			Expect(err).To(MatchError(ContainSubstring("User/project-admin")))
+		})
+
+		It("should allow project admins to disable the inheritance", func() {
+			project := createProject(projectMember("admin", pwv1alpha1.ProjectRoleAdmin), projectMember("project-admin", pwv1alpha1.ProjectRoleAdmin))
+
+			workspace := &pwv1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      uniqueName(),
+					Namespace: projectNamespace(project.Name).Name,
+				},
+				Spec: pwv1alpha1.WorkspaceSpec{
+					Members: []pwv1alpha1.WorkspaceMember{
+						{
+							Subject: pwv1alpha1.Subject{
+								Kind: "User",
+								Name: "admin",
+							},
+							Roles: []pwv1alpha1.WorkspaceMemberRole{
+								pwv1alpha1.WorkspaceRoleAdmin,
+							},
+						},
+					},
+					InheritProjectMembers: &pwv1alpha1.InheritProjectMembers{
+						Enabled: true,
+					},
+				},
+			}
+			Expect(realUserClient.Create(ctx, workspace)).To(Succeed())
+
+			workspace.Spec.InheritProjectMembers = nil
+			Expect(realUserClient.Update(ctx, workspace)).To(Succeed())
+		})
+	})
+
+	Context("When project admins manage the workspaces of their project", func() {
+		newWorkspace := func(namespace string) *pwv1alpha1.Workspace {
+			return &pwv1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      uniqueName(),
+					Namespace: namespace,
+				},
+				Spec: pwv1alpha1.WorkspaceSpec{
+					Members: []pwv1alpha1.WorkspaceMember{
+						{
+							Subject: pwv1alpha1.Subject{
+								Kind: "User",
+								Name: "workspace-admin",
+							},
+							Roles: []pwv1alpha1.WorkspaceMemberRole{
+								pwv1alpha1.WorkspaceRoleAdmin,
+							},
+						},
+					},
+				},
+			}
+		}
+
+		It("should allow project admins to manage the workspace without being a workspace member", func() {
+			sharedInformationForTests.ProjectAdminsManageWorkspacesData = true
+			project := createProject(projectMember("admin", pwv1alpha1.ProjectRoleAdmin))
+
+			workspace := newWorkspace(projectNamespace(project.Name).Name)
+			Expect(realUserClient.Create(ctx, workspace)).To(Succeed())
+
+			workspace.Labels = map[string]string{"key": "value"}
+			Expect(realUserClient.Update(ctx, workspace)).To(Succeed())
+		})
+
+		It("should deny project viewers to manage the workspace", func() {
+			sharedInformationForTests.ProjectAdminsManageWorkspacesData = true
+			project := createProject(projectMember("admin", pwv1alpha1.ProjectRoleView))
+
+			err := realUserClient.Create(ctx, newWorkspace(projectNamespace(project.Name).Name))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should deny project admins to manage the workspace if it is not enabled", func() {
+			project := createProject(projectMember("admin", pwv1alpha1.ProjectRoleAdmin))
+
+			err := realUserClient.Create(ctx, newWorkspace(projectNamespace(project.Name).Name))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("When deleting a Workspace", func() {
+		BeforeEach(func() {
+			sharedInformationForTests.OnboardingCluster = clusters.NewTestClusterFromClient("onboarding", k8sClient)
+			sharedInformationForTests.ResourcesBlockingWorkspaceDeletionData = []config.DeletionBlockingResource{
+				{GroupVersionKind: metav1.GroupVersionKind{Version: "v1", Kind: "Secret"}, Source: pwv1alpha1.SourceBuiltin},
+			}
+		})
+
+		AfterEach(func() {
+			sharedInformationForTests.OnboardingCluster = nil
+			sharedInformationForTests.ResourcesBlockingWorkspaceDeletionData = nil
+		})
+
+		It("should deny the deletion while the workspace namespace contains resources blocking it", func() {
+			workspace := &pwv1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      uniqueName(),
+					Namespace: testProjectNamespace.Name,
+				},
+				Spec: pwv1alpha1.WorkspaceSpec{
+					Members: []pwv1alpha1.WorkspaceMember{
+						{
+							Subject: pwv1alpha1.Subject{
+								Kind: "User",
+								Name: "admin",
+							},
+							Roles: []pwv1alpha1.WorkspaceMemberRole{
+								pwv1alpha1.WorkspaceRoleAdmin,
+							},
+						},
+					},
+				},
+			}
+			Expect(realUserClient.Create(ctx, workspace)).To(Succeed())
+			namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: uniqueName()}}
+			Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+			workspace.Status.Namespace = namespace.Name
+			Expect(k8sClient.Status().Update(ctx, workspace)).To(Succeed())
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "blocker", Namespace: namespace.Name}}
+			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+
+			err := realUserClient.Delete(ctx, workspace)
+			Expect(err).To(MatchError(ContainSubstring("Secret 'blocker'")))
+
+			Expect(k8sClient.Delete(ctx, secret)).To(Succeed())
+			Expect(realUserClient.Delete(ctx, workspace)).To(Succeed())
+		})
+
+		It("should allow the deletion if the remaining resources do not block it because of their policy", func() {
+			sharedInformationForTests.ResourcesBlockingWorkspaceDeletionData[0].Policy = pwv1alpha1.BlockingResourcePolicyCascade
+			workspace := &pwv1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      uniqueName(),
+					Namespace: testProjectNamespace.Name,
+				},
+				Spec: pwv1alpha1.WorkspaceSpec{
+					Members: []pwv1alpha1.WorkspaceMember{
+						{
+							Subject: pwv1alpha1.Subject{
+								Kind: "User",
+								Name: "admin",
+							},
+							Roles: []pwv1alpha1.WorkspaceMemberRole{
+								pwv1alpha1.WorkspaceRoleAdmin,
+							},
+						},
+					},
+				},
+			}
+			Expect(realUserClient.Create(ctx, workspace)).To(Succeed())
+			namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: uniqueName()}}
+			Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+			workspace.Status.Namespace = namespace.Name
+			Expect(k8sClient.Status().Update(ctx, workspace)).To(Succeed())
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "cascaded", Namespace: namespace.Name}}
+			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+
+			Expect(realUserClient.Delete(ctx, workspace)).To(Succeed())
+		})
+	})
 })